@@ -6,17 +6,20 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/docs"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/di"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/tracing"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/middlewares"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/routes"
 	wsRoutes "github.com/gbrayhan/microservices-go/src/infrastructure/ws/routes"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
@@ -83,6 +86,12 @@ func main() {
 	// Load server configuration
 	serverConfig := loadServerConfig()
 
+	// Install the tracer provider before the database and redis clients are instrumented
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.LoadConfigFromEnv(), loggerInstance)
+	if err != nil {
+		loggerInstance.Panic("Error initializing tracing", zap.Error(err))
+	}
+
 	// Initialize application context with dependencies and logger
 	appContext, err := di.SetupDependencies(loggerInstance)
 	if err != nil {
@@ -118,6 +127,12 @@ func main() {
 	<-quit
 	loggerInstance.Info("Shutting down server...")
 
+	// Fail the readiness probe first and give the load balancer time to stop routing here
+	appContext.Health.MarkShuttingDown()
+	if delay, err := strconv.Atoi(getEnvOrDefault("HEALTH_SHUTDOWN_DELAY_SECONDS", "0")); err == nil && delay > 0 {
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
 	// Create a context with a timeout for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -127,6 +142,15 @@ func main() {
 		loggerInstance.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Flush the buffered operation records once no request can add more
+	if err := appContext.OperationWriter.Close(ctx); err != nil {
+		loggerInstance.Error("Operation records not fully flushed", zap.Error(err))
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		loggerInstance.Error("Pending spans not fully exported", zap.Error(err))
+	}
+
 	loggerInstance.Info("Server exiting")
 }
 
@@ -144,15 +168,21 @@ func setupRouter(appContext *di.ApplicationContext, logger *logger.Logger) *gin.
 
 	// set file upload configuration
 	router.MaxMultipartMemory = 10 << 20 // 10 MB
-	router.Static("/public", "./public")
 	router.RedirectTrailingSlash = false
 
 	// Agregar middlewares de recuperación y logger personalizados
+	// the server span comes first, then the request id so every later middleware and log
+	// line can use it
+	router.Use(otelgin.Middleware(tracing.LoadConfigFromEnv().ServiceName,
+		otelgin.WithFilter(func(r *http.Request) bool { return !middlewares.IsMonitoringPath(r.URL.Path) })))
+	router.Use(middlewares.RequestID())
+	router.Use(middlewares.Metrics())
 	router.Use(gin.Recovery())
 	router.Use(middlewares.CorsHeader())
 	// Add middlewares
 	router.Use(middlewares.ErrorHandler())
-	router.Use(middlewares.GinBodyLogMiddleware(appContext.DB, appContext.Logger))
+	router.Use(middlewares.IgnoreApiMiddleware(appContext.IgnoreApiModule.UseCase))
+	router.Use(middlewares.GinBodyLogMiddleware(appContext.OperationWriter, appContext.Redactor, appContext.Logger))
 	router.Use(middlewares.SecurityHeaders())
 	// Add logger middleware
 	router.Use(logger.GinZapLogger())
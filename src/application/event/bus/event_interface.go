@@ -12,3 +12,8 @@ type EventBus interface {
 	Subscribe(eventType string, handler model.EventHandler) error
 	Unsubscribe(eventType string, handler model.EventHandler) error
 }
+
+// HealthChecker 依赖外部消息中间件的事件总线实现此接口, 供就绪探针检查连接状态
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
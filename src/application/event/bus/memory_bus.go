@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/gbrayhan/microservices-go/src/application/event/model"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"go.uber.org/zap"
 )
@@ -68,13 +69,18 @@ func (eb *InMemoryEventBus) Unsubscribe(eventType string, handler model.EventHan
 
 // Publish 发布事件
 func (eb *InMemoryEventBus) Publish(ctx context.Context, event model.ApplicationEvent) error {
+	requestID := model.AttachRequestID(ctx, event)
+	ctx, span := startEventSpan(ctx, "memory", operationPublish, event)
+	defer span.End()
 	eb.logger.Info("Publishing event",
 		zap.String("eventType", event.EventType()),
-		zap.String("eventID", event.EventID()))
+		zap.String("eventID", event.EventID()),
+		zap.String("request_id", requestID))
 
 	eb.mutex.RLock()
 	defer eb.mutex.RUnlock()
 
+	metrics.EventsPublished.WithLabelValues(event.EventType(), "ok").Inc()
 	handlers, exists := eb.handlers[event.EventType()]
 	if !exists || len(handlers) == 0 {
 		eb.logger.Debug("No handlers found for event", zap.String("eventType", event.EventType()))
@@ -98,10 +104,15 @@ func (eb *InMemoryEventBus) Publish(ctx context.Context, event model.Application
 				zap.String("eventType", event.EventType()),
 				zap.String("eventID", event.EventID()))
 
-			if err := h.Handle(event); err != nil {
+			_, handleSpan := startEventSpan(ctx, "memory", operationProcess, event)
+			err := h.Handle(event)
+			endEventSpan(handleSpan, err)
+			metrics.EventsHandled.WithLabelValues(event.EventType(), metrics.Result(err)).Inc()
+			if err != nil {
 				eb.logger.Error("Error handling event",
 					zap.String("eventType", event.EventType()),
 					zap.String("eventID", event.EventID()),
+					zap.String("request_id", requestID),
 					zap.Error(err))
 				errChan <- err
 			} else {
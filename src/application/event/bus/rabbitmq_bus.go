@@ -3,15 +3,22 @@ package bus
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/application/event/model"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/tracing"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 // RabbitMQEventBus RabbitMQ事件总线实现
@@ -24,6 +31,8 @@ type RabbitMQEventBus struct {
 	handlers     map[string][]model.EventHandler
 	handlerMutex sync.RWMutex
 	logger       *logger.Logger
+	// channelClosed 由 NotifyClose 置位, 通道关闭后无法再发布消息
+	channelClosed atomic.Bool
 }
 
 // NewRabbitMQEventBus 创建RabbitMQ事件总线
@@ -93,6 +102,13 @@ func NewRabbitMQEventBus(logger *logger.Logger) (EventBus, error) {
 		logger:       logger,
 	}
 
+	// 监听通道关闭
+	closeNotify := ch.NotifyClose(make(chan *amqp.Error, 1))
+	go func() {
+		<-closeNotify
+		eventBus.channelClosed.Store(true)
+	}()
+
 	// 启动消费者
 	go eventBus.startConsumer()
 
@@ -125,19 +141,36 @@ func (rb *RabbitMQEventBus) Subscribe(eventType string, handler model.EventHandl
 
 // Publish 发布事件
 func (rb *RabbitMQEventBus) Publish(ctx context.Context, event model.ApplicationEvent) error {
+	requestID := model.AttachRequestID(ctx, event)
+	ctx, span := startEventSpan(ctx, "rabbitmq", operationPublish, event)
+	defer span.End()
+
 	// 序列化事件
 	eventData, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %v", err)
 	}
 
+	// 元数据和链路上下文写入消息头, 请求ID同时作为 CorrelationId
+	headers := amqp.Table{}
+	for key, value := range event.Metadata() {
+		headers[key] = value
+	}
+	traceCarrier := propagation.MapCarrier{}
+	tracing.Inject(ctx, traceCarrier)
+	for key, value := range traceCarrier {
+		headers[key] = value
+	}
+
 	// 创建消息
 	msg := amqp.Publishing{
-		ContentType: "application/json",
-		Body:        eventData,
-		Timestamp:   time.Now(),
-		MessageId:   event.EventID(),
-		Type:        event.EventType(),
+		ContentType:   "application/json",
+		Body:          eventData,
+		Timestamp:     time.Now(),
+		MessageId:     event.EventID(),
+		Type:          event.EventType(),
+		CorrelationId: requestID,
+		Headers:       headers,
 	}
 
 	// 发布消息
@@ -149,8 +182,10 @@ func (rb *RabbitMQEventBus) Publish(ctx context.Context, event model.Application
 		false,           // immediate
 		msg,
 	)
+	metrics.EventsPublished.WithLabelValues(event.EventType(), metrics.Result(err)).Inc()
 
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("failed to publish message: %v", err)
 	}
 
@@ -220,7 +255,7 @@ func (rb *RabbitMQEventBus) startConsumer() {
 			}
 
 			// 处理事件
-			if err := rb.handleEvent(eventType, event); err != nil {
+			if err := rb.handleEvent(eventType, event, deliveryMetadata(d)); err != nil {
 				log.Printf("Failed to handle event %s: %v", eventType, err)
 				d.Nack(false, true) // 重新入队
 				continue
@@ -236,7 +271,7 @@ func (rb *RabbitMQEventBus) startConsumer() {
 }
 
 // handleEvent 处理事件
-func (rb *RabbitMQEventBus) handleEvent(eventType string, eventData map[string]interface{}) error {
+func (rb *RabbitMQEventBus) handleEvent(eventType string, eventData map[string]interface{}, metadata map[string]string) error {
 	rb.handlerMutex.RLock()
 	defer rb.handlerMutex.RUnlock()
 
@@ -248,10 +283,14 @@ func (rb *RabbitMQEventBus) handleEvent(eventType string, eventData map[string]i
 
 	// 创建事件对象
 	event := &RabbitMQApplicationEvent{
-		Data: eventData,
-		Type: eventType,
+		EventMetadata: model.EventMetadata{Meta: metadata},
+		Data:          eventData,
+		Type:          eventType,
 	}
 
+	// 从消息头恢复发布方的链路上下文
+	ctx := tracing.Extract(context.Background(), propagation.MapCarrier(metadata))
+
 	// 并发处理所有处理器
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(handlers))
@@ -260,7 +299,11 @@ func (rb *RabbitMQEventBus) handleEvent(eventType string, eventData map[string]i
 		wg.Add(1)
 		go func(h model.EventHandler) {
 			defer wg.Done()
-			if err := h.Handle(event); err != nil {
+			_, span := startEventSpan(ctx, "rabbitmq", operationProcess, event)
+			err := h.Handle(event)
+			endEventSpan(span, err)
+			metrics.EventsHandled.WithLabelValues(eventType, metrics.Result(err)).Inc()
+			if err != nil {
 				errChan <- err
 			}
 		}(handler)
@@ -277,6 +320,31 @@ func (rb *RabbitMQEventBus) handleEvent(eventType string, eventData map[string]i
 	return nil
 }
 
+// deliveryMetadata 从消息头恢复事件元数据
+func deliveryMetadata(d amqp.Delivery) map[string]string {
+	metadata := make(map[string]string)
+	for key, value := range d.Headers {
+		if s, ok := value.(string); ok {
+			metadata[key] = s
+		}
+	}
+	if metadata[requestid.MetadataKey] == "" && d.CorrelationId != "" {
+		metadata[requestid.MetadataKey] = d.CorrelationId
+	}
+	return metadata
+}
+
+// HealthCheck 检查连接和通道是否仍然可用
+func (rb *RabbitMQEventBus) HealthCheck(ctx context.Context) error {
+	if rb.connection == nil || rb.connection.IsClosed() {
+		return errors.New("rabbitmq connection is closed")
+	}
+	if rb.channelClosed.Load() {
+		return errors.New("rabbitmq channel is closed")
+	}
+	return nil
+}
+
 // Close 关闭连接
 func (rb *RabbitMQEventBus) Close() error {
 	if rb.channel != nil {
@@ -296,6 +364,7 @@ func (rb *RabbitMQEventBus) Close() error {
 
 // RabbitMQApplicationEvent RabbitMQ应用事件实现
 type RabbitMQApplicationEvent struct {
+	model.EventMetadata
 	Data map[string]interface{}
 	Type string
 }
@@ -0,0 +1,42 @@
+package bus
+
+import (
+	"context"
+
+	"github.com/gbrayhan/microservices-go/src/application/event/model"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// messaging operations recorded on event spans
+const (
+	operationPublish = "publish"
+	operationProcess = "process"
+)
+
+// startEventSpan 为事件的发布或处理创建span, 发布为 producer, 处理为 consumer
+func startEventSpan(ctx context.Context, system, operation string, event model.ApplicationEvent) (context.Context, trace.Span) {
+	kind := trace.SpanKindProducer
+	if operation == operationProcess {
+		kind = trace.SpanKindConsumer
+	}
+	return tracing.Tracer().Start(ctx, operation+" "+event.EventType(),
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(
+			attribute.String("messaging.system", system),
+			attribute.String("messaging.operation.type", operation),
+			attribute.String("messaging.destination.name", event.EventType()),
+			attribute.String("messaging.message.id", event.EventID()),
+		))
+}
+
+// endEventSpan 记录错误并结束span
+func endEventSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
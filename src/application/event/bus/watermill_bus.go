@@ -11,6 +11,9 @@ import (
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
 	"github.com/gbrayhan/microservices-go/src/application/event/model"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/tracing"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 // WatermillEventBus 基于Watermill的事件总线
@@ -60,17 +63,25 @@ func (eb *WatermillEventBus) Subscribe(eventType string, handler model.EventHand
 			}
 
 			event := &GenericApplicationEvent{
-				Data: eventModel,
-				Type: eventType,
+				EventMetadata: model.EventMetadata{Meta: msg.Metadata},
+				Data:          eventModel,
+				Type:          eventType,
 			}
 
+			// 从消息元数据恢复发布方的链路上下文
+			ctx := tracing.Extract(context.Background(), propagation.MapCarrier(msg.Metadata))
+
 			// 执行所有处理器
 			eb.handlerMutex.RLock()
 			handlers := eb.handlers[eventType]
 			eb.handlerMutex.RUnlock()
 
 			for _, h := range handlers {
-				if err := h.Handle(event); err != nil {
+				_, span := startEventSpan(ctx, "watermill", operationProcess, event)
+				err := h.Handle(event)
+				endEventSpan(span, err)
+				metrics.EventsHandled.WithLabelValues(eventType, metrics.Result(err)).Inc()
+				if err != nil {
 					eb.logger.Error("Error handling event", err, nil)
 					msg.Nack()
 					continue
@@ -91,8 +102,17 @@ func (eb *WatermillEventBus) Publish(ctx context.Context, event model.Applicatio
 		return err
 	}
 
+	model.AttachRequestID(ctx, event)
+	ctx, span := startEventSpan(ctx, "watermill", operationPublish, event)
 	msg := message.NewMessage(event.EventID(), payload)
-	return eb.publisher.Publish(event.EventType(), msg)
+	for key, value := range event.Metadata() {
+		msg.Metadata.Set(key, value)
+	}
+	tracing.Inject(ctx, propagation.MapCarrier(msg.Metadata))
+	err = eb.publisher.Publish(event.EventType(), msg)
+	metrics.EventsPublished.WithLabelValues(event.EventType(), metrics.Result(err)).Inc()
+	endEventSpan(span, err)
+	return err
 }
 
 // Unsubscribe 取消订阅指定事件类型
@@ -117,6 +137,7 @@ func (eb *WatermillEventBus) Unsubscribe(eventType string, handler model.EventHa
 
 // GenericApplicationEvent 通用应用事件实现
 type GenericApplicationEvent struct {
+	model.EventMetadata
 	Data map[string]interface{}
 	Type string
 	ID   string
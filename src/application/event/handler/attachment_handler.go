@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gbrayhan/microservices-go/src/application/event/model"
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+)
+
+// AttachmentEventHandler 附件事件处理器, 实体删除后移除其文件附件
+type AttachmentEventHandler struct {
+	attachments domainFiles.IFileAttachmentService
+}
+
+// NewAttachmentEventHandler 创建附件事件处理器
+func NewAttachmentEventHandler(attachments domainFiles.IFileAttachmentService) *AttachmentEventHandler {
+	return &AttachmentEventHandler{attachments: attachments}
+}
+
+// Handle 处理事件
+func (h *AttachmentEventHandler) Handle(event model.ApplicationEvent) error {
+	switch event.EventType() {
+	case model.EntityDeletedEventType:
+		return h.handleEntityDeleted(event)
+	default:
+		return nil
+	}
+}
+
+func (h *AttachmentEventHandler) handleEntityDeleted(event model.ApplicationEvent) error {
+	payload, ok := event.Payload().(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected payload of event %s", event.EventID())
+	}
+	entityType, _ := payload["entityType"].(string)
+	rawID, _ := payload["entityID"].(string)
+	entityID, err := strconv.ParseInt(rawID, 10, 64)
+	if entityType == "" || err != nil {
+		return fmt.Errorf("event %s has no entity", event.EventID())
+	}
+	// 未清理的附件由文件垃圾回收任务移除
+	_, err = h.attachments.DetachEntity(context.Background(), entityType, entityID)
+	return err
+}
@@ -3,4 +3,10 @@ package model
 const (
 	UserRegisteredEventType = "UserRegistered"
 	OrderCreatedEventType   = "OrderCreated"
+	EntityDeletedEventType  = "EntityDeleted"
+)
+
+// 实体类型, 用于 EntityDeletedEvent 与文件附件
+const (
+	EntityTypeUser = "user"
 )
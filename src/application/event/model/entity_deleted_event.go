@@ -0,0 +1,39 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// EntityDeletedEvent 实体删除事件, 删除实体的模块发布, 附件等依附于实体的数据据此清理
+type EntityDeletedEvent struct {
+	EventMetadata
+	ID         string
+	EntityType string
+	EntityID   int64
+	DeletedAt  time.Time
+}
+
+// EventID 事件ID
+func (e *EntityDeletedEvent) EventID() string {
+	return e.ID
+}
+
+// EventType 事件类型
+func (e *EntityDeletedEvent) EventType() string {
+	return EntityDeletedEventType
+}
+
+// Timestamp 事件时间戳
+func (e *EntityDeletedEvent) Timestamp() time.Time {
+	return e.DeletedAt
+}
+
+// Payload 事件载荷, 实体ID以字符串传递, 经 JSON 序列化的事件总线不会损失精度
+func (e *EntityDeletedEvent) Payload() interface{} {
+	return map[string]interface{}{
+		"entityType": e.EntityType,
+		"entityID":   strconv.FormatInt(e.EntityID, 10),
+		"deletedAt":  e.DeletedAt,
+	}
+}
@@ -10,6 +10,8 @@ type ApplicationEvent interface {
 	EventType() string
 	Timestamp() time.Time
 	Payload() interface{}
+	// Metadata 事件元数据, 如请求ID, 由事件总线随事件传递
+	Metadata() map[string]string
 }
 
 // EventHandler 事件处理器接口
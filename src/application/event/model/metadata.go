@@ -0,0 +1,33 @@
+package model
+
+import (
+	"context"
+
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
+)
+
+// EventMetadata 事件元数据, 由事件结构体嵌入, 用于携带请求ID等与载荷无关的信息
+type EventMetadata struct {
+	Meta map[string]string `json:"metadata,omitempty"`
+}
+
+// Metadata 返回元数据, 调用方可直接写入
+func (m *EventMetadata) Metadata() map[string]string {
+	if m.Meta == nil {
+		m.Meta = make(map[string]string)
+	}
+	return m.Meta
+}
+
+// AttachRequestID 将 ctx 中的请求ID写入事件元数据, 已有请求ID时保持不变, 返回最终的请求ID
+func AttachRequestID(ctx context.Context, event ApplicationEvent) string {
+	metadata := event.Metadata()
+	if id := metadata[requestid.MetadataKey]; id != "" {
+		return id
+	}
+	id := requestid.FromContext(ctx)
+	if id != "" {
+		metadata[requestid.MetadataKey] = id
+	}
+	return id
+}
@@ -13,6 +13,7 @@ type RegisterUserCommand struct {
 
 // UserRegisteredEvent 用户注册事件
 type UserRegisteredEvent struct {
+	EventMetadata
 	ID           string
 	UserID       string
 	Username     string
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	domainUser "github.com/gbrayhan/microservices-go/src/domain/user"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/user_role"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/user"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/security"
 	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
@@ -19,16 +21,17 @@ import (
 )
 
 type IAuthUseCase interface {
-	Login(username, password string) (*domainUser.User, *AuthTokens, *domainRole.Role, error)
-	Logout(jwtToken string) (*domain.CommonResponse[string], error)
-	Register(user RegisterUser) (*domain.CommonResponse[SecurityRegisterUser], error)
-	AccessTokenByRefreshToken(refreshToken string) (*domainUser.User, *AuthTokens, error)
-	SwitchRole(userId int, roleId int64) (*domainUser.User, *AuthTokens, *domainRole.Role, error)
+	Login(ctx context.Context, username, password string) (*domainUser.User, *AuthTokens, *domainRole.Role, error)
+	Logout(ctx context.Context, jwtToken string) (*domain.CommonResponse[string], error)
+	Register(ctx context.Context, user RegisterUser) (*domain.CommonResponse[SecurityRegisterUser], error)
+	AccessTokenByRefreshToken(ctx context.Context, refreshToken string) (*domainUser.User, *AuthTokens, error)
+	SwitchRole(ctx context.Context, userId int, roleId int64) (*domainUser.User, *AuthTokens, *domainRole.Role, error)
 }
 
 type AuthUseCase struct {
 	UserRepository         user.UserRepositoryInterface
 	RoleRepository         role.ISysRolesRepository
+	UserRoleRepository     user_role.ISysUserRoleRepository
 	JWTService             security.IJWTService
 	Logger                 *logger.Logger
 	jwtBlacklistRepository jwtBlacklistDomain.IJwtBlacklistService
@@ -37,6 +40,7 @@ type AuthUseCase struct {
 func NewAuthUseCase(
 	userRepository user.UserRepositoryInterface,
 	RoleRepository role.ISysRolesRepository,
+	userRoleRepository user_role.ISysUserRoleRepository,
 	jwtService security.IJWTService,
 	loggerInstance *logger.Logger,
 	jwtBlacklistRepository jwtBlacklistDomain.IJwtBlacklistService,
@@ -44,6 +48,7 @@ func NewAuthUseCase(
 	return &AuthUseCase{
 		UserRepository:         userRepository,
 		RoleRepository:         RoleRepository,
+		UserRoleRepository:     userRoleRepository,
 		JWTService:             jwtService,
 		Logger:                 loggerInstance,
 		jwtBlacklistRepository: jwtBlacklistRepository,
@@ -57,9 +62,9 @@ type AuthTokens struct {
 	ExpirationRefreshDateTime time.Time
 }
 
-func (s *AuthUseCase) SwitchRole(userId int, roleId int64) (*domainUser.User, *AuthTokens, *domainRole.Role, error) {
+func (s *AuthUseCase) SwitchRole(ctx context.Context, userId int, roleId int64) (*domainUser.User, *AuthTokens, *domainRole.Role, error) {
 	s.Logger.Info("User switch attempt", zap.Int("userId", userId))
-	user, err := s.UserRepository.GetByID(int(userId))
+	user, err := s.UserRepository.GetByID(ctx, int(userId))
 	if err != nil {
 		s.Logger.Error("Error getting user for switch", zap.Error(err), zap.Int("userId", userId))
 		return nil, nil, nil, err
@@ -68,7 +73,14 @@ func (s *AuthUseCase) SwitchRole(userId int, roleId int64) (*domainUser.User, *A
 		s.Logger.Warn("Login failed: user not found", zap.Int("userId", userId))
 		return nil, nil, nil, domainErrors.NewAppError(errors.New("user don't no found"), domainErrors.NotAuthorized)
 	}
-	role, err := s.RoleRepository.GetByID(int(roleId))
+	if err := s.filterValidRoles(ctx, user); err != nil {
+		return nil, nil, nil, err
+	}
+	if !hasRole(user, roleId) {
+		s.Logger.Warn("Switch failed: role not granted or no longer valid", zap.Int("userId", userId), zap.Int64("roleId", roleId))
+		return nil, nil, nil, domainErrors.NewAppError(errors.New("role is not valid for user"), domainErrors.NotAuthorized)
+	}
+	role, err := s.RoleRepository.GetByID(ctx, int(roleId))
 	if err != nil {
 		s.Logger.Error("Error getting role for switch", zap.Error(err), zap.Int("roleId", int(roleId)))
 		return nil, nil, nil, err
@@ -95,9 +107,9 @@ func (s *AuthUseCase) SwitchRole(userId int, roleId int64) (*domainUser.User, *A
 	return user, authTokens, role, nil
 }
 
-func (s *AuthUseCase) Login(username, password string) (*domainUser.User, *AuthTokens, *domainRole.Role, error) {
+func (s *AuthUseCase) Login(ctx context.Context, username, password string) (*domainUser.User, *AuthTokens, *domainRole.Role, error) {
 	s.Logger.Info("User login attempt", zap.String("username", username))
-	user, err := s.UserRepository.GetByUsername(username)
+	user, err := s.UserRepository.GetByUsername(ctx, username)
 	if err != nil {
 		s.Logger.Error("Error getting user for login", zap.Error(err), zap.String("username", username))
 		return nil, nil, nil, err
@@ -112,6 +124,9 @@ func (s *AuthUseCase) Login(username, password string) (*domainUser.User, *AuthT
 		s.Logger.Warn("Login failed: invalid password", zap.String("username", username))
 		return nil, nil, nil, domainErrors.NewAppError(errors.New("username or password does not match"), domainErrors.NotAuthorized)
 	}
+	if err := s.filterValidRoles(ctx, user); err != nil {
+		return nil, nil, nil, err
+	}
 	var role domainRole.Role
 	var roleId int64
 	if len(user.Roles) > 0 {
@@ -140,7 +155,7 @@ func (s *AuthUseCase) Login(username, password string) (*domainUser.User, *AuthT
 	return user, authTokens, &role, nil
 }
 
-func (s *AuthUseCase) AccessTokenByRefreshToken(refreshToken string) (*domainUser.User, *AuthTokens, error) {
+func (s *AuthUseCase) AccessTokenByRefreshToken(ctx context.Context, refreshToken string) (*domainUser.User, *AuthTokens, error) {
 	s.Logger.Info("Refreshing access token")
 	claimsMap, err := s.JWTService.GetClaimsAndVerifyToken(refreshToken, "refresh")
 	if err != nil {
@@ -148,12 +163,21 @@ func (s *AuthUseCase) AccessTokenByRefreshToken(refreshToken string) (*domainUse
 		return nil, nil, err
 	}
 	userID := int(claimsMap["id"].(float64))
-	user, err := s.UserRepository.GetByID(userID)
+	user, err := s.UserRepository.GetByID(ctx, userID)
 	if err != nil {
 		s.Logger.Error("Error getting user for token refresh", zap.Error(err), zap.Int("userID", userID))
 		return nil, nil, err
 	}
 	roleId := int64(claimsMap["role_id"].(float64))
+	if roleId != 0 {
+		if err := s.filterValidRoles(ctx, user); err != nil {
+			return nil, nil, err
+		}
+		if !hasRole(user, roleId) {
+			s.Logger.Warn("Token refresh failed: role no longer valid", zap.Int("userID", userID), zap.Int64("roleId", roleId))
+			return nil, nil, domainErrors.NewAppError(errors.New("role is not valid for user"), domainErrors.NotAuthorized)
+		}
+	}
 	accessTokenClaims, err := s.JWTService.GenerateJWTToken(user.ID, roleId, "access")
 	if err != nil {
 		s.Logger.Error("Error generating new access token", zap.Error(err), zap.Int64("userID", user.ID))
@@ -173,12 +197,42 @@ func (s *AuthUseCase) AccessTokenByRefreshToken(refreshToken string) (*domainUse
 	return user, authTokens, nil
 }
 
+// filterValidRoles drops the user's roles whose binding has not started yet or has already expired
+func (s *AuthUseCase) filterValidRoles(ctx context.Context, user *domainUser.User) error {
+	validIds, err := s.UserRoleRepository.GetValidRoleIds(ctx, user.ID, time.Now())
+	if err != nil {
+		s.Logger.Error("Error getting valid roles", zap.Error(err), zap.Int64("userID", user.ID))
+		return err
+	}
+	valid := make(map[int64]bool, len(validIds))
+	for _, id := range validIds {
+		valid[id] = true
+	}
+	roles := make([]domainRole.Role, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		if valid[role.ID] {
+			roles = append(roles, role)
+		}
+	}
+	user.Roles = roles
+	return nil
+}
+
+func hasRole(user *domainUser.User, roleId int64) bool {
+	for _, role := range user.Roles {
+		if role.ID == roleId {
+			return true
+		}
+	}
+	return false
+}
+
 // Register implements IAuthUseCase.
-func (s *AuthUseCase) Register(user RegisterUser) (*domain.CommonResponse[SecurityRegisterUser], error) {
+func (s *AuthUseCase) Register(ctx context.Context, user RegisterUser) (*domain.CommonResponse[SecurityRegisterUser], error) {
 	// user is exist
 	whereCondition := make(map[string]interface{}, 3)
 	whereCondition["user_name"] = user.UserName
-	dbUser, err := s.UserRepository.GetOneByMap(whereCondition)
+	dbUser, err := s.UserRepository.GetOneByMap(ctx, whereCondition)
 	if err != nil {
 		return nil, err
 	}
@@ -202,7 +256,7 @@ func (s *AuthUseCase) Register(user RegisterUser) (*domain.CommonResponse[Securi
 	userRepo.UUID = uuid.New().String()
 	userRepo.Status = 1
 
-	res, err := s.UserRepository.Create(&userRepo)
+	res, err := s.UserRepository.Create(ctx, &userRepo)
 
 	return &domain.CommonResponse[SecurityRegisterUser]{
 		Data: SecurityRegisterUser{
@@ -221,10 +275,10 @@ func (s *AuthUseCase) Register(user RegisterUser) (*domain.CommonResponse[Securi
 
 }
 
-func (s *AuthUseCase) Logout(jwtToken string) (*domain.CommonResponse[string], error) {
+func (s *AuthUseCase) Logout(ctx context.Context, jwtToken string) (*domain.CommonResponse[string], error) {
 	var err error
 	// check exist
-	exist, err := s.jwtBlacklistRepository.IsJwtInBlacklist(jwtToken)
+	exist, err := s.jwtBlacklistRepository.IsJwtInBlacklist(ctx, jwtToken)
 	if err != nil {
 		return nil, domainErrors.NewAppError(err, domainErrors.TokenError)
 	}
@@ -233,7 +287,7 @@ func (s *AuthUseCase) Logout(jwtToken string) (*domain.CommonResponse[string], e
 	}
 
 	// add token to black list
-	err = s.jwtBlacklistRepository.AddToBlacklist(jwtToken)
+	err = s.jwtBlacklistRepository.AddToBlacklist(ctx, jwtToken)
 	if err != nil {
 		return nil, domainErrors.NewAppError(err, domainErrors.TokenError)
 	}
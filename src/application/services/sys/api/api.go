@@ -1,100 +1,120 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/casbin/casbin/v2"
 	"github.com/gbrayhan/microservices-go/src/domain"
 	apiDomain "github.com/gbrayhan/microservices-go/src/domain/sys/api"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/cache"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	apiRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/api"
 	dictionaryRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/dictionary"
 	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
 	"go.uber.org/zap"
 )
 
 type ISysApiService interface {
-	GetAll() (*[]apiDomain.Api, error)
-	GetByID(id int) (*apiDomain.Api, error)
-	Create(newApi *apiDomain.Api) (*apiDomain.Api, error)
-	Delete(ids []int) error
-	Update(id int, userMap map[string]interface{}) (*apiDomain.Api, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[apiDomain.Api], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*apiDomain.Api, error)
-	GetApisGroup() (*[]apiDomain.GroupApiItem, error)
-	SynchronizeRouterToApi(router gin.RoutesInfo) (*int, error)
+	GetAll(ctx context.Context) (*[]apiDomain.Api, error)
+	GetByID(ctx context.Context, id int) (*apiDomain.Api, error)
+	Create(ctx context.Context, newApi *apiDomain.Api) (*apiDomain.Api, error)
+	Delete(ctx context.Context, ids []int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*apiDomain.Api, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[apiDomain.Api], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*apiDomain.Api, error)
+	GetApisGroup(ctx context.Context) (*[]apiDomain.GroupApiItem, error)
+	SynchronizeRouterToApi(ctx context.Context, router gin.RoutesInfo, prune bool) (*apiDomain.SyncResult, error)
 }
 
 type SysApiUseCase struct {
 	sysApiRepository     apiRepo.ApiRepositoryInterface
 	dictionaryRepository dictionaryRepo.DictionaryRepositoryInterface
+	enforcer             *casbin.Enforcer
+	btnPermissions       *cache.BtnPermissionCache
 	Logger               *logger.Logger
 }
 
 func NewSysApiUseCase(
 	sysApiRepository apiRepo.ApiRepositoryInterface,
 	dictionaryRepository dictionaryRepo.DictionaryRepositoryInterface,
+	enforcer *casbin.Enforcer,
+	btnPermissions *cache.BtnPermissionCache,
 	loggerInstance *logger.Logger) ISysApiService {
 	return &SysApiUseCase{
 		sysApiRepository:     sysApiRepository,
 		dictionaryRepository: dictionaryRepository,
+		enforcer:             enforcer,
+		btnPermissions:       btnPermissions,
 		Logger:               loggerInstance,
 	}
 }
 
-func (s *SysApiUseCase) GetAll() (*[]apiDomain.Api, error) {
+func (s *SysApiUseCase) GetAll(ctx context.Context) (*[]apiDomain.Api, error) {
 	s.Logger.Info("Getting all roles")
-	return s.sysApiRepository.GetAll()
+	return s.sysApiRepository.GetAll(ctx)
 }
 
-func (s *SysApiUseCase) GetByID(id int) (*apiDomain.Api, error) {
+func (s *SysApiUseCase) GetByID(ctx context.Context, id int) (*apiDomain.Api, error) {
 	s.Logger.Info("Getting api by ID", zap.Int("id", id))
-	return s.sysApiRepository.GetByID(id)
+	return s.sysApiRepository.GetByID(ctx, id)
 }
 
-func (s *SysApiUseCase) Create(newApi *apiDomain.Api) (*apiDomain.Api, error) {
+func (s *SysApiUseCase) Create(ctx context.Context, newApi *apiDomain.Api) (*apiDomain.Api, error) {
 	s.Logger.Info("Creating new api", zap.String("path", newApi.Path))
-	return s.sysApiRepository.Create(newApi)
+	return s.sysApiRepository.Create(ctx, newApi)
 }
 
-func (s *SysApiUseCase) Delete(ids []int) error {
+func (s *SysApiUseCase) Delete(ctx context.Context, ids []int) error {
 	s.Logger.Info("Deleting api", zap.String("ids", fmt.Sprintf("%v", ids)))
-	return s.sysApiRepository.Delete(ids)
+	if err := s.sysApiRepository.Delete(ctx, ids); err != nil {
+		return err
+	}
+	s.btnPermissions.Invalidate()
+	return nil
 }
 
-func (s *SysApiUseCase) Update(id int, userMap map[string]interface{}) (*apiDomain.Api, error) {
+func (s *SysApiUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*apiDomain.Api, error) {
 	s.Logger.Info("Updating api", zap.Int("id", id))
-	return s.sysApiRepository.Update(id, userMap)
+	api, err := s.sysApiRepository.Update(ctx, id, userMap)
+	if err != nil {
+		return nil, err
+	}
+	// the buttons guard apis by path and method
+	s.btnPermissions.Invalidate()
+	return api, nil
 }
 
-func (s *SysApiUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[apiDomain.Api], error) {
+func (s *SysApiUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[apiDomain.Api], error) {
 	s.Logger.Info("Searching apis with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysApiRepository.SearchPaginated(filters)
+	return s.sysApiRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *SysApiUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *SysApiUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching api by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysApiRepository.SearchByProperty(property, searchText)
+	return s.sysApiRepository.SearchByProperty(ctx, property, searchText)
 }
 
 // Get one api by map
-func (s *SysApiUseCase) GetOneByMap(userMap map[string]interface{}) (*apiDomain.Api, error) {
-	return s.sysApiRepository.GetOneByMap(userMap)
+func (s *SysApiUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*apiDomain.Api, error) {
+	return s.sysApiRepository.GetOneByMap(ctx, userMap)
 }
 
 // GetApisGroup
-func (s *SysApiUseCase) GetApisGroup() (*[]apiDomain.GroupApiItem, error) {
-	apis, err := s.sysApiRepository.GetAll()
+func (s *SysApiUseCase) GetApisGroup(ctx context.Context) (*[]apiDomain.GroupApiItem, error) {
+	apis, err := s.sysApiRepository.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	dictionary, err := s.dictionaryRepository.GetByType("api_group")
+	dictionary, err := s.dictionaryRepository.GetByType(ctx, "api_group")
 	if err != nil {
 		return nil, err
 	}
@@ -121,18 +141,41 @@ func (s *SysApiUseCase) GetApisGroup() (*[]apiDomain.GroupApiItem, error) {
 	return &groups, nil
 }
 
-func (c *SysApiUseCase) SynchronizeRouterToApi(routes gin.RoutesInfo) (*int, error) {
-	count := 0
+// SynchronizeRouterToApi upserts the registered routes, classified by their swagger @Tags and @Summary
+// or by their path when the swagger document misses them, and reports the apis whose route has been
+// removed. Those are deleted along with their casbin policies when prune is set, whether a route is
+// documented never decides it is removed.
+func (c *SysApiUseCase) SynchronizeRouterToApi(ctx context.Context, routes gin.RoutesInfo, prune bool) (*apiDomain.SyncResult, error) {
+	docs := c.loadRouteDocs()
+	groups := c.loadApiGroups(ctx)
+	result := &apiDomain.SyncResult{Removed: make([]apiDomain.Api, 0), Undocumented: make([]string, 0)}
+	registered := make(map[string]bool, len(routes))
 	for _, route := range routes {
 		if c.shouldSyncRoute(route.Path) {
+			registered[routeKey(route.Method, route.Path)] = true
 			apiModel := &apiRepo.SysApi{
 				Path:        route.Path,
 				Method:      route.Method,
 				Description: c.generateDescription(route.Path, route.Method),
-				ApiGroup:    "Other",
+				ApiGroup:    apiDomain.DefaultGroup,
+			}
+			if doc, ok := docs[routeKey(route.Method, route.Path)]; ok {
+				if group := resolveGroup(doc.Tags, groups); group != "" {
+					apiModel.ApiGroup = group
+				}
+				if doc.Description != "" {
+					apiModel.Description = doc.Description
+				}
+			} else {
+				// the docs lag behind the routes until swag init runs again, the route is still
+				// synced and classified by its path
+				result.Undocumented = append(result.Undocumented, routeKey(route.Method, route.Path))
+				if group := resolveGroup(pathTags(route.Path), groups); group != "" {
+					apiModel.ApiGroup = group
+				}
 			}
 
-			ok, err := c.sysApiRepository.Upsert(apiModel)
+			created, updated, err := c.sysApiRepository.Upsert(ctx, apiModel)
 			if err != nil {
 				c.Logger.Error("Failed to sync route",
 					zap.String("path", route.Path),
@@ -140,12 +183,75 @@ func (c *SysApiUseCase) SynchronizeRouterToApi(routes gin.RoutesInfo) (*int, err
 					zap.Error(err))
 				continue
 			}
-			if ok {
-				count++
+			if created {
+				result.Created++
+			}
+			if updated {
+				result.Updated++
 			}
 		}
 	}
-	return &count, nil
+
+	apis, err := c.sysApiRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	removedIds := make([]int, 0)
+	for _, api := range *apis {
+		if c.shouldSyncRoute(api.Path) && !registered[routeKey(api.Method, api.Path)] {
+			result.Removed = append(result.Removed, api)
+			removedIds = append(removedIds, api.ID)
+		}
+	}
+	if prune && len(removedIds) > 0 {
+		if err := c.sysApiRepository.Prune(ctx, removedIds); err != nil {
+			return nil, err
+		}
+		c.btnPermissions.Invalidate()
+		// casbin keeps policies in memory, reload them after the rules table changed
+		if err := c.enforcer.LoadPolicy(); err != nil {
+			c.Logger.Error("Error reloading casbin policy", zap.Error(err))
+			return nil, err
+		}
+		result.Pruned = true
+	}
+	c.Logger.Info("Synchronized routes",
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("removed", len(result.Removed)),
+		zap.Int("undocumented", len(result.Undocumented)),
+		zap.Bool("pruned", result.Pruned))
+	return result, nil
+}
+
+// loadApiGroups indexes the api_group dictionary labels by lowercase label and value
+func (c *SysApiUseCase) loadApiGroups(ctx context.Context) map[string]string {
+	groups := make(map[string]string)
+	dictionary, err := c.dictionaryRepository.GetByType(ctx, "api_group")
+	if err != nil || dictionary.Details == nil {
+		c.Logger.Warn("Api group dictionary not available, routes are not classified", zap.Error(err))
+		return groups
+	}
+	for _, item := range *dictionary.Details {
+		groups[strings.ToLower(item.Label)] = item.Label
+		groups[strings.ToLower(item.Value)] = item.Label
+	}
+	return groups
+}
+
+// loadRouteDocs reads the swagger document registered by the docs package
+func (c *SysApiUseCase) loadRouteDocs() map[string]routeDoc {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		c.Logger.Warn("Swagger document not available, routes are not classified", zap.Error(err))
+		return map[string]routeDoc{}
+	}
+	docs, err := parseRouteDocs(doc)
+	if err != nil {
+		c.Logger.Warn("Error parsing swagger document", zap.Error(err))
+		return map[string]routeDoc{}
+	}
+	return docs
 }
 
 func (a *SysApiUseCase) shouldSyncRoute(path string) bool {
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// routeDoc is the classification of a route taken from its swagger annotations
+type routeDoc struct {
+	Tags        []string
+	Description string
+}
+
+type swaggerSpec struct {
+	BasePath string                                 `json:"basePath"`
+	Paths    map[string]map[string]swaggerOperation `json:"paths"`
+}
+
+type swaggerOperation struct {
+	Tags        []string `json:"tags"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+}
+
+var (
+	swaggerParamPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+	versionSegment      = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// parseRouteDocs indexes the swagger operations by "METHOD path", with paths in gin syntax
+// (/v1/user/:id) so they can be matched against the registered routes.
+func parseRouteDocs(doc string) (map[string]routeDoc, error) {
+	var spec swaggerSpec
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, err
+	}
+	basePath := strings.TrimSuffix(spec.BasePath, "/")
+	docs := make(map[string]routeDoc)
+	for path, operations := range spec.Paths {
+		path = swaggerParamPattern.ReplaceAllString(path, ":$1")
+		for method, operation := range operations {
+			item := routeDoc{Tags: operation.Tags, Description: strings.TrimSpace(operation.Summary)}
+			if item.Description == "" {
+				item.Description = strings.TrimSpace(operation.Description)
+			}
+			// @Router annotations carry the full path here, but may also be relative to the base path
+			docs[routeKey(method, path)] = item
+			if basePath != "" && !strings.HasPrefix(path, basePath+"/") {
+				if _, ok := docs[routeKey(method, basePath+path)]; !ok {
+					docs[routeKey(method, basePath+path)] = item
+				}
+			}
+		}
+	}
+	return docs, nil
+}
+
+func routeKey(method string, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// resolveGroup maps swagger tags onto the api groups, which are keyed by lowercase label and value.
+// A tag matches either as a whole ("user") or by one of its words ("user role").
+func resolveGroup(tags []string, groups map[string]string) string {
+	for _, tag := range tags {
+		if group, ok := groups[strings.ToLower(strings.TrimSpace(tag))]; ok {
+			return group
+		}
+	}
+	for _, tag := range tags {
+		for _, word := range strings.Fields(strings.ToLower(tag)) {
+			if group, ok := groups[word]; ok {
+				return group
+			}
+		}
+	}
+	return ""
+}
+
+// pathTags stands in for the tags of a route missing from the swagger document: its first
+// segment after the version (/v1/file/attachments/:id gives "file"), then the following ones.
+func pathTags(path string) []string {
+	tags := make([]string, 0)
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || versionSegment.MatchString(segment) || strings.HasPrefix(segment, ":") ||
+			strings.HasPrefix(segment, "*") {
+			continue
+		}
+		tags = append(tags, strings.ReplaceAll(segment, "-", " "))
+	}
+	return tags
+}
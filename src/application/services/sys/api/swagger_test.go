@@ -0,0 +1,70 @@
+package api
+
+import (
+	"testing"
+)
+
+const testSwaggerDoc = `{
+	"basePath": "/v1",
+	"paths": {
+		"/v1/user/{id}": {
+			"get": {"tags": ["user"], "summary": "get user"},
+			"delete": {"tags": ["user role"], "description": "delete user"}
+		},
+		"/role": {
+			"post": {"summary": "create role"}
+		}
+	}
+}`
+
+func TestParseRouteDocs(t *testing.T) {
+	docs, err := parseRouteDocs(testSwaggerDoc)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if doc, ok := docs["GET /v1/user/:id"]; !ok || doc.Description != "get user" {
+		t.Errorf("Expected GET /v1/user/:id to be documented, got %v", docs)
+	}
+	if doc := docs["DELETE /v1/user/:id"]; doc.Description != "delete user" {
+		t.Errorf("Expected description fallback, got %q", doc.Description)
+	}
+	if _, ok := docs["POST /v1/role"]; !ok {
+		t.Errorf("Expected base path to be prefixed, got %v", docs)
+	}
+
+	if _, err := parseRouteDocs("not json"); err == nil {
+		t.Error("Expected error for invalid document")
+	}
+}
+
+func TestResolveGroup(t *testing.T) {
+	groups := map[string]string{"user": "User", "other": "Other"}
+
+	if group := resolveGroup([]string{"User"}, groups); group != "User" {
+		t.Errorf("Expected User, got %q", group)
+	}
+	if group := resolveGroup([]string{"user role"}, groups); group != "User" {
+		t.Errorf("Expected User from tag words, got %q", group)
+	}
+	if group := resolveGroup([]string{"sync apis"}, groups); group != "" {
+		t.Errorf("Expected no group, got %q", group)
+	}
+}
+
+func TestPathTags(t *testing.T) {
+	groups := map[string]string{"file": "File", "user": "User"}
+
+	tags := pathTags("/v1/file/attachments/:entity_type/:entity_id")
+	if len(tags) != 2 || tags[0] != "file" || tags[1] != "attachments" {
+		t.Errorf("Expected file and attachments, got %v", tags)
+	}
+	if group := resolveGroup(pathTags("/v1/file/attachments/:entity_type/:entity_id"), groups); group != "File" {
+		t.Errorf("Expected File for an undocumented file route, got %q", group)
+	}
+	if group := resolveGroup(pathTags("/v1/user-role/*path"), groups); group != "User" {
+		t.Errorf("Expected User from the path words, got %q", group)
+	}
+	if group := resolveGroup(pathTags("/metrics"), groups); group != "" {
+		t.Errorf("Expected no group, got %q", group)
+	}
+}
@@ -0,0 +1,39 @@
+package audit_log
+
+import (
+	"context"
+	"github.com/gbrayhan/microservices-go/src/domain"
+	auditLogDomain "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	auditLogRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
+	"go.uber.org/zap"
+)
+
+type ISysAuditLogService interface {
+	GetByID(ctx context.Context, id int) (*auditLogDomain.AuditLog, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[auditLogDomain.AuditLog], error)
+}
+
+type SysAuditLogUseCase struct {
+	auditLogRepository auditLogRepo.AuditLogRepositoryInterface
+	Logger             *logger.Logger
+}
+
+func NewSysAuditLogUseCase(auditLogRepository auditLogRepo.AuditLogRepositoryInterface, loggerInstance *logger.Logger) ISysAuditLogService {
+	return &SysAuditLogUseCase{
+		auditLogRepository: auditLogRepository,
+		Logger:             loggerInstance,
+	}
+}
+
+func (s *SysAuditLogUseCase) GetByID(ctx context.Context, id int) (*auditLogDomain.AuditLog, error) {
+	s.Logger.Info("Getting audit log by ID", zap.Int("id", id))
+	return s.auditLogRepository.GetByID(ctx, id)
+}
+
+func (s *SysAuditLogUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[auditLogDomain.AuditLog], error) {
+	s.Logger.Info("Searching audit logs with pagination",
+		zap.Int("page", filters.Page),
+		zap.Int("pageSize", filters.PageSize))
+	return s.auditLogRepository.SearchPaginated(ctx, filters)
+}
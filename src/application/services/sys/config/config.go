@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	configDomain "github.com/gbrayhan/microservices-go/src/domain/sys/config"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	configRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/config"
@@ -10,9 +11,9 @@ import (
 )
 
 type ISysConfigService interface {
-	GetConfigByGroup() (*[]configDomain.GroupConfig, error)
-	Update(module string, dataMap map[string]interface{}) error
-	GetConfigByModule(module string) (*[]configDomain.Config, error)
+	GetConfigByGroup(ctx context.Context) (*[]configDomain.GroupConfig, error)
+	Update(ctx context.Context, module string, actorId int64, dataMap map[string]interface{}) error
+	GetConfigByModule(ctx context.Context, module string) (*[]configDomain.Config, error)
 }
 
 type SysConfigUseCase struct {
@@ -33,7 +34,7 @@ func NewSysConfigUseCase(
 }
 
 // Update implements ISysConfigService.
-func (s *SysConfigUseCase) Update(module string, userMap map[string]interface{}) error {
+func (s *SysConfigUseCase) Update(ctx context.Context, module string, actorId int64, userMap map[string]interface{}) error {
 	s.Logger.Info("Updating config")
 
 	for key, value := range userMap {
@@ -42,7 +43,7 @@ func (s *SysConfigUseCase) Update(module string, userMap map[string]interface{})
 			// 可选：记录日志或处理类型断言失败的情况
 			continue
 		}
-		err := s.sysConfigRepository.UpdateByModule(module, key, configValue)
+		err := s.sysConfigRepository.UpdateByModule(ctx, module, key, configValue, actorId)
 		if err != nil {
 			// 可选：记录日志或处理更新失败的情况
 			continue
@@ -52,9 +53,9 @@ func (s *SysConfigUseCase) Update(module string, userMap map[string]interface{})
 }
 
 // GetConfigByGroup implements ISysConfigService.
-func (s *SysConfigUseCase) GetConfigByGroup() (*[]configDomain.GroupConfig, error) {
+func (s *SysConfigUseCase) GetConfigByGroup(ctx context.Context) (*[]configDomain.GroupConfig, error) {
 	s.Logger.Info("Get config to group")
-	list, err := s.sysConfigRepository.GetAll()
+	list, err := s.sysConfigRepository.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +83,7 @@ func (s *SysConfigUseCase) GetConfigByGroup() (*[]configDomain.GroupConfig, erro
 			if g.Name == item.Module {
 
 				if item.ConfigType == "select" {
-					dictData, err := s.sysDictionaryRepository.GetByType(item.ConfigKey)
+					dictData, err := s.sysDictionaryRepository.GetByType(ctx, item.ConfigKey)
 					if err != nil {
 						item.SelectOptions = nil
 					} else {
@@ -100,7 +101,7 @@ func (s *SysConfigUseCase) GetConfigByGroup() (*[]configDomain.GroupConfig, erro
 }
 
 // GetConfigByModule implements ISysConfigService.
-func (s *SysConfigUseCase) GetConfigByModule(module string) (*[]configDomain.Config, error) {
+func (s *SysConfigUseCase) GetConfigByModule(ctx context.Context, module string) (*[]configDomain.Config, error) {
 	s.Logger.Info("get config by module", zap.String("module", module))
-	return s.sysConfigRepository.GetConfigByModule(module)
+	return s.sysConfigRepository.GetConfigByModule(ctx, module)
 }
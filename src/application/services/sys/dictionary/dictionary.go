@@ -1,6 +1,7 @@
 package dictionary
 
 import (
+	"context"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	dictionaryRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/dictionary"
 
@@ -10,15 +11,15 @@ import (
 )
 
 type ISysDictionaryService interface {
-	GetAll() (*[]dictionaryDomain.Dictionary, error)
-	GetByID(id int) (*dictionaryDomain.Dictionary, error)
-	Create(newDictionary *dictionaryDomain.Dictionary) (*dictionaryDomain.Dictionary, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*dictionaryDomain.Dictionary, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[dictionaryDomain.Dictionary], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*dictionaryDomain.Dictionary, error)
-	GetByType(typeText string) (*dictionaryDomain.Dictionary, error)
+	GetAll(ctx context.Context) (*[]dictionaryDomain.Dictionary, error)
+	GetByID(ctx context.Context, id int) (*dictionaryDomain.Dictionary, error)
+	Create(ctx context.Context, newDictionary *dictionaryDomain.Dictionary) (*dictionaryDomain.Dictionary, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*dictionaryDomain.Dictionary, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[dictionaryDomain.Dictionary], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*dictionaryDomain.Dictionary, error)
+	GetByType(ctx context.Context, typeText string) (*dictionaryDomain.Dictionary, error)
 }
 
 type SysDictionaryUseCase struct {
@@ -36,49 +37,49 @@ func NewSysDictionaryUseCase(
 	}
 }
 
-func (s *SysDictionaryUseCase) GetAll() (*[]dictionaryDomain.Dictionary, error) {
+func (s *SysDictionaryUseCase) GetAll(ctx context.Context) (*[]dictionaryDomain.Dictionary, error) {
 	s.Logger.Info("Getting all dictionaries")
-	return s.sysDictionaryRepository.GetAll()
+	return s.sysDictionaryRepository.GetAll(ctx)
 }
 
-func (s *SysDictionaryUseCase) GetByID(id int) (*dictionaryDomain.Dictionary, error) {
+func (s *SysDictionaryUseCase) GetByID(ctx context.Context, id int) (*dictionaryDomain.Dictionary, error) {
 	s.Logger.Info("Getting dictionary by ID", zap.Int("id", id))
-	return s.sysDictionaryRepository.GetByID(id)
+	return s.sysDictionaryRepository.GetByID(ctx, id)
 }
 
-func (s *SysDictionaryUseCase) Create(newDictionary *dictionaryDomain.Dictionary) (*dictionaryDomain.Dictionary, error) {
+func (s *SysDictionaryUseCase) Create(ctx context.Context, newDictionary *dictionaryDomain.Dictionary) (*dictionaryDomain.Dictionary, error) {
 	s.Logger.Info("Creating new dictionary", zap.String("Name", newDictionary.Name))
-	return s.sysDictionaryRepository.Create(newDictionary)
+	return s.sysDictionaryRepository.Create(ctx, newDictionary)
 }
 
-func (s *SysDictionaryUseCase) Delete(id int) error {
+func (s *SysDictionaryUseCase) Delete(ctx context.Context, id int) error {
 	s.Logger.Info("Deleting dictionary", zap.Int("id", id))
-	return s.sysDictionaryRepository.Delete(id)
+	return s.sysDictionaryRepository.Delete(ctx, id)
 }
 
-func (s *SysDictionaryUseCase) Update(id int, userMap map[string]interface{}) (*dictionaryDomain.Dictionary, error) {
+func (s *SysDictionaryUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*dictionaryDomain.Dictionary, error) {
 	s.Logger.Info("Updating dictionary", zap.Int("id", id))
-	return s.sysDictionaryRepository.Update(id, userMap)
+	return s.sysDictionaryRepository.Update(ctx, id, userMap)
 }
 
-func (s *SysDictionaryUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[dictionaryDomain.Dictionary], error) {
+func (s *SysDictionaryUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[dictionaryDomain.Dictionary], error) {
 	s.Logger.Info("Searching dictionary with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysDictionaryRepository.SearchPaginated(filters)
+	return s.sysDictionaryRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *SysDictionaryUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *SysDictionaryUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching dictionary by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysDictionaryRepository.SearchByProperty(property, searchText)
+	return s.sysDictionaryRepository.SearchByProperty(ctx, property, searchText)
 }
 
-func (s *SysDictionaryUseCase) GetOneByMap(userMap map[string]interface{}) (*dictionaryDomain.Dictionary, error) {
-	return s.sysDictionaryRepository.GetOneByMap(userMap)
+func (s *SysDictionaryUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*dictionaryDomain.Dictionary, error) {
+	return s.sysDictionaryRepository.GetOneByMap(ctx, userMap)
 }
 
-func (s *SysDictionaryUseCase) GetByType(typeText string) (*dictionaryDomain.Dictionary, error) {
-	return s.sysDictionaryRepository.GetByType(typeText)
+func (s *SysDictionaryUseCase) GetByType(ctx context.Context, typeText string) (*dictionaryDomain.Dictionary, error) {
+	return s.sysDictionaryRepository.GetByType(ctx, typeText)
 }
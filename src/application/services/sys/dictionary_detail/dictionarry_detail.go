@@ -1,6 +1,7 @@
 package dictionary_detail
 
 import (
+	"context"
 	"fmt"
 
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
@@ -12,14 +13,14 @@ import (
 )
 
 type ISysDictionaryService interface {
-	GetAll() (*[]dictionaryDomain.DictionaryDetail, error)
-	GetByID(id int) (*dictionaryDomain.DictionaryDetail, error)
-	Create(newDictionary *dictionaryDomain.DictionaryDetail) (*dictionaryDomain.DictionaryDetail, error)
-	Delete(ids []int) error
-	Update(id int, userMap map[string]interface{}) (*dictionaryDomain.DictionaryDetail, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[dictionaryDomain.DictionaryDetail], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*dictionaryDomain.DictionaryDetail, error)
+	GetAll(ctx context.Context) (*[]dictionaryDomain.DictionaryDetail, error)
+	GetByID(ctx context.Context, id int) (*dictionaryDomain.DictionaryDetail, error)
+	Create(ctx context.Context, newDictionary *dictionaryDomain.DictionaryDetail) (*dictionaryDomain.DictionaryDetail, error)
+	Delete(ctx context.Context, ids []int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*dictionaryDomain.DictionaryDetail, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[dictionaryDomain.DictionaryDetail], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*dictionaryDomain.DictionaryDetail, error)
 }
 
 type SysDictionaryUseCase struct {
@@ -34,45 +35,45 @@ func NewSysDictionaryUseCase(sysDictionaryRepository dictionaryRepo.DictionaryRe
 	}
 }
 
-func (s *SysDictionaryUseCase) GetAll() (*[]dictionaryDomain.DictionaryDetail, error) {
+func (s *SysDictionaryUseCase) GetAll(ctx context.Context) (*[]dictionaryDomain.DictionaryDetail, error) {
 	s.Logger.Info("Getting all dictionary_detail")
-	return s.sysDictionaryRepository.GetAll()
+	return s.sysDictionaryRepository.GetAll(ctx)
 }
 
-func (s *SysDictionaryUseCase) GetByID(id int) (*dictionaryDomain.DictionaryDetail, error) {
+func (s *SysDictionaryUseCase) GetByID(ctx context.Context, id int) (*dictionaryDomain.DictionaryDetail, error) {
 	s.Logger.Info("Getting dictionary_detailby ID", zap.Int("id", id))
-	return s.sysDictionaryRepository.GetByID(id)
+	return s.sysDictionaryRepository.GetByID(ctx, id)
 }
 
-func (s *SysDictionaryUseCase) Create(newDictionary *dictionaryDomain.DictionaryDetail) (*dictionaryDomain.DictionaryDetail, error) {
+func (s *SysDictionaryUseCase) Create(ctx context.Context, newDictionary *dictionaryDomain.DictionaryDetail) (*dictionaryDomain.DictionaryDetail, error) {
 	s.Logger.Info("Creating new dictionary_detail", zap.String("Label", newDictionary.Label))
-	return s.sysDictionaryRepository.Create(newDictionary)
+	return s.sysDictionaryRepository.Create(ctx, newDictionary)
 }
 
-func (s *SysDictionaryUseCase) Delete(ids []int) error {
+func (s *SysDictionaryUseCase) Delete(ctx context.Context, ids []int) error {
 	s.Logger.Info("Deleting dictionary_detail", zap.String("ids", fmt.Sprintf("%v", ids)))
-	return s.sysDictionaryRepository.Delete(ids)
+	return s.sysDictionaryRepository.Delete(ctx, ids)
 }
 
-func (s *SysDictionaryUseCase) Update(id int, userMap map[string]interface{}) (*dictionaryDomain.DictionaryDetail, error) {
+func (s *SysDictionaryUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*dictionaryDomain.DictionaryDetail, error) {
 	s.Logger.Info("Updating dictionary", zap.Int("id", id))
-	return s.sysDictionaryRepository.Update(id, userMap)
+	return s.sysDictionaryRepository.Update(ctx, id, userMap)
 }
 
-func (s *SysDictionaryUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[dictionaryDomain.DictionaryDetail], error) {
+func (s *SysDictionaryUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[dictionaryDomain.DictionaryDetail], error) {
 	s.Logger.Info("Searching dictionary_detail with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysDictionaryRepository.SearchPaginated(filters)
+	return s.sysDictionaryRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *SysDictionaryUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *SysDictionaryUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching dictionary_detail by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysDictionaryRepository.SearchByProperty(property, searchText)
+	return s.sysDictionaryRepository.SearchByProperty(ctx, property, searchText)
 }
 
-func (s *SysDictionaryUseCase) GetOneByMap(userMap map[string]interface{}) (*dictionaryDomain.DictionaryDetail, error) {
-	return s.sysDictionaryRepository.GetOneByMap(userMap)
+func (s *SysDictionaryUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*dictionaryDomain.DictionaryDetail, error) {
+	return s.sysDictionaryRepository.GetOneByMap(ctx, userMap)
 }
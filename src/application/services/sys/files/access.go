@@ -0,0 +1,221 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+	"go.uber.org/zap"
+)
+
+// FileAccessConfig configures how files are shared. Files that aren't public are read through
+// the download endpoint of BaseURL, the api address.
+type FileAccessConfig struct {
+	// DefaultVisibility of the uploads that don't choose one
+	DefaultVisibility string
+	BaseURL           string
+	// SignedURLTTL is the lifetime of a signed url when none is asked, MaxSignedURLTTL bounds it
+	SignedURLTTL    time.Duration
+	MaxSignedURLTTL time.Duration
+}
+
+func LoadFileAccessConfigFromEnv() FileAccessConfig {
+	return FileAccessConfig{
+		DefaultVisibility: files.DefaultVisibility(),
+		BaseURL:           strings.TrimSuffix(sharedUtil.GetEnv("APP_URL", ""), "/"),
+		SignedURLTTL:      time.Duration(sharedUtil.GetEnvAsInt("FILE_SIGNED_URL_TTL_SECONDS", 900)) * time.Second,
+		MaxSignedURLTTL:   time.Duration(sharedUtil.GetEnvAsInt("FILE_SIGNED_URL_MAX_TTL_SECONDS", 7*24*3600)) * time.Second,
+	}
+}
+
+// validOwner rejects an unknown visibility before the content is stored
+func validOwner(owner filesDomain.FileOwner) error {
+	if owner.Visibility != "" && !filesDomain.ValidVisibility(owner.Visibility) {
+		return domainErrors.NewAppError(filesDomain.ErrInvalidVisibility, domainErrors.ValidationError)
+	}
+	return nil
+}
+
+// owned fills the visibility and owner of a new file, the visibility is checked by validOwner
+func (s *SysFilesUseCase) owned(file *filesDomain.SysFiles, owner filesDomain.FileOwner) {
+	file.Visibility = owner.Visibility
+	if file.Visibility == "" {
+		file.Visibility = s.access.DefaultVisibility
+	}
+	if file.Visibility == "" {
+		file.Visibility = filesDomain.VisibilityPrivate
+	}
+	if owner.UserID != 0 {
+		file.OwnerID = &owner.UserID
+	}
+	if owner.RoleID != 0 {
+		file.OwnerRoleID = &owner.RoleID
+	}
+}
+
+// canRead applies the visibility of the file to the requester
+func canRead(file *filesDomain.SysFiles, access filesDomain.FileAccess) bool {
+	switch {
+	case isPublic(file) || access.Granted:
+		return true
+	case file.OwnerID != nil && *file.OwnerID == access.UserID:
+		return true
+	case file.Visibility == filesDomain.VisibilityRole && file.OwnerRoleID != nil && *file.OwnerRoleID == access.RoleID:
+		return true
+	}
+	return false
+}
+
+// blobReadable tells whether the owner can read one of the files that share the blob
+func (s *SysFilesUseCase) blobReadable(ctx context.Context, blobID int64, owner filesDomain.FileOwner) (bool, error) {
+	shared, err := s.sysFilesRepository.GetByBlobID(ctx, blobID)
+	if err != nil {
+		return false, err
+	}
+	access := filesDomain.FileAccess{UserID: owner.UserID, RoleID: owner.RoleID}
+	for i := range *shared {
+		if canRead(&(*shared)[i], access) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isPublic also holds for the files recorded before visibilities existed
+func isPublic(file *filesDomain.SysFiles) bool {
+	return file.Visibility == filesDomain.VisibilityPublic || file.Visibility == ""
+}
+
+// readable returns the file when the requester may read it, a file that isn't shared with the
+// requester is reported as not found to hide its existence
+func (s *SysFilesUseCase) readable(ctx context.Context, id int, access filesDomain.FileAccess) (*filesDomain.SysFiles, error) {
+	file, err := s.sysFilesRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canRead(file, access) {
+		s.Logger.Warn("File access denied", zap.Int("id", id), zap.Int64("userId", access.UserID), zap.Int64("roleId", access.RoleID))
+		return nil, domainErrors.NewAppError(filesDomain.ErrFileAccessDenied, domainErrors.NotFound)
+	}
+	return file, nil
+}
+
+// Download implements ISysFilesService.
+func (s *SysFilesUseCase) Download(ctx context.Context, id int, access filesDomain.FileAccess) (*filesDomain.SysFiles, io.ReadCloser, error) {
+	file, err := s.readable(ctx, id, access)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.openObject(ctx, file.StorageEngine, file.FilePath)
+	if err != nil {
+		s.Logger.Error("Error opening stored file", zap.Int("id", id), zap.String("key", file.FilePath), zap.Error(err))
+		return nil, nil, storageError(err)
+	}
+	return s.withURL(file), content, nil
+}
+
+// SignedURL implements ISysFilesService.
+// Local files are signed by the local driver, remote files get a presigned url of their bucket.
+func (s *SysFilesUseCase) SignedURL(ctx context.Context, id int, access filesDomain.FileAccess, expires time.Duration) (string, time.Time, error) {
+	if expires <= 0 {
+		expires = s.access.SignedURLTTL
+	}
+	if expires > s.access.MaxSignedURLTTL {
+		return "", time.Time{}, domainErrors.NewAppError(fmt.Errorf("signed urls expire within %s", s.access.MaxSignedURLTTL),
+			domainErrors.ValidationError)
+	}
+	file, err := s.readable(ctx, id, access)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	driver, err := s.storage.Driver(file.StorageEngine)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(expires)
+	signed, err := driver.SignedURL(ctx, file.FilePath, expires)
+	if err != nil {
+		s.Logger.Error("Error signing file url", zap.Int("id", id), zap.String("engine", file.StorageEngine), zap.Error(err))
+		return "", time.Time{}, err
+	}
+	s.Logger.Info("File url signed", zap.Int("id", id), zap.Int64("userId", access.UserID), zap.Time("expiresAt", expiresAt))
+	return signed, expiresAt, nil
+}
+
+// OpenSigned implements ISysFilesService.
+func (s *SysFilesUseCase) OpenSigned(ctx context.Context, key string, expires string, signature string) (io.ReadCloser, error) {
+	local, err := s.localDriver()
+	if err != nil {
+		return nil, err
+	}
+	if err := local.VerifySignedURL(key, expires, signature); err != nil {
+		s.Logger.Warn("Signed url rejected", zap.String("key", key), zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.NotAuthorized)
+	}
+	content, err := local.Get(ctx, key)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	return content, nil
+}
+
+// OpenPublic implements ISysFilesService.
+func (s *SysFilesUseCase) OpenPublic(ctx context.Context, key string) (io.ReadCloser, error) {
+	if storage.ValidKey(key) != nil {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	local, err := s.localDriver()
+	if err != nil {
+		return nil, err
+	}
+	public, err := s.sysFilesRepository.IsPublicPath(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !public {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	content, err := local.Get(ctx, key)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	return content, nil
+}
+
+func (s *SysFilesUseCase) localDriver() (*storage.LocalDriver, error) {
+	driver, err := s.storage.Driver(storage.EngineLocal)
+	if err != nil {
+		return nil, err
+	}
+	local, ok := driver.(*storage.LocalDriver)
+	if !ok {
+		return nil, fmt.Errorf("storage engine %q is not the local driver", storage.EngineLocal)
+	}
+	return local, nil
+}
+
+// storageError reports a missing object as not found
+func storageError(err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return err
+}
+
+// downloadURL is the authenticated address of a file that isn't public
+func (s *SysFilesUseCase) downloadURL(file *filesDomain.SysFiles) string {
+	return fmt.Sprintf("%s/v1/file/%d/download", s.access.BaseURL, file.ID)
+}
+
+func (s *SysFilesUseCase) thumbnailURL(file *filesDomain.SysFiles, size string) string {
+	return fmt.Sprintf("%s/v1/file/%d/thumbnail?size=%s", s.access.BaseURL, file.ID, url.QueryEscape(size))
+}
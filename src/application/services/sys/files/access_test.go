@@ -0,0 +1,146 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uploadOwned(t *testing.T, useCase *SysFilesUseCase, name, content string, owner filesDomain.FileOwner) *filesDomain.SysFiles {
+	record, err := useCase.Upload(context.Background(), filesDomain.UploadFile{
+		OriginName: name,
+		Size:       -1,
+		Content:    strings.NewReader(content),
+		Owner:      owner,
+	})
+	require.NoError(t, err)
+	return record
+}
+
+func assertAppError(t *testing.T, err error, errorType domainErrors.ErrorType) {
+	t.Helper()
+	var appErr *domainErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errorType, appErr.Type)
+}
+
+func TestPrivateFileAccess(t *testing.T) {
+	useCase, _, _ := newTestUseCase(t)
+	ctx := context.Background()
+	private := uploadOwned(t, useCase, "salary.txt", "private content",
+		filesDomain.FileOwner{UserID: 7, RoleID: 2, Visibility: filesDomain.VisibilityPrivate})
+	shared := uploadOwned(t, useCase, "team.txt", "team content",
+		filesDomain.FileOwner{UserID: 7, RoleID: 2, Visibility: filesDomain.VisibilityRole})
+	assert.Equal(t, fmt.Sprintf("http://api/v1/file/%d/download", private.ID), private.FileUrl)
+
+	read := func(id int64, access filesDomain.FileAccess) (string, error) {
+		_, content, err := useCase.Download(ctx, int(id), access)
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = content.Close() }()
+		data, err := io.ReadAll(content)
+		return string(data), err
+	}
+
+	data, err := read(private.ID, filesDomain.FileAccess{UserID: 7, RoleID: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "private content", data)
+	_, err = read(private.ID, filesDomain.FileAccess{UserID: 8, RoleID: 2})
+	assertAppError(t, err, domainErrors.NotFound)
+	_, err = read(private.ID, filesDomain.FileAccess{UserID: 8, RoleID: 3, Granted: true})
+	assert.NoError(t, err)
+
+	_, err = read(shared.ID, filesDomain.FileAccess{UserID: 8, RoleID: 2})
+	assert.NoError(t, err)
+	_, err = read(shared.ID, filesDomain.FileAccess{UserID: 8, RoleID: 3})
+	assertAppError(t, err, domainErrors.NotFound)
+
+	_, err = useCase.Upload(ctx, filesDomain.UploadFile{
+		OriginName: "x.txt",
+		Size:       -1,
+		Content:    strings.NewReader("x"),
+		Owner:      filesDomain.FileOwner{Visibility: "everyone"},
+	})
+	assertAppError(t, err, domainErrors.ValidationError)
+}
+
+func TestInstantUploadOfPrivateFile(t *testing.T) {
+	useCase, repository, _ := newTestUseCase(t)
+	ctx := context.Background()
+	private := uploadOwned(t, useCase, "salary.txt", "private content",
+		filesDomain.FileOwner{UserID: 7, RoleID: 2, Visibility: filesDomain.VisibilityPrivate})
+	shared := uploadOwned(t, useCase, "team.txt", "team content",
+		filesDomain.FileOwner{UserID: 7, RoleID: 2, Visibility: filesDomain.VisibilityRole})
+
+	stolen, err := useCase.InstantUpload(ctx, private.FileMD5, private.FileSize, "mine.txt",
+		filesDomain.FileOwner{UserID: 8, RoleID: 2, Visibility: filesDomain.VisibilityPublic})
+	require.NoError(t, err)
+	assert.Nil(t, stolen, "knowing the md5 and size of a private file doesn't share it")
+	assert.EqualValues(t, 1, repository.blobs[*private.BlobID].RefCount)
+
+	_, err = useCase.InstantUpload(ctx, shared.FileMD5, shared.FileSize, "team copy.txt",
+		filesDomain.FileOwner{UserID: 9, RoleID: 3})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, repository.blobs[*shared.BlobID].RefCount)
+
+	copied, err := useCase.InstantUpload(ctx, shared.FileMD5, shared.FileSize, "team copy.txt",
+		filesDomain.FileOwner{UserID: 8, RoleID: 2})
+	require.NoError(t, err)
+	require.NotNil(t, copied)
+	assert.Equal(t, shared.BlobID, copied.BlobID)
+
+	own, err := useCase.InstantUpload(ctx, private.FileMD5, private.FileSize, "salary copy.txt",
+		filesDomain.FileOwner{UserID: 7, RoleID: 2, Visibility: filesDomain.VisibilityPrivate})
+	require.NoError(t, err)
+	require.NotNil(t, own)
+	assert.EqualValues(t, 2, repository.blobs[*private.BlobID].RefCount)
+}
+
+func TestPublicAndSignedFiles(t *testing.T) {
+	useCase, _, _ := newTestUseCase(t)
+	ctx := context.Background()
+	public := upload(t, useCase, "logo.txt", "public content")
+	private := uploadOwned(t, useCase, "secret.txt", "secret content",
+		filesDomain.FileOwner{UserID: 7, Visibility: filesDomain.VisibilityPrivate})
+
+	content, err := useCase.OpenPublic(ctx, public.FilePath)
+	require.NoError(t, err)
+	_ = content.Close()
+	_, err = useCase.OpenPublic(ctx, private.FilePath)
+	assertAppError(t, err, domainErrors.NotFound)
+	_, err = useCase.OpenPublic(ctx, "../"+private.FilePath)
+	assertAppError(t, err, domainErrors.NotFound)
+
+	_, _, err = useCase.SignedURL(ctx, int(private.ID), filesDomain.FileAccess{UserID: 8}, 0)
+	assertAppError(t, err, domainErrors.NotFound)
+	_, _, err = useCase.SignedURL(ctx, int(private.ID), filesDomain.FileAccess{UserID: 7}, 2*time.Hour)
+	assertAppError(t, err, domainErrors.ValidationError)
+
+	signed, expiresAt, err := useCase.SignedURL(ctx, int(private.ID), filesDomain.FileAccess{UserID: 7}, 0)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, 5*time.Second)
+	parsed, err := url.Parse(signed)
+	require.NoError(t, err)
+	key := strings.TrimPrefix(parsed.Path, "/v1/file/signed/")
+	assert.Equal(t, private.FilePath, key)
+
+	content, err = useCase.OpenSigned(ctx, key, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	_ = content.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "secret content", string(data))
+
+	_, err = useCase.OpenSigned(ctx, public.FilePath, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+	assertAppError(t, err, domainErrors.NotAuthorized)
+}
@@ -0,0 +1,198 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	"go.uber.org/zap"
+)
+
+// maxAttachmentLabel is the size of the label column
+const maxAttachmentLabel = 191
+
+// AttachmentEntity is a type of entity files are attached to, its rows are in Table. SoftDelete
+// tells that a row with deleted_at set is a deleted entity.
+type AttachmentEntity struct {
+	Table      string
+	SoftDelete bool
+}
+
+// AttachmentEntities are the entity types files can be attached to, registered by the modules
+// owning them. Whoever deletes an entity of one of them publishes an EntityDeletedEvent, the
+// attachments the event missed are pruned by the file garbage collection.
+type AttachmentEntities struct {
+	entities map[string]AttachmentEntity
+}
+
+func NewAttachmentEntities() *AttachmentEntities {
+	return &AttachmentEntities{entities: make(map[string]AttachmentEntity)}
+}
+
+// Register makes entityType attachable, registering it again replaces its table
+func (e *AttachmentEntities) Register(entityType string, entity AttachmentEntity) {
+	e.entities[entityType] = entity
+}
+
+func (e *AttachmentEntities) Get(entityType string) (AttachmentEntity, bool) {
+	entity, ok := e.entities[entityType]
+	return entity, ok
+}
+
+// Types returns the registered entity types in order
+func (e *AttachmentEntities) Types() []string {
+	return slices.Sorted(maps.Keys(e.entities))
+}
+
+type FileAttachmentUseCase struct {
+	filesUseCase       filesDomain.ISysFilesService
+	sysFilesRepository files.ISysFilesRepository
+	entities           *AttachmentEntities
+	Logger             *logger.Logger
+}
+
+func NewFileAttachmentUseCase(filesUseCase filesDomain.ISysFilesService, sysFilesRepository files.ISysFilesRepository,
+	entities *AttachmentEntities, loggerInstance *logger.Logger) filesDomain.IFileAttachmentService {
+	return &FileAttachmentUseCase{
+		filesUseCase:       filesUseCase,
+		sysFilesRepository: sysFilesRepository,
+		entities:           entities,
+		Logger:             loggerInstance,
+	}
+}
+
+func (s *FileAttachmentUseCase) entity(entityType string) (AttachmentEntity, error) {
+	entity, ok := s.entities.Get(entityType)
+	if !ok {
+		return entity, domainErrors.NewAppError(fmt.Errorf("%w: %q", filesDomain.ErrUnknownEntityType, entityType),
+			domainErrors.ValidationError)
+	}
+	return entity, nil
+}
+
+// Attach implements IFileAttachmentService.
+func (s *FileAttachmentUseCase) Attach(ctx context.Context, attachment filesDomain.FileAttachment,
+	access filesDomain.FileAccess) (*filesDomain.FileAttachment, error) {
+	entity, err := s.entity(attachment.EntityType)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachment.Label) > maxAttachmentLabel {
+		return nil, domainErrors.NewAppError(fmt.Errorf("the label exceeds %d bytes", maxAttachmentLabel),
+			domainErrors.ValidationError)
+	}
+	exists, err := s.sysFilesRepository.EntityExists(ctx, entity.Table, entity.SoftDelete, attachment.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domainErrors.NewAppError(filesDomain.ErrEntityNotFound, domainErrors.NotFound)
+	}
+	file, err := s.sysFilesRepository.GetByID(ctx, int(attachment.FileID))
+	if err != nil {
+		return nil, err
+	}
+	if !canRead(file, access) {
+		s.Logger.Warn("File attachment denied", zap.Int64("fileId", attachment.FileID), zap.Int64("userId", access.UserID))
+		return nil, domainErrors.NewAppError(filesDomain.ErrFileAccessDenied, domainErrors.NotFound)
+	}
+	saved, err := s.sysFilesRepository.AttachFile(ctx, &attachment)
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("File attached", zap.String("entityType", saved.EntityType), zap.Int64("entityId", saved.EntityID),
+		zap.Int64("fileId", saved.FileID), zap.Int64("userId", access.UserID))
+	return saved, nil
+}
+
+// Detach implements IFileAttachmentService.
+// The file itself is kept, the garbage collection removes it once nothing references it.
+func (s *FileAttachmentUseCase) Detach(ctx context.Context, entityType string, entityID int64, fileID int64) error {
+	if _, err := s.entity(entityType); err != nil {
+		return err
+	}
+	found, err := s.sysFilesRepository.DetachFile(ctx, entityType, entityID, fileID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domainErrors.NewAppError(filesDomain.ErrAttachmentNotFound, domainErrors.NotFound)
+	}
+	s.Logger.Info("File detached", zap.String("entityType", entityType), zap.Int64("entityId", entityID),
+		zap.Int64("fileId", fileID))
+	return nil
+}
+
+// Reorder implements IFileAttachmentService.
+func (s *FileAttachmentUseCase) Reorder(ctx context.Context, entityType string, entityID int64, fileIDs []int64) (*[]filesDomain.FileAttachment, error) {
+	if _, err := s.entity(entityType); err != nil {
+		return nil, err
+	}
+	if err := s.sysFilesRepository.SortAttachments(ctx, entityType, entityID, fileIDs); err != nil {
+		return nil, err
+	}
+	return s.List(ctx, entityType, entityID)
+}
+
+// List implements IFileAttachmentService.
+func (s *FileAttachmentUseCase) List(ctx context.Context, entityType string, entityID int64) (*[]filesDomain.FileAttachment, error) {
+	if _, err := s.entity(entityType); err != nil {
+		return nil, err
+	}
+	attachments, err := s.sysFilesRepository.GetAttachments(ctx, entityType, entityID)
+	if err != nil || len(*attachments) == 0 {
+		return attachments, err
+	}
+	fileIDs := make([]int64, len(*attachments))
+	for i, attachment := range *attachments {
+		fileIDs[i] = attachment.FileID
+	}
+	attached, err := s.filesUseCase.GetByIDs(ctx, fileIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*filesDomain.SysFiles, len(*attached))
+	for i := range *attached {
+		byID[(*attached)[i].ID] = &(*attached)[i]
+	}
+	for i := range *attachments {
+		(*attachments)[i].File = byID[(*attachments)[i].FileID]
+	}
+	return attachments, nil
+}
+
+// DetachEntity implements IFileAttachmentService.
+func (s *FileAttachmentUseCase) DetachEntity(ctx context.Context, entityType string, entityID int64) (int64, error) {
+	detached, err := s.sysFilesRepository.DetachEntity(ctx, entityType, entityID)
+	if err != nil {
+		return 0, err
+	}
+	if detached > 0 {
+		s.Logger.Info("Deleted entity files detached", zap.String("entityType", entityType), zap.Int64("entityId", entityID),
+			zap.Int64("count", detached))
+	}
+	return detached, nil
+}
+
+// Prune implements IFileAttachmentService.
+func (s *FileAttachmentUseCase) Prune(ctx context.Context) (int64, error) {
+	var pruned int64
+	for _, entityType := range s.entities.Types() {
+		entity, _ := s.entities.Get(entityType)
+		detached, err := s.sysFilesRepository.DetachDeletedEntities(ctx, entityType, entity.Table, entity.SoftDelete)
+		if err != nil {
+			return pruned, err
+		}
+		if detached > 0 {
+			s.Logger.Info("Attachments of deleted entities pruned", zap.String("entityType", entityType),
+				zap.Int64("count", detached))
+		}
+		pruned += detached
+	}
+	return pruned, nil
+}
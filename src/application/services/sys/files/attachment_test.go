@@ -0,0 +1,245 @@
+package files
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func (r *memoryRepository) GetByIDs(_ context.Context, ids []int64) (*[]filesDomain.SysFiles, error) {
+	var found []filesDomain.SysFiles
+	for _, id := range ids {
+		if file, ok := r.files[id]; ok {
+			found = append(found, file)
+		}
+	}
+	return &found, nil
+}
+
+func (r *memoryRepository) GetByBlobID(_ context.Context, blobID int64) (*[]filesDomain.SysFiles, error) {
+	var found []filesDomain.SysFiles
+	for _, file := range r.files {
+		if file.BlobID != nil && *file.BlobID == blobID {
+			found = append(found, file)
+		}
+	}
+	return &found, nil
+}
+
+func (r *memoryRepository) EntityExists(_ context.Context, _ string, _ bool, id int64) (bool, error) {
+	return r.entities[id], nil
+}
+
+func (r *memoryRepository) AttachFile(_ context.Context, attachment *filesDomain.FileAttachment) (*filesDomain.FileAttachment, error) {
+	sort := 0
+	for i, stored := range r.attachments {
+		if stored.EntityType != attachment.EntityType || stored.EntityID != attachment.EntityID {
+			continue
+		}
+		if stored.FileID == attachment.FileID {
+			r.attachments[i].Label = attachment.Label
+			return &r.attachments[i], nil
+		}
+		sort = max(sort, stored.Sort)
+	}
+	r.nextID++
+	saved := *attachment
+	saved.ID = r.nextID
+	saved.Sort = sort + 1
+	r.attachments = append(r.attachments, saved)
+	return &saved, nil
+}
+
+func (r *memoryRepository) DetachFile(_ context.Context, entityType string, entityID int64, fileID int64) (bool, error) {
+	count := len(r.attachments)
+	r.attachments = slices.DeleteFunc(r.attachments, func(attachment filesDomain.FileAttachment) bool {
+		return attachment.EntityType == entityType && attachment.EntityID == entityID && attachment.FileID == fileID
+	})
+	return len(r.attachments) < count, nil
+}
+
+func (r *memoryRepository) GetAttachments(_ context.Context, entityType string, entityID int64) (*[]filesDomain.FileAttachment, error) {
+	found := []filesDomain.FileAttachment{}
+	for _, attachment := range r.attachments {
+		if attachment.EntityType == entityType && attachment.EntityID == entityID {
+			found = append(found, attachment)
+		}
+	}
+	slices.SortFunc(found, func(a, b filesDomain.FileAttachment) int { return cmp.Compare(a.Sort, b.Sort) })
+	return &found, nil
+}
+
+func (r *memoryRepository) SortAttachments(ctx context.Context, entityType string, entityID int64, fileIDs []int64) error {
+	attached, _ := r.GetAttachments(ctx, entityType, entityID)
+	if len(*attached) != len(fileIDs) {
+		return domainErrors.NewAppError(filesDomain.ErrAttachmentOrder, domainErrors.ValidationError)
+	}
+	for i := range r.attachments {
+		if r.attachments[i].EntityType == entityType && r.attachments[i].EntityID == entityID {
+			position := slices.Index(fileIDs, r.attachments[i].FileID)
+			if position < 0 {
+				return domainErrors.NewAppError(filesDomain.ErrAttachmentOrder, domainErrors.ValidationError)
+			}
+			r.attachments[i].Sort = position + 1
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) DetachEntity(_ context.Context, entityType string, entityID int64) (int64, error) {
+	count := len(r.attachments)
+	r.attachments = slices.DeleteFunc(r.attachments, func(attachment filesDomain.FileAttachment) bool {
+		return attachment.EntityType == entityType && attachment.EntityID == entityID
+	})
+	return int64(count - len(r.attachments)), nil
+}
+
+func (r *memoryRepository) DetachDeletedEntities(_ context.Context, entityType string, _ string, _ bool) (int64, error) {
+	count := len(r.attachments)
+	r.attachments = slices.DeleteFunc(r.attachments, func(attachment filesDomain.FileAttachment) bool {
+		return attachment.EntityType == entityType && !r.entities[attachment.EntityID]
+	})
+	return int64(count - len(r.attachments)), nil
+}
+
+func newTestAttachmentUseCase(t *testing.T) (*FileAttachmentUseCase, *SysFilesUseCase, *memoryRepository) {
+	useCase, repository, _ := newTestUseCase(t)
+	entities := NewAttachmentEntities()
+	entities.Register("customer", AttachmentEntity{Table: "customers"})
+	attachments := NewFileAttachmentUseCase(useCase, repository, entities, &logger.Logger{Log: zap.NewNop()})
+	return attachments.(*FileAttachmentUseCase), useCase, repository
+}
+
+func TestFileAttachments(t *testing.T) {
+	attachments, useCase, repository := newTestAttachmentUseCase(t)
+	ctx := context.Background()
+	repository.entities[42] = true
+	owner := filesDomain.FileOwner{UserID: 7, Visibility: filesDomain.VisibilityPrivate}
+	access := filesDomain.FileAccess{UserID: 7}
+	files := []*filesDomain.SysFiles{
+		uploadOwned(t, useCase, "a.pdf", "a", owner),
+		uploadOwned(t, useCase, "b.pdf", "b", owner),
+		uploadOwned(t, useCase, "c.pdf", "c", owner),
+	}
+	for _, file := range files {
+		_, err := attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "customer", EntityID: 42, FileID: file.ID}, access)
+		require.NoError(t, err)
+	}
+	relabeled, err := attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "customer", EntityID: 42,
+		FileID: files[0].ID, Label: "contract"}, access)
+	require.NoError(t, err)
+	assert.Equal(t, 1, relabeled.Sort, "attaching again keeps the position")
+	assert.Equal(t, "contract", relabeled.Label)
+
+	listed, err := attachments.List(ctx, "customer", 42)
+	require.NoError(t, err)
+	require.Len(t, *listed, 3)
+	assert.Equal(t, files[2].ID, (*listed)[2].FileID)
+	require.NotNil(t, (*listed)[0].File)
+	assert.Equal(t, "a.pdf", (*listed)[0].File.FileOriginName)
+	assert.NotEmpty(t, (*listed)[0].File.FileUrl)
+
+	reordered, err := attachments.Reorder(ctx, "customer", 42, []int64{files[2].ID, files[0].ID, files[1].ID})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{files[2].ID, files[0].ID, files[1].ID}, []int64{(*reordered)[0].FileID, (*reordered)[1].FileID,
+		(*reordered)[2].FileID})
+	_, err = attachments.Reorder(ctx, "customer", 42, []int64{files[0].ID})
+	assertAppError(t, err, domainErrors.ValidationError)
+
+	require.NoError(t, attachments.Detach(ctx, "customer", 42, files[1].ID))
+	assertAppError(t, attachments.Detach(ctx, "customer", 42, files[1].ID), domainErrors.NotFound)
+	assert.Contains(t, repository.files, files[1].ID, "detaching keeps the file")
+}
+
+func TestFileAttachmentValidation(t *testing.T) {
+	attachments, useCase, repository := newTestAttachmentUseCase(t)
+	ctx := context.Background()
+	repository.entities[42] = true
+	private := uploadOwned(t, useCase, "a.pdf", "a", filesDomain.FileOwner{UserID: 7, Visibility: filesDomain.VisibilityPrivate})
+
+	_, err := attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "order", EntityID: 42, FileID: private.ID},
+		filesDomain.FileAccess{UserID: 7})
+	assertAppError(t, err, domainErrors.ValidationError)
+	_, err = attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "customer", EntityID: 43, FileID: private.ID},
+		filesDomain.FileAccess{UserID: 7})
+	assertAppError(t, err, domainErrors.NotFound)
+	_, err = attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "customer", EntityID: 42, FileID: private.ID},
+		filesDomain.FileAccess{UserID: 8})
+	assertAppError(t, err, domainErrors.NotFound)
+	_, err = attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "customer", EntityID: 42, FileID: private.ID},
+		filesDomain.FileAccess{UserID: 8, Granted: true})
+	require.NoError(t, err)
+}
+
+func TestDeletedEntityAttachments(t *testing.T) {
+	attachments, useCase, repository := newTestAttachmentUseCase(t)
+	ctx := context.Background()
+	repository.entities[42] = true
+	repository.entities[43] = true
+	owner := filesDomain.FileOwner{UserID: 7}
+	first := uploadOwned(t, useCase, "a.pdf", "a", owner)
+	second := uploadOwned(t, useCase, "b.pdf", "b", owner)
+	_, err := attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "customer", EntityID: 42, FileID: first.ID},
+		filesDomain.FileAccess{UserID: 7})
+	require.NoError(t, err)
+	_, err = attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "customer", EntityID: 43, FileID: second.ID},
+		filesDomain.FileAccess{UserID: 7})
+	require.NoError(t, err)
+
+	report, err := useCase.CollectGarbage(ctx, filesDomain.GCOptions{CollectFiles: true, Grace: -time.Minute})
+	require.NoError(t, err)
+	assert.Empty(t, report.UnreferencedFiles, "attached files are referenced")
+
+	detached, err := attachments.DetachEntity(ctx, "customer", 42)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), detached)
+	// the other entity is deleted without publishing the event
+	delete(repository.entities, 43)
+	pruned, err := attachments.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	_, err = useCase.CollectGarbage(ctx, filesDomain.GCOptions{CollectFiles: true, Grace: -time.Minute})
+	require.NoError(t, err)
+	assert.Empty(t, repository.files, "the files of the deleted entities are collected")
+}
+
+func TestRegisteredAttachmentEntities(t *testing.T) {
+	attachments, useCase, repository := newTestAttachmentUseCase(t)
+	ctx := context.Background()
+	repository.entities[42] = true
+	access := filesDomain.FileAccess{UserID: 7}
+	owner := filesDomain.FileOwner{UserID: 7}
+	first := uploadOwned(t, useCase, "a.pdf", "a", owner)
+	second := uploadOwned(t, useCase, "b.pdf", "b", owner)
+
+	_, err := attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "contract", EntityID: 42, FileID: first.ID}, access)
+	assertAppError(t, err, domainErrors.ValidationError)
+
+	// a module registers its entity type after the attachment use case is built
+	attachments.entities.Register("contract", AttachmentEntity{Table: "contracts", SoftDelete: true})
+	assert.Equal(t, []string{"contract", "customer"}, attachments.entities.Types())
+	_, err = attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "contract", EntityID: 42, FileID: first.ID}, access)
+	require.NoError(t, err)
+	_, err = attachments.Attach(ctx, filesDomain.FileAttachment{EntityType: "customer", EntityID: 42, FileID: second.ID}, access)
+	require.NoError(t, err)
+
+	listed, err := attachments.List(ctx, "contract", 42)
+	require.NoError(t, err)
+	require.Len(t, *listed, 1)
+	assert.Equal(t, first.ID, (*listed)[0].FileID)
+
+	delete(repository.entities, 42)
+	pruned, err := attachments.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pruned, "the attachments of every registered type are pruned")
+}
@@ -0,0 +1,194 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+	"go.uber.org/zap"
+)
+
+// maxObjectKeyLength is the size of the file_path column
+const maxObjectKeyLength = 191
+
+// md5ETag matches the etag of an object uploaded in one part, the md5 of its content. The etag
+// of a multipart upload isn't.
+var md5ETag = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+
+// UploadCallbackConfig configures the callbacks of the uploads made with STS tokens, Engine is
+// the storage engine of the bucket the tokens write to
+type UploadCallbackConfig struct {
+	Engine     string
+	BucketName string
+	// KeyPrefix is the storage key prefix the upload prefixes start with
+	KeyPrefix string
+}
+
+func LoadUploadCallbackConfigFromEnv() UploadCallbackConfig {
+	return UploadCallbackConfig{
+		Engine:     storage.EngineAliyunOSS,
+		BucketName: sharedUtil.GetEnv("OSS_BUCKET_NAME", ""),
+	}
+}
+
+// UploadGrantStore returns the grants stored by the STS use case, implemented by
+// cache.STSCacheService
+type UploadGrantStore interface {
+	GetUploadGrant(ctx context.Context, prefix string) (*filesDomain.STSGrant, error)
+}
+
+type UploadCallbackUseCase struct {
+	filesUseCase       filesDomain.ISysFilesService
+	sysFilesRepository files.ISysFilesRepository
+	grants             UploadGrantStore
+	storage            *storage.Manager
+	config             UploadCallbackConfig
+	Logger             *logger.Logger
+}
+
+func NewUploadCallbackUseCase(filesUseCase filesDomain.ISysFilesService, sysFilesRepository files.ISysFilesRepository,
+	grants UploadGrantStore, storageManager *storage.Manager, config UploadCallbackConfig,
+	loggerInstance *logger.Logger) filesDomain.IUploadCallbackService {
+	return &UploadCallbackUseCase{
+		filesUseCase:       filesUseCase,
+		sysFilesRepository: sysFilesRepository,
+		grants:             grants,
+		storage:            storageManager,
+		config:             config,
+		Logger:             loggerInstance,
+	}
+}
+
+// Register implements IUploadCallbackService.
+// The callback body is written by the client, only the key is trusted from it: the owner and the
+// limits come from the grant of the prefix, the size, type and md5 from the object itself.
+func (s *UploadCallbackUseCase) Register(ctx context.Context, object filesDomain.UploadedObject) (*filesDomain.SysFiles, error) {
+	driver, err := s.storage.Driver(s.config.Engine)
+	if err != nil {
+		return nil, domainErrors.NewAppError(filesDomain.ErrCallbackNotConfigured, domainErrors.UploadError)
+	}
+	if object.Bucket != "" && object.Bucket != s.config.BucketName {
+		return nil, domainErrors.NewAppError(fmt.Errorf("unknown bucket %q", object.Bucket), domainErrors.ValidationError)
+	}
+	if storage.ValidKey(object.Key) != nil || len(object.Key) > maxObjectKeyLength {
+		return nil, domainErrors.NewAppError(storage.ErrInvalidKey, domainErrors.ValidationError)
+	}
+	prefix, userID, ok := s.grantPrefix(object.Key)
+	if !ok {
+		s.Logger.Warn("Upload callback outside the upload prefixes", zap.String("key", object.Key))
+		return nil, domainErrors.NewAppError(filesDomain.ErrObjectNotGranted, domainErrors.NotAuthorized)
+	}
+	existing, err := s.sysFilesRepository.GetOneByMap(ctx, map[string]interface{}{
+		"file_path":      object.Key,
+		"storage_engine": s.config.Engine,
+		"owner_id":       userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if existing.ID != 0 {
+		s.Logger.Info("Upload callback repeated", zap.String("key", object.Key), zap.Int64("id", existing.ID))
+		return s.filesUseCase.GetByID(ctx, int(existing.ID))
+	}
+	grant, err := s.grants.GetUploadGrant(ctx, prefix)
+	if err != nil || grant.UserID != userID {
+		s.Logger.Warn("Upload callback without grant", zap.String("key", object.Key), zap.Error(err))
+		return nil, domainErrors.NewAppError(filesDomain.ErrObjectNotGranted, domainErrors.NotAuthorized)
+	}
+
+	info, err := driver.Stat(ctx, object.Key)
+	if err != nil {
+		s.Logger.Warn("Error reading uploaded object", zap.String("key", object.Key), zap.Error(err))
+		return nil, storageError(err)
+	}
+	if err := inScope(object.Key, info, grant.Scope); err != nil {
+		s.Logger.Warn("Uploaded object out of scope", zap.String("key", object.Key), zap.Int64("userId", userID), zap.Error(err))
+		if deleteErr := driver.Delete(ctx, object.Key); deleteErr != nil {
+			s.Logger.Warn("Error removing uploaded object", zap.String("key", object.Key), zap.Error(deleteErr))
+		}
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	fileMD5 := ""
+	if md5ETag.MatchString(info.ETag) {
+		fileMD5 = info.ETag
+	}
+	originName := path.Base(object.FileName)
+	if object.FileName == "" || !strings.EqualFold(path.Ext(originName), path.Ext(object.Key)) {
+		originName = path.Base(object.Key)
+	}
+	record, err := s.filesUseCase.RegisterObject(ctx, filesDomain.StoredObject{
+		Engine:      s.config.Engine,
+		Key:         object.Key,
+		MD5:         fileMD5,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		OriginName:  originName,
+		Owner:       filesDomain.FileOwner{UserID: grant.UserID, RoleID: grant.RoleID, Visibility: object.Visibility},
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Uploaded object registered", zap.String("key", object.Key), zap.Int64("id", record.ID), zap.Int64("userId", userID))
+	return record, nil
+}
+
+// stsRoot is the prefix every upload prefix starts with
+func (s *UploadCallbackUseCase) stsRoot() string {
+	return path.Join(s.config.KeyPrefix, STSPrefix) + "/"
+}
+
+// grantPrefix returns the upload prefix of a key, <root><user id>/<token id>/, and its user
+func (s *UploadCallbackUseCase) grantPrefix(key string) (string, int64, bool) {
+	rest, ok := strings.CutPrefix(key, s.stsRoot())
+	if !ok {
+		return "", 0, false
+	}
+	segments := strings.SplitN(rest, "/", 3)
+	if len(segments) != 3 || segments[1] == "" || segments[2] == "" {
+		return "", 0, false
+	}
+	userID, err := strconv.ParseInt(segments[0], 10, 64)
+	if err != nil || userID <= 0 {
+		return "", 0, false
+	}
+	return s.stsRoot() + segments[0] + "/" + segments[1] + "/", userID, true
+}
+
+// inScope checks the object against the limits the policy of its token couldn't enforce
+func inScope(key string, info *storage.ObjectInfo, scope filesDomain.STSScope) error {
+	if !strings.EqualFold(path.Ext(key), scope.Extension) {
+		return fmt.Errorf("%w: the extension must be %s", filesDomain.ErrObjectOutOfScope, scope.Extension)
+	}
+	if scope.MaxSize > 0 && info.Size > scope.MaxSize {
+		return fmt.Errorf("%w: %d bytes exceed the limit of %d bytes", filesDomain.ErrObjectOutOfScope, info.Size, scope.MaxSize)
+	}
+	if len(scope.ContentTypes) > 0 && info.ContentType != "" {
+		mediaType, _, err := mime.ParseMediaType(info.ContentType)
+		if err != nil || !slices.Contains(scope.ContentTypes, mediaType) {
+			return fmt.Errorf("%w: content type %q is not allowed", filesDomain.ErrObjectOutOfScope, info.ContentType)
+		}
+	}
+	return nil
+}
+
+// Reconcile implements IUploadCallbackService.
+// minAge leaves the uploads whose callback may still be on its way.
+func (s *UploadCallbackUseCase) Reconcile(ctx context.Context, minAge time.Duration, remove bool) ([]filesDomain.OrphanObject, error) {
+	driver, err := s.storage.Driver(s.config.Engine)
+	if err != nil {
+		return nil, err
+	}
+	return findOrphans(ctx, s.sysFilesRepository, driver, s.stsRoot(), time.Now().Add(-minAge), remove, s.Logger)
+}
@@ -0,0 +1,122 @@
+package files
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestCallbackUseCase records the callbacks of the local storage with the grants of an STS use case
+func newTestCallbackUseCase(t *testing.T) (*UploadCallbackUseCase, *STSUseCase, *memoryRepository, string) {
+	filesUseCase, repository, root := newTestUseCase(t)
+	stsUseCase, _, store, _ := newTestSTSUseCase()
+	config := UploadCallbackConfig{Engine: storage.EngineLocal, BucketName: "media", KeyPrefix: "public"}
+	callbacks := NewUploadCallbackUseCase(filesUseCase, repository, store, filesUseCase.storage, config,
+		&logger.Logger{Log: zap.NewNop()}).(*UploadCallbackUseCase)
+	return callbacks, stsUseCase, repository, root
+}
+
+func putObject(t *testing.T, root, key, content string) {
+	target := filepath.Join(root, filepath.FromSlash(key))
+	require.NoError(t, os.MkdirAll(filepath.Dir(target), 0o755))
+	require.NoError(t, os.WriteFile(target, []byte(content), 0o644))
+}
+
+func TestUploadCallbackRegistersGrantedObjects(t *testing.T) {
+	callbacks, stsUseCase, repository, root := newTestCallbackUseCase(t)
+	ctx := context.Background()
+	token, err := stsUseCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, RoleID: 2, FileName: "photo.png", FileSize: 10})
+	require.NoError(t, err)
+	key := token.Scope.Prefix + "photo.png"
+	putObject(t, root, key, "0123456789")
+
+	record, err := callbacks.Register(ctx, filesDomain.UploadedObject{Bucket: "media", Key: key, FileName: "Holiday.png",
+		Visibility: filesDomain.VisibilityRole})
+	require.NoError(t, err)
+	assert.Equal(t, key, record.FilePath)
+	assert.Equal(t, "781e5e245d69b566979b86e28d23f2c7", record.FileMD5)
+	assert.Equal(t, int64(10), record.FileSize)
+	assert.Equal(t, storage.EngineLocal, record.StorageEngine)
+	assert.Equal(t, "Holiday.png", record.FileOriginName)
+	assert.Equal(t, filesDomain.VisibilityRole, record.Visibility)
+	assert.Equal(t, int64(7), *record.OwnerID)
+	assert.Equal(t, int64(2), *record.OwnerRoleID)
+	require.NotNil(t, record.BlobID)
+
+	repeated, err := callbacks.Register(ctx, filesDomain.UploadedObject{Key: key})
+	require.NoError(t, err)
+	assert.Equal(t, record.ID, repeated.ID, "a repeated callback returns the recorded file")
+	assert.Len(t, repository.files, 1)
+
+	for _, rejected := range []filesDomain.UploadedObject{
+		{Key: "public/2026/10/19/photo.png"},
+		{Key: "public/sts/7/unknown/photo.png"},
+		{Key: strings.Replace(key, "/sts/7/", "/sts/8/", 1)},
+	} {
+		_, err = callbacks.Register(ctx, rejected)
+		assertAppError(t, err, domainErrors.NotAuthorized)
+	}
+	_, err = callbacks.Register(ctx, filesDomain.UploadedObject{Bucket: "other", Key: key})
+	assertAppError(t, err, domainErrors.ValidationError)
+	_, err = callbacks.Register(ctx, filesDomain.UploadedObject{Key: token.Scope.Prefix + "missing.png"})
+	assertAppError(t, err, domainErrors.NotFound)
+}
+
+func TestUploadCallbackRemovesObjectsOutOfScope(t *testing.T) {
+	callbacks, stsUseCase, repository, root := newTestCallbackUseCase(t)
+	ctx := context.Background()
+	token, err := stsUseCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "photo.png", FileSize: 10})
+	require.NoError(t, err)
+
+	for _, key := range []string{token.Scope.Prefix + "large.png", token.Scope.Prefix + "page.html"} {
+		putObject(t, root, key, "0123456789 and more")
+		_, err = callbacks.Register(ctx, filesDomain.UploadedObject{Key: key})
+		assertAppError(t, err, domainErrors.ValidationError)
+		_, err = os.Stat(filepath.Join(root, key))
+		assert.True(t, os.IsNotExist(err), "the object out of scope is removed")
+	}
+	assert.Empty(t, repository.files)
+}
+
+func TestUploadCallbackReconcile(t *testing.T) {
+	callbacks, stsUseCase, _, root := newTestCallbackUseCase(t)
+	ctx := context.Background()
+	token, err := stsUseCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "photo.png", FileSize: -1})
+	require.NoError(t, err)
+	registered, orphan, recent := token.Scope.Prefix+"a.png", token.Scope.Prefix+"b.png", token.Scope.Prefix+"c.png"
+	for _, key := range []string{registered, orphan, recent, "public/other.png"} {
+		putObject(t, root, key, key)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	for _, key := range []string{registered, orphan, "public/other.png"} {
+		require.NoError(t, os.Chtimes(filepath.Join(root, key), old, old))
+	}
+	_, err = callbacks.Register(ctx, filesDomain.UploadedObject{Key: registered})
+	require.NoError(t, err)
+
+	orphans, err := callbacks.Reconcile(ctx, time.Hour, false)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, orphan, orphans[0].Key)
+	assert.Equal(t, storage.EngineLocal, orphans[0].Engine)
+	assert.FileExists(t, filepath.Join(root, orphan), "orphans are only reported")
+
+	orphans, err = callbacks.Reconcile(ctx, time.Hour, true)
+	require.NoError(t, err)
+	assert.Len(t, orphans, 1)
+	assert.NoFileExists(t, filepath.Join(root, orphan))
+	assert.FileExists(t, filepath.Join(root, registered))
+	assert.FileExists(t, filepath.Join(root, recent), "recent uploads may still get their callback")
+	assert.FileExists(t, filepath.Join(root, "public/other.png"))
+}
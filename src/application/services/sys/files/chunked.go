@@ -0,0 +1,479 @@
+package files
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadpolicy"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/upload_session"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MaxUploadParts bounds the part numbers of a chunked upload
+const MaxUploadParts = 10000
+
+// ChunkedUploadConfig configures the chunked uploads. Parts are written below TempDir, every
+// instance serving uploads has to share it.
+type ChunkedUploadConfig struct {
+	TempDir string
+	// TTL is how long a session is kept after its last part
+	TTL         time.Duration
+	MaxPartSize int64
+	// MaxSize bounds the file size, 0 is unlimited
+	MaxSize int64
+}
+
+func LoadChunkedUploadConfigFromEnv() ChunkedUploadConfig {
+	return ChunkedUploadConfig{
+		TempDir:     sharedUtil.GetEnv("UPLOAD_TEMP_DIR", filepath.Join(os.TempDir(), "chunked_uploads")),
+		TTL:         time.Duration(sharedUtil.GetEnvAsInt("UPLOAD_SESSION_TTL_HOURS", 24)) * time.Hour,
+		MaxPartSize: int64(sharedUtil.GetEnvAsInt("UPLOAD_PART_MAX_SIZE_MB", 64)) << 20,
+		MaxSize:     int64(sharedUtil.GetEnvAsInt("UPLOAD_MAX_SIZE_MB", 0)) << 20,
+	}
+}
+
+type ChunkedUploadUseCase struct {
+	sessionRepository upload_session.IUploadSessionRepository
+	filesUseCase      filesDomain.ISysFilesService
+	// validator rejects names and sizes no policy accepts before any part is sent, the
+	// assembled content is validated by the files use case
+	validator *uploadpolicy.Validator
+	config    ChunkedUploadConfig
+	Logger    *logger.Logger
+}
+
+func NewChunkedUploadUseCase(
+	sessionRepository upload_session.IUploadSessionRepository,
+	filesUseCase filesDomain.ISysFilesService,
+	validator *uploadpolicy.Validator,
+	config ChunkedUploadConfig,
+	loggerInstance *logger.Logger) filesDomain.IChunkedUploadService {
+	return &ChunkedUploadUseCase{
+		sessionRepository: sessionRepository,
+		filesUseCase:      filesUseCase,
+		validator:         validator,
+		config:            config,
+		Logger:            loggerInstance,
+	}
+}
+
+// Initiate implements IChunkedUploadService.
+func (s *ChunkedUploadUseCase) Initiate(ctx context.Context, upload filesDomain.InitiateUpload) (*filesDomain.UploadSession, error) {
+	if upload.FileSize <= 0 {
+		return nil, domainErrors.NewAppError(errors.New("file size must be positive"), domainErrors.ValidationError)
+	}
+	if s.config.MaxSize > 0 && upload.FileSize > s.config.MaxSize {
+		return nil, domainErrors.NewAppError(fmt.Errorf("file size exceeds the limit of %d bytes", s.config.MaxSize),
+			domainErrors.ValidationError)
+	}
+	if s.validator != nil {
+		if _, err := s.validator.Check(upload.FileName, upload.FileSize); err != nil {
+			return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+		}
+	}
+	if upload.Visibility != "" && !filesDomain.ValidVisibility(upload.Visibility) {
+		return nil, domainErrors.NewAppError(filesDomain.ErrInvalidVisibility, domainErrors.ValidationError)
+	}
+	session, err := s.sessionRepository.Create(ctx, &filesDomain.UploadSession{
+		ID:            uuid.NewString(),
+		FileName:      upload.FileName,
+		FileSize:      upload.FileSize,
+		ContentType:   upload.ContentType,
+		FileMD5:       strings.ToLower(upload.FileMD5),
+		Status:        filesDomain.UploadStatusUploading,
+		CreatedBy:     upload.CreatedBy,
+		CreatedByRole: upload.CreatedByRole,
+		Visibility:    upload.Visibility,
+		ExpiresAt:     s.expiresAt(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Upload session initiated", zap.String("uploadId", session.ID), zap.String("fileName", session.FileName),
+		zap.Int64("fileSize", session.FileSize))
+	return session, nil
+}
+
+// Get implements IChunkedUploadService.
+func (s *ChunkedUploadUseCase) Get(ctx context.Context, uploadID string, userID int64) (*filesDomain.UploadSession, error) {
+	session, err := s.session(ctx, uploadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := s.sessionRepository.GetParts(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	session.Parts = *parts
+	return session, nil
+}
+
+// UploadPart implements IChunkedUploadService.
+func (s *ChunkedUploadUseCase) UploadPart(ctx context.Context, uploadID string, userID int64, partNumber int, partMD5 string, content io.Reader) (*filesDomain.UploadPart, error) {
+	if partNumber < 1 || partNumber > MaxUploadParts {
+		return nil, domainErrors.NewAppError(fmt.Errorf("part number must be between 1 and %d", MaxUploadParts),
+			domainErrors.ValidationError)
+	}
+	session, err := s.uploadingSession(ctx, uploadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	written, err := s.writePart(session.ID, content, min(s.config.MaxPartSize, session.FileSize), false)
+	if err != nil {
+		return nil, err
+	}
+	if err := written.verify(partMD5); err != nil {
+		return nil, err
+	}
+	part := &filesDomain.UploadPart{PartNumber: partNumber, Size: written.size, MD5: written.md5}
+	if _, err := s.sessionRepository.SavePart(ctx, session.ID, part, s.expiresAt()); err != nil {
+		written.discard()
+		return nil, err
+	}
+	if err := written.keep(s.partPath(session.ID, partNumber)); err != nil {
+		return nil, err
+	}
+	part.CreatedAt = time.Now()
+	return part, nil
+}
+
+// Append implements IChunkedUploadService.
+func (s *ChunkedUploadUseCase) Append(ctx context.Context, uploadID string, userID int64, offset int64, partMD5 string, content io.Reader) (*filesDomain.UploadSession, error) {
+	session, err := s.uploadingSession(ctx, uploadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Offset != offset {
+		return nil, domainErrors.NewAppError(filesDomain.ErrUploadOffsetMismatch, domainErrors.ValidationError)
+	}
+	parts, err := s.sessionRepository.GetParts(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(*parts) >= MaxUploadParts {
+		return nil, domainErrors.NewAppError(fmt.Errorf("upload has reached %d parts", MaxUploadParts), domainErrors.ValidationError)
+	}
+	partNumber := 1
+	if len(*parts) > 0 {
+		partNumber = (*parts)[len(*parts)-1].PartNumber + 1
+	}
+
+	written, err := s.writePart(session.ID, content, session.FileSize-offset, partMD5 == "")
+	if err != nil {
+		return nil, err
+	}
+	if err := written.verify(partMD5); err != nil {
+		return nil, err
+	}
+	if written.size == 0 {
+		written.discard()
+		return session, written.readErr
+	}
+	part := &filesDomain.UploadPart{PartNumber: partNumber, Size: written.size, MD5: written.md5}
+	updated, err := s.sessionRepository.AppendPart(ctx, session.ID, offset, part, s.expiresAt())
+	if err != nil {
+		written.discard()
+		return nil, err
+	}
+	if err := written.keep(s.partPath(session.ID, partNumber)); err != nil {
+		return nil, err
+	}
+	if written.readErr != nil {
+		s.Logger.Info("Kept the part received before the upload was interrupted", zap.String("uploadId", session.ID),
+			zap.Int64("offset", updated.Offset), zap.Error(written.readErr))
+		return nil, written.readErr
+	}
+	return updated, nil
+}
+
+// ListParts implements IChunkedUploadService.
+func (s *ChunkedUploadUseCase) ListParts(ctx context.Context, uploadID string, userID int64) (*[]filesDomain.UploadPart, error) {
+	if _, err := s.session(ctx, uploadID, userID); err != nil {
+		return nil, err
+	}
+	return s.sessionRepository.GetParts(ctx, uploadID)
+}
+
+// Complete implements IChunkedUploadService.
+// The parts are streamed in part number order to the files use case, the session then keeps the
+// recorded file id until it expires so that a repeated Complete returns the same file.
+func (s *ChunkedUploadUseCase) Complete(ctx context.Context, uploadID string, userID int64, fileMD5 string) (*filesDomain.SysFiles, error) {
+	session, err := s.session(ctx, uploadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == filesDomain.UploadStatusCompleted && session.FileID != nil {
+		return s.filesUseCase.GetByID(ctx, int(*session.FileID))
+	}
+	ok, err := s.sessionRepository.Transition(ctx, session.ID, filesDomain.UploadStatusUploading,
+		filesDomain.UploadStatusCompleting, nil, s.expiresAt())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domainErrors.NewAppError(filesDomain.ErrUploadNotActive, domainErrors.ValidationError)
+	}
+
+	record, err := s.assemble(ctx, session, strings.ToLower(fileMD5))
+	if err != nil {
+		if _, revertErr := s.sessionRepository.Transition(ctx, session.ID, filesDomain.UploadStatusCompleting,
+			filesDomain.UploadStatusUploading, nil, s.expiresAt()); revertErr != nil {
+			s.Logger.Warn("Error reopening upload session", zap.String("uploadId", session.ID), zap.Error(revertErr))
+		}
+		return nil, err
+	}
+	if _, err := s.sessionRepository.Transition(ctx, session.ID, filesDomain.UploadStatusCompleting,
+		filesDomain.UploadStatusCompleted, &record.ID, s.expiresAt()); err != nil {
+		s.Logger.Warn("Error marking upload session completed", zap.String("uploadId", session.ID), zap.Error(err))
+	}
+	s.removeParts(session.ID)
+	s.Logger.Info("Upload session completed", zap.String("uploadId", session.ID), zap.Int64("fileId", record.ID))
+	return record, nil
+}
+
+func (s *ChunkedUploadUseCase) assemble(ctx context.Context, session *filesDomain.UploadSession, fileMD5 string) (*filesDomain.SysFiles, error) {
+	parts, err := s.sessionRepository.GetParts(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	paths := make([]string, len(*parts))
+	for i, part := range *parts {
+		paths[i] = s.partPath(session.ID, part.PartNumber)
+		if info, err := os.Stat(paths[i]); err != nil || info.Size() != part.Size {
+			return nil, domainErrors.NewAppError(fmt.Errorf("%w: part %d is missing, upload it again", filesDomain.ErrUploadIncomplete,
+				part.PartNumber), domainErrors.ValidationError)
+		}
+		total += part.Size
+	}
+	if total != session.FileSize {
+		return nil, domainErrors.NewAppError(fmt.Errorf("%w: %d of %d bytes uploaded", filesDomain.ErrUploadIncomplete,
+			total, session.FileSize), domainErrors.ValidationError)
+	}
+
+	content := &partsReader{paths: paths}
+	defer content.Close()
+	// the checksum is of the received content, the stored one may differ once stripped of metadata
+	hash := md5.New()
+	record, err := s.filesUseCase.Upload(ctx, filesDomain.UploadFile{
+		OriginName:  session.FileName,
+		Size:        session.FileSize,
+		ContentType: session.ContentType,
+		Content:     io.TeeReader(content, hash),
+		Owner:       filesDomain.FileOwner{UserID: session.CreatedBy, RoleID: session.CreatedByRole, Visibility: session.Visibility},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fileMD5 == "" {
+		fileMD5 = session.FileMD5
+	}
+	if received := hex.EncodeToString(hash.Sum(nil)); fileMD5 != "" && fileMD5 != received {
+		s.Logger.Warn("Assembled upload fails its checksum", zap.String("uploadId", session.ID),
+			zap.String("expected", fileMD5), zap.String("actual", received))
+		if err := s.filesUseCase.Delete(ctx, []int64{record.ID}); err != nil {
+			s.Logger.Warn("Error removing the mismatching file", zap.Int64("fileId", record.ID), zap.Error(err))
+		}
+		return nil, domainErrors.NewAppError(filesDomain.ErrUploadChecksumMismatch, domainErrors.ValidationError)
+	}
+	return record, nil
+}
+
+// Abort implements IChunkedUploadService.
+// A completed session is only forgotten, the file it recorded stays.
+func (s *ChunkedUploadUseCase) Abort(ctx context.Context, uploadID string, userID int64) error {
+	session, err := s.session(ctx, uploadID, userID)
+	if err != nil {
+		return err
+	}
+	if session.Status == filesDomain.UploadStatusCompleting {
+		return domainErrors.NewAppError(filesDomain.ErrUploadNotActive, domainErrors.ValidationError)
+	}
+	if err := s.sessionRepository.Delete(ctx, session.ID); err != nil {
+		return err
+	}
+	s.removeParts(session.ID)
+	s.Logger.Info("Upload session aborted", zap.String("uploadId", session.ID))
+	return nil
+}
+
+// CleanExpired implements IChunkedUploadService.
+func (s *ChunkedUploadUseCase) CleanExpired(ctx context.Context) (int, error) {
+	removed := 0
+	for {
+		sessions, err := s.sessionRepository.GetExpired(ctx, time.Now(), 100)
+		if err != nil {
+			return removed, err
+		}
+		if len(*sessions) == 0 {
+			return removed, nil
+		}
+		for _, session := range *sessions {
+			if err := s.sessionRepository.Delete(ctx, session.ID); err != nil {
+				return removed, err
+			}
+			s.removeParts(session.ID)
+			removed++
+		}
+	}
+}
+
+// session loads the session of userID, other users' sessions are reported as not found
+func (s *ChunkedUploadUseCase) session(ctx context.Context, uploadID string, userID int64) (*filesDomain.UploadSession, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	session, err := s.sessionRepository.GetByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.CreatedBy != userID {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return session, nil
+}
+
+// uploadingSession checks the session accepts parts before its content is read
+func (s *ChunkedUploadUseCase) uploadingSession(ctx context.Context, uploadID string, userID int64) (*filesDomain.UploadSession, error) {
+	session, err := s.session(ctx, uploadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != filesDomain.UploadStatusUploading {
+		return nil, domainErrors.NewAppError(filesDomain.ErrUploadNotActive, domainErrors.ValidationError)
+	}
+	return session, nil
+}
+
+func (s *ChunkedUploadUseCase) expiresAt() time.Time {
+	return time.Now().Add(s.config.TTL)
+}
+
+func (s *ChunkedUploadUseCase) sessionDir(uploadID string) string {
+	return filepath.Join(s.config.TempDir, uploadID)
+}
+
+func (s *ChunkedUploadUseCase) partPath(uploadID string, partNumber int) string {
+	return filepath.Join(s.sessionDir(uploadID), strconv.Itoa(partNumber))
+}
+
+func (s *ChunkedUploadUseCase) removeParts(uploadID string) {
+	if err := os.RemoveAll(s.sessionDir(uploadID)); err != nil {
+		s.Logger.Warn("Error removing upload parts", zap.String("uploadId", uploadID), zap.Error(err))
+	}
+}
+
+// writtenPart is a part received into a temporary file, kept under its part number once recorded
+type writtenPart struct {
+	path string
+	size int64
+	md5  string
+	// readErr interrupted the content, the bytes before it were kept
+	readErr error
+}
+
+// writePart copies at most limit bytes of content into the session directory, more content is
+// rejected. With keepPartial the bytes received before a read error are returned with readErr.
+func (s *ChunkedUploadUseCase) writePart(uploadID string, content io.Reader, limit int64, keepPartial bool) (*writtenPart, error) {
+	if err := os.MkdirAll(s.sessionDir(uploadID), 0o750); err != nil {
+		s.Logger.Error("Error creating upload directory", zap.String("uploadId", uploadID), zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	file, err := os.CreateTemp(s.sessionDir(uploadID), "part-*.tmp")
+	if err != nil {
+		s.Logger.Error("Error creating upload part", zap.String("uploadId", uploadID), zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	hash := md5.New()
+	size, copyErr := io.Copy(io.MultiWriter(file, hash), io.LimitReader(content, limit+1))
+	closeErr := file.Close()
+	written := &writtenPart{path: file.Name(), size: size, md5: hex.EncodeToString(hash.Sum(nil))}
+	switch {
+	case size > limit:
+		written.discard()
+		return nil, domainErrors.NewAppError(fmt.Errorf("part exceeds %d bytes", limit), domainErrors.ValidationError)
+	case closeErr != nil:
+		written.discard()
+		s.Logger.Error("Error writing upload part", zap.String("uploadId", uploadID), zap.Error(closeErr))
+		return nil, domainErrors.NewAppError(closeErr, domainErrors.UploadError)
+	case copyErr != nil && (!keepPartial || size == 0):
+		written.discard()
+		return nil, domainErrors.NewAppError(copyErr, domainErrors.UploadError)
+	case copyErr != nil:
+		written.readErr = domainErrors.NewAppError(copyErr, domainErrors.UploadError)
+	}
+	return written, nil
+}
+
+// verify discards the part when it doesn't match the expected md5
+func (p *writtenPart) verify(expected string) error {
+	if expected != "" && !strings.EqualFold(expected, p.md5) {
+		p.discard()
+		return domainErrors.NewAppError(filesDomain.ErrUploadChecksumMismatch, domainErrors.ValidationError)
+	}
+	return nil
+}
+
+func (p *writtenPart) keep(partPath string) error {
+	if err := os.Rename(p.path, partPath); err != nil {
+		p.discard()
+		return domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	return nil
+}
+
+func (p *writtenPart) discard() {
+	_ = os.Remove(p.path)
+}
+
+// partsReader reads the part files one after the other, opening each only when it is reached
+type partsReader struct {
+	paths   []string
+	current *os.File
+}
+
+func (r *partsReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.paths) == 0 {
+				return 0, io.EOF
+			}
+			file, err := os.Open(r.paths[0])
+			if err != nil {
+				return 0, err
+			}
+			r.current, r.paths = file, r.paths[1:]
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			_ = r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *partsReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package files
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memorySessionRepository keeps upload sessions and their parts in maps
+type memorySessionRepository struct {
+	sessions map[string]*filesDomain.UploadSession
+	parts    map[string]map[int]filesDomain.UploadPart
+}
+
+func newMemorySessionRepository() *memorySessionRepository {
+	return &memorySessionRepository{
+		sessions: map[string]*filesDomain.UploadSession{},
+		parts:    map[string]map[int]filesDomain.UploadPart{},
+	}
+}
+
+func (r *memorySessionRepository) Create(_ context.Context, session *filesDomain.UploadSession) (*filesDomain.UploadSession, error) {
+	stored := *session
+	r.sessions[stored.ID] = &stored
+	r.parts[stored.ID] = map[int]filesDomain.UploadPart{}
+	copied := stored
+	return &copied, nil
+}
+
+func (r *memorySessionRepository) GetByID(_ context.Context, id string) (*filesDomain.UploadSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *memorySessionRepository) GetParts(_ context.Context, id string) (*[]filesDomain.UploadPart, error) {
+	parts := make([]filesDomain.UploadPart, 0, len(r.parts[id]))
+	for _, part := range r.parts[id] {
+		parts = append(parts, part)
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return &parts, nil
+}
+
+func (r *memorySessionRepository) SavePart(ctx context.Context, id string, part *filesDomain.UploadPart, expiresAt time.Time) (*filesDomain.UploadSession, error) {
+	session := r.sessions[id]
+	if session.Status != filesDomain.UploadStatusUploading {
+		return nil, domainErrors.NewAppError(filesDomain.ErrUploadNotActive, domainErrors.ValidationError)
+	}
+	var offset int64
+	for number, stored := range r.parts[id] {
+		if number != part.PartNumber {
+			offset += stored.Size
+		}
+	}
+	if offset+part.Size > session.FileSize {
+		return nil, domainErrors.NewAppError(filesDomain.ErrUploadTooLarge, domainErrors.ValidationError)
+	}
+	r.parts[id][part.PartNumber] = *part
+	session.Offset = offset + part.Size
+	session.ExpiresAt = expiresAt
+	return r.GetByID(ctx, id)
+}
+
+func (r *memorySessionRepository) AppendPart(ctx context.Context, id string, offset int64, part *filesDomain.UploadPart, expiresAt time.Time) (*filesDomain.UploadSession, error) {
+	if r.sessions[id].Offset != offset {
+		return nil, domainErrors.NewAppError(filesDomain.ErrUploadOffsetMismatch, domainErrors.ValidationError)
+	}
+	return r.SavePart(ctx, id, part, expiresAt)
+}
+
+func (r *memorySessionRepository) Transition(_ context.Context, id string, from string, to string, fileID *int64, expiresAt time.Time) (bool, error) {
+	session := r.sessions[id]
+	if session == nil || session.Status != from {
+		return false, nil
+	}
+	session.Status, session.FileID, session.ExpiresAt = to, fileID, expiresAt
+	return true, nil
+}
+
+func (r *memorySessionRepository) Delete(_ context.Context, id string) error {
+	delete(r.sessions, id)
+	delete(r.parts, id)
+	return nil
+}
+
+func (r *memorySessionRepository) GetExpired(_ context.Context, at time.Time, limit int) (*[]filesDomain.UploadSession, error) {
+	var expired []filesDomain.UploadSession
+	for _, session := range r.sessions {
+		if !session.ExpiresAt.After(at) && len(expired) < limit {
+			expired = append(expired, *session)
+		}
+	}
+	return &expired, nil
+}
+
+func newTestChunkedUseCase(t *testing.T) (*ChunkedUploadUseCase, *memorySessionRepository, *memoryRepository) {
+	filesUseCase, filesRepository, _ := newTestUseCase(t)
+	sessions := newMemorySessionRepository()
+	useCase := NewChunkedUploadUseCase(sessions, filesUseCase, nil, ChunkedUploadConfig{
+		TempDir:     t.TempDir(),
+		TTL:         time.Hour,
+		MaxPartSize: 8,
+	}, &logger.Logger{Log: zap.NewNop()}).(*ChunkedUploadUseCase)
+	return useCase, sessions, filesRepository
+}
+
+func md5Hex(content string) string {
+	sum := md5.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func initiate(t *testing.T, useCase *ChunkedUploadUseCase, size int64, fileMD5 string) *filesDomain.UploadSession {
+	session, err := useCase.Initiate(context.Background(), filesDomain.InitiateUpload{
+		FileName:  "export.csv",
+		FileSize:  size,
+		FileMD5:   fileMD5,
+		CreatedBy: 7,
+	})
+	require.NoError(t, err)
+	return session
+}
+
+func assertUploadError(t *testing.T, err error, target error) {
+	t.Helper()
+	var appErr *domainErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.ErrorIs(t, appErr.Err, target)
+}
+
+func TestChunkedUploadCompletesPartsInOrder(t *testing.T) {
+	useCase, _, filesRepository := newTestChunkedUseCase(t)
+	ctx := context.Background()
+	content := "hello chunked world"
+	session := initiate(t, useCase, int64(len(content)), md5Hex(content))
+
+	chunks := []string{content[:8], content[8:16], content[16:]}
+	for _, number := range []int{3, 1, 2} {
+		_, err := useCase.UploadPart(ctx, session.ID, 7, number, md5Hex(chunks[number-1]), strings.NewReader(chunks[number-1]))
+		require.NoError(t, err)
+	}
+	_, err := useCase.UploadPart(ctx, session.ID, 7, 4, md5Hex("x"), strings.NewReader("y"))
+	assertUploadError(t, err, filesDomain.ErrUploadChecksumMismatch)
+	_, err = useCase.UploadPart(ctx, session.ID, 7, 4, "", strings.NewReader("more than eight bytes"))
+	require.Error(t, err)
+
+	parts, err := useCase.ListParts(ctx, session.ID, 7)
+	require.NoError(t, err)
+	assert.Len(t, *parts, 3)
+	_, err = useCase.ListParts(ctx, session.ID, 8)
+	require.Error(t, err, "sessions of other users are not found")
+
+	file, err := useCase.Complete(ctx, session.ID, 7, "")
+	require.NoError(t, err)
+	assert.Equal(t, md5Hex(content), file.FileMD5)
+	assert.Equal(t, "export.csv", file.FileOriginName)
+	assert.Len(t, filesRepository.files, 1)
+	_, err = os.Stat(useCase.sessionDir(session.ID))
+	assert.True(t, os.IsNotExist(err), "parts are removed once completed")
+
+	again, err := useCase.Complete(ctx, session.ID, 7, "")
+	require.NoError(t, err)
+	assert.Equal(t, file.ID, again.ID)
+}
+
+func TestChunkedUploadCompleteChecks(t *testing.T) {
+	useCase, sessions, filesRepository := newTestChunkedUseCase(t)
+	ctx := context.Background()
+	session := initiate(t, useCase, 10, "")
+
+	_, err := useCase.UploadPart(ctx, session.ID, 7, 1, "", strings.NewReader("12345"))
+	require.NoError(t, err)
+	_, err = useCase.Complete(ctx, session.ID, 7, "")
+	assertUploadError(t, err, filesDomain.ErrUploadIncomplete)
+	assert.Equal(t, filesDomain.UploadStatusUploading, sessions.sessions[session.ID].Status)
+
+	_, err = useCase.UploadPart(ctx, session.ID, 7, 2, "", strings.NewReader("67890"))
+	require.NoError(t, err)
+	_, err = useCase.Complete(ctx, session.ID, 7, md5Hex("something else"))
+	assertUploadError(t, err, filesDomain.ErrUploadChecksumMismatch)
+	assert.Empty(t, filesRepository.files, "the mismatching file is removed")
+
+	require.NoError(t, useCase.Abort(ctx, session.ID, 7))
+	assert.Empty(t, sessions.sessions)
+	_, err = os.Stat(useCase.sessionDir(session.ID))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestChunkedUploadPartsBoundedBySize(t *testing.T) {
+	useCase, _, _ := newTestChunkedUseCase(t)
+	ctx := context.Background()
+	session := initiate(t, useCase, 5, "")
+
+	_, err := useCase.UploadPart(ctx, session.ID, 7, 1, "", strings.NewReader("123456"))
+	require.Error(t, err, "a part can't be larger than the file")
+	_, err = useCase.UploadPart(ctx, session.ID, 7, 1, "", strings.NewReader("1234"))
+	require.NoError(t, err)
+	_, err = useCase.UploadPart(ctx, session.ID, 7, 2, "", strings.NewReader("56"))
+	assertUploadError(t, err, filesDomain.ErrUploadTooLarge)
+	_, err = os.Stat(useCase.partPath(session.ID, 2))
+	assert.True(t, os.IsNotExist(err), "the rejected part is discarded")
+
+	// resending a part replaces it, its previous size doesn't count
+	_, err = useCase.UploadPart(ctx, session.ID, 7, 1, "", strings.NewReader("123"))
+	require.NoError(t, err)
+	_, err = useCase.UploadPart(ctx, session.ID, 7, 2, "", strings.NewReader("45"))
+	require.NoError(t, err)
+	current, err := useCase.Get(ctx, session.ID, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), current.Offset)
+}
+
+// failingReader returns its content then fails, like a dropped connection
+type failingReader struct {
+	io.Reader
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestChunkedUploadAppend(t *testing.T) {
+	useCase, _, filesRepository := newTestChunkedUseCase(t)
+	ctx := context.Background()
+	session := initiate(t, useCase, 12, "")
+
+	_, err := useCase.Append(ctx, session.ID, 7, 0, "", failingReader{strings.NewReader("hello ")})
+	require.Error(t, err)
+	current, err := useCase.Get(ctx, session.ID, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), current.Offset, "the bytes received before the failure are kept")
+
+	_, err = useCase.Append(ctx, session.ID, 7, 0, "", strings.NewReader("world!"))
+	assertUploadError(t, err, filesDomain.ErrUploadOffsetMismatch)
+	_, err = useCase.Append(ctx, session.ID, 7, 6, "", strings.NewReader("world!!"))
+	require.Error(t, err, "content beyond the upload length is rejected")
+
+	updated, err := useCase.Append(ctx, session.ID, 7, 6, md5Hex("world!"), strings.NewReader("world!"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), updated.Offset)
+	file, err := useCase.Complete(ctx, session.ID, 7, "")
+	require.NoError(t, err)
+	assert.Equal(t, md5Hex("hello world!"), file.FileMD5)
+	assert.Len(t, filesRepository.files, 1)
+}
+
+func TestChunkedUploadCleanExpired(t *testing.T) {
+	useCase, sessions, _ := newTestChunkedUseCase(t)
+	ctx := context.Background()
+	expired := initiate(t, useCase, 4, "")
+	active := initiate(t, useCase, 4, "")
+	_, err := useCase.UploadPart(ctx, expired.ID, 7, 1, "", strings.NewReader("ab"))
+	require.NoError(t, err)
+	sessions.sessions[expired.ID].ExpiresAt = time.Now().Add(-time.Minute)
+
+	removed, err := useCase.CleanExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NotContains(t, sessions.sessions, expired.ID)
+	assert.Contains(t, sessions.sessions, active.ID)
+	_, err = os.Stat(useCase.sessionDir(expired.ID))
+	assert.True(t, os.IsNotExist(err))
+}
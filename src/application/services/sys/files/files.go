@@ -1,78 +1,541 @@
 package files
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
 	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/imaging"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadpolicy"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
 	"go.uber.org/zap"
 )
 
 type ISysFilesService interface {
-	Create(data *filesDomain.SysFiles) (*filesDomain.SysFiles, error)
-	GetAll() (*[]filesDomain.SysFiles, error)
-	GetByID(id int) (*filesDomain.SysFiles, error)
-	Delete(ids []int64) error
-	Update(id int, userMap map[string]interface{}) (*filesDomain.SysFiles, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[filesDomain.SysFiles], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*filesDomain.SysFiles, error)
+	Upload(ctx context.Context, file filesDomain.UploadFile) (*filesDomain.SysFiles, error)
+	InstantUpload(ctx context.Context, md5 string, size int64, originName string, owner filesDomain.FileOwner) (*filesDomain.SysFiles, error)
+	RegisterObject(ctx context.Context, object filesDomain.StoredObject) (*filesDomain.SysFiles, error)
+	Thumbnail(ctx context.Context, id int, size string, access filesDomain.FileAccess) (*filesDomain.FileRendition, io.ReadCloser, error)
+	Download(ctx context.Context, id int, access filesDomain.FileAccess) (*filesDomain.SysFiles, io.ReadCloser, error)
+	SignedURL(ctx context.Context, id int, access filesDomain.FileAccess, expires time.Duration) (string, time.Time, error)
+	OpenSigned(ctx context.Context, key string, expires string, signature string) (io.ReadCloser, error)
+	OpenPublic(ctx context.Context, key string) (io.ReadCloser, error)
+	Create(ctx context.Context, data *filesDomain.SysFiles) (*filesDomain.SysFiles, error)
+	GetAll(ctx context.Context) (*[]filesDomain.SysFiles, error)
+	GetByID(ctx context.Context, id int) (*filesDomain.SysFiles, error)
+	GetByIDs(ctx context.Context, ids []int64) (*[]filesDomain.SysFiles, error)
+	Delete(ctx context.Context, ids []int64) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*filesDomain.SysFiles, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[filesDomain.SysFiles], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*filesDomain.SysFiles, error)
+	CollectGarbage(ctx context.Context, options filesDomain.GCOptions) (*filesDomain.GCReport, error)
+	UsageByUser(ctx context.Context, userID int64, limit int) (*[]filesDomain.UserStorageUsage, error)
+	UsageByEngine(ctx context.Context) (*[]filesDomain.EngineStorageUsage, error)
 }
 
 type SysFilesUseCase struct {
 	sysFilesRepository files.ISysFilesRepository
-	Logger             *logger.Logger
+	storage            *storage.Manager
+	// validator applies the upload policies, nil accepts every file
+	validator *uploadpolicy.Validator
+	// images strips the metadata of uploaded images and renders their thumbnails, nil stores
+	// images as uploaded
+	images *imaging.Processor
+	access FileAccessConfig
+	Logger *logger.Logger
+}
+
+// Upload stores the file with the default storage driver and records it in sys_files. The md5
+// is computed while the content streams to the driver, when a blob with the same content
+// already exists the new object is removed and the file shares that blob. The content is
+// checked against the upload policy of its extension and scanned on the way, the stored
+// object is removed when it is rejected. Images are stored without their metadata and their
+// thumbnails are rendered once recorded, a failed rendering only leaves them to be rendered
+// when asked.
+func (s *SysFilesUseCase) Upload(ctx context.Context, file filesDomain.UploadFile) (*filesDomain.SysFiles, error) {
+	if err := validOwner(file.Owner); err != nil {
+		return nil, err
+	}
+	content, contentType := file.Content, file.ContentType
+	var inspection *uploadpolicy.Inspection
+	if s.validator != nil {
+		var err error
+		inspection, err = s.validator.Inspect(ctx, file.OriginName, file.Size, file.Content)
+		if err != nil {
+			s.Logger.Warn("Upload rejected", zap.String("filename", file.OriginName), zap.Error(err))
+			return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+		}
+		defer inspection.Close()
+		content, contentType = inspection.Content, inspection.ContentType
+	}
+	contentSize := file.Size
+	// the md5 of the content as received is kept when the stored content differs, that is the
+	// one a client sends for an instant upload
+	var received io.Reader
+	sourceHash := md5.New()
+	sourceSize := &byteCounter{}
+	if s.images != nil {
+		received = io.TeeReader(content, io.MultiWriter(sourceHash, sourceSize))
+		if stripped, ok := s.images.StripMetadata(received, contentType); ok {
+			content, contentSize = stripped, -1
+		} else {
+			received = nil
+		}
+	}
+
+	driver := s.storage.Default()
+	key := s.storage.NewKey(file.OriginName)
+	hash := md5.New()
+	size := &byteCounter{}
+	err := driver.Put(ctx, key, io.TeeReader(content, io.MultiWriter(hash, size)), contentSize,
+		storage.PutOptions{ContentType: contentType})
+	if err != nil {
+		if inspection != nil && inspection.Rejected() != nil {
+			s.Logger.Warn("Upload rejected", zap.String("filename", file.OriginName), zap.Error(inspection.Rejected()))
+			return nil, domainErrors.NewAppError(inspection.Rejected(), domainErrors.UploadError)
+		}
+		s.Logger.Error("Error storing file", zap.String("key", key), zap.String("engine", driver.Name()), zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	if inspection != nil {
+		err := inspection.Verdict()
+		if scanErr := inspection.ScanError(); scanErr != nil {
+			s.Logger.Error("Error scanning upload", zap.String("filename", file.OriginName), zap.Error(scanErr))
+		}
+		if err != nil {
+			s.Logger.Warn("Upload rejected", zap.String("filename", file.OriginName), zap.Error(err))
+			s.deleteObject(ctx, driver.Name(), key)
+			return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+		}
+	}
+
+	newBlob := &filesDomain.FileBlob{
+		FileMD5:       hex.EncodeToString(hash.Sum(nil)),
+		FileSize:      size.n,
+		FilePath:      key,
+		StorageEngine: driver.Name(),
+	}
+	if received != nil {
+		// the stripping may stop before the end of the content, the rest counts as received
+		_, _ = io.Copy(io.Discard, received)
+		if sourceMD5 := hex.EncodeToString(sourceHash.Sum(nil)); sourceMD5 != newBlob.FileMD5 {
+			newBlob.SourceMD5, newBlob.SourceSize = sourceMD5, sourceSize.n
+		}
+	}
+	blob, err := s.sysFilesRepository.AcquireBlob(ctx, newBlob)
+	if err != nil {
+		s.deleteObject(ctx, driver.Name(), key)
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	if blob.FilePath != key || blob.StorageEngine != driver.Name() {
+		s.Logger.Info("Upload matches a stored blob", zap.Int64("blobId", blob.ID), zap.String("md5", blob.FileMD5))
+		s.deleteObject(ctx, driver.Name(), key)
+	}
+	record, err := s.createWithBlob(ctx, blob, file.OriginName, file.Owner)
+	if err != nil {
+		return nil, err
+	}
+	s.renderThumbnails(ctx, record)
+	return record, nil
+}
+
+// InstantUpload implements ISysFilesService.
+// The content of the blob was validated for the extension it was uploaded with, a name with
+// another extension has to upload the content to have it validated again. Knowing the md5 and
+// size of a file doesn't share it: the owner has to be able to read one of the files of the
+// blob, otherwise the blob is reported as not stored.
+func (s *SysFilesUseCase) InstantUpload(ctx context.Context, md5 string, size int64, originName string, owner filesDomain.FileOwner) (*filesDomain.SysFiles, error) {
+	if err := validOwner(owner); err != nil {
+		return nil, err
+	}
+	if s.validator != nil {
+		if _, err := s.validator.Check(originName, size); err != nil {
+			return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+		}
+	}
+	blob, err := s.sysFilesRepository.AcquireBlobByContent(ctx, strings.ToLower(md5), size)
+	if err != nil {
+		return nil, err
+	}
+	if blob == nil {
+		s.Logger.Info("No stored blob for instant upload", zap.String("md5", md5), zap.Int64("size", size))
+		return nil, nil
+	}
+	if s.validator != nil && !strings.EqualFold(path.Ext(blob.FilePath), path.Ext(originName)) {
+		s.Logger.Info("Stored blob has another extension, skipping instant upload", zap.Int64("blobId", blob.ID))
+		s.releaseBlobs(ctx, []int64{blob.ID})
+		return nil, nil
+	}
+	if readable, err := s.blobReadable(ctx, blob.ID, owner); err != nil || !readable {
+		s.releaseBlobs(ctx, []int64{blob.ID})
+		if err != nil {
+			return nil, err
+		}
+		s.Logger.Warn("Stored blob isn't shared with the owner, skipping instant upload", zap.Int64("blobId", blob.ID),
+			zap.Int64("userId", owner.UserID))
+		return nil, nil
+	}
+	return s.createWithBlob(ctx, blob, originName, owner)
+}
+
+// RegisterObject implements ISysFilesService.
+// The object is checked against the upload policy of its name and removed when rejected, its
+// content can't be scanned on the way like an upload. It is then recorded as a blob like an
+// upload: when the same content is already stored the object is removed and the file shares
+// that blob.
+func (s *SysFilesUseCase) RegisterObject(ctx context.Context, object filesDomain.StoredObject) (*filesDomain.SysFiles, error) {
+	if err := validOwner(object.Owner); err != nil {
+		return nil, err
+	}
+	if s.validator != nil {
+		if _, err := s.validator.Check(object.OriginName, object.Size); err != nil {
+			s.Logger.Warn("Stored object rejected", zap.String("key", object.Key), zap.Error(err))
+			s.deleteObject(ctx, object.Engine, object.Key)
+			return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+		}
+	}
+	fileMD5 := strings.ToLower(object.MD5)
+	if fileMD5 == "" {
+		content, err := s.openObject(ctx, object.Engine, object.Key)
+		if err != nil {
+			return nil, storageError(err)
+		}
+		hash := md5.New()
+		_, err = io.Copy(hash, content)
+		_ = content.Close()
+		if err != nil {
+			return nil, err
+		}
+		fileMD5 = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	blob, err := s.sysFilesRepository.AcquireBlob(ctx, &filesDomain.FileBlob{
+		FileMD5:       fileMD5,
+		FileSize:      object.Size,
+		FilePath:      object.Key,
+		StorageEngine: object.Engine,
+	})
+	if err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	if blob.FilePath != object.Key || blob.StorageEngine != object.Engine {
+		s.Logger.Info("Stored object matches a stored blob", zap.Int64("blobId", blob.ID), zap.String("md5", blob.FileMD5))
+		s.deleteObject(ctx, object.Engine, object.Key)
+	}
+	record, err := s.createWithBlob(ctx, blob, object.OriginName, object.Owner)
+	if err != nil {
+		return nil, err
+	}
+	s.renderThumbnails(ctx, record)
+	return record, nil
+}
+
+// renderThumbnails renders the thumbnails of a new image file in every size, a failed rendering
+// only leaves them to be rendered when asked
+func (s *SysFilesUseCase) renderThumbnails(ctx context.Context, record *filesDomain.SysFiles) {
+	if s.images != nil && s.images.Supported(record.FileOriginName) && len(s.images.Sizes()) > 0 {
+		if _, err := s.render(ctx, record, s.images.Sizes()); err != nil {
+			s.Logger.Warn("Error rendering thumbnails", zap.Int64("id", record.ID), zap.Error(err))
+		}
+	}
+}
+
+// Thumbnail implements ISysFilesService.
+func (s *SysFilesUseCase) Thumbnail(ctx context.Context, id int, sizeName string, access filesDomain.FileAccess) (*filesDomain.FileRendition, io.ReadCloser, error) {
+	if s.images == nil {
+		return nil, nil, domainErrors.NewAppError(filesDomain.ErrUnknownThumbnailSize, domainErrors.ValidationError)
+	}
+	size, ok := s.images.Size(sizeName)
+	if !ok {
+		return nil, nil, domainErrors.NewAppError(fmt.Errorf("%w %q", filesDomain.ErrUnknownThumbnailSize, sizeName), domainErrors.ValidationError)
+	}
+	file, err := s.readable(ctx, id, access)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.images.Supported(file.FileOriginName) {
+		return nil, nil, domainErrors.NewAppError(filesDomain.ErrNotAnImage, domainErrors.ValidationError)
+	}
+	renditions, err := s.sysFilesRepository.GetRenditions(ctx, file.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rendition := findRendition(*renditions, size.Name)
+	if rendition != nil {
+		content, err := s.openObject(ctx, rendition.StorageEngine, rendition.FilePath)
+		if err == nil {
+			return s.withRenditionURL(file, rendition), content, nil
+		}
+		// a rendition whose object is missing is rendered again
+		s.Logger.Warn("Error opening file rendition", zap.Int64("id", rendition.ID), zap.Error(err))
+	}
+	rendered, err := s.render(ctx, file, []imaging.Size{size})
+	if err != nil {
+		return nil, nil, err
+	}
+	rendition = &rendered[0]
+	content, err := s.openObject(ctx, rendition.StorageEngine, rendition.FilePath)
+	if err != nil {
+		return nil, nil, storageError(err)
+	}
+	return s.withRenditionURL(file, rendition), content, nil
+}
+
+// render makes the renditions of an image file in the given sizes, stores them with the default
+// driver and records them against the file
+func (s *SysFilesUseCase) render(ctx context.Context, file *filesDomain.SysFiles, sizes []imaging.Size) ([]filesDomain.FileRendition, error) {
+	source, err := s.openObject(ctx, file.StorageEngine, file.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = source.Close() }()
+	rendered, err := s.images.Render(source, sizes)
+	if errors.Is(err, imaging.ErrInvalidImage) || errors.Is(err, imaging.ErrTooManyPixels) {
+		return nil, domainErrors.NewAppError(fmt.Errorf("%w: %v", filesDomain.ErrNotAnImage, err), domainErrors.ValidationError)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	driver := s.storage.Default()
+	renditions := make([]filesDomain.FileRendition, 0, len(rendered))
+	for _, item := range rendered {
+		// keyed by the file, files sharing a blob have renditions of their own
+		key := fmt.Sprintf("%s_%d_%s%s", strings.TrimSuffix(file.FilePath, path.Ext(file.FilePath)), file.ID, item.Size.Name, item.Extension)
+		if err := driver.Put(ctx, key, bytes.NewReader(item.Content), int64(len(item.Content)),
+			storage.PutOptions{ContentType: item.ContentType}); err != nil {
+			return nil, err
+		}
+		rendition, err := s.sysFilesRepository.SaveRendition(ctx, &filesDomain.FileRendition{
+			FileID:        file.ID,
+			Size:          item.Size.Name,
+			Width:         item.Width,
+			Height:        item.Height,
+			ContentType:   item.ContentType,
+			FilePath:      key,
+			FileSize:      int64(len(item.Content)),
+			StorageEngine: driver.Name(),
+		})
+		if err != nil {
+			s.deleteObject(ctx, driver.Name(), key)
+			return nil, err
+		}
+		renditions = append(renditions, *rendition)
+	}
+	s.Logger.Info("Thumbnails rendered", zap.Int64("id", file.ID), zap.Int("count", len(renditions)))
+	return renditions, nil
+}
+
+func findRendition(renditions []filesDomain.FileRendition, size string) *filesDomain.FileRendition {
+	for i := range renditions {
+		if renditions[i].Size == size {
+			return &renditions[i]
+		}
+	}
+	return nil
+}
+
+func (s *SysFilesUseCase) openObject(ctx context.Context, engine, key string) (io.ReadCloser, error) {
+	driver, err := s.storage.Driver(engine)
+	if err != nil {
+		return nil, err
+	}
+	return driver.Get(ctx, key)
+}
+
+// createWithBlob records a file for a blob already holding a reference for it, the reference
+// is released when the file can't be recorded
+func (s *SysFilesUseCase) createWithBlob(ctx context.Context, blob *filesDomain.FileBlob, originName string,
+	owner filesDomain.FileOwner) (*filesDomain.SysFiles, error) {
+	file := &filesDomain.SysFiles{
+		FileName:       path.Base(blob.FilePath),
+		FilePath:       blob.FilePath,
+		FileMD5:        blob.FileMD5,
+		FileSize:       blob.FileSize,
+		FileOriginName: originName,
+		StorageEngine:  blob.StorageEngine,
+		BlobID:         &blob.ID,
+	}
+	s.owned(file, owner)
+	record, err := s.sysFilesRepository.Create(ctx, file)
+	if err != nil {
+		s.releaseBlobs(ctx, []int64{blob.ID})
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	s.Logger.Info("File recorded", zap.Int64("id", record.ID), zap.Int64("blobId", blob.ID), zap.String("engine", blob.StorageEngine))
+	return s.withURL(record), nil
+}
+
+func (s *SysFilesUseCase) releaseBlobs(ctx context.Context, blobIDs []int64) {
+	released, err := s.sysFilesRepository.ReleaseBlobs(ctx, blobIDs)
+	if err != nil {
+		return
+	}
+	s.deleteBlobObjects(ctx, released)
+}
+
+// deleteBlobObjects removes the objects of the blobs left without references, a failure only
+// leaves an orphan object behind
+func (s *SysFilesUseCase) deleteBlobObjects(ctx context.Context, blobs *[]filesDomain.FileBlob) {
+	for _, blob := range *blobs {
+		s.deleteObject(ctx, blob.StorageEngine, blob.FilePath)
+	}
+}
+
+func (s *SysFilesUseCase) deleteObject(ctx context.Context, engine, key string) {
+	driver, err := s.storage.Driver(engine)
+	if err == nil {
+		err = driver.Delete(ctx, key)
+	}
+	if err != nil {
+		s.Logger.Warn("Error removing stored file", zap.String("engine", engine), zap.String("key", key), zap.Error(err))
+	}
+}
+
+// byteCounter counts the bytes of an upload whose size wasn't announced
+type byteCounter struct {
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
 }
 
 // Create implements ISysFilesService.
-func (s *SysFilesUseCase) Create(data *filesDomain.SysFiles) (*filesDomain.SysFiles, error) {
+func (s *SysFilesUseCase) Create(ctx context.Context, data *filesDomain.SysFiles) (*filesDomain.SysFiles, error) {
 	s.Logger.Info("Getting file by filename", zap.String("filename", data.FileName))
-	return s.sysFilesRepository.Create(data)
+	record, err := s.sysFilesRepository.Create(ctx, data)
+	return s.withURL(record), err
 }
 
-func NewSysFilesUseCase(sysFilesRepository files.ISysFilesRepository, loggerInstance *logger.Logger) ISysFilesService {
+func NewSysFilesUseCase(sysFilesRepository files.ISysFilesRepository, storageManager *storage.Manager,
+	validator *uploadpolicy.Validator, images *imaging.Processor, access FileAccessConfig, loggerInstance *logger.Logger) ISysFilesService {
 	return &SysFilesUseCase{
 		sysFilesRepository: sysFilesRepository,
+		storage:            storageManager,
+		validator:          validator,
+		images:             images,
+		access:             access,
 		Logger:             loggerInstance,
 	}
 }
 
-func (s *SysFilesUseCase) GetAll() (*[]filesDomain.SysFiles, error) {
+// withURL fills FileUrl through the driver of the engine a public file was stored with, the
+// other files are read through the download endpoint
+func (s *SysFilesUseCase) withURL(file *filesDomain.SysFiles) *filesDomain.SysFiles {
+	if file != nil && file.FilePath != "" {
+		if isPublic(file) {
+			file.FileUrl = s.storage.URL(file.StorageEngine, file.FilePath)
+		} else {
+			file.FileUrl = s.downloadURL(file)
+		}
+	}
+	return file
+}
+
+func (s *SysFilesUseCase) withRenditionURL(file *filesDomain.SysFiles, rendition *filesDomain.FileRendition) *filesDomain.FileRendition {
+	if isPublic(file) {
+		rendition.FileUrl = s.storage.URL(rendition.StorageEngine, rendition.FilePath)
+	} else {
+		rendition.FileUrl = s.thumbnailURL(file, rendition.Size)
+	}
+	return rendition
+}
+
+func (s *SysFilesUseCase) withURLs(list *[]filesDomain.SysFiles) *[]filesDomain.SysFiles {
+	if list != nil {
+		for i := range *list {
+			s.withURL(&(*list)[i])
+		}
+	}
+	return list
+}
+
+func (s *SysFilesUseCase) GetAll(ctx context.Context) (*[]filesDomain.SysFiles, error) {
 	s.Logger.Info("Getting all files")
-	return s.sysFilesRepository.GetAll()
+	list, err := s.sysFilesRepository.GetAll(ctx)
+	return s.withURLs(list), err
 }
 
-func (s *SysFilesUseCase) GetByID(id int) (*filesDomain.SysFiles, error) {
+func (s *SysFilesUseCase) GetByIDs(ctx context.Context, ids []int64) (*[]filesDomain.SysFiles, error) {
+	list, err := s.sysFilesRepository.GetByIDs(ctx, ids)
+	return s.withURLs(list), err
+}
+
+func (s *SysFilesUseCase) GetByID(ctx context.Context, id int) (*filesDomain.SysFiles, error) {
 	s.Logger.Info("Getting file by ID", zap.Int("id", id))
-	return s.sysFilesRepository.GetByID(id)
+	record, err := s.sysFilesRepository.GetByID(ctx, id)
+	if err != nil {
+		return record, err
+	}
+	renditions, err := s.sysFilesRepository.GetRenditions(ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *renditions {
+		s.withRenditionURL(record, &(*renditions)[i])
+	}
+	record.Renditions = *renditions
+	return s.withURL(record), nil
 }
 
-func (s *SysFilesUseCase) Delete(ids []int64) error {
-	s.Logger.Info("Deleting file", zap.String("ids", fmt.Sprintf("%s", ids)))
-	return s.sysFilesRepository.Delete(ids)
+func (s *SysFilesUseCase) Delete(ctx context.Context, ids []int64) error {
+	s.Logger.Info("Deleting file", zap.Int64s("ids", ids))
+	released, err := s.sysFilesRepository.Delete(ctx, ids)
+	if err != nil {
+		return err
+	}
+	// the objects are removed even when the request is cancelled, the records are gone already
+	ctx = context.WithoutCancel(ctx)
+	s.deleteBlobObjects(ctx, released)
+	// a failure leaves the renditions of deleted files to the orphan cleanup
+	if renditions, err := s.sysFilesRepository.DeleteRenditions(ctx, ids); err == nil {
+		for _, rendition := range *renditions {
+			s.deleteObject(ctx, rendition.StorageEngine, rendition.FilePath)
+		}
+	}
+	return nil
 }
 
-func (s *SysFilesUseCase) Update(id int, userMap map[string]interface{}) (*filesDomain.SysFiles, error) {
+func (s *SysFilesUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*filesDomain.SysFiles, error) {
 	s.Logger.Info("Updating file", zap.Int("id", id))
-	return s.sysFilesRepository.Update(id, userMap)
+	record, err := s.sysFilesRepository.Update(ctx, id, userMap)
+	return s.withURL(record), err
 }
 
-func (s *SysFilesUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[filesDomain.SysFiles], error) {
+func (s *SysFilesUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[filesDomain.SysFiles], error) {
 	s.Logger.Info("Searching file with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysFilesRepository.SearchPaginated(filters)
+	result, err := s.sysFilesRepository.SearchPaginated(ctx, filters)
+	if result != nil {
+		s.withURLs(result.Data)
+	}
+	return result, err
 }
 
-func (s *SysFilesUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *SysFilesUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching file by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysFilesRepository.SearchByProperty(property, searchText)
+	return s.sysFilesRepository.SearchByProperty(ctx, property, searchText)
 }
 
-func (s *SysFilesUseCase) GetOneByMap(userMap map[string]interface{}) (*filesDomain.SysFiles, error) {
-	return s.sysFilesRepository.GetOneByMap(userMap)
+func (s *SysFilesUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*filesDomain.SysFiles, error) {
+	record, err := s.sysFilesRepository.GetOneByMap(ctx, userMap)
+	return s.withURL(record), err
 }
@@ -0,0 +1,446 @@
+package files
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"image"
+	"image/jpeg"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/imaging"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/scanner"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadpolicy"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	filesRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryRepository keeps files and blobs in maps, the methods not used by the tests panic
+type memoryRepository struct {
+	filesRepo.ISysFilesRepository
+	files      map[int64]filesDomain.SysFiles
+	blobs      map[int64]*filesDomain.FileBlob
+	renditions []filesDomain.FileRendition
+	// referenced are the ids of the files an entity references
+	referenced  map[int64]bool
+	attachments []filesDomain.FileAttachment
+	// entities are the ids of the existing entities of every type
+	entities map[int64]bool
+	nextID   int64
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{files: map[int64]filesDomain.SysFiles{}, blobs: map[int64]*filesDomain.FileBlob{},
+		referenced: map[int64]bool{}, entities: map[int64]bool{}}
+}
+
+func (r *memoryRepository) SaveRendition(_ context.Context, rendition *filesDomain.FileRendition) (*filesDomain.FileRendition, error) {
+	r.nextID++
+	saved := *rendition
+	saved.ID = r.nextID
+	r.renditions = slices.DeleteFunc(r.renditions, func(stored filesDomain.FileRendition) bool {
+		return stored.FileID == saved.FileID && stored.Size == saved.Size
+	})
+	r.renditions = append(r.renditions, saved)
+	return &saved, nil
+}
+
+func (r *memoryRepository) GetRenditions(_ context.Context, fileID int64) (*[]filesDomain.FileRendition, error) {
+	var found []filesDomain.FileRendition
+	for _, rendition := range r.renditions {
+		if rendition.FileID == fileID {
+			found = append(found, rendition)
+		}
+	}
+	return &found, nil
+}
+
+func (r *memoryRepository) DeleteRenditions(_ context.Context, fileIDs []int64) (*[]filesDomain.FileRendition, error) {
+	var deleted, kept []filesDomain.FileRendition
+	for _, rendition := range r.renditions {
+		if slices.Contains(fileIDs, rendition.FileID) {
+			deleted = append(deleted, rendition)
+		} else {
+			kept = append(kept, rendition)
+		}
+	}
+	r.renditions = kept
+	return &deleted, nil
+}
+
+func (r *memoryRepository) IsPublicPath(_ context.Context, path string) (bool, error) {
+	for _, file := range r.files {
+		if file.FilePath == path && isPublic(&file) {
+			return true, nil
+		}
+	}
+	for _, rendition := range r.renditions {
+		if file, ok := r.files[rendition.FileID]; ok && rendition.FilePath == path && isPublic(&file) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryRepository) Create(_ context.Context, data *filesDomain.SysFiles) (*filesDomain.SysFiles, error) {
+	r.nextID++
+	record := *data
+	record.ID = r.nextID
+	record.CreatedAt = time.Now()
+	r.files[record.ID] = record
+	return &record, nil
+}
+
+func (r *memoryRepository) GetByID(_ context.Context, id int) (*filesDomain.SysFiles, error) {
+	record := r.files[int64(id)]
+	return &record, nil
+}
+
+func (r *memoryRepository) AcquireBlob(_ context.Context, blob *filesDomain.FileBlob) (*filesDomain.FileBlob, error) {
+	for _, existing := range r.blobs {
+		if existing.FileMD5 == blob.FileMD5 && existing.FileSize == blob.FileSize {
+			existing.RefCount++
+			copied := *existing
+			return &copied, nil
+		}
+	}
+	r.nextID++
+	stored := *blob
+	stored.ID = r.nextID
+	stored.RefCount = 1
+	stored.UpdatedAt = time.Now()
+	r.blobs[stored.ID] = &stored
+	copied := stored
+	return &copied, nil
+}
+
+func (r *memoryRepository) AcquireBlobByContent(_ context.Context, md5 string, size int64) (*filesDomain.FileBlob, error) {
+	for _, blob := range r.blobs {
+		if (blob.FileMD5 == md5 && blob.FileSize == size) || (blob.SourceMD5 == md5 && blob.SourceSize == size) {
+			blob.RefCount++
+			copied := *blob
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryRepository) ReleaseBlobs(_ context.Context, blobIDs []int64) (*[]filesDomain.FileBlob, error) {
+	var released []filesDomain.FileBlob
+	for _, id := range blobIDs {
+		blob := r.blobs[id]
+		blob.RefCount--
+		if blob.RefCount == 0 {
+			released = append(released, *blob)
+			delete(r.blobs, id)
+		}
+	}
+	return &released, nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, ids []int64) (*[]filesDomain.FileBlob, error) {
+	var blobIDs []int64
+	var legacy []filesDomain.SysFiles
+	for _, id := range ids {
+		if file, ok := r.files[id]; ok {
+			if file.BlobID != nil {
+				blobIDs = append(blobIDs, *file.BlobID)
+			} else {
+				legacy = append(legacy, file)
+			}
+			delete(r.files, id)
+		}
+	}
+	r.attachments = slices.DeleteFunc(r.attachments, func(attachment filesDomain.FileAttachment) bool {
+		return slices.Contains(ids, attachment.FileID)
+	})
+	released, err := r.ReleaseBlobs(ctx, blobIDs)
+	for _, file := range legacy {
+		if known, _ := r.KnownPaths(ctx, file.StorageEngine, []string{file.FilePath}); !known[file.FilePath] {
+			*released = append(*released, filesDomain.FileBlob{FilePath: file.FilePath, StorageEngine: file.StorageEngine})
+		}
+	}
+	return released, err
+}
+
+func (r *memoryRepository) GetOneByMap(_ context.Context, fileMap map[string]interface{}) (*filesDomain.SysFiles, error) {
+	for _, file := range r.files {
+		if file.FilePath == fileMap["file_path"] && file.StorageEngine == fileMap["storage_engine"] {
+			return &file, nil
+		}
+	}
+	return &filesDomain.SysFiles{}, nil
+}
+
+func (r *memoryRepository) KnownPaths(_ context.Context, engine string, paths []string) (map[string]bool, error) {
+	known := map[string]bool{}
+	mark := func(storageEngine, path string) {
+		if storageEngine == engine && slices.Contains(paths, path) {
+			known[path] = true
+		}
+	}
+	for _, file := range r.files {
+		mark(file.StorageEngine, file.FilePath)
+	}
+	for _, blob := range r.blobs {
+		mark(blob.StorageEngine, blob.FilePath)
+	}
+	for _, rendition := range r.renditions {
+		mark(rendition.StorageEngine, rendition.FilePath)
+	}
+	return known, nil
+}
+
+func (r *memoryRepository) DanglingBlobs(_ context.Context, before time.Time, afterID int64, limit int) (*[]filesDomain.FileBlob, error) {
+	var dangling []filesDomain.FileBlob
+	for _, blob := range r.blobs {
+		referenced := slices.ContainsFunc(slices.Collect(maps.Values(r.files)), func(file filesDomain.SysFiles) bool {
+			return file.BlobID != nil && *file.BlobID == blob.ID
+		})
+		if blob.ID > afterID && blob.UpdatedAt.Before(before) && !referenced {
+			dangling = append(dangling, *blob)
+		}
+	}
+	slices.SortFunc(dangling, func(a, b filesDomain.FileBlob) int { return cmp.Compare(a.ID, b.ID) })
+	return &dangling, nil
+}
+
+func (r *memoryRepository) DeleteDanglingBlobs(ctx context.Context, ids []int64, before time.Time) (*[]filesDomain.FileBlob, error) {
+	dangling, _ := r.DanglingBlobs(ctx, before, 0, len(r.blobs))
+	var deleted []filesDomain.FileBlob
+	for _, blob := range *dangling {
+		if slices.Contains(ids, blob.ID) {
+			deleted = append(deleted, blob)
+			delete(r.blobs, blob.ID)
+		}
+	}
+	return &deleted, nil
+}
+
+// UnreferencedFiles returns the owned files neither listed in referenced nor attached
+func (r *memoryRepository) UnreferencedFiles(_ context.Context, before time.Time, afterID int64, limit int) (*[]filesDomain.SysFiles, error) {
+	var unreferenced []filesDomain.SysFiles
+	for _, file := range r.files {
+		attached := slices.ContainsFunc(r.attachments, func(attachment filesDomain.FileAttachment) bool {
+			return attachment.FileID == file.ID
+		})
+		if file.ID > afterID && file.OwnerID != nil && file.CreatedAt.Before(before) && !r.referenced[file.ID] && !attached {
+			unreferenced = append(unreferenced, file)
+		}
+	}
+	slices.SortFunc(unreferenced, func(a, b filesDomain.SysFiles) int { return cmp.Compare(a.ID, b.ID) })
+	return &unreferenced, nil
+}
+
+func newTestUseCase(t *testing.T) (*SysFilesUseCase, *memoryRepository, string) {
+	root := t.TempDir()
+	repository := newMemoryRepository()
+	manager := storage.NewManager(storage.NewLocalDriver(storage.LocalConfig{
+		Root:    root,
+		BaseURL: "http://app",
+		Signer:  storage.NewURLSigner([]byte("secret")),
+	}))
+	access := FileAccessConfig{
+		DefaultVisibility: filesDomain.VisibilityPublic,
+		BaseURL:           "http://api",
+		SignedURLTTL:      time.Minute,
+		MaxSignedURLTTL:   time.Hour,
+	}
+	useCase := NewSysFilesUseCase(repository, manager, nil, nil, access, &logger.Logger{Log: zap.NewNop()}).(*SysFilesUseCase)
+	return useCase, repository, root
+}
+
+func upload(t *testing.T, useCase *SysFilesUseCase, name, content string) *filesDomain.SysFiles {
+	record, err := useCase.Upload(context.Background(), filesDomain.UploadFile{
+		OriginName: name,
+		Size:       -1,
+		Content:    strings.NewReader(content),
+	})
+	require.NoError(t, err)
+	return record
+}
+
+func countStored(t *testing.T, root string) int {
+	count := 0
+	err := filepath.WalkDir(root, func(_ string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			count++
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return count
+}
+
+func TestUploadSharesBlobOfSameContent(t *testing.T) {
+	useCase, repository, root := newTestUseCase(t)
+
+	first := upload(t, useCase, "a.txt", "hello")
+	second := upload(t, useCase, "b.txt", "hello")
+	other := upload(t, useCase, "c.txt", "world")
+
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", first.FileMD5)
+	assert.Equal(t, int64(5), first.FileSize)
+	assert.Equal(t, first.FilePath, second.FilePath, "same content shares the stored object")
+	assert.Equal(t, "b.txt", second.FileOriginName)
+	assert.Equal(t, "http://app/"+first.FilePath, second.FileUrl)
+	assert.NotEqual(t, first.FilePath, other.FilePath)
+	assert.Equal(t, 2, countStored(t, root), "the duplicate object is removed")
+	assert.Equal(t, int64(2), repository.blobs[*first.BlobID].RefCount)
+
+	require.NoError(t, useCase.Delete(context.Background(), []int64{first.ID}))
+	assert.Equal(t, 2, countStored(t, root), "the object is kept while another file uses it")
+	require.NoError(t, useCase.Delete(context.Background(), []int64{second.ID}))
+	assert.Equal(t, 1, countStored(t, root), "the last reference removes the object")
+}
+
+func TestInstantUpload(t *testing.T) {
+	useCase, repository, _ := newTestUseCase(t)
+	stored := upload(t, useCase, "a.txt", "hello")
+
+	missing, err := useCase.InstantUpload(context.Background(), "00000000000000000000000000000000", 5, "x.txt", filesDomain.FileOwner{})
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	hit, err := useCase.InstantUpload(context.Background(), "5D41402ABC4B2A76B9719D911017C592", 5, "copy.txt", filesDomain.FileOwner{})
+	require.NoError(t, err)
+	require.NotNil(t, hit)
+	assert.NotEqual(t, stored.ID, hit.ID)
+	assert.Equal(t, stored.FilePath, hit.FilePath)
+	assert.Equal(t, "copy.txt", hit.FileOriginName)
+	assert.Equal(t, int64(2), repository.blobs[*stored.BlobID].RefCount)
+
+	wrongSize, err := useCase.InstantUpload(context.Background(), stored.FileMD5, 6, "x.txt", filesDomain.FileOwner{})
+	require.NoError(t, err)
+	assert.Nil(t, wrongSize)
+}
+
+// markerScanner reports the content containing MALWARE as infected
+type markerScanner struct{}
+
+func (markerScanner) Name() string {
+	return "marker"
+}
+
+func (markerScanner) Scan(_ context.Context, content io.Reader) (*scanner.Result, error) {
+	data, err := io.ReadAll(content)
+	return &scanner.Result{Infected: strings.Contains(string(data), "MALWARE"), Signature: "Marker"}, err
+}
+
+func TestUploadValidation(t *testing.T) {
+	useCase, repository, root := newTestUseCase(t)
+	useCase.validator = uploadpolicy.NewValidator(uploadpolicy.DefaultPolicies(), markerScanner{}, false)
+
+	reject := func(name, content, reason string) {
+		t.Helper()
+		_, err := useCase.Upload(context.Background(), filesDomain.UploadFile{OriginName: name, Size: -1, Content: strings.NewReader(content)})
+		var appErr *domainErrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, domainErrors.UploadError, appErr.Type)
+		var rejection *uploadpolicy.Rejection
+		require.ErrorAs(t, appErr.Err, &rejection)
+		assert.Equal(t, reason, rejection.Reason)
+	}
+	reject("shell.php", "<?php echo 1;", uploadpolicy.ReasonExtension)
+	reject("invoice.pdf", "<html></html>", uploadpolicy.ReasonContent)
+	reject("notes.txt", "plain text with MALWARE inside", uploadpolicy.ReasonMalware)
+	assert.Equal(t, 0, countStored(t, root), "rejected content is not kept")
+	assert.Empty(t, repository.files)
+
+	record, err := useCase.Upload(context.Background(), filesDomain.UploadFile{
+		OriginName: "notes.txt", Size: -1, ContentType: "text/html", Content: strings.NewReader("plain text"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "notes.txt", record.FileOriginName)
+
+	_, err = useCase.InstantUpload(context.Background(), record.FileMD5, record.FileSize, "page.html", filesDomain.FileOwner{})
+	require.Error(t, err)
+	renamed, err := useCase.InstantUpload(context.Background(), record.FileMD5, record.FileSize, "notes.csv", filesDomain.FileOwner{})
+	require.NoError(t, err)
+	assert.Nil(t, renamed, "another extension has to upload the content")
+	assert.Equal(t, int64(1), repository.blobs[*record.BlobID].RefCount)
+}
+
+// cameraPhoto is a 40x20 JPEG whose EXIF segment holds a location
+func cameraPhoto(t *testing.T) []byte {
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil))
+	data := encoded.Bytes()
+	exif := []byte("Exif\x00\x00MM\x00*\x00\x00\x00\x08\x00\x00\x00\x00\x00\x00GPS-SECRET")
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(exif) + 2)}, exif...)
+	return append(append(append([]byte{}, data[:2]...), segment...), data[2:]...)
+}
+
+func TestImageUpload(t *testing.T) {
+	useCase, repository, root := newTestUseCase(t)
+	useCase.images = imaging.NewProcessor(imaging.Config{
+		Sizes:         []imaging.Size{{Name: "small", Width: 16}, {Name: "large", Width: 1024}},
+		StripMetadata: true,
+		JPEGQuality:   80,
+	})
+	ctx := context.Background()
+
+	record, err := useCase.Upload(ctx, filesDomain.UploadFile{
+		OriginName: "avatar.jpg", Size: -1, ContentType: "image/jpeg", Content: bytes.NewReader(cameraPhoto(t)),
+	})
+	require.NoError(t, err)
+	stored, err := os.ReadFile(filepath.Join(root, record.FilePath))
+	require.NoError(t, err)
+	assert.NotContains(t, string(stored), "GPS-SECRET", "the metadata is stripped")
+	require.Len(t, repository.renditions, 2, "a rendition per size")
+
+	photo := cameraPhoto(t)
+	assert.NotEqual(t, md5Hex(string(photo)), record.FileMD5)
+	instant, err := useCase.InstantUpload(ctx, md5Hex(string(photo)), int64(len(photo)), "copy.jpg", filesDomain.FileOwner{})
+	require.NoError(t, err)
+	require.NotNil(t, instant, "the md5 of the photo as sent finds the stripped blob")
+	assert.Equal(t, record.FilePath, instant.FilePath)
+	require.NoError(t, useCase.Delete(ctx, []int64{instant.ID}))
+
+	rendition, content, err := useCase.Thumbnail(ctx, int(record.ID), "", filesDomain.FileAccess{})
+	require.NoError(t, err)
+	thumbnail, _ := io.ReadAll(content)
+	_ = content.Close()
+	assert.Equal(t, "small", rendition.Size)
+	assert.Equal(t, 16, rendition.Width)
+	assert.Equal(t, 8, rendition.Height)
+	assert.Equal(t, "http://app/"+rendition.FilePath, rendition.FileUrl)
+	config, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, 16, config.Width)
+
+	withRenditions, err := useCase.GetByID(context.Background(), int(record.ID))
+	require.NoError(t, err)
+	assert.Len(t, withRenditions.Renditions, 2)
+
+	large := withRenditions.Renditions[1]
+	require.NoError(t, os.Remove(filepath.Join(root, large.FilePath)))
+	rendered, content, err := useCase.Thumbnail(ctx, int(record.ID), "large", filesDomain.FileAccess{})
+	require.NoError(t, err, "a missing rendition is rendered when asked")
+	_ = content.Close()
+	assert.NotEqual(t, large.ID, rendered.ID)
+	assert.Len(t, repository.renditions, 2)
+
+	_, _, err = useCase.Thumbnail(ctx, int(record.ID), "huge", filesDomain.FileAccess{})
+	assertUploadError(t, err, filesDomain.ErrUnknownThumbnailSize)
+	text := upload(t, useCase, "notes.txt", "hello")
+	_, _, err = useCase.Thumbnail(ctx, int(text.ID), "small", filesDomain.FileAccess{})
+	assertUploadError(t, err, filesDomain.ErrNotAnImage)
+
+	require.NoError(t, useCase.Delete(context.Background(), []int64{record.ID, text.ID}))
+	assert.Empty(t, repository.renditions)
+	assert.Equal(t, 0, countStored(t, root), "renditions are removed with their file")
+}
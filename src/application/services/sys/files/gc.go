@@ -0,0 +1,195 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	"go.uber.org/zap"
+)
+
+// gcBatchSize is how many blobs or files the garbage collection reads at once
+const gcBatchSize = 500
+
+// orphanBatchSize is how many listed keys are looked up at once
+const orphanBatchSize = 500
+
+// Rows returned by UsageByUser
+const (
+	defaultUsageLimit = 100
+	maxUsageLimit     = 1000
+)
+
+// CollectGarbage implements ISysFilesService.
+// The records go first, the objects they leave behind are then found by listing the engines.
+func (s *SysFilesUseCase) CollectGarbage(ctx context.Context, options filesDomain.GCOptions) (*filesDomain.GCReport, error) {
+	cutoff := time.Now().Add(-options.Grace)
+	report := &filesDomain.GCReport{DryRun: options.DryRun}
+	if err := s.collectBlobs(ctx, cutoff, options.DryRun, report); err != nil {
+		return report, err
+	}
+	if err := s.collectFiles(ctx, cutoff, options.CollectFiles && !options.DryRun, report); err != nil {
+		return report, err
+	}
+	// without a key prefix the local engine would list the working directory
+	if s.storage.KeyPrefix() == "" {
+		s.Logger.Warn("No storage key prefix, the orphaned objects are not searched")
+	} else {
+		for _, engine := range s.storage.Engines() {
+			driver, err := s.storage.Driver(engine)
+			if err != nil {
+				return report, err
+			}
+			if _, ok := driver.(storage.Lister); !ok {
+				s.Logger.Info("Storage engine can't list its objects", zap.String("engine", engine))
+				continue
+			}
+			orphans, err := findOrphans(ctx, s.sysFilesRepository, driver, s.storage.KeyPrefix()+"/", cutoff,
+				!options.DryRun, s.Logger)
+			report.OrphanObjects = append(report.OrphanObjects, orphans...)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+	s.Logger.Info("Collected file garbage",
+		zap.Int("danglingBlobs", len(report.DanglingBlobs)),
+		zap.Int("unreferencedFiles", len(report.UnreferencedFiles)),
+		zap.Int("orphanObjects", len(report.OrphanObjects)),
+		zap.Bool("dryRun", options.DryRun))
+	return report, nil
+}
+
+func (s *SysFilesUseCase) collectBlobs(ctx context.Context, cutoff time.Time, dryRun bool, report *filesDomain.GCReport) error {
+	var afterID int64
+	for {
+		blobs, err := s.sysFilesRepository.DanglingBlobs(ctx, cutoff, afterID, gcBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(*blobs) == 0 {
+			return nil
+		}
+		afterID = (*blobs)[len(*blobs)-1].ID
+		if dryRun {
+			report.DanglingBlobs = append(report.DanglingBlobs, *blobs...)
+		} else {
+			ids := make([]int64, len(*blobs))
+			for i, blob := range *blobs {
+				ids[i] = blob.ID
+			}
+			deleted, err := s.sysFilesRepository.DeleteDanglingBlobs(ctx, ids, cutoff)
+			if err != nil {
+				return err
+			}
+			s.deleteBlobObjects(ctx, deleted)
+			report.DanglingBlobs = append(report.DanglingBlobs, *deleted...)
+		}
+		if len(*blobs) < gcBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *SysFilesUseCase) collectFiles(ctx context.Context, cutoff time.Time, remove bool, report *filesDomain.GCReport) error {
+	var afterID int64
+	for {
+		unreferenced, err := s.sysFilesRepository.UnreferencedFiles(ctx, cutoff, afterID, gcBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(*unreferenced) == 0 {
+			return nil
+		}
+		afterID = (*unreferenced)[len(*unreferenced)-1].ID
+		report.UnreferencedFiles = append(report.UnreferencedFiles, *unreferenced...)
+		if remove {
+			ids := make([]int64, len(*unreferenced))
+			for i, file := range *unreferenced {
+				ids[i] = file.ID
+			}
+			if err := s.Delete(ctx, ids); err != nil {
+				return err
+			}
+		}
+		if len(*unreferenced) < gcBatchSize {
+			return nil
+		}
+	}
+}
+
+// findOrphans lists the objects of driver under prefix last modified before the cutoff and
+// returns those no record references, removed when remove is set
+func findOrphans(ctx context.Context, repository files.ISysFilesRepository, driver storage.Driver, prefix string,
+	cutoff time.Time, remove bool, log *logger.Logger) ([]filesDomain.OrphanObject, error) {
+	engine := driver.Name()
+	lister, ok := driver.(storage.Lister)
+	if !ok {
+		return nil, fmt.Errorf("storage engine %q can't list its objects", engine)
+	}
+	var orphans []filesDomain.OrphanObject
+	batch := make([]storage.ObjectInfo, 0, orphanBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		keys := make([]string, len(batch))
+		for i, info := range batch {
+			keys[i] = info.Key
+		}
+		known, err := repository.KnownPaths(ctx, engine, keys)
+		if err != nil {
+			return err
+		}
+		for _, info := range batch {
+			if known[info.Key] {
+				continue
+			}
+			orphans = append(orphans, filesDomain.OrphanObject{Engine: engine, Key: info.Key, Size: info.Size,
+				LastModified: info.LastModified})
+			log.Info("Orphaned object", zap.String("engine", engine), zap.String("key", info.Key),
+				zap.Int64("size", info.Size), zap.Bool("removed", remove))
+			if remove {
+				if err := driver.Delete(ctx, info.Key); err != nil {
+					return err
+				}
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+	err := lister.List(ctx, prefix, func(info storage.ObjectInfo) error {
+		if info.LastModified.After(cutoff) {
+			return nil
+		}
+		batch = append(batch, info)
+		if len(batch) < orphanBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		log.Error("Error searching orphaned objects", zap.String("engine", engine), zap.Error(err))
+	}
+	return orphans, err
+}
+
+// UsageByUser implements ISysFilesService.
+func (s *SysFilesUseCase) UsageByUser(ctx context.Context, userID int64, limit int) (*[]filesDomain.UserStorageUsage, error) {
+	if limit <= 0 {
+		limit = defaultUsageLimit
+	}
+	return s.sysFilesRepository.UsageByOwner(ctx, userID, min(limit, maxUsageLimit))
+}
+
+// UsageByEngine implements ISysFilesService.
+func (s *SysFilesUseCase) UsageByEngine(ctx context.Context) (*[]filesDomain.EngineStorageUsage, error) {
+	return s.sysFilesRepository.UsageByEngine(ctx)
+}
@@ -0,0 +1,75 @@
+package files
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRemovesObjectOfFilesWithoutBlob(t *testing.T) {
+	useCase, repository, root := newTestUseCase(t)
+	putObject(t, root, "public/legacy.txt", "old")
+	first, _ := repository.Create(context.Background(), &filesDomain.SysFiles{FilePath: "public/legacy.txt", StorageEngine: storage.EngineLocal})
+	second, _ := repository.Create(context.Background(), &filesDomain.SysFiles{FilePath: "public/legacy.txt", StorageEngine: storage.EngineLocal})
+
+	require.NoError(t, useCase.Delete(context.Background(), []int64{first.ID}))
+	assert.Equal(t, 1, countStored(t, root), "the object is kept while another record uses it")
+	require.NoError(t, useCase.Delete(context.Background(), []int64{second.ID}))
+	assert.Equal(t, 0, countStored(t, root))
+}
+
+func TestCollectGarbage(t *testing.T) {
+	useCase, repository, root := newTestUseCase(t)
+	owner := filesDomain.FileOwner{UserID: 7}
+	kept := upload(t, useCase, "kept.txt", "kept")
+	repository.referenced[kept.ID] = true
+	unreferenced, err := useCase.Upload(context.Background(), filesDomain.UploadFile{OriginName: "lost.txt", Size: -1,
+		Content: strings.NewReader("lost"), Owner: owner})
+	require.NoError(t, err)
+	archive, _ := repository.Create(context.Background(), &filesDomain.SysFiles{FilePath: "storage/archive/records.jsonl.gz",
+		StorageEngine: storage.EngineLocal})
+	putObject(t, root, archive.FilePath, "archive")
+	// a blob left by an upload that failed after acquiring it
+	putObject(t, root, "public/dangling.txt", "dangling")
+	_, _ = repository.AcquireBlob(context.Background(), &filesDomain.FileBlob{FileMD5: "d", FileSize: 8, FilePath: "public/dangling.txt",
+		StorageEngine: storage.EngineLocal})
+	putObject(t, root, "public/orphan.txt", "orphan")
+	require.Equal(t, 5, countStored(t, root))
+
+	recent, err := useCase.CollectGarbage(context.Background(), filesDomain.GCOptions{Grace: time.Hour})
+	require.NoError(t, err)
+	assert.Empty(t, recent.DanglingBlobs, "nothing within the grace period is touched")
+	assert.Empty(t, recent.UnreferencedFiles)
+	assert.Empty(t, recent.OrphanObjects)
+
+	dryRun, err := useCase.CollectGarbage(context.Background(), filesDomain.GCOptions{CollectFiles: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, dryRun.DanglingBlobs, 1)
+	assert.Len(t, dryRun.OrphanObjects, 1)
+	assert.Len(t, dryRun.UnreferencedFiles, 1)
+	assert.Equal(t, 5, countStored(t, root), "a dry run removes nothing")
+
+	report, err := useCase.CollectGarbage(context.Background(), filesDomain.GCOptions{})
+	require.NoError(t, err)
+	require.Len(t, report.DanglingBlobs, 1)
+	assert.Equal(t, "public/dangling.txt", report.DanglingBlobs[0].FilePath)
+	require.Len(t, report.OrphanObjects, 1)
+	assert.Equal(t, "public/orphan.txt", report.OrphanObjects[0].Key)
+	require.Len(t, report.UnreferencedFiles, 1)
+	assert.Equal(t, unreferenced.ID, report.UnreferencedFiles[0].ID)
+	assert.Contains(t, repository.files, unreferenced.ID, "unreferenced files are only reported by default")
+	assert.Equal(t, 3, countStored(t, root))
+
+	_, err = useCase.CollectGarbage(context.Background(), filesDomain.GCOptions{CollectFiles: true})
+	require.NoError(t, err)
+	assert.NotContains(t, repository.files, unreferenced.ID)
+	assert.Contains(t, repository.files, kept.ID)
+	assert.Contains(t, repository.files, archive.ID, "files without owner are never collected")
+	assert.Equal(t, 2, countStored(t, root))
+}
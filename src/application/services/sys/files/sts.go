@@ -0,0 +1,268 @@
+package files
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	auditLogDomain "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/sts"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadpolicy"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// STSPrefix is the segment after the storage key prefix under which STS uploads are written,
+// followed by the user id and an id unique to the token
+const STSPrefix = "sts"
+
+// stsGrantGrace keeps the grant of a prefix past the expiration of its token, for the callbacks
+// of the uploads started just before
+const stsGrantGrace = time.Hour
+
+// STSConfig configures the STS tokens. Tokens are cached until RenewBefore their expiration.
+type STSConfig struct {
+	BucketName string
+	Region     string
+	// KeyPrefix is the storage key prefix the upload prefixes start with
+	KeyPrefix   string
+	Duration    time.Duration
+	RenewBefore time.Duration
+	// MaxSize bounds the size allowed by a token below the limit of the upload policy, 0 leaves
+	// the policy limit
+	MaxSize int64
+}
+
+func LoadSTSConfigFromEnv() STSConfig {
+	return STSConfig{
+		BucketName:  sharedUtil.GetEnv("OSS_BUCKET_NAME", ""),
+		Region:      sharedUtil.GetEnv("SECURITY_REGION_ID", ""),
+		Duration:    time.Duration(sharedUtil.GetEnvAsInt("STS_DURATION_SECONDS", 3600)) * time.Second,
+		RenewBefore: 5 * time.Minute,
+		MaxSize:     int64(sharedUtil.GetEnvAsInt("STS_MAX_SIZE_MB", 0)) << 20,
+	}
+}
+
+// STSTokenStore caches the tokens and the refresh tokens, implemented by cache.STSCacheService
+type STSTokenStore interface {
+	SetSTSToken(ctx context.Context, key string, token *filesDomain.STSTokenCache, expiration time.Duration) error
+	GetSTSToken(ctx context.Context, key string) (*filesDomain.STSTokenCache, error)
+	GenerateRefreshToken(ctx context.Context, userID string, scope filesDomain.STSScope) (string, error)
+	ValidateRefreshToken(ctx context.Context, token string) (*filesDomain.RefreshToken, error)
+	DeleteRefreshToken(ctx context.Context, token string) error
+	SetUploadGrant(ctx context.Context, prefix string, grant *filesDomain.STSGrant, expiration time.Duration) error
+	GetUploadGrant(ctx context.Context, prefix string) (*filesDomain.STSGrant, error)
+}
+
+// AuditRecorder stores audit entries, implemented by the audit log repository
+type AuditRecorder interface {
+	Create(ctx context.Context, entry *auditLogDomain.AuditLog) error
+}
+
+type STSUseCase struct {
+	// provider is nil when STS isn't configured
+	provider  sts.Provider
+	store     STSTokenStore
+	audit     AuditRecorder
+	validator *uploadpolicy.Validator
+	config    STSConfig
+	Logger    *logger.Logger
+}
+
+func NewSTSUseCase(provider sts.Provider, store STSTokenStore, audit AuditRecorder, validator *uploadpolicy.Validator,
+	config STSConfig, loggerInstance *logger.Logger) filesDomain.ISTSService {
+	return &STSUseCase{
+		provider:  provider,
+		store:     store,
+		audit:     audit,
+		validator: validator,
+		config:    config,
+		Logger:    loggerInstance,
+	}
+}
+
+// Issue implements ISTSService.
+func (s *STSUseCase) Issue(ctx context.Context, request filesDomain.STSRequest) (*filesDomain.STSToken, error) {
+	if s.provider == nil {
+		return nil, domainErrors.NewAppError(filesDomain.ErrSTSNotConfigured, domainErrors.UploadError)
+	}
+	if request.UserID == 0 {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotAuthenticated)
+	}
+	scope, err := s.scope(request)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := stsCacheKey(request.UserID, scope)
+	if cached, err := s.store.GetSTSToken(ctx, cacheKey); err == nil && time.Until(cached.Expiration) > s.config.RenewBefore {
+		// refresh tokens are used once, every token served gets its own
+		return tokenFromCache(cached, s.refreshToken(ctx, request.UserID, cached.Scope)), nil
+	}
+	return s.issue(ctx, request.UserID, request.RoleID, scope, cacheKey, false)
+}
+
+// Refresh implements ISTSService.
+func (s *STSUseCase) Refresh(ctx context.Context, userID int64, roleID int64, refreshToken string) (*filesDomain.STSToken, error) {
+	if s.provider == nil {
+		return nil, domainErrors.NewAppError(filesDomain.ErrSTSNotConfigured, domainErrors.UploadError)
+	}
+	stored, err := s.store.ValidateRefreshToken(ctx, refreshToken)
+	if err != nil || stored.UserID != strconv.FormatInt(userID, 10) {
+		s.Logger.Warn("STS refresh token rejected", zap.Int64("userId", userID), zap.Error(err))
+		return nil, domainErrors.NewAppError(filesDomain.ErrInvalidRefreshToken, domainErrors.NotAuthenticated)
+	}
+	// refresh tokens are used once
+	if err := s.store.DeleteRefreshToken(ctx, refreshToken); err != nil {
+		s.Logger.Error("Error deleting STS refresh token", zap.Int64("userId", userID), zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return s.issue(ctx, userID, roleID, stored.Scope, stsCacheKey(userID, stored.Scope), true)
+}
+
+// scope limits a token to the file asked: the extension of its name, the size limit of the
+// upload policy and the content types of the extension
+func (s *STSUseCase) scope(request filesDomain.STSRequest) (filesDomain.STSScope, error) {
+	scope := filesDomain.STSScope{Extension: strings.ToLower(path.Ext(request.FileName)), MaxSize: s.config.MaxSize}
+	if s.validator != nil {
+		policy, err := s.validator.Check(request.FileName, request.FileSize)
+		if err != nil {
+			return scope, domainErrors.NewAppError(err, domainErrors.UploadError)
+		}
+		if policy.MaxSize > 0 && (scope.MaxSize == 0 || policy.MaxSize < scope.MaxSize) {
+			scope.MaxSize = policy.MaxSize
+		}
+		scope.ContentTypes = policy.Extensions[scope.Extension]
+	}
+	if request.FileSize > 0 {
+		if scope.MaxSize > 0 && request.FileSize > scope.MaxSize {
+			return scope, domainErrors.NewAppError(fmt.Errorf("file size exceeds the limit of %d bytes", scope.MaxSize),
+				domainErrors.ValidationError)
+		}
+		scope.MaxSize = request.FileSize
+	}
+	if request.ContentType != "" {
+		mediaType, _, err := mime.ParseMediaType(request.ContentType)
+		if err != nil || (scope.ContentTypes != nil && !slices.Contains(scope.ContentTypes, mediaType)) {
+			return scope, domainErrors.NewAppError(fmt.Errorf("content type %q is not allowed for %s files",
+				request.ContentType, scope.Extension), domainErrors.ValidationError)
+		}
+		scope.ContentTypes = []string{mediaType}
+	}
+	return scope, nil
+}
+
+// issue has credentials issued for a new prefix of the user, records the issuance and caches them
+// with the grant of the prefix
+func (s *STSUseCase) issue(ctx context.Context, userID int64, roleID int64, scope filesDomain.STSScope, cacheKey string,
+	refreshed bool) (*filesDomain.STSToken, error) {
+	scope.Prefix = path.Join(s.config.KeyPrefix, STSPrefix, strconv.FormatInt(userID, 10),
+		strings.ReplaceAll(uuid.NewString(), "-", "")) + "/"
+	policy, err := sts.UploadPolicy(s.config.BucketName, scope.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	sessionName := fmt.Sprintf("upload-%d-%d", userID, time.Now().Unix())
+	credentials, err := s.provider.AssumeRole(ctx, sts.AssumeRoleRequest{
+		SessionName: sessionName,
+		Policy:      policy,
+		Duration:    s.config.Duration,
+	})
+	if err != nil {
+		s.Logger.Error("Failed to generate STS token", zap.Int64("userId", userID), zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	s.record(ctx, userID, sessionName, credentials, scope, refreshed)
+	grant := &filesDomain.STSGrant{UserID: userID, RoleID: roleID, Scope: scope}
+	if err := s.store.SetUploadGrant(ctx, scope.Prefix, grant, time.Until(credentials.Expiration)+stsGrantGrace); err != nil {
+		// without the grant the uploads can't be recorded by their callback
+		s.Logger.Error("Failed to store STS upload grant", zap.Int64("userId", userID), zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+
+	refreshToken := s.refreshToken(ctx, userID, scope)
+	cached := &filesDomain.STSTokenCache{
+		AccessKeyId:     credentials.AccessKeyID,
+		AccessKeySecret: credentials.AccessKeySecret,
+		SecurityToken:   credentials.SecurityToken,
+		Expiration:      credentials.Expiration,
+		BucketName:      s.config.BucketName,
+		Region:          s.config.Region,
+		Scope:           scope,
+		CreatedAt:       time.Now(),
+	}
+	// cached until a minute before it expires, it is renewed RenewBefore its expiration anyway
+	if duration := time.Until(credentials.Expiration) - time.Minute; duration > 0 {
+		if err := s.store.SetSTSToken(ctx, cacheKey, cached, duration); err != nil {
+			s.Logger.Error("Failed to cache STS token", zap.Int64("userId", userID), zap.Error(err))
+		}
+	}
+	return tokenFromCache(cached, refreshToken), nil
+}
+
+// refreshToken generates a refresh token for the scope, empty when it fails: the token is still
+// returned, it just can't be refreshed
+func (s *STSUseCase) refreshToken(ctx context.Context, userID int64, scope filesDomain.STSScope) string {
+	refreshToken, err := s.store.GenerateRefreshToken(ctx, strconv.FormatInt(userID, 10), scope)
+	if err != nil {
+		s.Logger.Error("Failed to generate refresh token", zap.Int64("userId", userID), zap.Error(err))
+	}
+	return refreshToken
+}
+
+// record writes the issuance to the audit log, the access key id identifies the credentials in
+// the logs of the cloud provider
+func (s *STSUseCase) record(ctx context.Context, userID int64, sessionName string, credentials *sts.Credentials, scope filesDomain.STSScope, refreshed bool) {
+	if s.audit == nil {
+		return
+	}
+	entry := &auditLogDomain.AuditLog{
+		EntityType: auditLogDomain.EntitySTSToken,
+		EntityID:   strconv.FormatInt(userID, 10),
+		ActorID:    userID,
+		Action:     auditLogDomain.ActionIssue,
+		Changes: map[string]auditLogDomain.Change{
+			"session_name":  {After: sessionName},
+			"access_key_id": {After: credentials.AccessKeyID},
+			"expiration":    {After: credentials.Expiration},
+			"bucket":        {After: s.config.BucketName},
+			"prefix":        {After: scope.Prefix},
+			"max_size":      {After: scope.MaxSize},
+			"content_types": {After: scope.ContentTypes},
+			"refreshed":     {After: refreshed},
+		},
+	}
+	if err := s.audit.Create(ctx, entry); err != nil {
+		s.Logger.Error("Error recording STS issuance audit log", zap.Int64("userId", userID), zap.Error(err))
+	}
+}
+
+// stsCacheKey keys the cached token by user and by the limits of its scope, the prefix being
+// unique to the token
+func stsCacheKey(userID int64, scope filesDomain.STSScope) string {
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s", scope.Extension, scope.MaxSize, strings.Join(scope.ContentTypes, ","))))
+	return fmt.Sprintf("sts_token:%d:%s", userID, hex.EncodeToString(digest[:8]))
+}
+
+func tokenFromCache(cached *filesDomain.STSTokenCache, refreshToken string) *filesDomain.STSToken {
+	return &filesDomain.STSToken{
+		AccessKeyId:     cached.AccessKeyId,
+		AccessKeySecret: cached.AccessKeySecret,
+		SecurityToken:   cached.SecurityToken,
+		Expiration:      cached.Expiration,
+		BucketName:      cached.BucketName,
+		Region:          cached.Region,
+		Scope:           cached.Scope,
+		RefreshToken:    refreshToken,
+	}
+}
@@ -0,0 +1,204 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	auditLogDomain "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/sts"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadpolicy"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubProvider issues numbered credentials and keeps the requests
+type stubProvider struct {
+	requests []sts.AssumeRoleRequest
+	err      error
+}
+
+func (p *stubProvider) AssumeRole(_ context.Context, request sts.AssumeRoleRequest) (*sts.Credentials, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.requests = append(p.requests, request)
+	return &sts.Credentials{
+		AccessKeyID:     fmt.Sprintf("key-%d", len(p.requests)),
+		AccessKeySecret: "secret",
+		SecurityToken:   "token",
+		Expiration:      time.Now().Add(request.Duration),
+	}, nil
+}
+
+type memoryTokenStore struct {
+	tokens        map[string]filesDomain.STSTokenCache
+	refreshTokens map[string]filesDomain.RefreshToken
+	grants        map[string]filesDomain.STSGrant
+	generated     int
+}
+
+func (m *memoryTokenStore) SetSTSToken(_ context.Context, key string, token *filesDomain.STSTokenCache, _ time.Duration) error {
+	m.tokens[key] = *token
+	return nil
+}
+
+func (m *memoryTokenStore) GetSTSToken(_ context.Context, key string) (*filesDomain.STSTokenCache, error) {
+	token, ok := m.tokens[key]
+	if !ok {
+		return nil, errors.New("not cached")
+	}
+	return &token, nil
+}
+
+func (m *memoryTokenStore) GenerateRefreshToken(_ context.Context, userID string, scope filesDomain.STSScope) (string, error) {
+	m.generated++
+	token := fmt.Sprintf("rt_%d", m.generated)
+	m.refreshTokens[token] = filesDomain.RefreshToken{Token: token, UserID: userID, Scope: scope}
+	return token, nil
+}
+
+func (m *memoryTokenStore) ValidateRefreshToken(_ context.Context, token string) (*filesDomain.RefreshToken, error) {
+	refreshToken, ok := m.refreshTokens[token]
+	if !ok {
+		return nil, errors.New("unknown refresh token")
+	}
+	return &refreshToken, nil
+}
+
+func (m *memoryTokenStore) DeleteRefreshToken(_ context.Context, token string) error {
+	delete(m.refreshTokens, token)
+	return nil
+}
+
+func (m *memoryTokenStore) SetUploadGrant(_ context.Context, prefix string, grant *filesDomain.STSGrant, _ time.Duration) error {
+	m.grants[prefix] = *grant
+	return nil
+}
+
+func (m *memoryTokenStore) GetUploadGrant(_ context.Context, prefix string) (*filesDomain.STSGrant, error) {
+	grant, ok := m.grants[prefix]
+	if !ok {
+		return nil, errors.New("no grant")
+	}
+	return &grant, nil
+}
+
+type memoryAudit struct {
+	entries []auditLogDomain.AuditLog
+}
+
+func (m *memoryAudit) Create(_ context.Context, entry *auditLogDomain.AuditLog) error {
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func newTestSTSUseCase() (*STSUseCase, *stubProvider, *memoryTokenStore, *memoryAudit) {
+	provider := &stubProvider{}
+	store := &memoryTokenStore{tokens: map[string]filesDomain.STSTokenCache{}, refreshTokens: map[string]filesDomain.RefreshToken{},
+		grants: map[string]filesDomain.STSGrant{}}
+	audit := &memoryAudit{}
+	config := STSConfig{BucketName: "media", Region: "oss-cn-guangzhou", KeyPrefix: "public", Duration: time.Hour,
+		RenewBefore: 5 * time.Minute}
+	validator := uploadpolicy.NewValidator(uploadpolicy.DefaultPolicies(), nil, false)
+	useCase := NewSTSUseCase(provider, store, audit, validator, config, &logger.Logger{Log: zap.NewNop()}).(*STSUseCase)
+	return useCase, provider, store, audit
+}
+
+func TestSTSIssueIsScopedPerUser(t *testing.T) {
+	useCase, provider, store, audit := newTestSTSUseCase()
+	ctx := context.Background()
+
+	token, err := useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, RoleID: 2, FileName: "photo.JPG", FileSize: 1000, ContentType: "image/jpeg"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token.Scope.Prefix, "public/sts/7/"), token.Scope.Prefix)
+	assert.Equal(t, int64(1000), token.Scope.MaxSize)
+	assert.Equal(t, []string{"image/jpeg"}, token.Scope.ContentTypes)
+	assert.NotEmpty(t, token.RefreshToken)
+	require.Len(t, provider.requests, 1)
+	assert.Contains(t, provider.requests[0].Policy, "acs:oss:*:*:media/"+token.Scope.Prefix+"*")
+	assert.Equal(t, filesDomain.STSGrant{UserID: 7, RoleID: 2, Scope: token.Scope}, store.grants[token.Scope.Prefix])
+
+	cached, err := useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "other.jpg", FileSize: 1000, ContentType: "image/jpeg"})
+	require.NoError(t, err)
+	assert.Equal(t, token.AccessKeyId, cached.AccessKeyId, "the same scope is served from the cache")
+	assert.Len(t, provider.requests, 1)
+	assert.NotEmpty(t, cached.RefreshToken, "a cached token can be refreshed too")
+	assert.NotEqual(t, token.RefreshToken, cached.RefreshToken, "refresh tokens are used once")
+
+	other, err := useCase.Issue(ctx, filesDomain.STSRequest{UserID: 8, FileName: "photo.jpg", FileSize: 1000, ContentType: "image/jpeg"})
+	require.NoError(t, err)
+	assert.NotEqual(t, token.AccessKeyId, other.AccessKeyId, "users don't share tokens")
+	assert.True(t, strings.HasPrefix(other.Scope.Prefix, "public/sts/8/"))
+
+	require.Len(t, audit.entries, 2)
+	assert.Equal(t, auditLogDomain.EntitySTSToken, audit.entries[0].EntityType)
+	assert.Equal(t, auditLogDomain.ActionIssue, audit.entries[0].Action)
+	assert.Equal(t, "7", audit.entries[0].EntityID)
+	assert.Equal(t, token.Scope.Prefix, audit.entries[0].Changes["prefix"].After)
+	assert.Equal(t, "key-1", audit.entries[0].Changes["access_key_id"].After)
+}
+
+func TestSTSIssueRejectsOutOfPolicyFiles(t *testing.T) {
+	useCase, provider, _, _ := newTestSTSUseCase()
+	ctx := context.Background()
+
+	_, err := useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "page.html", FileSize: -1})
+	assertAppError(t, err, domainErrors.UploadError)
+	_, err = useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "photo.jpg", FileSize: 1 << 30})
+	assertAppError(t, err, domainErrors.UploadError)
+	_, err = useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "photo.jpg", FileSize: -1, ContentType: "text/html"})
+	assertAppError(t, err, domainErrors.ValidationError)
+	_, err = useCase.Issue(ctx, filesDomain.STSRequest{FileName: "photo.jpg", FileSize: -1})
+	assertAppError(t, err, domainErrors.NotAuthenticated)
+	assert.Empty(t, provider.requests)
+
+	token, err := useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "photo.png", FileSize: -1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(20<<20), token.Scope.MaxSize, "the size limit of the image policy")
+	assert.Equal(t, []string{"image/png"}, token.Scope.ContentTypes)
+
+	provider.err = errors.New("sts unavailable")
+	_, err = useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "notes.pdf", FileSize: -1})
+	assertAppError(t, err, domainErrors.UploadError)
+
+	unconfigured := NewSTSUseCase(nil, nil, nil, nil, STSConfig{}, &logger.Logger{Log: zap.NewNop()})
+	_, err = unconfigured.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "photo.png", FileSize: -1})
+	assertAppError(t, err, domainErrors.UploadError)
+}
+
+func TestSTSRefresh(t *testing.T) {
+	useCase, provider, store, audit := newTestSTSUseCase()
+	ctx := context.Background()
+	token, err := useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "photo.jpg", FileSize: 1000})
+	require.NoError(t, err)
+
+	_, err = useCase.Refresh(ctx, 8, 2, token.RefreshToken)
+	assertAppError(t, err, domainErrors.NotAuthenticated)
+	assert.Contains(t, store.refreshTokens, token.RefreshToken, "another user doesn't consume the token")
+
+	refreshed, err := useCase.Refresh(ctx, 7, 2, token.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, token.Scope.MaxSize, refreshed.Scope.MaxSize)
+	assert.NotEqual(t, token.Scope.Prefix, refreshed.Scope.Prefix)
+	assert.Len(t, provider.requests, 2)
+	assert.Equal(t, true, audit.entries[1].Changes["refreshed"].After)
+
+	_, err = useCase.Refresh(ctx, 7, 2, token.RefreshToken)
+	assertAppError(t, err, domainErrors.NotAuthenticated)
+
+	cached, err := useCase.Issue(ctx, filesDomain.STSRequest{UserID: 7, FileName: "other.jpg", FileSize: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, refreshed.AccessKeyId, cached.AccessKeyId)
+	fromCache, err := useCase.Refresh(ctx, 7, 2, cached.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, token.Scope.MaxSize, fromCache.Scope.MaxSize)
+	assert.Len(t, provider.requests, 3)
+}
@@ -0,0 +1,102 @@
+package ignore_api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ignoreApiDomain "github.com/gbrayhan/microservices-go/src/domain/sys/ignore_api"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	ignoreApiRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/ignore_api"
+	"go.uber.org/zap"
+)
+
+type ISysIgnoreApiService interface {
+	GetAll(ctx context.Context) (*[]ignoreApiDomain.IgnoreApi, error)
+	GetByID(ctx context.Context, id int) (*ignoreApiDomain.IgnoreApi, error)
+	Create(ctx context.Context, newIgnoreApi *ignoreApiDomain.IgnoreApi) (*ignoreApiDomain.IgnoreApi, error)
+	Update(ctx context.Context, id int, ignoreApiMap map[string]interface{}) (*ignoreApiDomain.IgnoreApi, error)
+	Delete(ctx context.Context, ids []int) error
+	IsIgnored(path string, method string) bool
+	Refresh(ctx context.Context) error
+}
+
+// SysIgnoreApiUseCase keeps the ignore list in memory so the middlewares match requests
+// without a query, the cache is reloaded after every change.
+type SysIgnoreApiUseCase struct {
+	ignoreApiRepository ignoreApiRepo.IgnoreApiRepositoryInterface
+	Logger              *logger.Logger
+	mu                  sync.RWMutex
+	cache               []ignoreApiDomain.IgnoreApi
+}
+
+func NewSysIgnoreApiUseCase(
+	ignoreApiRepository ignoreApiRepo.IgnoreApiRepositoryInterface,
+	loggerInstance *logger.Logger) ISysIgnoreApiService {
+	return &SysIgnoreApiUseCase{
+		ignoreApiRepository: ignoreApiRepository,
+		Logger:              loggerInstance,
+	}
+}
+
+func (s *SysIgnoreApiUseCase) GetAll(ctx context.Context) (*[]ignoreApiDomain.IgnoreApi, error) {
+	s.Logger.Info("Getting all ignore apis")
+	return s.ignoreApiRepository.GetAll(ctx)
+}
+
+func (s *SysIgnoreApiUseCase) GetByID(ctx context.Context, id int) (*ignoreApiDomain.IgnoreApi, error) {
+	s.Logger.Info("Getting ignore api by ID", zap.Int("id", id))
+	return s.ignoreApiRepository.GetByID(ctx, id)
+}
+
+func (s *SysIgnoreApiUseCase) Create(ctx context.Context, newIgnoreApi *ignoreApiDomain.IgnoreApi) (*ignoreApiDomain.IgnoreApi, error) {
+	s.Logger.Info("Creating new ignore api", zap.String("path", newIgnoreApi.Path))
+	ignoreApi, err := s.ignoreApiRepository.Create(ctx, newIgnoreApi)
+	if err != nil {
+		return nil, err
+	}
+	return ignoreApi, s.Refresh(ctx)
+}
+
+func (s *SysIgnoreApiUseCase) Update(ctx context.Context, id int, ignoreApiMap map[string]interface{}) (*ignoreApiDomain.IgnoreApi, error) {
+	s.Logger.Info("Updating ignore api", zap.Int("id", id))
+	ignoreApi, err := s.ignoreApiRepository.Update(ctx, id, ignoreApiMap)
+	if err != nil {
+		return nil, err
+	}
+	return ignoreApi, s.Refresh(ctx)
+}
+
+func (s *SysIgnoreApiUseCase) Delete(ctx context.Context, ids []int) error {
+	s.Logger.Info("Deleting ignore api", zap.String("ids", fmt.Sprintf("%v", ids)))
+	if err := s.ignoreApiRepository.Delete(ctx, ids); err != nil {
+		return err
+	}
+	return s.Refresh(ctx)
+}
+
+// IsIgnored reports whether the request matches an entry of the cached ignore list
+func (s *SysIgnoreApiUseCase) IsIgnored(path string, method string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, ignoreApi := range s.cache {
+		if ignoreApi.Matches(path, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh reloads the ignore list from the database
+func (s *SysIgnoreApiUseCase) Refresh(ctx context.Context) error {
+	ignoreApis, err := s.ignoreApiRepository.GetAll(ctx)
+	if err != nil {
+		s.Logger.Error("Error refreshing ignore api cache", zap.Error(err))
+		return err
+	}
+	s.mu.Lock()
+	s.cache = *ignoreApis
+	s.mu.Unlock()
+	s.Logger.Info("Ignore api cache refreshed", zap.Int("count", len(*ignoreApis)))
+	return nil
+}
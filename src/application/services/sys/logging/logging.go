@@ -0,0 +1,199 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	loggingDomain "github.com/gbrayhan/microservices-go/src/domain/sys/logging"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	configRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type ISysLoggingService interface {
+	GetLevels() (*loggingDomain.Levels, error)
+	SetLevel(component string, level string) (*loggingDomain.Levels, error)
+	ResetLevel(component string) (*loggingDomain.Levels, error)
+	Reload(ctx context.Context) (*loggingDomain.Levels, error)
+}
+
+// SysLoggingUseCase changes the levels of the running logger. Levels set through the admin
+// endpoint last until the next reload or restart, the persistent settings live in the "log"
+// module of sys_config and are applied by Reload.
+type SysLoggingUseCase struct {
+	sysConfigRepository configRepo.ConfigRepositoryInterface
+	Logger              *logger.Logger
+}
+
+func NewSysLoggingUseCase(
+	sysConfigRepository configRepo.ConfigRepositoryInterface,
+	loggerInstance *logger.Logger) ISysLoggingService {
+	return &SysLoggingUseCase{
+		sysConfigRepository: sysConfigRepository,
+		Logger:              loggerInstance,
+	}
+}
+
+func (s *SysLoggingUseCase) GetLevels() (*loggingDomain.Levels, error) {
+	registry, err := s.registry()
+	if err != nil {
+		return nil, err
+	}
+	return toDomainLevels(registry.Snapshot()), nil
+}
+
+// SetLevel changes the default level when component is empty, otherwise overrides the
+// level of that component
+func (s *SysLoggingUseCase) SetLevel(component string, level string) (*loggingDomain.Levels, error) {
+	registry, err := s.registry()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := logger.ParseLevel(level)
+	if err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	if component == "" {
+		registry.SetDefault(parsed)
+	} else {
+		registry.SetLevel(component, parsed)
+	}
+	s.Logger.Info("Log level changed", zap.String("component", component), zap.String("level", parsed.String()))
+	return toDomainLevels(registry.Snapshot()), nil
+}
+
+func (s *SysLoggingUseCase) ResetLevel(component string) (*loggingDomain.Levels, error) {
+	registry, err := s.registry()
+	if err != nil {
+		return nil, err
+	}
+	registry.ResetLevel(component)
+	s.Logger.Info("Log level override removed", zap.String("component", component))
+	return toDomainLevels(registry.Snapshot()), nil
+}
+
+// Reload reads the "log" module of sys_config and applies it on top of the settings the logger
+// started with. Nothing is changed when a value is invalid.
+func (s *SysLoggingUseCase) Reload(ctx context.Context) (*loggingDomain.Levels, error) {
+	registry, err := s.registry()
+	if err != nil {
+		return nil, err
+	}
+	configs, err := s.sysConfigRepository.GetConfigByModule(ctx, loggingDomain.ConfigModule)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(*configs))
+	for _, item := range *configs {
+		values[item.ConfigKey] = item.ConfigValue
+	}
+
+	output, _ := s.Logger.OutputConfig()
+	settings, err := parseSettings(values, output)
+	if err != nil {
+		s.Logger.Warn("Invalid logging configuration", zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	if err := s.Logger.ApplyOutput(settings.output); err != nil {
+		s.Logger.Error("Error applying logging output", zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	if settings.defaultLevel != nil {
+		registry.SetDefault(*settings.defaultLevel)
+	}
+	registry.ReplaceOverrides(settings.overrides)
+
+	s.Logger.Info("Logging configuration reloaded",
+		zap.Bool("stdout", settings.output.Stdout),
+		zap.Bool("file", settings.output.File.Enabled),
+		zap.String("sink", settings.output.Sink))
+	return toDomainLevels(registry.Snapshot()), nil
+}
+
+func (s *SysLoggingUseCase) registry() (*logger.LevelRegistry, error) {
+	registry := s.Logger.Levels()
+	if registry == nil {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return registry, nil
+}
+
+type settings struct {
+	defaultLevel *zapcore.Level
+	overrides    map[string]zapcore.Level
+	output       logger.OutputConfig
+}
+
+// parseSettings overlays the sys_config values on the base output
+func parseSettings(values map[string]string, output logger.OutputConfig) (*settings, error) {
+	result := &settings{overrides: map[string]zapcore.Level{}, output: output}
+
+	if text, ok := values[loggingDomain.KeyLevel]; ok && text != "" {
+		level, err := logger.ParseLevel(text)
+		if err != nil {
+			return nil, err
+		}
+		result.defaultLevel = &level
+	}
+	if text, ok := values[loggingDomain.KeyComponentLevels]; ok && text != "" {
+		var levels map[string]string
+		if err := json.Unmarshal([]byte(text), &levels); err != nil {
+			return nil, fmt.Errorf("%s must be a json object: %w", loggingDomain.KeyComponentLevels, err)
+		}
+		for component, text := range levels {
+			level, err := logger.ParseLevel(text)
+			if err != nil {
+				return nil, err
+			}
+			result.overrides[component] = level
+		}
+	}
+
+	bools := map[string]*bool{
+		loggingDomain.KeyStdout:       &result.output.Stdout,
+		loggingDomain.KeyFileEnabled:  &result.output.File.Enabled,
+		loggingDomain.KeyFileCompress: &result.output.File.Compress,
+	}
+	for key, target := range bools {
+		if text, ok := values[key]; ok && text != "" {
+			value, err := strconv.ParseBool(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a boolean", key)
+			}
+			*target = value
+		}
+	}
+	ints := map[string]*int{
+		loggingDomain.KeyFileMaxSizeMB:  &result.output.File.MaxSizeMB,
+		loggingDomain.KeyFileMaxAgeDays: &result.output.File.MaxAgeDays,
+		loggingDomain.KeyFileMaxBackups: &result.output.File.MaxBackups,
+	}
+	for key, target := range ints {
+		if text, ok := values[key]; ok && text != "" {
+			value, err := strconv.Atoi(text)
+			if err != nil || value < 0 {
+				return nil, fmt.Errorf("%s must be a non negative number", key)
+			}
+			*target = value
+		}
+	}
+	if text, ok := values[loggingDomain.KeyFilePath]; ok && text != "" {
+		result.output.File.Path = text
+	}
+	if text, ok := values[loggingDomain.KeySink]; ok {
+		result.output.Sink = text
+	}
+	return result, nil
+}
+
+func toDomainLevels(snapshot logger.LevelSnapshot) *loggingDomain.Levels {
+	return &loggingDomain.Levels{
+		Default:    snapshot.Default,
+		Overrides:  snapshot.Overrides,
+		Components: snapshot.Components,
+	}
+}
@@ -1,6 +1,7 @@
 package menu
 
 import (
+	"context"
 	"github.com/gbrayhan/microservices-go/src/domain"
 	menuDomain "github.com/gbrayhan/microservices-go/src/domain/sys/menu"
 	menuBtnDomain "github.com/gbrayhan/microservices-go/src/domain/sys/menu_btn"
@@ -15,15 +16,15 @@ import (
 )
 
 type ISysMenuService interface {
-	GetAll(groupId int) ([]*menuDomain.Menu, error)
-	GetByID(id int) (*menuDomain.Menu, error)
-	Create(newMenu *menuDomain.Menu) (*menuDomain.Menu, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*menuDomain.Menu, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[menuDomain.Menu], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*menuDomain.Menu, error)
-	GetUserMenus(roleId int64) ([]*menuDomain.MenuGroup, error)
+	GetAll(ctx context.Context, groupId int) ([]*menuDomain.Menu, error)
+	GetByID(ctx context.Context, id int) (*menuDomain.Menu, error)
+	Create(ctx context.Context, newMenu *menuDomain.Menu) (*menuDomain.Menu, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, actorId int64, userMap map[string]interface{}) (*menuDomain.Menu, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[menuDomain.Menu], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*menuDomain.Menu, error)
+	GetUserMenus(ctx context.Context, roleId int64) ([]*menuDomain.MenuGroup, error)
 }
 
 type SysMenuUseCase struct {
@@ -53,55 +54,55 @@ func NewSysMenuUseCase(
 	}
 }
 
-func (s *SysMenuUseCase) GetAll(groupId int) ([]*menuDomain.Menu, error) {
+func (s *SysMenuUseCase) GetAll(ctx context.Context, groupId int) ([]*menuDomain.Menu, error) {
 	s.Logger.Info("Getting all menus")
-	menus, err := s.sysMenuRepository.GetAll(groupId)
+	menus, err := s.sysMenuRepository.GetAll(ctx, groupId)
 	if err != nil {
 		return nil, err
 	}
 	return buildMenuTree(menus, ""), nil
 }
 
-func (s *SysMenuUseCase) GetByID(id int) (*menuDomain.Menu, error) {
+func (s *SysMenuUseCase) GetByID(ctx context.Context, id int) (*menuDomain.Menu, error) {
 	s.Logger.Info("Getting menu by ID", zap.Int("id", id))
-	return s.sysMenuRepository.GetByID(id)
+	return s.sysMenuRepository.GetByID(ctx, id)
 }
 
-func (s *SysMenuUseCase) Create(newMenu *menuDomain.Menu) (*menuDomain.Menu, error) {
+func (s *SysMenuUseCase) Create(ctx context.Context, newMenu *menuDomain.Menu) (*menuDomain.Menu, error) {
 	s.Logger.Info("Creating new menu", zap.String("path", newMenu.Path))
-	return s.sysMenuRepository.Create(newMenu)
+	return s.sysMenuRepository.Create(ctx, newMenu)
 }
 
-func (s *SysMenuUseCase) Delete(id int) error {
+func (s *SysMenuUseCase) Delete(ctx context.Context, id int) error {
 	s.Logger.Info("Deleting menu", zap.Int("id", id))
-	return s.sysMenuRepository.Delete(id)
+	return s.sysMenuRepository.Delete(ctx, id)
 }
 
-func (s *SysMenuUseCase) Update(id int, userMap map[string]interface{}) (*menuDomain.Menu, error) {
+func (s *SysMenuUseCase) Update(ctx context.Context, id int, actorId int64, userMap map[string]interface{}) (*menuDomain.Menu, error) {
 	s.Logger.Info("Updating menu", zap.Int("id", id))
-	return s.sysMenuRepository.Update(id, userMap)
+	return s.sysMenuRepository.Update(ctx, id, actorId, userMap)
 }
 
-func (s *SysMenuUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[menuDomain.Menu], error) {
+func (s *SysMenuUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[menuDomain.Menu], error) {
 	s.Logger.Info("Searching menus with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysMenuRepository.SearchPaginated(filters)
+	return s.sysMenuRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *SysMenuUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *SysMenuUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching menu by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysMenuRepository.SearchByProperty(property, searchText)
+	return s.sysMenuRepository.SearchByProperty(ctx, property, searchText)
 }
 
-func (s *SysMenuUseCase) GetOneByMap(userMap map[string]interface{}) (*menuDomain.Menu, error) {
-	return s.sysMenuRepository.GetOneByMap(userMap)
+func (s *SysMenuUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*menuDomain.Menu, error) {
+	return s.sysMenuRepository.GetOneByMap(ctx, userMap)
 }
 
 // GetUserMenus
-func (s *SysMenuUseCase) GetUserMenus(roleId int64) ([]*menuDomain.MenuGroup, error) {
+func (s *SysMenuUseCase) GetUserMenus(ctx context.Context, roleId int64) ([]*menuDomain.MenuGroup, error) {
 	s.Logger.Info("Getting user menus", zap.Int64("roleId", roleId))
 	var roleMenuIds []int
 	// role bind menu list
@@ -110,11 +111,11 @@ func (s *SysMenuUseCase) GetUserMenus(roleId int64) ([]*menuDomain.MenuGroup, er
 	if roleId == 0 {
 		roleMenuIds = []int{}
 	} else {
-		roleMenuIds, err = s.sysRoleMenuRepository.GetByRoleId(roleId)
+		roleMenuIds, err = s.sysRoleMenuRepository.GetByRoleId(ctx, roleId)
 		if err != nil {
 			return nil, err
 		}
-		roleBtns, err = s.sysRoleBtnRepository.GetByRoleId(roleId)
+		roleBtns, err = s.sysRoleBtnRepository.GetByRoleId(ctx, roleId)
 		if err != nil {
 			return nil, err
 		}
@@ -130,7 +131,7 @@ func (s *SysMenuUseCase) GetUserMenus(roleId int64) ([]*menuDomain.MenuGroup, er
 	}
 
 	s.Logger.Info("Getting user menus", zap.Int("menusCount", len(roleMenuIds)))
-	groups, err := s.sysMenuGroupRepository.GetByRoleId(roleMenuIds, roleId)
+	groups, err := s.sysMenuGroupRepository.GetByRoleId(ctx, roleMenuIds, roleId)
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,14 @@
 package menu_btn
 
 import (
+	"context"
+	"slices"
+
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/cache"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	menuBtnRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu_btn"
+	menuBtnApiRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/menu_btn_api"
+	roleBtnRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role_btn"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
 	menuBtnDomain "github.com/gbrayhan/microservices-go/src/domain/sys/menu_btn"
@@ -10,67 +16,146 @@ import (
 )
 
 type IMenuBtnService interface {
-	GetAll(menuId int64) (*[]menuBtnDomain.MenuBtn, error)
-	GetByID(id int) (*menuBtnDomain.MenuBtn, error)
-	Create(newMenuBtn *menuBtnDomain.MenuBtn) (*menuBtnDomain.MenuBtn, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*menuBtnDomain.MenuBtn, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[menuBtnDomain.MenuBtn], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*menuBtnDomain.MenuBtn, error)
+	GetAll(ctx context.Context, menuId int64) (*[]menuBtnDomain.MenuBtn, error)
+	GetByID(ctx context.Context, id int) (*menuBtnDomain.MenuBtn, error)
+	Create(ctx context.Context, newMenuBtn *menuBtnDomain.MenuBtn) (*menuBtnDomain.MenuBtn, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*menuBtnDomain.MenuBtn, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[menuBtnDomain.MenuBtn], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*menuBtnDomain.MenuBtn, error)
+	GetBtnApiIds(ctx context.Context, id int) ([]int64, error)
+	BindBtnApis(ctx context.Context, id int, updateMap map[string]interface{}) error
+	HasBtnPermission(ctx context.Context, roleId int64, path string, method string) (bool, error)
 }
 
 type MenuBtnUseCase struct {
-	sysMenuBtnRepository menuBtnRepo.MenuBtnRepositoryInterface
-	Logger               *logger.Logger
+	sysMenuBtnRepository    menuBtnRepo.MenuBtnRepositoryInterface
+	sysMenuBtnApiRepository menuBtnApiRepo.ISysMenuBtnApiRepository
+	sysRoleBtnRepository    roleBtnRepo.ISysRoleBtnRepository
+	btnPermissions          *cache.BtnPermissionCache
+	Logger                  *logger.Logger
 }
 
-func NewMenuBtnUseCase(sysMenuBtnRepository menuBtnRepo.MenuBtnRepositoryInterface, loggerInstance *logger.Logger) IMenuBtnService {
+func NewMenuBtnUseCase(
+	sysMenuBtnRepository menuBtnRepo.MenuBtnRepositoryInterface,
+	sysMenuBtnApiRepository menuBtnApiRepo.ISysMenuBtnApiRepository,
+	sysRoleBtnRepository roleBtnRepo.ISysRoleBtnRepository,
+	btnPermissions *cache.BtnPermissionCache,
+	loggerInstance *logger.Logger) IMenuBtnService {
 	return &MenuBtnUseCase{
-		sysMenuBtnRepository: sysMenuBtnRepository,
-		Logger:               loggerInstance,
+		sysMenuBtnRepository:    sysMenuBtnRepository,
+		sysMenuBtnApiRepository: sysMenuBtnApiRepository,
+		sysRoleBtnRepository:    sysRoleBtnRepository,
+		btnPermissions:          btnPermissions,
+		Logger:                  loggerInstance,
 	}
 }
 
-func (s *MenuBtnUseCase) GetAll(menuID int64) (*[]menuBtnDomain.MenuBtn, error) {
+func (s *MenuBtnUseCase) GetAll(ctx context.Context, menuID int64) (*[]menuBtnDomain.MenuBtn, error) {
 	s.Logger.Info("Getting all roles")
-	return s.sysMenuBtnRepository.GetAll(menuID)
+	return s.sysMenuBtnRepository.GetAll(ctx, menuID)
 }
 
-func (s *MenuBtnUseCase) GetByID(id int) (*menuBtnDomain.MenuBtn, error) {
+func (s *MenuBtnUseCase) GetByID(ctx context.Context, id int) (*menuBtnDomain.MenuBtn, error) {
 	s.Logger.Info("Getting menuBtn by ID", zap.Int("id", id))
-	return s.sysMenuBtnRepository.GetByID(id)
+	return s.sysMenuBtnRepository.GetByID(ctx, id)
 }
 
-func (s *MenuBtnUseCase) Create(newMenuBtn *menuBtnDomain.MenuBtn) (*menuBtnDomain.MenuBtn, error) {
+func (s *MenuBtnUseCase) Create(ctx context.Context, newMenuBtn *menuBtnDomain.MenuBtn) (*menuBtnDomain.MenuBtn, error) {
 	s.Logger.Info("Creating new menuBtn", zap.String("Name", newMenuBtn.Name))
-	return s.sysMenuBtnRepository.Create(newMenuBtn)
+	return s.sysMenuBtnRepository.Create(ctx, newMenuBtn)
 }
 
-func (s *MenuBtnUseCase) Delete(id int) error {
+func (s *MenuBtnUseCase) Delete(ctx context.Context, id int) error {
 	s.Logger.Info("Deleting menuBtn", zap.Int("id", id))
-	return s.sysMenuBtnRepository.Delete(id)
+	if err := s.sysMenuBtnRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	err := s.sysMenuBtnApiRepository.DeleteByBtnId(ctx, int64(id))
+	s.btnPermissions.Invalidate()
+	return err
 }
 
-func (s *MenuBtnUseCase) Update(id int, userMap map[string]interface{}) (*menuBtnDomain.MenuBtn, error) {
+func (s *MenuBtnUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*menuBtnDomain.MenuBtn, error) {
 	s.Logger.Info("Updating menuBtn", zap.Int("id", id))
-	return s.sysMenuBtnRepository.Update(id, userMap)
+	return s.sysMenuBtnRepository.Update(ctx, id, userMap)
 }
 
-func (s *MenuBtnUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[menuBtnDomain.MenuBtn], error) {
+func (s *MenuBtnUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[menuBtnDomain.MenuBtn], error) {
 	s.Logger.Info("Searching menuBtn with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysMenuBtnRepository.SearchPaginated(filters)
+	return s.sysMenuBtnRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *MenuBtnUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *MenuBtnUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching menuBtn by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysMenuBtnRepository.SearchByProperty(property, searchText)
+	return s.sysMenuBtnRepository.SearchByProperty(ctx, property, searchText)
+}
+
+func (s *MenuBtnUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*menuBtnDomain.MenuBtn, error) {
+	return s.sysMenuBtnRepository.GetOneByMap(ctx, userMap)
 }
 
-func (s *MenuBtnUseCase) GetOneByMap(userMap map[string]interface{}) (*menuBtnDomain.MenuBtn, error) {
-	return s.sysMenuBtnRepository.GetOneByMap(userMap)
+// GetBtnApiIds returns the ids of the apis guarded by the button
+func (s *MenuBtnUseCase) GetBtnApiIds(ctx context.Context, id int) ([]int64, error) {
+	s.Logger.Info("Getting menuBtn apis", zap.Int("id", id))
+	if _, err := s.sysMenuBtnRepository.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.sysMenuBtnApiRepository.GetByBtnId(ctx, int64(id))
+}
+
+// BindBtnApis replaces the apis guarded by the button
+func (s *MenuBtnUseCase) BindBtnApis(ctx context.Context, id int, updateMap map[string]interface{}) error {
+	s.Logger.Info("Binding menuBtn apis", zap.Int("id", id))
+	if _, err := s.sysMenuBtnRepository.GetByID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.sysMenuBtnApiRepository.Insert(ctx, int64(id), updateMap); err != nil {
+		return err
+	}
+	s.btnPermissions.Invalidate()
+	return nil
+}
+
+// HasBtnPermission reports whether the role owns one of the buttons guarding the api.
+// Apis that are not bound to any button are always allowed. The bindings are cached.
+func (s *MenuBtnUseCase) HasBtnPermission(ctx context.Context, roleId int64, path string, method string) (bool, error) {
+	btnIds, err := s.btnPermissions.ApiBtns(path, method, func() ([]int64, error) {
+		return s.sysMenuBtnApiRepository.GetBtnIdsByApi(ctx, path, method)
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(btnIds) == 0 {
+		return true, nil
+	}
+	roleBtnIds, err := s.btnPermissions.RoleBtns(roleId, func() ([]int64, error) {
+		roleBtns, err := s.sysRoleBtnRepository.GetByRoleId(ctx, roleId)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int64, 0, len(roleBtns))
+		for _, roleBtn := range roleBtns {
+			ids = append(ids, roleBtn.SysBaseMenuBtnID)
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, roleBtnId := range roleBtnIds {
+		if slices.Contains(btnIds, roleBtnId) {
+			return true, nil
+		}
+	}
+	s.Logger.Warn("Role lacks button permission",
+		zap.Int64("roleId", roleId),
+		zap.String("path", path),
+		zap.String("method", method))
+	return false, nil
 }
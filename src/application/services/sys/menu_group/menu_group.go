@@ -1,6 +1,7 @@
 package menu_group
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -11,14 +12,14 @@ import (
 )
 
 type ISysMenuGroupService interface {
-	GetAll() (*[]menuGroupDomain.MenuGroup, error)
-	GetByID(id int) (*menuGroupDomain.MenuGroup, error)
-	Create(newMenuGroup *menuGroupDomain.MenuGroup) (*menuGroupDomain.MenuGroup, error)
-	Delete(ids []int) error
-	Update(id int, userMap map[string]interface{}) (*menuGroupDomain.MenuGroup, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[menuGroupDomain.MenuGroup], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*menuGroupDomain.MenuGroup, error)
+	GetAll(ctx context.Context) (*[]menuGroupDomain.MenuGroup, error)
+	GetByID(ctx context.Context, id int) (*menuGroupDomain.MenuGroup, error)
+	Create(ctx context.Context, newMenuGroup *menuGroupDomain.MenuGroup) (*menuGroupDomain.MenuGroup, error)
+	Delete(ctx context.Context, ids []int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*menuGroupDomain.MenuGroup, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[menuGroupDomain.MenuGroup], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*menuGroupDomain.MenuGroup, error)
 }
 
 type SysMenuGroupUseCase struct {
@@ -33,46 +34,46 @@ func NewSysMenuGroupUseCase(sysMenuGroupRepository menuGroupRepo.MenuGroupReposi
 	}
 }
 
-func (s *SysMenuGroupUseCase) GetAll() (*[]menuGroupDomain.MenuGroup, error) {
+func (s *SysMenuGroupUseCase) GetAll(ctx context.Context) (*[]menuGroupDomain.MenuGroup, error) {
 	s.Logger.Info("Getting all roles")
-	return s.sysMenuGroupRepository.GetAll()
+	return s.sysMenuGroupRepository.GetAll(ctx)
 }
 
-func (s *SysMenuGroupUseCase) GetByID(id int) (*menuGroupDomain.MenuGroup, error) {
+func (s *SysMenuGroupUseCase) GetByID(ctx context.Context, id int) (*menuGroupDomain.MenuGroup, error) {
 	s.Logger.Info("Getting menuGroup by ID", zap.Int("id", id))
-	return s.sysMenuGroupRepository.GetByID(id)
+	return s.sysMenuGroupRepository.GetByID(ctx, id)
 }
 
-func (s *SysMenuGroupUseCase) Create(newMenuGroup *menuGroupDomain.MenuGroup) (*menuGroupDomain.MenuGroup, error) {
+func (s *SysMenuGroupUseCase) Create(ctx context.Context, newMenuGroup *menuGroupDomain.MenuGroup) (*menuGroupDomain.MenuGroup, error) {
 	s.Logger.Info("Creating new menuGroup", zap.String("Name", newMenuGroup.Name))
-	return s.sysMenuGroupRepository.Create(newMenuGroup)
+	return s.sysMenuGroupRepository.Create(ctx, newMenuGroup)
 }
 
-func (s *SysMenuGroupUseCase) Delete(ids []int) error {
+func (s *SysMenuGroupUseCase) Delete(ctx context.Context, ids []int) error {
 	s.Logger.Info("Deleting menuGroup", zap.String("ids", fmt.Sprintf("%v", ids)))
-	return s.sysMenuGroupRepository.Delete(ids)
+	return s.sysMenuGroupRepository.Delete(ctx, ids)
 }
 
-func (s *SysMenuGroupUseCase) Update(id int, userMap map[string]interface{}) (*menuGroupDomain.MenuGroup, error) {
+func (s *SysMenuGroupUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*menuGroupDomain.MenuGroup, error) {
 	s.Logger.Info("Updating menuGroup", zap.Int("id", id))
-	return s.sysMenuGroupRepository.Update(id, userMap)
+	return s.sysMenuGroupRepository.Update(ctx, id, userMap)
 }
 
-func (s *SysMenuGroupUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[menuGroupDomain.MenuGroup], error) {
+func (s *SysMenuGroupUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[menuGroupDomain.MenuGroup], error) {
 	s.Logger.Info("Searching menuGroups with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysMenuGroupRepository.SearchPaginated(filters)
+	return s.sysMenuGroupRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *SysMenuGroupUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *SysMenuGroupUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching menuGroup by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysMenuGroupRepository.SearchByProperty(property, searchText)
+	return s.sysMenuGroupRepository.SearchByProperty(ctx, property, searchText)
 }
 
 // Get one menuGroup by map
-func (s *SysMenuGroupUseCase) GetOneByMap(userMap map[string]interface{}) (*menuGroupDomain.MenuGroup, error) {
-	return s.sysMenuGroupRepository.GetOneByMap(userMap)
+func (s *SysMenuGroupUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*menuGroupDomain.MenuGroup, error) {
+	return s.sysMenuGroupRepository.GetOneByMap(ctx, userMap)
 }
@@ -1,6 +1,7 @@
 package menu_parameter
 
 import (
+	"context"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	menuParameterRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu_parameter"
 
@@ -10,14 +11,14 @@ import (
 )
 
 type IMenuParameterService interface {
-	GetAll(menuID int64) (*[]menuParameterDomain.MenuParameter, error)
-	GetByID(id int) (*menuParameterDomain.MenuParameter, error)
-	Create(newMenuParameter *menuParameterDomain.MenuParameter) (*menuParameterDomain.MenuParameter, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*menuParameterDomain.MenuParameter, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[menuParameterDomain.MenuParameter], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*menuParameterDomain.MenuParameter, error)
+	GetAll(ctx context.Context, menuID int64) (*[]menuParameterDomain.MenuParameter, error)
+	GetByID(ctx context.Context, id int) (*menuParameterDomain.MenuParameter, error)
+	Create(ctx context.Context, newMenuParameter *menuParameterDomain.MenuParameter) (*menuParameterDomain.MenuParameter, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*menuParameterDomain.MenuParameter, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[menuParameterDomain.MenuParameter], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*menuParameterDomain.MenuParameter, error)
 }
 
 type MenuParameterUseCase struct {
@@ -32,45 +33,45 @@ func NewMenuParameterUseCase(menuParameterRepository menuParameterRepo.MenuParam
 	}
 }
 
-func (s *MenuParameterUseCase) GetAll(menuID int64) (*[]menuParameterDomain.MenuParameter, error) {
+func (s *MenuParameterUseCase) GetAll(ctx context.Context, menuID int64) (*[]menuParameterDomain.MenuParameter, error) {
 	s.Logger.Info("Getting all roles")
-	return s.menuParameterRepository.GetAll(menuID)
+	return s.menuParameterRepository.GetAll(ctx, menuID)
 }
 
-func (s *MenuParameterUseCase) GetByID(id int) (*menuParameterDomain.MenuParameter, error) {
+func (s *MenuParameterUseCase) GetByID(ctx context.Context, id int) (*menuParameterDomain.MenuParameter, error) {
 	s.Logger.Info("Getting menuParameter by ID", zap.Int("id", id))
-	return s.menuParameterRepository.GetByID(id)
+	return s.menuParameterRepository.GetByID(ctx, id)
 }
 
-func (s *MenuParameterUseCase) Create(newMenuParameter *menuParameterDomain.MenuParameter) (*menuParameterDomain.MenuParameter, error) {
+func (s *MenuParameterUseCase) Create(ctx context.Context, newMenuParameter *menuParameterDomain.MenuParameter) (*menuParameterDomain.MenuParameter, error) {
 	s.Logger.Info("Creating new menuParameter", zap.String("Key", newMenuParameter.Key))
-	return s.menuParameterRepository.Create(newMenuParameter)
+	return s.menuParameterRepository.Create(ctx, newMenuParameter)
 }
 
-func (s *MenuParameterUseCase) Delete(id int) error {
+func (s *MenuParameterUseCase) Delete(ctx context.Context, id int) error {
 	s.Logger.Info("Deleting menuParameter", zap.Int("id", id))
-	return s.menuParameterRepository.Delete(id)
+	return s.menuParameterRepository.Delete(ctx, id)
 }
 
-func (s *MenuParameterUseCase) Update(id int, userMap map[string]interface{}) (*menuParameterDomain.MenuParameter, error) {
+func (s *MenuParameterUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*menuParameterDomain.MenuParameter, error) {
 	s.Logger.Info("Updating menuParameter", zap.Int("id", id))
-	return s.menuParameterRepository.Update(id, userMap)
+	return s.menuParameterRepository.Update(ctx, id, userMap)
 }
 
-func (s *MenuParameterUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[menuParameterDomain.MenuParameter], error) {
+func (s *MenuParameterUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[menuParameterDomain.MenuParameter], error) {
 	s.Logger.Info("Searching menuParameter with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.menuParameterRepository.SearchPaginated(filters)
+	return s.menuParameterRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *MenuParameterUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *MenuParameterUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching menuParameter by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.menuParameterRepository.SearchByProperty(property, searchText)
+	return s.menuParameterRepository.SearchByProperty(ctx, property, searchText)
 }
 
-func (s *MenuParameterUseCase) GetOneByMap(userMap map[string]interface{}) (*menuParameterDomain.MenuParameter, error) {
-	return s.menuParameterRepository.GetOneByMap(userMap)
+func (s *MenuParameterUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*menuParameterDomain.MenuParameter, error) {
+	return s.menuParameterRepository.GetOneByMap(ctx, userMap)
 }
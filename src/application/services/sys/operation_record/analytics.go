@@ -0,0 +1,136 @@
+package operation_record
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	operationDomain "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	operationRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const analyticsCachePrefix = "operation:analytics"
+
+type ISysOperationAnalyticsService interface {
+	LatencyPercentiles(ctx context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.RouteLatency, error)
+	ErrorRates(ctx context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.ErrorRateBucket, error)
+	TopUsers(ctx context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.UserActivity, error)
+	SlowestEndpoints(ctx context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.SlowEndpoint, error)
+}
+
+// AnalyticsCache stores the computed aggregates, Get returns redis.Nil for a missing key
+type AnalyticsCache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, data []byte, ttl time.Duration) error
+}
+
+type redisAnalyticsCache struct {
+	redisClient *redis.Client
+}
+
+func (c *redisAnalyticsCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return c.redisClient.Get(ctx, key).Bytes()
+}
+
+func (c *redisAnalyticsCache) Set(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	return c.redisClient.Set(ctx, key, data, ttl).Err()
+}
+
+// SysOperationAnalyticsUseCase caches the aggregates for a short while, they scan the whole
+// period and dashboards tend to poll them
+type SysOperationAnalyticsUseCase struct {
+	analyticsRepository operationRepo.OperationAnalyticsRepositoryInterface
+	// cache is nil without redis
+	cache    AnalyticsCache
+	cacheTTL time.Duration
+	Logger   *logger.Logger
+}
+
+func NewSysOperationAnalyticsUseCase(
+	analyticsRepository operationRepo.OperationAnalyticsRepositoryInterface,
+	redisClient *redis.Client,
+	loggerInstance *logger.Logger) ISysOperationAnalyticsService {
+	cacheTTL := 60 * time.Second
+	if seconds := sharedUtil.GetEnvAsInt("OPERATION_ANALYTICS_CACHE_TTL_SECONDS", 60); seconds >= 0 {
+		cacheTTL = time.Duration(seconds) * time.Second
+	}
+	useCase := &SysOperationAnalyticsUseCase{
+		analyticsRepository: analyticsRepository,
+		cacheTTL:            cacheTTL,
+		Logger:              loggerInstance,
+	}
+	if redisClient != nil {
+		useCase.cache = &redisAnalyticsCache{redisClient: redisClient}
+	}
+	return useCase
+}
+
+func (s *SysOperationAnalyticsUseCase) LatencyPercentiles(ctx context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.RouteLatency, error) {
+	s.Logger.Info("Computing operation latency percentiles", zap.Time("start", query.Start), zap.Time("end", query.End))
+	return cached(ctx, s, "latency", query, s.analyticsRepository.LatencyPercentiles)
+}
+
+func (s *SysOperationAnalyticsUseCase) ErrorRates(ctx context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.ErrorRateBucket, error) {
+	s.Logger.Info("Computing operation error rates", zap.String("bucket", query.Bucket))
+	if !operationDomain.IsValidBucket(query.Bucket) {
+		return nil, domainErrors.NewAppError(fmt.Errorf("bucket must be minute, hour or day"), domainErrors.ValidationError)
+	}
+	return cached(ctx, s, "error_rate", query, s.analyticsRepository.ErrorRates)
+}
+
+func (s *SysOperationAnalyticsUseCase) TopUsers(ctx context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.UserActivity, error) {
+	s.Logger.Info("Computing top operation users", zap.Int("limit", query.Limit))
+	return cached(ctx, s, "top_users", query, s.analyticsRepository.TopUsers)
+}
+
+func (s *SysOperationAnalyticsUseCase) SlowestEndpoints(ctx context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.SlowEndpoint, error) {
+	s.Logger.Info("Computing slowest endpoints", zap.Int("limit", query.Limit))
+	return cached(ctx, s, "slowest", query, s.analyticsRepository.SlowestEndpoints)
+}
+
+// cacheKey identifies an aggregate by kind and parameters, times are truncated to the second
+// so that repeated requests for the same window share an entry
+func cacheKey(kind string, query operationDomain.AnalyticsQuery) string {
+	return fmt.Sprintf("%s:%s:%d:%d:%d:%s", analyticsCachePrefix, kind,
+		query.Start.Unix(), query.End.Unix(), query.Limit, query.Bucket)
+}
+
+// cached validates the query and returns the cached aggregate if any, otherwise computes and
+// stores it. Redis failures are logged and the aggregate is computed directly.
+func cached[T any](ctx context.Context, s *SysOperationAnalyticsUseCase, kind string, query operationDomain.AnalyticsQuery,
+	compute func(context.Context, operationDomain.AnalyticsQuery) (*[]T, error)) (*[]T, error) {
+	if err := query.Validate(); err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	if s.cache == nil || s.cacheTTL == 0 {
+		return compute(ctx, query)
+	}
+	key := cacheKey(kind, query)
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		var rows []T
+		if err := json.Unmarshal(data, &rows); err == nil {
+			return &rows, nil
+		}
+	} else if err != redis.Nil {
+		s.Logger.Warn("Error reading operation analytics cache", zap.String("key", key), zap.Error(err))
+	}
+
+	rows, err := compute(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(rows)
+	if err == nil {
+		err = s.cache.Set(ctx, key, data, s.cacheTTL)
+	}
+	if err != nil {
+		s.Logger.Warn("Error caching operation analytics", zap.String("key", key), zap.Error(err))
+	}
+	return rows, nil
+}
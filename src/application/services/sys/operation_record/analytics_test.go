@@ -0,0 +1,175 @@
+package operation_record
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	operationDomain "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubAnalyticsRepository struct {
+	queries []operationDomain.AnalyticsQuery
+}
+
+func (r *stubAnalyticsRepository) LatencyPercentiles(_ context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.RouteLatency, error) {
+	r.queries = append(r.queries, query)
+	return &[]operationDomain.RouteLatency{{Method: "GET", Route: "/v1/user/:id", Count: 3, P50: 10, P95: 40, P99: 90}}, nil
+}
+
+func (r *stubAnalyticsRepository) ErrorRates(_ context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.ErrorRateBucket, error) {
+	r.queries = append(r.queries, query)
+	return &[]operationDomain.ErrorRateBucket{{Bucket: query.Start, Total: 4, ServerErrors: 1, ErrorRate: 0.25}}, nil
+}
+
+func (r *stubAnalyticsRepository) TopUsers(_ context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.UserActivity, error) {
+	r.queries = append(r.queries, query)
+	return &[]operationDomain.UserActivity{}, nil
+}
+
+func (r *stubAnalyticsRepository) SlowestEndpoints(_ context.Context, query operationDomain.AnalyticsQuery) (*[]operationDomain.SlowEndpoint, error) {
+	r.queries = append(r.queries, query)
+	return &[]operationDomain.SlowEndpoint{}, nil
+}
+
+type memoryAnalyticsCache struct {
+	entries map[string][]byte
+	ttls    map[string]time.Duration
+	err     error
+}
+
+func (c *memoryAnalyticsCache) Get(_ context.Context, key string) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	data, ok := c.entries[key]
+	if !ok {
+		return nil, redis.Nil
+	}
+	return data, nil
+}
+
+func (c *memoryAnalyticsCache) Set(_ context.Context, key string, data []byte, ttl time.Duration) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.entries[key] = data
+	c.ttls[key] = ttl
+	return nil
+}
+
+func newTestAnalyticsUseCase() (*SysOperationAnalyticsUseCase, *stubAnalyticsRepository, *memoryAnalyticsCache) {
+	repository := &stubAnalyticsRepository{}
+	cache := &memoryAnalyticsCache{entries: map[string][]byte{}, ttls: map[string]time.Duration{}}
+	return &SysOperationAnalyticsUseCase{
+		analyticsRepository: repository,
+		cache:               cache,
+		cacheTTL:            time.Minute,
+		Logger:              &logger.Logger{Log: zap.NewNop()},
+	}, repository, cache
+}
+
+func testAnalyticsQuery() operationDomain.AnalyticsQuery {
+	end := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return operationDomain.AnalyticsQuery{Start: end.Add(-24 * time.Hour), End: end, Limit: 20}
+}
+
+func assertValidationError(t *testing.T, err error) {
+	t.Helper()
+	var appErr *domainErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, domainErrors.ValidationError, appErr.Type)
+}
+
+func TestAnalyticsCache(t *testing.T) {
+	useCase, repository, cache := newTestAnalyticsUseCase()
+	query := testAnalyticsQuery()
+
+	first, err := useCase.LatencyPercentiles(context.Background(), query)
+	require.NoError(t, err)
+	second, err := useCase.LatencyPercentiles(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Len(t, repository.queries, 1, "the second request is served from the cache")
+
+	key := "operation:analytics:latency:1772280000:1772366400:20:"
+	assert.Equal(t, key, cacheKey("latency", query))
+	assert.Contains(t, cache.entries, key)
+	assert.Equal(t, time.Minute, cache.ttls[key])
+
+	// another kind, limit or window is another entry
+	_, err = useCase.SlowestEndpoints(context.Background(), query)
+	require.NoError(t, err)
+	query.Limit = 5
+	_, err = useCase.LatencyPercentiles(context.Background(), query)
+	require.NoError(t, err)
+	assert.Len(t, repository.queries, 3)
+	assert.Len(t, cache.entries, 3)
+}
+
+func TestAnalyticsCacheFailures(t *testing.T) {
+	useCase, repository, cache := newTestAnalyticsUseCase()
+	cache.err = errors.New("connection refused")
+	rows, err := useCase.LatencyPercentiles(context.Background(), testAnalyticsQuery())
+	require.NoError(t, err, "redis failures fall back to the database")
+	assert.Len(t, *rows, 1)
+
+	useCase.cacheTTL = 0
+	cache.err = nil
+	_, err = useCase.LatencyPercentiles(context.Background(), testAnalyticsQuery())
+	require.NoError(t, err)
+	assert.Empty(t, cache.entries, "a zero ttl disables the cache")
+	assert.Len(t, repository.queries, 2)
+}
+
+func TestAnalyticsCacheTTL(t *testing.T) {
+	t.Setenv("OPERATION_ANALYTICS_CACHE_TTL_SECONDS", "15")
+	useCase := NewSysOperationAnalyticsUseCase(&stubAnalyticsRepository{}, nil, &logger.Logger{Log: zap.NewNop()})
+	assert.Equal(t, 15*time.Second, useCase.(*SysOperationAnalyticsUseCase).cacheTTL)
+	assert.Nil(t, useCase.(*SysOperationAnalyticsUseCase).cache, "no cache without redis")
+
+	t.Setenv("OPERATION_ANALYTICS_CACHE_TTL_SECONDS", "-1")
+	useCase = NewSysOperationAnalyticsUseCase(&stubAnalyticsRepository{}, nil, &logger.Logger{Log: zap.NewNop()})
+	assert.Equal(t, time.Minute, useCase.(*SysOperationAnalyticsUseCase).cacheTTL)
+}
+
+func TestAnalyticsRangeValidation(t *testing.T) {
+	useCase, repository, _ := newTestAnalyticsUseCase()
+
+	query := testAnalyticsQuery()
+	query.Start = query.End
+	_, err := useCase.LatencyPercentiles(context.Background(), query)
+	assertValidationError(t, err)
+
+	query = testAnalyticsQuery()
+	query.Start = query.End.Add(time.Hour)
+	_, err = useCase.TopUsers(context.Background(), query)
+	assertValidationError(t, err)
+
+	for _, limit := range []int{0, operationDomain.MaxAnalyticsLimit + 1} {
+		query = testAnalyticsQuery()
+		query.Limit = limit
+		_, err = useCase.SlowestEndpoints(context.Background(), query)
+		assertValidationError(t, err)
+	}
+
+	query = testAnalyticsQuery()
+	_, err = useCase.ErrorRates(context.Background(), query)
+	assertValidationError(t, err)
+	query.Bucket = "week"
+	_, err = useCase.ErrorRates(context.Background(), query)
+	assertValidationError(t, err)
+	assert.Empty(t, repository.queries, "invalid queries never reach the database")
+
+	query.Bucket = operationDomain.BucketDay
+	rows, err := useCase.ErrorRates(context.Background(), query)
+	require.NoError(t, err)
+	assert.Len(t, *rows, 1)
+}
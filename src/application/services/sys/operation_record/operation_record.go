@@ -1,7 +1,9 @@
 package operation_record
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	operationRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
@@ -12,14 +14,15 @@ import (
 )
 
 type ISysOperationService interface {
-	GetAll() (*[]operationDomain.SysOperationRecord, error)
-	GetByID(id int) (*operationDomain.SysOperationRecord, error)
-	Create(newOperation *operationDomain.SysOperationRecord) (*operationDomain.SysOperationRecord, error)
-	Delete(ids []int) error
-	Update(id int, userMap map[string]interface{}) (*operationDomain.SysOperationRecord, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[operationDomain.SysOperationRecord], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*operationDomain.SysOperationRecord, error)
+	GetAll(ctx context.Context) (*[]operationDomain.SysOperationRecord, error)
+	GetByID(ctx context.Context, id int) (*operationDomain.SysOperationRecord, error)
+	Create(ctx context.Context, newOperation *operationDomain.SysOperationRecord) (*operationDomain.SysOperationRecord, error)
+	Delete(ctx context.Context, ids []int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*operationDomain.SysOperationRecord, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[operationDomain.SysOperationRecord], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*operationDomain.SysOperationRecord, error)
+	Export(ctx context.Context, start, end time.Time, fn func(record *operationDomain.SysOperationRecord) error) error
 }
 
 type SysOperationUseCase struct {
@@ -34,45 +37,51 @@ func NewSysOperationUseCase(sysOperationRepository operationRepo.OperationReposi
 	}
 }
 
-func (s *SysOperationUseCase) GetAll() (*[]operationDomain.SysOperationRecord, error) {
+func (s *SysOperationUseCase) GetAll(ctx context.Context) (*[]operationDomain.SysOperationRecord, error) {
 	s.Logger.Info("Getting all roles")
-	return s.sysOperationRepository.GetAll()
+	return s.sysOperationRepository.GetAll(ctx)
 }
 
-func (s *SysOperationUseCase) GetByID(id int) (*operationDomain.SysOperationRecord, error) {
+func (s *SysOperationUseCase) GetByID(ctx context.Context, id int) (*operationDomain.SysOperationRecord, error) {
 	s.Logger.Info("Getting operation by ID", zap.Int("id", id))
-	return s.sysOperationRepository.GetByID(id)
+	return s.sysOperationRepository.GetByID(ctx, id)
 }
 
-func (s *SysOperationUseCase) Create(newOperation *operationDomain.SysOperationRecord) (*operationDomain.SysOperationRecord, error) {
+func (s *SysOperationUseCase) Create(ctx context.Context, newOperation *operationDomain.SysOperationRecord) (*operationDomain.SysOperationRecord, error) {
 	s.Logger.Info("Creating new operation", zap.String("path", newOperation.Path))
-	return s.sysOperationRepository.Create(newOperation)
+	return s.sysOperationRepository.Create(ctx, newOperation)
 }
 
-func (s *SysOperationUseCase) Delete(ids []int) error {
+func (s *SysOperationUseCase) Delete(ctx context.Context, ids []int) error {
 	s.Logger.Info("Deleting operation", zap.String("ids", fmt.Sprintf("%v", ids)))
-	return s.sysOperationRepository.Delete(ids)
+	return s.sysOperationRepository.Delete(ctx, ids)
 }
 
-func (s *SysOperationUseCase) Update(id int, userMap map[string]interface{}) (*operationDomain.SysOperationRecord, error) {
+func (s *SysOperationUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*operationDomain.SysOperationRecord, error) {
 	s.Logger.Info("Updating operation", zap.Int("id", id))
-	return s.sysOperationRepository.Update(id, userMap)
+	return s.sysOperationRepository.Update(ctx, id, userMap)
 }
 
-func (s *SysOperationUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[operationDomain.SysOperationRecord], error) {
+func (s *SysOperationUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[operationDomain.SysOperationRecord], error) {
 	s.Logger.Info("Searching operations with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysOperationRepository.SearchPaginated(filters)
+	return s.sysOperationRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *SysOperationUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *SysOperationUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching operation by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysOperationRepository.SearchByProperty(property, searchText)
+	return s.sysOperationRepository.SearchByProperty(ctx, property, searchText)
 }
 
-func (s *SysOperationUseCase) GetOneByMap(userMap map[string]interface{}) (*operationDomain.SysOperationRecord, error) {
-	return s.sysOperationRepository.GetOneByMap(userMap)
+func (s *SysOperationUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*operationDomain.SysOperationRecord, error) {
+	return s.sysOperationRepository.GetOneByMap(ctx, userMap)
+}
+
+// Export streams the records created in [start, end) to fn in id order
+func (s *SysOperationUseCase) Export(ctx context.Context, start, end time.Time, fn func(record *operationDomain.SysOperationRecord) error) error {
+	s.Logger.Info("Exporting operations", zap.Time("start", start), zap.Time("end", end))
+	return s.sysOperationRepository.StreamByDateRange(ctx, start, end, fn)
 }
@@ -0,0 +1,83 @@
+package rbac
+
+import (
+	"context"
+	"github.com/casbin/casbin/v2"
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	rbacDomain "github.com/gbrayhan/microservices-go/src/domain/sys/rbac"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/cache"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	rbacRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/rbac"
+	"go.uber.org/zap"
+)
+
+type ISysRbacService interface {
+	Export(ctx context.Context) (*rbacDomain.Document, error)
+	Import(ctx context.Context, doc *rbacDomain.Document, options rbacDomain.ImportOptions) (*rbacDomain.ImportResult, error)
+}
+
+type SysRbacUseCase struct {
+	rbacRepository rbacRepo.IRbacRepository
+	enforcer       *casbin.Enforcer
+	btnPermissions *cache.BtnPermissionCache
+	Logger         *logger.Logger
+}
+
+func NewSysRbacUseCase(
+	rbacRepository rbacRepo.IRbacRepository,
+	enforcer *casbin.Enforcer,
+	btnPermissions *cache.BtnPermissionCache,
+	loggerInstance *logger.Logger) ISysRbacService {
+	return &SysRbacUseCase{
+		rbacRepository: rbacRepository,
+		enforcer:       enforcer,
+		btnPermissions: btnPermissions,
+		Logger:         loggerInstance,
+	}
+}
+
+func (s *SysRbacUseCase) Export(ctx context.Context) (*rbacDomain.Document, error) {
+	s.Logger.Info("Exporting rbac configuration")
+	doc, err := s.rbacRepository.Export(ctx)
+	if err != nil {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.RepositoryError)
+	}
+	return doc, nil
+}
+
+// Import previews the changes the document would make and applies them unless running dry
+func (s *SysRbacUseCase) Import(ctx context.Context, doc *rbacDomain.Document, options rbacDomain.ImportOptions) (*rbacDomain.ImportResult, error) {
+	s.Logger.Info("Importing rbac configuration",
+		zap.Bool("dryRun", options.DryRun),
+		zap.Bool("prune", options.Prune))
+	if err := doc.Validate(); err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+
+	current, err := s.rbacRepository.Export(ctx)
+	if err != nil {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.RepositoryError)
+	}
+	changes := rbacDomain.Diff(current, doc, options.Prune)
+	result := &rbacDomain.ImportResult{
+		DryRun:  options.DryRun,
+		Changes: changes,
+		Summary: rbacDomain.Summarize(changes),
+	}
+	if options.DryRun || len(changes) == 0 {
+		return result, nil
+	}
+
+	if err := s.rbacRepository.Apply(ctx, doc, options.Prune); err != nil {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.RepositoryError)
+	}
+	s.btnPermissions.Invalidate()
+	// casbin keeps policies in memory, reload them after the rules table changed
+	if err := s.enforcer.LoadPolicy(); err != nil {
+		s.Logger.Error("Error reloading casbin policy", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	result.Applied = true
+	s.Logger.Info("Rbac configuration imported", zap.Int("changes", len(changes)))
+	return result, nil
+}
@@ -1,8 +1,10 @@
 package role
 
 import (
+	"context"
 	"strconv"
 
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/cache"
 	menuRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -16,23 +18,23 @@ import (
 )
 
 type ISysRoleService interface {
-	GetAll(status int) ([]*roleDomain.RoleTree, error)
-	GetByID(id int) (*roleDomain.Role, error)
-	GetByName(name string) (*roleDomain.Role, error)
-	Create(newRole *roleDomain.Role) (*roleDomain.Role, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*roleDomain.Role, error)
-	SearchPaginated(filters domain.DataFilters) (*roleDomain.SearchResultRole, error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*roleDomain.Role, error)
-	GetTreeRoles(status int) (*roleDomain.RoleNode, error)
+	GetAll(ctx context.Context, status int) ([]*roleDomain.RoleTree, error)
+	GetByID(ctx context.Context, id int) (*roleDomain.Role, error)
+	GetByName(ctx context.Context, name string) (*roleDomain.Role, error)
+	Create(ctx context.Context, newRole *roleDomain.Role) (*roleDomain.Role, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, actorId int64, userMap map[string]interface{}) (*roleDomain.Role, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*roleDomain.SearchResultRole, error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*roleDomain.Role, error)
+	GetTreeRoles(ctx context.Context, status int) (*roleDomain.RoleNode, error)
 
-	GetRoleMenuIds(id int64) (map[int][]int, map[int64][]int64, error)
-	UpdateRoleMenuIds(id int, updateMap map[string]any) error
+	GetRoleMenuIds(ctx context.Context, id int64) (map[int][]int, map[int64][]int64, error)
+	UpdateRoleMenuIds(ctx context.Context, id int, updateMap map[string]any) error
 
-	GetApiRuleList(roleId int) ([]string, error)
-	BindApiRule(roleId int, updateMap map[string]interface{}) error
-	BindRoleMenuBtns(roleId int64, updateMap map[string]interface{}) error
+	GetApiRuleList(ctx context.Context, roleId int) ([]string, error)
+	BindApiRule(ctx context.Context, roleId int, updateMap map[string]interface{}) error
+	BindRoleMenuBtns(ctx context.Context, roleId int64, updateMap map[string]interface{}) error
 }
 
 type SysRoleUseCase struct {
@@ -41,6 +43,7 @@ type SysRoleUseCase struct {
 	sysMenuRepository     menuRepo.MenuRepositoryInterface
 	casbinRuleRepo        casbinRepo.ICasbinRuleRepository
 	sysRoleBtnRepo        roleBtnRepo.ISysRoleBtnRepository
+	btnPermissions        *cache.BtnPermissionCache
 
 	Logger *logger.Logger
 }
@@ -51,21 +54,23 @@ func NewSysRoleUseCase(
 	casbinRuleRepo casbinRepo.ICasbinRuleRepository,
 	sysMenuRepository menuRepo.MenuRepositoryInterface,
 	sysRoleBtnRepo roleBtnRepo.ISysRoleBtnRepository,
+	btnPermissions *cache.BtnPermissionCache,
 	loggerInstance *logger.Logger) ISysRoleService {
 	return &SysRoleUseCase{
 		sysRoleRepository:     sysRoleRepository,
 		sysRoleMenuRepository: sysRoleMenuRepository,
 		sysMenuRepository:     sysMenuRepository,
 		sysRoleBtnRepo:        sysRoleBtnRepo,
+		btnPermissions:        btnPermissions,
 		casbinRuleRepo:        casbinRuleRepo,
 		Logger:                loggerInstance,
 	}
 }
 
-func (s *SysRoleUseCase) GetAll(status int) ([]*roleDomain.RoleTree, error) {
+func (s *SysRoleUseCase) GetAll(ctx context.Context, status int) ([]*roleDomain.RoleTree, error) {
 
 	s.Logger.Info("Getting all roles")
-	roles, err := s.sysRoleRepository.GetAll(0)
+	roles, err := s.sysRoleRepository.GetAll(ctx, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -114,52 +119,56 @@ func BuildRoleTree(roles *[]roleDomain.Role) []*roleDomain.RoleTree {
 	return roots
 }
 
-func (s *SysRoleUseCase) GetByID(id int) (*roleDomain.Role, error) {
+func (s *SysRoleUseCase) GetByID(ctx context.Context, id int) (*roleDomain.Role, error) {
 	s.Logger.Info("Getting role by ID", zap.Int("id", id))
-	return s.sysRoleRepository.GetByID(id)
+	return s.sysRoleRepository.GetByID(ctx, id)
 }
 
-func (s *SysRoleUseCase) GetByName(name string) (*roleDomain.Role, error) {
+func (s *SysRoleUseCase) GetByName(ctx context.Context, name string) (*roleDomain.Role, error) {
 	s.Logger.Info("Getting role by name", zap.String("name", name))
-	return s.sysRoleRepository.GetByName(name)
+	return s.sysRoleRepository.GetByName(ctx, name)
 }
 
-func (s *SysRoleUseCase) Create(newRole *roleDomain.Role) (*roleDomain.Role, error) {
+func (s *SysRoleUseCase) Create(ctx context.Context, newRole *roleDomain.Role) (*roleDomain.Role, error) {
 	s.Logger.Info("Creating new role", zap.String("name", newRole.Name))
-	return s.sysRoleRepository.Create(newRole)
+	return s.sysRoleRepository.Create(ctx, newRole)
 }
 
-func (s *SysRoleUseCase) Delete(id int) error {
+func (s *SysRoleUseCase) Delete(ctx context.Context, id int) error {
 	s.Logger.Info("Deleting role", zap.Int("id", id))
-	return s.sysRoleRepository.Delete(id)
+	if err := s.sysRoleRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.btnPermissions.InvalidateRole(int64(id))
+	return nil
 }
 
-func (s *SysRoleUseCase) Update(id int, userMap map[string]interface{}) (*roleDomain.Role, error) {
+func (s *SysRoleUseCase) Update(ctx context.Context, id int, actorId int64, userMap map[string]interface{}) (*roleDomain.Role, error) {
 	s.Logger.Info("Updating role", zap.Int("id", id))
-	return s.sysRoleRepository.Update(id, userMap)
+	return s.sysRoleRepository.Update(ctx, id, actorId, userMap)
 }
 
-func (s *SysRoleUseCase) SearchPaginated(filters domain.DataFilters) (*roleDomain.SearchResultRole, error) {
+func (s *SysRoleUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*roleDomain.SearchResultRole, error) {
 	s.Logger.Info("Searching roles with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.sysRoleRepository.SearchPaginated(filters)
+	return s.sysRoleRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *SysRoleUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *SysRoleUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching role by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.sysRoleRepository.SearchByProperty(property, searchText)
+	return s.sysRoleRepository.SearchByProperty(ctx, property, searchText)
 }
 
-func (s *SysRoleUseCase) GetOneByMap(userMap map[string]interface{}) (*roleDomain.Role, error) {
-	return s.sysRoleRepository.GetOneByMap(userMap)
+func (s *SysRoleUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*roleDomain.Role, error) {
+	return s.sysRoleRepository.GetOneByMap(ctx, userMap)
 }
 
 // GetTreeRoles implements ISysRoleService.
-func (s *SysRoleUseCase) GetTreeRoles(status int) (*roleDomain.RoleNode, error) {
-	roles, err := s.sysRoleRepository.GetAll(status)
+func (s *SysRoleUseCase) GetTreeRoles(ctx context.Context, status int) (*roleDomain.RoleNode, error) {
+	roles, err := s.sysRoleRepository.GetAll(ctx, status)
 	if err != nil {
 		return nil, err
 	}
@@ -200,17 +209,17 @@ func (s *SysRoleUseCase) GetTreeRoles(status int) (*roleDomain.RoleNode, error)
 	}, nil
 }
 
-func (s *SysRoleUseCase) GetRoleMenuIds(id int64) (map[int][]int, map[int64][]int64, error) {
-	menuIds, err := s.sysRoleMenuRepository.GetByRoleId(id)
+func (s *SysRoleUseCase) GetRoleMenuIds(ctx context.Context, id int64) (map[int][]int, map[int64][]int64, error) {
+	menuIds, err := s.sysRoleMenuRepository.GetByRoleId(ctx, id)
 	if err != nil {
 		return nil, nil, err
 	}
-	menus, err := s.sysMenuRepository.GetByIDs(menuIds)
+	menus, err := s.sysMenuRepository.GetByIDs(ctx, menuIds)
 	menuGroups := make(map[int][]int, 0)
 	for _, v := range *menus {
 		menuGroups[v.MenuGroupId] = append(menuGroups[v.MenuGroupId], v.ID)
 	}
-	menuBtns, err := s.sysRoleBtnRepo.GetByRoleId(id)
+	menuBtns, err := s.sysRoleBtnRepo.GetByRoleId(ctx, id)
 	roleBtns := make(map[int64][]int64, 0)
 	for _, v := range menuBtns {
 		roleBtns[v.SysMenuID] = append(roleBtns[v.SysMenuID], v.SysBaseMenuBtnID)
@@ -218,17 +227,21 @@ func (s *SysRoleUseCase) GetRoleMenuIds(id int64) (map[int][]int, map[int64][]in
 	return menuGroups, roleBtns, nil
 }
 
-func (s *SysRoleUseCase) UpdateRoleMenuIds(id int, updateMap map[string]any) error {
-	return s.sysRoleMenuRepository.Insert(id, updateMap)
+func (s *SysRoleUseCase) UpdateRoleMenuIds(ctx context.Context, id int, updateMap map[string]any) error {
+	return s.sysRoleMenuRepository.Insert(ctx, id, updateMap)
 }
-func (s *SysRoleUseCase) GetApiRuleList(roleId int) ([]string, error) {
-	return s.casbinRuleRepo.GetByRoleId(roleId)
+func (s *SysRoleUseCase) GetApiRuleList(ctx context.Context, roleId int) ([]string, error) {
+	return s.casbinRuleRepo.GetByRoleId(ctx, roleId)
 
 }
-func (s *SysRoleUseCase) BindApiRule(roleId int, updateMap map[string]interface{}) error {
-	return s.casbinRuleRepo.Insert(roleId, updateMap)
+func (s *SysRoleUseCase) BindApiRule(ctx context.Context, roleId int, updateMap map[string]interface{}) error {
+	return s.casbinRuleRepo.Insert(ctx, roleId, updateMap)
 }
 
-func (s *SysRoleUseCase) BindRoleMenuBtns(roleId int64, updateMap map[string]interface{}) error {
-	return s.sysRoleBtnRepo.Insert(roleId, updateMap)
+func (s *SysRoleUseCase) BindRoleMenuBtns(ctx context.Context, roleId int64, updateMap map[string]interface{}) error {
+	if err := s.sysRoleBtnRepo.Insert(ctx, roleId, updateMap); err != nil {
+		return err
+	}
+	s.btnPermissions.InvalidateRole(roleId)
+	return nil
 }
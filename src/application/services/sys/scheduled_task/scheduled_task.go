@@ -1,6 +1,7 @@
 package scheduled_task
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -14,15 +15,15 @@ import (
 )
 
 type IScheduledTaskService interface {
-	GetAll() (*[]scheduledTaskDomain.ScheduledTask, error)
-	Create(apiDomain *scheduledTaskDomain.ScheduledTask) (*scheduledTaskDomain.ScheduledTask, error)
-	GetByID(id int) (*scheduledTaskDomain.ScheduledTask, error)
-	Update(id int, apiMap map[string]interface{}) (*scheduledTaskDomain.ScheduledTask, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[scheduledTaskDomain.ScheduledTask], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	EnableTask(id int) error
-	DisableTask(id int) error
+	GetAll(ctx context.Context) (*[]scheduledTaskDomain.ScheduledTask, error)
+	Create(ctx context.Context, apiDomain *scheduledTaskDomain.ScheduledTask) (*scheduledTaskDomain.ScheduledTask, error)
+	GetByID(ctx context.Context, id int) (*scheduledTaskDomain.ScheduledTask, error)
+	Update(ctx context.Context, id int, actorId int64, apiMap map[string]interface{}) (*scheduledTaskDomain.ScheduledTask, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[scheduledTaskDomain.ScheduledTask], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	EnableTask(ctx context.Context, id int, actorId int64) error
+	DisableTask(ctx context.Context, id int, actorId int64) error
 	ReloadTasks() error
 }
 
@@ -43,52 +44,52 @@ func NewScheduledTaskUseCase(
 	}
 }
 
-func (s *ScheduledTaskUseCase) GetAll() (*[]scheduledTaskDomain.ScheduledTask, error) {
+func (s *ScheduledTaskUseCase) GetAll(ctx context.Context) (*[]scheduledTaskDomain.ScheduledTask, error) {
 	s.Logger.Info("Getting all tasks")
-	return s.scheduledTaskRepository.GetAll()
+	return s.scheduledTaskRepository.GetAll(ctx)
 }
 
-func (s *ScheduledTaskUseCase) GetByID(id int) (*scheduledTaskDomain.ScheduledTask, error) {
+func (s *ScheduledTaskUseCase) GetByID(ctx context.Context, id int) (*scheduledTaskDomain.ScheduledTask, error) {
 	s.Logger.Info("Getting task by ID", zap.Int("id", id))
-	return s.scheduledTaskRepository.GetByID(id)
+	return s.scheduledTaskRepository.GetByID(ctx, id)
 }
 
-func (s *ScheduledTaskUseCase) Create(newData *scheduledTaskDomain.ScheduledTask) (*scheduledTaskDomain.ScheduledTask, error) {
+func (s *ScheduledTaskUseCase) Create(ctx context.Context, newData *scheduledTaskDomain.ScheduledTask) (*scheduledTaskDomain.ScheduledTask, error) {
 	s.Logger.Info("Creating new task", zap.String("TaskName", newData.TaskName))
-	return s.scheduledTaskRepository.Create(newData)
+	return s.scheduledTaskRepository.Create(ctx, newData)
 }
 
-func (s *ScheduledTaskUseCase) Delete(ids []int) error {
+func (s *ScheduledTaskUseCase) Delete(ctx context.Context, ids []int) error {
 	s.Logger.Info("Deleting task", zap.String("ids", fmt.Sprintf("%v", ids)))
-	return s.scheduledTaskRepository.Delete(ids)
+	return s.scheduledTaskRepository.Delete(ctx, ids)
 }
 
-func (s *ScheduledTaskUseCase) Update(id int, userMap map[string]interface{}) (*scheduledTaskDomain.ScheduledTask, error) {
+func (s *ScheduledTaskUseCase) Update(ctx context.Context, id int, actorId int64, userMap map[string]interface{}) (*scheduledTaskDomain.ScheduledTask, error) {
 	s.Logger.Info("Updating task", zap.Int("id", id))
-	return s.scheduledTaskRepository.Update(id, userMap)
+	return s.scheduledTaskRepository.Update(ctx, id, actorId, userMap)
 }
 
-func (s *ScheduledTaskUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[scheduledTaskDomain.ScheduledTask], error) {
+func (s *ScheduledTaskUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[scheduledTaskDomain.ScheduledTask], error) {
 	s.Logger.Info("Searching tasks with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.scheduledTaskRepository.SearchPaginated(filters)
+	return s.scheduledTaskRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *ScheduledTaskUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *ScheduledTaskUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching tasks by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.scheduledTaskRepository.SearchByProperty(property, searchText)
+	return s.scheduledTaskRepository.SearchByProperty(ctx, property, searchText)
 }
 
 // DisableTask implements IScheduledTaskService.
-func (s *ScheduledTaskUseCase) DisableTask(taskID int) error {
+func (s *ScheduledTaskUseCase) DisableTask(ctx context.Context, taskID int, actorId int64) error {
 	updateData := map[string]interface{}{
 		"status": scheduleTaskConstants.TaskStatusDisabled,
 	}
 	time.Sleep(time.Millisecond * 3000)
-	_, err := s.scheduledTaskRepository.Update(taskID, updateData)
+	_, err := s.scheduledTaskRepository.Update(ctx, taskID, actorId, updateData)
 	if err != nil {
 		return err
 	}
@@ -97,11 +98,11 @@ func (s *ScheduledTaskUseCase) DisableTask(taskID int) error {
 }
 
 // EnableTask implements IScheduledTaskService.
-func (s *ScheduledTaskUseCase) EnableTask(taskID int) error {
+func (s *ScheduledTaskUseCase) EnableTask(ctx context.Context, taskID int, actorId int64) error {
 	updateData := map[string]interface{}{
 		"status": scheduleTaskConstants.TaskStatusEnabled,
 	}
-	_, err := s.scheduledTaskRepository.Update(taskID, updateData)
+	_, err := s.scheduledTaskRepository.Update(ctx, taskID, actorId, updateData)
 	if err != nil {
 		return err
 	}
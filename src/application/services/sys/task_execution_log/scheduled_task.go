@@ -1,6 +1,7 @@
 package scheduled_task
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -11,11 +12,11 @@ import (
 )
 
 type ITaskExecutionLogService interface {
-	GetByID(id int) (*taskExecutionLogDomain.TaskExecutionLog, error)
-	Delete(ids []int) error
-	GetByTaskID(taskID uint, limit int) (*[]taskExecutionLogDomain.TaskExecutionLog, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[taskExecutionLogDomain.TaskExecutionLog], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
+	GetByID(ctx context.Context, id int) (*taskExecutionLogDomain.TaskExecutionLog, error)
+	Delete(ctx context.Context, ids []int) error
+	GetByTaskID(ctx context.Context, taskID uint, limit int) (*[]taskExecutionLogDomain.TaskExecutionLog, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[taskExecutionLogDomain.TaskExecutionLog], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
 }
 
 type TaskExecutionLogUseCase struct {
@@ -24,9 +25,9 @@ type TaskExecutionLogUseCase struct {
 }
 
 // GetByTaskID implements ITaskExecutionLogService.
-func (s *TaskExecutionLogUseCase) GetByTaskID(taskID uint, limit int) (*[]taskExecutionLogDomain.TaskExecutionLog, error) {
+func (s *TaskExecutionLogUseCase) GetByTaskID(ctx context.Context, taskID uint, limit int) (*[]taskExecutionLogDomain.TaskExecutionLog, error) {
 	s.Logger.Info("Getting log by TaskID", zap.Uint("taskID", taskID))
-	return s.taskExecutionLogRepository.GetByTaskID(taskID, limit)
+	return s.taskExecutionLogRepository.GetByTaskID(ctx, taskID, limit)
 }
 
 func NewTaskExecutionLogUseCase(
@@ -39,26 +40,26 @@ func NewTaskExecutionLogUseCase(
 	}
 }
 
-func (s *TaskExecutionLogUseCase) GetByID(id int) (*taskExecutionLogDomain.TaskExecutionLog, error) {
+func (s *TaskExecutionLogUseCase) GetByID(ctx context.Context, id int) (*taskExecutionLogDomain.TaskExecutionLog, error) {
 	s.Logger.Info("Getting task by ID", zap.Int("id", id))
-	return s.taskExecutionLogRepository.GetByID(id)
+	return s.taskExecutionLogRepository.GetByID(ctx, id)
 }
 
-func (s *TaskExecutionLogUseCase) Delete(ids []int) error {
+func (s *TaskExecutionLogUseCase) Delete(ctx context.Context, ids []int) error {
 	s.Logger.Info("Deleting task", zap.String("ids", fmt.Sprintf("%v", ids)))
-	return s.taskExecutionLogRepository.Delete(ids)
+	return s.taskExecutionLogRepository.Delete(ctx, ids)
 }
 
-func (s *TaskExecutionLogUseCase) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[taskExecutionLogDomain.TaskExecutionLog], error) {
+func (s *TaskExecutionLogUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[taskExecutionLogDomain.TaskExecutionLog], error) {
 	s.Logger.Info("Searching tasks with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.taskExecutionLogRepository.SearchPaginated(filters)
+	return s.taskExecutionLogRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *TaskExecutionLogUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *TaskExecutionLogUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching tasks by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.taskExecutionLogRepository.SearchByProperty(property, searchText)
+	return s.taskExecutionLogRepository.SearchByProperty(ctx, property, searchText)
 }
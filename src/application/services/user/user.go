@@ -1,11 +1,14 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/gbrayhan/microservices-go/src/application/event/bus"
+	"github.com/gbrayhan/microservices-go/src/application/event/model"
 	"github.com/gbrayhan/microservices-go/src/domain"
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
 	userDomain "github.com/gbrayhan/microservices-go/src/domain/user"
@@ -19,18 +22,20 @@ import (
 )
 
 type IUserUseCase interface {
-	GetAll() (*[]userDomain.User, error)
-	GetByID(id int) (*userDomain.User, error)
-	GetByEmail(email string) (*userDomain.User, error)
-	Create(newUser *userDomain.User) (*userDomain.User, error)
-	Delete(id int) error
-	Update(id int64, userMap map[string]interface{}) (*userDomain.User, error)
-	SearchPaginated(filters domain.DataFilters) (*userDomain.SearchResultUser, error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*userDomain.User, error)
-	UserBindRoles(userId int64, updateMap map[string]interface{}) error
-	ResetPassword(userId int64) (*userDomain.User, error)
-	EditPassword(userId int64, data userDomain.PasswordEditRequest) (*userDomain.User, error)
+	GetAll(ctx context.Context) (*[]userDomain.User, error)
+	GetByID(ctx context.Context, id int) (*userDomain.User, error)
+	GetByEmail(ctx context.Context, email string) (*userDomain.User, error)
+	Create(ctx context.Context, newUser *userDomain.User) (*userDomain.User, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int64, actorId int64, userMap map[string]interface{}) (*userDomain.User, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*userDomain.SearchResultUser, error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*userDomain.User, error)
+	UserBindRoles(ctx context.Context, userId int64, grantedBy int64, updateMap map[string]interface{}) error
+	GrantRole(ctx context.Context, userId int64, grantedBy int64, grant userDomain.UserRoleGrant) error
+	GetUserRoles(ctx context.Context, userId int64) (*[]userDomain.UserRole, error)
+	ResetPassword(ctx context.Context, userId int64, actorId int64) (*userDomain.User, error)
+	EditPassword(ctx context.Context, userId int64, actorId int64, data userDomain.PasswordEditRequest) (*userDomain.User, error)
 }
 
 type UserUseCase struct {
@@ -53,7 +58,7 @@ func NewUserUseCase(
 	}
 }
 
-func (s *UserUseCase) GetAll() (*[]userDomain.User, error) {
+func (s *UserUseCase) GetAll(ctx context.Context) (*[]userDomain.User, error) {
 	// fmt.Println("s.eventBus", s.eventBus)
 	// context := context.Background()
 	// s.eventBus.Publish(context, &model.UserRegisteredEvent{
@@ -64,20 +69,20 @@ func (s *UserUseCase) GetAll() (*[]userDomain.User, error) {
 	// 	RegisteredAt: time.Now(),
 	// })
 	s.Logger.Info("Getting all users")
-	return s.userRepository.GetAll()
+	return s.userRepository.GetAll(ctx)
 }
 
-func (s *UserUseCase) GetByID(id int) (*userDomain.User, error) {
+func (s *UserUseCase) GetByID(ctx context.Context, id int) (*userDomain.User, error) {
 	s.Logger.Info("Getting user by ID", zap.Int("id", id))
-	return s.userRepository.GetByID(id)
+	return s.userRepository.GetByID(ctx, id)
 }
 
-func (s *UserUseCase) GetByEmail(email string) (*userDomain.User, error) {
+func (s *UserUseCase) GetByEmail(ctx context.Context, email string) (*userDomain.User, error) {
 	s.Logger.Info("Getting user by email", zap.String("email", email))
-	return s.userRepository.GetByEmail(email)
+	return s.userRepository.GetByEmail(ctx, email)
 }
 
-func (s *UserUseCase) Create(newUser *userDomain.User) (*userDomain.User, error) {
+func (s *UserUseCase) Create(ctx context.Context, newUser *userDomain.User) (*userDomain.User, error) {
 	s.Logger.Info("Creating new user", zap.String("email", newUser.Email))
 	hash, err := bcrypt.GenerateFromPassword([]byte(newUser.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -88,41 +93,86 @@ func (s *UserUseCase) Create(newUser *userDomain.User) (*userDomain.User, error)
 	newUser.UUID = uuid.New().String()
 	newUser.Status = 1
 	fmt.Println("UUID", newUser.UUID)
-	return s.userRepository.Create(newUser)
+	return s.userRepository.Create(ctx, newUser)
 }
 
-func (s *UserUseCase) Delete(id int) error {
+func (s *UserUseCase) Delete(ctx context.Context, id int) error {
 	s.Logger.Info("Deleting user", zap.Int("id", id))
-	return s.userRepository.Delete(id)
+	if err := s.userRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	// the files attached to the user are detached by the subscribers
+	if s.eventBus != nil {
+		err := s.eventBus.Publish(ctx, &model.EntityDeletedEvent{
+			ID:         uuid.New().String(),
+			EntityType: model.EntityTypeUser,
+			EntityID:   int64(id),
+			DeletedAt:  time.Now(),
+		})
+		if err != nil {
+			s.Logger.Warn("Error publishing user deletion", zap.Int("id", id), zap.Error(err))
+		}
+	}
+	return nil
 }
 
-func (s *UserUseCase) Update(id int64, userMap map[string]interface{}) (*userDomain.User, error) {
+func (s *UserUseCase) Update(ctx context.Context, id int64, actorId int64, userMap map[string]interface{}) (*userDomain.User, error) {
 	s.Logger.Info("Updating user", zap.Int64("id", id))
-	return s.userRepository.Update(id, userMap)
+	return s.userRepository.Update(ctx, id, actorId, userMap)
 }
 
-func (s *UserUseCase) SearchPaginated(filters domain.DataFilters) (*userDomain.SearchResultUser, error) {
+func (s *UserUseCase) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*userDomain.SearchResultUser, error) {
 	s.Logger.Info("Searching users with pagination",
 		zap.Int("page", filters.Page),
 		zap.Int("pageSize", filters.PageSize))
-	return s.userRepository.SearchPaginated(filters)
+	return s.userRepository.SearchPaginated(ctx, filters)
 }
 
-func (s *UserUseCase) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (s *UserUseCase) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	s.Logger.Info("Searching users by property",
 		zap.String("property", property),
 		zap.String("searchText", searchText))
-	return s.userRepository.SearchByProperty(property, searchText)
+	return s.userRepository.SearchByProperty(ctx, property, searchText)
 }
 
-func (s *UserUseCase) GetOneByMap(userMap map[string]interface{}) (*userDomain.User, error) {
-	return s.userRepository.GetOneByMap(userMap)
+func (s *UserUseCase) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*userDomain.User, error) {
+	return s.userRepository.GetOneByMap(ctx, userMap)
+}
+func (s *UserUseCase) UserBindRoles(ctx context.Context, userId int64, grantedBy int64, updateMap map[string]interface{}) error {
+	return s.userRoleRepository.Insert(ctx, userId, grantedBy, updateMap)
 }
-func (s *UserUseCase) UserBindRoles(userId int64, updateMap map[string]interface{}) error {
-	return s.userRoleRepository.Insert(userId, updateMap)
+
+// GrantRole binds a single role to the user, optionally limited to a validity window
+func (s *UserUseCase) GrantRole(ctx context.Context, userId int64, grantedBy int64, grant userDomain.UserRoleGrant) error {
+	binding := &userDomain.UserRole{
+		UserID:    userId,
+		RoleID:    grant.RoleID,
+		GrantedBy: grantedBy,
+	}
+	if grant.StartAt != nil && !grant.StartAt.IsZero() {
+		binding.StartAt = &grant.StartAt.Time
+	}
+	if grant.ExpiresAt != nil && !grant.ExpiresAt.IsZero() {
+		binding.ExpiresAt = &grant.ExpiresAt.Time
+		if !binding.ExpiresAt.After(time.Now()) {
+			return domainErrors.NewAppError(errors.New("expiresAt must be in the future"), domainErrors.ValidationError)
+		}
+		if binding.StartAt != nil && !binding.ExpiresAt.After(*binding.StartAt) {
+			return domainErrors.NewAppError(errors.New("expiresAt must be after startAt"), domainErrors.ValidationError)
+		}
+	}
+	s.Logger.Info("Granting user role",
+		zap.Int64("userId", userId),
+		zap.Int64("roleId", grant.RoleID),
+		zap.Int64("grantedBy", grantedBy))
+	return s.userRoleRepository.Grant(ctx, binding)
+}
+
+func (s *UserUseCase) GetUserRoles(ctx context.Context, userId int64) (*[]userDomain.UserRole, error) {
+	return s.userRoleRepository.GetBindingsByUserId(ctx, userId)
 }
 
-func (s *UserUseCase) ResetPassword(userId int64) (*userDomain.User, error) {
+func (s *UserUseCase) ResetPassword(ctx context.Context, userId int64, actorId int64) (*userDomain.User, error) {
 	updateMap := make(map[string]interface{})
 	password := os.Getenv("RESET_USER_PASSWORD")
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -131,11 +181,11 @@ func (s *UserUseCase) ResetPassword(userId int64) (*userDomain.User, error) {
 		return nil, err
 	}
 	updateMap["hash_password"] = hash
-	return s.userRepository.Update(userId, updateMap)
+	return s.userRepository.Update(ctx, userId, actorId, updateMap)
 }
 
-func (s *UserUseCase) EditPassword(userId int64, data userDomain.PasswordEditRequest) (*userDomain.User, error) {
-	userInfo, err := s.userRepository.GetByID(int(userId))
+func (s *UserUseCase) EditPassword(ctx context.Context, userId int64, actorId int64, data userDomain.PasswordEditRequest) (*userDomain.User, error) {
+	userInfo, err := s.userRepository.GetByID(ctx, int(userId))
 	if err != nil {
 		s.Logger.Error("Error getting user info", zap.Error(err))
 		return nil, err
@@ -153,5 +203,5 @@ func (s *UserUseCase) EditPassword(userId int64, data userDomain.PasswordEditReq
 	}
 	updateMap := make(map[string]interface{})
 	updateMap["hash_password"] = hash
-	return s.userRepository.Update(userId, updateMap)
+	return s.userRepository.Update(ctx, userId, actorId, updateMap)
 }
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gbrayhan/microservices-go/src/application/event/bus"
+	"github.com/gbrayhan/microservices-go/src/application/event/model"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	userRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubUserRepository records the deleted ids, the methods not used by the tests panic
+type stubUserRepository struct {
+	userRepo.UserRepositoryInterface
+	deleted []int
+}
+
+func (r *stubUserRepository) Delete(_ context.Context, id int) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+type recordingHandler struct {
+	events []model.ApplicationEvent
+}
+
+func (h *recordingHandler) Handle(event model.ApplicationEvent) error {
+	h.events = append(h.events, event)
+	return nil
+}
+
+func TestDeletePublishesWithRequestContext(t *testing.T) {
+	log := &logger.Logger{Log: zap.NewNop()}
+	eventBus := bus.NewInMemoryEventBus(log)
+	handler := &recordingHandler{}
+	require.NoError(t, eventBus.Subscribe(model.EntityDeletedEventType, handler))
+	repository := &stubUserRepository{}
+	useCase := NewUserUseCase(repository, nil, eventBus, log)
+
+	ctx := requestid.WithContext(context.Background(), "req-42")
+	require.NoError(t, useCase.Delete(ctx, 7))
+
+	assert.Equal(t, []int{7}, repository.deleted)
+	require.Len(t, handler.events, 1)
+	assert.Equal(t, "req-42", handler.events[0].Metadata()[requestid.MetadataKey],
+		"the event carries the request id of the deletion")
+}
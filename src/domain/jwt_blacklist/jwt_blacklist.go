@@ -1,6 +1,8 @@
 package jwt_blacklist
 
+import "context"
+
 type IJwtBlacklistService interface {
-	AddToBlacklist(jwtToken string) error
-	IsJwtInBlacklist(token string) (bool, error)
+	AddToBlacklist(ctx context.Context, jwtToken string) error
+	IsJwtInBlacklist(ctx context.Context, token string) (bool, error)
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -17,17 +18,31 @@ type Api struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// DefaultGroup is the group of synchronized routes without swagger tags
+const DefaultGroup = "Other"
+
+// SyncResult reports what a router synchronization changed.
+// Removed lists apis whose route no longer exists; they are only deleted when Pruned is set.
+// Undocumented lists the routes missing from the swagger document, classified by their path.
+type SyncResult struct {
+	Created      int      `json:"created"`
+	Updated      int      `json:"updated"`
+	Removed      []Api    `json:"removed"`
+	Pruned       bool     `json:"pruned"`
+	Undocumented []string `json:"undocumented"`
+}
+
 type IApiService interface {
-	GetAll() (*[]Api, error)
-	GetByID(id int) (*Api, error)
-	Create(newApi *Api) (*Api, error)
-	Delete(ids []int) error
-	Update(id int, userMap map[string]interface{}) (*Api, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[Api], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*Api, error)
-	GetApisGroup() (*[]GroupApiItem, error)
-	SynchronizeRouterToApi(router gin.RoutesInfo) (*int, error)
+	GetAll(ctx context.Context) (*[]Api, error)
+	GetByID(ctx context.Context, id int) (*Api, error)
+	Create(ctx context.Context, newApi *Api) (*Api, error)
+	Delete(ctx context.Context, ids []int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*Api, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[Api], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*Api, error)
+	GetApisGroup(ctx context.Context) (*[]GroupApiItem, error)
+	SynchronizeRouterToApi(ctx context.Context, router gin.RoutesInfo, prune bool) (*SyncResult, error)
 }
 
 type GroupApiItem struct {
@@ -0,0 +1,114 @@
+package audit_log
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gbrayhan/microservices-go/src/domain"
+)
+
+// Entity types recorded in the audit trail
+const (
+	EntityUser          = "user"
+	EntityRole          = "role"
+	EntityMenu          = "menu"
+	EntityConfig        = "config"
+	EntityScheduledTask = "scheduled_task"
+	// EntitySTSToken entries are keyed by the user the credentials were issued to
+	EntitySTSToken = "sts_token"
+)
+
+const (
+	ActionUpdate = "update"
+	// ActionIssue records credentials handed out, the changes hold their scope
+	ActionIssue = "issue"
+)
+
+// SystemActor is the actor of changes made by the application itself, e.g. the scheduler
+// updating the run status of a task
+const SystemActor int64 = 0
+
+// Mask replaces the values of sensitive columns, the change itself is still recorded
+const Mask = "******"
+
+// Change holds the value of a single column before and after an update
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditLog is one entity change: who changed which entity and how
+type AuditLog struct {
+	ID         int               `json:"id"`
+	EntityType string            `json:"entity_type"`
+	EntityID   string            `json:"entity_id"`
+	ActorID    int64             `json:"actor_id"`
+	Action     string            `json:"action"`
+	Changes    map[string]Change `json:"changes"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Diff compares two column snapshots and returns the changed columns. Columns listed in
+// ignore are skipped, sensitive ones (passwords, secrets) are recorded with masked values.
+func Diff(before, after map[string]any, ignore ...string) map[string]Change {
+	changes := make(map[string]Change)
+	skip := make(map[string]bool, len(ignore))
+	for _, column := range ignore {
+		skip[column] = true
+	}
+	for _, column := range unionKeys(before, after) {
+		if skip[column] {
+			continue
+		}
+		oldValue, newValue := before[column], after[column]
+		if equalValues(oldValue, newValue) {
+			continue
+		}
+		if isSensitive(column) {
+			oldValue, newValue = Mask, Mask
+		}
+		changes[column] = Change{Before: oldValue, After: newValue}
+	}
+	return changes
+}
+
+func unionKeys(before, after map[string]any) []string {
+	keys := make([]string, 0, len(before))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func equalValues(a, b any) bool {
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			return at.Equal(bt)
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	// the same value may come back from the database with another type, e.g. []byte and string
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+func isSensitive(column string) bool {
+	column = strings.ToLower(column)
+	return strings.Contains(column, "password") || strings.Contains(column, "secret") || strings.Contains(column, "token")
+}
+
+type IAuditLogService interface {
+	GetByID(ctx context.Context, id int) (*AuditLog, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[AuditLog], error)
+}
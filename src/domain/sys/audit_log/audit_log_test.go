@@ -0,0 +1,34 @@
+package audit_log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff_ChangedColumnsOnly(t *testing.T) {
+	now := time.Now()
+	before := map[string]any{"id": 1, "name": "admin", "sort": 1, "updated_at": now, "created_at": now}
+	after := map[string]any{"id": 1, "name": "root", "sort": 1, "updated_at": now.Add(time.Second), "created_at": now.UTC()}
+
+	changes := Diff(before, after, "updated_at")
+
+	assert.Equal(t, map[string]Change{"name": {Before: "admin", After: "root"}}, changes)
+}
+
+func TestDiff_MasksSensitiveColumns(t *testing.T) {
+	changes := Diff(
+		map[string]any{"hash_password": "$2a$old", "access_key_secret": "a"},
+		map[string]any{"hash_password": "$2a$new", "access_key_secret": "a"},
+	)
+
+	assert.Equal(t, map[string]Change{"hash_password": {Before: Mask, After: Mask}}, changes)
+}
+
+func TestDiff_AddedAndRemovedColumns(t *testing.T) {
+	changes := Diff(map[string]any{"icon": "home"}, map[string]any{"title": "Home"})
+
+	assert.Equal(t, Change{Before: "home", After: nil}, changes["icon"])
+	assert.Equal(t, Change{Before: nil, After: "Home"}, changes["title"])
+}
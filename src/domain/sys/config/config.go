@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"github.com/gbrayhan/microservices-go/src/domain"
 	domainDictionaryDetail "github.com/gbrayhan/microservices-go/src/domain/sys/dictionary_detail"
 )
@@ -24,7 +25,7 @@ type GroupConfig struct {
 }
 
 type IConfigService interface {
-	GetConfigByGroup() (*[]GroupConfig, error)
-	Update(module string, dataMap map[string]interface{}) error
-	GetConfigByModule(module string) (*[]Config, error)
+	GetConfigByGroup(ctx context.Context) (*[]GroupConfig, error)
+	Update(ctx context.Context, module string, actorId int64, dataMap map[string]interface{}) error
+	GetConfigByModule(ctx context.Context, module string) (*[]Config, error)
 }
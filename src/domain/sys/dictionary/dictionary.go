@@ -1,6 +1,7 @@
 package dictionary
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -20,13 +21,13 @@ type Dictionary struct {
 }
 
 type IDictionaryService interface {
-	GetAll() (*[]Dictionary, error)
-	GetByID(id int) (*Dictionary, error)
-	Create(newDictionary *Dictionary) (*Dictionary, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*Dictionary, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[Dictionary], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*Dictionary, error)
-	GetByType(typeText string) (*Dictionary, error)
+	GetAll(ctx context.Context) (*[]Dictionary, error)
+	GetByID(ctx context.Context, id int) (*Dictionary, error)
+	Create(ctx context.Context, newDictionary *Dictionary) (*Dictionary, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*Dictionary, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[Dictionary], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*Dictionary, error)
+	GetByType(ctx context.Context, typeText string) (*Dictionary, error)
 }
@@ -1,6 +1,7 @@
 package dictionary_detail
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -20,12 +21,12 @@ type DictionaryDetail struct {
 }
 
 type IDictionaryDetailService interface {
-	GetAll() (*[]DictionaryDetail, error)
-	GetByID(id int) (*DictionaryDetail, error)
-	Create(newDictionary *DictionaryDetail) (*DictionaryDetail, error)
-	Delete(ids []int) error
-	Update(id int, userMap map[string]interface{}) (*DictionaryDetail, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[DictionaryDetail], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*DictionaryDetail, error)
+	GetAll(ctx context.Context) (*[]DictionaryDetail, error)
+	GetByID(ctx context.Context, id int) (*DictionaryDetail, error)
+	Create(ctx context.Context, newDictionary *DictionaryDetail) (*DictionaryDetail, error)
+	Delete(ctx context.Context, ids []int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*DictionaryDetail, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[DictionaryDetail], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*DictionaryDetail, error)
 }
@@ -0,0 +1,44 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// FileAttachment links a file to an entity of any type, like the documents of a customer. The
+// attachments of an entity are ordered by Sort.
+type FileAttachment struct {
+	ID         int64     `json:"id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   int64     `json:"entity_id"`
+	FileID     int64     `json:"file_id"`
+	Sort       int       `json:"sort"`
+	Label      string    `json:"label"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// File is only filled when listing the attachments of an entity
+	File *SysFiles `json:"file,omitempty"`
+}
+
+var (
+	ErrUnknownEntityType  = errors.New("files can't be attached to this entity type")
+	ErrEntityNotFound     = errors.New("the entity doesn't exist")
+	ErrAttachmentNotFound = errors.New("the file is not attached to the entity")
+	ErrAttachmentOrder    = errors.New("the order must list every attached file once")
+)
+
+type IFileAttachmentService interface {
+	// Attach adds a file the requester may read at the end of the attachments of an entity.
+	// Attaching it again only changes its label.
+	Attach(ctx context.Context, attachment FileAttachment, access FileAccess) (*FileAttachment, error)
+	Detach(ctx context.Context, entityType string, entityID int64, fileID int64) error
+	// Reorder sorts the attachments of an entity in the order of fileIDs, which lists each
+	// attached file once
+	Reorder(ctx context.Context, entityType string, entityID int64, fileIDs []int64) (*[]FileAttachment, error)
+	List(ctx context.Context, entityType string, entityID int64) (*[]FileAttachment, error)
+	// DetachEntity removes the attachments of a deleted entity and returns how many there were
+	DetachEntity(ctx context.Context, entityType string, entityID int64) (int64, error)
+	// Prune removes the attachments of the entities deleted without DetachEntity
+	Prune(ctx context.Context) (int64, error)
+}
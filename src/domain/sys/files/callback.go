@@ -0,0 +1,52 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrCallbackNotConfigured = errors.New("upload callbacks are not configured")
+	ErrObjectNotGranted      = errors.New("the object was not uploaded with an upload token")
+	ErrObjectOutOfScope      = errors.New("the object exceeds the scope of its upload token")
+)
+
+// UploadedObject is announced by the callback of an upload straight to the bucket. The owner is
+// the user the prefix of the key was granted to, FileName and Visibility are chosen by the client.
+type UploadedObject struct {
+	Bucket     string
+	Key        string
+	FileName   string
+	Visibility string
+}
+
+// StoredObject is an object already in the storage to record as a file, an empty MD5 is computed
+// from the content
+type StoredObject struct {
+	Engine      string
+	Key         string
+	MD5         string
+	Size        int64
+	ContentType string
+	OriginName  string
+	Owner       FileOwner
+}
+
+// OrphanObject is an object of the bucket no file, blob or rendition references
+type OrphanObject struct {
+	Engine       string    `json:"storage_engine"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+type IUploadCallbackService interface {
+	// Register records the object of a callback as a file of the user its prefix was granted
+	// to, an object outside the scope of its token is removed. A repeated callback returns the
+	// file already recorded.
+	Register(ctx context.Context, object UploadedObject) (*SysFiles, error)
+	// Reconcile lists the objects written with upload tokens, older than minAge, that are not
+	// recorded, and removes them when remove is set
+	Reconcile(ctx context.Context, minAge time.Duration, remove bool) ([]OrphanObject, error)
+}
@@ -3,23 +3,24 @@
 // Dictionary type: file_storage_engine
 
 package constants
+
 // file_storage_engine - storage_engine
 const (
 	FileStorageEngineAliyunoss = "aliyunoss" // Aliyunoss
-	FileStorageEngineLocal = "local" // Local
+	FileStorageEngineLocal     = "local"     // Local
 )
 
 // file_storage_engineLabelMap label mapping for file_storage_engine
 var file_storage_engineLabelMap = map[string]string{
 	FileStorageEngineAliyunoss: "Aliyunoss",
-	FileStorageEngineLocal: "Local",
+	FileStorageEngineLocal:     "Local",
 }
 
 // file_storage_engineOptions all options for file_storage_engine
-var file_storage_engineOptions = []struct{
+var file_storage_engineOptions = []struct {
 	Value string
 	Label string
 }{
-	{ FileStorageEngineAliyunoss, "Aliyunoss" },
-	{ FileStorageEngineLocal, "Local" },
+	{FileStorageEngineAliyunoss, "Aliyunoss"},
+	{FileStorageEngineLocal, "Local"},
 }
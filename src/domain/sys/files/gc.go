@@ -0,0 +1,42 @@
+package files
+
+import "time"
+
+// GCOptions tells what a garbage collection removes. Nothing updated within Grace is touched, it
+// may belong to an upload still in progress.
+type GCOptions struct {
+	Grace time.Duration
+	// CollectFiles deletes the files no entity references, they are only reported otherwise
+	CollectFiles bool
+	// DryRun only reports what would be removed
+	DryRun bool
+}
+
+// GCReport lists what a garbage collection found, removed unless it was a dry run
+type GCReport struct {
+	// DanglingBlobs are the blobs no file references
+	DanglingBlobs []FileBlob `json:"dangling_blobs"`
+	// UnreferencedFiles are the uploaded files no entity references, deleted with CollectFiles
+	UnreferencedFiles []SysFiles `json:"unreferenced_files"`
+	// OrphanObjects are the objects of the engines no record references
+	OrphanObjects []OrphanObject `json:"orphan_objects"`
+	DryRun        bool           `json:"dry_run"`
+}
+
+// UserStorageUsage is what the files of one owner take, UserID is nil for the files recorded
+// without owner. Bytes counts a content shared by several files once per file.
+type UserStorageUsage struct {
+	UserID *int64 `json:"user_id"`
+	Files  int64  `json:"files"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// EngineStorageUsage is what one storage engine holds: Files and Bytes are the recorded files,
+// Objects and StoredBytes the distinct objects behind them and their thumbnails
+type EngineStorageUsage struct {
+	Engine      string `json:"engine"`
+	Files       int64  `json:"files"`
+	Bytes       int64  `json:"bytes"`
+	Objects     int64  `json:"objects"`
+	StoredBytes int64  `json:"stored_bytes"`
+}
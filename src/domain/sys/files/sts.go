@@ -0,0 +1,61 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSTSNotConfigured    = errors.New("sts credentials are not configured")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+)
+
+// STSScope is what the credentials of an STS token allow: writing objects under Prefix, unique
+// to the token and its user. RAM policies can't bound the object size and content type, they are
+// checked against MaxSize and ContentTypes when the object is recorded.
+type STSScope struct {
+	Prefix       string   `json:"prefix"`
+	Extension    string   `json:"extension"`
+	MaxSize      int64    `json:"max_size"`
+	ContentTypes []string `json:"content_types"`
+}
+
+// STSGrant is kept for the prefix of a token, the callbacks of the uploads under the prefix are
+// recorded for the user and checked against the scope
+type STSGrant struct {
+	UserID int64    `json:"user_id"`
+	RoleID int64    `json:"role_id"`
+	Scope  STSScope `json:"scope"`
+}
+
+// STSRequest asks credentials to upload one file, FileSize is -1 when unknown and ContentType
+// narrows the types allowed for the extension of FileName. The uploaded files belong to UserID
+// and RoleID.
+type STSRequest struct {
+	UserID      int64
+	RoleID      int64
+	FileName    string
+	FileSize    int64
+	ContentType string
+}
+
+// STSToken are temporary credentials of the bucket limited to Scope
+type STSToken struct {
+	AccessKeyId     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      time.Time
+	BucketName      string
+	Region          string
+	Scope           STSScope
+	RefreshToken    string
+}
+
+type ISTSService interface {
+	// Issue returns the cached token of the user for the same scope, or has new credentials
+	// issued; every issuance is recorded in the audit log
+	Issue(ctx context.Context, request STSRequest) (*STSToken, error)
+	// Refresh consumes a refresh token of the user and issues a token of the same scope
+	Refresh(ctx context.Context, userID int64, roleID int64, refreshToken string) (*STSToken, error)
+}
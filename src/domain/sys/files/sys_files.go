@@ -1,6 +1,9 @@
 package files
 
 import (
+	"context"
+	"errors"
+	"io"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -11,12 +14,99 @@ type SysFiles struct {
 	FileName       string    `json:"file_name"`
 	FileMD5        string    `json:"file_md5"`
 	FilePath       string    `json:"file_path"`
+	FileSize       int64     `json:"file_size"`
 	FileUrl        string    `json:"file_url"`
 	StorageEngine  string    `json:"storage_engine"`
 	FileOriginName string    `json:"file_origin_name"`
+	BlobID         *int64    `json:"blob_id,omitempty"`
+	Visibility     string    `json:"visibility"`
+	OwnerID        *int64    `json:"owner_id,omitempty"`
+	OwnerRoleID    *int64    `json:"owner_role_id,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	// Renditions are the thumbnails derived from an image, only filled when getting one file
+	Renditions []FileRendition `json:"renditions,omitempty"`
 }
+
+// File visibilities: public files are served to anyone, role files to the users of the owner
+// role and private files to their owner only. Users granted the download route by casbin read
+// every file.
+const (
+	VisibilityPublic  = "public"
+	VisibilityRole    = "role"
+	VisibilityPrivate = "private"
+)
+
+// ValidVisibility tells whether v is one of the visibilities
+func ValidVisibility(v string) bool {
+	return v == VisibilityPublic || v == VisibilityRole || v == VisibilityPrivate
+}
+
+// FileOwner is who an uploaded file belongs to, an empty Visibility takes the configured default
+type FileOwner struct {
+	UserID     int64
+	RoleID     int64
+	Visibility string
+}
+
+// FileAccess is who asks to read a file, Granted is set for the users allowed to read every file
+type FileAccess struct {
+	UserID  int64
+	RoleID  int64
+	Granted bool
+}
+
+var (
+	ErrNotAnImage           = errors.New("the file is not an image")
+	ErrUnknownThumbnailSize = errors.New("unknown thumbnail size")
+	ErrInvalidVisibility    = errors.New("visibility must be public, role or private")
+	ErrFileAccessDenied     = errors.New("the file is not shared with you")
+)
+
+// FileRendition is a thumbnail of an image file, stored as its own object and removed with the
+// file it was derived from
+type FileRendition struct {
+	ID            int64     `json:"id"`
+	FileID        int64     `json:"file_id"`
+	Size          string    `json:"size"`
+	Width         int       `json:"width"`
+	Height        int       `json:"height"`
+	ContentType   string    `json:"content_type"`
+	FilePath      string    `json:"file_path"`
+	FileSize      int64     `json:"file_size"`
+	FileUrl       string    `json:"file_url"`
+	StorageEngine string    `json:"storage_engine"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// FileBlob is a stored object shared by every file with the same content. It is removed from
+// the storage once RefCount drops to zero. Files recorded before blobs existed have no blob,
+// their object is removed with the last record at its path.
+type FileBlob struct {
+	ID            int64  `json:"id"`
+	FileMD5       string `json:"file_md5"`
+	FileSize      int64  `json:"file_size"`
+	FilePath      string `json:"file_path"`
+	StorageEngine string `json:"storage_engine"`
+	// SourceMD5 and SourceSize describe the content as received when it was stored modified,
+	// an image without its metadata, so instant uploads of the received content still match
+	SourceMD5  string    `json:"source_md5,omitempty"`
+	SourceSize int64     `json:"source_size,omitempty"`
+	RefCount   int64     `json:"ref_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// UploadFile is a file received by the upload endpoints, Size is -1 when unknown
+type UploadFile struct {
+	OriginName  string
+	Size        int64
+	ContentType string
+	Content     io.Reader
+	Owner       FileOwner
+}
+
 type STSTokenCache struct {
 	AccessKeyId     string    `json:"access_key_id"`
 	AccessKeySecret string    `json:"access_key_secret"`
@@ -24,6 +114,7 @@ type STSTokenCache struct {
 	Expiration      time.Time `json:"expiration"`
 	BucketName      string    `json:"bucket_name"`
 	Region          string    `json:"region"`
+	Scope           STSScope  `json:"scope"`
 	CreatedAt       time.Time `json:"created_at"`
 }
 
@@ -31,15 +122,45 @@ type RefreshToken struct {
 	Token     string    `json:"token"`
 	ExpiresAt time.Time `json:"expires_at"`
 	UserID    string    `json:"user_id"` // 可选，用于关联用户
+	// Scope of the token refreshed, the new token gets the same limits under a new prefix
+	Scope     STSScope  `json:"scope"`
 	CreatedAt time.Time `json:"created_at"`
 }
 type ISysFilesService interface {
-	Create(data *SysFiles) (*SysFiles, error)
-	GetAll() (*[]SysFiles, error)
-	GetByID(id int) (*SysFiles, error)
-	Delete(ids []int64) error
-	Update(id int, userMap map[string]interface{}) (*SysFiles, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[SysFiles], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*SysFiles, error)
+	Upload(ctx context.Context, file UploadFile) (*SysFiles, error)
+	// InstantUpload records a file sharing the stored blob with the same md5 and size, it
+	// returns nil when no such blob exists and the content has to be uploaded
+	InstantUpload(ctx context.Context, md5 string, size int64, originName string, owner FileOwner) (*SysFiles, error)
+	// RegisterObject records an object a client wrote to the storage, an object rejected by the
+	// upload policy is removed
+	RegisterObject(ctx context.Context, object StoredObject) (*SysFiles, error)
+	// Thumbnail opens the rendition of an image in a configured size, the first size when empty.
+	// A missing rendition is rendered from the image.
+	Thumbnail(ctx context.Context, id int, size string, access FileAccess) (*FileRendition, io.ReadCloser, error)
+	// Download opens the content of a file the requester may read
+	Download(ctx context.Context, id int, access FileAccess) (*SysFiles, io.ReadCloser, error)
+	// SignedURL returns a url reading the file without authentication until it expires, a zero
+	// expires takes the configured default
+	SignedURL(ctx context.Context, id int, access FileAccess, expires time.Duration) (string, time.Time, error)
+	// OpenSigned opens a local object from the expires and signature of its signed url
+	OpenSigned(ctx context.Context, key string, expires string, signature string) (io.ReadCloser, error)
+	// OpenPublic opens a local object of a public file or of the rendition of one
+	OpenPublic(ctx context.Context, key string) (io.ReadCloser, error)
+	Create(ctx context.Context, data *SysFiles) (*SysFiles, error)
+	GetAll(ctx context.Context) (*[]SysFiles, error)
+	GetByID(ctx context.Context, id int) (*SysFiles, error)
+	// GetByIDs returns the files that exist among ids, without renditions
+	GetByIDs(ctx context.Context, ids []int64) (*[]SysFiles, error)
+	Delete(ctx context.Context, ids []int64) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*SysFiles, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[SysFiles], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*SysFiles, error)
+	// CollectGarbage removes the blobs and the objects no record references and, with
+	// CollectFiles, the files no entity references
+	CollectGarbage(ctx context.Context, options GCOptions) (*GCReport, error)
+	// UsageByUser returns the usage of each owner, the largest first, or of one user when
+	// userID isn't zero. A zero limit takes the default.
+	UsageByUser(ctx context.Context, userID int64, limit int) (*[]UserStorageUsage, error)
+	UsageByEngine(ctx context.Context) (*[]EngineStorageUsage, error)
 }
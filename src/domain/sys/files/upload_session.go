@@ -0,0 +1,87 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Upload session statuses, a session is completing while its parts are assembled
+const (
+	UploadStatusUploading  = "uploading"
+	UploadStatusCompleting = "completing"
+	UploadStatusCompleted  = "completed"
+)
+
+var (
+	// ErrUploadNotActive is returned for parts sent to a session that is completing or completed
+	ErrUploadNotActive = errors.New("upload is not accepting parts")
+	// ErrUploadOffsetMismatch is returned when an appended part doesn't start at the session offset
+	ErrUploadOffsetMismatch = errors.New("upload offset does not match")
+	// ErrUploadChecksumMismatch is returned when a part or the assembled file fails its checksum
+	ErrUploadChecksumMismatch = errors.New("upload checksum does not match")
+	// ErrUploadIncomplete is returned when completing a session whose parts don't add up to its size
+	ErrUploadIncomplete = errors.New("upload is incomplete")
+	// ErrUploadTooLarge is returned when the parts would add up to more than the session size
+	ErrUploadTooLarge = errors.New("upload parts exceed the declared size")
+)
+
+// UploadSession is a chunked upload in progress. Parts are kept in a temporary area until the
+// session is completed into a SysFiles row, or removed once ExpiresAt passes.
+type UploadSession struct {
+	ID          string `json:"id"`
+	FileName    string `json:"file_name"`
+	FileSize    int64  `json:"file_size"`
+	ContentType string `json:"content_type"`
+	FileMD5     string `json:"file_md5,omitempty"`
+	Offset      int64  `json:"offset"`
+	Status      string `json:"status"`
+	FileID      *int64 `json:"file_id,omitempty"`
+	CreatedBy   int64  `json:"created_by"`
+	// CreatedByRole and Visibility make the owner of the completed file
+	CreatedByRole int64        `json:"created_by_role,omitempty"`
+	Visibility    string       `json:"visibility,omitempty"`
+	ExpiresAt     time.Time    `json:"expires_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	Parts         []UploadPart `json:"parts,omitempty"`
+}
+
+// UploadPart is one stored chunk of an upload session, parts are assembled by PartNumber
+type UploadPart struct {
+	PartNumber int       `json:"part_number"`
+	Size       int64     `json:"size"`
+	MD5        string    `json:"md5"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// InitiateUpload describes the file of a new upload session, FileMD5 is optional and checked
+// against the assembled file
+type InitiateUpload struct {
+	FileName    string
+	FileSize    int64
+	ContentType string
+	FileMD5     string
+	CreatedBy   int64
+	// CreatedByRole and Visibility make the owner of the completed file
+	CreatedByRole int64
+	Visibility    string
+}
+
+type IChunkedUploadService interface {
+	Initiate(ctx context.Context, upload InitiateUpload) (*UploadSession, error)
+	// Get returns the session of userID with its parts
+	Get(ctx context.Context, uploadID string, userID int64) (*UploadSession, error)
+	// UploadPart stores or replaces part partNumber, partMD5 is the hex md5 the content must have
+	UploadPart(ctx context.Context, uploadID string, userID int64, partNumber int, partMD5 string, content io.Reader) (*UploadPart, error)
+	// Append stores the content as the next part, it has to start at offset. Without partMD5
+	// the bytes received before a read error are kept, as resumable clients expect.
+	Append(ctx context.Context, uploadID string, userID int64, offset int64, partMD5 string, content io.Reader) (*UploadSession, error)
+	ListParts(ctx context.Context, uploadID string, userID int64) (*[]UploadPart, error)
+	// Complete assembles the parts into a SysFiles row, fileMD5 is optional
+	Complete(ctx context.Context, uploadID string, userID int64, fileMD5 string) (*SysFiles, error)
+	Abort(ctx context.Context, uploadID string, userID int64) error
+	// CleanExpired removes the sessions past their expiry with their parts
+	CleanExpired(ctx context.Context) (int, error)
+}
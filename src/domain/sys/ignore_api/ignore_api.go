@@ -0,0 +1,37 @@
+package ignore_api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2/util"
+)
+
+// IgnoreApi is a path and method pattern that skips casbin authorization and request body capture.
+// Path uses the casbin keyMatch2 syntax (/v1/dictionary/:type, /v1/public/*), an empty method or "*" matches any method.
+type IgnoreApi struct {
+	ID          int       `json:"id"`
+	Path        string    `json:"path"`
+	Method      string    `json:"method"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Matches reports whether the request path and method fall under the pattern
+func (i IgnoreApi) Matches(path string, method string) bool {
+	if i.Method != "" && i.Method != "*" && !strings.EqualFold(i.Method, method) {
+		return false
+	}
+	return util.KeyMatch2(path, i.Path)
+}
+
+type IIgnoreApiService interface {
+	GetAll(ctx context.Context) (*[]IgnoreApi, error)
+	GetByID(ctx context.Context, id int) (*IgnoreApi, error)
+	Create(ctx context.Context, newIgnoreApi *IgnoreApi) (*IgnoreApi, error)
+	Update(ctx context.Context, id int, ignoreApiMap map[string]interface{}) (*IgnoreApi, error)
+	Delete(ctx context.Context, ids []int) error
+	IsIgnored(path string, method string) bool
+}
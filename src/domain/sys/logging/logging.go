@@ -0,0 +1,34 @@
+package logging
+
+import "context"
+
+// ConfigModule is the sys_config module holding the logging settings
+const ConfigModule = "log"
+
+// sys_config keys of the logging module, a missing key keeps the value the logger started with
+const (
+	KeyLevel           = "log_level"            // default level: debug, info, warn, error
+	KeyComponentLevels = "log_component_levels" // json object, component name to level
+	KeyStdout          = "log_stdout"
+	KeyFileEnabled     = "log_file_enabled"
+	KeyFilePath        = "log_file_path"
+	KeyFileMaxSizeMB   = "log_file_max_size_mb"
+	KeyFileMaxAgeDays  = "log_file_max_age_days"
+	KeyFileMaxBackups  = "log_file_max_backups"
+	KeyFileCompress    = "log_file_compress"
+	KeySink            = "log_sink" // syslog, tcp://host:port, udp://host:port or unix:///path
+)
+
+// Levels is the default level, the per component overrides and the components that log
+type Levels struct {
+	Default    string            `json:"default"`
+	Overrides  map[string]string `json:"overrides"`
+	Components []string          `json:"components"`
+}
+
+type ILoggingService interface {
+	GetLevels() (*Levels, error)
+	SetLevel(component string, level string) (*Levels, error)
+	ResetLevel(component string) (*Levels, error)
+	Reload(ctx context.Context) (*Levels, error)
+}
@@ -1,6 +1,7 @@
 package menu
 
 import (
+	"context"
 	"github.com/gbrayhan/microservices-go/src/domain"
 	menuBtnDomain "github.com/gbrayhan/microservices-go/src/domain/sys/menu_btn"
 	menuParameterDomain "github.com/gbrayhan/microservices-go/src/domain/sys/menu_parameter"
@@ -44,11 +45,11 @@ type MenuGroup struct {
 }
 
 type IMenuService interface {
-	GetAll(groupId int) ([]*Menu, error)
-	GetByID(id int) (*Menu, error)
-	Create(newMenu *Menu) (*Menu, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*Menu, error)
-	GetOneByMap(userMap map[string]interface{}) (*Menu, error)
-	GetUserMenus(roleId int64) ([]*MenuGroup, error)
+	GetAll(ctx context.Context, groupId int) ([]*Menu, error)
+	GetByID(ctx context.Context, id int) (*Menu, error)
+	Create(ctx context.Context, newMenu *Menu) (*Menu, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, actorId int64, userMap map[string]interface{}) (*Menu, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*Menu, error)
+	GetUserMenus(ctx context.Context, roleId int64) ([]*MenuGroup, error)
 }
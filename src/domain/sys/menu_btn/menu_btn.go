@@ -1,6 +1,7 @@
 package menu_btn
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -16,12 +17,14 @@ type MenuBtn struct {
 }
 
 type IMenuBtnService interface {
-	GetAll(menuID int64) (*[]MenuBtn, error)
-	GetByID(id int) (*MenuBtn, error)
-	Create(newMenu *MenuBtn) (*MenuBtn, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*MenuBtn, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[MenuBtn], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*MenuBtn, error)
+	GetAll(ctx context.Context, menuID int64) (*[]MenuBtn, error)
+	GetByID(ctx context.Context, id int) (*MenuBtn, error)
+	Create(ctx context.Context, newMenu *MenuBtn) (*MenuBtn, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*MenuBtn, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[MenuBtn], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*MenuBtn, error)
+	GetBtnApiIds(ctx context.Context, id int) ([]int64, error)
+	BindBtnApis(ctx context.Context, id int, updateMap map[string]interface{}) error
 }
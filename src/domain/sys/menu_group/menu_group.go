@@ -1,6 +1,7 @@
 package menu_group
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -19,12 +20,12 @@ type MenuGroup struct {
 }
 
 type IMenuGroupService interface {
-	GetAll() (*[]MenuGroup, error)
-	GetByID(id int) (*MenuGroup, error)
-	Create(newMenuGroup *MenuGroup) (*MenuGroup, error)
-	Delete(ids []int) error
-	Update(id int, userMap map[string]interface{}) (*MenuGroup, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[MenuGroup], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*MenuGroup, error)
+	GetAll(ctx context.Context) (*[]MenuGroup, error)
+	GetByID(ctx context.Context, id int) (*MenuGroup, error)
+	Create(ctx context.Context, newMenuGroup *MenuGroup) (*MenuGroup, error)
+	Delete(ctx context.Context, ids []int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*MenuGroup, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[MenuGroup], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*MenuGroup, error)
 }
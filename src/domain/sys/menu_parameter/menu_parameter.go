@@ -1,6 +1,7 @@
 package menu_parameter
 
 import (
+	"context"
 	"time"
 )
 
@@ -15,10 +16,10 @@ type MenuParameter struct {
 }
 
 type IMenuParameterService interface {
-	GetAll(menuID int64) (*[]MenuParameter, error)
-	GetByID(id int) (*MenuParameter, error)
-	Create(newMenu *MenuParameter) (*MenuParameter, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*MenuParameter, error)
-	GetOneByMap(userMap map[string]interface{}) (*MenuParameter, error)
+	GetAll(ctx context.Context, menuID int64) (*[]MenuParameter, error)
+	GetByID(ctx context.Context, id int) (*MenuParameter, error)
+	Create(ctx context.Context, newMenu *MenuParameter) (*MenuParameter, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*MenuParameter, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*MenuParameter, error)
 }
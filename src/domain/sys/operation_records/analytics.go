@@ -0,0 +1,82 @@
+package operation_records
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Time bucket sizes for the error rate series
+const (
+	BucketMinute = "minute"
+	BucketHour   = "hour"
+	BucketDay    = "day"
+)
+
+// MaxAnalyticsLimit bounds the ranked lists
+const MaxAnalyticsLimit = 500
+
+// AnalyticsQuery selects the records created in [Start, End), Limit bounds the ranked lists
+type AnalyticsQuery struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Limit  int       `json:"limit"`
+	Bucket string    `json:"bucket"`
+}
+
+// Validate checks the period and the limit, and the bucket when one is set
+func (q AnalyticsQuery) Validate() error {
+	if q.Start.IsZero() || !q.End.After(q.Start) {
+		return errors.New("start must be before end")
+	}
+	if q.Limit < 1 || q.Limit > MaxAnalyticsLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxAnalyticsLimit)
+	}
+	if q.Bucket != "" && !IsValidBucket(q.Bucket) {
+		return errors.New("bucket must be minute, hour or day")
+	}
+	return nil
+}
+
+// IsValidBucket reports whether the bucket is one of the supported sizes
+func IsValidBucket(bucket string) bool {
+	return bucket == BucketMinute || bucket == BucketHour || bucket == BucketDay
+}
+
+// RouteLatency holds the latency percentiles in milliseconds of one route. Routes are the
+// request paths without query string and with numeric segments collapsed to :id.
+type RouteLatency struct {
+	Method string  `json:"method"`
+	Route  string  `json:"route"`
+	Count  int64   `json:"count"`
+	P50    float64 `json:"p50"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+}
+
+// ErrorRateBucket counts the requests of one time bucket by outcome
+type ErrorRateBucket struct {
+	Bucket       time.Time `json:"bucket"`
+	Total        int64     `json:"total"`
+	ClientErrors int64     `json:"client_errors"`
+	ServerErrors int64     `json:"server_errors"`
+	ErrorRate    float64   `json:"error_rate"`
+}
+
+// UserActivity is a user's request count and rank within the period
+type UserActivity struct {
+	Rank   int64   `json:"rank"`
+	UserID int64   `json:"user_id"`
+	Count  int64   `json:"count"`
+	Share  float64 `json:"share"`
+}
+
+// SlowEndpoint ranks routes by average latency
+type SlowEndpoint struct {
+	Rank       int64   `json:"rank"`
+	Method     string  `json:"method"`
+	Route      string  `json:"route"`
+	Count      int64   `json:"count"`
+	AvgLatency float64 `json:"avg_latency"`
+	MaxLatency int64   `json:"max_latency"`
+}
@@ -0,0 +1,63 @@
+package operation_records
+
+import (
+	"strconv"
+	"time"
+)
+
+// ExportRecord is the flat representation used by archives and exports, one JSON object per
+// line for JSONL or one row for CSV
+type ExportRecord struct {
+	ID           int       `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UserID       int64     `json:"user_id"`
+	IP           string    `json:"ip"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Status       int64     `json:"status"`
+	Latency      int64     `json:"latency"`
+	Agent        string    `json:"agent"`
+	ErrorMessage string    `json:"error_message"`
+	Body         string    `json:"body"`
+	Resp         string    `json:"resp"`
+	RequestID    string    `json:"request_id"`
+}
+
+// CSVHeader lists the columns in the order of ExportRecord.CSVRow
+var CSVHeader = []string{"id", "created_at", "user_id", "ip", "method", "path", "status", "latency", "agent", "error_message", "body", "resp", "request_id"}
+
+func (r *SysOperationRecord) ToExport() ExportRecord {
+	return ExportRecord{
+		ID:           r.ID,
+		CreatedAt:    r.CreatedAt.Time,
+		UserID:       r.UserID,
+		IP:           r.IP,
+		Method:       r.Method,
+		Path:         r.Path,
+		Status:       r.Status,
+		Latency:      r.Latency,
+		Agent:        r.Agent,
+		ErrorMessage: r.ErrorMessage,
+		Body:         r.Body,
+		Resp:         r.Resp,
+		RequestID:    r.RequestID,
+	}
+}
+
+func (r ExportRecord) CSVRow() []string {
+	return []string{
+		strconv.Itoa(r.ID),
+		r.CreatedAt.Format(time.RFC3339),
+		strconv.FormatInt(r.UserID, 10),
+		r.IP,
+		r.Method,
+		r.Path,
+		strconv.FormatInt(r.Status, 10),
+		strconv.FormatInt(r.Latency, 10),
+		r.Agent,
+		r.ErrorMessage,
+		r.Body,
+		r.Resp,
+		r.RequestID,
+	}
+}
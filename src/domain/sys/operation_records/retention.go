@@ -0,0 +1,75 @@
+package operation_records
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2/util"
+)
+
+// RetentionPolicy keeps the operation records matching Path and Status for RetentionDays.
+// Path uses the casbin keyMatch2 syntax (/v1/auth/*), Status is an exact code ("404") or a
+// class ("5xx"); empty fields match every record. A RetentionDays of 0 keeps the records forever.
+type RetentionPolicy struct {
+	Path          string `json:"path"`
+	Status        string `json:"status"`
+	RetentionDays int    `json:"retention_days"`
+}
+
+// RetentionConfig holds the policies in priority order, the first matching policy wins and
+// records matching none are kept for DefaultRetentionDays
+type RetentionConfig struct {
+	Policies             []RetentionPolicy `json:"policies"`
+	DefaultRetentionDays int               `json:"default_retention_days"`
+	BatchSize            int               `json:"batch_size"`
+}
+
+// Matches reports whether the record falls under the policy
+func (p RetentionPolicy) Matches(record *SysOperationRecord) bool {
+	if p.Path != "" {
+		path, _, _ := strings.Cut(record.Path, "?")
+		if !util.KeyMatch2(path, p.Path) {
+			return false
+		}
+	}
+	if p.Status == "" {
+		return true
+	}
+	status := strings.ToLower(p.Status)
+	if class, ok := strings.CutSuffix(status, "xx"); ok {
+		return strconv.FormatInt(record.Status/100, 10) == class
+	}
+	return strconv.FormatInt(record.Status, 10) == status
+}
+
+// RetentionFor returns the number of days the record is kept
+func (c RetentionConfig) RetentionFor(record *SysOperationRecord) int {
+	for _, policy := range c.Policies {
+		if policy.Matches(record) {
+			return policy.RetentionDays
+		}
+	}
+	return c.DefaultRetentionDays
+}
+
+// MinRetentionDays is the shortest limited retention of all policies, records younger than that
+// are never expired. It returns 0 when every policy keeps its records forever.
+func (c RetentionConfig) MinRetentionDays() int {
+	days := c.DefaultRetentionDays
+	for _, policy := range c.Policies {
+		if policy.RetentionDays > 0 && (days <= 0 || policy.RetentionDays < days) {
+			days = policy.RetentionDays
+		}
+	}
+	return max(days, 0)
+}
+
+// Expired reports whether the record has outlived its retention at the given time
+func (c RetentionConfig) Expired(record *SysOperationRecord, now time.Time) bool {
+	days := c.RetentionFor(record)
+	if days <= 0 {
+		return false
+	}
+	return record.CreatedAt.Before(now.AddDate(0, 0, -days))
+}
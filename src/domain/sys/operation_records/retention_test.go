@@ -0,0 +1,41 @@
+package operation_records
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gbrayhan/microservices-go/src/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionConfig_FirstMatchingPolicyWins(t *testing.T) {
+	config := RetentionConfig{
+		Policies: []RetentionPolicy{
+			{Status: "5xx", RetentionDays: 365},
+			{Path: "/v1/auth/*", RetentionDays: 7},
+			{Path: "/v1/user/:id", Status: "404", RetentionDays: 1},
+		},
+		DefaultRetentionDays: 90,
+	}
+
+	assert.Equal(t, 365, config.RetentionFor(&SysOperationRecord{Path: "/v1/auth/signin", Status: 502}))
+	assert.Equal(t, 7, config.RetentionFor(&SysOperationRecord{Path: "/v1/auth/signin?x=1", Status: 200}))
+	assert.Equal(t, 1, config.RetentionFor(&SysOperationRecord{Path: "/v1/user/12", Status: 404}))
+	assert.Equal(t, 90, config.RetentionFor(&SysOperationRecord{Path: "/v1/user/12", Status: 200}))
+	assert.Equal(t, 1, config.MinRetentionDays())
+}
+
+func TestRetentionConfig_Expired(t *testing.T) {
+	now := time.Now()
+	config := RetentionConfig{
+		Policies:             []RetentionPolicy{{Status: "4xx", RetentionDays: 0}},
+		DefaultRetentionDays: 30,
+	}
+	old := domain.CustomTime{Time: now.AddDate(0, 0, -31)}
+
+	assert.True(t, config.Expired(&SysOperationRecord{Status: 200, CreatedAt: old}, now))
+	assert.False(t, config.Expired(&SysOperationRecord{Status: 200, CreatedAt: domain.CustomTime{Time: now}}, now))
+	// a retention of 0 keeps the records forever
+	assert.False(t, config.Expired(&SysOperationRecord{Status: 401, CreatedAt: old}, now))
+	assert.Equal(t, 30, config.MinRetentionDays())
+}
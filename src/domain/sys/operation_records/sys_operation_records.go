@@ -1,6 +1,7 @@
 package operation_records
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -18,17 +19,19 @@ type SysOperationRecord struct {
 	Body         string
 	Resp         string
 	UserID       int64
+	RequestID    string
 	CreatedAt    domain.CustomTime
 	UpdatedAt    domain.CustomTime
 	DeletedAt    time.Time
 }
 type ISysOperationRecordService interface {
-	GetAll() (*[]SysOperationRecord, error)
-	GetByID(id int) (*SysOperationRecord, error)
-	Create(newSysOperationRecord *SysOperationRecord) (*SysOperationRecord, error)
-	Delete(ids []int) error
-	Update(id int, userMap map[string]interface{}) (*SysOperationRecord, error)
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[SysOperationRecord], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*SysOperationRecord, error)
+	GetAll(ctx context.Context) (*[]SysOperationRecord, error)
+	GetByID(ctx context.Context, id int) (*SysOperationRecord, error)
+	Create(ctx context.Context, newSysOperationRecord *SysOperationRecord) (*SysOperationRecord, error)
+	Delete(ctx context.Context, ids []int) error
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*SysOperationRecord, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[SysOperationRecord], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*SysOperationRecord, error)
+	Export(ctx context.Context, start, end time.Time, fn func(record *SysOperationRecord) error) error
 }
@@ -0,0 +1,366 @@
+package rbac
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type ChangeAction string
+
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeDelete ChangeAction = "delete"
+)
+
+const (
+	EntityApi           = "api"
+	EntityMenuGroup     = "menu_group"
+	EntityMenu          = "menu"
+	EntityMenuBtn       = "menu_btn"
+	EntityMenuParameter = "menu_parameter"
+	EntityMenuBtnApi    = "menu_btn_api"
+	EntityRole          = "role"
+	EntityRoleMenu      = "role_menu"
+	EntityRoleBtn       = "role_btn"
+	EntityPolicy        = "policy"
+)
+
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+type Change struct {
+	Entity string                 `json:"entity"`
+	Key    string                 `json:"key"`
+	Action ChangeAction           `json:"action"`
+	Fields map[string]FieldChange `json:"fields,omitempty"`
+}
+
+func (r ApiRef) Key() string {
+	return r.Method + " " + r.Path
+}
+
+func (b ButtonRef) Key() string {
+	return b.Menu + "/" + b.Button
+}
+
+// Diff compares the current configuration with the incoming document.
+// Entities are only deleted when prune is set, while relation sets (role menus, role buttons,
+// policies and button apis) are replaced for every role and button present in the document.
+func Diff(current *Document, incoming *Document, prune bool) []Change {
+	changes := make([]Change, 0)
+
+	changes = append(changes, diffEntities(EntityApi, current.Apis, incoming.Apis, prune,
+		func(a Api) string { return ApiRef{Path: a.Path, Method: a.Method}.Key() },
+		func(a Api) map[string]any {
+			return map[string]any{"api_group": a.ApiGroup, "description": a.Description}
+		})...)
+
+	changes = append(changes, diffEntities(EntityMenuGroup, current.MenuGroups, incoming.MenuGroups, prune,
+		func(g MenuGroup) string { return g.Name },
+		func(g MenuGroup) map[string]any {
+			return map[string]any{"path": g.Path, "sort": g.Sort, "status": g.Status}
+		})...)
+
+	changes = append(changes, diffEntities(EntityMenu, current.Menus, incoming.Menus, prune,
+		func(m Menu) string { return m.Name },
+		func(m Menu) map[string]any {
+			return map[string]any{
+				"parent": m.Parent, "group": m.Group, "path": m.Path, "menu_level": m.MenuLevel,
+				"hidden": m.Hidden, "component": m.Component, "sort": m.Sort,
+				"keep_alive": m.KeepAlive, "title": m.Title, "icon": m.Icon,
+			}
+		})...)
+
+	currentBtns, currentParams := flattenMenuChildren(current.Menus)
+	incomingBtns, incomingParams := flattenMenuChildren(incoming.Menus)
+	changes = append(changes, diffEntities(EntityMenuBtn, currentBtns, incomingBtns, prune,
+		func(b menuBtnEntry) string { return b.key() },
+		func(b menuBtnEntry) map[string]any { return map[string]any{"desc": b.Desc} })...)
+	changes = append(changes, diffEntities(EntityMenuParameter, currentParams, incomingParams, prune,
+		func(p menuParameterEntry) string { return p.key() },
+		func(p menuParameterEntry) map[string]any { return map[string]any{"value": p.Value} })...)
+
+	currentBtnApis := make(map[string][]string, len(currentBtns))
+	for _, btn := range currentBtns {
+		currentBtnApis[btn.key()] = apiRefKeys(btn.Apis)
+	}
+	for _, btn := range incomingBtns {
+		changes = append(changes, diffSet(EntityMenuBtnApi, btn.key(), currentBtnApis[btn.key()], apiRefKeys(btn.Apis))...)
+	}
+
+	changes = append(changes, diffEntities(EntityRole, current.Roles, incoming.Roles, prune,
+		func(r Role) string { return r.Name },
+		func(r Role) map[string]any {
+			return map[string]any{
+				"parent": r.Parent, "default_router": r.DefaultRouter, "status": r.Status,
+				"order": r.Order, "label": r.Label, "description": r.Description,
+			}
+		})...)
+
+	currentRoles := make(map[string]Role, len(current.Roles))
+	for _, role := range current.Roles {
+		currentRoles[role.Name] = role
+	}
+	for _, role := range incoming.Roles {
+		existing := currentRoles[role.Name]
+		changes = append(changes, diffSet(EntityRoleMenu, role.Name, existing.Menus, role.Menus)...)
+		changes = append(changes, diffSet(EntityRoleBtn, role.Name, buttonRefKeys(existing.Buttons), buttonRefKeys(role.Buttons))...)
+		changes = append(changes, diffSet(EntityPolicy, role.Name, apiRefKeys(existing.Policies), apiRefKeys(role.Policies))...)
+	}
+
+	return changes
+}
+
+// Summarize counts the changes per entity and action, e.g. "menu.create"
+func Summarize(changes []Change) map[string]int {
+	summary := make(map[string]int)
+	for _, change := range changes {
+		summary[fmt.Sprintf("%s.%s", change.Entity, change.Action)]++
+	}
+	return summary
+}
+
+type menuBtnEntry struct {
+	Menu string
+	MenuBtn
+}
+
+func (b menuBtnEntry) key() string {
+	return ButtonRef{Menu: b.Menu, Button: b.Name}.Key()
+}
+
+type menuParameterEntry struct {
+	Menu string
+	MenuParameter
+}
+
+func (p menuParameterEntry) key() string {
+	return fmt.Sprintf("%s/%s:%s", p.Menu, p.Type, p.Key)
+}
+
+func flattenMenuChildren(menus []Menu) ([]menuBtnEntry, []menuParameterEntry) {
+	btns := make([]menuBtnEntry, 0)
+	params := make([]menuParameterEntry, 0)
+	for _, menu := range menus {
+		for _, btn := range menu.Buttons {
+			btns = append(btns, menuBtnEntry{Menu: menu.Name, MenuBtn: btn})
+		}
+		for _, param := range menu.Parameters {
+			params = append(params, menuParameterEntry{Menu: menu.Name, MenuParameter: param})
+		}
+	}
+	return btns, params
+}
+
+func diffEntities[T any](entity string, current []T, incoming []T, prune bool,
+	key func(T) string, fields func(T) map[string]any) []Change {
+	changes := make([]Change, 0)
+	currentMap := make(map[string]T, len(current))
+	for _, item := range current {
+		currentMap[key(item)] = item
+	}
+	incomingKeys := make(map[string]bool, len(incoming))
+	for _, item := range incoming {
+		k := key(item)
+		incomingKeys[k] = true
+		existing, ok := currentMap[k]
+		if !ok {
+			changes = append(changes, Change{Entity: entity, Key: k, Action: ChangeCreate})
+			continue
+		}
+		fieldChanges := diffFields(fields(existing), fields(item))
+		if len(fieldChanges) > 0 {
+			changes = append(changes, Change{Entity: entity, Key: k, Action: ChangeUpdate, Fields: fieldChanges})
+		}
+	}
+	if prune {
+		removed := make([]string, 0)
+		for k := range currentMap {
+			if !incomingKeys[k] {
+				removed = append(removed, k)
+			}
+		}
+		sort.Strings(removed)
+		for _, k := range removed {
+			changes = append(changes, Change{Entity: entity, Key: k, Action: ChangeDelete})
+		}
+	}
+	return changes
+}
+
+func diffFields(from map[string]any, to map[string]any) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	for name, value := range to {
+		if !reflect.DeepEqual(from[name], value) {
+			changes[name] = FieldChange{From: from[name], To: value}
+		}
+	}
+	return changes
+}
+
+func diffSet(entity string, owner string, current []string, incoming []string) []Change {
+	changes := make([]Change, 0)
+	currentSet := make(map[string]bool, len(current))
+	for _, item := range current {
+		currentSet[item] = true
+	}
+	incomingSet := make(map[string]bool, len(incoming))
+	for _, item := range incoming {
+		incomingSet[item] = true
+	}
+	added := make([]string, 0)
+	for item := range incomingSet {
+		if !currentSet[item] {
+			added = append(added, item)
+		}
+	}
+	removed := make([]string, 0)
+	for item := range currentSet {
+		if !incomingSet[item] {
+			removed = append(removed, item)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	for _, item := range added {
+		changes = append(changes, Change{Entity: entity, Key: owner + " -> " + item, Action: ChangeCreate})
+	}
+	for _, item := range removed {
+		changes = append(changes, Change{Entity: entity, Key: owner + " -> " + item, Action: ChangeDelete})
+	}
+	return changes
+}
+
+func apiRefKeys(refs []ApiRef) []string {
+	keys := make([]string, len(refs))
+	for i, ref := range refs {
+		keys[i] = ref.Key()
+	}
+	return keys
+}
+
+func buttonRefKeys(refs []ButtonRef) []string {
+	keys := make([]string, len(refs))
+	for i, ref := range refs {
+		keys[i] = ref.Key()
+	}
+	return keys
+}
+
+// duplicateKey returns the first key listed twice, or an empty string
+func duplicateKey(keys []string) string {
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			return key
+		}
+		seen[key] = true
+	}
+	return ""
+}
+
+// Validate checks that every entity is defined once and every reference in the document points
+// to an entity it defines
+func (d *Document) Validate() error {
+	if d.Version != DocumentVersion {
+		return fmt.Errorf("unsupported document version %q", d.Version)
+	}
+	apis := make(map[string]bool, len(d.Apis))
+	for _, api := range d.Apis {
+		if api.Path == "" || api.Method == "" {
+			return fmt.Errorf("api path and method are required")
+		}
+		key := ApiRef{Path: api.Path, Method: api.Method}.Key()
+		if apis[key] {
+			return fmt.Errorf("duplicate api %q", key)
+		}
+		apis[key] = true
+	}
+	groups := make(map[string]bool, len(d.MenuGroups))
+	for _, group := range d.MenuGroups {
+		if group.Name == "" {
+			return fmt.Errorf("menu group name is required")
+		}
+		if groups[group.Name] {
+			return fmt.Errorf("duplicate menu group %q", group.Name)
+		}
+		groups[group.Name] = true
+	}
+	menus := make(map[string]map[string]bool, len(d.Menus))
+	for _, menu := range d.Menus {
+		if menu.Name == "" {
+			return fmt.Errorf("menu name is required")
+		}
+		if _, ok := menus[menu.Name]; ok {
+			return fmt.Errorf("duplicate menu %q", menu.Name)
+		}
+		btns := make(map[string]bool, len(menu.Buttons))
+		for _, btn := range menu.Buttons {
+			if btn.Name == "" {
+				return fmt.Errorf("button name of menu %q is required", menu.Name)
+			}
+			if btns[btn.Name] {
+				return fmt.Errorf("duplicate button %q of menu %q", btn.Name, menu.Name)
+			}
+			btns[btn.Name] = true
+			if key := duplicateKey(apiRefKeys(btn.Apis)); key != "" {
+				return fmt.Errorf("button %q of menu %q references api %q twice", btn.Name, menu.Name, key)
+			}
+			for _, ref := range btn.Apis {
+				if !apis[ref.Key()] {
+					return fmt.Errorf("button %q of menu %q references unknown api %q", btn.Name, menu.Name, ref.Key())
+				}
+			}
+		}
+		menus[menu.Name] = btns
+	}
+	for _, menu := range d.Menus {
+		if menu.Parent != "" {
+			if _, ok := menus[menu.Parent]; !ok {
+				return fmt.Errorf("menu %q references unknown parent %q", menu.Name, menu.Parent)
+			}
+		}
+		if menu.Group != "" && !groups[menu.Group] {
+			return fmt.Errorf("menu %q references unknown group %q", menu.Name, menu.Group)
+		}
+	}
+	roles := make(map[string]bool, len(d.Roles))
+	for _, role := range d.Roles {
+		if role.Name == "" {
+			return fmt.Errorf("role name is required")
+		}
+		if roles[role.Name] {
+			return fmt.Errorf("duplicate role %q", role.Name)
+		}
+		roles[role.Name] = true
+	}
+	for _, role := range d.Roles {
+		if role.Parent != "" && !roles[role.Parent] {
+			return fmt.Errorf("role %q references unknown parent %q", role.Name, role.Parent)
+		}
+		if key := duplicateKey(role.Menus); key != "" {
+			return fmt.Errorf("role %q references menu %q twice", role.Name, key)
+		}
+		if key := duplicateKey(buttonRefKeys(role.Buttons)); key != "" {
+			return fmt.Errorf("role %q references button %q twice", role.Name, key)
+		}
+		if key := duplicateKey(apiRefKeys(role.Policies)); key != "" {
+			return fmt.Errorf("role %q references policy %q twice", role.Name, key)
+		}
+		for _, menu := range role.Menus {
+			if _, ok := menus[menu]; !ok {
+				return fmt.Errorf("role %q references unknown menu %q", role.Name, menu)
+			}
+		}
+		for _, btn := range role.Buttons {
+			if !menus[btn.Menu][btn.Button] {
+				return fmt.Errorf("role %q references unknown button %q", role.Name, btn.Key())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package rbac
+
+import (
+	"testing"
+)
+
+func sampleDocument() *Document {
+	return &Document{
+		Version: DocumentVersion,
+		Apis: []Api{
+			{Path: "/v1/user/:id", Method: "GET", ApiGroup: "user"},
+			{Path: "/v1/user", Method: "POST", ApiGroup: "user"},
+		},
+		MenuGroups: []MenuGroup{{Name: "system", Path: "/system"}},
+		Menus: []Menu{
+			{Name: "user", Group: "system", Path: "/user", Title: "Users",
+				Buttons: []MenuBtn{{Name: "add", Apis: []ApiRef{{Path: "/v1/user", Method: "POST"}}}}},
+		},
+		Roles: []Role{
+			{Name: "admin", Menus: []string{"user"},
+				Buttons:  []ButtonRef{{Menu: "user", Button: "add"}},
+				Policies: []ApiRef{{Path: "/v1/user/:id", Method: "GET"}}},
+		},
+	}
+}
+
+func countChanges(changes []Change, entity string, action ChangeAction) int {
+	count := 0
+	for _, change := range changes {
+		if change.Entity == entity && change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	changes := Diff(sampleDocument(), sampleDocument(), true)
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestDiff_CreateAndUpdate(t *testing.T) {
+	current := sampleDocument()
+	incoming := sampleDocument()
+	incoming.Menus[0].Title = "Members"
+	incoming.Roles = append(incoming.Roles, Role{Name: "viewer", Menus: []string{"user"}})
+
+	changes := Diff(current, incoming, false)
+	if countChanges(changes, EntityMenu, ChangeUpdate) != 1 {
+		t.Errorf("Expected one menu update, got %v", changes)
+	}
+	for _, change := range changes {
+		if change.Entity == EntityMenu && change.Fields["title"].To != "Members" {
+			t.Errorf("Expected title to change to Members, got %v", change.Fields["title"])
+		}
+	}
+	if countChanges(changes, EntityRole, ChangeCreate) != 1 {
+		t.Errorf("Expected one role create, got %v", changes)
+	}
+	if countChanges(changes, EntityRoleMenu, ChangeCreate) != 1 {
+		t.Errorf("Expected one role menu create, got %v", changes)
+	}
+}
+
+func TestDiff_PruneOnlyDeletesWhenRequested(t *testing.T) {
+	current := sampleDocument()
+	incoming := sampleDocument()
+	incoming.Apis = incoming.Apis[:1]
+	incoming.Menus[0].Buttons[0].Apis = nil
+
+	if countChanges(Diff(current, incoming, false), EntityApi, ChangeDelete) != 0 {
+		t.Error("Expected no api deletes without prune")
+	}
+	changes := Diff(current, incoming, true)
+	if countChanges(changes, EntityApi, ChangeDelete) != 1 {
+		t.Errorf("Expected one api delete with prune, got %v", changes)
+	}
+	// relation sets are replaced regardless of prune
+	if countChanges(Diff(current, incoming, false), EntityMenuBtnApi, ChangeDelete) != 1 {
+		t.Error("Expected button api binding to be removed")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summary := Summarize([]Change{
+		{Entity: EntityRole, Action: ChangeCreate},
+		{Entity: EntityRole, Action: ChangeCreate},
+		{Entity: EntityPolicy, Action: ChangeDelete},
+	})
+	if summary["role.create"] != 2 || summary["policy.delete"] != 1 {
+		t.Errorf("Unexpected summary %v", summary)
+	}
+}
+
+func TestDocument_Validate(t *testing.T) {
+	if err := sampleDocument().Validate(); err != nil {
+		t.Errorf("Expected valid document, got %v", err)
+	}
+
+	doc := sampleDocument()
+	doc.Version = "0"
+	if err := doc.Validate(); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+
+	doc = sampleDocument()
+	doc.Roles[0].Buttons = []ButtonRef{{Menu: "user", Button: "delete"}}
+	if err := doc.Validate(); err == nil {
+		t.Error("Expected error for unknown button")
+	}
+
+	doc = sampleDocument()
+	doc.Menus[0].Buttons[0].Apis = []ApiRef{{Path: "/v1/missing", Method: "GET"}}
+	if err := doc.Validate(); err == nil {
+		t.Error("Expected error for unknown api")
+	}
+
+	doc = sampleDocument()
+	doc.Menus = append(doc.Menus, Menu{Name: "user"})
+	if err := doc.Validate(); err == nil {
+		t.Error("Expected error for duplicate menu")
+	}
+
+	doc = sampleDocument()
+	doc.Apis = append(doc.Apis, Api{Path: "/v1/user", Method: "POST", ApiGroup: "other"})
+	if err := doc.Validate(); err == nil {
+		t.Error("Expected error for duplicate api")
+	}
+
+	doc = sampleDocument()
+	doc.Menus[0].Buttons = append(doc.Menus[0].Buttons, MenuBtn{Name: "add"})
+	if err := doc.Validate(); err == nil {
+		t.Error("Expected error for duplicate button")
+	}
+
+	doc = sampleDocument()
+	doc.Roles[0].Buttons = append(doc.Roles[0].Buttons, ButtonRef{Menu: "user", Button: "add"})
+	if err := doc.Validate(); err == nil {
+		t.Error("Expected error for duplicate role button")
+	}
+
+	doc = sampleDocument()
+	doc.Menus[0].Buttons[0].Apis = append(doc.Menus[0].Buttons[0].Apis, ApiRef{Path: "/v1/user", Method: "POST"})
+	if err := doc.Validate(); err == nil {
+		t.Error("Expected error for duplicate button api")
+	}
+}
@@ -0,0 +1,103 @@
+package rbac
+
+import (
+	"context"
+	"time"
+)
+
+// DocumentVersion is the version written by exports and accepted by imports
+const DocumentVersion = "1"
+
+// Document holds the full rbac configuration keyed by natural keys instead of database ids.
+// Menu groups and menus are keyed by name, apis by path and method, roles by name.
+type Document struct {
+	Version    string      `json:"version" yaml:"version"`
+	ExportedAt time.Time   `json:"exported_at" yaml:"exported_at"`
+	Apis       []Api       `json:"apis" yaml:"apis"`
+	MenuGroups []MenuGroup `json:"menu_groups" yaml:"menu_groups"`
+	Menus      []Menu      `json:"menus" yaml:"menus"`
+	Roles      []Role      `json:"roles" yaml:"roles"`
+}
+
+type ApiRef struct {
+	Path   string `json:"path" yaml:"path"`
+	Method string `json:"method" yaml:"method"`
+}
+
+type Api struct {
+	Path        string `json:"path" yaml:"path"`
+	Method      string `json:"method" yaml:"method"`
+	ApiGroup    string `json:"api_group" yaml:"api_group"`
+	Description string `json:"description" yaml:"description"`
+}
+
+type MenuGroup struct {
+	Name   string `json:"name" yaml:"name"`
+	Path   string `json:"path" yaml:"path"`
+	Sort   int8   `json:"sort" yaml:"sort"`
+	Status int16  `json:"status" yaml:"status"`
+}
+
+type Menu struct {
+	Name       string          `json:"name" yaml:"name"`
+	Parent     string          `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Group      string          `json:"group,omitempty" yaml:"group,omitempty"`
+	Path       string          `json:"path" yaml:"path"`
+	MenuLevel  int             `json:"menu_level" yaml:"menu_level"`
+	Hidden     bool            `json:"hidden" yaml:"hidden"`
+	Component  string          `json:"component" yaml:"component"`
+	Sort       int8            `json:"sort" yaml:"sort"`
+	KeepAlive  int16           `json:"keep_alive" yaml:"keep_alive"`
+	Title      string          `json:"title" yaml:"title"`
+	Icon       string          `json:"icon" yaml:"icon"`
+	Buttons    []MenuBtn       `json:"buttons,omitempty" yaml:"buttons,omitempty"`
+	Parameters []MenuParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+type MenuBtn struct {
+	Name string   `json:"name" yaml:"name"`
+	Desc string   `json:"desc" yaml:"desc"`
+	Apis []ApiRef `json:"apis,omitempty" yaml:"apis,omitempty"`
+}
+
+type MenuParameter struct {
+	Type  string `json:"type" yaml:"type"`
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+type ButtonRef struct {
+	Menu   string `json:"menu" yaml:"menu"`
+	Button string `json:"button" yaml:"button"`
+}
+
+type Role struct {
+	Name          string      `json:"name" yaml:"name"`
+	Parent        string      `json:"parent,omitempty" yaml:"parent,omitempty"`
+	DefaultRouter string      `json:"default_router" yaml:"default_router"`
+	Status        int16       `json:"status" yaml:"status"`
+	Order         int64       `json:"order" yaml:"order"`
+	Label         string      `json:"label" yaml:"label"`
+	Description   string      `json:"description" yaml:"description"`
+	Menus         []string    `json:"menus,omitempty" yaml:"menus,omitempty"`
+	Buttons       []ButtonRef `json:"buttons,omitempty" yaml:"buttons,omitempty"`
+	Policies      []ApiRef    `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+type ImportOptions struct {
+	DryRun bool `json:"dry_run"`
+	// Prune deletes entities that exist in the database but not in the document
+	Prune bool `json:"prune"`
+}
+
+type ImportResult struct {
+	DryRun  bool           `json:"dry_run"`
+	Applied bool           `json:"applied"`
+	Changes []Change       `json:"changes"`
+	Summary map[string]int `json:"summary"`
+}
+
+type IRbacService interface {
+	Export(ctx context.Context) (*Document, error)
+	Import(ctx context.Context, doc *Document, options ImportOptions) (*ImportResult, error)
+}
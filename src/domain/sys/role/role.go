@@ -1,6 +1,7 @@
 package role
 
 import (
+	"context"
 	"github.com/gbrayhan/microservices-go/src/domain"
 )
 
@@ -48,18 +49,18 @@ type RoleTree struct {
 }
 
 type IRoleService interface {
-	GetAll(status int) ([]*RoleTree, error)
-	GetByID(id int) (*Role, error)
-	Create(newRole *Role) (*Role, error)
-	Delete(id int) error
-	Update(id int, userMap map[string]interface{}) (*Role, error)
-	SearchPaginated(filters domain.DataFilters) (*SearchResultRole, error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*Role, error)
-	GetTreeRoles(status int) (*RoleNode, error)
-	GetRoleMenuIds(id int64) (map[int][]int, map[int64][]int64, error)
-	UpdateRoleMenuIds(id int, updateMap map[string]any) error
-	GetApiRuleList(roleId int) ([]string, error)
-	BindApiRule(roleId int, updateMap map[string]interface{}) error
-	BindRoleMenuBtns(roleId int64, updateMap map[string]interface{}) error
+	GetAll(ctx context.Context, status int) ([]*RoleTree, error)
+	GetByID(ctx context.Context, id int) (*Role, error)
+	Create(ctx context.Context, newRole *Role) (*Role, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, actorId int64, userMap map[string]interface{}) (*Role, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*SearchResultRole, error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*Role, error)
+	GetTreeRoles(ctx context.Context, status int) (*RoleNode, error)
+	GetRoleMenuIds(ctx context.Context, id int64) (map[int][]int, map[int64][]int64, error)
+	UpdateRoleMenuIds(ctx context.Context, id int, updateMap map[string]any) error
+	GetApiRuleList(ctx context.Context, roleId int) ([]string, error)
+	BindApiRule(ctx context.Context, roleId int, updateMap map[string]interface{}) error
+	BindRoleMenuBtns(ctx context.Context, roleId int64, updateMap map[string]interface{}) error
 }
@@ -1,6 +1,7 @@
 package scheduled_task
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -20,17 +21,19 @@ type ScheduledTask struct {
 	NextExecuteTime time.Time      `json:"next_execute_time"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
+	// RequestID identifies the current run in logs and outgoing requests, it is not persisted
+	RequestID string `json:"-"`
 }
 
 type IScheduledTaskService interface {
-	GetAll() (*[]ScheduledTask, error)
-	Create(apiDomain *ScheduledTask) (*ScheduledTask, error)
-	GetByID(id int) (*ScheduledTask, error)
-	Update(id int, apiMap map[string]interface{}) (*ScheduledTask, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[ScheduledTask], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	EnableTask(id int) error
-	DisableTask(id int) error
+	GetAll(ctx context.Context) (*[]ScheduledTask, error)
+	Create(ctx context.Context, apiDomain *ScheduledTask) (*ScheduledTask, error)
+	GetByID(ctx context.Context, id int) (*ScheduledTask, error)
+	Update(ctx context.Context, id int, actorId int64, apiMap map[string]interface{}) (*ScheduledTask, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[ScheduledTask], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	EnableTask(ctx context.Context, id int, actorId int64) error
+	DisableTask(ctx context.Context, id int, actorId int64) error
 	ReloadTasks() error
 }
@@ -1,6 +1,7 @@
 package task_execution_log
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -18,9 +19,9 @@ type TaskExecutionLog struct {
 }
 
 type ITaskExecutionLogService interface {
-	GetByID(id int) (*TaskExecutionLog, error)
-	GetByTaskID(taskID uint, limit int) (*[]TaskExecutionLog, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[TaskExecutionLog], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
+	GetByID(ctx context.Context, id int) (*TaskExecutionLog, error)
+	GetByTaskID(ctx context.Context, taskID uint, limit int) (*[]TaskExecutionLog, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[TaskExecutionLog], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
 }
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -30,21 +31,49 @@ type SearchResultUser struct {
 	PageSize   int     `json:"page_size"`
 	TotalPages int     `json:"total_page"`
 }
+
+// UserRole is a user-role binding, bounded in time when StartAt or ExpiresAt is set
+type UserRole struct {
+	UserID    int64      `json:"userId"`
+	RoleID    int64      `json:"roleId"`
+	StartAt   *time.Time `json:"startAt"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	GrantedBy int64      `json:"grantedBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Valid     bool       `json:"valid"`
+}
+
+// IsValidAt reports whether the binding has started and not yet expired at t
+func (r UserRole) IsValidAt(t time.Time) bool {
+	if r.StartAt != nil && r.StartAt.After(t) {
+		return false
+	}
+	return r.ExpiresAt == nil || r.ExpiresAt.After(t)
+}
+
+type UserRoleGrant struct {
+	RoleID    int64              `json:"roleId" binding:"required"`
+	StartAt   *domain.CustomTime `json:"startAt"`
+	ExpiresAt *domain.CustomTime `json:"expiresAt"`
+}
+
 type PasswordEditRequest struct {
 	ID          int    `json:"id"`
 	OldPassword string `json:"oldPassword"`
 	NewPasswd   string `json:"newPassword"`
 }
 type IUserService interface {
-	GetAll() (*[]User, error)
-	GetByID(id int) (*User, error)
-	Create(newUser *User) (*User, error)
-	Delete(id int) error
-	Update(id int64, userMap map[string]interface{}) (*User, error)
-	SearchPaginated(filters domain.DataFilters) (*SearchResultUser, error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*User, error)
-	UserBindRoles(userId int64, updateMap map[string]interface{}) error
-	ResetPassword(userId int64) (*User, error)
-	EditPassword(userId int64, data PasswordEditRequest) (*User, error)
+	GetAll(ctx context.Context) (*[]User, error)
+	GetByID(ctx context.Context, id int) (*User, error)
+	Create(ctx context.Context, newUser *User) (*User, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, id int64, actorId int64, userMap map[string]interface{}) (*User, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*SearchResultUser, error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*User, error)
+	UserBindRoles(ctx context.Context, userId int64, grantedBy int64, updateMap map[string]interface{}) error
+	GrantRole(ctx context.Context, userId int64, grantedBy int64, grant UserRoleGrant) error
+	GetUserRoles(ctx context.Context, userId int64) (*[]UserRole, error)
+	ResetPassword(ctx context.Context, userId int64, actorId int64) (*User, error)
+	EditPassword(ctx context.Context, userId int64, actorId int64, data PasswordEditRequest) (*User, error)
 }
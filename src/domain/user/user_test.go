@@ -30,7 +30,7 @@ func TestUser_Fields(t *testing.T) {
 	}
 
 	if user.Status == 2 {
-		t.Errorf("Expected Status to be true, got %t", user.Status)
+		t.Errorf("Expected Status to be true, got %d", user.Status)
 	}
 
 	if user.HashPassword != "hashedpassword" {
@@ -74,7 +74,7 @@ func TestUser_ZeroValues(t *testing.T) {
 	}
 
 	if user.Status == 2 {
-		t.Errorf("Expected Status to be false, got %t", user.Status)
+		t.Errorf("Expected Status to be false, got %d", user.Status)
 	}
 
 	if user.HashPassword != "" {
@@ -93,3 +93,26 @@ func TestUser_ZeroValues(t *testing.T) {
 		t.Errorf("Expected UpdatedAt to be zero, got %v", user.UpdatedAt)
 	}
 }
+
+func TestUserRole_IsValidAt(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	cases := []struct {
+		name    string
+		binding UserRole
+		valid   bool
+	}{
+		{"permanent", UserRole{}, true},
+		{"started", UserRole{StartAt: &past}, true},
+		{"not started", UserRole{StartAt: &future}, false},
+		{"not expired", UserRole{StartAt: &past, ExpiresAt: &future}, true},
+		{"expired", UserRole{ExpiresAt: &past}, false},
+	}
+	for _, c := range cases {
+		if got := c.binding.IsValidAt(now); got != c.valid {
+			t.Errorf("%s: expected valid to be %t, got %t", c.name, c.valid, got)
+		}
+	}
+}
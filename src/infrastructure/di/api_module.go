@@ -20,6 +20,8 @@ func setupApiModule(appContext *ApplicationContext) error {
 	apiUC := apiUseCase.NewSysApiUseCase(
 		apiRepository,
 		appContext.Repositories.DictionaryRepository,
+		appContext.Enforcer,
+		appContext.BtnPermissions,
 		appContext.Logger)
 	// Initialize controllers
 	apiController := apiController.NewApiController(apiUC, appContext.Logger)
@@ -0,0 +1,31 @@
+package di
+
+import (
+	auditLogUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/audit_log"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
+	auditLogController "github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/audit_log"
+)
+
+type AuditLogModule struct {
+	Controller auditLogController.IAuditLogController
+	UseCase    auditLogUseCase.ISysAuditLogService
+	Repository audit_log.AuditLogRepositoryInterface
+}
+
+func setupAuditLogModule(appContext *ApplicationContext) error {
+	// Initialize repositories
+	auditLogRepository := audit_log.NewAuditLogRepository(appContext.DB, appContext.Logger)
+
+	// Initialize use cases
+	auditLogUC := auditLogUseCase.NewSysAuditLogUseCase(auditLogRepository, appContext.Logger)
+
+	// Initialize controllers
+	auditLogController := auditLogController.NewAuditLogController(auditLogUC, appContext.Logger)
+
+	appContext.AuditLogModule = AuditLogModule{
+		Controller: auditLogController,
+		UseCase:    auditLogUC,
+		Repository: auditLogRepository,
+	}
+	return nil
+}
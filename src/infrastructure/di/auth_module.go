@@ -22,6 +22,7 @@ func setupAuthModule(appContext *ApplicationContext) error {
 	authUC := authUseCase.NewAuthUseCase(
 		appContext.Repositories.UserRepository,
 		appContext.Repositories.RoleRepository,
+		appContext.Repositories.UserRoleRepository,
 		appContext.JWTService,
 		appContext.Logger,
 		appContext.Repositories.JwtBlacklistRepository)
@@ -4,18 +4,28 @@ package di
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gbrayhan/microservices-go/src/application/event/bus"
 	"github.com/gbrayhan/microservices-go/src/application/event/factory"
+	filesUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/files"
 	taskConstants "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task/constants"
 	lib "github.com/gbrayhan/microservices-go/src/infrastructure/lib"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/cache"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/executor"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/health"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/imaging"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/redact"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadpolicy"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/scheduled_task"
 
 	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/scheduler"
 	ws "github.com/gbrayhan/microservices-go/src/infrastructure/lib/websocket"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/writer"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/jwt_blacklist"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/api"
@@ -27,6 +37,8 @@ import (
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/dictionary"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/dictionary_detail"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/menu_btn_api"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role_btn"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role_menu"
@@ -65,6 +77,15 @@ type ApplicationContext struct {
 	TaskScheduler    *scheduler.TaskScheduler
 	HttpExecutor     *executor.HTTPExecutor
 	FunctionExecutor *executor.FunctionExecutor
+	OperationWriter  *writer.OperationWriter
+	Redactor         *redact.Redactor
+	Health           *health.Probe
+	Storage          *storage.Manager
+	UploadValidator  *uploadpolicy.Validator
+	ImageProcessor   *imaging.Processor
+	BtnPermissions   *cache.BtnPermissionCache
+	// AttachmentEntities are registered by the modules owning the entity types
+	AttachmentEntities *filesUseCase.AttachmentEntities
 
 	UserModule             UserModule
 	AuthModule             AuthModule
@@ -82,6 +103,10 @@ type ApplicationContext struct {
 	ScheduledTaskModule    ScheduledTaskModule
 	TaskExecutionLogModule TaskExecutionLogModule
 	ConfigModule           ConfigModule
+	LoggingModule          LoggingModule
+	RbacModule             RbacModule
+	IgnoreApiModule        IgnoreApiModule
+	AuditLogModule         AuditLogModule
 }
 type RepositoryContainer struct {
 	RoleMenuRepository         role_menu.ISysRoleMenuRepository
@@ -100,6 +125,7 @@ type RepositoryContainer struct {
 	UserRepository             user.UserRepositoryInterface
 	FileRepository             files.ISysFilesRepository
 	ScheduledTaskRepository    scheduled_task.IScheduledTaskRepository
+	MenuBtnApiRepository       menu_btn_api.ISysMenuBtnApiRepository
 }
 
 // SetupDependencies creates a new application context with all dependencies
@@ -126,19 +152,20 @@ func SetupDependencies(loggerInstance *logger.Logger) (*ApplicationContext, erro
 		UserRepository:          user.NewUserRepository(db, loggerInstance),
 		FileRepository:          files.NewSysFilesRepository(db, loggerInstance),
 		ScheduledTaskRepository: scheduled_task.NewScheduledTaskRepository(db, loggerInstance),
+		MenuBtnApiRepository:    menu_btn_api.NewSysMenuBtnApiRepository(db, loggerInstance),
 	}
 
 	// create event bus
-	eventBus := factory.CreateEventBus(loggerInstance)
+	eventBus := factory.CreateEventBus(loggerInstance.Named("event_bus"))
 
 	// Initialize Redis client
-	redisClientInstance, err := lib.InitRedisClient(loggerInstance)
+	redisClientInstance, err := lib.InitRedisClient(loggerInstance.Named("redis"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Redis client: %w", err)
 	}
 
 	// Initialize Casbin
-	enforcer, err := sharedUtil.InitCasbinEnforcer(db, loggerInstance)
+	enforcer, err := sharedUtil.InitCasbinEnforcer(db, loggerInstance.Named("casbin"))
 	if err != nil {
 		return nil, err
 	}
@@ -147,18 +174,51 @@ func SetupDependencies(loggerInstance *logger.Logger) (*ApplicationContext, erro
 	wsRouter := ws.NewWebSocketRouter()
 
 	// initialize task executor
-	taskExecutor := executor.NewTaskExecutorManager(loggerInstance)
-	functionExecutor := executor.NewFunctionExecutor(loggerInstance)
-	httpCallExecutor := executor.NewHTTPExecutor(loggerInstance)
+	executorLogger := loggerInstance.Named("executor")
+	taskExecutor := executor.NewTaskExecutorManager(executorLogger)
+	functionExecutor := executor.NewFunctionExecutor(executorLogger)
+	httpCallExecutor := executor.NewHTTPExecutor(executorLogger)
 	taskExecutor.RegisterExecutor(taskConstants.TaskTypeFunction, functionExecutor)
 	taskExecutor.RegisterExecutor(taskConstants.TaskTypeHttpCall, httpCallExecutor)
 
 	// initialize task scheduler
-	taskScheduler := scheduler.NewTaskScheduler(repositories.ScheduledTaskRepository, loggerInstance, taskExecutor)
+	taskScheduler := scheduler.NewTaskScheduler(repositories.ScheduledTaskRepository, loggerInstance.Named("scheduler"), taskExecutor)
 
 	// Initialize JWT service
 	jwtService := security.NewJWTService()
 
+	// initialize async operation record writer, drained by main on shutdown
+	operationWriter := writer.NewOperationWriter(
+		operation_records.NewOperationRepository(db, loggerInstance),
+		writer.LoadConfigFromEnv(),
+		loggerInstance.Named("operation_writer"))
+	metrics.Registry.MustRegister(operationWriter)
+	// uploads go to STORAGE_DRIVER, files stored with another configured engine stay readable
+	storageManager, err := storage.LoadManagerFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// per category upload policies and the malware scanner of uploaded content
+	uploadValidator, err := uploadpolicy.LoadValidatorFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// metadata stripping and thumbnail sizes of uploaded images
+	imageProcessor, err := imaging.LoadProcessorFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// button bindings read by the button permission middleware, invalidated by the services
+	// changing them
+	btnPermissions := cache.NewBtnPermissionCache(
+		time.Duration(sharedUtil.GetEnvAsInt("BTN_PERMISSION_CACHE_TTL_SECONDS", 60)) * time.Second)
+
+	// masks passwords, tokens and secrets in recorded bodies before they reach the writer
+	redactor := redact.NewRedactor(redact.LoadConfigFromEnv())
+
 	// create context
 	appContext := &ApplicationContext{
 		DB:               db,
@@ -173,8 +233,18 @@ func SetupDependencies(loggerInstance *logger.Logger) (*ApplicationContext, erro
 		TaskScheduler:    taskScheduler,
 		FunctionExecutor: functionExecutor,
 		HttpExecutor:     httpCallExecutor,
+		OperationWriter:  operationWriter,
+		Redactor:         redactor,
+		Storage:          storageManager,
+		UploadValidator:  uploadValidator,
+		ImageProcessor:   imageProcessor,
+		BtnPermissions:   btnPermissions,
+
+		AttachmentEntities: filesUseCase.NewAttachmentEntities(),
 	}
 
+	appContext.Health = newHealthProbe(appContext)
+
 	// module slice
 	moduleSetupFuncs := []func(*ApplicationContext) error{
 		setupUserModule,
@@ -192,7 +262,11 @@ func SetupDependencies(loggerInstance *logger.Logger) (*ApplicationContext, erro
 		setupFileModule,
 		setupScheduledTaskModule,
 		setupConfigModule,
+		setupLoggingModule,
 		setupTaskExecutionLogModule,
+		setupRbacModule,
+		setupIgnoreApiModule,
+		setupAuditLogModule,
 	}
 
 	for _, setupFunc := range moduleSetupFuncs {
@@ -1,30 +1,54 @@
 package di
 
 import (
+	eventHandler "github.com/gbrayhan/microservices-go/src/application/event/handler"
+	eventModel "github.com/gbrayhan/microservices-go/src/application/event/model"
 	filesUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/job"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
 	fileController "github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/file"
 )
 
 type FileModule struct {
 	Controller fileController.IFileController
-	UseCase    filesUseCase.ISysFilesService
-	Repository files.ISysFilesRepository
+	// AttachmentController attaches files to the entities
+	AttachmentController fileController.IFileAttachmentController
+	UseCase              filesUseCase.ISysFilesService
+	Repository           files.ISysFilesRepository
 }
 
 func setupFileModule(appContext *ApplicationContext) error {
 	// Initialize use cases
 	filesUC := filesUseCase.NewSysFilesUseCase(
 		appContext.Repositories.FileRepository,
+		appContext.Storage,
+		appContext.UploadValidator,
+		appContext.ImageProcessor,
+		filesUseCase.LoadFileAccessConfigFromEnv(),
 		appContext.Logger)
+	attachmentUC := filesUseCase.NewFileAttachmentUseCase(
+		filesUC,
+		appContext.Repositories.FileRepository,
+		appContext.AttachmentEntities,
+		appContext.Logger)
+
+	// Initialize event
+	appContext.EventBus.Subscribe(
+		eventModel.EntityDeletedEventType, eventHandler.NewAttachmentEventHandler(attachmentUC))
+
+	// initialize executor
+	appContext.FunctionExecutor.RegisterFunction(job.CollectFilesFunction,
+		job.NewCollectFiles(filesUC, attachmentUC, appContext.Logger))
 
 	// Initialize controllers
-	fileController := fileController.NewFileController(filesUC, appContext.Logger)
+	attachmentController := fileController.NewFileAttachmentController(attachmentUC, appContext.Logger)
+	fileController := fileController.NewFileController(filesUC, appContext.Enforcer, appContext.Logger)
 
 	appContext.FileModule = FileModule{
-		Controller: fileController,
-		UseCase:    filesUC,
-		Repository: appContext.Repositories.FileRepository,
+		Controller:           fileController,
+		AttachmentController: attachmentController,
+		UseCase:              filesUC,
+		Repository:           appContext.Repositories.FileRepository,
 	}
 	return nil
 }
@@ -0,0 +1,57 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gbrayhan/microservices-go/src/application/event/bus"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/health"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+)
+
+// newHealthProbe registers the readiness checks of the shared dependencies
+func newHealthProbe(appContext *ApplicationContext) *health.Probe {
+	timeout := 3 * time.Second
+	if seconds := sharedUtil.GetEnvAsInt("HEALTH_CHECK_TIMEOUT_SECONDS", 3); seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	probe := health.NewProbe(timeout)
+	probe.Register("database", func(ctx context.Context) error {
+		sqlDB, err := appContext.DB.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+	probe.Register("redis", func(ctx context.Context) error {
+		return appContext.RedisClient.Ping(ctx).Err()
+	})
+	probe.Register("event_bus", func(ctx context.Context) error {
+		if appContext.EventBus == nil {
+			return errors.New("event bus is not configured")
+		}
+		// the in-memory bus has nothing to lose its connection to
+		if checker, ok := appContext.EventBus.(bus.HealthChecker); ok {
+			return checker.HealthCheck(ctx)
+		}
+		return nil
+	})
+	probe.Register("scheduler", func(context.Context) error {
+		if !appContext.TaskScheduler.IsRunning() {
+			return errors.New("task scheduler is not running")
+		}
+		return nil
+	})
+	probe.Register("casbin", func(context.Context) error {
+		policies, err := appContext.Enforcer.GetPolicy()
+		if err != nil {
+			return err
+		}
+		if len(policies) == 0 {
+			return errors.New("no casbin policy loaded")
+		}
+		return nil
+	})
+	return probe
+}
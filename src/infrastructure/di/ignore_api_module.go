@@ -0,0 +1,37 @@
+package di
+
+import (
+	"context"
+	ignoreApiUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/ignore_api"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/ignore_api"
+	ignoreApiController "github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/ignore_api"
+	"go.uber.org/zap"
+)
+
+type IgnoreApiModule struct {
+	Controller ignoreApiController.IIgnoreApiController
+	UseCase    ignoreApiUseCase.ISysIgnoreApiService
+	Repository ignore_api.IgnoreApiRepositoryInterface
+}
+
+func setupIgnoreApiModule(appContext *ApplicationContext) error {
+	// Initialize repositories
+	ignoreApiRepository := ignore_api.NewIgnoreApiRepository(appContext.DB, appContext.Logger)
+
+	// Initialize use cases
+	ignoreApiUC := ignoreApiUseCase.NewSysIgnoreApiUseCase(ignoreApiRepository, appContext.Logger)
+	// warm up the cache, the middlewares only read from memory
+	if err := ignoreApiUC.Refresh(context.Background()); err != nil {
+		appContext.Logger.Warn("Ignore api cache not loaded", zap.Error(err))
+	}
+
+	// Initialize controllers
+	ignoreApiController := ignoreApiController.NewIgnoreApiController(ignoreApiUC, appContext.Logger)
+
+	appContext.IgnoreApiModule = IgnoreApiModule{
+		Controller: ignoreApiController,
+		UseCase:    ignoreApiUC,
+		Repository: ignoreApiRepository,
+	}
+	return nil
+}
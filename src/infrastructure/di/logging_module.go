@@ -0,0 +1,28 @@
+package di
+
+import (
+	"context"
+	loggingUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/logging"
+	loggingController "github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/logging"
+	"go.uber.org/zap"
+)
+
+type LoggingModule struct {
+	Controller loggingController.ILoggingController
+	UseCase    loggingUseCase.ISysLoggingService
+}
+
+// setupLoggingModule needs the config module, the sys_config logging settings are applied here
+// so everything logged after startup follows them
+func setupLoggingModule(appContext *ApplicationContext) error {
+	loggingUC := loggingUseCase.NewSysLoggingUseCase(appContext.ConfigModule.Repository, appContext.Logger)
+	if _, err := loggingUC.Reload(context.Background()); err != nil {
+		appContext.Logger.Warn("Logging configuration not applied, keeping the startup settings", zap.Error(err))
+	}
+
+	appContext.LoggingModule = LoggingModule{
+		Controller: loggingController.NewLoggingController(loggingUC, appContext.Logger),
+		UseCase:    loggingUC,
+	}
+	return nil
+}
@@ -1,31 +1,41 @@
 package di
 
 import (
-	"github.com/gbrayhan/microservices-go/src/application/services/sys/operation_record"
 	operationUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/operation_record"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/job"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
 	operationController "github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/operation"
 )
 
 type OperationModule struct {
-	Controller operationController.IOperationController
-	UseCase    operationUseCase.ISysOperationService
-	Repository operation_record.ISysOperationService
+	Controller       operationController.IOperationController
+	UseCase          operationUseCase.ISysOperationService
+	AnalyticsUseCase operationUseCase.ISysOperationAnalyticsService
+	Repository       operation_records.OperationRepositoryInterface
 }
 
 func setupOperationModule(appContext *ApplicationContext) error {
 	// Initialize repositories
 	operationRepo := operation_records.NewOperationRepository(appContext.DB, appContext.Logger)
 
+	// initialize executor
+	appContext.FunctionExecutor.RegisterFunction(job.ArchiveOperationRecordsFunction,
+		job.NewArchiveOperationRecords(operationRepo, appContext.Repositories.FileRepository,
+			appContext.Storage, appContext.Logger))
+
 	// Initialize use cases
 	operationUC := operationUseCase.NewSysOperationUseCase(operationRepo, appContext.Logger)
+	analyticsUC := operationUseCase.NewSysOperationAnalyticsUseCase(
+		operation_records.NewOperationAnalyticsRepository(appContext.DB, appContext.Logger),
+		appContext.RedisClient, appContext.Logger)
 
 	// Initialize controllers
-	operationController := operationController.NewOperationController(operationUC, appContext.Logger)
+	operationController := operationController.NewOperationController(operationUC, analyticsUC, appContext.Logger)
 	appContext.OperationModule = OperationModule{
-		Controller: operationController,
-		UseCase:    operationUC,
-		Repository: operationRepo,
+		Controller:       operationController,
+		UseCase:          operationUC,
+		AnalyticsUseCase: analyticsUC,
+		Repository:       operationRepo,
 	}
 	return nil
 
@@ -0,0 +1,32 @@
+package di
+
+import (
+	rbacUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/rbac"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/rbac"
+	rbacController "github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/rbac"
+)
+
+type RbacModule struct {
+	Controller rbacController.IRbacController
+	UseCase    rbacUseCase.ISysRbacService
+	Repository rbac.IRbacRepository
+}
+
+func setupRbacModule(appContext *ApplicationContext) error {
+	// Initialize repositories
+	rbacRepository := rbac.NewRbacRepository(appContext.DB, appContext.Logger)
+
+	// Initialize use cases
+	rbacUC := rbacUseCase.NewSysRbacUseCase(rbacRepository, appContext.Enforcer, appContext.BtnPermissions,
+		appContext.Logger)
+
+	// Initialize controllers
+	rbacController := rbacController.NewRbacController(rbacUC, appContext.Logger)
+
+	appContext.RbacModule = RbacModule{
+		Controller: rbacController,
+		UseCase:    rbacUC,
+		Repository: rbacRepository,
+	}
+	return nil
+}
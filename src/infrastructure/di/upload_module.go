@@ -2,28 +2,87 @@ package di
 
 import (
 	filesUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/files"
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/job"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/cache"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/sts"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadcallback"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/upload_session"
 	uploadController "github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/upload"
 )
 
 type UploadModule struct {
-	Controller uploadController.IUploadController
-	UseCase    filesUseCase.ISysFilesService
-	Repository files.ISysFilesRepository
+	Controller        uploadController.IUploadController
+	ChunkedController uploadController.IChunkedUploadController
+	// CallbackController registers the objects uploaded straight to the bucket
+	CallbackController uploadController.IUploadCallbackController
+	UseCase            filesUseCase.ISysFilesService
+	ChunkedUseCase     domainFiles.IChunkedUploadService
+	Repository         files.ISysFilesRepository
 }
 
 func setupUploadModule(appContext *ApplicationContext) error {
 	// Initialize use cases
 	filesUC := filesUseCase.NewSysFilesUseCase(
 		appContext.Repositories.FileRepository,
+		appContext.Storage,
+		appContext.UploadValidator,
+		appContext.ImageProcessor,
+		filesUseCase.LoadFileAccessConfigFromEnv(),
 		appContext.Logger)
+	chunkedConfig := filesUseCase.LoadChunkedUploadConfigFromEnv()
+	chunkedUC := filesUseCase.NewChunkedUploadUseCase(
+		upload_session.NewUploadSessionRepository(appContext.DB, appContext.Logger),
+		filesUC,
+		appContext.UploadValidator,
+		chunkedConfig,
+		appContext.Logger)
+
+	// initialize executor
+	appContext.FunctionExecutor.RegisterFunction(job.CleanExpiredUploadsFunction,
+		job.NewCleanExpiredUploads(chunkedUC, appContext.Logger))
 
 	// Initialize controllers
-	uploadController := uploadController.NewAuthController(filesUC, appContext.Logger, appContext.RedisClient)
+	chunkedController := uploadController.NewChunkedUploadController(chunkedUC, chunkedConfig.MaxSize, appContext.Logger)
+	stsProvider, err := sts.LoadProviderFromEnv()
+	if err != nil {
+		return err
+	}
+	stsConfig := filesUseCase.LoadSTSConfigFromEnv()
+	stsConfig.KeyPrefix = appContext.Storage.KeyPrefix()
+	stsCache := cache.NewSTSCacheService(appContext.RedisClient)
+	stsUC := filesUseCase.NewSTSUseCase(
+		stsProvider,
+		stsCache,
+		audit_log.NewAuditLogRepository(appContext.DB, appContext.Logger),
+		appContext.UploadValidator,
+		stsConfig,
+		appContext.Logger)
+	callbackConfig := filesUseCase.LoadUploadCallbackConfigFromEnv()
+	callbackConfig.KeyPrefix = appContext.Storage.KeyPrefix()
+	callbackUC := filesUseCase.NewUploadCallbackUseCase(
+		filesUC,
+		appContext.Repositories.FileRepository,
+		stsCache,
+		appContext.Storage,
+		callbackConfig,
+		appContext.Logger)
+	appContext.FunctionExecutor.RegisterFunction(job.ReconcileUploadsFunction,
+		job.NewReconcileUploads(callbackUC, appContext.Logger))
+	callbackController := uploadController.NewUploadCallbackController(callbackUC,
+		uploadcallback.NewOSSVerifier(nil),
+		uploadcallback.LoadHMACVerifierFromEnv(),
+		appContext.Logger)
+	uploadController := uploadController.NewAuthController(filesUC, stsUC, appContext.Logger)
 	appContext.UploadModule = UploadModule{
-		Controller: uploadController,
-		UseCase:    filesUC,
-		Repository: appContext.Repositories.FileRepository,
+		Controller:         uploadController,
+		ChunkedController:  chunkedController,
+		CallbackController: callbackController,
+		UseCase:            filesUC,
+		ChunkedUseCase:     chunkedUC,
+		Repository:         appContext.Repositories.FileRepository,
 	}
 	return nil
 }
@@ -3,6 +3,7 @@ package di
 import (
 	eventHandler "github.com/gbrayhan/microservices-go/src/application/event/handler"
 	eventModel "github.com/gbrayhan/microservices-go/src/application/event/model"
+	filesUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/files"
 	userUseCase "github.com/gbrayhan/microservices-go/src/application/services/user"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/job"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/user"
@@ -21,8 +22,14 @@ func setupUserModule(appContext *ApplicationContext) error {
 	appContext.EventBus.Subscribe(
 		eventModel.UserRegisteredEventType, eventHandler.NewNotificationEventHandler())
 
+	// users are deleted softly, their files are detached on the EntityDeletedEvent of the use case
+	appContext.AttachmentEntities.Register(eventModel.EntityTypeUser,
+		filesUseCase.AttachmentEntity{Table: "sys_users", SoftDelete: true})
+
 	// initialize executor
 	appContext.FunctionExecutor.RegisterFunction("clean_up_old_data", job.CleanOldData)
+	appContext.FunctionExecutor.RegisterFunction(job.RevokeExpiredUserRolesFunction,
+		job.NewRevokeExpiredUserRoles(appContext.Repositories.UserRoleRepository))
 
 	// Initialize use cases
 	userUC := userUseCase.NewUserUseCase(
@@ -0,0 +1,75 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	domainScheduledTask "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.uber.org/zap"
+)
+
+// CollectFilesFunction is the function name to use in a function task's params
+const CollectFilesFunction = "collect_file_garbage"
+
+// fileGCParams are read from the task params, e.g.
+//
+//	{"function_name": "collect_file_garbage", "params": {"grace_hours": 72, "collect_files": true}}
+type fileGCParams struct {
+	Params struct {
+		// GraceHours leaves what changed recently, 24 by default
+		GraceHours int `json:"grace_hours"`
+		// CollectFiles deletes the files no entity references, they are only reported otherwise
+		CollectFiles bool `json:"collect_files"`
+		// DryRun only reports what would be removed
+		DryRun bool `json:"dry_run"`
+	} `json:"params"`
+}
+
+// NewCollectFiles returns a function task removing the blobs and objects no record references
+// and, when asked, the files no entity references. The attachments of deleted entities are
+// pruned first, their files are then unreferenced.
+func NewCollectFiles(files domainFiles.ISysFilesService, attachments domainFiles.IFileAttachmentService,
+	loggerInstance *logger.Logger) func(*domainScheduledTask.ScheduledTask) error {
+	return func(task *domainScheduledTask.ScheduledTask) error {
+		var params fileGCParams
+		params.Params.GraceHours = 24
+		if len(task.TaskParams) > 0 {
+			if err := json.Unmarshal(task.TaskParams, &params); err != nil {
+				return fmt.Errorf("failed to parse file gc params: %w", err)
+			}
+		}
+		if params.Params.GraceHours < 0 {
+			return fmt.Errorf("grace_hours must not be negative")
+		}
+		if !params.Params.DryRun {
+			if _, err := attachments.Prune(context.Background()); err != nil {
+				return err
+			}
+		}
+		report, err := files.CollectGarbage(context.Background(), domainFiles.GCOptions{
+			Grace:        time.Duration(params.Params.GraceHours) * time.Hour,
+			CollectFiles: params.Params.CollectFiles,
+			DryRun:       params.Params.DryRun,
+		})
+		if report != nil {
+			var size int64
+			for _, blob := range report.DanglingBlobs {
+				size += blob.FileSize
+			}
+			for _, orphan := range report.OrphanObjects {
+				size += orphan.Size
+			}
+			loggerInstance.Info("File garbage collection done",
+				zap.Int("danglingBlobs", len(report.DanglingBlobs)),
+				zap.Int("unreferencedFiles", len(report.UnreferencedFiles)),
+				zap.Int("orphanObjects", len(report.OrphanObjects)),
+				zap.Int64("bytes", size),
+				zap.Bool("dryRun", report.DryRun))
+		}
+		return err
+	}
+}
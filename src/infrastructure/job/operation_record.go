@@ -0,0 +1,171 @@
+package job
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	domainOperation "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
+	domainScheduledTask "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/storage"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
+	shareUtils "github.com/gbrayhan/microservices-go/src/shared/utils"
+	"go.uber.org/zap"
+)
+
+// ArchiveOperationRecordsFunction is the function name to use in a function task's params
+const ArchiveOperationRecordsFunction = "archive_operation_records"
+
+// archiveParams are read from the task params, e.g.
+//
+//	{"function_name": "archive_operation_records", "params": {"default_retention_days": 90,
+//	  "policies": [{"status": "5xx", "retention_days": 365}, {"path": "/v1/auth/*", "retention_days": 30}]}}
+type archiveParams struct {
+	Params struct {
+		domainOperation.RetentionConfig
+		// MaxRecords bounds one run, the remaining records are archived by the next runs
+		MaxRecords int `json:"max_records"`
+	} `json:"params"`
+}
+
+// NewArchiveOperationRecords returns a function task moving the operation records past their
+// retention into a gzip compressed JSONL file stored with the default storage driver and
+// registered in sys_files, then deleting them. Records are only deleted once the archive is
+// stored and registered.
+func NewArchiveOperationRecords(
+	operationRepository operation_records.OperationRepositoryInterface,
+	filesRepository files.ISysFilesRepository,
+	storageManager *storage.Manager,
+	loggerInstance *logger.Logger) func(*domainScheduledTask.ScheduledTask) error {
+	return func(task *domainScheduledTask.ScheduledTask) error {
+		var params archiveParams
+		params.Params.DefaultRetentionDays = shareUtils.GetEnvAsInt("OPERATION_LOG_RETENTION_DAYS", 90)
+		if len(task.TaskParams) > 0 {
+			if err := json.Unmarshal(task.TaskParams, &params); err != nil {
+				return fmt.Errorf("failed to parse archive params: %w", err)
+			}
+		}
+		config := params.Params.RetentionConfig
+		if config.BatchSize <= 0 {
+			config.BatchSize = 1000
+		}
+		maxRecords := params.Params.MaxRecords
+		if maxRecords <= 0 {
+			maxRecords = 100000
+		}
+		minDays := config.MinRetentionDays()
+		if minDays == 0 {
+			loggerInstance.Info("Operation record retention is unlimited, nothing to archive")
+			return nil
+		}
+
+		ctx := context.Background()
+		now := time.Now()
+		cutoff := now.AddDate(0, 0, -minDays)
+		// the archive is built in a temporary file, the driver needs its size and md5 up front
+		archiveFile, err := os.CreateTemp("", "operation_records_*.jsonl.gz")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = archiveFile.Close()
+			_ = os.Remove(archiveFile.Name())
+		}()
+		gzipWriter := gzip.NewWriter(archiveFile)
+		encoder := json.NewEncoder(gzipWriter)
+
+		var archivedIDs []int
+		afterID := 0
+		for len(archivedIDs) < maxRecords {
+			records, err := operationRepository.FindBefore(ctx, cutoff, afterID, config.BatchSize)
+			if err != nil {
+				return err
+			}
+			for i := range *records {
+				record := &(*records)[i]
+				afterID = record.ID
+				if !config.Expired(record, now) || len(archivedIDs) >= maxRecords {
+					continue
+				}
+				if err := encoder.Encode(record.ToExport()); err != nil {
+					return err
+				}
+				archivedIDs = append(archivedIDs, record.ID)
+			}
+			if len(*records) < config.BatchSize {
+				break
+			}
+		}
+
+		if len(archivedIDs) == 0 {
+			loggerInstance.Info("No expired operation records to archive")
+			return nil
+		}
+		if err := gzipWriter.Close(); err != nil {
+			return err
+		}
+		if err := archiveFile.Close(); err != nil {
+			return err
+		}
+
+		md5Value, err := shareUtils.CalculateFileMD5(archiveFile.Name())
+		if err != nil {
+			return err
+		}
+		archivePrefix := os.Getenv("OPERATION_LOG_ARCHIVE_DIR")
+		if archivePrefix == "" {
+			archivePrefix = "storage/archive/operation_records"
+		}
+		fileName := fmt.Sprintf("operation_records_%s.jsonl.gz", now.Format("20060102150405"))
+		archiveKey := path.Join(archivePrefix, fileName)
+		driver := storageManager.Default()
+		if err := putFile(driver, archiveKey, archiveFile.Name(), "application/gzip"); err != nil {
+			return err
+		}
+		if _, err := filesRepository.Create(ctx, &domainFiles.SysFiles{
+			FileName:       fileName,
+			FilePath:       archiveKey,
+			FileMD5:        md5Value,
+			FileOriginName: fileName,
+			StorageEngine:  driver.Name(),
+			// the archives hold the request bodies, only the administrators may read them
+			Visibility: domainFiles.VisibilityPrivate,
+		}); err != nil {
+			// an archive nothing refers to would never be cleaned up
+			_ = driver.Delete(ctx, archiveKey)
+			return err
+		}
+
+		for start := 0; start < len(archivedIDs); start += config.BatchSize {
+			end := min(start+config.BatchSize, len(archivedIDs))
+			if err := operationRepository.Purge(ctx, archivedIDs[start:end]); err != nil {
+				return err
+			}
+		}
+		loggerInstance.Info("Operation records archived",
+			zap.Int("count", len(archivedIDs)),
+			zap.String("engine", driver.Name()),
+			zap.String("key", archiveKey))
+		return nil
+	}
+}
+
+func putFile(driver storage.Driver, key, name, contentType string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return driver.Put(context.Background(), key, file, info.Size(), storage.PutOptions{ContentType: contentType})
+}
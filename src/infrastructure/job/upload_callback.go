@@ -0,0 +1,55 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	domainScheduledTask "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.uber.org/zap"
+)
+
+// ReconcileUploadsFunction is the function name to use in a function task's params
+const ReconcileUploadsFunction = "reconcile_uploaded_objects"
+
+// reconcileParams are read from the task params, e.g.
+//
+//	{"function_name": "reconcile_uploaded_objects", "params": {"min_age_minutes": 1440, "remove": false}}
+type reconcileParams struct {
+	Params struct {
+		// MinAgeMinutes leaves the recent uploads whose callback may still come, 60 by default
+		MinAgeMinutes int `json:"min_age_minutes"`
+		// Remove deletes the orphaned objects, true by default: the STS policy doesn't bound their
+		// size or type, only the callback checks them. They are only reported when false.
+		Remove bool `json:"remove"`
+	} `json:"params"`
+}
+
+// NewReconcileUploads returns a function task finding the objects uploaded straight to the
+// bucket with an STS token that no callback registered
+func NewReconcileUploads(callbacks domainFiles.IUploadCallbackService, loggerInstance *logger.Logger) func(*domainScheduledTask.ScheduledTask) error {
+	return func(task *domainScheduledTask.ScheduledTask) error {
+		var params reconcileParams
+		params.Params.MinAgeMinutes = 60
+		params.Params.Remove = true
+		if len(task.TaskParams) > 0 {
+			if err := json.Unmarshal(task.TaskParams, &params); err != nil {
+				return fmt.Errorf("failed to parse reconcile params: %w", err)
+			}
+		}
+		minAge := time.Duration(params.Params.MinAgeMinutes) * time.Minute
+		orphans, err := callbacks.Reconcile(context.Background(), minAge, params.Params.Remove)
+		if len(orphans) > 0 {
+			var size int64
+			for _, orphan := range orphans {
+				size += orphan.Size
+			}
+			loggerInstance.Info("Found orphaned uploaded objects", zap.Int("count", len(orphans)), zap.Int64("bytes", size),
+				zap.Bool("removed", params.Params.Remove))
+		}
+		return err
+	}
+}
@@ -0,0 +1,25 @@
+package job
+
+import (
+	"context"
+
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	domainScheduledTask "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.uber.org/zap"
+)
+
+// CleanExpiredUploadsFunction is the function name to use in a function task's params
+const CleanExpiredUploadsFunction = "clean_expired_uploads"
+
+// NewCleanExpiredUploads returns a function task removing the chunked upload sessions past their
+// expiry together with their temporary parts
+func NewCleanExpiredUploads(chunkedUploads domainFiles.IChunkedUploadService, loggerInstance *logger.Logger) func(*domainScheduledTask.ScheduledTask) error {
+	return func(*domainScheduledTask.ScheduledTask) error {
+		removed, err := chunkedUploads.CleanExpired(context.Background())
+		if removed > 0 {
+			loggerInstance.Info("Removed expired upload sessions", zap.Int("count", removed))
+		}
+		return err
+	}
+}
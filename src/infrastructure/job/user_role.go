@@ -0,0 +1,20 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	domainScheduledTask "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/user_role"
+)
+
+// RevokeExpiredUserRolesFunction is the function name to use in a function task's params
+const RevokeExpiredUserRolesFunction = "revoke_expired_user_roles"
+
+// NewRevokeExpiredUserRoles returns a function task deleting user-role bindings whose expiry has passed
+func NewRevokeExpiredUserRoles(userRoleRepository user_role.ISysUserRoleRepository) func(*domainScheduledTask.ScheduledTask) error {
+	return func(*domainScheduledTask.ScheduledTask) error {
+		_, err := userRoleRepository.RevokeExpired(context.Background(), time.Now())
+		return err
+	}
+}
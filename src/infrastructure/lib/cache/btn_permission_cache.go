@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// BtnPermissionCache keeps in memory the buttons guarding each api and the buttons owned by
+// each role, so the button permission middleware checks a request without a query. Whoever
+// changes a binding invalidates it, entries also expire after ttl which bounds how long another
+// instance keeps serving bindings changed elsewhere.
+type BtnPermissionCache struct {
+	ttl time.Duration
+
+	mu sync.RWMutex
+	// generation is bumped by every invalidation, a load started before it isn't stored
+	generation uint64
+	apiBtns    map[string]btnEntry
+	roleBtns   map[int64]btnEntry
+}
+
+type btnEntry struct {
+	btnIds   []int64
+	loadedAt time.Time
+}
+
+func NewBtnPermissionCache(ttl time.Duration) *BtnPermissionCache {
+	return &BtnPermissionCache{
+		ttl:      ttl,
+		apiBtns:  make(map[string]btnEntry),
+		roleBtns: make(map[int64]btnEntry),
+	}
+}
+
+// ApiBtns returns the buttons guarding the api, load reads them on a miss
+func (c *BtnPermissionCache) ApiBtns(path string, method string, load func() ([]int64, error)) ([]int64, error) {
+	key := method + " " + path
+	return c.get(func() (btnEntry, bool) {
+		entry, ok := c.apiBtns[key]
+		return entry, ok
+	}, func(entry btnEntry) {
+		c.apiBtns[key] = entry
+	}, load)
+}
+
+// RoleBtns returns the buttons owned by the role, load reads them on a miss
+func (c *BtnPermissionCache) RoleBtns(roleId int64, load func() ([]int64, error)) ([]int64, error) {
+	return c.get(func() (btnEntry, bool) {
+		entry, ok := c.roleBtns[roleId]
+		return entry, ok
+	}, func(entry btnEntry) {
+		c.roleBtns[roleId] = entry
+	}, load)
+}
+
+func (c *BtnPermissionCache) get(lookup func() (btnEntry, bool), store func(btnEntry),
+	load func() ([]int64, error)) ([]int64, error) {
+	c.mu.RLock()
+	entry, ok := lookup()
+	generation := c.generation
+	c.mu.RUnlock()
+	if ok && time.Since(entry.loadedAt) < c.ttl {
+		return entry.btnIds, nil
+	}
+
+	btnIds, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if generation == c.generation {
+		store(btnEntry{btnIds: btnIds, loadedAt: time.Now()})
+	}
+	c.mu.Unlock()
+	return btnIds, nil
+}
+
+// InvalidateRole drops the buttons of one role, after its button bindings changed
+func (c *BtnPermissionCache) InvalidateRole(roleId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	delete(c.roleBtns, roleId)
+}
+
+// Invalidate drops every entry, after buttons, apis or their bindings changed
+func (c *BtnPermissionCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.apiBtns = make(map[string]btnEntry)
+	c.roleBtns = make(map[int64]btnEntry)
+}
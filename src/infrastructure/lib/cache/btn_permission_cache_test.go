@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBtnPermissionCache_LoadsOnce(t *testing.T) {
+	c := NewBtnPermissionCache(time.Minute)
+	loads := 0
+	load := func() ([]int64, error) {
+		loads++
+		return []int64{1, 2}, nil
+	}
+	for range 3 {
+		btnIds, err := c.ApiBtns("/v1/user", "GET", load)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, btnIds)
+	}
+	assert.Equal(t, 1, loads)
+
+	_, err := c.ApiBtns("/v1/user", "POST", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads, "apis are cached by path and method")
+}
+
+func TestBtnPermissionCache_Invalidate(t *testing.T) {
+	c := NewBtnPermissionCache(time.Minute)
+	roleLoads := map[int64]int{}
+	loadRole := func(roleId int64) func() ([]int64, error) {
+		return func() ([]int64, error) {
+			roleLoads[roleId]++
+			return []int64{roleId}, nil
+		}
+	}
+	apiLoads := 0
+	loadApi := func() ([]int64, error) {
+		apiLoads++
+		return nil, nil
+	}
+	for _, roleId := range []int64{1, 2} {
+		_, err := c.RoleBtns(roleId, loadRole(roleId))
+		require.NoError(t, err)
+	}
+	_, err := c.ApiBtns("/v1/user", "GET", loadApi)
+	require.NoError(t, err)
+
+	c.InvalidateRole(1)
+	for _, roleId := range []int64{1, 2} {
+		_, err := c.RoleBtns(roleId, loadRole(roleId))
+		require.NoError(t, err)
+	}
+	_, err = c.ApiBtns("/v1/user", "GET", loadApi)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int{1: 2, 2: 1}, roleLoads, "only the invalidated role is reloaded")
+	assert.Equal(t, 1, apiLoads)
+
+	c.Invalidate()
+	_, err = c.RoleBtns(2, loadRole(2))
+	require.NoError(t, err)
+	_, err = c.ApiBtns("/v1/user", "GET", loadApi)
+	require.NoError(t, err)
+	assert.Equal(t, 2, roleLoads[2])
+	assert.Equal(t, 2, apiLoads)
+}
+
+func TestBtnPermissionCache_StaleLoadNotStored(t *testing.T) {
+	c := NewBtnPermissionCache(time.Minute)
+	// the bindings change while the first load is reading them
+	_, err := c.RoleBtns(1, func() ([]int64, error) {
+		c.InvalidateRole(1)
+		return []int64{1}, nil
+	})
+	require.NoError(t, err)
+	btnIds, err := c.RoleBtns(1, func() ([]int64, error) { return []int64{2}, nil })
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, btnIds)
+}
+
+func TestBtnPermissionCache_ExpiresAndErrors(t *testing.T) {
+	c := NewBtnPermissionCache(0)
+	loads := 0
+	load := func() ([]int64, error) {
+		loads++
+		return []int64{1}, nil
+	}
+	_, _ = c.RoleBtns(1, load)
+	_, _ = c.RoleBtns(1, load)
+	assert.Equal(t, 2, loads, "expired entries are reloaded")
+
+	_, err := c.ApiBtns("/v1/user", "GET", func() ([]int64, error) { return nil, errors.New("db down") })
+	assert.Error(t, err)
+}
@@ -3,6 +3,8 @@ package cache
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -63,13 +65,17 @@ func (s *STSCacheService) DeleteSTSToken(ctx context.Context, key string) error
 }
 
 // 生成并缓存Refresh Token
-func (s *STSCacheService) GenerateRefreshToken(ctx context.Context, userID string) (string, error) {
-	refreshToken := generateRandomToken() // 实现随机token生成函数
+func (s *STSCacheService) GenerateRefreshToken(ctx context.Context, userID string, scope files.STSScope) (string, error) {
+	refreshToken, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
 
 	rt := &files.RefreshToken{
 		Token:     refreshToken,
 		ExpiresAt: time.Now().Add(24 * time.Hour), // 24小时有效期
 		UserID:    userID,
+		Scope:     scope,
 		CreatedAt: time.Now(),
 	}
 
@@ -116,9 +122,33 @@ func (s *STSCacheService) DeleteRefreshToken(ctx context.Context, token string)
 	return s.redisClient.Del(ctx, key).Err()
 }
 
-// 生成随机token的辅助函数
-func generateRandomToken() string {
-	// 实现随机token生成逻辑
-	// 可以使用crypto/rand或者uuid
-	return fmt.Sprintf("rt_%d", time.Now().UnixNano())
+// 缓存STS上传前缀的授权，上传回调据此登记文件
+func (s *STSCacheService) SetUploadGrant(ctx context.Context, prefix string, grant *files.STSGrant, expiration time.Duration) error {
+	data, err := json.Marshal(grant)
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Set(ctx, fmt.Sprintf("sts_grant:%s", prefix), data, expiration).Err()
+}
+
+// 获取STS上传前缀的授权
+func (s *STSCacheService) GetUploadGrant(ctx context.Context, prefix string) (*files.STSGrant, error) {
+	data, err := s.redisClient.Get(ctx, fmt.Sprintf("sts_grant:%s", prefix)).Result()
+	if err != nil {
+		return nil, err
+	}
+	var grant files.STSGrant
+	if err := json.Unmarshal([]byte(data), &grant); err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// 生成随机token的辅助函数，refresh token 必须不可猜测
+func generateRandomToken() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return "rt_" + hex.EncodeToString(random), nil
 }
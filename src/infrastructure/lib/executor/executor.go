@@ -42,7 +42,8 @@ func (m *TaskExecutorManager) Execute(task *domainScheduledTask.ScheduledTask) e
 	m.logger.Info("Executing task",
 		zap.Int("task_id", task.ID),
 		zap.String("task_name", task.TaskName),
-		zap.String("task_type", task.TaskType))
+		zap.String("task_type", task.TaskType),
+		zap.String("request_id", task.RequestID))
 
 	return executor.Execute(task)
 }
@@ -46,7 +46,8 @@ func (e *FunctionExecutor) Execute(task *domainScheduledTask.ScheduledTask) erro
 
 	e.logger.Info("Executing function task",
 		zap.Int("task_id", task.ID),
-		zap.String("function_name", params.FunctionName))
+		zap.String("function_name", params.FunctionName),
+		zap.String("request_id", task.RequestID))
 
 	return function(task)
 }
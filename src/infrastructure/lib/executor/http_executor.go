@@ -10,7 +10,13 @@ import (
 	"time"
 
 	domainScheduledTask "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/tracing"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -30,7 +36,8 @@ type HTTPParams struct {
 
 func NewHTTPExecutor(logger *logger.Logger) *HTTPExecutor {
 	return &HTTPExecutor{
-		client: &http.Client{},
+		// the transport creates a client span per call and sends the trace context downstream
+		client: &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
 		logger: logger,
 	}
 }
@@ -47,7 +54,12 @@ func (e *HTTPExecutor) Execute(task *domainScheduledTask.ScheduledTask) error {
 		timeout = params.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	ctx, span := tracing.Tracer().Start(context.Background(), "task.http "+task.TaskName,
+		trace.WithAttributes(
+			attribute.Int("task.id", task.ID),
+			attribute.String("task.request_id", task.RequestID)))
+	defer span.End()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
 	defer cancel()
 
 	// 准备请求体
@@ -76,10 +88,15 @@ func (e *HTTPExecutor) Execute(task *domainScheduledTask.ScheduledTask) error {
 	if params.Body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	// 传递本次执行的请求ID, 任务参数中显式设置的优先
+	if task.RequestID != "" && req.Header.Get(requestid.Header) == "" {
+		req.Header.Set(requestid.Header, task.RequestID)
+	}
 
 	// 发送请求
 	resp, err := e.client.Do(req)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("failed to execute HTTP request: %w", err)
 	}
 	defer resp.Body.Close()
@@ -87,7 +104,8 @@ func (e *HTTPExecutor) Execute(task *domainScheduledTask.ScheduledTask) error {
 	e.logger.Info("HTTP task executed successfully",
 		zap.Int("task_id", task.ID),
 		zap.String("url", params.URL),
-		zap.Int("status_code", resp.StatusCode))
+		zap.Int("status_code", resp.StatusCode),
+		zap.String("request_id", task.RequestID))
 
 	return nil
 }
@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Check reports a dependency as healthy by returning nil
+type Check func(ctx context.Context) error
+
+// CheckResult is the outcome of one check, latency in milliseconds
+type CheckResult struct {
+	Name    string  `json:"name"`
+	Status  Status  `json:"status"`
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Report is the readiness of the service, up only if every check is up and the service is
+// not shutting down
+type Report struct {
+	Status       Status        `json:"status"`
+	ShuttingDown bool          `json:"shutting_down,omitempty"`
+	Checks       []CheckResult `json:"checks"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Probe runs the registered readiness checks concurrently, each bounded by the timeout
+type Probe struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	checks       []namedCheck
+	shuttingDown atomic.Bool
+}
+
+func NewProbe(timeout time.Duration) *Probe {
+	return &Probe{timeout: timeout}
+}
+
+// Register adds a readiness check, checks are reported in registration order
+func (p *Probe) Register(name string, check Check) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checks = append(p.checks, namedCheck{name: name, check: check})
+}
+
+// MarkShuttingDown flips readiness to down so load balancers stop routing new requests while
+// the in-flight ones are drained
+func (p *Probe) MarkShuttingDown() {
+	p.shuttingDown.Store(true)
+}
+
+func (p *Probe) ShuttingDown() bool {
+	return p.shuttingDown.Load()
+}
+
+// Readiness runs every check and aggregates the results
+func (p *Probe) Readiness(ctx context.Context) Report {
+	p.mu.RLock()
+	checks := append([]namedCheck(nil), p.checks...)
+	p.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+			results[i] = run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, ShuttingDown: p.ShuttingDown(), Checks: results}
+	if report.ShuttingDown {
+		report.Status = StatusDown
+	}
+	for _, result := range results {
+		if result.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// run returns once the check ends or the context expires, a hanging check is reported down
+func run(ctx context.Context, c namedCheck) CheckResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- c.check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := CheckResult{
+		Name:    c.name,
+		Status:  StatusUp,
+		Latency: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe_ReadinessAggregatesChecks(t *testing.T) {
+	probe := NewProbe(time.Second)
+	probe.Register("db", func(context.Context) error { return nil })
+	probe.Register("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	report := probe.Readiness(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, "db", report.Checks[0].Name)
+	assert.Equal(t, StatusUp, report.Checks[0].Status)
+	assert.Equal(t, StatusDown, report.Checks[1].Status)
+	assert.Equal(t, "connection refused", report.Checks[1].Error)
+}
+
+func TestProbe_HangingCheckTimesOut(t *testing.T) {
+	probe := NewProbe(20 * time.Millisecond)
+	probe.Register("broker", func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	report := probe.Readiness(context.Background())
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+func TestProbe_NotReadyWhenShuttingDown(t *testing.T) {
+	probe := NewProbe(time.Second)
+	probe.Register("db", func(context.Context) error { return nil })
+	assert.Equal(t, StatusUp, probe.Readiness(context.Background()).Status)
+
+	probe.MarkShuttingDown()
+	report := probe.Readiness(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.True(t, report.ShuttingDown)
+}
@@ -0,0 +1,139 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+)
+
+// Rendition formats, WebP is only produced when Config.WebP is set
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeWebP = "image/webp"
+)
+
+// ErrTooManyPixels rejects images whose decoding would take too much memory
+var ErrTooManyPixels = errors.New("the image has too many pixels to be processed")
+
+// Size is a named thumbnail size, the rendition fits in a Width x Width box
+type Size struct {
+	Name  string
+	Width int
+}
+
+type Config struct {
+	// Sizes rendered on upload, the first one is served when no size is asked
+	Sizes []Size
+	// WebP encodes the renditions as lossless WebP instead of JPEG, or PNG for transparent images
+	WebP bool
+	// StripMetadata removes EXIF, XMP, IPTC and text metadata from stored JPEG and PNG files, the
+	// EXIF orientation is kept
+	StripMetadata bool
+	JPEGQuality   int
+	// MaxPixels bounds the width x height of the images that are decoded
+	MaxPixels int64
+}
+
+var sizeName = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// decodable are the extensions of the images renditions are made of
+var decodable = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true, ".bmp": true}
+
+func DefaultConfig() Config {
+	return Config{
+		Sizes:         []Size{{Name: "small", Width: 128}, {Name: "medium", Width: 512}, {Name: "large", Width: 1024}},
+		StripMetadata: true,
+		JPEGQuality:   85,
+		MaxPixels:     40_000_000,
+	}
+}
+
+// ParseSizes reads a comma separated list of name:width, "none" for no size
+func ParseSizes(value string) ([]Size, error) {
+	if strings.TrimSpace(value) == "none" {
+		return nil, nil
+	}
+	var sizes []Size
+	seen := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		name, width, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || !sizeName.MatchString(name) {
+			return nil, fmt.Errorf("invalid thumbnail size %q, expected name:width", item)
+		}
+		pixels, err := strconv.Atoi(width)
+		if err != nil || pixels < 16 || pixels > 4096 {
+			return nil, fmt.Errorf("invalid width of thumbnail size %q, expected 16 to 4096", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicated thumbnail size %q", name)
+		}
+		seen[name] = true
+		sizes = append(sizes, Size{Name: name, Width: pixels})
+	}
+	return sizes, nil
+}
+
+// LoadConfigFromEnv reads IMAGE_THUMBNAIL_SIZES, IMAGE_THUMBNAIL_WEBP, IMAGE_STRIP_METADATA,
+// IMAGE_JPEG_QUALITY and IMAGE_MAX_MEGAPIXELS
+func LoadConfigFromEnv() (Config, error) {
+	config := DefaultConfig()
+	if value := sharedUtil.GetEnv("IMAGE_THUMBNAIL_SIZES", ""); value != "" {
+		sizes, err := ParseSizes(value)
+		if err != nil {
+			return config, err
+		}
+		config.Sizes = sizes
+	}
+	config.WebP = sharedUtil.GetEnvAsBool("IMAGE_THUMBNAIL_WEBP", config.WebP)
+	config.StripMetadata = sharedUtil.GetEnvAsBool("IMAGE_STRIP_METADATA", config.StripMetadata)
+	if quality := sharedUtil.GetEnvAsInt("IMAGE_JPEG_QUALITY", config.JPEGQuality); quality >= 1 && quality <= 100 {
+		config.JPEGQuality = quality
+	}
+	if megapixels := sharedUtil.GetEnvAsInt("IMAGE_MAX_MEGAPIXELS", 0); megapixels > 0 {
+		config.MaxPixels = int64(megapixels) * 1_000_000
+	}
+	return config, nil
+}
+
+// Processor strips the metadata of uploaded images and renders their thumbnails
+type Processor struct {
+	config Config
+}
+
+func NewProcessor(config Config) *Processor {
+	return &Processor{config: config}
+}
+
+func LoadProcessorFromEnv() (*Processor, error) {
+	config, err := LoadConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewProcessor(config), nil
+}
+
+// Sizes are the thumbnail sizes rendered on upload
+func (p *Processor) Sizes() []Size {
+	return p.config.Sizes
+}
+
+// Size finds a configured size by name, an empty name is the first size
+func (p *Processor) Size(name string) (Size, bool) {
+	for _, size := range p.config.Sizes {
+		if name == "" || size.Name == name {
+			return size, true
+		}
+	}
+	return Size{}, false
+}
+
+// Supported tells whether renditions can be made of a file with this name
+func (p *Processor) Supported(name string) bool {
+	return decodable[strings.ToLower(path.Ext(name))]
+}
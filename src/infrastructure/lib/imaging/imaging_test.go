@@ -0,0 +1,167 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	red  = color.RGBA{R: 255, A: 255}
+	blue = color.RGBA{B: 255, A: 255}
+)
+
+// halves is a 40x20 image, red on the left and blue on the right
+func halves() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			img.SetRGBA(x, y, red)
+			if x >= 20 {
+				img.SetRGBA(x, y, blue)
+			}
+		}
+	}
+	return img
+}
+
+// photo is a JPEG of halves with the metadata of a camera, displayed rotated by orientation 6
+func photo(t *testing.T) []byte {
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, halves(), &jpeg.Options{Quality: 95}))
+	data := encoded.Bytes()
+	exif := append(orientationExif(6), []byte("GPS-SECRET")...)
+	var result []byte
+	result = append(result, data[:2]...)
+	result = append(result, jpegSegmentBytes(markerAPP1, exif)...)
+	result = append(result, jpegSegmentBytes(markerAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<xmp>author</xmp>"))...)
+	result = append(result, jpegSegmentBytes(markerCOM, []byte("secret comment"))...)
+	return append(result, data[2:]...)
+}
+
+func pngChunkBytes(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func isColor(t *testing.T, expected color.RGBA, actual color.Color) {
+	t.Helper()
+	r, g, b, _ := actual.RGBA()
+	assert.InDelta(t, expected.R, r>>8, 40)
+	assert.InDelta(t, expected.G, g>>8, 40)
+	assert.InDelta(t, expected.B, b>>8, 40)
+}
+
+func TestStripMetadataJPEG(t *testing.T) {
+	processor := NewProcessor(DefaultConfig())
+	original := photo(t)
+
+	reader, ok := processor.StripMetadata(bytes.NewReader(original), "image/jpeg")
+	require.True(t, ok)
+	stripped, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.NotContains(t, string(stripped), "GPS-SECRET")
+	assert.NotContains(t, string(stripped), "author")
+	assert.NotContains(t, string(stripped), "secret comment")
+	assert.Equal(t, 6, jpegOrientation(stripped), "the orientation is kept")
+	_, err = jpeg.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+
+	reader, _ = processor.StripMetadata(strings.NewReader("\xff\xd8\xff\xe1\x00"), "image/jpeg")
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, ErrInvalidImage)
+
+	_, ok = processor.StripMetadata(strings.NewReader("GIF89a"), "image/gif")
+	assert.False(t, ok)
+	disabled := NewProcessor(Config{})
+	_, ok = disabled.StripMetadata(bytes.NewReader(original), "image/jpeg")
+	assert.False(t, ok)
+}
+
+func TestStripMetadataPNG(t *testing.T) {
+	var encoded bytes.Buffer
+	require.NoError(t, png.Encode(&encoded, halves()))
+	data := encoded.Bytes()
+	// the signature and IHDR take 33 bytes
+	withText := append(append([]byte{}, data[:33]...), pngChunkBytes("tEXt", []byte("Author\x00someone"))...)
+	withText = append(withText, pngChunkBytes("eXIf", []byte("MM\x00*GPS"))...)
+	withText = append(withText, data[33:]...)
+
+	reader, ok := NewProcessor(DefaultConfig()).StripMetadata(bytes.NewReader(withText), "image/png")
+	require.True(t, ok)
+	stripped, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, stripped)
+}
+
+func TestRender(t *testing.T) {
+	processor := NewProcessor(DefaultConfig())
+	renditions, err := processor.Render(bytes.NewReader(photo(t)), []Size{{Name: "tiny", Width: 10}, {Name: "big", Width: 100}})
+	require.NoError(t, err)
+	require.Len(t, renditions, 2)
+
+	tiny := renditions[0]
+	assert.Equal(t, ContentTypeJPEG, tiny.ContentType)
+	assert.Equal(t, 5, tiny.Width, "orientation 6 turns the image")
+	assert.Equal(t, 10, tiny.Height)
+	decoded, err := jpeg.Decode(bytes.NewReader(tiny.Content))
+	require.NoError(t, err)
+	isColor(t, red, decoded.At(2, 1))
+	isColor(t, blue, decoded.At(2, 8))
+	assert.Equal(t, 1, jpegOrientation(tiny.Content))
+	assert.Equal(t, 20, renditions[1].Width, "images are not enlarged")
+
+	transparent := image.NewNRGBA(image.Rect(0, 0, 30, 30))
+	var encoded bytes.Buffer
+	require.NoError(t, png.Encode(&encoded, transparent))
+	renditions, err = processor.Render(bytes.NewReader(encoded.Bytes()), []Size{{Name: "small", Width: 16}})
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypePNG, renditions[0].ContentType)
+
+	webp := NewProcessor(Config{Sizes: DefaultConfig().Sizes, WebP: true})
+	renditions, err = webp.Render(bytes.NewReader(encoded.Bytes()), []Size{{Name: "small", Width: 16}})
+	require.NoError(t, err)
+	assert.Equal(t, ".webp", renditions[0].Extension)
+	_, format, err := image.Decode(bytes.NewReader(renditions[0].Content))
+	require.NoError(t, err)
+	assert.Equal(t, "webp", format)
+
+	limited := NewProcessor(Config{MaxPixels: 100})
+	_, err = limited.Render(bytes.NewReader(encoded.Bytes()), []Size{{Name: "small", Width: 16}})
+	assert.ErrorIs(t, err, ErrTooManyPixels)
+	_, err = processor.Render(strings.NewReader("not an image"), []Size{{Name: "small", Width: 16}})
+	assert.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestParseSizes(t *testing.T) {
+	sizes, err := ParseSizes("avatar:64, preview:800")
+	require.NoError(t, err)
+	assert.Equal(t, []Size{{Name: "avatar", Width: 64}, {Name: "preview", Width: 800}}, sizes)
+
+	processor := NewProcessor(Config{Sizes: sizes})
+	size, ok := processor.Size("")
+	assert.True(t, ok)
+	assert.Equal(t, "avatar", size.Name)
+	_, ok = processor.Size("large")
+	assert.False(t, ok)
+
+	sizes, err = ParseSizes("none")
+	require.NoError(t, err)
+	assert.Empty(t, sizes)
+	for _, invalid := range []string{"small", "small:8", "Small:64", "a:64,a:128"} {
+		_, err = ParseSizes(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
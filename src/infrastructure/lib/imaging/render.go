@@ -0,0 +1,137 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the decoders of the uploaded formats
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/HugoSmits86/nativewebp"
+	_ "golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Rendition is an encoded thumbnail of an image
+type Rendition struct {
+	Size        Size
+	Width       int
+	Height      int
+	ContentType string
+	// Extension of the encoded format, with its dot
+	Extension string
+	Content   []byte
+}
+
+// Render decodes the image once and encodes a rendition per size. Images are never enlarged, the
+// EXIF orientation of a JPEG is applied and the renditions hold no metadata.
+func (p *Processor) Render(content io.Reader, sizes []Size) ([]Rendition, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if p.config.MaxPixels > 0 && int64(config.Width)*int64(config.Height) > p.config.MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+	source, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	renditions := make([]Rendition, 0, len(sizes))
+	for _, size := range sizes {
+		rendition, err := p.render(source, orientation, size)
+		if err != nil {
+			return nil, err
+		}
+		renditions = append(renditions, *rendition)
+	}
+	return renditions, nil
+}
+
+func (p *Processor) render(source image.Image, orientation int, size Size) (*Rendition, error) {
+	bounds := source.Bounds()
+	// the box is square, the orientation applied after scaling doesn't change the fit
+	width, height := fit(bounds.Dx(), bounds.Dy(), size.Width)
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), source, bounds, draw.Src, nil)
+	oriented := orient(scaled, orientation)
+
+	var buffer bytes.Buffer
+	rendition := &Rendition{Size: size, Width: oriented.Bounds().Dx(), Height: oriented.Bounds().Dy()}
+	var err error
+	switch {
+	case p.config.WebP:
+		rendition.ContentType, rendition.Extension = ContentTypeWebP, ".webp"
+		err = nativewebp.Encode(&buffer, oriented, nil)
+	case oriented.Opaque():
+		rendition.ContentType, rendition.Extension = ContentTypeJPEG, ".jpg"
+		err = jpeg.Encode(&buffer, oriented, &jpeg.Options{Quality: p.config.JPEGQuality})
+	default:
+		rendition.ContentType, rendition.Extension = ContentTypePNG, ".png"
+		err = png.Encode(&buffer, oriented)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rendition.Content = buffer.Bytes()
+	return rendition, nil
+}
+
+// fit scales width and height down to fit in a box x box square
+func fit(width, height, box int) (int, int) {
+	if width <= box && height <= box {
+		return width, height
+	}
+	if width >= height {
+		return box, max(1, (height*box+width/2)/width)
+	}
+	return max(1, (width*box+height/2)/height), box
+}
+
+// orient applies an EXIF orientation, the result is the image as it has to be displayed
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
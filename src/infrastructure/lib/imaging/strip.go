@@ -0,0 +1,261 @@
+package imaging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrInvalidImage is returned while stripping content that doesn't have the structure of its type
+var ErrInvalidImage = errors.New("invalid image structure")
+
+// JPEG markers
+const (
+	markerSOI  = 0xD8
+	markerEOI  = 0xD9
+	markerSOS  = 0xDA
+	markerAPP1 = 0xE1
+	// APP13 holds the Photoshop resources with the IPTC metadata
+	markerAPP13 = 0xED
+	markerCOM   = 0xFE
+)
+
+var (
+	exifHeader   = []byte("Exif\x00\x00")
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	// pngMetadata are the PNG chunks dropped, the ones describing the pixels are kept
+	pngMetadata = map[string]bool{"tEXt": true, "zTXt": true, "iTXt": true, "eXIf": true, "tIME": true}
+)
+
+// StripMetadata returns the content without its metadata when the content type is one it knows
+// how to strip. The content is processed as it is read, a malformed image fails the read with
+// ErrInvalidImage.
+func (p *Processor) StripMetadata(content io.Reader, contentType string) (io.Reader, bool) {
+	if !p.config.StripMetadata {
+		return content, false
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case ContentTypeJPEG:
+		return &stripReader{src: bufio.NewReader(content), step: jpegStart}, true
+	case ContentTypePNG:
+		return &stripReader{src: bufio.NewReader(content), step: pngStart}, true
+	}
+	return content, false
+}
+
+// stripReader hands out the pending bytes produced by step, then copies raw bytes of the source
+// as is before the next step; raw is -1 once the rest of the source is copied
+type stripReader struct {
+	src     *bufio.Reader
+	pending []byte
+	raw     int64
+	step    func(*stripReader) error
+	err     error
+}
+
+func (s *stripReader) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 && s.raw == 0 && s.err == nil {
+		s.err = s.step(s)
+	}
+	if len(s.pending) > 0 {
+		n := copy(p, s.pending)
+		s.pending = s.pending[n:]
+		return n, nil
+	}
+	if s.raw < 0 {
+		return s.src.Read(p)
+	}
+	if s.raw > 0 {
+		if int64(len(p)) > s.raw {
+			p = p[:s.raw]
+		}
+		n, err := s.src.Read(p)
+		s.raw -= int64(n)
+		if errors.Is(err, io.EOF) {
+			err = nil
+			if s.raw > 0 {
+				err = ErrInvalidImage
+			}
+		}
+		return n, err
+	}
+	return 0, s.err
+}
+
+func jpegStart(s *stripReader) error {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(s.src, head); err != nil || head[0] != 0xFF || head[1] != markerSOI {
+		return ErrInvalidImage
+	}
+	s.pending = head
+	s.step = jpegSegment
+	return nil
+}
+
+// jpegSegment keeps the segment read unless it is metadata, an EXIF segment is replaced by one
+// holding only the orientation. The scan data after SOS is copied as is.
+func jpegSegment(s *stripReader) error {
+	marker, payload, err := readJPEGSegment(s.src)
+	if err != nil {
+		return err
+	}
+	switch {
+	case marker == markerSOS || marker == markerEOI:
+		s.pending = []byte{0xFF, marker}
+		s.raw = -1
+	case payload == nil:
+		s.pending = []byte{0xFF, marker}
+	case marker == markerAPP1:
+		if bytes.HasPrefix(payload, exifHeader) {
+			if orientation := exifOrientation(payload[len(exifHeader):]); orientation > 1 {
+				s.pending = jpegSegmentBytes(markerAPP1, orientationExif(orientation))
+			}
+		}
+	case marker == markerAPP13 || marker == markerCOM:
+	default:
+		s.pending = jpegSegmentBytes(marker, payload)
+	}
+	return nil
+}
+
+// readJPEGSegment reads the next marker and its payload, nil for the markers without one. The
+// payload of SOS isn't read.
+func readJPEGSegment(src *bufio.Reader) (byte, []byte, error) {
+	first, err := src.ReadByte()
+	if err != nil || first != 0xFF {
+		return 0, nil, ErrInvalidImage
+	}
+	marker := byte(0xFF)
+	// markers may be preceded by fill bytes
+	for marker == 0xFF {
+		if marker, err = src.ReadByte(); err != nil {
+			return 0, nil, ErrInvalidImage
+		}
+	}
+	if marker == markerSOS || marker == markerEOI || marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+		return marker, nil, nil
+	}
+	length := make([]byte, 2)
+	if _, err := io.ReadFull(src, length); err != nil {
+		return 0, nil, ErrInvalidImage
+	}
+	size := int(binary.BigEndian.Uint16(length))
+	if size < 2 {
+		return 0, nil, ErrInvalidImage
+	}
+	payload := make([]byte, size-2)
+	if _, err := io.ReadFull(src, payload); err != nil {
+		return 0, nil, ErrInvalidImage
+	}
+	return marker, payload, nil
+}
+
+func jpegSegmentBytes(marker byte, payload []byte) []byte {
+	segment := make([]byte, 4, 4+len(payload))
+	segment[0], segment[1] = 0xFF, marker
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// jpegOrientation is the EXIF orientation of a JPEG, 1 when it has none
+func jpegOrientation(data []byte) int {
+	src := bufio.NewReader(bytes.NewReader(data))
+	if head, err := src.Peek(2); err != nil || head[0] != 0xFF || head[1] != markerSOI {
+		return 1
+	}
+	_, _ = src.Discard(2)
+	for {
+		marker, payload, err := readJPEGSegment(src)
+		if err != nil || marker == markerSOS || marker == markerEOI {
+			return 1
+		}
+		if marker == markerAPP1 && bytes.HasPrefix(payload, exifHeader) {
+			if orientation := exifOrientation(payload[len(exifHeader):]); orientation > 1 {
+				return orientation
+			}
+			return 1
+		}
+	}
+}
+
+// exifOrientation reads the orientation tag of the first IFD of a TIFF structure, 0 when absent
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	offset := int64(order.Uint32(tiff[4:8]))
+	if offset+2 > int64(len(tiff)) {
+		return 0
+	}
+	count := int64(order.Uint16(tiff[offset:]))
+	for i := int64(0); i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > int64(len(tiff)) {
+			return 0
+		}
+		// orientation is a SHORT stored in the value field
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// orientationExif is an EXIF payload holding only the orientation tag
+func orientationExif(orientation int) []byte {
+	payload := append([]byte{}, exifHeader...)
+	payload = append(payload, 'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1)
+	payload = append(payload, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, byte(orientation), 0, 0)
+	return append(payload, 0, 0, 0, 0)
+}
+
+func pngStart(s *stripReader) error {
+	head := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(s.src, head); err != nil || !bytes.Equal(head, pngSignature) {
+		return ErrInvalidImage
+	}
+	s.pending = head
+	s.step = pngChunk
+	return nil
+}
+
+// pngChunk skips the metadata chunks and copies the others, the content after IEND is copied
+// as is
+func pngChunk(s *stripReader) error {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(s.src, header); err != nil {
+		return ErrInvalidImage
+	}
+	// the data and the CRC follow the header
+	length := int64(binary.BigEndian.Uint32(header)) + 4
+	chunkType := string(header[4:])
+	switch {
+	case chunkType == "IEND":
+		s.pending = header
+		s.raw = -1
+	case pngMetadata[chunkType]:
+		if discarded, err := s.src.Discard(int(length)); err != nil || int64(discarded) != length {
+			return ErrInvalidImage
+		}
+	default:
+		s.pending = header
+		s.raw = length
+	}
+	return nil
+}
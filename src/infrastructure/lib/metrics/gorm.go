@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// GormPlugin observes DBQueryDuration from the gorm callbacks, the operation is taken from the
+// built statement so the bound values are never inlined just to label a query
+type GormPlugin struct{}
+
+type gormRegister interface {
+	Register(name string, fn func(*gorm.DB)) error
+}
+
+func (GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		before, after gormRegister
+		name          string
+	}{
+		{cb.Create().Before("gorm:create"), cb.Create().After("gorm:create"), "create"},
+		{cb.Query().Before("gorm:query"), cb.Query().After("gorm:query"), "query"},
+		{cb.Update().Before("gorm:update"), cb.Update().After("gorm:update"), "update"},
+		{cb.Delete().Before("gorm:delete"), cb.Delete().After("gorm:delete"), "delete"},
+		{cb.Row().Before("gorm:row"), cb.Row().After("gorm:row"), "row"},
+		{cb.Raw().Before("gorm:raw"), cb.Raw().After("gorm:raw"), "raw"},
+	}
+	for _, hook := range hooks {
+		if err := hook.before.Register("metrics:before_"+hook.name, startQuery); err != nil {
+			return err
+		}
+		if err := hook.after.Register("metrics:after_"+hook.name, observeQuery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func observeQuery(db *gorm.DB) {
+	value, ok := db.InstanceGet(startKey)
+	if !ok {
+		return
+	}
+	start, _ := value.(time.Time)
+	failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+	ObserveDBQuery(db.Statement.SQL.String(), time.Since(start), failed)
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "microservices"
+
+// Path is where the metrics are served, scrapes are not recorded as operations
+const Path = "/metrics"
+
+// Registry holds every collector of the application, exposed by Handler on /metrics
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// HTTP requests, labelled by gin route template so /v1/user/12 and /v1/user/13 share a series
+var (
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+)
+
+// Database queries, observed by GormPlugin
+var DBQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "GORM query latency by statement type and result.",
+	Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+}, []string{"operation", "result"})
+
+// Scheduled task runs, labelled by task name
+var (
+	TaskRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "task_runs_total",
+		Help:      "Scheduled task executions.",
+	}, []string{"task"})
+
+	TaskFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "task_failures_total",
+		Help:      "Scheduled task executions that returned an error.",
+	}, []string{"task"})
+
+	TaskDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "task_duration_seconds",
+		Help:      "Scheduled task execution time.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 900},
+	}, []string{"task"})
+)
+
+// Event bus traffic, result is "ok" or "error"
+var (
+	EventsPublished = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "event_bus",
+		Name:      "published_total",
+		Help:      "Events published by event type and result.",
+	}, []string{"event_type", "result"})
+
+	EventsHandled = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "event_bus",
+		Name:      "handled_total",
+		Help:      "Event handler invocations by event type and result.",
+	}, []string{"event_type", "result"})
+)
+
+// WebSocket connections
+var (
+	WebSocketConnections = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "websocket",
+		Name:      "connections",
+		Help:      "Open WebSocket connections.",
+	})
+
+	WebSocketConnectionsTotal = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "websocket",
+		Name:      "connections_total",
+		Help:      "WebSocket connections accepted since start.",
+	})
+)
+
+// Result turns an error into the result label value
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// ObserveDBQuery records a query duration, the operation is the statement's leading keyword
+func ObserveDBQuery(sql string, elapsed time.Duration, failed bool) {
+	result := "ok"
+	if failed {
+		result = "error"
+	}
+	DBQueryDuration.WithLabelValues(sqlOperation(sql), result).Observe(elapsed.Seconds())
+}
+
+func sqlOperation(sql string) string {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	switch keyword = strings.ToLower(keyword); keyword {
+	case "select", "insert", "update", "delete", "with":
+		return keyword
+	default:
+		return "other"
+	}
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+func TestSQLOperation(t *testing.T) {
+	assert.Equal(t, "select", sqlOperation(`SELECT * FROM "users" WHERE id = 1`))
+	assert.Equal(t, "insert", sqlOperation("  insert into sys_files values (1)"))
+	assert.Equal(t, "with", sqlOperation("WITH ranked AS (SELECT 1) SELECT * FROM ranked"))
+	assert.Equal(t, "other", sqlOperation("BEGIN"))
+	assert.Equal(t, "other", sqlOperation(""))
+}
+
+func TestObserveDBQuery(t *testing.T) {
+	before := testutil.CollectAndCount(DBQueryDuration)
+	ObserveDBQuery("DELETE FROM sys_apis", time.Millisecond, true)
+	assert.Equal(t, before+1, testutil.CollectAndCount(DBQueryDuration))
+}
+
+func TestGormPlugin(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: gormLogger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(GormPlugin{}))
+
+	count := func(operation string) uint64 {
+		metric := &dto.Metric{}
+		observer, _ := DBQueryDuration.GetMetricWithLabelValues(operation, "ok")
+		_ = observer.(prometheus.Metric).Write(metric)
+		return metric.GetHistogram().GetSampleCount()
+	}
+	selects, deletes := count("select"), count("delete")
+	var rows []struct{ ID int }
+	db.Table("sys_apis").Where("id = ?", 1).Find(&rows)
+	db.Exec("DELETE FROM sys_apis WHERE id = ?", 1)
+	assert.Equal(t, selects+1, count("select"))
+	assert.Equal(t, deletes+1, count("delete"))
+}
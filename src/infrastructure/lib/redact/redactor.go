@@ -0,0 +1,225 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+)
+
+// Mask replaces redacted values
+const Mask = "******"
+
+type Config struct {
+	// Keys are matched against every object key, ignoring case, "_" and "-", anywhere in the key, so
+	// "token" also covers "jwtAccessToken" and "refresh_token" and "password" "password_confirmation"
+	Keys []string
+	// Paths are dot separated JSON paths such as "data.user.phone", "*" matches any key and arrays
+	// are traversed transparently
+	Paths []string
+	// MaxBodySize truncates bodies after redaction, 0 keeps them whole
+	MaxBodySize int
+	// ContentTypes lists the media types whose bodies are kept, a trailing "/*" matches a whole type
+	ContentTypes []string
+}
+
+var defaultKeys = []string{"password", "token", "secret", "access_key_secret", "authorization"}
+
+var defaultContentTypes = []string{"application/json", "application/x-www-form-urlencoded", "text/*"}
+
+// LoadConfigFromEnv reads the OPERATION_LOG_REDACT_* variables, lists are comma separated
+func LoadConfigFromEnv() Config {
+	config := Config{
+		Keys:         sharedUtil.GetEnvAsList("OPERATION_LOG_REDACT_KEYS", defaultKeys),
+		Paths:        sharedUtil.GetEnvAsList("OPERATION_LOG_REDACT_PATHS", nil),
+		MaxBodySize:  4096,
+		ContentTypes: sharedUtil.GetEnvAsList("OPERATION_LOG_CONTENT_TYPES", defaultContentTypes),
+	}
+	if size := sharedUtil.GetEnvAsInt("OPERATION_LOG_MAX_BODY_SIZE", config.MaxBodySize); size >= 0 {
+		config.MaxBodySize = size
+	}
+	return config
+}
+
+// Redactor masks sensitive values in request and response bodies before they are persisted
+type Redactor struct {
+	config  Config
+	keys    []string
+	paths   [][]string
+	pattern *regexp.Regexp
+}
+
+func NewRedactor(config Config) *Redactor {
+	r := &Redactor{config: config}
+	for _, key := range config.Keys {
+		if key = normalizeKey(key); key != "" {
+			r.keys = append(r.keys, key)
+		}
+	}
+	for _, path := range config.Paths {
+		path = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(path), "$"), ".")
+		if path != "" {
+			r.paths = append(r.paths, strings.Split(path, "."))
+		}
+	}
+	// fallback for bodies that are not valid JSON, e.g. truncated ones: mask "key": "value" pairs
+	r.pattern = regexp.MustCompile(`("([^"\\]*)"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\]\s]+)`)
+	return r
+}
+
+// MaxBodySize is the number of bytes worth capturing for a body
+func (r *Redactor) MaxBodySize() int {
+	return r.config.MaxBodySize
+}
+
+// Redact masks the sensitive values of the body, drops bodies whose content type is not allowed
+// and truncates the result to the configured size
+func (r *Redactor) Redact(body string, contentType string) string {
+	if body == "" {
+		return body
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	if !r.allowed(mediaType) {
+		return fmt.Sprintf("[omitted %s body]", mediaType)
+	}
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		body = r.redactForm(body)
+	default:
+		body = r.redactJSON(body)
+	}
+	return r.truncate(body)
+}
+
+func (r *Redactor) allowed(mediaType string) bool {
+	// bodies without a content type are usually JSON sent by loose clients
+	if mediaType == "" {
+		return true
+	}
+	for _, allowed := range r.config.ContentTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mediaType || allowed == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) redactJSON(body string) string {
+	// UseNumber keeps large ids intact instead of turning them into floats
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return r.redactText(body)
+	}
+	value = r.redactValue(value)
+	for _, path := range r.paths {
+		value = redactPath(value, path)
+	}
+	var redacted strings.Builder
+	encoder := json.NewEncoder(&redacted)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return r.redactText(body)
+	}
+	return strings.TrimSuffix(redacted.String(), "\n")
+}
+
+func (r *Redactor) redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if r.sensitive(key) {
+				v[key] = Mask
+				continue
+			}
+			v[key] = r.redactValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = r.redactValue(item)
+		}
+	}
+	return value
+}
+
+func redactPath(value any, path []string) any {
+	if len(path) == 0 {
+		return Mask
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if path[0] == "*" || path[0] == key {
+				v[key] = redactPath(item, path[1:])
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactPath(item, path)
+		}
+	}
+	return value
+}
+
+func (r *Redactor) redactText(body string) string {
+	return r.pattern.ReplaceAllStringFunc(body, func(match string) string {
+		groups := r.pattern.FindStringSubmatch(match)
+		if !r.sensitive(groups[2]) {
+			return match
+		}
+		return groups[1] + `"` + Mask + `"`
+	})
+}
+
+func (r *Redactor) redactForm(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return r.redactText(body)
+	}
+	for key := range values {
+		if r.sensitive(key) {
+			values[key] = []string{Mask}
+		}
+	}
+	return values.Encode()
+}
+
+func (r *Redactor) sensitive(key string) bool {
+	key = normalizeKey(key)
+	for _, rule := range r.keys {
+		if strings.Contains(key, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) truncate(body string) string {
+	if r.config.MaxBodySize <= 0 || len(body) <= r.config.MaxBodySize {
+		return body
+	}
+	// cut before the rune the limit falls in, a broken UTF-8 sequence would not be stored
+	cut := r.config.MaxBodySize
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return body[:cut] + "...(truncated)"
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
@@ -0,0 +1,80 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRedactor(paths ...string) *Redactor {
+	return NewRedactor(Config{
+		Keys:         defaultKeys,
+		Paths:        paths,
+		MaxBodySize:  4096,
+		ContentTypes: defaultContentTypes,
+	})
+}
+
+func TestRedact_SigninPassword(t *testing.T) {
+	r := newTestRedactor()
+	body := r.Redact(`{"email":"admin@example.com","password":"s3cret"}`, "application/json; charset=utf-8")
+	assert.NotContains(t, body, "s3cret")
+	assert.Contains(t, body, `"password":"******"`)
+	assert.Contains(t, body, "admin@example.com")
+}
+
+func TestRedact_EditPasswordAndNestedTokens(t *testing.T) {
+	r := newTestRedactor()
+	body := r.Redact(`{"oldPassword":"old","newPassword":"new"}`, "application/json")
+	assert.Equal(t, `{"newPassword":"******","oldPassword":"******"}`, body)
+
+	resp := r.Redact(`{"data":{"userinfo":{"id":12345678901234567},"security":{"jwtAccessToken":"eyJ.a.b","jwtRefreshToken":"eyJ.c.d"}}}`, "application/json")
+	assert.NotContains(t, resp, "eyJ")
+	assert.Contains(t, resp, "12345678901234567")
+}
+
+func TestRedact_KeysContainingSensitiveWords(t *testing.T) {
+	r := newTestRedactor()
+	body := r.Redact(`{"user":{"password_confirmation":"p1","new_password":"p2","profile":{"accessToken":"t1","Client-Secret-Key":"s1"}},"name":"a"}`,
+		"application/json")
+	for _, value := range []string{"p1", "p2", "t1", "s1"} {
+		assert.NotContains(t, body, `"`+value+`"`)
+	}
+	assert.Contains(t, body, `"name":"a"`)
+}
+
+func TestRedact_JSONPath(t *testing.T) {
+	r := newTestRedactor("$.data.*.phone", "items.email")
+	body := r.Redact(`{"data":{"user":{"phone":"555","name":"a"}},"items":[{"email":"x@y.z"},{"email":"w@y.z"}]}`, "application/json")
+	assert.NotContains(t, body, "555")
+	assert.NotContains(t, body, "@y.z")
+	assert.Contains(t, body, `"name":"a"`)
+}
+
+func TestRedact_TruncatedJSONFallsBackToPattern(t *testing.T) {
+	r := newTestRedactor()
+	body := r.Redact(`{"user":"a","access_key_secret":"abc\"def","token":"eyJhbGciOi`, "application/json")
+	assert.NotContains(t, body, "abc")
+	assert.NotContains(t, body, "eyJ")
+	assert.Contains(t, body, `"user":"a"`)
+}
+
+func TestRedact_Form(t *testing.T) {
+	r := newTestRedactor()
+	body := r.Redact("username=a&password=b", "application/x-www-form-urlencoded")
+	assert.Equal(t, "password=%2A%2A%2A%2A%2A%2A&username=a", body)
+}
+
+func TestRedact_ContentTypeAndSize(t *testing.T) {
+	r := NewRedactor(Config{Keys: defaultKeys, MaxBodySize: 10, ContentTypes: []string{"text/*"}})
+	assert.Equal(t, "[omitted application/octet-stream body]", r.Redact("\x00\x01", "application/octet-stream"))
+	assert.Equal(t, "abcdefghij...(truncated)", r.Redact(strings.Repeat("abcdefghij", 3), "text/plain"))
+
+	// "é" takes two bytes and "中" three, the limit falls inside them
+	assert.Equal(t, "abcdefghi...(truncated)", r.Redact("abcdefghié"+strings.Repeat("x", 10), "text/plain"))
+	truncated := r.Redact("abcdefgh中文", "text/plain")
+	assert.Equal(t, "abcdefgh...(truncated)", truncated)
+	assert.True(t, utf8.ValidString(truncated))
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -47,6 +48,11 @@ func InitRedisClient(loggerInstance *logger.Logger) (*redis.Client, error) {
 		WriteTimeout: 3 * time.Second,
 	})
 
+	// 为每条命令创建span
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		return nil, fmt.Errorf("failed to instrument Redis tracing: %w", err)
+	}
+
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
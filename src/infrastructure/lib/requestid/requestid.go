@@ -0,0 +1,53 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header carries the request id in http requests and responses
+	Header = "X-Request-ID"
+	// ContextKey stores the request id in the gin context
+	ContextKey = "request_id"
+	// MetadataKey stores the request id in event metadata and message headers
+	MetadataKey = "request_id"
+	// maxLength bounds the ids accepted from clients
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// New returns a fresh request id
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether a client supplied id can be kept: not empty, at most 128 characters
+// and only printable ASCII without spaces, so it can't break log lines or headers
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithContext returns a copy of ctx carrying the request id
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id carried by ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// clamavChunkSize is the size of the INSTREAM chunks, clamd rejects chunks above StreamMaxLength
+const clamavChunkSize = 64 << 10
+
+// ClamAVScanner streams the content to clamd with the INSTREAM command. The content is scanned
+// as it arrives, so uploads above clamd's StreamMaxLength fail the scan.
+type ClamAVScanner struct {
+	network string
+	address string
+	// timeout bounds every write and the wait for the verdict
+	timeout time.Duration
+}
+
+func NewClamAVScanner(address string, timeout time.Duration) (*ClamAVScanner, error) {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CLAMAV_ADDRESS %q: %w", address, err)
+	}
+	switch parsed.Scheme {
+	case "tcp":
+		return &ClamAVScanner{network: "tcp", address: parsed.Host, timeout: timeout}, nil
+	case "unix":
+		return &ClamAVScanner{network: "unix", address: parsed.Path, timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("CLAMAV_ADDRESS %q must start with tcp:// or unix://", address)
+	}
+}
+
+func (s *ClamAVScanner) Name() string {
+	return NameClamAV
+}
+
+// Scan implements Scanner.
+func (s *ClamAVScanner) Scan(ctx context.Context, content io.Reader) (*Result, error) {
+	// the rest of the content is drained whatever happens, the storage reads it too
+	defer func() { _, _ = io.Copy(io.Discard, content) }()
+
+	var dialer net.Dialer
+	dialCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	conn, err := dialer.DialContext(dialCtx, s.network, s.address)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("clamav: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err := s.stream(conn, content); err != nil {
+		return nil, err
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(s.timeout))
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return nil, fmt.Errorf("clamav: reading the verdict: %w", err)
+	}
+	return parseReply(reply)
+}
+
+// stream sends the INSTREAM command and the content as length prefixed chunks. When clamd
+// closes the stream early, e.g. over its size limit, its reply is still read.
+func (s *ClamAVScanner) stream(conn net.Conn, content io.Reader) error {
+	write := func(p []byte) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(s.timeout))
+		_, err := conn.Write(p)
+		return err
+	}
+	if err := write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("clamav: %w", err)
+	}
+	chunk := make([]byte, 4+clamavChunkSize)
+	for {
+		n, readErr := io.ReadFull(content, chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if err := write(chunk[:4+n]); err != nil {
+				return nil
+			}
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	_ = write([]byte{0, 0, 0, 0})
+	return nil
+}
+
+// parseReply reads "stream: OK", "stream: <signature> FOUND" or "<message> ERROR"
+func parseReply(reply string) (*Result, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	verdict := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case verdict == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(verdict, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamav: %s", reply)
+	}
+}
@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClamd answers INSTREAM like clamd: FOUND when the stream contains EICAR, a size limit
+// error once more than maxLength bytes arrived
+func fakeClamd(t *testing.T, maxLength int) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveClamd(conn, maxLength)
+		}
+	}()
+	return "tcp://" + listener.Addr().String()
+}
+
+func serveClamd(conn net.Conn, maxLength int) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	if command, err := reader.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+		_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+	var received bytes.Buffer
+	for {
+		var size uint32
+		if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+			return
+		}
+		if size == 0 {
+			break
+		}
+		if _, err := io.CopyN(&received, reader, int64(size)); err != nil {
+			return
+		}
+		if received.Len() > maxLength {
+			_, _ = conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+			return
+		}
+	}
+	if strings.Contains(received.String(), "EICAR") {
+		_, _ = conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		return
+	}
+	_, _ = conn.Write([]byte("stream: OK\x00"))
+}
+
+func TestClamAVScanner(t *testing.T) {
+	scanner, err := NewClamAVScanner(fakeClamd(t, 1<<20), 5*time.Second)
+	require.NoError(t, err)
+
+	result, err := scanner.Scan(context.Background(), strings.NewReader("harmless content"))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	large := strings.Repeat("a", 3*clamavChunkSize) + "EICAR"
+	result, err = scanner.Scan(context.Background(), strings.NewReader(large))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+
+	content := strings.NewReader(strings.Repeat("b", 2<<20))
+	_, err = scanner.Scan(context.Background(), content)
+	assert.ErrorContains(t, err, "size limit exceeded")
+	assert.Zero(t, content.Len(), "the content is drained")
+}
+
+func TestNewClamAVScannerAddress(t *testing.T) {
+	scanner, err := NewClamAVScanner("unix:///var/run/clamav/clamd.ctl", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "unix", scanner.network)
+	assert.Equal(t, "/var/run/clamav/clamd.ctl", scanner.address)
+
+	_, err = NewClamAVScanner("localhost:3310", time.Second)
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+)
+
+// Scanner names, UPLOAD_SCANNER selects one
+const (
+	NameNone   = "none"
+	NameClamAV = "clamav"
+)
+
+// Result is the verdict on a scanned content, Signature names the threat found
+type Result struct {
+	Infected  bool
+	Signature string
+}
+
+// Scanner checks uploaded content for malware. Scan reads the content to its end even when it
+// stops scanning early, the content is streamed to the storage at the same time.
+type Scanner interface {
+	Name() string
+	Scan(ctx context.Context, content io.Reader) (*Result, error)
+}
+
+// NoopScanner accepts everything, it is the default when no scanner is configured
+type NoopScanner struct{}
+
+func (NoopScanner) Name() string {
+	return NameNone
+}
+
+func (NoopScanner) Scan(_ context.Context, content io.Reader) (*Result, error) {
+	_, err := io.Copy(io.Discard, content)
+	return &Result{}, err
+}
+
+// LoadScannerFromEnv builds the scanner selected by UPLOAD_SCANNER, clamav connects to
+// CLAMAV_ADDRESS, tcp://host:port or unix:///path/clamd.sock
+func LoadScannerFromEnv() (Scanner, error) {
+	switch name := sharedUtil.GetEnv("UPLOAD_SCANNER", ""); name {
+	case "", NameNone:
+		return NoopScanner{}, nil
+	case NameClamAV:
+		timeout := sharedUtil.GetEnvAsInt("CLAMAV_TIMEOUT_SECONDS", 60)
+		if timeout <= 0 {
+			timeout = 60
+		}
+		address := sharedUtil.GetEnv("CLAMAV_ADDRESS", "")
+		if address == "" {
+			address = "tcp://localhost:3310"
+		}
+		return NewClamAVScanner(address, time.Duration(timeout)*time.Second)
+	default:
+		return nil, fmt.Errorf("upload scanner %q is unknown", name)
+	}
+}
@@ -2,6 +2,7 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,8 +13,11 @@ import (
 	scheduleTaskConstants "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task/constants"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
 	domainScheduledTask "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/executor"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/scheduled_task"
 	"github.com/go-co-op/gocron"
@@ -54,6 +58,11 @@ func (s *TaskScheduler) Start() {
 	s.logger.Info("Task scheduler started")
 }
 
+// IsRunning reports whether the scheduler has been started and not stopped
+func (s *TaskScheduler) IsRunning() bool {
+	return s.scheduler.IsRunning()
+}
+
 func (s *TaskScheduler) Stop() {
 	s.scheduler.Stop()
 	s.logger.Info("Task scheduler stopped")
@@ -65,7 +74,7 @@ func (s *TaskScheduler) loadTasks() {
 		},
 	}
 
-	result, err := s.repo.SearchPaginated(filters)
+	result, err := s.repo.SearchPaginated(context.Background(), filters)
 	if err != nil {
 		s.logger.Error("Failed to load tasks", zap.Error(err))
 		return
@@ -148,9 +157,14 @@ func (s *TaskScheduler) executeTask(task *domainScheduledTask.ScheduledTask) {
 		}
 		s.mutex.Unlock()
 	}()
+	// 每次执行使用独立的副本和请求ID, 便于关联同一次执行的日志、事件和外部请求
+	run := *task
+	run.RequestID = requestid.New()
+	task = &run
 	s.logger.Info("Executing task",
 		zap.Int("task_id", task.ID),
-		zap.String("task_name", task.TaskName))
+		zap.String("task_name", task.TaskName),
+		zap.String("request_id", task.RequestID))
 
 	// 更新任务状态为"运行中"
 	now := time.Now()
@@ -159,7 +173,7 @@ func (s *TaskScheduler) executeTask(task *domainScheduledTask.ScheduledTask) {
 		"last_execute_time": &now,
 	}
 
-	_, err := s.repo.Update(task.ID, updateData)
+	_, err := s.repo.Update(context.Background(), task.ID, domainAuditLog.SystemActor, updateData)
 	if err != nil {
 		s.logger.Error("Failed to update task status to running",
 			zap.Int("task_id", task.ID),
@@ -167,7 +181,13 @@ func (s *TaskScheduler) executeTask(task *domainScheduledTask.ScheduledTask) {
 	}
 
 	// 执行任务
+	started := time.Now()
 	err = s.executor.Execute(task)
+	metrics.TaskRuns.WithLabelValues(task.TaskName).Inc()
+	metrics.TaskDuration.WithLabelValues(task.TaskName).Observe(time.Since(started).Seconds())
+	if err != nil {
+		metrics.TaskFailures.WithLabelValues(task.TaskName).Inc()
+	}
 
 	// 执行完成后，根据任务类型更新状态
 	// 对于周期性任务，执行完成后恢复为"启用"状态
@@ -186,6 +206,7 @@ func (s *TaskScheduler) executeTask(task *domainScheduledTask.ScheduledTask) {
 		s.logger.Error("Task execution failed",
 			zap.Int("task_id", task.ID),
 			zap.String("task_name", task.TaskName),
+			zap.String("request_id", task.RequestID),
 			zap.Error(err))
 
 		// 执行失败，更新状态为"错误"
@@ -193,7 +214,8 @@ func (s *TaskScheduler) executeTask(task *domainScheduledTask.ScheduledTask) {
 	} else {
 		s.logger.Info("Task executed successfully",
 			zap.Int("task_id", task.ID),
-			zap.String("task_name", task.TaskName))
+			zap.String("task_name", task.TaskName),
+			zap.String("request_id", task.RequestID))
 	}
 
 	// 更新执行结果状态
@@ -201,7 +223,7 @@ func (s *TaskScheduler) executeTask(task *domainScheduledTask.ScheduledTask) {
 		"status": finalStatus,
 	}
 
-	_, err = s.repo.Update(task.ID, updateData)
+	_, err = s.repo.Update(context.Background(), task.ID, domainAuditLog.SystemActor, updateData)
 	if err != nil {
 		s.logger.Error("Failed to update task execution result",
 			zap.Int("task_id", task.ID),
@@ -255,7 +277,7 @@ func (s *TaskScheduler) StopAllTasks() {
 // StartTask 启动单个任务
 func (s *TaskScheduler) StartTask(taskID int) error {
 	// 先从数据库获取任务（在锁外面进行数据库操作）
-	task, err := s.repo.GetByID(taskID)
+	task, err := s.repo.GetByID(context.Background(), taskID)
 	if err != nil {
 		s.logger.Error("Failed to get task by ID",
 			zap.Int("task_id", taskID),
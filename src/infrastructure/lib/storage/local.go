@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalSignedPath is the route serving the signed urls of local files, followed by the key
+const LocalSignedPath = "/v1/file/signed/"
+
+// LocalConfig stores files under Root, BaseURL is prepended to the key to build the file url.
+// The default root "." keeps the keys equal to the paths the files were saved at before the
+// drivers existed, e.g. public/1700000000.png served by the /public route. Signer signs the
+// urls of LocalSignedPath, nil disables SignedURL.
+type LocalConfig struct {
+	Root    string
+	BaseURL string
+	Signer  *URLSigner
+}
+
+type LocalDriver struct {
+	root    string
+	baseURL string
+	signer  *URLSigner
+}
+
+func NewLocalDriver(config LocalConfig) *LocalDriver {
+	return &LocalDriver{root: config.Root, baseURL: strings.TrimSuffix(config.BaseURL, "/"), signer: config.Signer}
+}
+
+func (d *LocalDriver) Name() string {
+	return EngineLocal
+}
+
+func (d *LocalDriver) path(key string) (string, error) {
+	if err := ValidKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(d.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes to a temporary file renamed into place once complete, readers never see a
+// partial file
+func (d *LocalDriver) Put(_ context.Context, key string, content io.Reader, _ int64, _ PutOptions) error {
+	target, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := io.Copy(tmp, content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (d *LocalDriver) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	target, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (d *LocalDriver) Delete(_ context.Context, key string) error {
+	target, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Stat hashes the file to fill the ETag, like the md5 etag of a single part upload
+func (d *LocalDriver) Stat(_ context.Context, key string) (*ObjectInfo, error) {
+	target, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         info.Size(),
+		ContentType:  mime.TypeByExtension(path.Ext(key)),
+		ETag:         hex.EncodeToString(hash.Sum(nil)),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+// List walks the directory of the prefix, the ETag isn't filled to avoid hashing every file and
+// the temporary files of the uploads in progress are skipped
+func (d *LocalDriver) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	if prefix != "" && ValidKey(strings.TrimSuffix(prefix, "/")) != nil {
+		return ErrInvalidKey
+	}
+	dir := path.Dir(prefix)
+	if strings.HasSuffix(prefix, "/") {
+		dir = path.Clean(prefix)
+	}
+	start := filepath.Join(d.root, filepath.FromSlash(dir))
+	err := filepath.WalkDir(start, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+		relative, err := filepath.Rel(d.root, name)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relative)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return fn(ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			ContentType:  mime.TypeByExtension(path.Ext(key)),
+			LastModified: info.ModTime(),
+		})
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (d *LocalDriver) URL(key string) string {
+	return d.baseURL + "/" + key
+}
+
+// SignedURL returns the LocalSignedPath url of the key with its expiry and signature
+func (d *LocalDriver) SignedURL(_ context.Context, key string, expires time.Duration) (string, error) {
+	if err := ValidKey(key); err != nil {
+		return "", err
+	}
+	if d.signer == nil {
+		return "", ErrSigningNotConfigured
+	}
+	expiresAt := d.signer.now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", d.signer.Sign(key, expiresAt))
+	return d.baseURL + (&url.URL{Path: LocalSignedPath + key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// VerifySignedURL checks the expires and signature query values of a signed url of the key
+func (d *LocalDriver) VerifySignedURL(key string, expires string, signature string) error {
+	if d.signer == nil {
+		return ErrSigningNotConfigured
+	}
+	if err := ValidKey(key); err != nil {
+		return err
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	return d.signer.Verify(key, expiresAt, signature)
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalDriverLifecycle(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	driver := NewLocalDriver(LocalConfig{Root: root, BaseURL: "http://localhost:8080/"})
+
+	require.NoError(t, driver.Put(ctx, "public/a/b.txt", strings.NewReader("hello"), 5, PutOptions{}))
+	_, err := os.Stat(filepath.Join(root, "public", "a", "b.txt"))
+	require.NoError(t, err)
+
+	body, err := driver.Get(ctx, "public/a/b.txt")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	_ = body.Close()
+	assert.Equal(t, "hello", string(data))
+
+	info, err := driver.Stat(ctx, "public/a/b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), info.Size)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", info.ETag)
+	assert.Equal(t, "http://localhost:8080/public/a/b.txt", driver.URL("public/a/b.txt"))
+
+	require.NoError(t, driver.Delete(ctx, "public/a/b.txt"))
+	require.NoError(t, driver.Delete(ctx, "public/a/b.txt"), "deleting a missing key is not an error")
+	_, err = driver.Stat(ctx, "public/a/b.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = driver.Get(ctx, "public/a/b.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestValidKey(t *testing.T) {
+	for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", "a//b", `a\b`, "a/./b"} {
+		assert.ErrorIs(t, ValidKey(key), ErrInvalidKey, key)
+	}
+	for _, key := range []string{"public/1.png", "uploads/2026/10/19/x.jpg", "a..b"} {
+		assert.NoError(t, ValidKey(key), key)
+	}
+	driver := NewLocalDriver(LocalConfig{Root: t.TempDir()})
+	assert.ErrorIs(t, driver.Put(context.Background(), "../x", strings.NewReader("x"), 1, PutOptions{}), ErrInvalidKey)
+}
+
+func TestManager(t *testing.T) {
+	local := NewLocalDriver(LocalConfig{Root: t.TempDir(), BaseURL: "http://app"})
+	manager := NewManager(local)
+	assert.Equal(t, EngineLocal, manager.Default().Name())
+	assert.Equal(t, "http://app/public/x.png", manager.URL(EngineLocal, "public/x.png"))
+	assert.Equal(t, "", manager.URL(EngineS3, "public/x.png"))
+	_, err := manager.Driver(EngineS3)
+	assert.Error(t, err)
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	signer := NewURLSigner([]byte("secret"))
+	signer.now = func() time.Time { return time.Unix(1000, 0) }
+	driver := NewLocalDriver(LocalConfig{Root: t.TempDir(), BaseURL: "http://app", Signer: signer})
+
+	signed, err := driver.SignedURL(context.Background(), "public/a b.png", time.Minute)
+	require.NoError(t, err)
+	parsed, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/file/signed/public/a b.png", parsed.Path)
+	expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+	assert.Equal(t, "1060", expires)
+
+	assert.NoError(t, driver.VerifySignedURL("public/a b.png", expires, signature))
+	assert.ErrorIs(t, driver.VerifySignedURL("public/other.png", expires, signature), ErrSignatureInvalid)
+	assert.ErrorIs(t, driver.VerifySignedURL("public/a b.png", "1061", signature), ErrSignatureInvalid)
+	assert.ErrorIs(t, driver.VerifySignedURL("../a b.png", expires, signature), ErrInvalidKey)
+	signer.now = func() time.Time { return time.Unix(1061, 0) }
+	assert.ErrorIs(t, driver.VerifySignedURL("public/a b.png", expires, signature), ErrSignatureExpired)
+
+	_, err = NewLocalDriver(LocalConfig{Root: t.TempDir()}).SignedURL(context.Background(), "public/x.png", time.Minute)
+	assert.ErrorIs(t, err, ErrSigningNotConfigured)
+}
+
+func TestLocalDriverList(t *testing.T) {
+	ctx := context.Background()
+	driver := NewLocalDriver(LocalConfig{Root: t.TempDir()})
+	for _, key := range []string{"public/sts/7/a/x.png", "public/sts/7/b/y.png", "public/sts/8/a/z.png", "public/other.png"} {
+		require.NoError(t, driver.Put(ctx, key, strings.NewReader("x"), 1, PutOptions{}))
+	}
+
+	var keys []string
+	require.NoError(t, driver.List(ctx, "public/sts/7/", func(info ObjectInfo) error {
+		keys = append(keys, info.Key)
+		assert.Equal(t, int64(1), info.Size)
+		return nil
+	}))
+	assert.ElementsMatch(t, []string{"public/sts/7/a/x.png", "public/sts/7/b/y.png"}, keys)
+
+	keys = nil
+	require.NoError(t, driver.List(ctx, "public/o", func(info ObjectInfo) error {
+		keys = append(keys, info.Key)
+		return nil
+	}))
+	assert.Equal(t, []string{"public/other.png"}, keys)
+
+	require.NoError(t, driver.List(ctx, "missing/", func(ObjectInfo) error {
+		t.Fatal("nothing is listed")
+		return nil
+	}))
+	assert.ErrorIs(t, driver.List(ctx, "../", func(ObjectInfo) error { return nil }), ErrInvalidKey)
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// OSSConfig addresses an Aliyun OSS bucket, Endpoint is e.g. https://oss-cn-guangzhou.aliyuncs.com
+// and BaseURL the public bucket url, OSS_BASE_URL
+type OSSConfig struct {
+	Endpoint        string
+	Bucket          string
+	AccessKeyID     string
+	AccessKeySecret string
+	BaseURL         string
+}
+
+type OSSDriver struct {
+	bucket  *oss.Bucket
+	baseURL string
+}
+
+func NewOSSDriver(config OSSConfig) (*OSSDriver, error) {
+	client, err := oss.New(config.Endpoint, config.AccessKeyID, config.AccessKeySecret)
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := client.Bucket(config.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &OSSDriver{bucket: bucket, baseURL: strings.TrimSuffix(config.BaseURL, "/")}, nil
+}
+
+func (d *OSSDriver) Name() string {
+	return EngineAliyunOSS
+}
+
+func (d *OSSDriver) Put(ctx context.Context, key string, content io.Reader, size int64, opts PutOptions) error {
+	if err := ValidKey(key); err != nil {
+		return err
+	}
+	options := []oss.Option{oss.WithContext(ctx)}
+	if opts.ContentType != "" {
+		options = append(options, oss.ContentType(opts.ContentType))
+	}
+	if size >= 0 {
+		options = append(options, oss.ContentLength(size))
+	}
+	return d.bucket.PutObject(key, content, options...)
+}
+
+func (d *OSSDriver) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ValidKey(key); err != nil {
+		return nil, err
+	}
+	body, err := d.bucket.GetObject(key, oss.WithContext(ctx))
+	if err != nil {
+		return nil, ossError(err)
+	}
+	return body, nil
+}
+
+func (d *OSSDriver) Delete(ctx context.Context, key string) error {
+	if err := ValidKey(key); err != nil {
+		return err
+	}
+	return d.bucket.DeleteObject(key, oss.WithContext(ctx))
+}
+
+func (d *OSSDriver) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	if err := ValidKey(key); err != nil {
+		return nil, err
+	}
+	header, err := d.bucket.GetObjectDetailedMeta(key, oss.WithContext(ctx))
+	if err != nil {
+		return nil, ossError(err)
+	}
+	size, _ := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	modified, _ := http.ParseTime(header.Get("Last-Modified"))
+	return &ObjectInfo{
+		Key:          key,
+		Size:         size,
+		ContentType:  header.Get("Content-Type"),
+		ETag:         strings.Trim(header.Get("ETag"), `"`),
+		LastModified: modified,
+	}, nil
+}
+
+// List pages through the bucket a thousand keys at a time, the content type isn't listed by OSS
+func (d *OSSDriver) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	token := ""
+	for {
+		options := []oss.Option{oss.Prefix(prefix), oss.MaxKeys(1000), oss.WithContext(ctx)}
+		if token != "" {
+			options = append(options, oss.ContinuationToken(token))
+		}
+		result, err := d.bucket.ListObjectsV2(options...)
+		if err != nil {
+			return err
+		}
+		for _, object := range result.Objects {
+			if err := fn(ObjectInfo{
+				Key:          object.Key,
+				Size:         object.Size,
+				ETag:         strings.Trim(object.ETag, `"`),
+				LastModified: object.LastModified,
+			}); err != nil {
+				return err
+			}
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// URL also accepts the keys with a leading slash saved before the drivers existed
+func (d *OSSDriver) URL(key string) string {
+	return d.baseURL + "/" + strings.TrimPrefix(key, "/")
+}
+
+func (d *OSSDriver) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := ValidKey(key); err != nil {
+		return "", err
+	}
+	return d.bucket.SignURL(key, oss.HTTPGet, int64(expires.Seconds()), oss.WithContext(ctx))
+}
+
+// ossError maps the missing key errors to ErrNotFound, HEAD responses carry no error code
+func ossError(err error) error {
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) && (serviceErr.Code == "NoSuchKey" || serviceErr.StatusCode == http.StatusNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config addresses any S3 compatible service, Endpoint is host[:port] without scheme, e.g.
+// s3.amazonaws.com or localhost:9000 for MinIO. BaseURL defaults to the path style bucket url.
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+	BaseURL         string
+}
+
+type S3Driver struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+func NewS3Driver(config S3Config) (*S3Driver, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("S3_ENDPOINT is required with S3_BUCKET")
+	}
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, ""),
+		Secure: config.UseSSL,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		scheme := "http"
+		if config.UseSSL {
+			scheme = "https"
+		}
+		baseURL = fmt.Sprintf("%s://%s/%s", scheme, config.Endpoint, config.Bucket)
+	}
+	return &S3Driver{client: client, bucket: config.Bucket, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (d *S3Driver) Name() string {
+	return EngineS3
+}
+
+func (d *S3Driver) Put(ctx context.Context, key string, content io.Reader, size int64, opts PutOptions) error {
+	if err := ValidKey(key); err != nil {
+		return err
+	}
+	_, err := d.client.PutObject(ctx, d.bucket, key, content, size, minio.PutObjectOptions{ContentType: opts.ContentType})
+	return err
+}
+
+// Get stats the object first, minio only reports a missing key on the first read
+func (d *S3Driver) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if _, err := d.Stat(ctx, key); err != nil {
+		return nil, err
+	}
+	return d.client.GetObject(ctx, d.bucket, key, minio.GetObjectOptions{})
+}
+
+func (d *S3Driver) Delete(ctx context.Context, key string) error {
+	if err := ValidKey(key); err != nil {
+		return err
+	}
+	return d.client.RemoveObject(ctx, d.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (d *S3Driver) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	if err := ValidKey(key); err != nil {
+		return nil, err
+	}
+	info, err := d.client.StatObject(ctx, d.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+func (d *S3Driver) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	// stops the listing when fn returns early
+	defer cancel()
+	for object := range d.client.ListObjects(ctx, d.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return object.Err
+		}
+		if err := fn(ObjectInfo{
+			Key:          object.Key,
+			Size:         object.Size,
+			ContentType:  object.ContentType,
+			ETag:         object.ETag,
+			LastModified: object.LastModified,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *S3Driver) URL(key string) string {
+	return d.baseURL + "/" + key
+}
+
+func (d *S3Driver) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if err := ValidKey(key); err != nil {
+		return "", err
+	}
+	signed, err := d.client.PresignedGetObject(ctx, d.bucket, key, expires, url.Values{})
+	if err != nil {
+		return "", err
+	}
+	return signed.String(), nil
+}
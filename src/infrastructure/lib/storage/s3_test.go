@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestS3DriverAgainstMinIO runs when S3_TEST_ENDPOINT points to a MinIO server, e.g.
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	S3_TEST_ENDPOINT=localhost:9000 S3_TEST_ACCESS_KEY=minioadmin S3_TEST_SECRET_KEY=minioadmin go test ./src/infrastructure/lib/storage/
+func TestS3DriverAgainstMinIO(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set")
+	}
+	ctx := context.Background()
+	bucket := "storage-test"
+	driver, err := NewS3Driver(S3Config{
+		Endpoint:        endpoint,
+		Bucket:          bucket,
+		AccessKeyID:     os.Getenv("S3_TEST_ACCESS_KEY"),
+		SecretAccessKey: os.Getenv("S3_TEST_SECRET_KEY"),
+	})
+	require.NoError(t, err)
+	if exists, err := driver.client.BucketExists(ctx, bucket); err == nil && !exists {
+		require.NoError(t, driver.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}))
+	}
+
+	key := "public/test/" + time.Now().Format("150405.000") + ".txt"
+	require.NoError(t, driver.Put(ctx, key, strings.NewReader("hello"), 5, PutOptions{ContentType: "text/plain"}))
+
+	info, err := driver.Stat(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), info.Size)
+	assert.Equal(t, "text/plain", info.ContentType)
+
+	body, err := driver.Get(ctx, key)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	_ = body.Close()
+	assert.Equal(t, "hello", string(data))
+
+	signed, err := driver.SignedURL(ctx, key, time.Minute)
+	require.NoError(t, err)
+	resp, err := http.Get(signed)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, driver.Delete(ctx, key))
+	_, err = driver.Stat(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = driver.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
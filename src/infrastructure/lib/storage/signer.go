@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrSignatureInvalid is returned for a signed url that wasn't signed with our secret
+	ErrSignatureInvalid = errors.New("storage: invalid url signature")
+	// ErrSignatureExpired is returned for a signed url past its expiry
+	ErrSignatureExpired = errors.New("storage: signed url expired")
+	// ErrSigningNotConfigured is returned by SignedURL when no signing secret is set
+	ErrSigningNotConfigured = errors.New("storage: url signing secret not configured")
+)
+
+// URLSigner signs a key with its expiry time using HMAC-SHA256
+type URLSigner struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func NewURLSigner(secret []byte) *URLSigner {
+	return &URLSigner{secret: secret, now: time.Now}
+}
+
+// Sign returns the signature granting access to the key until expires, a unix time
+func (s *URLSigner) Sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the key and that expires hasn't passed
+func (s *URLSigner) Verify(key string, expires int64, signature string) error {
+	if !hmac.Equal([]byte(s.Sign(key, expires)), []byte(signature)) {
+		return ErrSignatureInvalid
+	}
+	if s.now().Unix() > expires {
+		return ErrSignatureExpired
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	fileConstants "github.com/gbrayhan/microservices-go/src/domain/sys/files/constants"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+)
+
+// Storage engines, stored in sys_files.storage_engine
+const (
+	EngineLocal     = fileConstants.FileStorageEngineLocal
+	EngineS3        = "s3"
+	EngineAliyunOSS = fileConstants.FileStorageEngineAliyunoss
+)
+
+var (
+	// ErrNotFound is returned by Get and Stat when the key doesn't exist
+	ErrNotFound = errors.New("storage: object not found")
+	// ErrInvalidKey is returned for empty keys, absolute keys and keys leaving the root
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// PutOptions are the optional attributes of a stored object
+type PutOptions struct {
+	ContentType string
+}
+
+// Driver stores objects by slash separated keys. Size may be -1 when unknown, drivers buffer
+// or stream the content as their backend needs.
+type Driver interface {
+	// Name is the storage engine recorded in sys_files
+	Name() string
+	Put(ctx context.Context, key string, content io.Reader, size int64, opts PutOptions) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// URL is the address the object is served from, it only works for public objects
+	URL(key string) string
+	// SignedURL grants read access to the object until expires elapses
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// Lister is implemented by the drivers able to enumerate their objects, used to find the objects
+// no file references
+type Lister interface {
+	// List calls fn with every object whose key starts with prefix, in no particular order. It
+	// stops at the first error fn returns.
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+}
+
+// ValidKey rejects keys that could escape the storage root
+func ValidKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return ErrInvalidKey
+	}
+	if clean := path.Clean(key); clean != key || clean == ".." || strings.HasPrefix(clean, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// Manager holds the configured drivers. New files go to the default driver, existing files are
+// read through the driver of the engine they were stored with.
+type Manager struct {
+	defaultDriver Driver
+	drivers       map[string]Driver
+	keyPrefix     string
+}
+
+// DefaultKeyPrefix puts uploads under public/, served by the /public route for public files
+const DefaultKeyPrefix = "public"
+
+// NewManager uses the first driver as default
+func NewManager(defaultDriver Driver, others ...Driver) *Manager {
+	m := &Manager{
+		defaultDriver: defaultDriver,
+		drivers:       map[string]Driver{defaultDriver.Name(): defaultDriver},
+		keyPrefix:     DefaultKeyPrefix,
+	}
+	for _, driver := range others {
+		m.drivers[driver.Name()] = driver
+	}
+	return m
+}
+
+// Default is the driver new files are stored with
+func (m *Manager) Default() Driver {
+	return m.defaultDriver
+}
+
+// Driver returns the driver of an engine, or an error when that engine isn't configured
+func (m *Manager) Driver(engine string) (Driver, error) {
+	driver, ok := m.drivers[engine]
+	if !ok {
+		return nil, fmt.Errorf("storage engine %q is not configured", engine)
+	}
+	return driver, nil
+}
+
+// Engines returns the names of the configured engines, sorted
+func (m *Manager) Engines() []string {
+	engines := make([]string, 0, len(m.drivers))
+	for engine := range m.drivers {
+		engines = append(engines, engine)
+	}
+	sort.Strings(engines)
+	return engines
+}
+
+// SetKeyPrefix changes the first segment of the keys returned by NewKey
+func (m *Manager) SetKeyPrefix(prefix string) {
+	m.keyPrefix = strings.Trim(prefix, "/")
+}
+
+// KeyPrefix is the first segment of the keys of new files
+func (m *Manager) KeyPrefix() string {
+	return m.keyPrefix
+}
+
+// NewKey returns a unique key for an uploaded file: prefix/yyyy/mm/dd/unixnano.ext, the
+// original name is only kept for its extension
+func (m *Manager) NewKey(originName string) string {
+	now := time.Now()
+	return path.Join(m.keyPrefix, now.Format("2006/01/02"), fmt.Sprintf("%d%s", now.UnixNano(), strings.ToLower(path.Ext(path.Base(originName)))))
+}
+
+// URL returns the address of a file stored with engine, "" when the engine isn't configured
+func (m *Manager) URL(engine, key string) string {
+	driver, ok := m.drivers[engine]
+	if !ok {
+		return ""
+	}
+	return driver.URL(key)
+}
+
+// LoadManagerFromEnv builds the local driver and every remote driver with a bucket configured.
+// STORAGE_DRIVER selects the default: local, s3 or aliyunoss.
+func LoadManagerFromEnv() (*Manager, error) {
+	drivers := map[string]Driver{
+		EngineLocal: NewLocalDriver(LocalConfig{
+			Root:    sharedUtil.GetEnv("STORAGE_LOCAL_ROOT", "."),
+			BaseURL: sharedUtil.GetEnv("APP_URL", ""),
+			Signer:  loadSignerFromEnv(),
+		}),
+	}
+	if bucket := sharedUtil.GetEnv("S3_BUCKET", ""); bucket != "" {
+		driver, err := NewS3Driver(S3Config{
+			Endpoint:        sharedUtil.GetEnv("S3_ENDPOINT", ""),
+			Region:          sharedUtil.GetEnv("S3_REGION", ""),
+			Bucket:          bucket,
+			AccessKeyID:     sharedUtil.GetEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: sharedUtil.GetEnv("S3_SECRET_ACCESS_KEY", ""),
+			UseSSL:          sharedUtil.GetEnvAsBool("S3_USE_SSL", true),
+			BaseURL:         sharedUtil.GetEnv("S3_BASE_URL", ""),
+		})
+		if err != nil {
+			return nil, err
+		}
+		drivers[EngineS3] = driver
+	}
+	if bucket, endpoint := sharedUtil.GetEnv("OSS_BUCKET_NAME", ""), sharedUtil.GetEnv("OSS_ENDPOINT", ""); bucket != "" && endpoint != "" {
+		driver, err := NewOSSDriver(OSSConfig{
+			Endpoint:        endpoint,
+			Bucket:          bucket,
+			AccessKeyID:     sharedUtil.GetEnv("ALIBABA_CLOUD_ACCESS_KEY_ID", ""),
+			AccessKeySecret: sharedUtil.GetEnv("ALIBABA_CLOUD_ACCESS_KEY_SECRET", ""),
+			BaseURL:         sharedUtil.GetEnv("OSS_BASE_URL", ""),
+		})
+		if err != nil {
+			return nil, err
+		}
+		drivers[EngineAliyunOSS] = driver
+	}
+
+	engine := sharedUtil.GetEnv("STORAGE_DRIVER", EngineLocal)
+	defaultDriver, ok := drivers[engine]
+	if !ok {
+		return nil, fmt.Errorf("storage driver %q is unknown or not configured", engine)
+	}
+	others := make([]Driver, 0, len(drivers))
+	for name, driver := range drivers {
+		if name != engine {
+			others = append(others, driver)
+		}
+	}
+	manager := NewManager(defaultDriver, others...)
+	manager.SetKeyPrefix(sharedUtil.GetEnv("STORAGE_KEY_PREFIX", DefaultKeyPrefix))
+	return manager, nil
+}
+
+// loadSignerFromEnv signs with STORAGE_SIGNING_SECRET, or a key derived from JWT_ACCESS_SECRET so
+// that a token secret can't be used to forge urls and the other way round
+func loadSignerFromEnv() *URLSigner {
+	if secret := sharedUtil.GetEnv("STORAGE_SIGNING_SECRET", ""); secret != "" {
+		return NewURLSigner([]byte(secret))
+	}
+	if secret := sharedUtil.GetEnv("JWT_ACCESS_SECRET", ""); secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("storage signed urls"))
+		return NewURLSigner(mac.Sum(nil))
+	}
+	return nil
+}
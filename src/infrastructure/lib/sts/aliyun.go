@@ -0,0 +1,91 @@
+package sts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
+	sts20150401 "github.com/alibabacloud-go/sts-20150401/v2/client"
+	util "github.com/alibabacloud-go/tea-utils/v2/service"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// AliyunConfig holds the access keys of the RAM user allowed to assume RoleArn, Endpoint is the
+// STS endpoint, e.g. sts.cn-guangzhou.aliyuncs.com
+type AliyunConfig struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	Endpoint        string
+	RoleArn         string
+}
+
+type AliyunProvider struct {
+	client  *sts20150401.Client
+	roleArn string
+}
+
+func NewAliyunProvider(config AliyunConfig) (*AliyunProvider, error) {
+	client, err := sts20150401.NewClient(&openapi.Config{
+		AccessKeyId:     tea.String(config.AccessKeyID),
+		AccessKeySecret: tea.String(config.AccessKeySecret),
+		Endpoint:        tea.String(config.Endpoint),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AliyunProvider{client: client, roleArn: config.RoleArn}, nil
+}
+
+// LoadProviderFromEnv builds the Aliyun provider from ALIBABA_CLOUD_ACCESS_KEY_ID,
+// ALIBABA_CLOUD_ACCESS_KEY_SECRET, SECURITY_SERVICE_ADDRESS and RAM_ROLE_ARN, nil is returned when
+// the role isn't configured
+func LoadProviderFromEnv() (Provider, error) {
+	config := AliyunConfig{
+		AccessKeyID:     os.Getenv("ALIBABA_CLOUD_ACCESS_KEY_ID"),
+		AccessKeySecret: os.Getenv("ALIBABA_CLOUD_ACCESS_KEY_SECRET"),
+		Endpoint:        os.Getenv("SECURITY_SERVICE_ADDRESS"),
+		RoleArn:         os.Getenv("RAM_ROLE_ARN"),
+	}
+	if config.RoleArn == "" || config.AccessKeyID == "" {
+		return nil, nil
+	}
+	provider, err := NewAliyunProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// AssumeRole implements Provider.
+// The duration is rounded to seconds, STS accepts 900 seconds up to the maximum session duration
+// of the role.
+func (p *AliyunProvider) AssumeRole(ctx context.Context, request AssumeRoleRequest) (*Credentials, error) {
+	assumeRequest := &sts20150401.AssumeRoleRequest{
+		DurationSeconds: tea.Int64(int64(request.Duration / time.Second)),
+		RoleArn:         tea.String(p.roleArn),
+		RoleSessionName: tea.String(request.SessionName),
+	}
+	if request.Policy != "" {
+		assumeRequest.Policy = tea.String(request.Policy)
+	}
+	response, err := p.client.AssumeRoleWithOptions(assumeRequest, &util.RuntimeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if response.Body == nil || response.Body.Credentials == nil {
+		return nil, errors.New("sts response holds no credentials")
+	}
+	credentials := response.Body.Credentials
+	expiration, err := time.Parse(time.RFC3339, tea.StringValue(credentials.Expiration))
+	if err != nil {
+		return nil, err
+	}
+	return &Credentials{
+		AccessKeyID:     tea.StringValue(credentials.AccessKeyId),
+		AccessKeySecret: tea.StringValue(credentials.AccessKeySecret),
+		SecurityToken:   tea.StringValue(credentials.SecurityToken),
+		Expiration:      expiration,
+	}, nil
+}
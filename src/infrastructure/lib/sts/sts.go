@@ -0,0 +1,70 @@
+package sts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Credentials are temporary access keys returned by a provider
+type Credentials struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      time.Time
+}
+
+// AssumeRoleRequest asks credentials of the configured role narrowed by Policy, an inline policy
+// document; the permissions granted are the intersection of the role and the policy
+type AssumeRoleRequest struct {
+	SessionName string
+	Policy      string
+	Duration    time.Duration
+}
+
+// Provider issues temporary credentials, the Aliyun implementation calls AssumeRole of the STS
+// service and tests stub it
+type Provider interface {
+	AssumeRole(ctx context.Context, request AssumeRoleRequest) (*Credentials, error)
+}
+
+// uploadActions are the OSS actions needed by simple and multipart uploads
+var uploadActions = []string{
+	"oss:PutObject",
+	"oss:InitiateMultipartUpload",
+	"oss:UploadPart",
+	"oss:CompleteMultipartUpload",
+	"oss:AbortMultipartUpload",
+	"oss:ListParts",
+}
+
+type policyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Effect   string   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource []string `json:"Resource"`
+}
+
+// UploadPolicy is an inline policy allowing uploads to the objects of bucket under prefix only.
+// RAM policies can't bound the size or the content type of an upload, so the tokens depend on the
+// upload callback: it checks the object against the scope of its token and deletes it when out of
+// scope, and the reconcile_uploaded_objects task deletes the objects no callback registered.
+func UploadPolicy(bucket, prefix string) (string, error) {
+	document, err := json.Marshal(policyDocument{
+		Version: "1",
+		Statement: []policyStatement{{
+			Effect:   "Allow",
+			Action:   uploadActions,
+			Resource: []string{fmt.Sprintf("acs:oss:*:*:%s/%s*", bucket, prefix)},
+		}},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(document), nil
+}
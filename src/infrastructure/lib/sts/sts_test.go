@@ -0,0 +1,23 @@
+package sts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadPolicy(t *testing.T) {
+	document, err := UploadPolicy("media", "public/sts/7/abc/")
+	require.NoError(t, err)
+
+	var policy policyDocument
+	require.NoError(t, json.Unmarshal([]byte(document), &policy))
+	assert.Equal(t, "1", policy.Version)
+	require.Len(t, policy.Statement, 1)
+	assert.Equal(t, "Allow", policy.Statement[0].Effect)
+	assert.Equal(t, []string{"acs:oss:*:*:media/public/sts/7/abc/*"}, policy.Statement[0].Resource)
+	assert.Contains(t, policy.Statement[0].Action, "oss:PutObject")
+	assert.NotContains(t, policy.Statement[0].Action, "oss:GetObject")
+}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+func TestInit_StdoutExporterPropagatesContext(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{Exporter: ExporterStdout, ServiceName: "test", SampleRatio: 1},
+		&logger.Logger{Log: zap.NewNop()})
+	require.NoError(t, err)
+	defer func() { _ = shutdown(context.Background()) }()
+
+	ctx, span := Tracer().Start(context.Background(), "publish")
+	carrier := propagation.MapCarrier{}
+	Inject(ctx, carrier)
+	span.End()
+	assert.Contains(t, carrier["traceparent"], span.SpanContext().TraceID().String())
+
+	_, child := Tracer().Start(Extract(context.Background(), carrier), "consume")
+	defer child.End()
+	assert.Equal(t, span.SpanContext().TraceID(), child.SpanContext().TraceID())
+}
+
+func TestInit_UnknownExporter(t *testing.T) {
+	_, err := Init(context.Background(), Config{Exporter: "zipkin"}, &logger.Logger{Log: zap.NewNop()})
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+package uploadcallback
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrInvalidSignature is returned for a callback that isn't signed by a trusted sender
+var ErrInvalidSignature = errors.New("uploadcallback: invalid callback signature")
+
+// Verifier checks the signature of a callback announcing an object a client uploaded straight to
+// a bucket, body is the request body already read
+type Verifier interface {
+	Verify(r *http.Request, body []byte) error
+}
@@ -0,0 +1,70 @@
+package uploadcallback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+)
+
+// Headers of the generic callbacks
+const (
+	HeaderTimestamp = "X-Callback-Timestamp"
+	HeaderSignature = "X-Callback-Signature"
+)
+
+// HMACVerifier checks the generic callbacks: X-Callback-Signature is the hex HMAC-SHA256 of the
+// unix time of X-Callback-Timestamp, a newline and the body. Callbacks sent more than MaxSkew
+// away from now are rejected so that a captured callback can't be replayed later.
+type HMACVerifier struct {
+	secret  []byte
+	maxSkew time.Duration
+	now     func() time.Time
+}
+
+func NewHMACVerifier(secret []byte, maxSkew time.Duration) *HMACVerifier {
+	return &HMACVerifier{secret: secret, maxSkew: maxSkew, now: time.Now}
+}
+
+// Sign returns the signature of a callback body sent at timestamp, a unix time
+func (v *HMACVerifier) Sign(timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'\n'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify implements Verifier.
+func (v *HMACVerifier) Verify(r *http.Request, body []byte) error {
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+	}
+	if !hmac.Equal([]byte(v.Sign(timestamp, body)), []byte(r.Header.Get(HeaderSignature))) {
+		return ErrInvalidSignature
+	}
+	if skew := v.now().Sub(time.Unix(timestamp, 0)); skew > v.maxSkew || skew < -v.maxSkew {
+		return fmt.Errorf("%w: timestamp outside the allowed skew", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// LoadHMACVerifierFromEnv verifies the generic callbacks with UPLOAD_CALLBACK_SECRET, nil when it
+// isn't set. UPLOAD_CALLBACK_MAX_SKEW_SECONDS bounds the age of a callback, 300 by default.
+func LoadHMACVerifierFromEnv() Verifier {
+	secret := sharedUtil.GetEnv("UPLOAD_CALLBACK_SECRET", "")
+	if secret == "" {
+		return nil
+	}
+	maxSkew := 300
+	if value := sharedUtil.GetEnvAsInt("UPLOAD_CALLBACK_MAX_SKEW_SECONDS", maxSkew); value > 0 {
+		maxSkew = value
+	}
+	return NewHMACVerifier([]byte(secret), time.Duration(maxSkew)*time.Second)
+}
@@ -0,0 +1,84 @@
+package uploadcallback
+
+import (
+	"context"
+	"crypto"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeyURL = "https://gosspublic.alicdn.com/callback_pub_key_v1.pem"
+
+func TestOSSVerifier(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	fetched := 0
+	verifier := NewOSSVerifier(nil)
+	verifier.fetch = func(_ context.Context, keyURL string) ([]byte, error) {
+		fetched++
+		if keyURL != testKeyURL {
+			return nil, errors.New("unexpected url")
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+	}
+	body := []byte("object=public%2Fsts%2F7%2Fa%2Fx.png&size=5")
+	request := func(keyURL string, signed string) *http.Request {
+		digest := md5.Sum([]byte(signed + "\n" + string(body)))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.MD5, digest[:])
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodPost, "/v1/upload/callback/oss?from=oss", strings.NewReader(string(body)))
+		r.Header.Set("x-oss-pub-key-url", base64.StdEncoding.EncodeToString([]byte(keyURL)))
+		r.Header.Set("Authorization", base64.StdEncoding.EncodeToString(signature))
+		return r
+	}
+
+	assert.NoError(t, verifier.Verify(request(testKeyURL, "/v1/upload/callback/oss?from=oss"), body))
+	assert.NoError(t, verifier.Verify(request(testKeyURL, "/v1/upload/callback/oss?from=oss"), body))
+	assert.Equal(t, 1, fetched, "the key is fetched once")
+
+	assert.ErrorIs(t, verifier.Verify(request(testKeyURL, "/v1/upload/callback/oss"), body), ErrInvalidSignature)
+	assert.ErrorIs(t, verifier.Verify(request(testKeyURL, "/v1/upload/callback/oss?from=oss"), []byte("object=other")), ErrInvalidSignature)
+	assert.ErrorIs(t, verifier.Verify(request("https://attacker.example/key.pem", "/v1/upload/callback/oss?from=oss"), body),
+		ErrInvalidSignature)
+	assert.ErrorIs(t, verifier.Verify(request("https://gosspublic.alicdn.com.attacker.example/key.pem",
+		"/v1/upload/callback/oss?from=oss"), body), ErrInvalidSignature)
+	unsigned := request(testKeyURL, "/v1/upload/callback/oss?from=oss")
+	unsigned.Header.Del("Authorization")
+	assert.ErrorIs(t, verifier.Verify(unsigned, body), ErrInvalidSignature)
+}
+
+func TestHMACVerifier(t *testing.T) {
+	verifier := NewHMACVerifier([]byte("secret"), 5*time.Minute)
+	verifier.now = func() time.Time { return time.Unix(10000, 0) }
+	body := []byte(`{"key":"public/sts/7/a/x.png"}`)
+	request := func(timestamp int64, signature string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/v1/upload/callback", strings.NewReader(string(body)))
+		r.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		r.Header.Set(HeaderSignature, signature)
+		return r
+	}
+
+	assert.NoError(t, verifier.Verify(request(10000, verifier.Sign(10000, body)), body))
+	assert.NoError(t, verifier.Verify(request(9800, verifier.Sign(9800, body)), body))
+	assert.ErrorIs(t, verifier.Verify(request(9000, verifier.Sign(9000, body)), body), ErrInvalidSignature, "too old")
+	assert.ErrorIs(t, verifier.Verify(request(10000, verifier.Sign(9999, body)), body), ErrInvalidSignature)
+	assert.ErrorIs(t, verifier.Verify(request(10000, verifier.Sign(10000, body)), []byte("{}")), ErrInvalidSignature)
+	other := NewHMACVerifier([]byte("other"), 5*time.Minute)
+	assert.ErrorIs(t, verifier.Verify(request(10000, other.Sign(10000, body)), body), ErrInvalidSignature)
+}
@@ -0,0 +1,156 @@
+package uploadpolicy
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/scanner"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+)
+
+// Upload categories, every allowed extension belongs to one
+const (
+	CategoryImage    = "image"
+	CategoryDocument = "document"
+	CategoryArchive  = "archive"
+)
+
+// Rejection reasons
+const (
+	ReasonExtension = "extension_not_allowed"
+	ReasonSize      = "file_too_large"
+	ReasonContent   = "content_mismatch"
+	ReasonMalware   = "malware_detected"
+	ReasonScan      = "scan_failed"
+)
+
+// Rejection is the error for a file refused by a policy, Reason is one of the Reason constants
+type Rejection struct {
+	Reason  string
+	Message string
+}
+
+func (r *Rejection) Error() string {
+	return r.Message
+}
+
+func reject(reason string, format string, args ...any) *Rejection {
+	return &Rejection{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+// Policy is what a category accepts: the content of a file named with one of Extensions has to
+// be sniffed as one of the MIME types listed for it
+type Policy struct {
+	Category string
+	// MaxSize in bytes, 0 is unlimited
+	MaxSize    int64
+	Extensions map[string][]string
+}
+
+// knownExtensions are the extensions a category may allow with the MIME types their content can
+// have. SVG and HTML are left out, they run scripts when served from our origin.
+var knownExtensions = map[string]map[string][]string{
+	CategoryImage: {
+		".jpg":  {"image/jpeg"},
+		".jpeg": {"image/jpeg"},
+		".png":  {"image/png"},
+		".gif":  {"image/gif"},
+		".webp": {"image/webp"},
+		".bmp":  {"image/bmp"},
+	},
+	CategoryDocument: {
+		".pdf":  {"application/pdf"},
+		".doc":  {"application/msword"},
+		".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		".xls":  {"application/vnd.ms-excel"},
+		".xlsx": {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		".ppt":  {"application/vnd.ms-powerpoint"},
+		".pptx": {"application/vnd.openxmlformats-officedocument.presentationml.presentation"},
+		".txt":  {"text/plain"},
+		".csv":  {"text/csv", "text/plain"},
+		".json": {"application/json", "text/plain"},
+	},
+	CategoryArchive: {
+		".zip": {"application/zip"},
+		".gz":  {"application/gzip"},
+		".tgz": {"application/gzip"},
+		".tar": {"application/x-tar"},
+		".7z":  {"application/x-7z-compressed"},
+		".rar": {"application/x-rar-compressed"},
+	},
+}
+
+var defaultMaxSizeMB = map[string]int{
+	CategoryImage:    20,
+	CategoryDocument: 100,
+	CategoryArchive:  4096,
+}
+
+// DefaultPolicies allows every known extension with the default size limits
+func DefaultPolicies() []Policy {
+	policies := make([]Policy, 0, len(knownExtensions))
+	for _, category := range []string{CategoryImage, CategoryDocument, CategoryArchive} {
+		policies = append(policies, Policy{
+			Category:   category,
+			MaxSize:    int64(defaultMaxSizeMB[category]) << 20,
+			Extensions: knownExtensions[category],
+		})
+	}
+	return policies
+}
+
+// LoadPoliciesFromEnv narrows the default policies with UPLOAD_<CATEGORY>_EXTENSIONS, a comma
+// separated subset of the known extensions ("none" disables the category), and sets their
+// limits from UPLOAD_<CATEGORY>_MAX_SIZE_MB
+func LoadPoliciesFromEnv() ([]Policy, error) {
+	policies := DefaultPolicies()
+	for i := range policies {
+		policy := &policies[i]
+		prefix := "UPLOAD_" + strings.ToUpper(policy.Category)
+		if value := sharedUtil.GetEnv(prefix+"_MAX_SIZE_MB", ""); value != "" {
+			maxSize, err := strconv.Atoi(value)
+			if err != nil || maxSize < 0 {
+				return nil, fmt.Errorf("%s_MAX_SIZE_MB must be a non-negative integer", prefix)
+			}
+			policy.MaxSize = int64(maxSize) << 20
+		}
+		value := strings.TrimSpace(sharedUtil.GetEnv(prefix+"_EXTENSIONS", ""))
+		if value == "" {
+			continue
+		}
+		allowed := map[string][]string{}
+		if value != "none" {
+			for _, extension := range strings.Split(value, ",") {
+				extension = "." + strings.TrimPrefix(strings.ToLower(strings.TrimSpace(extension)), ".")
+				types, ok := knownExtensions[policy.Category][extension]
+				if !ok {
+					return nil, fmt.Errorf("%s_EXTENSIONS: %s is not a known %s extension", prefix, extension, policy.Category)
+				}
+				allowed[extension] = types
+			}
+		}
+		policy.Extensions = allowed
+	}
+	return policies, nil
+}
+
+// LoadValidatorFromEnv builds the validator of the env policies and UPLOAD_SCANNER, a failed
+// scan rejects the file unless UPLOAD_SCAN_FAIL_OPEN is true
+func LoadValidatorFromEnv() (*Validator, error) {
+	policies, err := LoadPoliciesFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	fileScanner, err := scanner.LoadScannerFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewValidator(policies, fileScanner, sharedUtil.GetEnvAsBool("UPLOAD_SCAN_FAIL_OPEN", false)), nil
+}
+
+// extension is the lower-case extension of a file name, the last one only: a.tar.gz is .gz
+func extension(originName string) string {
+	return strings.ToLower(path.Ext(path.Base(strings.ReplaceAll(originName, `\`, "/"))))
+}
@@ -0,0 +1,214 @@
+package uploadpolicy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/scanner"
+)
+
+// sniffLength is how much of the content is read to detect its type
+const sniffLength = 3072
+
+// errScanAborted stops the scan of a content that won't be stored
+var errScanAborted = errors.New("upload aborted")
+
+// Validator applies the category policies and the malware scanner to uploads
+type Validator struct {
+	byExtension map[string]*Policy
+	scanner     scanner.Scanner
+	failOpen    bool
+}
+
+func NewValidator(policies []Policy, fileScanner scanner.Scanner, failOpen bool) *Validator {
+	byExtension := map[string]*Policy{}
+	for i := range policies {
+		for extension := range policies[i].Extensions {
+			byExtension[extension] = &policies[i]
+		}
+	}
+	return &Validator{byExtension: byExtension, scanner: fileScanner, failOpen: failOpen}
+}
+
+// Check validates the extension of the name and the announced size, -1 when unknown, before
+// any content is received
+func (v *Validator) Check(originName string, size int64) (*Policy, error) {
+	ext := extension(originName)
+	policy, ok := v.byExtension[ext]
+	if !ok {
+		if ext == "" {
+			return nil, reject(ReasonExtension, "files without extension are not allowed")
+		}
+		return nil, reject(ReasonExtension, "%s files are not allowed", ext)
+	}
+	if policy.MaxSize > 0 && size > policy.MaxSize {
+		return nil, reject(ReasonSize, "%s files are limited to %d bytes", policy.Category, policy.MaxSize)
+	}
+	return policy, nil
+}
+
+// Inspection is the content of an accepted upload being checked while it streams to the storage.
+// Verdict is asked once the content is stored, Close releases the scan of a content that wasn't.
+type Inspection struct {
+	Policy *Policy
+	// ContentType is the sniffed MIME type, to be stored instead of the one the client sent
+	ContentType string
+	Content     io.Reader
+
+	limited  *limitedReader
+	pipe     *io.PipeWriter
+	scanDone chan struct{}
+	result   *scanner.Result
+	scanErr  error
+	failOpen bool
+	once     sync.Once
+}
+
+// Inspect checks the name and size, sniffs the beginning of the content against the extension
+// and starts the malware scan of the content as it is read
+func (v *Validator) Inspect(ctx context.Context, originName string, size int64, content io.Reader) (*Inspection, error) {
+	policy, err := v.Check(originName, size)
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, reject(ReasonContent, "the file is empty")
+	}
+	ext := extension(originName)
+	detected := mimetype.Detect(head[:n])
+	if !matchesAny(detected, policy.Extensions[ext]) {
+		return nil, reject(ReasonContent, "the content of the file is %s, not a %s file", detected.String(), ext)
+	}
+
+	inspection := &Inspection{
+		Policy:      policy,
+		ContentType: detected.String(),
+		limited:     &limitedReader{reader: io.MultiReader(bytes.NewReader(head[:n]), content), remaining: policy.MaxSize, policy: policy},
+		scanDone:    make(chan struct{}),
+		failOpen:    v.failOpen,
+	}
+	inspection.Content = inspection.limited
+	if v.scanner != nil {
+		reader, writer := io.Pipe()
+		inspection.pipe = writer
+		inspection.Content = &teeReader{reader: inspection.limited, pipe: writer}
+		go func() {
+			defer close(inspection.scanDone)
+			inspection.result, inspection.scanErr = v.scanner.Scan(ctx, reader)
+			// a scanner stopping early must not block the upload
+			_, _ = io.Copy(io.Discard, reader)
+		}()
+	} else {
+		close(inspection.scanDone)
+	}
+	return inspection, nil
+}
+
+// matchesAny compares the detected type and its aliases to the allowed ones, ignoring parameters
+func matchesAny(detected *mimetype.MIME, allowed []string) bool {
+	for _, mimeType := range allowed {
+		if detected.Is(mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Verdict waits for the scan of the stored content. A failed scan is returned with ScanError
+// and only rejects the file when the validator doesn't fail open.
+func (i *Inspection) Verdict() error {
+	// drivers given the size may stop reading before EOF, the scanner waits for it
+	if _, err := io.Copy(io.Discard, i.Content); err != nil && i.limited.err == nil {
+		i.Close()
+	}
+	<-i.scanDone
+	if err := i.limited.err; err != nil {
+		return err
+	}
+	if i.scanErr != nil {
+		if i.failOpen {
+			return nil
+		}
+		return reject(ReasonScan, "the file could not be scanned")
+	}
+	if i.result != nil && i.result.Infected {
+		return reject(ReasonMalware, "the file contains malware: %s", i.result.Signature)
+	}
+	return nil
+}
+
+// Close ends the scan, a content that wasn't read to its end is reported as aborted to the scanner
+func (i *Inspection) Close() {
+	i.once.Do(func() {
+		if i.pipe != nil {
+			_ = i.pipe.CloseWithError(errScanAborted)
+		}
+	})
+}
+
+// ScanError is the error of a failed scan, set once Verdict returned
+func (i *Inspection) ScanError() error {
+	return i.scanErr
+}
+
+// Rejected is the size rejection of a content that ran over its category limit
+func (i *Inspection) Rejected() error {
+	if i.limited.err != nil {
+		return i.limited.err
+	}
+	return nil
+}
+
+// limitedReader fails the read that goes over the policy size
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	policy    *Policy
+	err       *Rejection
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.reader.Read(p)
+	if r.policy.MaxSize > 0 {
+		r.remaining -= int64(n)
+		if r.remaining < 0 {
+			r.err = reject(ReasonSize, "%s files are limited to %d bytes", r.policy.Category, r.policy.MaxSize)
+			return 0, r.err
+		}
+	}
+	return n, err
+}
+
+// teeReader copies the content to the scanner pipe and closes it at the end of the content
+type teeReader struct {
+	reader io.Reader
+	pipe   *io.PipeWriter
+}
+
+func (t *teeReader) Read(p []byte) (int, error) {
+	n, err := t.reader.Read(p)
+	if n > 0 {
+		if _, writeErr := t.pipe.Write(p[:n]); writeErr != nil && !errors.Is(writeErr, io.ErrClosedPipe) {
+			return n, writeErr
+		}
+	}
+	switch {
+	case errors.Is(err, io.EOF):
+		_ = t.pipe.Close()
+	case err != nil:
+		_ = t.pipe.CloseWithError(err)
+	}
+	return n, err
+}
@@ -0,0 +1,140 @@
+package uploadpolicy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+// keywordScanner reports the content containing its keyword, or fails with err
+type keywordScanner struct {
+	keyword string
+	err     error
+}
+
+func (s keywordScanner) Name() string {
+	return "keyword"
+}
+
+func (s keywordScanner) Scan(_ context.Context, content io.Reader) (*scanner.Result, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &scanner.Result{Infected: strings.Contains(string(data), s.keyword), Signature: "Test-Signature"}, nil
+}
+
+func assertRejected(t *testing.T, err error, reason string) {
+	t.Helper()
+	var rejection *Rejection
+	require.ErrorAs(t, err, &rejection)
+	assert.Equal(t, reason, rejection.Reason)
+}
+
+// store reads the inspected content like a driver and returns the verdict
+func store(t *testing.T, validator *Validator, name string, content string) (string, error) {
+	inspection, err := validator.Inspect(context.Background(), name, int64(len(content)), strings.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	defer inspection.Close()
+	stored, err := io.ReadAll(inspection.Content)
+	if err != nil {
+		return "", err
+	}
+	return string(stored), inspection.Verdict()
+}
+
+func TestValidatorCheck(t *testing.T) {
+	validator := NewValidator(DefaultPolicies(), nil, false)
+
+	policy, err := validator.Check("photo.JPG", 1024)
+	require.NoError(t, err)
+	assert.Equal(t, CategoryImage, policy.Category)
+
+	_, err = validator.Check("page.html", 10)
+	assertRejected(t, err, ReasonExtension)
+	_, err = validator.Check("README", 10)
+	assertRejected(t, err, ReasonExtension)
+	_, err = validator.Check("photo.png", 21<<20)
+	assertRejected(t, err, ReasonSize)
+}
+
+func TestValidatorInspect(t *testing.T) {
+	validator := NewValidator(DefaultPolicies(), keywordScanner{keyword: "EICAR"}, false)
+
+	stored, err := validator.Inspect(context.Background(), "image.png", -1, strings.NewReader(pngHeader))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", stored.ContentType)
+	content, _ := io.ReadAll(stored.Content)
+	assert.Equal(t, pngHeader, string(content), "the sniffed bytes are part of the content")
+	require.NoError(t, stored.Verdict())
+
+	_, err = store(t, validator, "report.pdf", pngHeader)
+	assertRejected(t, err, ReasonContent)
+	_, err = store(t, validator, "notes.txt", "<html><script>alert(1)</script></html>")
+	assertRejected(t, err, ReasonContent)
+	_, err = store(t, validator, "empty.txt", "")
+	assertRejected(t, err, ReasonContent)
+
+	csv := "id,name\n1,a\n"
+	stored2, err := store(t, validator, "export.csv", csv)
+	require.NoError(t, err)
+	assert.Equal(t, csv, stored2)
+
+	_, err = store(t, validator, "notes.txt", strings.Repeat("x", 5000)+" EICAR")
+	assertRejected(t, err, ReasonMalware)
+}
+
+func TestValidatorSizeAndScanFailure(t *testing.T) {
+	policies := DefaultPolicies()
+	policies[1].MaxSize = 4096
+	failing := keywordScanner{err: errors.New("clamd unavailable")}
+
+	validator := NewValidator(policies, failing, false)
+	inspection, err := validator.Inspect(context.Background(), "notes.txt", -1, strings.NewReader(strings.Repeat("x", 5000)))
+	require.NoError(t, err, "the size is unknown up front")
+	_, err = io.ReadAll(inspection.Content)
+	assertRejected(t, err, ReasonSize)
+	assertRejected(t, inspection.Rejected(), ReasonSize)
+	inspection.Close()
+
+	_, err = store(t, validator, "notes.txt", "short")
+	assertRejected(t, err, ReasonScan)
+
+	_, err = store(t, NewValidator(policies, failing, true), "notes.txt", "short")
+	assert.NoError(t, err, "fail open accepts the file when the scan fails")
+}
+
+func TestLoadPoliciesFromEnv(t *testing.T) {
+	t.Setenv("UPLOAD_IMAGE_EXTENSIONS", "png, .JPG")
+	t.Setenv("UPLOAD_ARCHIVE_EXTENSIONS", "none")
+	t.Setenv("UPLOAD_DOCUMENT_MAX_SIZE_MB", "5")
+	policies, err := LoadPoliciesFromEnv()
+	require.NoError(t, err)
+	validator := NewValidator(policies, nil, false)
+
+	_, err = validator.Check("a.png", 1)
+	assert.NoError(t, err)
+	_, err = validator.Check("a.gif", 1)
+	assertRejected(t, err, ReasonExtension)
+	_, err = validator.Check("a.zip", 1)
+	assertRejected(t, err, ReasonExtension)
+	_, err = validator.Check("a.pdf", 6<<20)
+	assertRejected(t, err, ReasonSize)
+
+	t.Setenv("UPLOAD_IMAGE_EXTENSIONS", "svg")
+	_, err = LoadPoliciesFromEnv()
+	assert.Error(t, err)
+}
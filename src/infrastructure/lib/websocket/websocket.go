@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
@@ -133,12 +134,15 @@ func (wsm *WebSocketManager) Run() {
 		select {
 		case conn := <-wsm.register:
 			wsm.clients[conn] = true
+			metrics.WebSocketConnectionsTotal.Inc()
+			metrics.WebSocketConnections.Set(float64(len(wsm.clients)))
 			log.Println("Client connected. Total clients:", len(wsm.clients))
 
 		case conn := <-wsm.unregister:
 			if _, ok := wsm.clients[conn]; ok {
 				delete(wsm.clients, conn)
 				conn.Close()
+				metrics.WebSocketConnections.Set(float64(len(wsm.clients)))
 				log.Println("Client disconnected. Total clients:", len(wsm.clients))
 			}
 
@@ -148,6 +152,7 @@ func (wsm *WebSocketManager) Run() {
 				if err != nil {
 					delete(wsm.clients, conn)
 					conn.Close()
+					metrics.WebSocketConnections.Set(float64(len(wsm.clients)))
 				}
 			}
 		}
@@ -0,0 +1,31 @@
+package writer
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	queuedDesc = prometheus.NewDesc("microservices_operation_writer_queued",
+		"Operation records waiting in the writer buffer.", nil, nil)
+	writtenDesc = prometheus.NewDesc("microservices_operation_writer_written_total",
+		"Operation records written to the database.", nil, nil)
+	droppedDesc = prometheus.NewDesc("microservices_operation_writer_dropped_total",
+		"Operation records dropped because the buffer was full.", nil, nil)
+	failedDesc = prometheus.NewDesc("microservices_operation_writer_failed_total",
+		"Operation records lost to failed batch inserts.", nil, nil)
+)
+
+// Describe makes the writer a prometheus collector exposing its Stats
+func (w *OperationWriter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queuedDesc
+	ch <- writtenDesc
+	ch <- droppedDesc
+	ch <- failedDesc
+}
+
+// Collect reads the current Stats on every scrape
+func (w *OperationWriter) Collect(ch chan<- prometheus.Metric) {
+	stats := w.Stats()
+	ch <- prometheus.MustNewConstMetric(queuedDesc, prometheus.GaugeValue, float64(stats.Queued))
+	ch <- prometheus.MustNewConstMetric(writtenDesc, prometheus.CounterValue, float64(stats.Written))
+	ch <- prometheus.MustNewConstMetric(droppedDesc, prometheus.CounterValue, float64(stats.Dropped))
+	ch <- prometheus.MustNewConstMetric(failedDesc, prometheus.CounterValue, float64(stats.Failed))
+}
@@ -0,0 +1,202 @@
+package writer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domainOperation "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	operationRecordsRepository "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+	"go.uber.org/zap"
+)
+
+// OverflowPolicy decides what Write does when the buffer is full
+type OverflowPolicy string
+
+const (
+	// PolicyDrop discards the record and counts it as dropped
+	PolicyDrop OverflowPolicy = "drop"
+	// PolicyBlock waits up to BlockTimeout for room in the buffer, then drops
+	PolicyBlock OverflowPolicy = "block"
+)
+
+type Config struct {
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	Policy        OverflowPolicy
+	BlockTimeout  time.Duration
+}
+
+// Stats are the writer counters since start
+type Stats struct {
+	Queued  int    `json:"queued"`
+	Written uint64 `json:"written"`
+	Dropped uint64 `json:"dropped"`
+	Failed  uint64 `json:"failed"`
+}
+
+// LoadConfigFromEnv reads the OPERATION_LOG_* variables, falling back to defaults
+func LoadConfigFromEnv() Config {
+	config := Config{
+		BufferSize:    sharedUtil.GetEnvAsInt("OPERATION_LOG_BUFFER_SIZE", 10000),
+		BatchSize:     sharedUtil.GetEnvAsInt("OPERATION_LOG_BATCH_SIZE", 200),
+		FlushInterval: time.Duration(sharedUtil.GetEnvAsInt("OPERATION_LOG_FLUSH_INTERVAL_MS", 1000)) * time.Millisecond,
+		Policy:        OverflowPolicy(sharedUtil.GetEnv("OPERATION_LOG_OVERFLOW_POLICY", string(PolicyDrop))),
+		BlockTimeout:  time.Duration(sharedUtil.GetEnvAsInt("OPERATION_LOG_BLOCK_TIMEOUT_MS", 50)) * time.Millisecond,
+	}
+	if config.Policy != PolicyBlock {
+		config.Policy = PolicyDrop
+	}
+	return config
+}
+
+// OperationWriter buffers operation records in a bounded channel and inserts them in batches
+// from a single goroutine, flushing when a batch is full or the interval elapses.
+type OperationWriter struct {
+	repository operationRecordsRepository.OperationRepositoryInterface
+	config     Config
+	logger     *logger.Logger
+
+	records chan *domainOperation.SysOperationRecord
+	done    chan struct{}
+	// mu guards records against a send after Close
+	mu     sync.RWMutex
+	closed bool
+
+	written atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+func NewOperationWriter(repository operationRecordsRepository.OperationRepositoryInterface, config Config, loggerInstance *logger.Logger) *OperationWriter {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 10000
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 200
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	w := &OperationWriter{
+		repository: repository,
+		config:     config,
+		logger:     loggerInstance,
+		records:    make(chan *domainOperation.SysOperationRecord, config.BufferSize),
+		done:       make(chan struct{}),
+	}
+	go w.run()
+	loggerInstance.Info("Operation writer started",
+		zap.Int("bufferSize", config.BufferSize),
+		zap.Int("batchSize", config.BatchSize),
+		zap.Duration("flushInterval", config.FlushInterval),
+		zap.String("policy", string(config.Policy)))
+	return w
+}
+
+// Write queues the record without waiting for the database, it returns false when the record was dropped
+func (w *OperationWriter) Write(record *domainOperation.SysOperationRecord) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		w.drop()
+		return false
+	}
+	select {
+	case w.records <- record:
+		return true
+	default:
+	}
+	if w.config.Policy == PolicyBlock {
+		timer := time.NewTimer(w.config.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case w.records <- record:
+			return true
+		case <-timer.C:
+		}
+	}
+	w.drop()
+	return false
+}
+
+func (w *OperationWriter) drop() {
+	// log the first drop and then every thousandth to keep the logs readable under pressure
+	if dropped := w.dropped.Add(1); dropped == 1 || dropped%1000 == 0 {
+		w.logger.Warn("Operation records dropped", zap.Uint64("dropped", dropped))
+	}
+}
+
+// Stats returns the current counters
+func (w *OperationWriter) Stats() Stats {
+	return Stats{
+		Queued:  len(w.records),
+		Written: w.written.Load(),
+		Dropped: w.dropped.Load(),
+		Failed:  w.failed.Load(),
+	}
+}
+
+// Close stops accepting records and waits until the buffer is drained or the context ends
+func (w *OperationWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.records)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		stats := w.Stats()
+		w.logger.Info("Operation writer drained",
+			zap.Uint64("written", stats.Written),
+			zap.Uint64("dropped", stats.Dropped),
+			zap.Uint64("failed", stats.Failed))
+		return nil
+	case <-ctx.Done():
+		w.logger.Warn("Operation writer drain interrupted", zap.Int("queued", len(w.records)))
+		return ctx.Err()
+	}
+}
+
+func (w *OperationWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*domainOperation.SysOperationRecord, 0, w.config.BatchSize)
+	for {
+		select {
+		case record, ok := <-w.records:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= w.config.BatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			w.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (w *OperationWriter) flush(batch []*domainOperation.SysOperationRecord) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := w.repository.CreateBatch(context.Background(), batch); err != nil {
+		w.failed.Add(uint64(len(batch)))
+		w.logger.Error("Error flushing operation records", zap.Error(err), zap.Int("count", len(batch)))
+		return
+	}
+	w.written.Add(uint64(len(batch)))
+}
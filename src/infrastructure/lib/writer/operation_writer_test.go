@@ -0,0 +1,97 @@
+package writer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	domainOperation "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	operationRecordsRepository "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeOperationRepository struct {
+	operationRecordsRepository.OperationRepositoryInterface
+	mu      sync.Mutex
+	batches [][]*domainOperation.SysOperationRecord
+	block   chan struct{}
+}
+
+func (f *fakeOperationRepository) CreateBatch(_ context.Context, records []*domainOperation.SysOperationRecord) error {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, append([]*domainOperation.SysOperationRecord(nil), records...))
+	return nil
+}
+
+func (f *fakeOperationRepository) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	total := 0
+	for _, batch := range f.batches {
+		total += len(batch)
+	}
+	return total
+}
+
+func newTestLogger() *logger.Logger {
+	return &logger.Logger{Log: zap.NewNop()}
+}
+
+func TestOperationWriter_FlushesOnBatchSize(t *testing.T) {
+	repo := &fakeOperationRepository{}
+	w := NewOperationWriter(repo, Config{BufferSize: 10, BatchSize: 2, FlushInterval: time.Hour}, newTestLogger())
+
+	assert.True(t, w.Write(&domainOperation.SysOperationRecord{Path: "/a"}))
+	assert.True(t, w.Write(&domainOperation.SysOperationRecord{Path: "/b"}))
+
+	assert.Eventually(t, func() bool { return repo.count() == 2 }, time.Second, 5*time.Millisecond)
+	assert.NoError(t, w.Close(context.Background()))
+}
+
+func TestOperationWriter_FlushesOnInterval(t *testing.T) {
+	repo := &fakeOperationRepository{}
+	w := NewOperationWriter(repo, Config{BufferSize: 10, BatchSize: 100, FlushInterval: 10 * time.Millisecond}, newTestLogger())
+
+	w.Write(&domainOperation.SysOperationRecord{Path: "/a"})
+
+	assert.Eventually(t, func() bool { return repo.count() == 1 }, time.Second, 5*time.Millisecond)
+	assert.NoError(t, w.Close(context.Background()))
+}
+
+func TestOperationWriter_DrainsOnClose(t *testing.T) {
+	repo := &fakeOperationRepository{}
+	w := NewOperationWriter(repo, Config{BufferSize: 10, BatchSize: 100, FlushInterval: time.Hour}, newTestLogger())
+
+	for i := 0; i < 5; i++ {
+		w.Write(&domainOperation.SysOperationRecord{})
+	}
+	assert.NoError(t, w.Close(context.Background()))
+	assert.Equal(t, 5, repo.count())
+	assert.Equal(t, uint64(5), w.Stats().Written)
+
+	assert.False(t, w.Write(&domainOperation.SysOperationRecord{}))
+	assert.Equal(t, uint64(1), w.Stats().Dropped)
+}
+
+func TestOperationWriter_DropsWhenFull(t *testing.T) {
+	repo := &fakeOperationRepository{block: make(chan struct{})}
+	w := NewOperationWriter(repo, Config{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour, Policy: PolicyBlock, BlockTimeout: time.Millisecond}, newTestLogger())
+
+	// the first record is taken by the flush goroutine which then blocks on the repository
+	w.Write(&domainOperation.SysOperationRecord{})
+	assert.Eventually(t, func() bool { return w.Stats().Queued == 0 }, time.Second, time.Millisecond)
+	assert.True(t, w.Write(&domainOperation.SysOperationRecord{}))
+	assert.False(t, w.Write(&domainOperation.SysOperationRecord{}))
+	assert.Equal(t, uint64(1), w.Stats().Dropped)
+
+	close(repo.block)
+	assert.NoError(t, w.Close(context.Background()))
+	assert.Equal(t, 2, repo.count())
+}
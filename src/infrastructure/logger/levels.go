@@ -0,0 +1,164 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelRegistry holds the default level and the per component overrides. Loggers created with
+// Named follow the override of their component, or the default level when there is none, so a
+// change applies to every logger already handed out.
+type LevelRegistry struct {
+	defaultLevel zap.AtomicLevel
+	mu           sync.RWMutex
+	overrides    map[string]zap.AtomicLevel
+	components   map[string]struct{}
+}
+
+// LevelSnapshot is the state reported by the admin endpoint
+type LevelSnapshot struct {
+	Default    string            `json:"default"`
+	Overrides  map[string]string `json:"overrides"`
+	Components []string          `json:"components"`
+}
+
+func NewLevelRegistry(level zapcore.Level) *LevelRegistry {
+	return &LevelRegistry{
+		defaultLevel: zap.NewAtomicLevelAt(level),
+		overrides:    map[string]zap.AtomicLevel{},
+		components:   map[string]struct{}{},
+	}
+}
+
+// ParseLevel accepts the zap level names: debug, info, warn, error, dpanic, panic and fatal
+func ParseLevel(text string) (zapcore.Level, error) {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(text)); err != nil {
+		return level, fmt.Errorf("invalid log level %q", text)
+	}
+	return level, nil
+}
+
+// SetDefault changes the level of the components without an override
+func (r *LevelRegistry) SetDefault(level zapcore.Level) {
+	r.defaultLevel.SetLevel(level)
+}
+
+// SetLevel overrides the level of one component, the component doesn't need to exist yet
+func (r *LevelRegistry) SetLevel(component string, level zapcore.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if current, ok := r.overrides[component]; ok {
+		current.SetLevel(level)
+		return
+	}
+	r.overrides[component] = zap.NewAtomicLevelAt(level)
+}
+
+// ResetLevel drops the override of a component, it follows the default level again
+func (r *LevelRegistry) ResetLevel(component string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.overrides, component)
+}
+
+// ReplaceOverrides swaps every override at once, used when the configuration is reloaded
+func (r *LevelRegistry) ReplaceOverrides(levels map[string]zapcore.Level) {
+	overrides := make(map[string]zap.AtomicLevel, len(levels))
+	for component, level := range levels {
+		overrides[component] = zap.NewAtomicLevelAt(level)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides = overrides
+}
+
+func (r *LevelRegistry) Snapshot() LevelSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snapshot := LevelSnapshot{
+		Default:    r.defaultLevel.Level().String(),
+		Overrides:  make(map[string]string, len(r.overrides)),
+		Components: make([]string, 0, len(r.components)),
+	}
+	for component, level := range r.overrides {
+		snapshot.Overrides[component] = level.Level().String()
+	}
+	for component := range r.components {
+		snapshot.Components = append(snapshot.Components, component)
+	}
+	sort.Strings(snapshot.Components)
+	return snapshot
+}
+
+func (r *LevelRegistry) register(component string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.components[component] = struct{}{}
+}
+
+func (r *LevelRegistry) enabled(component string, level zapcore.Level) bool {
+	r.mu.RLock()
+	override, ok := r.overrides[component]
+	r.mu.RUnlock()
+	if ok {
+		return override.Enabled(level)
+	}
+	return r.defaultLevel.Enabled(level)
+}
+
+// componentLevel is the LevelEnabler of one component
+type componentLevel struct {
+	registry  *LevelRegistry
+	component string
+}
+
+func (c componentLevel) Enabled(level zapcore.Level) bool {
+	return c.registry.enabled(c.component, level)
+}
+
+// leveledCore filters an unfiltered core by the level of its component
+type leveledCore struct {
+	zapcore.Core
+	level componentLevel
+}
+
+func (c *leveledCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+func (c *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *leveledCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Named returns a logger for a component whose level can be changed on its own through the
+// level registry. Loggers built without a registry, as in tests, are only named.
+func (l *Logger) Named(component string) *Logger {
+	if l.levels == nil {
+		return &Logger{Log: l.Log.Named(component)}
+	}
+	l.levels.register(component)
+	log := l.Log.Named(component).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if leveled, ok := core.(*leveledCore); ok {
+			core = leveled.Core
+		}
+		return &leveledCore{Core: core, level: componentLevel{registry: l.levels, component: component}}
+	}))
+	return &Logger{Log: log, levels: l.levels, output: l.output}
+}
+
+// Levels returns the level registry, nil for loggers built without one
+func (l *Logger) Levels() *LevelRegistry {
+	return l.levels
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newFileLogger(t *testing.T) (*Logger, string) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := newLogger(zap.InfoLevel, OutputConfig{File: FileConfig{Enabled: true, Path: path}})
+	require.NoError(t, err)
+	return l, path
+}
+
+// readLog returns "" for a file never written, lumberjack only creates it on the first line
+func readLog(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ""
+	}
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestComponentLevelOverride(t *testing.T) {
+	l, path := newFileLogger(t)
+	gorm := l.Named("gorm")
+
+	gorm.Debug("hidden debug")
+	l.Levels().SetLevel("gorm", zap.DebugLevel)
+	gorm.Debug("visible debug")
+	l.Debug("root debug")
+
+	content := readLog(t, path)
+	assert.NotContains(t, content, "hidden debug")
+	assert.Contains(t, content, "visible debug")
+	assert.NotContains(t, content, "root debug", "the override must not change other components")
+
+	l.Levels().ResetLevel("gorm")
+	l.Levels().SetDefault(zap.ErrorLevel)
+	gorm.Warn("after reset")
+	assert.NotContains(t, readLog(t, path), "after reset")
+
+	snapshot := l.Levels().Snapshot()
+	assert.Equal(t, "error", snapshot.Default)
+	assert.Equal(t, []string{RootComponent, "gorm"}, snapshot.Components)
+}
+
+func TestApplyOutputSwitchesWriters(t *testing.T) {
+	l, first := newFileLogger(t)
+	child := l.Named("scheduler").Log.With(zap.String("k", "v"))
+
+	second := filepath.Join(t.TempDir(), "other.log")
+	require.NoError(t, l.ApplyOutput(OutputConfig{File: FileConfig{Enabled: true, Path: second}}))
+	child.Info("after switch")
+
+	assert.NotContains(t, readLog(t, first), "after switch")
+	assert.True(t, strings.Contains(readLog(t, second), "after switch"))
+
+	assert.Error(t, l.ApplyOutput(OutputConfig{Sink: "ftp://example"}))
+	child.Info("still second")
+	assert.Contains(t, readLog(t, second), "still second", "a failed apply keeps the current output")
+}
@@ -3,9 +3,9 @@ package infrastructure
 import (
 	"context"
 	"errors"
-	"os"
 	"time"
 
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -13,38 +13,36 @@ import (
 )
 
 type Logger struct {
-	Log *zap.Logger
+	Log    *zap.Logger
+	levels *LevelRegistry
+	output *output
 }
 
-func NewLogger() (*Logger, error) {
-	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "timestamp",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		MessageKey:     "msg",
-		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.StringDurationEncoder,
-		EncodeCaller:   zapcore.FullCallerEncoder,
-		EncodeName:     zapcore.FullNameEncoder,
-	}
-
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(os.Stdout),
-		zap.NewAtomicLevelAt(zap.InfoLevel),
-	)
-
-	logger := zap.New(core)
+// RootComponent is the component of the logger returned by the constructors, the one most
+// services log through
+const RootComponent = "app"
 
-	return &Logger{Log: logger}, nil
+func NewLogger() (*Logger, error) {
+	return newLogger(zap.InfoLevel, OutputConfig{Stdout: true})
 }
 
 // NewDevelopmentLogger crea un logger para desarrollo con más información de debug
 func NewDevelopmentLogger() (*Logger, error) {
+	return newLogger(zap.DebugLevel, OutputConfig{
+		Stdout: true,
+		File: FileConfig{
+			Enabled:    true,
+			Path:       "logs/app.log",
+			MaxSizeMB:  100,
+			MaxAgeDays: 7,
+			MaxBackups: 5,
+		},
+	}, zap.AddStacktrace(zap.ErrorLevel))
+}
+
+// newLogger writes json lines to the configured output, the level of every component is
+// decided by the level registry so both can be changed at runtime
+func newLogger(level zapcore.Level, cfg OutputConfig, options ...zap.Option) (*Logger, error) {
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:        "timestamp",
 		LevelKey:       "level",
@@ -60,26 +58,19 @@ func NewDevelopmentLogger() (*Logger, error) {
 		EncodeName:     zapcore.FullNameEncoder,
 	}
 
-	// create file dir (if not exist)
-	logFilePath := "logs/app.log"
-	if err := os.MkdirAll("logs", os.ModePerm); err != nil {
-		return nil, err
-	}
-	// open log file
-	file, err := os.OpenFile(logFilePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	out, err := newOutput(cfg)
 	if err != nil {
 		return nil, err
 	}
+	levels := NewLevelRegistry(level)
+	levels.register(RootComponent)
 
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.NewMultiWriteSyncer(zapcore.AddSync(file), zapcore.AddSync(os.Stdout)),
-		zap.NewAtomicLevelAt(zap.DebugLevel),
-	)
-
-	logger := zap.New(core, zap.AddStacktrace(zap.ErrorLevel))
+	core := &leveledCore{
+		Core:  zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), out, zapcore.DebugLevel),
+		level: componentLevel{registry: levels, component: RootComponent},
+	}
 
-	return &Logger{Log: logger}, nil
+	return &Logger{Log: zap.New(core, options...), levels: levels, output: out}, nil
 }
 
 func (l *Logger) Info(msg string, fields ...zap.Field) {
@@ -159,7 +150,7 @@ func (l *Logger) GinZapLogger() gin.HandlerFunc {
 		start := time.Now()
 		c.Next()
 		latency := time.Since(start)
-		l.Log.Info("HTTP request", zap.String("method", c.Request.Method), zap.String("path", c.Request.URL.Path), zap.Int("status", c.Writer.Status()), zap.Duration("latency", latency), zap.String("client_ip", c.ClientIP()))
+		l.Log.Info("HTTP request", zap.String("method", c.Request.Method), zap.String("path", c.Request.URL.Path), zap.Int("status", c.Writer.Status()), zap.Duration("latency", latency), zap.String("client_ip", c.ClientIP()), zap.String("request_id", c.GetString(requestid.ContextKey)))
 	}
 }
 
@@ -208,7 +199,6 @@ func (l *GormZapLogger) Error(ctx context.Context, msg string, data ...interface
 
 func (l *GormZapLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
 	elapsed := time.Since(begin)
-
 	if err != nil {
 		if l.config.IgnoreRecordNotFoundError && errors.Is(err, gormlogger.ErrRecordNotFound) {
 			return
@@ -0,0 +1,263 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileConfig rotates the log file once it reaches MaxSizeMB, rotated files older than
+// MaxAgeDays or beyond MaxBackups are removed, 0 keeps them all
+type FileConfig struct {
+	Enabled    bool
+	Path       string
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+	Compress   bool
+}
+
+// OutputConfig lists where the json log lines are written. Sink is empty, "syslog" for the
+// local syslog daemon, or a tcp://host:port, udp://host:port or unix:///path socket address.
+type OutputConfig struct {
+	Stdout bool
+	File   FileConfig
+	Sink   string
+}
+
+// output is the write syncer of the logger cores, its writers are swapped when the
+// configuration changes so the loggers already handed out keep working
+type output struct {
+	mu      sync.RWMutex
+	writer  zapcore.WriteSyncer
+	closers []io.Closer
+	base    OutputConfig
+	current OutputConfig
+}
+
+func newOutput(cfg OutputConfig) (*output, error) {
+	out := &output{base: cfg}
+	if err := out.apply(cfg); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.writer.Write(p)
+}
+
+func (o *output) Sync() error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.writer.Sync()
+}
+
+// apply opens the writers of cfg and only then closes the previous ones, a configuration
+// that can't be opened leaves the current output untouched
+func (o *output) apply(cfg OutputConfig) error {
+	var writers []zapcore.WriteSyncer
+	var closers []io.Closer
+	fail := func(err error) error {
+		for _, closer := range closers {
+			_ = closer.Close()
+		}
+		return err
+	}
+
+	if cfg.Stdout {
+		writers = append(writers, zapcore.Lock(os.Stdout))
+	}
+	if cfg.File.Enabled {
+		if cfg.File.Path == "" {
+			return fail(errors.New("log file path is empty"))
+		}
+		if err := os.MkdirAll(filepath.Dir(cfg.File.Path), os.ModePerm); err != nil {
+			return fail(err)
+		}
+		file := &lumberjack.Logger{
+			Filename:   cfg.File.Path,
+			MaxSize:    cfg.File.MaxSizeMB,
+			MaxAge:     cfg.File.MaxAgeDays,
+			MaxBackups: cfg.File.MaxBackups,
+			Compress:   cfg.File.Compress,
+		}
+		writers = append(writers, zapcore.AddSync(file))
+		closers = append(closers, file)
+	}
+	if cfg.Sink != "" {
+		sink, err := openSink(cfg.Sink)
+		if err != nil {
+			return fail(err)
+		}
+		writers = append(writers, zapcore.AddSync(sink))
+		closers = append(closers, sink)
+	}
+
+	o.mu.Lock()
+	previous := o.closers
+	o.writer = zapcore.NewMultiWriteSyncer(writers...)
+	o.closers = closers
+	o.current = cfg
+	o.mu.Unlock()
+
+	for _, closer := range previous {
+		_ = closer.Close()
+	}
+	return nil
+}
+
+func (o *output) config() (base, current OutputConfig) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.base, o.current
+}
+
+func openSink(address string) (io.WriteCloser, error) {
+	if address == "syslog" {
+		return openSyslog()
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log sink %q: %w", address, err)
+	}
+	switch u.Scheme {
+	case "tcp", "udp":
+		return newSocketWriter(u.Scheme, u.Host, dialSink), nil
+	case "unix", "unixgram":
+		return newSocketWriter(u.Scheme, u.Path, dialSink), nil
+	default:
+		return nil, fmt.Errorf("unsupported log sink %q", address)
+	}
+}
+
+const (
+	sinkDialTimeout  = time.Second
+	sinkWriteTimeout = time.Second
+	sinkMinBackoff   = 100 * time.Millisecond
+	sinkMaxBackoff   = 30 * time.Second
+)
+
+func dialSink(network, address string) (net.Conn, error) {
+	return net.DialTimeout(network, address, sinkDialTimeout)
+}
+
+// socketWriter connects in the background and redials with a growing backoff after a failed
+// write. The lines written while the collector is unreachable are dropped, so logging never
+// waits for a dial and a collector restart only costs the lines written while it was down.
+type socketWriter struct {
+	network string
+	address string
+	dial    func(network, address string) (net.Conn, error)
+	done    chan struct{}
+
+	mu      sync.Mutex
+	conn    net.Conn
+	dialing bool
+	closed  bool
+}
+
+func newSocketWriter(network, address string, dial func(network, address string) (net.Conn, error)) *socketWriter {
+	w := &socketWriter{network: network, address: address, dial: dial, done: make(chan struct{})}
+	w.mu.Lock()
+	w.reconnect()
+	w.mu.Unlock()
+	return w
+}
+
+// reconnect starts dialing unless a dial is already running, w.mu must be held
+func (w *socketWriter) reconnect() {
+	if w.dialing || w.closed {
+		return
+	}
+	w.dialing = true
+	go w.redial()
+}
+
+func (w *socketWriter) redial() {
+	backoff := sinkMinBackoff
+	for {
+		conn, err := w.dial(w.network, w.address)
+		w.mu.Lock()
+		if err == nil && !w.closed {
+			w.conn = conn
+			w.dialing = false
+			w.mu.Unlock()
+			return
+		}
+		closed := w.closed
+		w.mu.Unlock()
+		if closed {
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		select {
+		case <-w.done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, sinkMaxBackoff)
+	}
+}
+
+func (w *socketWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		w.reconnect()
+		return len(p), nil
+	}
+	_ = w.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	n, err := w.conn.Write(p)
+	if err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		w.reconnect()
+	}
+	return n, err
+}
+
+func (w *socketWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// ApplyOutput replaces the log writers of this logger and of every logger derived from it
+func (l *Logger) ApplyOutput(cfg OutputConfig) error {
+	if l.output == nil {
+		return errors.New("logger output is not configurable")
+	}
+	return l.output.apply(cfg)
+}
+
+// OutputConfig returns the output the logger was created with and the one in use
+func (l *Logger) OutputConfig() (base, current OutputConfig) {
+	if l.output == nil {
+		return OutputConfig{}, OutputConfig{}
+	}
+	return l.output.config()
+}
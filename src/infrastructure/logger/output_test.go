@@ -0,0 +1,96 @@
+package infrastructure
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocketWriter_DropsLinesWhileDialing(t *testing.T) {
+	release := make(chan net.Conn)
+	w := newSocketWriter("tcp", "collector:5170", func(string, string) (net.Conn, error) {
+		return <-release, nil
+	})
+	defer func() { _ = w.Close() }()
+
+	start := time.Now()
+	n, err := w.Write([]byte("dropped\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 8, n)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "the write doesn't wait for the dial")
+
+	client, server := net.Pipe()
+	defer func() { _ = server.Close() }()
+	release <- client
+	assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.conn != nil
+	}, time.Second, 5*time.Millisecond)
+
+	received := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 64)
+		n, _ := server.Read(buf)
+		received <- string(buf[:n])
+	}()
+	_, err = w.Write([]byte("delivered\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "delivered\n", <-received)
+}
+
+func TestSocketWriter_RedialsAfterFailures(t *testing.T) {
+	var attempts atomic.Int32
+	var mu sync.Mutex
+	var server net.Conn
+	w := newSocketWriter("tcp", "collector:5170", func(string, string) (net.Conn, error) {
+		if attempts.Add(1) < 3 {
+			return nil, errors.New("connection refused")
+		}
+		client, other := net.Pipe()
+		mu.Lock()
+		server = other
+		mu.Unlock()
+		return client, nil
+	})
+	defer func() { _ = w.Close() }()
+
+	assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.conn != nil
+	}, 2*time.Second, 5*time.Millisecond, "the writer keeps dialing with a backoff")
+	assert.Equal(t, int32(3), attempts.Load())
+
+	// the collector goes away, the failed write starts a new dial
+	mu.Lock()
+	_ = server.Close()
+	mu.Unlock()
+	_, err := w.Write([]byte("lost\n"))
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+	assert.Eventually(t, func() bool { return attempts.Load() == 4 }, time.Second, 5*time.Millisecond)
+}
+
+func TestSocketWriter_CloseStopsDialing(t *testing.T) {
+	var attempts atomic.Int32
+	w := newSocketWriter("tcp", "collector:5170", func(string, string) (net.Conn, error) {
+		attempts.Add(1)
+		return nil, errors.New("connection refused")
+	})
+	require.Eventually(t, func() bool { return attempts.Load() >= 1 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, w.Close())
+	stopped := attempts.Load()
+	time.Sleep(3 * sinkMinBackoff)
+	assert.LessOrEqual(t, attempts.Load(), stopped+1)
+
+	n, err := w.Write([]byte("after close\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 12, n)
+}
@@ -0,0 +1,13 @@
+//go:build !windows && !plan9
+
+package infrastructure
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// openSyslog connects to the local syslog daemon, the json line is the message
+func openSyslog() (io.WriteCloser, error) {
+	return syslog.New(syslog.LOG_INFO|syslog.LOG_LOCAL0, "microservices-go")
+}
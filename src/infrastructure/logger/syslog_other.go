@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package infrastructure
+
+import (
+	"errors"
+	"io"
+)
+
+func openSyslog() (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not available on this platform")
+}
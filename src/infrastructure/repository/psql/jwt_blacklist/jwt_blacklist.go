@@ -1,6 +1,7 @@
 package jwt_blacklist
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -20,8 +21,8 @@ func (*JwtBlacklist) TableName() string {
 }
 
 type JwtBlacklistRepository interface {
-	AddToBlacklist(jwtToken string) error
-	IsJwtInBlacklist(token string) (bool, error)
+	AddToBlacklist(ctx context.Context, jwtToken string) error
+	IsJwtInBlacklist(ctx context.Context, token string) (bool, error)
 }
 
 type Repository struct {
@@ -33,16 +34,16 @@ func NewUJwtBlacklistRepository(db *gorm.DB) JwtBlacklistRepository {
 }
 
 // AddToBlacklist implements JwtBlacklistRepository.
-func (r *Repository) AddToBlacklist(jwtToken string) error {
-	result := r.DB.Create(&JwtBlacklist{
+func (r *Repository) AddToBlacklist(ctx context.Context, jwtToken string) error {
+	result := r.DB.WithContext(ctx).Create(&JwtBlacklist{
 		Jwt: jwtToken,
 	})
 	return result.Error
 }
 
 // IsJwtInBlacklist implements JwtBlacklistRepository.
-func (r *Repository) IsJwtInBlacklist(jwtToken string) (bool, error) {
+func (r *Repository) IsJwtInBlacklist(ctx context.Context, jwtToken string) (bool, error) {
 	var count int64
-	r.DB.Model(&JwtBlacklist{}).Where("jwt = ?", jwtToken).Count(&count)
+	r.DB.WithContext(ctx).Model(&JwtBlacklist{}).Where("jwt = ?", jwtToken).Count(&count)
 	return count > 0, nil
 }
@@ -5,14 +5,23 @@ import (
 	"os"
 	"strings"
 
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/api"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/ignore_api"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/menu_btn_api"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/upload_session"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/user_role"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/user"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
 // DatabaseConfig holds database-related configuration
@@ -117,7 +126,7 @@ func (r *PSQLRepository) InitDatabase() error {
 	}
 
 	// Create GORM logger with zap
-	gormZap := logger.NewGormLogger(r.Logger.Log).
+	gormZap := logger.NewGormLogger(r.Logger.Named("gorm").Log).
 		LogMode(gormlogger.Warn) // Silent / Error / Warn / Info
 
 	r.DB, err = gorm.Open(postgres.Open(cfg.GetDSN()), &gorm.Config{
@@ -127,6 +136,16 @@ func (r *PSQLRepository) InitDatabase() error {
 		r.Logger.Error("Error connecting to the database", zap.Error(err))
 		return err
 	}
+	// the repositories run their statements with the caller's context so query spans join the
+	// request trace, bound values are left out so passwords and tokens never reach the exporter
+	if err = r.DB.Use(gormtracing.NewPlugin(gormtracing.WithoutQueryVariables(), gormtracing.WithoutMetrics())); err != nil {
+		r.Logger.Error("Error installing the database tracing plugin", zap.Error(err))
+		return err
+	}
+	if err = r.DB.Use(metrics.GormPlugin{}); err != nil {
+		r.Logger.Error("Error installing the database metrics plugin", zap.Error(err))
+		return err
+	}
 
 	err = r.MigrateEntitiesGORM()
 	if err != nil {
@@ -148,9 +167,21 @@ func (r *PSQLRepository) MigrateEntitiesGORM() error {
 	// Import the models to register them with GORM
 	userModel := &user.User{}
 	apiModal := &api.SysApi{}
+	menuBtnApiModel := &menu_btn_api.SysMenuBtnApi{}
+	userRoleModel := &user_role.SysUserRole{}
+	ignoreApiModel := &ignore_api.SysIgnoreApi{}
+	auditLogModel := &audit_log.SysAuditLog{}
+	operationRecordModel := &operation_records.SysOperationRecord{}
+	filesModel := &files.SysFiles{}
+	fileBlobModel := &files.SysFileBlob{}
+	fileRenditionModel := &files.SysFileRendition{}
+	fileAttachmentModel := &files.SysFileAttachment{}
+	uploadSessionModel := &upload_session.SysUploadSession{}
+	uploadPartModel := &upload_session.SysUploadPart{}
 
 	// Auto migrate the models to create/update tables
-	err := r.DB.AutoMigrate(userModel, apiModal)
+	err := r.DB.AutoMigrate(userModel, apiModal, menuBtnApiModel, userRoleModel, ignoreApiModel, auditLogModel, operationRecordModel,
+		filesModel, fileBlobModel, fileRenditionModel, fileAttachmentModel, uploadSessionModel, uploadPartModel)
 	if err != nil {
 		r.Logger.Error("Error migrating database entities", zap.Error(err))
 		return err
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,8 @@ import (
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
 	domainApi "github.com/gbrayhan/microservices-go/src/domain/sys/api"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/casbin_rule"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/menu_btn_api"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/utils"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -45,15 +48,16 @@ var ColumnsApiMapping = map[string]string{
 
 // ApiRepositoryInterface defines the interface for api repository operations
 type ApiRepositoryInterface interface {
-	GetAll() (*[]domainApi.Api, error)
-	Create(apiDomain *domainApi.Api) (*domainApi.Api, error)
-	GetByID(id int) (*domainApi.Api, error)
-	Update(id int, apiMap map[string]interface{}) (*domainApi.Api, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainApi.Api], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(apiMap map[string]interface{}) (*domainApi.Api, error)
-	Upsert(api *SysApi) (bool, error)
+	GetAll(ctx context.Context) (*[]domainApi.Api, error)
+	Create(ctx context.Context, apiDomain *domainApi.Api) (*domainApi.Api, error)
+	GetByID(ctx context.Context, id int) (*domainApi.Api, error)
+	Update(ctx context.Context, id int, apiMap map[string]interface{}) (*domainApi.Api, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainApi.Api], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, apiMap map[string]interface{}) (*domainApi.Api, error)
+	Upsert(ctx context.Context, api *SysApi) (created bool, updated bool, err error)
+	Prune(ctx context.Context, ids []int) error
 }
 
 type Repository struct {
@@ -65,9 +69,9 @@ func NewApiRepository(db *gorm.DB, loggerInstance *logger.Logger) ApiRepositoryI
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainApi.Api, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainApi.Api, error) {
 	var apis []SysApi
-	if err := r.DB.Find(&apis).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&apis).Error; err != nil {
 		r.Logger.Error("Error getting all apis", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -75,10 +79,10 @@ func (r *Repository) GetAll() (*[]domainApi.Api, error) {
 	return arrayToDomainMapper(&apis), nil
 }
 
-func (r *Repository) Create(apiDomain *domainApi.Api) (*domainApi.Api, error) {
+func (r *Repository) Create(ctx context.Context, apiDomain *domainApi.Api) (*domainApi.Api, error) {
 	r.Logger.Info("Creating new api", zap.String("path", apiDomain.Path))
 	apiRepository := fromDomainMapper(apiDomain)
-	txDb := r.DB.Create(apiRepository)
+	txDb := r.DB.WithContext(ctx).Create(apiRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating api", zap.Error(err), zap.String("Path", apiDomain.Path))
@@ -100,9 +104,9 @@ func (r *Repository) Create(apiDomain *domainApi.Api) (*domainApi.Api, error) {
 	return apiRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainApi.Api, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainApi.Api, error) {
 	var api SysApi
-	err := r.DB.Where("id = ?", id).First(&api).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&api).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("Api not found", zap.Int("id", id))
@@ -117,11 +121,11 @@ func (r *Repository) GetByID(id int) (*domainApi.Api, error) {
 	return api.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainApi.Api, error) {
+func (r *Repository) Update(ctx context.Context, id int, apiMap map[string]interface{}) (*domainApi.Api, error) {
 	var apiObj SysApi
 	apiObj.ID = id
 	delete(apiMap, "updated_at")
-	err := r.DB.Model(&apiObj).Updates(apiMap).Error
+	err := r.DB.WithContext(ctx).Model(&apiObj).Updates(apiMap).Error
 	if err != nil {
 		r.Logger.Error("Error updating api", zap.Error(err), zap.Int("id", id))
 		byteErr, _ := json.Marshal(err)
@@ -137,7 +141,7 @@ func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainApi.A
 			return &domainApi.Api{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&apiObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&apiObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated api", zap.Error(err), zap.Int("id", id))
 		return &domainApi.Api{}, err
 	}
@@ -145,8 +149,8 @@ func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainApi.A
 	return apiObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(ids []int) error {
-	tx := r.DB.Where("id IN ?", ids).Delete(&SysApi{})
+func (r *Repository) Delete(ctx context.Context, ids []int) error {
+	tx := r.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&SysApi{})
 
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting api", zap.Error(tx.Error), zap.String("ids", fmt.Sprintf("%v", ids)))
@@ -160,8 +164,8 @@ func (r *Repository) Delete(ids []int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainApi.Api], error) {
-	query := r.DB.Model(&SysApi{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainApi.Api], error) {
+	query := r.DB.WithContext(ctx).Model(&SysApi{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -248,7 +252,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsApiMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -256,7 +260,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysApi{}).
+	if err := r.DB.WithContext(ctx).Model(&SysApi{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -272,27 +276,66 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	return &coincidences, nil
 }
 
-func (r *Repository) Upsert(api *SysApi) (bool, error) {
+// Upsert creates the api when missing. An existing api is only reclassified while it is still
+// in the default group, so groups and descriptions edited by hand are kept.
+func (r *Repository) Upsert(ctx context.Context, api *SysApi) (bool, bool, error) {
 	var existingApi SysApi
 
 	// 查找是否已存在
-	err := r.DB.Where("path = ? AND method = ?", api.Path, api.Method).
+	err := r.DB.WithContext(ctx).Where("path = ? AND method = ?", api.Path, api.Method).
 		First(&existingApi).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return false, err
+		return false, false, err
 	}
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		// 不存在则创建
-		result := r.DB.Create(api)
+		result := r.DB.WithContext(ctx).Create(api)
 		if result.Error != nil {
-			return false, result.Error
+			return false, false, result.Error
 		}
-	} else {
-		return false, nil
+		return true, false, nil
 	}
+	if existingApi.ApiGroup != domainApi.DefaultGroup || api.ApiGroup == domainApi.DefaultGroup {
+		return false, false, nil
+	}
+	err = r.DB.WithContext(ctx).Model(&existingApi).Updates(map[string]interface{}{
+		"api_group":   api.ApiGroup,
+		"description": api.Description,
+	}).Error
+	if err != nil {
+		return false, false, err
+	}
+	return false, true, nil
+}
 
-	return true, nil
+// Prune deletes the apis together with the casbin policies and button bindings pointing at them
+func (r *Repository) Prune(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var apis []SysApi
+		if err := tx.Where("id IN ?", ids).Find(&apis).Error; err != nil {
+			return err
+		}
+		for _, api := range apis {
+			if err := tx.Where("ptype = ? AND v1 = ? AND v2 = ?", "p", api.Path, api.Method).
+				Delete(&casbin_rule.CasbinRule{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("sys_api_id IN ?", ids).Delete(&menu_btn_api.SysMenuBtnApi{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&SysApi{}).Error
+	})
+	if err != nil {
+		r.Logger.Error("Error pruning apis", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", ids)))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	r.Logger.Info("Successfully pruned apis", zap.String("ids", fmt.Sprintf("%v", ids)))
+	return nil
 }
 
 func (u *SysApi) toDomainMapper() *domainApi.Api {
@@ -327,9 +370,9 @@ func arrayToDomainMapper(apis *[]SysApi) *[]domainApi.Api {
 	return &apisDomain
 }
 
-func (r *Repository) GetOneByMap(apiMap map[string]interface{}) (*domainApi.Api, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, apiMap map[string]interface{}) (*domainApi.Api, error) {
 	var apiRepository SysApi
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range apiMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
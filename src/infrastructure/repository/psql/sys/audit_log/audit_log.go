@@ -0,0 +1,271 @@
+package audit_log
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/gbrayhan/microservices-go/src/domain"
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SysAuditLog represents the sys_audit_logs table, one row per entity change
+type SysAuditLog struct {
+	ID         int                              `gorm:"column:id;primary_key;autoIncrement" json:"id"`
+	CreatedAt  time.Time                        `gorm:"column:created_at;index" json:"createdAt"`
+	EntityType string                           `gorm:"column:entity_type;type:varchar(50);index:idx_sys_audit_logs_entity" json:"entityType"`
+	EntityID   string                           `gorm:"column:entity_id;type:varchar(100);index:idx_sys_audit_logs_entity" json:"entityId"`
+	ActorID    int64                            `gorm:"column:actor_id;index" json:"actorId"`
+	Action     string                           `gorm:"column:action;type:varchar(20)" json:"action"`
+	Changes    map[string]domainAuditLog.Change `gorm:"column:changes;type:jsonb;serializer:json" json:"changes"`
+}
+
+func (SysAuditLog) TableName() string {
+	return "sys_audit_logs"
+}
+
+var ColumnsAuditLogMapping = map[string]string{
+	"id":         "id",
+	"entityType": "entity_type",
+	"entityId":   "entity_id",
+	"actorId":    "actor_id",
+	"action":     "action",
+	"createdAt":  "created_at",
+}
+
+// ignoredColumns change on every update and carry no information for the trail
+var ignoredColumns = []string{"updated_at"}
+
+// AuditLogRepositoryInterface defines the interface for audit log repository operations
+type AuditLogRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*domainAuditLog.AuditLog, error)
+	// Create stores an entry that isn't an entity update, e.g. the issuance of credentials
+	Create(ctx context.Context, entry *domainAuditLog.AuditLog) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainAuditLog.AuditLog], error)
+}
+
+type Repository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewAuditLogRepository(db *gorm.DB, loggerInstance *logger.Logger) AuditLogRepositoryInterface {
+	return &Repository{DB: db, Logger: loggerInstance}
+}
+
+// Snapshot returns the column values of a loaded GORM model keyed by column name,
+// associations are left out and pointers are dereferenced
+func Snapshot(db *gorm.DB, model any) (map[string]any, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	value := reflect.Indirect(reflect.ValueOf(model))
+	snapshot := make(map[string]any, len(stmt.Schema.Fields))
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		fieldValue, _ := field.ValueOf(context.Background(), value)
+		if v := reflect.ValueOf(fieldValue); v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				fieldValue = nil
+			} else {
+				fieldValue = v.Elem().Interface()
+			}
+		}
+		snapshot[field.DBName] = fieldValue
+	}
+	return snapshot, nil
+}
+
+// RecordUpdate stores an update entry with the columns that differ between the before and after
+// models, nothing is stored when no column changed. Changes made by the system are recorded too,
+// under SystemActor.
+func RecordUpdate(db *gorm.DB, entityType string, entityID any, actorID int64, before, after any) error {
+	beforeSnapshot, err := Snapshot(db, before)
+	if err != nil {
+		return err
+	}
+	afterSnapshot, err := Snapshot(db, after)
+	if err != nil {
+		return err
+	}
+	changes := domainAuditLog.Diff(beforeSnapshot, afterSnapshot, ignoredColumns...)
+	if len(changes) == 0 {
+		return nil
+	}
+	return db.Create(&SysAuditLog{
+		EntityType: entityType,
+		EntityID:   fmt.Sprintf("%v", entityID),
+		ActorID:    actorID,
+		Action:     domainAuditLog.ActionUpdate,
+		Changes:    changes,
+	}).Error
+}
+
+// UpdateAudited runs update in a transaction that locks and reads the row with the given id into
+// before, reloads it into after and records the changed columns, so the entry is stored with
+// the change or not at all. A missing row returns gorm.ErrRecordNotFound.
+func UpdateAudited(db *gorm.DB, entityType string, id any, actorID int64, before, after any, update func(tx *gorm.DB) *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(before).Error; err != nil {
+			return err
+		}
+		result := update(tx)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("id = ?", id).First(after).Error; err != nil {
+			return err
+		}
+		return RecordUpdate(tx, entityType, id, actorID, before, after)
+	})
+}
+
+func (r *Repository) Create(ctx context.Context, entry *domainAuditLog.AuditLog) error {
+	auditLog := &SysAuditLog{
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		Changes:    entry.Changes,
+	}
+	if err := r.DB.WithContext(ctx).Create(auditLog).Error; err != nil {
+		r.Logger.Error("Error creating audit log", zap.Error(err), zap.String("entityType", entry.EntityType),
+			zap.String("action", entry.Action))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	entry.ID = auditLog.ID
+	entry.CreatedAt = auditLog.CreatedAt
+	return nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainAuditLog.AuditLog, error) {
+	var auditLog SysAuditLog
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&auditLog).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			r.Logger.Warn("Audit log not found", zap.Int("id", id))
+			err = domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		} else {
+			r.Logger.Error("Error getting audit log by ID", zap.Error(err), zap.Int("id", id))
+			err = domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		}
+		return &domainAuditLog.AuditLog{}, err
+	}
+	return auditLog.toDomainMapper(), nil
+}
+
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainAuditLog.AuditLog], error) {
+	query := r.DB.WithContext(ctx).Model(&SysAuditLog{})
+
+	// Apply like filters
+	for field, values := range filters.LikeFilters {
+		column := ColumnsAuditLogMapping[field]
+		if column == "" {
+			continue
+		}
+		for _, value := range values {
+			if value != "" {
+				query = query.Where(column+"::text ILIKE ?", "%"+value+"%")
+			}
+		}
+	}
+
+	// Apply exact matches
+	for field, values := range filters.Matches {
+		if len(values) > 0 {
+			column := ColumnsAuditLogMapping[field]
+			if column != "" {
+				query = query.Where(column+" IN ?", values)
+			}
+		}
+	}
+
+	// Apply date range filters
+	for _, dateFilter := range filters.DateRangeFilters {
+		column := ColumnsAuditLogMapping[dateFilter.Field]
+		if column != "" {
+			if dateFilter.Start != nil {
+				query = query.Where(column+" >= ?", dateFilter.Start)
+			}
+			if dateFilter.End != nil {
+				query = query.Where(column+" <= ?", dateFilter.End)
+			}
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.Logger.Error("Error counting audit logs", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+
+	// Apply sorting, newest first unless asked otherwise
+	sorted := false
+	if len(filters.SortBy) > 0 && filters.SortDirection.IsValid() {
+		for _, sortField := range filters.SortBy {
+			column := ColumnsAuditLogMapping[sortField]
+			if column != "" {
+				query = query.Order(column + " " + string(filters.SortDirection))
+				sorted = true
+			}
+		}
+	}
+	if !sorted {
+		query = query.Order("id desc")
+	}
+
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.PageSize < 1 {
+		filters.PageSize = 10
+	}
+	offset := (filters.Page - 1) * filters.PageSize
+
+	var auditLogs []SysAuditLog
+	if err := query.Offset(offset).Limit(filters.PageSize).Find(&auditLogs).Error; err != nil {
+		r.Logger.Error("Error searching audit logs", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+
+	totalPages := int((total + int64(filters.PageSize) - 1) / int64(filters.PageSize))
+	return &domain.PaginatedResult[domainAuditLog.AuditLog]{
+		Data:       arrayToDomainMapper(&auditLogs),
+		Total:      total,
+		Page:       filters.Page,
+		PageSize:   filters.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (a *SysAuditLog) toDomainMapper() *domainAuditLog.AuditLog {
+	return &domainAuditLog.AuditLog{
+		ID:         a.ID,
+		EntityType: a.EntityType,
+		EntityID:   a.EntityID,
+		ActorID:    a.ActorID,
+		Action:     a.Action,
+		Changes:    a.Changes,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+func arrayToDomainMapper(auditLogs *[]SysAuditLog) *[]domainAuditLog.AuditLog {
+	auditLogsDomain := make([]domainAuditLog.AuditLog, len(*auditLogs))
+	for i, auditLog := range *auditLogs {
+		auditLogsDomain[i] = *auditLog.toDomainMapper()
+	}
+	return &auditLogsDomain
+}
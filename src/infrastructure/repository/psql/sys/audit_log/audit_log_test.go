@@ -0,0 +1,72 @@
+package audit_log
+
+import (
+	"testing"
+
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+type item struct {
+	ID   int    `gorm:"column:id;primary_key"`
+	Name string `gorm:"column:name"`
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormLogger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&item{}, &SysAuditLog{}))
+	require.NoError(t, db.Create(&item{ID: 1, Name: "before"}).Error)
+	return db
+}
+
+func update(id int, name string) (*item, *item, func(tx *gorm.DB) *gorm.DB) {
+	before, after := &item{}, &item{ID: id}
+	return before, after, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(after).Updates(map[string]any{"name": name})
+	}
+}
+
+func TestUpdateAudited(t *testing.T) {
+	db := newTestDB(t)
+
+	before, after, fn := update(1, "after")
+	require.NoError(t, UpdateAudited(db, "item", 1, domainAuditLog.SystemActor, before, after, fn))
+	assert.Equal(t, "before", before.Name)
+	assert.Equal(t, "after", after.Name)
+
+	var entries []SysAuditLog
+	require.NoError(t, db.Find(&entries).Error)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "1", entries[0].EntityID)
+	assert.Equal(t, domainAuditLog.SystemActor, entries[0].ActorID)
+	assert.Equal(t, domainAuditLog.Change{Before: "before", After: "after"}, entries[0].Changes["name"])
+}
+
+func TestUpdateAudited_MissingRow(t *testing.T) {
+	db := newTestDB(t)
+
+	before, after, fn := update(2, "after")
+	err := UpdateAudited(db, "item", 2, 7, before, after, fn)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	var count int64
+	require.NoError(t, db.Model(&SysAuditLog{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestUpdateAudited_RollsBackWhenAuditFails(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.Migrator().DropTable(&SysAuditLog{}))
+
+	before, after, fn := update(1, "after")
+	assert.Error(t, UpdateAudited(db, "item", 1, 7, before, after, fn))
+
+	var stored item
+	require.NoError(t, db.First(&stored, 1).Error)
+	assert.Equal(t, "before", stored.Name)
+}
@@ -1,6 +1,7 @@
 package base_menu
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -8,8 +9,10 @@ import (
 	"github.com/gbrayhan/microservices-go/src/domain"
 
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
 	domainMenu "github.com/gbrayhan/microservices-go/src/domain/sys/menu"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	auditLogRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
 	menuBtnRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu_btn"
 	menuParamRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu_parameter"
 
@@ -55,15 +58,15 @@ var ColumnsMenuMapping = map[string]string{
 
 // MenuRepositoryInterface defines the interface for menu repository operations
 type MenuRepositoryInterface interface {
-	GetAll(groupId int) (*[]domainMenu.Menu, error)
-	Create(menuDomain *domainMenu.Menu) (*domainMenu.Menu, error)
-	GetByID(id int) (*domainMenu.Menu, error)
-	Update(id int, menuMap map[string]interface{}) (*domainMenu.Menu, error)
-	Delete(id int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainMenu.Menu], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(menuMap map[string]interface{}) (*domainMenu.Menu, error)
-	GetByIDs(ids []int) (*[]domainMenu.Menu, error)
+	GetAll(ctx context.Context, groupId int) (*[]domainMenu.Menu, error)
+	Create(ctx context.Context, menuDomain *domainMenu.Menu) (*domainMenu.Menu, error)
+	GetByID(ctx context.Context, id int) (*domainMenu.Menu, error)
+	Update(ctx context.Context, id int, actorId int64, menuMap map[string]interface{}) (*domainMenu.Menu, error)
+	Delete(ctx context.Context, id int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainMenu.Menu], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, menuMap map[string]interface{}) (*domainMenu.Menu, error)
+	GetByIDs(ctx context.Context, ids []int) (*[]domainMenu.Menu, error)
 }
 
 type Repository struct {
@@ -75,9 +78,9 @@ func NewMenuRepository(db *gorm.DB, loggerInstance *logger.Logger) MenuRepositor
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll(groupId int) (*[]domainMenu.Menu, error) {
+func (r *Repository) GetAll(ctx context.Context, groupId int) (*[]domainMenu.Menu, error) {
 	var menus []SysBaseMenu
-	tx := r.DB
+	tx := r.DB.WithContext(ctx)
 	if groupId != 0 {
 		tx = tx.Where("menu_group_id = ?", groupId)
 	}
@@ -89,10 +92,10 @@ func (r *Repository) GetAll(groupId int) (*[]domainMenu.Menu, error) {
 	return ArrayToDomainMapper(&menus), nil
 }
 
-func (r *Repository) Create(menuDomain *domainMenu.Menu) (*domainMenu.Menu, error) {
+func (r *Repository) Create(ctx context.Context, menuDomain *domainMenu.Menu) (*domainMenu.Menu, error) {
 	r.Logger.Info("Creating new menu", zap.String("path", menuDomain.Path))
 	menuRepository := fromDomainMapper(menuDomain)
-	txDb := r.DB.Create(menuRepository)
+	txDb := r.DB.WithContext(ctx).Create(menuRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating menu", zap.Error(err), zap.String("Path", menuDomain.Path))
@@ -114,9 +117,9 @@ func (r *Repository) Create(menuDomain *domainMenu.Menu) (*domainMenu.Menu, erro
 	return menuRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainMenu.Menu, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainMenu.Menu, error) {
 	var menu SysBaseMenu
-	err := r.DB.Where("id = ?", id).First(&menu).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&menu).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("Menu not found", zap.Int("id", id))
@@ -131,15 +134,21 @@ func (r *Repository) GetByID(id int) (*domainMenu.Menu, error) {
 	return menu.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, menuMap map[string]interface{}) (*domainMenu.Menu, error) {
+func (r *Repository) Update(ctx context.Context, id int, actorId int64, menuMap map[string]interface{}) (*domainMenu.Menu, error) {
 	var menuObj SysBaseMenu
 	menuObj.ID = id
 	delete(menuMap, "updated_at")
-	err := r.DB.Model(&menuObj).
-		Select("parent_id", "menu_level", "name", "path", "component", "hidden", "sort", "icon", "title", "keep_alive").
-		Updates(menuMap).Error
+	var before SysBaseMenu
+	err := auditLogRepo.UpdateAudited(r.DB.WithContext(ctx), domainAuditLog.EntityMenu, id, actorId, &before, &menuObj, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&menuObj).
+			Select("parent_id", "menu_level", "name", "path", "component", "hidden", "sort", "icon", "title", "keep_alive").
+			Updates(menuMap)
+	})
 	if err != nil {
 		r.Logger.Error("Error updating menu", zap.Error(err), zap.Int("id", id))
+		if err == gorm.ErrRecordNotFound {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
 		byteErr, _ := json.Marshal(err)
 		var newError domainErrors.GormErr
 		errUnmarshal := json.Unmarshal(byteErr, &newError)
@@ -153,16 +162,12 @@ func (r *Repository) Update(id int, menuMap map[string]interface{}) (*domainMenu
 			return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&menuObj).Error; err != nil {
-		r.Logger.Error("Error retrieving updated menu", zap.Error(err), zap.Int("id", id))
-		return nil, err
-	}
 	r.Logger.Info("Successfully updated menu", zap.Int("id", id))
 	return menuObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(id int) error {
-	tx := r.DB.Delete(&SysBaseMenu{}, id)
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	tx := r.DB.WithContext(ctx).Delete(&SysBaseMenu{}, id)
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting menu", zap.Error(tx.Error), zap.Int("id", id))
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -175,8 +180,8 @@ func (r *Repository) Delete(id int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainMenu.Menu], error) {
-	query := r.DB.Model(&SysBaseMenu{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainMenu.Menu], error) {
+	query := r.DB.WithContext(ctx).Model(&SysBaseMenu{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -263,7 +268,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsMenuMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -271,7 +276,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysBaseMenu{}).
+	if err := r.DB.WithContext(ctx).Model(&SysBaseMenu{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -287,9 +292,9 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	return &coincidences, nil
 }
 
-func (r *Repository) GetByIDs(ids []int) (*[]domainMenu.Menu, error) {
+func (r *Repository) GetByIDs(ctx context.Context, ids []int) (*[]domainMenu.Menu, error) {
 	var menus []SysBaseMenu
-	if err := r.DB.Where("id in (?)", ids).Find(&menus).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id in (?)", ids).Find(&menus).Error; err != nil {
 		r.Logger.Error("Error getting all menus", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -343,9 +348,9 @@ func ArrayToDomainMapper(menus *[]SysBaseMenu) *[]domainMenu.Menu {
 	return &menusDomain
 }
 
-func (r *Repository) GetOneByMap(menuMap map[string]interface{}) (*domainMenu.Menu, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, menuMap map[string]interface{}) (*domainMenu.Menu, error) {
 	var menuRepository SysBaseMenu
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range menuMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
@@ -1,6 +1,7 @@
 package base_menu_btn
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -42,15 +43,15 @@ var ColumnsMenuBtnMapping = map[string]string{
 
 // MenuBtnRepositoryInterface defines the interface for menu repository operations
 type MenuBtnRepositoryInterface interface {
-	GetAll(menuId int64) (*[]domainMenuBtn.MenuBtn, error)
-	Create(menuDomain *domainMenuBtn.MenuBtn) (*domainMenuBtn.MenuBtn, error)
-	GetByID(id int) (*domainMenuBtn.MenuBtn, error)
-	Update(id int, menuMap map[string]interface{}) (*domainMenuBtn.MenuBtn, error)
-	Delete(id int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainMenuBtn.MenuBtn], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(menuMap map[string]interface{}) (*domainMenuBtn.MenuBtn, error)
-	GetByIDs(ids []int) (*[]domainMenuBtn.MenuBtn, error)
+	GetAll(ctx context.Context, menuId int64) (*[]domainMenuBtn.MenuBtn, error)
+	Create(ctx context.Context, menuDomain *domainMenuBtn.MenuBtn) (*domainMenuBtn.MenuBtn, error)
+	GetByID(ctx context.Context, id int) (*domainMenuBtn.MenuBtn, error)
+	Update(ctx context.Context, id int, menuMap map[string]interface{}) (*domainMenuBtn.MenuBtn, error)
+	Delete(ctx context.Context, id int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainMenuBtn.MenuBtn], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, menuMap map[string]interface{}) (*domainMenuBtn.MenuBtn, error)
+	GetByIDs(ctx context.Context, ids []int) (*[]domainMenuBtn.MenuBtn, error)
 }
 
 type Repository struct {
@@ -65,9 +66,9 @@ func NewMenuBtnRepository(db *gorm.DB, loggerInstance *logger.Logger) MenuBtnRep
 	}
 }
 
-func (r *Repository) GetAll(menuId int64) (*[]domainMenuBtn.MenuBtn, error) {
+func (r *Repository) GetAll(ctx context.Context, menuId int64) (*[]domainMenuBtn.MenuBtn, error) {
 	var menus []SysBaseMenuBtn
-	tx := r.DB
+	tx := r.DB.WithContext(ctx)
 	if menuId != 0 {
 		tx = tx.Where("sys_base_menu_id = ?", menuId)
 	}
@@ -79,10 +80,10 @@ func (r *Repository) GetAll(menuId int64) (*[]domainMenuBtn.MenuBtn, error) {
 	return ArrayToDomainMapper(&menus), nil
 }
 
-func (r *Repository) Create(menuDomain *domainMenuBtn.MenuBtn) (*domainMenuBtn.MenuBtn, error) {
+func (r *Repository) Create(ctx context.Context, menuDomain *domainMenuBtn.MenuBtn) (*domainMenuBtn.MenuBtn, error) {
 	r.Logger.Info("Creating new menu", zap.String("Name", menuDomain.Name))
 	menuRepository := fromDomainMapper(menuDomain)
-	txDb := r.DB.Create(menuRepository)
+	txDb := r.DB.WithContext(ctx).Create(menuRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating menu", zap.Error(err), zap.String("Name", menuDomain.Name))
@@ -104,9 +105,9 @@ func (r *Repository) Create(menuDomain *domainMenuBtn.MenuBtn) (*domainMenuBtn.M
 	return menuRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainMenuBtn.MenuBtn, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainMenuBtn.MenuBtn, error) {
 	var menu SysBaseMenuBtn
-	err := r.DB.Where("id = ?", id).First(&menu).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&menu).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("MenuBtn not found", zap.Int("id", id))
@@ -121,11 +122,11 @@ func (r *Repository) GetByID(id int) (*domainMenuBtn.MenuBtn, error) {
 	return menu.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, menuMap map[string]interface{}) (*domainMenuBtn.MenuBtn, error) {
+func (r *Repository) Update(ctx context.Context, id int, menuMap map[string]interface{}) (*domainMenuBtn.MenuBtn, error) {
 	var menuObj SysBaseMenuBtn
 	menuObj.ID = id
 	delete(menuMap, "updated_at")
-	err := r.DB.Model(&menuObj).
+	err := r.DB.WithContext(ctx).Model(&menuObj).
 		Select("name", "desc").
 		Updates(menuMap).Error
 	if err != nil {
@@ -143,7 +144,7 @@ func (r *Repository) Update(id int, menuMap map[string]interface{}) (*domainMenu
 			return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&menuObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&menuObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated menu", zap.Error(err), zap.Int("id", id))
 		return nil, err
 	}
@@ -151,8 +152,8 @@ func (r *Repository) Update(id int, menuMap map[string]interface{}) (*domainMenu
 	return menuObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(id int) error {
-	tx := r.DB.Delete(&SysBaseMenuBtn{}, id)
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	tx := r.DB.WithContext(ctx).Delete(&SysBaseMenuBtn{}, id)
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting menu", zap.Error(tx.Error), zap.Int("id", id))
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -165,8 +166,8 @@ func (r *Repository) Delete(id int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainMenuBtn.MenuBtn], error) {
-	query := r.DB.Model(&SysBaseMenuBtn{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainMenuBtn.MenuBtn], error) {
+	query := r.DB.WithContext(ctx).Model(&SysBaseMenuBtn{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -253,7 +254,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsMenuBtnMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -261,7 +262,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysBaseMenuBtn{}).
+	if err := r.DB.WithContext(ctx).Model(&SysBaseMenuBtn{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -277,9 +278,9 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	return &coincidences, nil
 }
 
-func (r *Repository) GetByIDs(ids []int) (*[]domainMenuBtn.MenuBtn, error) {
+func (r *Repository) GetByIDs(ctx context.Context, ids []int) (*[]domainMenuBtn.MenuBtn, error) {
 	var menus []SysBaseMenuBtn
-	if err := r.DB.Where("id in (?)", ids).Find(&menus).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id in (?)", ids).Find(&menus).Error; err != nil {
 		r.Logger.Error("Error getting all menus", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -317,9 +318,9 @@ func ArrayToDomainMapper(menus *[]SysBaseMenuBtn) *[]domainMenuBtn.MenuBtn {
 	return &menusDomain
 }
 
-func (r *Repository) GetOneByMap(menuMap map[string]interface{}) (*domainMenuBtn.MenuBtn, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, menuMap map[string]interface{}) (*domainMenuBtn.MenuBtn, error) {
 	var menuRepository SysBaseMenuBtn
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range menuMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
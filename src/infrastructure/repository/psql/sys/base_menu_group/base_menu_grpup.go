@@ -1,6 +1,7 @@
 package base_menu_group
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -47,15 +48,15 @@ var ColumnsMenuGroupMapping = map[string]string{
 
 // MenuGroupRepositoryInterface defines the interface for api repository operations
 type MenuGroupRepositoryInterface interface {
-	GetAll() (*[]domainMenuGroup.MenuGroup, error)
-	Create(apiDomain *domainMenuGroup.MenuGroup) (*domainMenuGroup.MenuGroup, error)
-	GetByID(id int) (*domainMenuGroup.MenuGroup, error)
-	Update(id int, apiMap map[string]interface{}) (*domainMenuGroup.MenuGroup, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainMenuGroup.MenuGroup], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(apiMap map[string]interface{}) (*domainMenuGroup.MenuGroup, error)
-	GetByRoleId(roleMenuIds []int, roleId int64) (*[]domainMenuGroup.MenuGroup, error)
+	GetAll(ctx context.Context) (*[]domainMenuGroup.MenuGroup, error)
+	Create(ctx context.Context, apiDomain *domainMenuGroup.MenuGroup) (*domainMenuGroup.MenuGroup, error)
+	GetByID(ctx context.Context, id int) (*domainMenuGroup.MenuGroup, error)
+	Update(ctx context.Context, id int, apiMap map[string]interface{}) (*domainMenuGroup.MenuGroup, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainMenuGroup.MenuGroup], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, apiMap map[string]interface{}) (*domainMenuGroup.MenuGroup, error)
+	GetByRoleId(ctx context.Context, roleMenuIds []int, roleId int64) (*[]domainMenuGroup.MenuGroup, error)
 }
 
 type Repository struct {
@@ -67,9 +68,9 @@ func NewMenuGroupRepository(db *gorm.DB, loggerInstance *logger.Logger) MenuGrou
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainMenuGroup.MenuGroup, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainMenuGroup.MenuGroup, error) {
 	var apis []SysBaseMenuGroups
-	if err := r.DB.
+	if err := r.DB.WithContext(ctx).
 		Preload("MenuItems").
 		Preload("MenuItems.MenuBtns").
 		Preload("MenuItems.MenuParameters").Find(&apis).Error; err != nil {
@@ -80,9 +81,9 @@ func (r *Repository) GetAll() (*[]domainMenuGroup.MenuGroup, error) {
 	return arrayToDomainMapper(&apis), nil
 }
 
-func (r *Repository) GetByRoleId(menuIds []int, roleId int64) (*[]domainMenuGroup.MenuGroup, error) {
+func (r *Repository) GetByRoleId(ctx context.Context, menuIds []int, roleId int64) (*[]domainMenuGroup.MenuGroup, error) {
 	var apis []SysBaseMenuGroups
-	db := r.DB.Where("status = ?", constants.StatusEnabled)
+	db := r.DB.WithContext(ctx).Where("status = ?", constants.StatusEnabled)
 	if roleId != 0 {
 		db = db.Preload("MenuItems", "id in (?)", menuIds)
 	} else {
@@ -99,10 +100,10 @@ func (r *Repository) GetByRoleId(menuIds []int, roleId int64) (*[]domainMenuGrou
 	return arrayToDomainMapper(&apis), nil
 }
 
-func (r *Repository) Create(apiDomain *domainMenuGroup.MenuGroup) (*domainMenuGroup.MenuGroup, error) {
+func (r *Repository) Create(ctx context.Context, apiDomain *domainMenuGroup.MenuGroup) (*domainMenuGroup.MenuGroup, error) {
 	r.Logger.Info("Creating new api", zap.String("name", apiDomain.Name))
 	apiRepository := fromDomainMapper(apiDomain)
-	txDb := r.DB.Create(apiRepository)
+	txDb := r.DB.WithContext(ctx).Create(apiRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating api", zap.Error(err), zap.String("Name", apiDomain.Name))
@@ -124,9 +125,9 @@ func (r *Repository) Create(apiDomain *domainMenuGroup.MenuGroup) (*domainMenuGr
 	return apiRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainMenuGroup.MenuGroup, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainMenuGroup.MenuGroup, error) {
 	var api SysBaseMenuGroups
-	err := r.DB.Where("id = ?", id).First(&api).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&api).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("MenuGroup not found", zap.Int("id", id))
@@ -141,11 +142,11 @@ func (r *Repository) GetByID(id int) (*domainMenuGroup.MenuGroup, error) {
 	return api.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainMenuGroup.MenuGroup, error) {
+func (r *Repository) Update(ctx context.Context, id int, apiMap map[string]interface{}) (*domainMenuGroup.MenuGroup, error) {
 	var apiObj SysBaseMenuGroups
 	apiObj.ID = id
 	delete(apiMap, "updated_at")
-	err := r.DB.Model(&apiObj).Updates(apiMap).Error
+	err := r.DB.WithContext(ctx).Model(&apiObj).Updates(apiMap).Error
 	if err != nil {
 		r.Logger.Error("Error updating api", zap.Error(err), zap.Int("id", id))
 		byteErr, _ := json.Marshal(err)
@@ -161,7 +162,7 @@ func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainMenuG
 			return &domainMenuGroup.MenuGroup{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&apiObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&apiObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated api", zap.Error(err), zap.Int("id", id))
 		return &domainMenuGroup.MenuGroup{}, err
 	}
@@ -169,8 +170,8 @@ func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainMenuG
 	return apiObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(ids []int) error {
-	tx := r.DB.Where("id IN ?", ids).Delete(&SysBaseMenuGroups{})
+func (r *Repository) Delete(ctx context.Context, ids []int) error {
+	tx := r.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&SysBaseMenuGroups{})
 
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting api", zap.Error(tx.Error), zap.String("ids", fmt.Sprintf("%v", ids)))
@@ -184,8 +185,8 @@ func (r *Repository) Delete(ids []int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainMenuGroup.MenuGroup], error) {
-	query := r.DB.Model(&SysBaseMenuGroups{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainMenuGroup.MenuGroup], error) {
+	query := r.DB.WithContext(ctx).Model(&SysBaseMenuGroups{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -272,7 +273,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsMenuGroupMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -280,7 +281,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysBaseMenuGroups{}).
+	if err := r.DB.WithContext(ctx).Model(&SysBaseMenuGroups{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -329,9 +330,9 @@ func arrayToDomainMapper(apis *[]SysBaseMenuGroups) *[]domainMenuGroup.MenuGroup
 	return &apisDomain
 }
 
-func (r *Repository) GetOneByMap(apiMap map[string]interface{}) (*domainMenuGroup.MenuGroup, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, apiMap map[string]interface{}) (*domainMenuGroup.MenuGroup, error) {
 	var apiRepository SysBaseMenuGroups
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range apiMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
@@ -1,6 +1,7 @@
 package base_menu_parameter
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -43,15 +44,15 @@ var ColumnsMenuParameterMapping = map[string]string{
 
 // MenuParameterRepositoryInterface defines the interface for menu repository operations
 type MenuParameterRepositoryInterface interface {
-	GetAll(menuID int64) (*[]domainMenuParameter.MenuParameter, error)
-	Create(menuDomain *domainMenuParameter.MenuParameter) (*domainMenuParameter.MenuParameter, error)
-	GetByID(id int) (*domainMenuParameter.MenuParameter, error)
-	Update(id int, menuMap map[string]interface{}) (*domainMenuParameter.MenuParameter, error)
-	Delete(id int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainMenuParameter.MenuParameter], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(menuMap map[string]interface{}) (*domainMenuParameter.MenuParameter, error)
-	GetByIDs(ids []int) (*[]domainMenuParameter.MenuParameter, error)
+	GetAll(ctx context.Context, menuID int64) (*[]domainMenuParameter.MenuParameter, error)
+	Create(ctx context.Context, menuDomain *domainMenuParameter.MenuParameter) (*domainMenuParameter.MenuParameter, error)
+	GetByID(ctx context.Context, id int) (*domainMenuParameter.MenuParameter, error)
+	Update(ctx context.Context, id int, menuMap map[string]interface{}) (*domainMenuParameter.MenuParameter, error)
+	Delete(ctx context.Context, id int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainMenuParameter.MenuParameter], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, menuMap map[string]interface{}) (*domainMenuParameter.MenuParameter, error)
+	GetByIDs(ctx context.Context, ids []int) (*[]domainMenuParameter.MenuParameter, error)
 }
 
 type Repository struct {
@@ -63,9 +64,9 @@ func NewMenuParameterRepository(db *gorm.DB, loggerInstance *logger.Logger) Menu
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll(menuID int64) (*[]domainMenuParameter.MenuParameter, error) {
+func (r *Repository) GetAll(ctx context.Context, menuID int64) (*[]domainMenuParameter.MenuParameter, error) {
 	var menus []SysBaseMenuParameter
-	tx := r.DB
+	tx := r.DB.WithContext(ctx)
 	if menuID != 0 {
 		tx = tx.Where("sys_base_menu_id = ?", menuID)
 	}
@@ -77,10 +78,10 @@ func (r *Repository) GetAll(menuID int64) (*[]domainMenuParameter.MenuParameter,
 	return ArrayToDomainMapper(&menus), nil
 }
 
-func (r *Repository) Create(menuDomain *domainMenuParameter.MenuParameter) (*domainMenuParameter.MenuParameter, error) {
+func (r *Repository) Create(ctx context.Context, menuDomain *domainMenuParameter.MenuParameter) (*domainMenuParameter.MenuParameter, error) {
 	r.Logger.Info("Creating new menu", zap.String("Key", menuDomain.Key))
 	menuRepository := fromDomainMapper(menuDomain)
-	txDb := r.DB.Create(menuRepository)
+	txDb := r.DB.WithContext(ctx).Create(menuRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating menu", zap.Error(err), zap.String("Key", menuDomain.Key))
@@ -102,9 +103,9 @@ func (r *Repository) Create(menuDomain *domainMenuParameter.MenuParameter) (*dom
 	return menuRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainMenuParameter.MenuParameter, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainMenuParameter.MenuParameter, error) {
 	var menu SysBaseMenuParameter
-	err := r.DB.Where("id = ?", id).First(&menu).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&menu).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("MenuParameter not found", zap.Int("id", id))
@@ -119,11 +120,11 @@ func (r *Repository) GetByID(id int) (*domainMenuParameter.MenuParameter, error)
 	return menu.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, menuMap map[string]interface{}) (*domainMenuParameter.MenuParameter, error) {
+func (r *Repository) Update(ctx context.Context, id int, menuMap map[string]interface{}) (*domainMenuParameter.MenuParameter, error) {
 	var menuObj SysBaseMenuParameter
 	menuObj.ID = id
 	delete(menuMap, "updated_at")
-	err := r.DB.Model(&menuObj).
+	err := r.DB.WithContext(ctx).Model(&menuObj).
 		Select("key", "type", "value").
 		Updates(menuMap).Error
 	if err != nil {
@@ -141,7 +142,7 @@ func (r *Repository) Update(id int, menuMap map[string]interface{}) (*domainMenu
 			return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&menuObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&menuObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated menu", zap.Error(err), zap.Int("id", id))
 		return nil, err
 	}
@@ -149,8 +150,8 @@ func (r *Repository) Update(id int, menuMap map[string]interface{}) (*domainMenu
 	return menuObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(id int) error {
-	tx := r.DB.Delete(&SysBaseMenuParameter{}, id)
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	tx := r.DB.WithContext(ctx).Delete(&SysBaseMenuParameter{}, id)
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting menu", zap.Error(tx.Error), zap.Int("id", id))
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -163,8 +164,8 @@ func (r *Repository) Delete(id int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainMenuParameter.MenuParameter], error) {
-	query := r.DB.Model(&SysBaseMenuParameter{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainMenuParameter.MenuParameter], error) {
+	query := r.DB.WithContext(ctx).Model(&SysBaseMenuParameter{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -251,7 +252,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsMenuParameterMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -259,7 +260,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysBaseMenuParameter{}).
+	if err := r.DB.WithContext(ctx).Model(&SysBaseMenuParameter{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -275,9 +276,9 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	return &coincidences, nil
 }
 
-func (r *Repository) GetByIDs(ids []int) (*[]domainMenuParameter.MenuParameter, error) {
+func (r *Repository) GetByIDs(ctx context.Context, ids []int) (*[]domainMenuParameter.MenuParameter, error) {
 	var menus []SysBaseMenuParameter
-	if err := r.DB.Where("id in (?)", ids).Find(&menus).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id in (?)", ids).Find(&menus).Error; err != nil {
 		r.Logger.Error("Error getting all menus", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -315,9 +316,9 @@ func ArrayToDomainMapper(menus *[]SysBaseMenuParameter) *[]domainMenuParameter.M
 	return &menusDomain
 }
 
-func (r *Repository) GetOneByMap(menuMap map[string]interface{}) (*domainMenuParameter.MenuParameter, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, menuMap map[string]interface{}) (*domainMenuParameter.MenuParameter, error) {
 	var menuRepository SysBaseMenuParameter
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range menuMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
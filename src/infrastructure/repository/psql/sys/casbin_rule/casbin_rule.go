@@ -1,6 +1,7 @@
 package casbin_rule
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -35,8 +36,8 @@ var ColumnsRoleMapping = map[string]string{
 }
 
 type ICasbinRuleRepository interface {
-	Insert(roleId int, UpdateMap map[string]any) error
-	GetByRoleId(roleId int) ([]string, error)
+	Insert(ctx context.Context, roleId int, UpdateMap map[string]any) error
+	GetByRoleId(ctx context.Context, roleId int) ([]string, error)
 }
 
 type Repository struct {
@@ -45,9 +46,9 @@ type Repository struct {
 }
 
 // GetByRoleId implements ISysRoleMenuRepository.
-func (r *Repository) GetByRoleId(roleId int) ([]string, error) {
+func (r *Repository) GetByRoleId(ctx context.Context, roleId int) ([]string, error) {
 	var casbinRules []CasbinRule
-	err := r.DB.Where(&CasbinRule{V0: strconv.Itoa(roleId)}).Find(&casbinRules).Error
+	err := r.DB.WithContext(ctx).Where(&CasbinRule{V0: strconv.Itoa(roleId)}).Find(&casbinRules).Error
 	if err != nil {
 		r.Logger.Error("Error getting all casbin_rule", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -60,7 +61,7 @@ func (r *Repository) GetByRoleId(roleId int) ([]string, error) {
 }
 
 // Insert implements ISysRoleMenuRepository.
-func (r *Repository) Insert(roleId int, UpdateMap map[string]any) error {
+func (r *Repository) Insert(ctx context.Context, roleId int, UpdateMap map[string]any) error {
 	apiPathsInterface, ok := UpdateMap["apiPaths"]
 	if !ok {
 		r.Logger.Error("apiPaths not found in update map")
@@ -89,11 +90,11 @@ func (r *Repository) Insert(roleId int, UpdateMap map[string]any) error {
 		}
 		casbinMenus = append(casbinMenus, roleMenu)
 	}
-	if err := r.DB.Where(&CasbinRule{V0: strconv.Itoa(roleId)}).
+	if err := r.DB.WithContext(ctx).Where(&CasbinRule{V0: strconv.Itoa(roleId)}).
 		Delete(&CasbinRule{}).Error; err != nil {
 		return err
 	}
-	if err := r.DB.Model(&CasbinRule{}).Create(&casbinMenus).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&CasbinRule{}).Create(&casbinMenus).Error; err != nil {
 		return err
 	}
 	return nil
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,8 +10,10 @@ import (
 	"github.com/gbrayhan/microservices-go/src/domain"
 
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
 	domainConfig "github.com/gbrayhan/microservices-go/src/domain/sys/config"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	auditLogRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/utils"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -47,16 +50,16 @@ var ColumnsConfigMapping = map[string]string{
 
 // ConfigRepositoryInterface defines the interface for config repository operations
 type ConfigRepositoryInterface interface {
-	GetAll() (*[]domainConfig.Config, error)
-	Create(configDomain *domainConfig.Config) (*domainConfig.Config, error)
-	GetByID(id int) (*domainConfig.Config, error)
-	Update(configDomain *domainConfig.Config) (*domainConfig.Config, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainConfig.Config], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(configMap map[string]interface{}) (*domainConfig.Config, error)
-	GetConfigByModule(module string) (*[]domainConfig.Config, error)
-	UpdateByModule(module, configKey, configValue string) error
+	GetAll(ctx context.Context) (*[]domainConfig.Config, error)
+	Create(ctx context.Context, configDomain *domainConfig.Config) (*domainConfig.Config, error)
+	GetByID(ctx context.Context, id int) (*domainConfig.Config, error)
+	Update(ctx context.Context, configDomain *domainConfig.Config, actorId int64) (*domainConfig.Config, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainConfig.Config], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, configMap map[string]interface{}) (*domainConfig.Config, error)
+	GetConfigByModule(ctx context.Context, module string) (*[]domainConfig.Config, error)
+	UpdateByModule(ctx context.Context, module, configKey, configValue string, actorId int64) error
 }
 
 type Repository struct {
@@ -68,9 +71,9 @@ func NewConfigRepository(db *gorm.DB, loggerInstance *logger.Logger) ConfigRepos
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainConfig.Config, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainConfig.Config, error) {
 	var configs []SysConfig
-	if err := r.DB.Order("sort ASC").Find(&configs).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Order("sort ASC").Find(&configs).Error; err != nil {
 		r.Logger.Error("Error getting all configs", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -78,10 +81,10 @@ func (r *Repository) GetAll() (*[]domainConfig.Config, error) {
 	return arrayToDomainMapper(&configs), nil
 }
 
-func (r *Repository) Create(configDomain *domainConfig.Config) (*domainConfig.Config, error) {
+func (r *Repository) Create(ctx context.Context, configDomain *domainConfig.Config) (*domainConfig.Config, error) {
 	r.Logger.Info("Creating new config", zap.String("ConfigKey", configDomain.ConfigKey))
 	configRepository := fromDomainMapper(configDomain)
-	txDb := r.DB.Create(configRepository)
+	txDb := r.DB.WithContext(ctx).Create(configRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating config", zap.Error(err), zap.String("ConfigKey", configDomain.ConfigKey))
@@ -103,9 +106,9 @@ func (r *Repository) Create(configDomain *domainConfig.Config) (*domainConfig.Co
 	return configRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainConfig.Config, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainConfig.Config, error) {
 	var config SysConfig
-	err := r.DB.Where("id = ?", id).First(&config).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&config).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("Config not found", zap.Int("id", id))
@@ -120,12 +123,18 @@ func (r *Repository) GetByID(id int) (*domainConfig.Config, error) {
 	return config.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(configDomain *domainConfig.Config) (*domainConfig.Config, error) {
+func (r *Repository) Update(ctx context.Context, configDomain *domainConfig.Config, actorId int64) (*domainConfig.Config, error) {
 	var configObj SysConfig
 	configObj.ID = configDomain.ID
-	err := r.DB.Model(&configObj).Updates(fromDomainMapper(configDomain)).Error
+	var before SysConfig
+	err := auditLogRepo.UpdateAudited(r.DB.WithContext(ctx), domainAuditLog.EntityConfig, configDomain.ID, actorId, &before, &configObj, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&configObj).Updates(fromDomainMapper(configDomain))
+	})
 	if err != nil {
 		r.Logger.Error("Error updating config", zap.Error(err), zap.Int64("id", configDomain.ID))
+		if err == gorm.ErrRecordNotFound {
+			return &domainConfig.Config{}, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
 		byteErr, _ := json.Marshal(err)
 		var newError domainErrors.GormErr
 		errUnmarshal := json.Unmarshal(byteErr, &newError)
@@ -139,16 +148,12 @@ func (r *Repository) Update(configDomain *domainConfig.Config) (*domainConfig.Co
 			return &domainConfig.Config{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", configDomain.ID).First(&configObj).Error; err != nil {
-		r.Logger.Error("Error retrieving updated config", zap.Error(err), zap.Int64("id", configDomain.ID))
-		return &domainConfig.Config{}, err
-	}
 	r.Logger.Info("Successfully updated config", zap.Int64("id", configDomain.ID))
 	return configObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(ids []int) error {
-	tx := r.DB.Where("id IN ?", ids).Delete(&SysConfig{})
+func (r *Repository) Delete(ctx context.Context, ids []int) error {
+	tx := r.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&SysConfig{})
 
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting config", zap.Error(tx.Error), zap.String("ids", fmt.Sprintf("%v", ids)))
@@ -162,8 +167,8 @@ func (r *Repository) Delete(ids []int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainConfig.Config], error) {
-	query := r.DB.Model(&SysConfig{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainConfig.Config], error) {
+	query := r.DB.WithContext(ctx).Model(&SysConfig{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -250,7 +255,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsConfigMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -258,7 +263,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysConfig{}).
+	if err := r.DB.WithContext(ctx).Model(&SysConfig{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -308,9 +313,9 @@ func arrayToDomainMapper(configs *[]SysConfig) *[]domainConfig.Config {
 	return &configsDomain
 }
 
-func (r *Repository) GetOneByMap(configMap map[string]interface{}) (*domainConfig.Config, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, configMap map[string]interface{}) (*domainConfig.Config, error) {
 	var configRepository SysConfig
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range configMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
@@ -322,26 +327,40 @@ func (r *Repository) GetOneByMap(configMap map[string]interface{}) (*domainConfi
 	return configRepository.toDomainMapper(), nil
 }
 
-func (r *Repository) GetConfigByModule(module string) (*[]domainConfig.Config, error) {
+func (r *Repository) GetConfigByModule(ctx context.Context, module string) (*[]domainConfig.Config, error) {
 	var configs []SysConfig
-	if err := r.DB.Where("module = ?", module).Find(&configs).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("module = ?", module).Find(&configs).Error; err != nil {
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
 	return arrayToDomainMapper(&configs), nil
 }
 
-func (r *Repository) UpdateByModule(module, configKey, configValue string) error {
+func (r *Repository) UpdateByModule(ctx context.Context, module, configKey, configValue string, actorId int64) error {
 	envType := os.Getenv("ENV_TYPE")
-	err := r.DB.
-		Model(&SysConfig{}).
-		Where("module = ? and config_key = ? and env_type = ?", module, configKey, envType).
-		Update("config_value", configValue).Error
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []int64
+		if err := tx.Model(&SysConfig{}).
+			Where("module = ? and config_key = ? and env_type = ?", module, configKey, envType).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			var before, after SysConfig
+			after.ID = id
+			if err := auditLogRepo.UpdateAudited(tx, domainAuditLog.EntityConfig, id, actorId, &before, &after, func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&after).Update("config_value", configValue)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		r.Logger.Error("Error updating config", zap.Error(err), zap.String("configKey", configKey))
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
-
 	}
-	fmt.Println(module, configKey, envType)
+	r.Logger.Debug("Config updated by module", zap.String("module", module), zap.String("configKey", configKey),
+		zap.String("envType", envType))
 	r.Logger.Info("Successfully updated config")
 	return nil
 }
@@ -1,6 +1,7 @@
 package dictionary
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -49,16 +50,16 @@ var ColumnsDictionaryMapping = map[string]string{
 
 // DictionaryRepositoryInterface defines the interface for dictionary repository operations
 type DictionaryRepositoryInterface interface {
-	GetAll() (*[]domainDictionary.Dictionary, error)
-	Create(dictionaryDomain *domainDictionary.Dictionary) (*domainDictionary.Dictionary, error)
-	GetByID(id int) (*domainDictionary.Dictionary, error)
-	Update(id int, dictionaryMap map[string]interface{}) (*domainDictionary.Dictionary, error)
-	Delete(id int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainDictionary.Dictionary], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(dictionaryMap map[string]interface{}) (*domainDictionary.Dictionary, error)
-
-	GetByType(typeText string) (*domainDictionary.Dictionary, error)
+	GetAll(ctx context.Context) (*[]domainDictionary.Dictionary, error)
+	Create(ctx context.Context, dictionaryDomain *domainDictionary.Dictionary) (*domainDictionary.Dictionary, error)
+	GetByID(ctx context.Context, id int) (*domainDictionary.Dictionary, error)
+	Update(ctx context.Context, id int, dictionaryMap map[string]interface{}) (*domainDictionary.Dictionary, error)
+	Delete(ctx context.Context, id int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainDictionary.Dictionary], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, dictionaryMap map[string]interface{}) (*domainDictionary.Dictionary, error)
+
+	GetByType(ctx context.Context, typeText string) (*domainDictionary.Dictionary, error)
 }
 
 type Repository struct {
@@ -70,9 +71,9 @@ func NewDictionaryRepository(db *gorm.DB, loggerInstance *logger.Logger) Diction
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainDictionary.Dictionary, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainDictionary.Dictionary, error) {
 	var dictionaries []SysDictionary
-	if err := r.DB.Find(&dictionaries).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&dictionaries).Error; err != nil {
 		r.Logger.Error("Error getting all dictionaries", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -80,10 +81,10 @@ func (r *Repository) GetAll() (*[]domainDictionary.Dictionary, error) {
 	return ArrayToDomainMapper(&dictionaries), nil
 }
 
-func (r *Repository) Create(dictionaryDomain *domainDictionary.Dictionary) (*domainDictionary.Dictionary, error) {
+func (r *Repository) Create(ctx context.Context, dictionaryDomain *domainDictionary.Dictionary) (*domainDictionary.Dictionary, error) {
 	r.Logger.Info("Creating new dictionary", zap.String("Name", dictionaryDomain.Name))
 	dictionaryRepository := fromDomainMapper(dictionaryDomain)
-	txDb := r.DB.Create(dictionaryRepository)
+	txDb := r.DB.WithContext(ctx).Create(dictionaryRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating dictionary", zap.Error(err), zap.String("Name", dictionaryDomain.Name))
@@ -105,9 +106,9 @@ func (r *Repository) Create(dictionaryDomain *domainDictionary.Dictionary) (*dom
 	return dictionaryRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainDictionary.Dictionary, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainDictionary.Dictionary, error) {
 	var dictionary SysDictionary
-	err := r.DB.Where("id = ?", id).First(&dictionary).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&dictionary).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("Dictionary not found", zap.Int("id", id))
@@ -122,11 +123,11 @@ func (r *Repository) GetByID(id int) (*domainDictionary.Dictionary, error) {
 	return dictionary.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, dictionaryMap map[string]interface{}) (*domainDictionary.Dictionary, error) {
+func (r *Repository) Update(ctx context.Context, id int, dictionaryMap map[string]interface{}) (*domainDictionary.Dictionary, error) {
 	var dictionaryObj SysDictionary
 	dictionaryObj.ID = id
 	delete(dictionaryMap, "updated_at")
-	err := r.DB.Model(&dictionaryObj).Updates(dictionaryMap).Error
+	err := r.DB.WithContext(ctx).Model(&dictionaryObj).Updates(dictionaryMap).Error
 	if err != nil {
 		r.Logger.Error("Error updating dictionary", zap.Error(err), zap.Int("id", id))
 		byteErr, _ := json.Marshal(err)
@@ -142,7 +143,7 @@ func (r *Repository) Update(id int, dictionaryMap map[string]interface{}) (*doma
 			return &domainDictionary.Dictionary{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&dictionaryObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&dictionaryObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated dictionary", zap.Error(err), zap.Int("id", id))
 		return &domainDictionary.Dictionary{}, err
 	}
@@ -150,8 +151,8 @@ func (r *Repository) Update(id int, dictionaryMap map[string]interface{}) (*doma
 	return dictionaryObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(id int) error {
-	tx := r.DB.Delete(&SysDictionary{}, id)
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	tx := r.DB.WithContext(ctx).Delete(&SysDictionary{}, id)
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting dictionary", zap.Error(tx.Error), zap.Int("id", id))
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -164,8 +165,8 @@ func (r *Repository) Delete(id int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainDictionary.Dictionary], error) {
-	query := r.DB.Model(&SysDictionary{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainDictionary.Dictionary], error) {
+	query := r.DB.WithContext(ctx).Model(&SysDictionary{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -252,7 +253,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsDictionaryMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -260,7 +261,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysDictionary{}).
+	if err := r.DB.WithContext(ctx).Model(&SysDictionary{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -311,9 +312,9 @@ func ArrayToDomainMapper(dictionaries *[]SysDictionary) *[]domainDictionary.Dict
 	return &dictionariesDomain
 }
 
-func (r *Repository) GetOneByMap(dictionaryMap map[string]interface{}) (*domainDictionary.Dictionary, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, dictionaryMap map[string]interface{}) (*domainDictionary.Dictionary, error) {
 	var dictionaryRepository SysDictionary
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range dictionaryMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
@@ -325,9 +326,9 @@ func (r *Repository) GetOneByMap(dictionaryMap map[string]interface{}) (*domainD
 	return dictionaryRepository.toDomainMapper(), nil
 }
 
-func (r *Repository) GetByType(typeText string) (*domainDictionary.Dictionary, error) {
+func (r *Repository) GetByType(ctx context.Context, typeText string) (*domainDictionary.Dictionary, error) {
 	var dictionaries SysDictionary
-	if err := r.DB.
+	if err := r.DB.WithContext(ctx).
 		Preload("Details", func(db *gorm.DB) *gorm.DB {
 			return db.Where("status = ?", constants.StatusEnabled)
 		}).
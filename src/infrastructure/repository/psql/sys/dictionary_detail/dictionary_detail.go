@@ -1,6 +1,7 @@
 package dictionary_detail
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -45,14 +46,14 @@ var ColumnsDictionaryMapping = map[string]string{
 
 // DictionaryRepositoryInterface defines the interface for dictionary repository operations
 type DictionaryRepositoryInterface interface {
-	GetAll() (*[]domainDictionary.DictionaryDetail, error)
-	Create(dictionaryDomain *domainDictionary.DictionaryDetail) (*domainDictionary.DictionaryDetail, error)
-	GetByID(id int) (*domainDictionary.DictionaryDetail, error)
-	Update(id int, dictionaryMap map[string]interface{}) (*domainDictionary.DictionaryDetail, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainDictionary.DictionaryDetail], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(dictionaryMap map[string]interface{}) (*domainDictionary.DictionaryDetail, error)
+	GetAll(ctx context.Context) (*[]domainDictionary.DictionaryDetail, error)
+	Create(ctx context.Context, dictionaryDomain *domainDictionary.DictionaryDetail) (*domainDictionary.DictionaryDetail, error)
+	GetByID(ctx context.Context, id int) (*domainDictionary.DictionaryDetail, error)
+	Update(ctx context.Context, id int, dictionaryMap map[string]interface{}) (*domainDictionary.DictionaryDetail, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainDictionary.DictionaryDetail], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, dictionaryMap map[string]interface{}) (*domainDictionary.DictionaryDetail, error)
 }
 
 type Repository struct {
@@ -64,9 +65,9 @@ func NewDictionaryRepository(db *gorm.DB, loggerInstance *logger.Logger) Diction
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainDictionary.DictionaryDetail, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainDictionary.DictionaryDetail, error) {
 	var dictionarys []SysDictionaryDetail
-	if err := r.DB.Find(&dictionarys).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&dictionarys).Error; err != nil {
 		r.Logger.Error("Error getting all dictionarys", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -74,10 +75,10 @@ func (r *Repository) GetAll() (*[]domainDictionary.DictionaryDetail, error) {
 	return ArrayToDomainMapper(&dictionarys), nil
 }
 
-func (r *Repository) Create(dictionaryDomain *domainDictionary.DictionaryDetail) (*domainDictionary.DictionaryDetail, error) {
+func (r *Repository) Create(ctx context.Context, dictionaryDomain *domainDictionary.DictionaryDetail) (*domainDictionary.DictionaryDetail, error) {
 	r.Logger.Info("Creating new dictionary", zap.String("label", dictionaryDomain.Label))
 	dictionaryRepository := fromDomainMapper(dictionaryDomain)
-	txDb := r.DB.Create(dictionaryRepository)
+	txDb := r.DB.WithContext(ctx).Create(dictionaryRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating dictionary", zap.Error(err), zap.String("Label", dictionaryDomain.Label))
@@ -99,9 +100,9 @@ func (r *Repository) Create(dictionaryDomain *domainDictionary.DictionaryDetail)
 	return dictionaryRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainDictionary.DictionaryDetail, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainDictionary.DictionaryDetail, error) {
 	var dictionary SysDictionaryDetail
-	err := r.DB.Where("id = ?", id).First(&dictionary).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&dictionary).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("Dictionary not found", zap.Int("id", id))
@@ -116,11 +117,11 @@ func (r *Repository) GetByID(id int) (*domainDictionary.DictionaryDetail, error)
 	return dictionary.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, dictionaryMap map[string]interface{}) (*domainDictionary.DictionaryDetail, error) {
+func (r *Repository) Update(ctx context.Context, id int, dictionaryMap map[string]interface{}) (*domainDictionary.DictionaryDetail, error) {
 	var dictionaryObj SysDictionaryDetail
 	dictionaryObj.ID = id
 	delete(dictionaryMap, "updated_at")
-	err := r.DB.Model(&dictionaryObj).Updates(dictionaryMap).Error
+	err := r.DB.WithContext(ctx).Model(&dictionaryObj).Updates(dictionaryMap).Error
 	if err != nil {
 		r.Logger.Error("Error updating dictionary", zap.Error(err), zap.Int("id", id))
 		byteErr, _ := json.Marshal(err)
@@ -136,7 +137,7 @@ func (r *Repository) Update(id int, dictionaryMap map[string]interface{}) (*doma
 			return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&dictionaryObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&dictionaryObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated dictionary", zap.Error(err), zap.Int("id", id))
 		return nil, err
 	}
@@ -144,8 +145,8 @@ func (r *Repository) Update(id int, dictionaryMap map[string]interface{}) (*doma
 	return dictionaryObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(ids []int) error {
-	tx := r.DB.Where("id IN ?", ids).Delete(&SysDictionaryDetail{})
+func (r *Repository) Delete(ctx context.Context, ids []int) error {
+	tx := r.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&SysDictionaryDetail{})
 
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting dictionary details", zap.Error(tx.Error), zap.String("ids", fmt.Sprintf("%v", ids)))
@@ -159,8 +160,8 @@ func (r *Repository) Delete(ids []int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainDictionary.DictionaryDetail], error) {
-	query := r.DB.Model(&SysDictionaryDetail{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainDictionary.DictionaryDetail], error) {
+	query := r.DB.WithContext(ctx).Model(&SysDictionaryDetail{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -247,7 +248,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsDictionaryMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -255,7 +256,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysDictionaryDetail{}).
+	if err := r.DB.WithContext(ctx).Model(&SysDictionaryDetail{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -309,9 +310,9 @@ func ArrayToDomainMapper(dictionarys *[]SysDictionaryDetail) *[]domainDictionary
 	return &dictionarysDomain
 }
 
-func (r *Repository) GetOneByMap(dictionaryMap map[string]interface{}) (*domainDictionary.DictionaryDetail, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, dictionaryMap map[string]interface{}) (*domainDictionary.DictionaryDetail, error) {
 	var dictionaryRepository SysDictionaryDetail
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range dictionaryMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
@@ -0,0 +1,171 @@
+package files
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SysFileAttachment links a sys_files row to an entity of any type, the entity isn't a foreign
+// key and its attachments are removed by whoever deletes it
+type SysFileAttachment struct {
+	ID         int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	EntityType string    `gorm:"column:entity_type;size:50;not null;uniqueIndex:idx_sys_file_attachments_file,priority:1"`
+	EntityID   int64     `gorm:"column:entity_id;not null;uniqueIndex:idx_sys_file_attachments_file,priority:2"`
+	FileID     int64     `gorm:"column:file_id;not null;index;uniqueIndex:idx_sys_file_attachments_file,priority:3"`
+	Sort       int       `gorm:"column:sort;not null;default:0"`
+	Label      string    `gorm:"column:label;size:191"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (SysFileAttachment) TableName() string {
+	return "sys_file_attachments"
+}
+
+func (m *SysFileAttachment) toDomainMapper() *filesDomain.FileAttachment {
+	return &filesDomain.FileAttachment{
+		ID:         m.ID,
+		EntityType: m.EntityType,
+		EntityID:   m.EntityID,
+		FileID:     m.FileID,
+		Sort:       m.Sort,
+		Label:      m.Label,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+// AttachFile adds the attachment after the others of its entity, or changes the label of the
+// same file already attached
+func (r *Repository) AttachFile(ctx context.Context, attachment *filesDomain.FileAttachment) (*filesDomain.FileAttachment, error) {
+	var saved SysFileAttachment
+	err := r.DB.WithContext(ctx).Raw(`
+INSERT INTO sys_file_attachments (entity_type, entity_id, file_id, sort, label, created_at, updated_at)
+SELECT ?::varchar, ?::bigint, ?::bigint, COALESCE(MAX(sort), 0) + 1, ?::varchar, now(), now()
+FROM sys_file_attachments WHERE entity_type = ? AND entity_id = ?
+ON CONFLICT (entity_type, entity_id, file_id) DO UPDATE
+SET label = EXCLUDED.label, updated_at = now()
+RETURNING *`, attachment.EntityType, attachment.EntityID, attachment.FileID, attachment.Label,
+		attachment.EntityType, attachment.EntityID).Scan(&saved).Error
+	if err != nil {
+		r.Logger.Error("Error attaching file", zap.Error(err), zap.String("entityType", attachment.EntityType),
+			zap.Int64("entityId", attachment.EntityID), zap.Int64("fileId", attachment.FileID))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return saved.toDomainMapper(), nil
+}
+
+// DetachFile removes one attachment, it returns false when the file wasn't attached
+func (r *Repository) DetachFile(ctx context.Context, entityType string, entityID int64, fileID int64) (bool, error) {
+	result := r.DB.WithContext(ctx).Exec(`DELETE FROM sys_file_attachments WHERE entity_type = ? AND entity_id = ? AND file_id = ?`,
+		entityType, entityID, fileID)
+	if result.Error != nil {
+		r.Logger.Error("Error detaching file", zap.Error(result.Error), zap.String("entityType", entityType),
+			zap.Int64("entityId", entityID), zap.Int64("fileId", fileID))
+		return false, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *Repository) GetAttachments(ctx context.Context, entityType string, entityID int64) (*[]filesDomain.FileAttachment, error) {
+	var attachments []SysFileAttachment
+	err := r.DB.WithContext(ctx).Where("entity_type = ? AND entity_id = ?", entityType, entityID).Order("sort, id").Find(&attachments).Error
+	if err != nil {
+		r.Logger.Error("Error getting file attachments", zap.Error(err), zap.String("entityType", entityType),
+			zap.Int64("entityId", entityID))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	result := make([]filesDomain.FileAttachment, len(attachments))
+	for i := range attachments {
+		result[i] = *attachments[i].toDomainMapper()
+	}
+	return &result, nil
+}
+
+// SortAttachments numbers the attachments of an entity in the order of fileIDs. It fails with
+// ErrAttachmentOrder unless fileIDs lists every attached file once.
+func (r *Repository) SortAttachments(ctx context.Context, entityType string, entityID int64, fileIDs []int64) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var attached []int64
+		if err := tx.Raw(`SELECT file_id FROM sys_file_attachments WHERE entity_type = ? AND entity_id = ? FOR UPDATE`,
+			entityType, entityID).Scan(&attached).Error; err != nil {
+			return err
+		}
+		ordered := slices.Clone(fileIDs)
+		slices.Sort(attached)
+		slices.Sort(ordered)
+		if !slices.Equal(attached, ordered) {
+			return filesDomain.ErrAttachmentOrder
+		}
+		for i, fileID := range fileIDs {
+			if err := tx.Exec(`UPDATE sys_file_attachments SET sort = ?, updated_at = now()
+WHERE entity_type = ? AND entity_id = ? AND file_id = ?`, i+1, entityType, entityID, fileID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == filesDomain.ErrAttachmentOrder {
+		return domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	if err != nil {
+		r.Logger.Error("Error sorting file attachments", zap.Error(err), zap.String("entityType", entityType),
+			zap.Int64("entityId", entityID))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return nil
+}
+
+// DetachEntity removes every attachment of an entity
+func (r *Repository) DetachEntity(ctx context.Context, entityType string, entityID int64) (int64, error) {
+	result := r.DB.WithContext(ctx).Exec(`DELETE FROM sys_file_attachments WHERE entity_type = ? AND entity_id = ?`, entityType, entityID)
+	if result.Error != nil {
+		r.Logger.Error("Error detaching entity files", zap.Error(result.Error), zap.String("entityType", entityType),
+			zap.Int64("entityId", entityID))
+		return 0, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return result.RowsAffected, nil
+}
+
+// DetachDeletedEntities removes the attachments of the entities of a type that are no longer in
+// table, or that are soft deleted there
+func (r *Repository) DetachDeletedEntities(ctx context.Context, entityType string, table string, softDelete bool) (int64, error) {
+	alive := `e.id = a.entity_id`
+	if softDelete {
+		alive += ` AND e.deleted_at IS NULL`
+	}
+	result := r.DB.WithContext(ctx).Exec(`DELETE FROM sys_file_attachments a WHERE a.entity_type = ?
+AND NOT EXISTS (SELECT 1 FROM `+quoteTable(table)+` e WHERE `+alive+`)`, entityType)
+	if result.Error != nil {
+		r.Logger.Error("Error detaching deleted entities", zap.Error(result.Error), zap.String("entityType", entityType))
+		return 0, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return result.RowsAffected, nil
+}
+
+// EntityExists tells whether table has the row id, not soft deleted
+func (r *Repository) EntityExists(ctx context.Context, table string, softDelete bool, id int64) (bool, error) {
+	condition := `id = ?`
+	if softDelete {
+		condition += ` AND deleted_at IS NULL`
+	}
+	var exists bool
+	err := r.DB.WithContext(ctx).Raw(`SELECT EXISTS (SELECT 1 FROM `+quoteTable(table)+` WHERE `+condition+`)`, id).Scan(&exists).Error
+	if err != nil {
+		r.Logger.Error("Error checking entity", zap.Error(err), zap.String("table", table), zap.Int64("id", id))
+		return false, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return exists, nil
+}
+
+// quoteTable quotes a table name configured in the code
+func quoteTable(table string) string {
+	return `"` + strings.ReplaceAll(table, `"`, `""`) + `"`
+}
@@ -0,0 +1,128 @@
+package files
+
+import (
+	"context"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SysFileBlob is a stored object referenced by RefCount sys_files rows
+type SysFileBlob struct {
+	ID            int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	FileMD5       string    `gorm:"column:file_md5;size:32;not null;uniqueIndex:idx_sys_file_blobs_content,priority:1"`
+	FileSize      int64     `gorm:"column:file_size;not null;uniqueIndex:idx_sys_file_blobs_content,priority:2"`
+	FilePath      string    `gorm:"column:file_path;size:191;not null"`
+	StorageEngine string    `gorm:"column:storage_engine;size:10;not null"`
+	SourceMD5     string    `gorm:"column:source_md5;size:32;not null;default:'';index:idx_sys_file_blobs_source,priority:1"`
+	SourceSize    int64     `gorm:"column:source_size;not null;default:0;index:idx_sys_file_blobs_source,priority:2"`
+	RefCount      int64     `gorm:"column:ref_count;not null;default:0"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (SysFileBlob) TableName() string {
+	return "sys_file_blobs"
+}
+
+func (b *SysFileBlob) toDomainMapper() *filesDomain.FileBlob {
+	return &filesDomain.FileBlob{
+		ID:            b.ID,
+		FileMD5:       b.FileMD5,
+		FileSize:      b.FileSize,
+		FilePath:      b.FilePath,
+		StorageEngine: b.StorageEngine,
+		SourceMD5:     b.SourceMD5,
+		SourceSize:    b.SourceSize,
+		RefCount:      b.RefCount,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+	}
+}
+
+// AcquireBlob records the blob with one reference, or adds a reference to the blob with the
+// same content. The returned blob's path differs from the given one when the content was
+// already stored.
+func (r *Repository) AcquireBlob(ctx context.Context, blob *filesDomain.FileBlob) (*filesDomain.FileBlob, error) {
+	var acquired SysFileBlob
+	err := r.DB.WithContext(ctx).Raw(`
+INSERT INTO sys_file_blobs (file_md5, file_size, file_path, storage_engine, source_md5, source_size, ref_count,
+	created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, 1, now(), now())
+ON CONFLICT (file_md5, file_size) DO UPDATE
+SET ref_count = sys_file_blobs.ref_count + 1, updated_at = now()
+RETURNING *`, blob.FileMD5, blob.FileSize, blob.FilePath, blob.StorageEngine, blob.SourceMD5, blob.SourceSize).
+		Scan(&acquired).Error
+	if err != nil {
+		r.Logger.Error("Error acquiring file blob", zap.Error(err), zap.String("md5", blob.FileMD5))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return acquired.toDomainMapper(), nil
+}
+
+// AcquireBlobByContent adds a reference to the blob with this md5 and size, stored as is or
+// received before being modified, nil when there is none. Blobs whose last reference is being
+// released are not revived.
+func (r *Repository) AcquireBlobByContent(ctx context.Context, md5 string, size int64) (*filesDomain.FileBlob, error) {
+	var acquired []SysFileBlob
+	err := r.DB.WithContext(ctx).Raw(`
+UPDATE sys_file_blobs SET ref_count = ref_count + 1, updated_at = now()
+WHERE id = (
+	SELECT id FROM sys_file_blobs
+	WHERE ((file_md5 = ? AND file_size = ?) OR (source_md5 = ? AND source_size = ?)) AND ref_count > 0
+	LIMIT 1
+) AND ref_count > 0
+RETURNING *`, md5, size, md5, size).Scan(&acquired).Error
+	if err != nil {
+		r.Logger.Error("Error acquiring file blob by content", zap.Error(err), zap.String("md5", md5))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	if len(acquired) == 0 {
+		return nil, nil
+	}
+	return acquired[0].toDomainMapper(), nil
+}
+
+// ReleaseBlobs drops one reference per id, an id may be repeated. The blobs left without
+// references are deleted and returned, their objects have to be removed from the storage.
+func (r *Repository) ReleaseBlobs(ctx context.Context, blobIDs []int64) (*[]filesDomain.FileBlob, error) {
+	var released []SysFileBlob
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return releaseBlobs(tx, blobIDs, &released)
+	})
+	if err != nil {
+		r.Logger.Error("Error releasing file blobs", zap.Error(err), zap.Int64s("blobIds", blobIDs))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return blobsToDomain(released), nil
+}
+
+func releaseBlobs(tx *gorm.DB, blobIDs []int64, released *[]SysFileBlob) error {
+	if len(blobIDs) == 0 {
+		return nil
+	}
+	counts := map[int64]int64{}
+	for _, id := range blobIDs {
+		counts[id]++
+	}
+	ids := make([]int64, 0, len(counts))
+	for id, count := range counts {
+		if err := tx.Exec(`UPDATE sys_file_blobs SET ref_count = ref_count - ?, updated_at = now() WHERE id = ?`,
+			count, id).Error; err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	return tx.Raw(`DELETE FROM sys_file_blobs WHERE id IN ? AND ref_count <= 0 RETURNING *`, ids).Scan(released).Error
+}
+
+func blobsToDomain(blobs []SysFileBlob) *[]filesDomain.FileBlob {
+	result := make([]filesDomain.FileBlob, len(blobs))
+	for i := range blobs {
+		result[i] = *blobs[i].toDomainMapper()
+	}
+	return &result
+}
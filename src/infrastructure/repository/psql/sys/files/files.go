@@ -1,9 +1,9 @@
 package files
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
@@ -11,6 +11,7 @@ import (
 	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/utils"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -23,9 +24,15 @@ type SysFiles struct {
 	FileName       string `gorm:"column:file_name;size:191;" json:"fileName"`
 	FileMD5        string `gorm:"column:file_md5;size:191;" json:"fileMD5"`
 	FilePath       string `gorm:"column:file_path;size:191;" json:"filePath"`
+	FileSize       int64  `gorm:"column:file_size;default:0" json:"fileSize"`
 	StorageEngine  string `gorm:"column:storage_engine;size:10;" json:"storageEngine"`
 	FileOriginName string `gorm:"column:file_origin_name;size:191;" json:"fileOriginName"`
-	ID             int64  `gorm:"column:id;primary_key;autoIncrement" json:"id,omitempty"`
+	BlobID         *int64 `gorm:"column:blob_id;index" json:"blobId,omitempty"`
+	// Visibility defaults to public for the files recorded before it existed
+	Visibility  string `gorm:"column:visibility;size:10;not null;default:public" json:"visibility"`
+	OwnerID     *int64 `gorm:"column:owner_id;index" json:"ownerId,omitempty"`
+	OwnerRoleID *int64 `gorm:"column:owner_role_id" json:"ownerRoleId,omitempty"`
+	ID          int64  `gorm:"column:id;primary_key;autoIncrement" json:"id,omitempty"`
 }
 
 func (SysFiles) TableName() string {
@@ -38,19 +45,61 @@ var ColumnsSysFilesMapping = map[string]string{
 	"fileName":       "file_name",
 	"selectedDictId": "file_group",
 	"method":         "method",
+	"visibility":     "visibility",
+	"ownerId":        "owner_id",
 	"createdAt":      "created_at",
 	"updatedAt":      "updated_at",
 }
 
 type ISysFilesRepository interface {
-	Create(data *filesDomain.SysFiles) (*filesDomain.SysFiles, error)
-	GetAll() (*[]filesDomain.SysFiles, error)
-	GetByID(id int) (*filesDomain.SysFiles, error)
-	Update(id int, fileMap map[string]interface{}) (*filesDomain.SysFiles, error)
-	Delete(ids []int64) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[filesDomain.SysFiles], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(fileMap map[string]interface{}) (*filesDomain.SysFiles, error)
+	Create(ctx context.Context, data *filesDomain.SysFiles) (*filesDomain.SysFiles, error)
+	GetAll(ctx context.Context) (*[]filesDomain.SysFiles, error)
+	GetByID(ctx context.Context, id int) (*filesDomain.SysFiles, error)
+	Update(ctx context.Context, id int, fileMap map[string]interface{}) (*filesDomain.SysFiles, error)
+	// Delete removes the files and returns the blobs they were the last references of. The
+	// objects of the files recorded without blob that nothing else references are returned as
+	// blobs without id.
+	Delete(ctx context.Context, ids []int64) (*[]filesDomain.FileBlob, error)
+	AcquireBlob(ctx context.Context, blob *filesDomain.FileBlob) (*filesDomain.FileBlob, error)
+	AcquireBlobByContent(ctx context.Context, md5 string, size int64) (*filesDomain.FileBlob, error)
+	ReleaseBlobs(ctx context.Context, blobIDs []int64) (*[]filesDomain.FileBlob, error)
+	// IsPublicPath tells whether the object at path belongs to a public file or to one of its
+	// renditions
+	IsPublicPath(ctx context.Context, path string) (bool, error)
+	// KnownPaths returns which of the paths of an engine a file, a blob or a rendition references
+	KnownPaths(ctx context.Context, engine string, paths []string) (map[string]bool, error)
+	SaveRendition(ctx context.Context, rendition *filesDomain.FileRendition) (*filesDomain.FileRendition, error)
+	GetRenditions(ctx context.Context, fileID int64) (*[]filesDomain.FileRendition, error)
+	// DeleteRenditions removes the renditions of the files and returns them, their objects have
+	// to be removed from the storage
+	DeleteRenditions(ctx context.Context, fileIDs []int64) (*[]filesDomain.FileRendition, error)
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[filesDomain.SysFiles], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, fileMap map[string]interface{}) (*filesDomain.SysFiles, error)
+	// GetByIDs returns the files that exist among ids
+	GetByIDs(ctx context.Context, ids []int64) (*[]filesDomain.SysFiles, error)
+	// GetByBlobID returns the files that share the blob
+	GetByBlobID(ctx context.Context, blobID int64) (*[]filesDomain.SysFiles, error)
+	AttachFile(ctx context.Context, attachment *filesDomain.FileAttachment) (*filesDomain.FileAttachment, error)
+	DetachFile(ctx context.Context, entityType string, entityID int64, fileID int64) (bool, error)
+	GetAttachments(ctx context.Context, entityType string, entityID int64) (*[]filesDomain.FileAttachment, error)
+	SortAttachments(ctx context.Context, entityType string, entityID int64, fileIDs []int64) error
+	DetachEntity(ctx context.Context, entityType string, entityID int64) (int64, error)
+	DetachDeletedEntities(ctx context.Context, entityType string, table string, softDelete bool) (int64, error)
+	EntityExists(ctx context.Context, table string, softDelete bool, id int64) (bool, error)
+	// DanglingBlobs returns the blobs no file references that were last updated before the
+	// cutoff, ordered by id from afterID
+	DanglingBlobs(ctx context.Context, before time.Time, afterID int64, limit int) (*[]filesDomain.FileBlob, error)
+	// DeleteDanglingBlobs deletes the blobs that are still dangling and returns them, their
+	// objects have to be removed from the storage
+	DeleteDanglingBlobs(ctx context.Context, ids []int64, before time.Time) (*[]filesDomain.FileBlob, error)
+	// UnreferencedFiles returns the uploaded files created before the cutoff that no entity
+	// references, ordered by id from afterID
+	UnreferencedFiles(ctx context.Context, before time.Time, afterID int64, limit int) (*[]filesDomain.SysFiles, error)
+	// UsageByOwner sums the files of each owner, the largest first, or of one owner when
+	// ownerID isn't zero
+	UsageByOwner(ctx context.Context, ownerID int64, limit int) (*[]filesDomain.UserStorageUsage, error)
+	UsageByEngine(ctx context.Context) (*[]filesDomain.EngineStorageUsage, error)
 }
 
 type Repository struct {
@@ -59,10 +108,13 @@ type Repository struct {
 }
 
 // Create implements ISysFilesRepository.
-func (r *Repository) Create(data *filesDomain.SysFiles) (*filesDomain.SysFiles, error) {
+func (r *Repository) Create(ctx context.Context, data *filesDomain.SysFiles) (*filesDomain.SysFiles, error) {
 	r.Logger.Info("Upload new file", zap.String("filename", data.FileName))
 	fileRepository := fromDomainMapper(data)
-	txDb := r.DB.Create(fileRepository)
+	if fileRepository.Visibility == "" {
+		fileRepository.Visibility = DefaultVisibility()
+	}
+	txDb := r.DB.WithContext(ctx).Create(fileRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating user", zap.Error(err), zap.String("filename", data.FileName))
@@ -71,6 +123,16 @@ func (r *Repository) Create(data *filesDomain.SysFiles) (*filesDomain.SysFiles,
 	return fileRepository.toDomainMapper(), err
 }
 
+// DefaultVisibility is the visibility of the files created without one, FILE_DEFAULT_VISIBILITY
+// or private. The column default only applies to the files recorded before visibilities existed.
+func DefaultVisibility() string {
+	visibility := sharedUtil.GetEnv("FILE_DEFAULT_VISIBILITY", filesDomain.VisibilityPrivate)
+	if !filesDomain.ValidVisibility(visibility) {
+		return filesDomain.VisibilityPrivate
+	}
+	return visibility
+}
+
 func NewSysFilesRepository(db *gorm.DB, loggerInstance *logger.Logger) ISysFilesRepository {
 	return &Repository{DB: db, Logger: loggerInstance}
 }
@@ -78,9 +140,15 @@ func NewSysFilesRepository(db *gorm.DB, loggerInstance *logger.Logger) ISysFiles
 func fromDomainMapper(u *filesDomain.SysFiles) *SysFiles {
 	return &SysFiles{
 		FileName:       u.FileName,
+		FileMD5:        u.FileMD5,
 		FilePath:       u.FilePath,
+		FileSize:       u.FileSize,
 		StorageEngine:  u.StorageEngine,
 		FileOriginName: u.FileOriginName,
+		BlobID:         u.BlobID,
+		Visibility:     u.Visibility,
+		OwnerID:        u.OwnerID,
+		OwnerRoleID:    u.OwnerRoleID,
 	}
 }
 
@@ -89,28 +157,22 @@ func (u *SysFiles) toDomainMapper() *filesDomain.SysFiles {
 		ID:             u.ID,
 		FileName:       u.FileName,
 		FileMD5:        u.FileMD5,
-		FileUrl:        u.getUrl(),
+		FilePath:       u.FilePath,
+		FileSize:       u.FileSize,
 		StorageEngine:  u.StorageEngine,
 		FileOriginName: u.FileOriginName,
+		BlobID:         u.BlobID,
+		Visibility:     u.Visibility,
+		OwnerID:        u.OwnerID,
+		OwnerRoleID:    u.OwnerRoleID,
 		CreatedAt:      *u.CreatedAt,
 		UpdatedAt:      *u.UpdatedAt,
 	}
 }
 
-func (u *SysFiles) getUrl() string {
-	switch u.StorageEngine {
-	case "local":
-		return fmt.Sprintf("%s/%s", os.Getenv("APP_URL"), u.FilePath)
-	case "aliyunoss":
-		return fmt.Sprintf("%s%s", os.Getenv("OSS_BASE_URL"), u.FilePath)
-	default:
-		return ""
-	}
-}
-
-func (r *Repository) GetAll() (*[]filesDomain.SysFiles, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]filesDomain.SysFiles, error) {
 	var files []SysFiles
-	if err := r.DB.Find(&files).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&files).Error; err != nil {
 		r.Logger.Error("Error getting all files", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -118,9 +180,9 @@ func (r *Repository) GetAll() (*[]filesDomain.SysFiles, error) {
 	return arrayToDomainMapper(&files), nil
 }
 
-func (r *Repository) GetByID(id int) (*filesDomain.SysFiles, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*filesDomain.SysFiles, error) {
 	var file SysFiles
-	err := r.DB.Where("id = ?", id).First(&file).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&file).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("SysFiles not found", zap.Int("id", id))
@@ -135,11 +197,11 @@ func (r *Repository) GetByID(id int) (*filesDomain.SysFiles, error) {
 	return file.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, fileMap map[string]interface{}) (*filesDomain.SysFiles, error) {
+func (r *Repository) Update(ctx context.Context, id int, fileMap map[string]interface{}) (*filesDomain.SysFiles, error) {
 	var fileObj SysFiles
 	fileObj.ID = int64(id)
 	delete(fileMap, "updated_at")
-	err := r.DB.Model(&fileObj).Updates(fileMap).Error
+	err := r.DB.WithContext(ctx).Model(&fileObj).Updates(fileMap).Error
 	if err != nil {
 		r.Logger.Error("Error updating file", zap.Error(err), zap.Int("id", id))
 		byteErr, _ := json.Marshal(err)
@@ -155,7 +217,7 @@ func (r *Repository) Update(id int, fileMap map[string]interface{}) (*filesDomai
 			return &filesDomain.SysFiles{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&fileObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&fileObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated file", zap.Error(err), zap.Int("id", id))
 		return &filesDomain.SysFiles{}, err
 	}
@@ -163,23 +225,106 @@ func (r *Repository) Update(id int, fileMap map[string]interface{}) (*filesDomai
 	return fileObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(ids []int64) error {
-	tx := r.DB.Where("id IN ?", ids).Delete(&SysFiles{})
-
-	if tx.Error != nil {
-		r.Logger.Error("Error deleting file", zap.Error(tx.Error), zap.String("ids", fmt.Sprintf("%v", ids)))
-		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+// Delete releases the blob reference of every deleted file in the same transaction, so a blob
+// is never counted for a file that no longer exists
+func (r *Repository) Delete(ctx context.Context, ids []int64) (*[]filesDomain.FileBlob, error) {
+	var released []SysFileBlob
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deleted []SysFiles
+		result := tx.Raw(`DELETE FROM sys_files WHERE id IN ? RETURNING *`, ids).Scan(&deleted)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(deleted) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Exec(`DELETE FROM sys_file_attachments WHERE file_id IN ?`, ids).Error; err != nil {
+			return err
+		}
+		var blobIDs []int64
+		for _, file := range deleted {
+			if file.BlobID != nil {
+				blobIDs = append(blobIDs, *file.BlobID)
+				continue
+			}
+			unreferenced, err := unreferencedPath(tx, file.StorageEngine, file.FilePath)
+			if err != nil {
+				return err
+			}
+			if unreferenced {
+				released = append(released, SysFileBlob{FileMD5: file.FileMD5, FileSize: file.FileSize,
+					FilePath: file.FilePath, StorageEngine: file.StorageEngine})
+			}
+		}
+		return releaseBlobs(tx, blobIDs, &released)
+	})
+	if err == gorm.ErrRecordNotFound {
+		r.Logger.Warn("File not found for deletion", zap.String("ids", fmt.Sprintf("%v", ids)))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
 	}
-	if tx.RowsAffected == 0 {
-		r.Logger.Warn("Api not found for deletion", zap.String("ids", fmt.Sprintf("%v", ids)))
-		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	if err != nil {
+		r.Logger.Error("Error deleting file", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", ids)))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
 	r.Logger.Info("Successfully deleted file", zap.String("ids", fmt.Sprintf("%v", ids)))
-	return nil
+	return blobsToDomain(released), nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[filesDomain.SysFiles], error) {
-	query := r.DB.Model(&SysFiles{})
+// unreferencedPath tells whether no file, blob or rendition is stored at path any more, files
+// recorded before blobs existed may share their object
+func unreferencedPath(tx *gorm.DB, engine, path string) (bool, error) {
+	if path == "" {
+		return false, nil
+	}
+	var referenced bool
+	err := tx.Raw(`
+SELECT EXISTS (SELECT 1 FROM sys_files WHERE storage_engine = ? AND file_path = ?)
+    OR EXISTS (SELECT 1 FROM sys_file_blobs WHERE storage_engine = ? AND file_path = ?)
+    OR EXISTS (SELECT 1 FROM sys_file_renditions WHERE storage_engine = ? AND file_path = ?)`,
+		engine, path, engine, path, engine, path).Scan(&referenced).Error
+	return !referenced, err
+}
+
+// IsPublicPath implements ISysFilesRepository.
+// Files sharing a blob share its path, the object is public when one of them is.
+func (r *Repository) IsPublicPath(ctx context.Context, path string) (bool, error) {
+	var public bool
+	err := r.DB.WithContext(ctx).Raw(`
+SELECT EXISTS (SELECT 1 FROM sys_files WHERE file_path = ? AND visibility = ? AND deleted_at IS NULL)
+    OR EXISTS (SELECT 1 FROM sys_file_renditions r JOIN sys_files f ON f.id = r.file_id
+               WHERE r.file_path = ? AND f.visibility = ? AND f.deleted_at IS NULL)`,
+		path, filesDomain.VisibilityPublic, path, filesDomain.VisibilityPublic).Scan(&public).Error
+	if err != nil {
+		r.Logger.Error("Error checking public path", zap.Error(err), zap.String("path", path))
+		return false, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return public, nil
+}
+
+// KnownPaths implements ISysFilesRepository.
+func (r *Repository) KnownPaths(ctx context.Context, engine string, paths []string) (map[string]bool, error) {
+	known := map[string]bool{}
+	if len(paths) == 0 {
+		return known, nil
+	}
+	var found []string
+	err := r.DB.WithContext(ctx).Raw(`
+SELECT file_path FROM sys_files WHERE storage_engine = ? AND file_path IN ? AND deleted_at IS NULL
+UNION SELECT file_path FROM sys_file_blobs WHERE storage_engine = ? AND file_path IN ?
+UNION SELECT file_path FROM sys_file_renditions WHERE storage_engine = ? AND file_path IN ?`,
+		engine, paths, engine, paths, engine, paths).Scan(&found).Error
+	if err != nil {
+		r.Logger.Error("Error checking known paths", zap.Error(err), zap.String("engine", engine))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	for _, path := range found {
+		known[path] = true
+	}
+	return known, nil
+}
+
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[filesDomain.SysFiles], error) {
+	query := r.DB.WithContext(ctx).Model(&SysFiles{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -266,7 +411,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsSysFilesMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -274,7 +419,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysFiles{}).
+	if err := r.DB.WithContext(ctx).Model(&SysFiles{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -298,9 +443,31 @@ func arrayToDomainMapper(files *[]SysFiles) *[]filesDomain.SysFiles {
 	return &filesDomain
 }
 
-func (r *Repository) GetOneByMap(fileMap map[string]interface{}) (*filesDomain.SysFiles, error) {
+// GetByIDs implements ISysFilesRepository.
+func (r *Repository) GetByIDs(ctx context.Context, ids []int64) (*[]filesDomain.SysFiles, error) {
+	var files []SysFiles
+	if len(ids) > 0 {
+		if err := r.DB.WithContext(ctx).Where("id IN ?", ids).Find(&files).Error; err != nil {
+			r.Logger.Error("Error getting files by IDs", zap.Error(err), zap.Int64s("ids", ids))
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		}
+	}
+	return arrayToDomainMapper(&files), nil
+}
+
+// GetByBlobID implements ISysFilesRepository.
+func (r *Repository) GetByBlobID(ctx context.Context, blobID int64) (*[]filesDomain.SysFiles, error) {
+	var files []SysFiles
+	if err := r.DB.WithContext(ctx).Where("blob_id = ? AND deleted_at IS NULL", blobID).Find(&files).Error; err != nil {
+		r.Logger.Error("Error getting files by blob", zap.Error(err), zap.Int64("blobId", blobID))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return arrayToDomainMapper(&files), nil
+}
+
+func (r *Repository) GetOneByMap(ctx context.Context, fileMap map[string]interface{}) (*filesDomain.SysFiles, error) {
 	var fileRepository SysFiles
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range fileMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
@@ -0,0 +1,117 @@
+package files
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"go.uber.org/zap"
+)
+
+// fileReferences are the ways an entity references the file f. An uploaded file none of them
+// matches is unreferenced.
+var fileReferences = []string{
+	// a chunked upload returns its file until the session expires
+	`EXISTS (SELECT 1 FROM sys_upload_sessions s WHERE s.file_id = f.id)`,
+	// the files attached to an entity
+	`EXISTS (SELECT 1 FROM sys_file_attachments a WHERE a.file_id = f.id)`,
+	// avatars keep the url of the file
+	`EXISTS (SELECT 1 FROM sys_users u WHERE strpos(u.header_img, f.file_path) > 0)`,
+}
+
+// DanglingBlobs implements ISysFilesRepository.
+func (r *Repository) DanglingBlobs(ctx context.Context, before time.Time, afterID int64, limit int) (*[]filesDomain.FileBlob, error) {
+	var blobs []SysFileBlob
+	err := r.DB.WithContext(ctx).Raw(`
+SELECT * FROM sys_file_blobs b
+WHERE b.id > ? AND b.updated_at < ? AND NOT EXISTS (SELECT 1 FROM sys_files f WHERE f.blob_id = b.id)
+ORDER BY b.id LIMIT ?`, afterID, before, limit).Scan(&blobs).Error
+	if err != nil {
+		r.Logger.Error("Error finding dangling blobs", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return blobsToDomain(blobs), nil
+}
+
+// DeleteDanglingBlobs implements ISysFilesRepository.
+// The conditions are checked again, a blob acquired since it was found is kept.
+func (r *Repository) DeleteDanglingBlobs(ctx context.Context, ids []int64, before time.Time) (*[]filesDomain.FileBlob, error) {
+	var deleted []SysFileBlob
+	if len(ids) == 0 {
+		return blobsToDomain(deleted), nil
+	}
+	err := r.DB.WithContext(ctx).Raw(`
+DELETE FROM sys_file_blobs b
+WHERE b.id IN ? AND b.updated_at < ? AND NOT EXISTS (SELECT 1 FROM sys_files f WHERE f.blob_id = b.id)
+RETURNING *`, ids, before).Scan(&deleted).Error
+	if err != nil {
+		r.Logger.Error("Error deleting dangling blobs", zap.Error(err), zap.Int64s("blobIds", ids))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return blobsToDomain(deleted), nil
+}
+
+// UnreferencedFiles implements ISysFilesRepository.
+// Only the files uploaded by a user are considered, those recorded without owner, like the
+// operation record archives, are kept by whoever recorded them.
+func (r *Repository) UnreferencedFiles(ctx context.Context, before time.Time, afterID int64, limit int) (*[]filesDomain.SysFiles, error) {
+	var files []SysFiles
+	err := r.DB.WithContext(ctx).Raw(`
+SELECT f.* FROM sys_files f
+WHERE f.id > ? AND f.created_at < ? AND f.owner_id IS NOT NULL AND f.deleted_at IS NULL
+  AND NOT (`+strings.Join(fileReferences, " OR ")+`)
+ORDER BY f.id LIMIT ?`, afterID, before, limit).Scan(&files).Error
+	if err != nil {
+		r.Logger.Error("Error finding unreferenced files", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return arrayToDomainMapper(&files), nil
+}
+
+// UsageByOwner implements ISysFilesRepository.
+func (r *Repository) UsageByOwner(ctx context.Context, ownerID int64, limit int) (*[]filesDomain.UserStorageUsage, error) {
+	var usage []filesDomain.UserStorageUsage
+	query := r.DB.WithContext(ctx).Table("sys_files").
+		Select("owner_id AS user_id, COUNT(*) AS files, COALESCE(SUM(file_size), 0) AS bytes").
+		Where("deleted_at IS NULL")
+	if ownerID != 0 {
+		query = query.Where("owner_id = ?", ownerID)
+	}
+	err := query.Group("owner_id").Order("bytes DESC").Limit(limit).Scan(&usage).Error
+	if err != nil {
+		r.Logger.Error("Error summing storage usage by owner", zap.Error(err), zap.Int64("ownerId", ownerID))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return &usage, nil
+}
+
+// UsageByEngine implements ISysFilesRepository.
+// An object is counted once however many files share it: the blobs, the objects of the files
+// recorded without blob and the renditions.
+func (r *Repository) UsageByEngine(ctx context.Context) (*[]filesDomain.EngineStorageUsage, error) {
+	var usage []filesDomain.EngineStorageUsage
+	err := r.DB.WithContext(ctx).Raw(`
+WITH recorded AS (
+    SELECT storage_engine, COUNT(*) AS files, COALESCE(SUM(file_size), 0) AS bytes
+    FROM sys_files WHERE deleted_at IS NULL GROUP BY storage_engine
+), objects AS (
+    SELECT storage_engine, file_path, file_size FROM sys_file_blobs
+    UNION SELECT storage_engine, file_path, file_size FROM sys_files WHERE blob_id IS NULL AND deleted_at IS NULL
+    UNION SELECT storage_engine, file_path, file_size FROM sys_file_renditions
+), stored AS (
+    SELECT storage_engine, COUNT(*) AS objects, COALESCE(SUM(file_size), 0) AS stored_bytes
+    FROM objects GROUP BY storage_engine
+)
+SELECT COALESCE(recorded.storage_engine, stored.storage_engine) AS engine,
+       COALESCE(recorded.files, 0) AS files, COALESCE(recorded.bytes, 0) AS bytes,
+       COALESCE(stored.objects, 0) AS objects, COALESCE(stored.stored_bytes, 0) AS stored_bytes
+FROM recorded FULL JOIN stored ON stored.storage_engine = recorded.storage_engine
+ORDER BY engine`).Scan(&usage).Error
+	if err != nil {
+		r.Logger.Error("Error summing storage usage by engine", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return &usage, nil
+}
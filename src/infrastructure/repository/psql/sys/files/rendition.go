@@ -0,0 +1,89 @@
+package files
+
+import (
+	"context"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"go.uber.org/zap"
+)
+
+// SysFileRendition is a thumbnail derived from the image of a sys_files row, one per size
+type SysFileRendition struct {
+	ID            int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	FileID        int64     `gorm:"column:file_id;not null;uniqueIndex:idx_sys_file_renditions_size,priority:1"`
+	Size          string    `gorm:"column:size;size:32;not null;uniqueIndex:idx_sys_file_renditions_size,priority:2"`
+	Width         int       `gorm:"column:width;not null"`
+	Height        int       `gorm:"column:height;not null"`
+	ContentType   string    `gorm:"column:content_type;size:64;not null"`
+	FilePath      string    `gorm:"column:file_path;size:191;not null"`
+	FileSize      int64     `gorm:"column:file_size;not null;default:0"`
+	StorageEngine string    `gorm:"column:storage_engine;size:10;not null"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (SysFileRendition) TableName() string {
+	return "sys_file_renditions"
+}
+
+func (m *SysFileRendition) toDomainMapper() *filesDomain.FileRendition {
+	return &filesDomain.FileRendition{
+		ID:            m.ID,
+		FileID:        m.FileID,
+		Size:          m.Size,
+		Width:         m.Width,
+		Height:        m.Height,
+		ContentType:   m.ContentType,
+		FilePath:      m.FilePath,
+		FileSize:      m.FileSize,
+		StorageEngine: m.StorageEngine,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+// SaveRendition records the rendition, replacing the one of the same file and size
+func (r *Repository) SaveRendition(ctx context.Context, rendition *filesDomain.FileRendition) (*filesDomain.FileRendition, error) {
+	var saved SysFileRendition
+	err := r.DB.WithContext(ctx).Raw(`
+INSERT INTO sys_file_renditions (file_id, size, width, height, content_type, file_path, file_size, storage_engine, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, now(), now())
+ON CONFLICT (file_id, size) DO UPDATE
+SET width = EXCLUDED.width, height = EXCLUDED.height, content_type = EXCLUDED.content_type, file_path = EXCLUDED.file_path,
+    file_size = EXCLUDED.file_size, storage_engine = EXCLUDED.storage_engine, updated_at = now()
+RETURNING *`, rendition.FileID, rendition.Size, rendition.Width, rendition.Height, rendition.ContentType,
+		rendition.FilePath, rendition.FileSize, rendition.StorageEngine).Scan(&saved).Error
+	if err != nil {
+		r.Logger.Error("Error saving file rendition", zap.Error(err), zap.Int64("fileId", rendition.FileID), zap.String("size", rendition.Size))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return saved.toDomainMapper(), nil
+}
+
+func (r *Repository) GetRenditions(ctx context.Context, fileID int64) (*[]filesDomain.FileRendition, error) {
+	var renditions []SysFileRendition
+	if err := r.DB.WithContext(ctx).Where("file_id = ?", fileID).Order("width, id").Find(&renditions).Error; err != nil {
+		r.Logger.Error("Error getting file renditions", zap.Error(err), zap.Int64("fileId", fileID))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return renditionsToDomain(renditions), nil
+}
+
+func (r *Repository) DeleteRenditions(ctx context.Context, fileIDs []int64) (*[]filesDomain.FileRendition, error) {
+	var deleted []SysFileRendition
+	if err := r.DB.WithContext(ctx).Raw(`DELETE FROM sys_file_renditions WHERE file_id IN ? RETURNING *`, fileIDs).Scan(&deleted).Error; err != nil {
+		r.Logger.Error("Error deleting file renditions", zap.Error(err), zap.Int64s("fileIds", fileIDs))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return renditionsToDomain(deleted), nil
+}
+
+func renditionsToDomain(renditions []SysFileRendition) *[]filesDomain.FileRendition {
+	result := make([]filesDomain.FileRendition, len(renditions))
+	for i := range renditions {
+		result[i] = *renditions[i].toDomainMapper()
+	}
+	return &result
+}
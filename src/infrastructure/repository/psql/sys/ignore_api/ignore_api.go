@@ -1 +1,143 @@
 package ignore_api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainIgnoreApi "github.com/gbrayhan/microservices-go/src/domain/sys/ignore_api"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SysIgnoreApi represents the sys_ignore_apis table in the database.
+type SysIgnoreApi struct {
+	ID          int            `gorm:"column:id;primary_key" json:"id"`
+	CreatedAt   time.Time      `gorm:"column:created_at" json:"createdAt,omitempty"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt,omitempty"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deletedAt,omitempty"`
+	Path        string         `gorm:"column:path;type:varchar(255)" json:"path"`       // 路径
+	Method      string         `gorm:"column:method;type:varchar(20)" json:"method"`    // 方法
+	Description string         `gorm:"column:description" json:"description,omitempty"` // 描述
+}
+
+func (SysIgnoreApi) TableName() string {
+	return "sys_ignore_apis"
+}
+
+// IgnoreApiRepositoryInterface defines the interface for ignore api repository operations
+type IgnoreApiRepositoryInterface interface {
+	GetAll(ctx context.Context) (*[]domainIgnoreApi.IgnoreApi, error)
+	Create(ctx context.Context, ignoreApiDomain *domainIgnoreApi.IgnoreApi) (*domainIgnoreApi.IgnoreApi, error)
+	GetByID(ctx context.Context, id int) (*domainIgnoreApi.IgnoreApi, error)
+	Update(ctx context.Context, id int, ignoreApiMap map[string]interface{}) (*domainIgnoreApi.IgnoreApi, error)
+	Delete(ctx context.Context, ids []int) error
+}
+
+type Repository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewIgnoreApiRepository(db *gorm.DB, loggerInstance *logger.Logger) IgnoreApiRepositoryInterface {
+	return &Repository{DB: db, Logger: loggerInstance}
+}
+
+func (r *Repository) GetAll(ctx context.Context) (*[]domainIgnoreApi.IgnoreApi, error) {
+	var ignoreApis []SysIgnoreApi
+	if err := r.DB.WithContext(ctx).Order("id asc").Find(&ignoreApis).Error; err != nil {
+		r.Logger.Error("Error getting all ignore apis", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return arrayToDomainMapper(&ignoreApis), nil
+}
+
+func (r *Repository) Create(ctx context.Context, ignoreApiDomain *domainIgnoreApi.IgnoreApi) (*domainIgnoreApi.IgnoreApi, error) {
+	r.Logger.Info("Creating new ignore api", zap.String("path", ignoreApiDomain.Path))
+	ignoreApiRepository := fromDomainMapper(ignoreApiDomain)
+	if err := r.DB.WithContext(ctx).Create(ignoreApiRepository).Error; err != nil {
+		r.Logger.Error("Error creating ignore api", zap.Error(err), zap.String("path", ignoreApiDomain.Path))
+		return &domainIgnoreApi.IgnoreApi{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	r.Logger.Info("Successfully created ignore api", zap.String("path", ignoreApiDomain.Path), zap.Int("id", ignoreApiRepository.ID))
+	return ignoreApiRepository.toDomainMapper(), nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainIgnoreApi.IgnoreApi, error) {
+	var ignoreApi SysIgnoreApi
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&ignoreApi).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			r.Logger.Warn("Ignore api not found", zap.Int("id", id))
+			err = domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		} else {
+			r.Logger.Error("Error getting ignore api by ID", zap.Error(err), zap.Int("id", id))
+			err = domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		}
+		return &domainIgnoreApi.IgnoreApi{}, err
+	}
+	return ignoreApi.toDomainMapper(), nil
+}
+
+func (r *Repository) Update(ctx context.Context, id int, ignoreApiMap map[string]interface{}) (*domainIgnoreApi.IgnoreApi, error) {
+	var ignoreApiObj SysIgnoreApi
+	ignoreApiObj.ID = id
+	delete(ignoreApiMap, "updated_at")
+	err := r.DB.WithContext(ctx).Model(&ignoreApiObj).
+		Select("path", "method", "description").
+		Updates(ignoreApiMap).Error
+	if err != nil {
+		r.Logger.Error("Error updating ignore api", zap.Error(err), zap.Int("id", id))
+		return &domainIgnoreApi.IgnoreApi{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&ignoreApiObj).Error; err != nil {
+		r.Logger.Error("Error retrieving updated ignore api", zap.Error(err), zap.Int("id", id))
+		return &domainIgnoreApi.IgnoreApi{}, err
+	}
+	r.Logger.Info("Successfully updated ignore api", zap.Int("id", id))
+	return ignoreApiObj.toDomainMapper(), nil
+}
+
+func (r *Repository) Delete(ctx context.Context, ids []int) error {
+	tx := r.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&SysIgnoreApi{})
+	if tx.Error != nil {
+		r.Logger.Error("Error deleting ignore api", zap.Error(tx.Error), zap.String("ids", fmt.Sprintf("%v", ids)))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	if tx.RowsAffected == 0 {
+		r.Logger.Warn("Ignore api not found for deletion", zap.String("ids", fmt.Sprintf("%v", ids)))
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	r.Logger.Info("Successfully deleted ignore api", zap.String("ids", fmt.Sprintf("%v", ids)))
+	return nil
+}
+
+func (u *SysIgnoreApi) toDomainMapper() *domainIgnoreApi.IgnoreApi {
+	return &domainIgnoreApi.IgnoreApi{
+		ID:          u.ID,
+		Path:        u.Path,
+		Method:      u.Method,
+		Description: u.Description,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+	}
+}
+
+func fromDomainMapper(u *domainIgnoreApi.IgnoreApi) *SysIgnoreApi {
+	return &SysIgnoreApi{
+		ID:          u.ID,
+		Path:        u.Path,
+		Method:      u.Method,
+		Description: u.Description,
+	}
+}
+
+func arrayToDomainMapper(ignoreApis *[]SysIgnoreApi) *[]domainIgnoreApi.IgnoreApi {
+	ignoreApisDomain := make([]domainIgnoreApi.IgnoreApi, len(*ignoreApis))
+	for i, ignoreApi := range *ignoreApis {
+		ignoreApisDomain[i] = *ignoreApi.toDomainMapper()
+	}
+	return &ignoreApisDomain
+}
@@ -0,0 +1,120 @@
+package menu_btn_api
+
+import (
+	"context"
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SysMenuBtnApi 按钮与接口关联表
+type SysMenuBtnApi struct {
+	SysBaseMenuBtnID int64 `gorm:"column:sys_base_menu_btn_id;type:int8;primaryKey" json:"sysBaseMenuBtnId"`
+	SysApiID         int64 `gorm:"column:sys_api_id;type:int8;primaryKey;index" json:"sysApiId"`
+}
+
+// TableName 指定表名
+func (SysMenuBtnApi) TableName() string {
+	return "sys_menu_btn_apis"
+}
+
+type ISysMenuBtnApiRepository interface {
+	Insert(ctx context.Context, btnId int64, UpdateMap map[string]any) error
+	GetByBtnId(ctx context.Context, btnId int64) ([]int64, error)
+	GetBtnIdsByApi(ctx context.Context, path string, method string) ([]int64, error)
+	DeleteByBtnId(ctx context.Context, btnId int64) error
+}
+
+type Repository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewSysMenuBtnApiRepository(db *gorm.DB, loggerInstance *logger.Logger) ISysMenuBtnApiRepository {
+	return &Repository{DB: db, Logger: loggerInstance}
+}
+
+// GetByBtnId returns the api ids bound to the button
+func (r *Repository) GetByBtnId(ctx context.Context, btnId int64) ([]int64, error) {
+	var btnApis []SysMenuBtnApi
+	err := r.DB.WithContext(ctx).Where("sys_base_menu_btn_id = ?", btnId).Find(&btnApis).Error
+	if err != nil {
+		r.Logger.Error("Error getting button apis", zap.Error(err), zap.Int64("btnId", btnId))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	apiIds := make([]int64, 0, len(btnApis))
+	for _, btnApi := range btnApis {
+		apiIds = append(apiIds, btnApi.SysApiID)
+	}
+	return apiIds, nil
+}
+
+// GetBtnIdsByApi returns the buttons guarding the api identified by route path and method
+func (r *Repository) GetBtnIdsByApi(ctx context.Context, path string, method string) ([]int64, error) {
+	var btnIds []int64
+	err := r.DB.WithContext(ctx).Table(SysMenuBtnApi{}.TableName()+" AS mba").
+		Joins("JOIN sys_apis ON sys_apis.id = mba.sys_api_id AND sys_apis.deleted_at IS NULL").
+		Where("sys_apis.path = ? AND sys_apis.method = ?", path, method).
+		Distinct().
+		Pluck("mba.sys_base_menu_btn_id", &btnIds).Error
+	if err != nil {
+		r.Logger.Error("Error getting buttons by api", zap.Error(err),
+			zap.String("path", path), zap.String("method", method))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return btnIds, nil
+}
+
+// Insert implements ISysMenuBtnApiRepository.
+func (r *Repository) Insert(ctx context.Context, btnId int64, UpdateMap map[string]any) error {
+	apiIdsInterface, ok := UpdateMap["apiIds"]
+	if !ok {
+		r.Logger.Error("apiIds not found in update map")
+		return domainErrors.NewAppErrorWithType(domainErrors.ValidationError)
+	}
+
+	apiIdsInterfaceSlice, ok := apiIdsInterface.([]interface{})
+	if !ok {
+		r.Logger.Error("apiIds is not an array")
+		return domainErrors.NewAppErrorWithType(domainErrors.ValidationError)
+	}
+
+	btnApis := make([]SysMenuBtnApi, 0, len(apiIdsInterfaceSlice))
+	for _, item := range apiIdsInterfaceSlice {
+		apiIdFloat64, ok := item.(float64)
+		if !ok {
+			r.Logger.Error("apiId is not a number")
+			return domainErrors.NewAppErrorWithType(domainErrors.ValidationError)
+		}
+		btnApis = append(btnApis, SysMenuBtnApi{
+			SysBaseMenuBtnID: btnId,
+			SysApiID:         int64(apiIdFloat64),
+		})
+	}
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("sys_base_menu_btn_id = ?", btnId).
+			Delete(&SysMenuBtnApi{}).Error; err != nil {
+			r.Logger.Error("Failed to delete existing button apis", zap.Error(err))
+			return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		}
+		if len(btnApis) == 0 {
+			return nil
+		}
+		if err := tx.Create(&btnApis).Error; err != nil {
+			r.Logger.Error("Failed to create button apis", zap.Error(err))
+			return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		}
+		return nil
+	})
+}
+
+// DeleteByBtnId removes every api binding of the button
+func (r *Repository) DeleteByBtnId(ctx context.Context, btnId int64) error {
+	if err := r.DB.WithContext(ctx).Where("sys_base_menu_btn_id = ?", btnId).Delete(&SysMenuBtnApi{}).Error; err != nil {
+		r.Logger.Error("Failed to delete button apis", zap.Error(err), zap.Int64("btnId", btnId))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return nil
+}
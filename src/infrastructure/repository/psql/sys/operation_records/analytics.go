@@ -0,0 +1,126 @@
+package operation_records
+
+import (
+	"context"
+	"fmt"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainOperation "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// numericSegment are the regexp_replace arguments collapsing the numeric path segments. A match
+// consumes the slash the next segment starts with, so /1/2 needs a second pass, a lookahead would
+// avoid it but its question mark would be taken by gorm for a placeholder.
+const numericSegment = `'/[0-9]+(/|$)', '/:id\1', 'g'`
+
+// routeExpr turns the stored request uri into a route: the query string is dropped and numeric
+// path segments are collapsed, so /v1/user/12?x=1 and /v1/user/13 both count as /v1/user/:id.
+// It must not contain a question mark either.
+const routeExpr = `regexp_replace(regexp_replace(split_part(path, chr(63), 1), ` + numericSegment + `), ` +
+	numericSegment + `)`
+
+const rangeFilter = `deleted_at IS NULL AND created_at >= ? AND created_at < ?`
+
+// OperationAnalyticsRepositoryInterface aggregates operation records with window functions
+type OperationAnalyticsRepositoryInterface interface {
+	LatencyPercentiles(ctx context.Context, query domainOperation.AnalyticsQuery) (*[]domainOperation.RouteLatency, error)
+	ErrorRates(ctx context.Context, query domainOperation.AnalyticsQuery) (*[]domainOperation.ErrorRateBucket, error)
+	TopUsers(ctx context.Context, query domainOperation.AnalyticsQuery) (*[]domainOperation.UserActivity, error)
+	SlowestEndpoints(ctx context.Context, query domainOperation.AnalyticsQuery) (*[]domainOperation.SlowEndpoint, error)
+}
+
+func NewOperationAnalyticsRepository(db *gorm.DB, loggerInstance *logger.Logger) OperationAnalyticsRepositoryInterface {
+	return &Repository{DB: db, Logger: loggerInstance}
+}
+
+// LatencyPercentiles uses cume_dist over each route's latencies, the nearest-rank percentile is
+// the smallest latency whose cumulative distribution reaches it
+func (r *Repository) LatencyPercentiles(ctx context.Context, query domainOperation.AnalyticsQuery) (*[]domainOperation.RouteLatency, error) {
+	sql := fmt.Sprintf(`
+WITH ranked AS (
+	SELECT method, %[1]s AS route, latency,
+		cume_dist() OVER (PARTITION BY method, %[1]s ORDER BY latency) AS dist,
+		count(*) OVER (PARTITION BY method, %[1]s) AS total
+	FROM sys_operation_records
+	WHERE %[2]s
+)
+SELECT method, route, max(total) AS count,
+	min(latency) FILTER (WHERE dist >= 0.5) AS p50,
+	min(latency) FILTER (WHERE dist >= 0.95) AS p95,
+	min(latency) FILTER (WHERE dist >= 0.99) AS p99
+FROM ranked
+GROUP BY method, route
+ORDER BY p95 DESC, count DESC
+LIMIT ?`, routeExpr, rangeFilter)
+	var rows []domainOperation.RouteLatency
+	if err := r.DB.WithContext(ctx).Raw(sql, query.Start, query.End, query.Limit).Scan(&rows).Error; err != nil {
+		r.Logger.Error("Error computing latency percentiles", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return &rows, nil
+}
+
+// ErrorRates counts 4xx and 5xx responses per time bucket, the bucket size is checked by the caller
+func (r *Repository) ErrorRates(ctx context.Context, query domainOperation.AnalyticsQuery) (*[]domainOperation.ErrorRateBucket, error) {
+	if !domainOperation.IsValidBucket(query.Bucket) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.ValidationError)
+	}
+	sql := fmt.Sprintf(`
+SELECT bucket, total, client_errors, server_errors,
+	(client_errors + server_errors)::float / total AS error_rate
+FROM (
+	SELECT date_trunc('%s', created_at) AS bucket, count(*) AS total,
+		count(*) FILTER (WHERE status BETWEEN 400 AND 499) AS client_errors,
+		count(*) FILTER (WHERE status >= 500) AS server_errors
+	FROM sys_operation_records
+	WHERE %s
+	GROUP BY 1
+) buckets
+ORDER BY bucket`, query.Bucket, rangeFilter)
+	var rows []domainOperation.ErrorRateBucket
+	if err := r.DB.WithContext(ctx).Raw(sql, query.Start, query.End).Scan(&rows).Error; err != nil {
+		r.Logger.Error("Error computing error rates", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return &rows, nil
+}
+
+// TopUsers ranks the authenticated users by request count, share is their part of all
+// authenticated requests in the period
+func (r *Repository) TopUsers(ctx context.Context, query domainOperation.AnalyticsQuery) (*[]domainOperation.UserActivity, error) {
+	sql := fmt.Sprintf(`
+SELECT rank() OVER (ORDER BY count(*) DESC) AS rank, user_id, count(*) AS count,
+	count(*)::float / sum(count(*)) OVER () AS share
+FROM sys_operation_records
+WHERE %s AND user_id <> 0
+GROUP BY user_id
+ORDER BY count DESC, user_id
+LIMIT ?`, rangeFilter)
+	var rows []domainOperation.UserActivity
+	if err := r.DB.WithContext(ctx).Raw(sql, query.Start, query.End, query.Limit).Scan(&rows).Error; err != nil {
+		r.Logger.Error("Error computing top users", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return &rows, nil
+}
+
+// SlowestEndpoints ranks the routes by average latency
+func (r *Repository) SlowestEndpoints(ctx context.Context, query domainOperation.AnalyticsQuery) (*[]domainOperation.SlowEndpoint, error) {
+	sql := fmt.Sprintf(`
+SELECT rank() OVER (ORDER BY avg(latency) DESC) AS rank, method, %s AS route, count(*) AS count,
+	avg(latency)::float AS avg_latency, max(latency) AS max_latency
+FROM sys_operation_records
+WHERE %s
+GROUP BY method, route
+ORDER BY avg_latency DESC, count DESC
+LIMIT ?`, routeExpr, rangeFilter)
+	var rows []domainOperation.SlowEndpoint
+	if err := r.DB.WithContext(ctx).Raw(sql, query.Start, query.End, query.Limit).Scan(&rows).Error; err != nil {
+		r.Logger.Error("Error computing slowest endpoints", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return &rows, nil
+}
@@ -0,0 +1,95 @@
+package operation_records
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	domainOperation "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// sqlCapture is a gorm logger keeping the statements with their variables inlined
+type sqlCapture struct {
+	gormLogger.Interface
+	statements []string
+}
+
+func (c *sqlCapture) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	c.statements = append(c.statements, sql)
+}
+
+// newDryRunRepository builds the statements without a database, scanning their rows fails
+func newDryRunRepository(t *testing.T) (*Repository, *sqlCapture) {
+	capture := &sqlCapture{Interface: gormLogger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: capture})
+	require.NoError(t, err)
+	return &Repository{DB: db, Logger: &logger.Logger{Log: zap.NewNop()}}, capture
+}
+
+func testQuery() domainOperation.AnalyticsQuery {
+	return domainOperation.AnalyticsQuery{
+		Start: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		Limit: 25,
+	}
+}
+
+func TestLatencyPercentilesSQL(t *testing.T) {
+	repository, capture := newDryRunRepository(t)
+	_, _ = repository.LatencyPercentiles(context.Background(), testQuery())
+	require.Len(t, capture.statements, 1)
+	sql := capture.statements[0]
+
+	assert.Contains(t, sql, "cume_dist() OVER (PARTITION BY method, "+routeExpr+" ORDER BY latency)")
+	for _, percentile := range []string{"dist >= 0.5)", "dist >= 0.95)", "dist >= 0.99)"} {
+		assert.Contains(t, sql, percentile)
+	}
+	assert.Contains(t, sql, "created_at >= '2026-03-01 00:00:00' AND created_at < '2026-03-02 00:00:00'")
+	assert.True(t, strings.HasSuffix(sql, "LIMIT 25"), sql)
+}
+
+func TestErrorRatesSQL(t *testing.T) {
+	repository, capture := newDryRunRepository(t)
+	query := testQuery()
+	query.Bucket = domainOperation.BucketHour
+	_, _ = repository.ErrorRates(context.Background(), query)
+	require.Len(t, capture.statements, 1)
+	assert.Contains(t, capture.statements[0], "date_trunc('hour', created_at)")
+	assert.Contains(t, capture.statements[0], "created_at >= '2026-03-01 00:00:00' AND created_at < '2026-03-02 00:00:00'")
+
+	query.Bucket = "hour', now()) --"
+	_, err := repository.ErrorRates(context.Background(), query)
+	assert.Error(t, err)
+	assert.Len(t, capture.statements, 1, "an unknown bucket never reaches the database")
+}
+
+func TestRouteExpr(t *testing.T) {
+	assert.NotContains(t, routeExpr, "?", "gorm would take it for a placeholder")
+	assert.Equal(t, 2, strings.Count(routeExpr, numericSegment))
+
+	// each pass behaves like regexp_replace with the g flag
+	segment := regexp.MustCompile(`/[0-9]+(/|$)`)
+	route := func(path string) string {
+		path, _, _ = strings.Cut(path, "?")
+		for range strings.Count(routeExpr, numericSegment) {
+			path = segment.ReplaceAllString(path, "/:id${1}")
+		}
+		return path
+	}
+	assert.Equal(t, "/v1/user/:id", route("/v1/user/12?x=1"))
+	assert.Equal(t, "/v1/user/:id/role/:id", route("/v1/user/12/role/3"))
+	assert.Equal(t, "/v1/user/:id/:id", route("/v1/user/1/2"))
+	assert.Equal(t, "/v1/user/:id/:id/:id", route("/v1/user/1/2/3"))
+	assert.Equal(t, "/v1/user/12a/v2", route("/v1/user/12a/v2"))
+}
@@ -1,6 +1,7 @@
 package operation_records
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -31,6 +32,7 @@ type SysOperationRecord struct {
 	Body         string         `gorm:"column:body" json:"body,omitempty"`
 	Resp         string         `gorm:"column:resp" json:"resp,omitempty"`
 	UserID       int64          `gorm:"column:user_id" json:"userId,omitempty"`
+	RequestID    string         `gorm:"column:request_id;type:varchar(128);index" json:"requestId,omitempty"`
 }
 
 func (*SysOperationRecord) TableName() string {
@@ -44,20 +46,25 @@ var ColumnsOperationMapping = map[string]string{
 	"description": "description",
 	"apiGroup":    "api_group",
 	"method":      "method",
+	"requestId":   "request_id",
 	"createdAt":   "created_at",
 	"updatedAt":   "updated_at",
 }
 
 // OperationRepositoryInterface defines the interface for api repository operations
 type OperationRepositoryInterface interface {
-	GetAll() (*[]domainOperation.SysOperationRecord, error)
-	Create(apiDomain *domainOperation.SysOperationRecord) (*domainOperation.SysOperationRecord, error)
-	GetByID(id int) (*domainOperation.SysOperationRecord, error)
-	Update(id int, apiMap map[string]interface{}) (*domainOperation.SysOperationRecord, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainOperation.SysOperationRecord], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(apiMap map[string]interface{}) (*domainOperation.SysOperationRecord, error)
+	GetAll(ctx context.Context) (*[]domainOperation.SysOperationRecord, error)
+	Create(ctx context.Context, apiDomain *domainOperation.SysOperationRecord) (*domainOperation.SysOperationRecord, error)
+	CreateBatch(ctx context.Context, records []*domainOperation.SysOperationRecord) error
+	GetByID(ctx context.Context, id int) (*domainOperation.SysOperationRecord, error)
+	Update(ctx context.Context, id int, apiMap map[string]interface{}) (*domainOperation.SysOperationRecord, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainOperation.SysOperationRecord], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, apiMap map[string]interface{}) (*domainOperation.SysOperationRecord, error)
+	FindBefore(ctx context.Context, before time.Time, afterID int, limit int) (*[]domainOperation.SysOperationRecord, error)
+	Purge(ctx context.Context, ids []int) error
+	StreamByDateRange(ctx context.Context, start, end time.Time, fn func(record *domainOperation.SysOperationRecord) error) error
 }
 
 type Repository struct {
@@ -69,9 +76,9 @@ func NewOperationRepository(db *gorm.DB, loggerInstance *logger.Logger) Operatio
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainOperation.SysOperationRecord, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainOperation.SysOperationRecord, error) {
 	var apis []SysOperationRecord
-	if err := r.DB.Find(&apis).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&apis).Error; err != nil {
 		r.Logger.Error("Error getting all apis", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -79,10 +86,10 @@ func (r *Repository) GetAll() (*[]domainOperation.SysOperationRecord, error) {
 	return arrayToDomainMapper(&apis), nil
 }
 
-func (r *Repository) Create(apiDomain *domainOperation.SysOperationRecord) (*domainOperation.SysOperationRecord, error) {
+func (r *Repository) Create(ctx context.Context, apiDomain *domainOperation.SysOperationRecord) (*domainOperation.SysOperationRecord, error) {
 	r.Logger.Info("Creating new api", zap.String("path", apiDomain.Path))
 	apiRepository := fromDomainMapper(apiDomain)
-	txDb := r.DB.Create(apiRepository)
+	txDb := r.DB.WithContext(ctx).Create(apiRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating api", zap.Error(err), zap.String("Path", apiDomain.Path))
@@ -104,9 +111,72 @@ func (r *Repository) Create(apiDomain *domainOperation.SysOperationRecord) (*dom
 	return apiRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainOperation.SysOperationRecord, error) {
+// CreateBatch inserts the records with a single statement
+func (r *Repository) CreateBatch(ctx context.Context, records []*domainOperation.SysOperationRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	rows := make([]*SysOperationRecord, len(records))
+	for i, record := range records {
+		rows[i] = fromDomainMapper(record)
+	}
+	if err := r.DB.WithContext(ctx).Create(&rows).Error; err != nil {
+		r.Logger.Error("Error creating operation records", zap.Error(err), zap.Int("count", len(rows)))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return nil
+}
+
+// FindBefore returns up to limit records created before the given time with an id greater than
+// afterID, ordered by id so callers can page through the table
+func (r *Repository) FindBefore(ctx context.Context, before time.Time, afterID int, limit int) (*[]domainOperation.SysOperationRecord, error) {
+	var records []SysOperationRecord
+	err := r.DB.WithContext(ctx).Where("created_at < ? AND id > ?", before, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		r.Logger.Error("Error finding operation records", zap.Error(err), zap.Time("before", before))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return arrayToDomainMapper(&records), nil
+}
+
+// Purge deletes the records permanently, used once they are archived
+func (r *Repository) Purge(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.DB.WithContext(ctx).Unscoped().Where("id IN ?", ids).Delete(&SysOperationRecord{}).Error; err != nil {
+		r.Logger.Error("Error purging operation records", zap.Error(err), zap.Int("count", len(ids)))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return nil
+}
+
+// StreamByDateRange calls fn for every record created in [start, end) in id order, loading
+// the table in batches so large ranges never sit in memory at once
+func (r *Repository) StreamByDateRange(ctx context.Context, start, end time.Time, fn func(record *domainOperation.SysOperationRecord) error) error {
+	var batch []SysOperationRecord
+	result := r.DB.WithContext(ctx).Where("created_at >= ? AND created_at < ?", start, end).
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				if err := fn(batch[i].toDomainMapper()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	if result.Error != nil {
+		r.Logger.Error("Error streaming operation records", zap.Error(result.Error))
+		return result.Error
+	}
+	return nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainOperation.SysOperationRecord, error) {
 	var api SysOperationRecord
-	err := r.DB.Where("id = ?", id).First(&api).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&api).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("Operation not found", zap.Int("id", id))
@@ -121,11 +191,11 @@ func (r *Repository) GetByID(id int) (*domainOperation.SysOperationRecord, error
 	return api.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainOperation.SysOperationRecord, error) {
+func (r *Repository) Update(ctx context.Context, id int, apiMap map[string]interface{}) (*domainOperation.SysOperationRecord, error) {
 	var apiObj SysOperationRecord
 	apiObj.ID = id
 	delete(apiMap, "updated_at")
-	err := r.DB.Model(&apiObj).
+	err := r.DB.WithContext(ctx).Model(&apiObj).
 		Select("api_name", "email", "nick_name", "status", "phone", "header_img").
 		Updates(apiMap).Error
 	if err != nil {
@@ -143,7 +213,7 @@ func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainOpera
 			return &domainOperation.SysOperationRecord{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&apiObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&apiObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated api", zap.Error(err), zap.Int("id", id))
 		return &domainOperation.SysOperationRecord{}, err
 	}
@@ -151,8 +221,8 @@ func (r *Repository) Update(id int, apiMap map[string]interface{}) (*domainOpera
 	return apiObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(ids []int) error {
-	tx := r.DB.Where("id IN ?", ids).Delete(&SysOperationRecord{})
+func (r *Repository) Delete(ctx context.Context, ids []int) error {
+	tx := r.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&SysOperationRecord{})
 
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting records", zap.Error(tx.Error), zap.Ints("ids", ids))
@@ -168,8 +238,8 @@ func (r *Repository) Delete(ids []int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainOperation.SysOperationRecord], error) {
-	query := r.DB.Model(&SysOperationRecord{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainOperation.SysOperationRecord], error) {
+	query := r.DB.WithContext(ctx).Model(&SysOperationRecord{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -256,7 +326,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsOperationMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -264,7 +334,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysOperationRecord{}).
+	if err := r.DB.WithContext(ctx).Model(&SysOperationRecord{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -291,6 +361,9 @@ func (u *SysOperationRecord) toDomainMapper() *domainOperation.SysOperationRecor
 		Agent:        u.Agent,
 		ErrorMessage: u.ErrorMessage,
 		Body:         u.Body,
+		Resp:         u.Resp,
+		UserID:       u.UserID,
+		RequestID:    u.RequestID,
 
 		CreatedAt: domain.CustomTime{Time: u.CreatedAt},
 		UpdatedAt: domain.CustomTime{Time: u.UpdatedAt},
@@ -305,9 +378,9 @@ func arrayToDomainMapper(apis *[]SysOperationRecord) *[]domainOperation.SysOpera
 	return &apisDomain
 }
 
-func (r *Repository) GetOneByMap(apiMap map[string]interface{}) (*domainOperation.SysOperationRecord, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, apiMap map[string]interface{}) (*domainOperation.SysOperationRecord, error) {
 	var apiRepository SysOperationRecord
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range apiMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
@@ -332,5 +405,6 @@ func fromDomainMapper(u *domainOperation.SysOperationRecord) *SysOperationRecord
 		Body:         u.Body,
 		Resp:         u.Resp,
 		UserID:       u.UserID,
+		RequestID:    u.RequestID,
 	}
 }
@@ -0,0 +1,640 @@
+package rbac
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	rbacDomain "github.com/gbrayhan/microservices-go/src/domain/sys/rbac"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	apiRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/api"
+	menuRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu"
+	menuBtnRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu_btn"
+	menuGroupRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu_group"
+	menuParamRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/base_menu_parameter"
+	casbinRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/casbin_rule"
+	menuBtnApiRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/menu_btn_api"
+	roleRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role"
+	roleBtnRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role_btn"
+	roleMenuRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role_menu"
+	userRoleRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/user_role"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IRbacRepository reads and writes the whole rbac configuration at once
+type IRbacRepository interface {
+	Export(ctx context.Context) (*rbacDomain.Document, error)
+	Apply(ctx context.Context, doc *rbacDomain.Document, prune bool) error
+}
+
+type Repository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewRbacRepository(db *gorm.DB, loggerInstance *logger.Logger) IRbacRepository {
+	return &Repository{DB: db, Logger: loggerInstance}
+}
+
+// Export builds a document from the current database state
+func (r *Repository) Export(ctx context.Context) (*rbacDomain.Document, error) {
+	var apis []apiRepo.SysApi
+	var groups []menuGroupRepo.SysBaseMenuGroups
+	var menus []menuRepo.SysBaseMenu
+	var btns []menuBtnRepo.SysBaseMenuBtn
+	var params []menuParamRepo.SysBaseMenuParameter
+	var btnApis []menuBtnApiRepo.SysMenuBtnApi
+	var roles []roleRepo.SysRole
+	var roleMenus []roleMenuRepo.SysRoleMenu
+	var roleBtns []roleBtnRepo.SysRoleBtn
+	var policies []casbinRepo.CasbinRule
+
+	loaders := []func() error{
+		func() error { return r.DB.WithContext(ctx).Order("path, method").Find(&apis).Error },
+		func() error { return r.DB.WithContext(ctx).Order("sort, id").Find(&groups).Error },
+		func() error { return r.DB.WithContext(ctx).Order("sort, id").Find(&menus).Error },
+		func() error { return r.DB.WithContext(ctx).Order("id").Find(&btns).Error },
+		func() error { return r.DB.WithContext(ctx).Order("id").Find(&params).Error },
+		func() error { return r.DB.WithContext(ctx).Find(&btnApis).Error },
+		func() error { return r.DB.WithContext(ctx).Order(`"order", id`).Find(&roles).Error },
+		func() error { return r.DB.WithContext(ctx).Find(&roleMenus).Error },
+		func() error { return r.DB.WithContext(ctx).Find(&roleBtns).Error },
+		func() error {
+			return r.DB.WithContext(ctx).Where("ptype = ?", "p").Order("v1, v2").Find(&policies).Error
+		},
+	}
+	for _, load := range loaders {
+		if err := load(); err != nil {
+			r.Logger.Error("Error loading rbac configuration", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	doc := &rbacDomain.Document{
+		Version:    rbacDomain.DocumentVersion,
+		ExportedAt: time.Now(),
+		Apis:       make([]rbacDomain.Api, 0, len(apis)),
+		MenuGroups: make([]rbacDomain.MenuGroup, 0, len(groups)),
+		Menus:      make([]rbacDomain.Menu, 0, len(menus)),
+		Roles:      make([]rbacDomain.Role, 0, len(roles)),
+	}
+
+	apiRefs := make(map[int64]rbacDomain.ApiRef, len(apis))
+	for _, api := range apis {
+		apiRefs[int64(api.ID)] = rbacDomain.ApiRef{Path: api.Path, Method: api.Method}
+		doc.Apis = append(doc.Apis, rbacDomain.Api{
+			Path:        api.Path,
+			Method:      api.Method,
+			ApiGroup:    api.ApiGroup,
+			Description: api.Description,
+		})
+	}
+
+	groupNames := make(map[int]string, len(groups))
+	for _, group := range groups {
+		groupNames[group.ID] = group.Name
+		doc.MenuGroups = append(doc.MenuGroups, rbacDomain.MenuGroup{
+			Name:   group.Name,
+			Path:   group.Path,
+			Sort:   group.Sort,
+			Status: group.Status,
+		})
+	}
+
+	btnApiRefs := make(map[int64][]rbacDomain.ApiRef)
+	for _, btnApi := range btnApis {
+		if ref, ok := apiRefs[btnApi.SysApiID]; ok {
+			btnApiRefs[btnApi.SysBaseMenuBtnID] = append(btnApiRefs[btnApi.SysBaseMenuBtnID], ref)
+		}
+	}
+	menuBtns := make(map[int64][]rbacDomain.MenuBtn)
+	btnNames := make(map[int64]string, len(btns))
+	for _, btn := range btns {
+		btnNames[int64(btn.ID)] = btn.Name
+		menuBtns[btn.SysBaseMenuID] = append(menuBtns[btn.SysBaseMenuID], rbacDomain.MenuBtn{
+			Name: btn.Name,
+			Desc: btn.Desc,
+			Apis: btnApiRefs[int64(btn.ID)],
+		})
+	}
+	menuParams := make(map[int64][]rbacDomain.MenuParameter)
+	for _, param := range params {
+		menuParams[param.SysBaseMenuID] = append(menuParams[param.SysBaseMenuID], rbacDomain.MenuParameter{
+			Type:  param.Type,
+			Key:   param.Key,
+			Value: param.Value,
+		})
+	}
+
+	menuNames := make(map[int]string, len(menus))
+	for _, menu := range menus {
+		menuNames[menu.ID] = menu.Name
+	}
+	for _, menu := range menus {
+		doc.Menus = append(doc.Menus, rbacDomain.Menu{
+			Name:       menu.Name,
+			Parent:     menuNames[menu.ParentID],
+			Group:      groupNames[menu.MenuGroupId],
+			Path:       menu.Path,
+			MenuLevel:  menu.MenuLevel,
+			Hidden:     menu.Hidden,
+			Component:  menu.Component,
+			Sort:       menu.Sort,
+			KeepAlive:  menu.KeepAlive,
+			Title:      menu.Title,
+			Icon:       menu.Icon,
+			Buttons:    menuBtns[int64(menu.ID)],
+			Parameters: menuParams[int64(menu.ID)],
+		})
+	}
+
+	roleNames := make(map[int64]string, len(roles))
+	for _, role := range roles {
+		roleNames[role.ID] = role.Name
+	}
+	roleMenuNames := make(map[int64][]string)
+	for _, roleMenu := range roleMenus {
+		if name, ok := menuNames[int(roleMenu.SysBaseMenuID)]; ok {
+			roleId := int64(roleMenu.SysRoleID)
+			roleMenuNames[roleId] = append(roleMenuNames[roleId], name)
+		}
+	}
+	roleButtons := make(map[int64][]rbacDomain.ButtonRef)
+	for _, roleBtn := range roleBtns {
+		menuName, menuOk := menuNames[int(roleBtn.SysMenuID)]
+		btnName, btnOk := btnNames[roleBtn.SysBaseMenuBtnID]
+		if menuOk && btnOk {
+			roleButtons[roleBtn.RoleID] = append(roleButtons[roleBtn.RoleID], rbacDomain.ButtonRef{Menu: menuName, Button: btnName})
+		}
+	}
+	rolePolicies := make(map[int64][]rbacDomain.ApiRef)
+	for _, policy := range policies {
+		roleId, err := strconv.ParseInt(policy.V0, 10, 64)
+		if err != nil {
+			continue
+		}
+		rolePolicies[roleId] = append(rolePolicies[roleId], rbacDomain.ApiRef{Path: policy.V1, Method: policy.V2})
+	}
+	for _, role := range roles {
+		doc.Roles = append(doc.Roles, rbacDomain.Role{
+			Name:          role.Name,
+			Parent:        roleNames[role.ParentID],
+			DefaultRouter: role.DefaultRouter,
+			Status:        role.Status,
+			Order:         role.Order,
+			Label:         role.Label,
+			Description:   role.Description,
+			Menus:         roleMenuNames[role.ID],
+			Buttons:       roleButtons[role.ID],
+			Policies:      rolePolicies[role.ID],
+		})
+	}
+
+	r.Logger.Info("Successfully exported rbac configuration",
+		zap.Int("apis", len(doc.Apis)),
+		zap.Int("menus", len(doc.Menus)),
+		zap.Int("roles", len(doc.Roles)))
+	return doc, nil
+}
+
+// Apply writes the document in a single transaction, matching rows by natural keys
+func (r *Repository) Apply(ctx context.Context, doc *rbacDomain.Document, prune bool) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		apiIds, err := applyApis(tx, doc.Apis, prune)
+		if err != nil {
+			return err
+		}
+		groupIds, err := applyMenuGroups(tx, doc.MenuGroups, prune)
+		if err != nil {
+			return err
+		}
+		menuIds, err := applyMenus(tx, doc.Menus, groupIds, prune)
+		if err != nil {
+			return err
+		}
+		btnIds, err := applyMenuChildren(tx, doc.Menus, menuIds, apiIds, prune)
+		if err != nil {
+			return err
+		}
+		return applyRoles(tx, doc.Roles, menuIds, btnIds, prune)
+	})
+	if err != nil {
+		r.Logger.Error("Error applying rbac configuration", zap.Error(err))
+		return err
+	}
+	r.Logger.Info("Successfully applied rbac configuration", zap.Bool("prune", prune))
+	return nil
+}
+
+func applyApis(tx *gorm.DB, apis []rbacDomain.Api, prune bool) (map[string]int, error) {
+	var existing []apiRepo.SysApi
+	if err := tx.Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	existingMap := make(map[string]apiRepo.SysApi, len(existing))
+	for _, api := range existing {
+		existingMap[rbacDomain.ApiRef{Path: api.Path, Method: api.Method}.Key()] = api
+	}
+	ids := make(map[string]int, len(apis))
+	for _, api := range apis {
+		key := rbacDomain.ApiRef{Path: api.Path, Method: api.Method}.Key()
+		if current, ok := existingMap[key]; ok {
+			if err := tx.Model(&apiRepo.SysApi{}).Where("id = ?", current.ID).Updates(map[string]any{
+				"api_group":   api.ApiGroup,
+				"description": api.Description,
+			}).Error; err != nil {
+				return nil, err
+			}
+			ids[key] = current.ID
+			continue
+		}
+		model := &apiRepo.SysApi{
+			Path:        api.Path,
+			Method:      api.Method,
+			ApiGroup:    api.ApiGroup,
+			Description: api.Description,
+		}
+		if err := tx.Create(model).Error; err != nil {
+			return nil, err
+		}
+		ids[key] = model.ID
+	}
+	if prune {
+		removed := make([]int, 0)
+		for key, api := range existingMap {
+			if _, ok := ids[key]; !ok {
+				removed = append(removed, api.ID)
+			}
+		}
+		if len(removed) > 0 {
+			if err := tx.Where("sys_api_id IN ?", removed).Delete(&menuBtnApiRepo.SysMenuBtnApi{}).Error; err != nil {
+				return nil, err
+			}
+			if err := tx.Where("id IN ?", removed).Delete(&apiRepo.SysApi{}).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ids, nil
+}
+
+func applyMenuGroups(tx *gorm.DB, groups []rbacDomain.MenuGroup, prune bool) (map[string]int, error) {
+	var existing []menuGroupRepo.SysBaseMenuGroups
+	if err := tx.Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	existingMap := make(map[string]menuGroupRepo.SysBaseMenuGroups, len(existing))
+	for _, group := range existing {
+		existingMap[group.Name] = group
+	}
+	ids := make(map[string]int, len(groups))
+	for _, group := range groups {
+		if current, ok := existingMap[group.Name]; ok {
+			if err := tx.Model(&menuGroupRepo.SysBaseMenuGroups{}).Where("id = ?", current.ID).Updates(map[string]any{
+				"path":   group.Path,
+				"sort":   group.Sort,
+				"status": group.Status,
+			}).Error; err != nil {
+				return nil, err
+			}
+			ids[group.Name] = current.ID
+			continue
+		}
+		model := &menuGroupRepo.SysBaseMenuGroups{
+			Name:   group.Name,
+			Path:   group.Path,
+			Sort:   group.Sort,
+			Status: group.Status,
+		}
+		if err := tx.Omit("MenuItems").Create(model).Error; err != nil {
+			return nil, err
+		}
+		ids[group.Name] = model.ID
+	}
+	if prune {
+		removed := make([]int, 0)
+		for name, group := range existingMap {
+			if _, ok := ids[name]; !ok {
+				removed = append(removed, group.ID)
+			}
+		}
+		if len(removed) > 0 {
+			if err := tx.Where("id IN ?", removed).Delete(&menuGroupRepo.SysBaseMenuGroups{}).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ids, nil
+}
+
+func applyMenus(tx *gorm.DB, menus []rbacDomain.Menu, groupIds map[string]int, prune bool) (map[string]int, error) {
+	var existing []menuRepo.SysBaseMenu
+	if err := tx.Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	existingMap := make(map[string]menuRepo.SysBaseMenu, len(existing))
+	for _, menu := range existing {
+		existingMap[menu.Name] = menu
+	}
+	ids := make(map[string]int, len(menus))
+	for _, menu := range menus {
+		values := map[string]any{
+			"path":          menu.Path,
+			"menu_level":    menu.MenuLevel,
+			"hidden":        menu.Hidden,
+			"component":     menu.Component,
+			"sort":          menu.Sort,
+			"keep_alive":    menu.KeepAlive,
+			"title":         menu.Title,
+			"icon":          menu.Icon,
+			"menu_group_id": groupIds[menu.Group],
+		}
+		if current, ok := existingMap[menu.Name]; ok {
+			if err := tx.Model(&menuRepo.SysBaseMenu{}).Where("id = ?", current.ID).Updates(values).Error; err != nil {
+				return nil, err
+			}
+			ids[menu.Name] = current.ID
+			continue
+		}
+		model := &menuRepo.SysBaseMenu{
+			Name:        menu.Name,
+			Path:        menu.Path,
+			MenuLevel:   menu.MenuLevel,
+			Hidden:      menu.Hidden,
+			Component:   menu.Component,
+			Sort:        menu.Sort,
+			KeepAlive:   menu.KeepAlive,
+			Title:       menu.Title,
+			Icon:        menu.Icon,
+			MenuGroupId: groupIds[menu.Group],
+		}
+		if err := tx.Omit("MenuBtns", "MenuParameters").Create(model).Error; err != nil {
+			return nil, err
+		}
+		ids[menu.Name] = model.ID
+	}
+	// parents are resolved once every menu has an id
+	for _, menu := range menus {
+		if err := tx.Model(&menuRepo.SysBaseMenu{}).Where("id = ?", ids[menu.Name]).
+			Update("parent_id", ids[menu.Parent]).Error; err != nil {
+			return nil, err
+		}
+	}
+	if prune {
+		removed := make([]int, 0)
+		for name, menu := range existingMap {
+			if _, ok := ids[name]; !ok {
+				removed = append(removed, menu.ID)
+			}
+		}
+		if len(removed) > 0 {
+			if err := tx.Where("sys_base_menu_id IN ?", removed).Delete(&roleMenuRepo.SysRoleMenu{}).Error; err != nil {
+				return nil, err
+			}
+			if err := tx.Where("id IN ?", removed).Delete(&menuRepo.SysBaseMenu{}).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ids, nil
+}
+
+// applyMenuChildren writes buttons, their api bindings and parameters, returning button ids keyed by "menu/button"
+func applyMenuChildren(tx *gorm.DB, menus []rbacDomain.Menu, menuIds map[string]int, apiIds map[string]int, prune bool) (map[string]int64, error) {
+	childKey := func(menuId int64, key string) string {
+		return strconv.FormatInt(menuId, 10) + "/" + key
+	}
+
+	var existingBtns []menuBtnRepo.SysBaseMenuBtn
+	if err := tx.Find(&existingBtns).Error; err != nil {
+		return nil, err
+	}
+	btnMap := make(map[string]menuBtnRepo.SysBaseMenuBtn, len(existingBtns))
+	for _, btn := range existingBtns {
+		btnMap[childKey(btn.SysBaseMenuID, btn.Name)] = btn
+	}
+	var existingParams []menuParamRepo.SysBaseMenuParameter
+	if err := tx.Find(&existingParams).Error; err != nil {
+		return nil, err
+	}
+	paramMap := make(map[string]menuParamRepo.SysBaseMenuParameter, len(existingParams))
+	for _, param := range existingParams {
+		paramMap[childKey(param.SysBaseMenuID, param.Type+":"+param.Key)] = param
+	}
+
+	btnIds := make(map[string]int64)
+	keptBtns := make(map[int]bool)
+	keptParams := make(map[int]bool)
+	for _, menu := range menus {
+		menuId := int64(menuIds[menu.Name])
+		for _, btn := range menu.Buttons {
+			key := rbacDomain.ButtonRef{Menu: menu.Name, Button: btn.Name}.Key()
+			if current, ok := btnMap[childKey(menuId, btn.Name)]; ok {
+				if err := tx.Model(&menuBtnRepo.SysBaseMenuBtn{}).Where("id = ?", current.ID).
+					Update("desc", btn.Desc).Error; err != nil {
+					return nil, err
+				}
+				btnIds[key] = int64(current.ID)
+				keptBtns[current.ID] = true
+			} else {
+				model := &menuBtnRepo.SysBaseMenuBtn{Name: btn.Name, Desc: btn.Desc, SysBaseMenuID: menuId}
+				if err := tx.Create(model).Error; err != nil {
+					return nil, err
+				}
+				btnIds[key] = int64(model.ID)
+			}
+
+			if err := tx.Where("sys_base_menu_btn_id = ?", btnIds[key]).Delete(&menuBtnApiRepo.SysMenuBtnApi{}).Error; err != nil {
+				return nil, err
+			}
+			btnApis := make([]menuBtnApiRepo.SysMenuBtnApi, 0, len(btn.Apis))
+			for _, ref := range btn.Apis {
+				if apiId, ok := apiIds[ref.Key()]; ok {
+					btnApis = append(btnApis, menuBtnApiRepo.SysMenuBtnApi{SysBaseMenuBtnID: btnIds[key], SysApiID: int64(apiId)})
+				}
+			}
+			if len(btnApis) > 0 {
+				if err := tx.Create(&btnApis).Error; err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, param := range menu.Parameters {
+			if current, ok := paramMap[childKey(menuId, param.Type+":"+param.Key)]; ok {
+				if err := tx.Model(&menuParamRepo.SysBaseMenuParameter{}).Where("id = ?", current.ID).
+					Update("value", param.Value).Error; err != nil {
+					return nil, err
+				}
+				keptParams[current.ID] = true
+				continue
+			}
+			model := &menuParamRepo.SysBaseMenuParameter{SysBaseMenuID: menuId, Type: param.Type, Key: param.Key, Value: param.Value}
+			if err := tx.Create(model).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if prune {
+		removedBtns := make([]int, 0)
+		for _, btn := range existingBtns {
+			if !keptBtns[btn.ID] {
+				removedBtns = append(removedBtns, btn.ID)
+			}
+		}
+		if len(removedBtns) > 0 {
+			if err := tx.Where("sys_base_menu_btn_id IN ?", removedBtns).Delete(&menuBtnApiRepo.SysMenuBtnApi{}).Error; err != nil {
+				return nil, err
+			}
+			if err := tx.Where("sys_base_menu_btn_id IN ?", removedBtns).Delete(&roleBtnRepo.SysRoleBtn{}).Error; err != nil {
+				return nil, err
+			}
+			if err := tx.Where("id IN ?", removedBtns).Delete(&menuBtnRepo.SysBaseMenuBtn{}).Error; err != nil {
+				return nil, err
+			}
+		}
+		removedParams := make([]int, 0)
+		for _, param := range existingParams {
+			if !keptParams[param.ID] {
+				removedParams = append(removedParams, param.ID)
+			}
+		}
+		if len(removedParams) > 0 {
+			if err := tx.Where("id IN ?", removedParams).Delete(&menuParamRepo.SysBaseMenuParameter{}).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+	return btnIds, nil
+}
+
+func applyRoles(tx *gorm.DB, roles []rbacDomain.Role, menuIds map[string]int, btnIds map[string]int64, prune bool) error {
+	var existing []roleRepo.SysRole
+	if err := tx.Find(&existing).Error; err != nil {
+		return err
+	}
+	existingMap := make(map[string]roleRepo.SysRole, len(existing))
+	for _, role := range existing {
+		existingMap[role.Name] = role
+	}
+	ids := make(map[string]int64, len(roles))
+	for _, role := range roles {
+		if current, ok := existingMap[role.Name]; ok {
+			if err := tx.Model(&roleRepo.SysRole{}).Where("id = ?", current.ID).Updates(map[string]any{
+				"default_router": role.DefaultRouter,
+				"status":         role.Status,
+				"order":          role.Order,
+				"label":          role.Label,
+				"description":    role.Description,
+			}).Error; err != nil {
+				return err
+			}
+			ids[role.Name] = current.ID
+			continue
+		}
+		model := &roleRepo.SysRole{
+			Name:          role.Name,
+			DefaultRouter: role.DefaultRouter,
+			Status:        role.Status,
+			Order:         role.Order,
+			Label:         role.Label,
+			Description:   role.Description,
+		}
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		ids[role.Name] = model.ID
+	}
+
+	for _, role := range roles {
+		roleId := ids[role.Name]
+		if err := tx.Model(&roleRepo.SysRole{}).Where("id = ?", roleId).
+			Update("parent_id", ids[role.Parent]).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("sys_role_id = ?", roleId).Delete(&roleMenuRepo.SysRoleMenu{}).Error; err != nil {
+			return err
+		}
+		roleMenus := make([]roleMenuRepo.SysRoleMenu, 0, len(role.Menus))
+		for _, menu := range role.Menus {
+			if menuId, ok := menuIds[menu]; ok {
+				roleMenus = append(roleMenus, roleMenuRepo.SysRoleMenu{SysBaseMenuID: uint64(menuId), SysRoleID: uint64(roleId)})
+			}
+		}
+		if len(roleMenus) > 0 {
+			if err := tx.Create(&roleMenus).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("role_id = ?", roleId).Delete(&roleBtnRepo.SysRoleBtn{}).Error; err != nil {
+			return err
+		}
+		roleBtns := make([]roleBtnRepo.SysRoleBtn, 0, len(role.Buttons))
+		for _, btn := range role.Buttons {
+			btnId, btnOk := btnIds[btn.Key()]
+			menuId, menuOk := menuIds[btn.Menu]
+			if btnOk && menuOk {
+				roleBtns = append(roleBtns, roleBtnRepo.SysRoleBtn{RoleID: roleId, SysMenuID: int64(menuId), SysBaseMenuBtnID: btnId})
+			}
+		}
+		if len(roleBtns) > 0 {
+			if err := tx.Create(&roleBtns).Error; err != nil {
+				return err
+			}
+		}
+
+		v0 := strconv.FormatInt(roleId, 10)
+		if err := tx.Where("ptype = ? AND v0 = ?", "p", v0).Delete(&casbinRepo.CasbinRule{}).Error; err != nil {
+			return err
+		}
+		policies := make([]casbinRepo.CasbinRule, 0, len(role.Policies))
+		for _, policy := range role.Policies {
+			policies = append(policies, casbinRepo.CasbinRule{PType: "p", V0: v0, V1: policy.Path, V2: policy.Method})
+		}
+		if len(policies) > 0 {
+			if err := tx.Create(&policies).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if prune {
+		removed := make([]int64, 0)
+		for name, role := range existingMap {
+			if _, ok := ids[name]; !ok {
+				removed = append(removed, role.ID)
+			}
+		}
+		if len(removed) > 0 {
+			return deleteRoles(tx, removed)
+		}
+	}
+	return nil
+}
+
+// deleteRoles removes the roles with everything that refers to them, users included, so no
+// assignment is left pointing at a missing role
+func deleteRoles(tx *gorm.DB, removed []int64) error {
+	removedV0 := make([]string, len(removed))
+	for i, id := range removed {
+		removedV0[i] = strconv.FormatInt(id, 10)
+	}
+	if err := tx.Where("sys_role_id IN ?", removed).Delete(&roleMenuRepo.SysRoleMenu{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("role_id IN ?", removed).Delete(&roleBtnRepo.SysRoleBtn{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("sys_role_id IN ?", removed).Delete(&userRoleRepo.SysUserRole{}).Error; err != nil {
+		return err
+	}
+	// the model has no role inheritance, grouping rules are only cleared in case some were added by hand
+	if err := tx.Where("(ptype = ? AND v0 IN ?) OR (ptype = ? AND (v0 IN ? OR v1 IN ?))",
+		"p", removedV0, "g", removedV0, removedV0).Delete(&casbinRepo.CasbinRule{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", removed).Delete(&roleRepo.SysRole{}).Error
+}
@@ -0,0 +1,40 @@
+package rbac
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// sqlCapture is a gorm logger keeping the statements with their variables inlined
+type sqlCapture struct {
+	gormLogger.Interface
+	statements []string
+}
+
+func (c *sqlCapture) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	c.statements = append(c.statements, sql)
+}
+
+func TestDeleteRolesRemovesAssignments(t *testing.T) {
+	capture := &sqlCapture{Interface: gormLogger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: capture})
+	require.NoError(t, err)
+
+	require.NoError(t, deleteRoles(db, []int64{4, 9}))
+
+	sql := strings.Join(capture.statements, "\n")
+	assert.Contains(t, sql, `DELETE FROM "sys_user_roles" WHERE sys_role_id IN (4,9)`)
+	assert.Contains(t, sql, `(ptype = 'p' AND v0 IN ('4','9')) OR (ptype = 'g' AND (v0 IN ('4','9') OR v1 IN ('4','9')))`)
+	assert.Contains(t, sql, `"sys_roles"`)
+	assert.Contains(t, sql, "id IN (4,9)")
+}
@@ -1,14 +1,17 @@
 package role
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
 	domainRole "github.com/gbrayhan/microservices-go/src/domain/sys/role"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	auditLogRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/utils"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -47,15 +50,15 @@ func (SysRole) TableName() string {
 }
 
 type ISysRolesRepository interface {
-	GetAll(status int) (*[]domainRole.Role, error)
-	Create(roleDomain *domainRole.Role) (*domainRole.Role, error)
-	GetByID(id int) (*domainRole.Role, error)
-	GetByName(name string) (*domainRole.Role, error)
-	Update(id int, roleMap map[string]interface{}) (*domainRole.Role, error)
-	Delete(id int) error
-	SearchPaginated(filters domain.DataFilters) (*domainRole.SearchResultRole, error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(roleMap map[string]interface{}) (*domainRole.Role, error)
+	GetAll(ctx context.Context, status int) (*[]domainRole.Role, error)
+	Create(ctx context.Context, roleDomain *domainRole.Role) (*domainRole.Role, error)
+	GetByID(ctx context.Context, id int) (*domainRole.Role, error)
+	GetByName(ctx context.Context, name string) (*domainRole.Role, error)
+	Update(ctx context.Context, id int, actorId int64, roleMap map[string]interface{}) (*domainRole.Role, error)
+	Delete(ctx context.Context, id int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domainRole.SearchResultRole, error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, roleMap map[string]interface{}) (*domainRole.Role, error)
 }
 
 type Repository struct {
@@ -67,9 +70,9 @@ func NewSysRolesRepository(db *gorm.DB, loggerInstance *logger.Logger) ISysRoles
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll(status int) (*[]domainRole.Role, error) {
+func (r *Repository) GetAll(ctx context.Context, status int) (*[]domainRole.Role, error) {
 	var roles []SysRole
-	tx := r.DB
+	tx := r.DB.WithContext(ctx)
 	if status != 0 {
 		tx = tx.Where("status = ?", status)
 	}
@@ -81,10 +84,10 @@ func (r *Repository) GetAll(status int) (*[]domainRole.Role, error) {
 	return ArrayToDomainMapper(&roles), nil
 }
 
-func (r *Repository) Create(roleDomain *domainRole.Role) (*domainRole.Role, error) {
+func (r *Repository) Create(ctx context.Context, roleDomain *domainRole.Role) (*domainRole.Role, error) {
 	r.Logger.Info("Creating new role", zap.String("Name", roleDomain.Name))
 	roleRepository := fromDomainMapper(roleDomain)
-	txDb := r.DB.Create(roleRepository)
+	txDb := r.DB.WithContext(ctx).Create(roleRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating role", zap.Error(err), zap.String("Name", roleDomain.Name))
@@ -106,9 +109,9 @@ func (r *Repository) Create(roleDomain *domainRole.Role) (*domainRole.Role, erro
 	return roleRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainRole.Role, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainRole.Role, error) {
 	var role SysRole
-	err := r.DB.Where("id = ?", id).First(&role).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&role).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("Role not found", zap.Int("id", id))
@@ -123,9 +126,9 @@ func (r *Repository) GetByID(id int) (*domainRole.Role, error) {
 	return role.toDomainMapper(), nil
 }
 
-func (r *Repository) GetByName(name string) (*domainRole.Role, error) {
+func (r *Repository) GetByName(ctx context.Context, name string) (*domainRole.Role, error) {
 	var role SysRole
-	err := r.DB.Where("name = ?", name).First(&role).Error
+	err := r.DB.WithContext(ctx).Where("name = ?", name).First(&role).Error
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -141,13 +144,19 @@ func (r *Repository) GetByName(name string) (*domainRole.Role, error) {
 	return role.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, roleMap map[string]interface{}) (*domainRole.Role, error) {
+func (r *Repository) Update(ctx context.Context, id int, actorId int64, roleMap map[string]interface{}) (*domainRole.Role, error) {
 	var roleObj SysRole
 	roleObj.ID = int64(id)
 	delete(roleMap, "updated_at")
-	err := r.DB.Model(&roleObj).Updates(roleMap).Error
+	var before SysRole
+	err := auditLogRepo.UpdateAudited(r.DB.WithContext(ctx), domainAuditLog.EntityRole, id, actorId, &before, &roleObj, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&roleObj).Updates(roleMap)
+	})
 	if err != nil {
 		r.Logger.Error("Error updating role", zap.Error(err), zap.Int("id", id))
+		if err == gorm.ErrRecordNotFound {
+			return &domainRole.Role{}, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
 		byteErr, _ := json.Marshal(err)
 		var newError domainErrors.GormErr
 		errUnmarshal := json.Unmarshal(byteErr, &newError)
@@ -161,16 +170,12 @@ func (r *Repository) Update(id int, roleMap map[string]interface{}) (*domainRole
 			return &domainRole.Role{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&roleObj).Error; err != nil {
-		r.Logger.Error("Error retrieving updated role", zap.Error(err), zap.Int("id", id))
-		return &domainRole.Role{}, err
-	}
 	r.Logger.Info("Successfully updated role", zap.Int("id", id))
 	return roleObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(id int) error {
-	tx := r.DB.Delete(&SysRole{}, id)
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	tx := r.DB.WithContext(ctx).Delete(&SysRole{}, id)
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting role", zap.Error(tx.Error), zap.Int("id", id))
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -183,8 +188,8 @@ func (r *Repository) Delete(id int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domainRole.SearchResultRole, error) {
-	query := r.DB.Model(&SysRole{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domainRole.SearchResultRole, error) {
+	query := r.DB.WithContext(ctx).Model(&SysRole{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -271,7 +276,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domainRole.Se
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsRoleMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -279,7 +284,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&SysRole{}).
+	if err := r.DB.WithContext(ctx).Model(&SysRole{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -330,9 +335,9 @@ func ArrayToDomainMapper(roles *[]SysRole) *[]domainRole.Role {
 	return &rolesDomain
 }
 
-func (r *Repository) GetOneByMap(roleMap map[string]interface{}) (*domainRole.Role, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, roleMap map[string]interface{}) (*domainRole.Role, error) {
 	var roleRepository SysRole
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range roleMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
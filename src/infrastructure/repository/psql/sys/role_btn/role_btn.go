@@ -1,6 +1,7 @@
 package role_btn
 
 import (
+	"context"
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"gorm.io/gorm"
@@ -19,8 +20,8 @@ func (SysRoleBtn) TableName() string {
 }
 
 type ISysRoleBtnRepository interface {
-	Insert(roleId int64, UpdateMap map[string]any) error
-	GetByRoleId(roleId int64) ([]*SysRoleBtn, error)
+	Insert(ctx context.Context, roleId int64, UpdateMap map[string]any) error
+	GetByRoleId(ctx context.Context, roleId int64) ([]*SysRoleBtn, error)
 }
 type Repository struct {
 	DB     *gorm.DB
@@ -31,9 +32,9 @@ func NewRoleBtnRepository(db *gorm.DB, loggerInstance *logger.Logger) ISysRoleBt
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetByRoleId(roleId int64) ([]*SysRoleBtn, error) {
+func (r *Repository) GetByRoleId(ctx context.Context, roleId int64) ([]*SysRoleBtn, error) {
 	var roleBtns []*SysRoleBtn
-	err := r.DB.Table(SysRoleBtn{}.TableName()).Where("role_id = ?", roleId).Find(&roleBtns).Error
+	err := r.DB.WithContext(ctx).Table(SysRoleBtn{}.TableName()).Where("role_id = ?", roleId).Find(&roleBtns).Error
 	if err != nil {
 		r.Logger.Error(err.Error())
 		return nil, err
@@ -42,7 +43,7 @@ func (r *Repository) GetByRoleId(roleId int64) ([]*SysRoleBtn, error) {
 }
 
 // Insert implements ISysRoleMenuRepository.
-func (r *Repository) Insert(roleId int64, UpdateMap map[string]any) error {
+func (r *Repository) Insert(ctx context.Context, roleId int64, UpdateMap map[string]any) error {
 	btnIdsInterface, ok := UpdateMap["btnIds"]
 	if !ok {
 		r.Logger.Error("btnIds not found in update map")
@@ -75,7 +76,7 @@ func (r *Repository) Insert(roleId int64, UpdateMap map[string]any) error {
 		}
 		roleBtns = append(roleBtns, roleMenu)
 	}
-	if err := r.DB.Model(&SysRoleBtn{}).
+	if err := r.DB.WithContext(ctx).Model(&SysRoleBtn{}).
 		Where("role_id = ?", roleId).
 		Where("sys_menu_id = ?", menuId).
 		Delete(&SysRoleBtn{}).Error; err != nil {
@@ -84,7 +85,7 @@ func (r *Repository) Insert(roleId int64, UpdateMap map[string]any) error {
 	if len(roleBtns) <= 0 {
 		return nil
 	}
-	if err := r.DB.Model(&SysRoleBtn{}).Create(&roleBtns).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&SysRoleBtn{}).Create(&roleBtns).Error; err != nil {
 		return err
 	}
 	return nil
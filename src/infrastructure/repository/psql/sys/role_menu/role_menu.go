@@ -1,6 +1,7 @@
 package role_menu
 
 import (
+	"context"
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"go.uber.org/zap"
@@ -23,8 +24,8 @@ var ColumnsRoleMapping = map[string]string{
 }
 
 type ISysRoleMenuRepository interface {
-	Insert(roleId int, UpdateMap map[string]any) error
-	GetByRoleId(roleId int64) ([]int, error)
+	Insert(ctx context.Context, roleId int, UpdateMap map[string]any) error
+	GetByRoleId(ctx context.Context, roleId int64) ([]int, error)
 }
 
 type Repository struct {
@@ -33,9 +34,9 @@ type Repository struct {
 }
 
 // GetByRoleId implements ISysRoleMenuRepository.
-func (r *Repository) GetByRoleId(roleId int64) ([]int, error) {
+func (r *Repository) GetByRoleId(ctx context.Context, roleId int64) ([]int, error) {
 	var roleMenus []SysRoleMenu
-	err := r.DB.Where("sys_role_id = ?", roleId).Find(&roleMenus).Error
+	err := r.DB.WithContext(ctx).Where("sys_role_id = ?", roleId).Find(&roleMenus).Error
 	if err != nil {
 		r.Logger.Error("Error getting all roles", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -48,7 +49,7 @@ func (r *Repository) GetByRoleId(roleId int64) ([]int, error) {
 }
 
 // Insert implements ISysRoleMenuRepository.
-func (r *Repository) Insert(roleId int, UpdateMap map[string]any) error {
+func (r *Repository) Insert(ctx context.Context, roleId int, UpdateMap map[string]any) error {
 	menuIdsInterface, ok := UpdateMap["menuIds"]
 	if !ok {
 		r.Logger.Error("menuIds not found in update map")
@@ -74,7 +75,7 @@ func (r *Repository) Insert(roleId int, UpdateMap map[string]any) error {
 		}
 		roleMenus = append(roleMenus, roleMenu)
 	}
-	if err := r.DB.Model(&SysRoleMenu{}).
+	if err := r.DB.WithContext(ctx).Model(&SysRoleMenu{}).
 		Where("sys_role_id = ?", roleId).
 		Delete(&SysRoleMenu{}).Error; err != nil {
 		return err
@@ -82,7 +83,7 @@ func (r *Repository) Insert(roleId int, UpdateMap map[string]any) error {
 	if len(roleMenus) <= 0 {
 		return nil
 	}
-	if err := r.DB.Model(&SysRoleMenu{}).Create(&roleMenus).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&SysRoleMenu{}).Create(&roleMenus).Error; err != nil {
 		return err
 	}
 	return nil
@@ -1,14 +1,17 @@
 package scheduled_task
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/gbrayhan/microservices-go/src/domain"
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
 	domainScheduledTask "github.com/gbrayhan/microservices-go/src/domain/sys/scheduled_task"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	auditLogRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
 	"go.uber.org/zap"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
@@ -41,13 +44,13 @@ var ColumnsScheduledTaskMapping = map[string]string{
 }
 
 type IScheduledTaskRepository interface {
-	GetAll() (*[]domainScheduledTask.ScheduledTask, error)
-	Create(domainScheduledTask *domainScheduledTask.ScheduledTask) (*domainScheduledTask.ScheduledTask, error)
-	GetByID(id int) (*domainScheduledTask.ScheduledTask, error)
-	Update(id int, taskMap map[string]interface{}) (*domainScheduledTask.ScheduledTask, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainScheduledTask.ScheduledTask], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
+	GetAll(ctx context.Context) (*[]domainScheduledTask.ScheduledTask, error)
+	Create(ctx context.Context, domainScheduledTask *domainScheduledTask.ScheduledTask) (*domainScheduledTask.ScheduledTask, error)
+	GetByID(ctx context.Context, id int) (*domainScheduledTask.ScheduledTask, error)
+	Update(ctx context.Context, id int, actorId int64, taskMap map[string]interface{}) (*domainScheduledTask.ScheduledTask, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainScheduledTask.ScheduledTask], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
 }
 type Repository struct {
 	DB     *gorm.DB
@@ -58,9 +61,9 @@ func NewScheduledTaskRepository(db *gorm.DB, loggerInstance *logger.Logger) ISch
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainScheduledTask.ScheduledTask, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainScheduledTask.ScheduledTask, error) {
 	var tasks []ScheduledTask
-	if err := r.DB.Find(&tasks).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&tasks).Error; err != nil {
 		r.Logger.Error("Error getting all tasks", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -68,10 +71,10 @@ func (r *Repository) GetAll() (*[]domainScheduledTask.ScheduledTask, error) {
 	return arrayToDomainMapper(&tasks), nil
 }
 
-func (r *Repository) Create(domainScheduledTask *domainScheduledTask.ScheduledTask) (*domainScheduledTask.ScheduledTask, error) {
+func (r *Repository) Create(ctx context.Context, domainScheduledTask *domainScheduledTask.ScheduledTask) (*domainScheduledTask.ScheduledTask, error) {
 	r.Logger.Info("Creating new task", zap.String("TaskName", domainScheduledTask.TaskName))
 	taskRepository := fromDomainMapper(domainScheduledTask)
-	txDb := r.DB.Create(taskRepository)
+	txDb := r.DB.WithContext(ctx).Create(taskRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating task", zap.Error(err), zap.String("TaskName", domainScheduledTask.TaskName))
@@ -93,9 +96,9 @@ func (r *Repository) Create(domainScheduledTask *domainScheduledTask.ScheduledTa
 	return taskRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainScheduledTask.ScheduledTask, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainScheduledTask.ScheduledTask, error) {
 	var task ScheduledTask
-	err := r.DB.Where("id = ?", id).First(&task).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&task).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("data not found", zap.Int("id", id))
@@ -110,13 +113,19 @@ func (r *Repository) GetByID(id int) (*domainScheduledTask.ScheduledTask, error)
 	return task.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, dataMap map[string]interface{}) (*domainScheduledTask.ScheduledTask, error) {
+func (r *Repository) Update(ctx context.Context, id int, actorId int64, dataMap map[string]interface{}) (*domainScheduledTask.ScheduledTask, error) {
 	var dataObj ScheduledTask
 	dataObj.ID = id
 	delete(dataMap, "updated_at")
-	err := r.DB.Model(&dataObj).Updates(dataMap).Error
+	var before ScheduledTask
+	err := auditLogRepo.UpdateAudited(r.DB.WithContext(ctx), domainAuditLog.EntityScheduledTask, id, actorId, &before, &dataObj, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&dataObj).Updates(dataMap)
+	})
 	if err != nil {
 		r.Logger.Error("Error updating task", zap.Error(err), zap.Int("id", id))
+		if err == gorm.ErrRecordNotFound {
+			return &domainScheduledTask.ScheduledTask{}, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
 		byteErr, _ := json.Marshal(err)
 		var newError domainErrors.GormErr
 		errUnmarshal := json.Unmarshal(byteErr, &newError)
@@ -130,16 +139,12 @@ func (r *Repository) Update(id int, dataMap map[string]interface{}) (*domainSche
 			return &domainScheduledTask.ScheduledTask{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&dataObj).Error; err != nil {
-		r.Logger.Error("Error retrieving updated task", zap.Error(err), zap.Int("id", id))
-		return &domainScheduledTask.ScheduledTask{}, err
-	}
 	r.Logger.Info("Successfully updated task", zap.Int("id", id))
 	return dataObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(ids []int) error {
-	tx := r.DB.Where("id IN ?", ids).Delete(&ScheduledTask{})
+func (r *Repository) Delete(ctx context.Context, ids []int) error {
+	tx := r.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&ScheduledTask{})
 
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting task", zap.Error(tx.Error), zap.String("ids", fmt.Sprintf("%v", ids)))
@@ -153,8 +158,8 @@ func (r *Repository) Delete(ids []int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainScheduledTask.ScheduledTask], error) {
-	query := r.DB.Model(&ScheduledTask{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainScheduledTask.ScheduledTask], error) {
+	query := r.DB.WithContext(ctx).Model(&ScheduledTask{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -241,7 +246,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsScheduledTaskMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -249,7 +254,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&ScheduledTask{}).
+	if err := r.DB.WithContext(ctx).Model(&ScheduledTask{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -1,6 +1,7 @@
 package task_execution_log
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -30,14 +31,14 @@ func (TaskExecutionLog) TableName() string {
 }
 
 type ITaskExecutionLogRepository interface {
-	GetAll() (*[]domainTaskExecution.TaskExecutionLog, error)
-	Create(logDomain *domainTaskExecution.TaskExecutionLog) (*domainTaskExecution.TaskExecutionLog, error)
-	GetByID(id int) (*domainTaskExecution.TaskExecutionLog, error)
-	Update(id int, apiMap map[string]interface{}) (*domainTaskExecution.TaskExecutionLog, error)
-	Delete(ids []int) error
-	SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainTaskExecution.TaskExecutionLog], error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetByTaskID(taskID uint, limit int) (*[]domainTaskExecution.TaskExecutionLog, error)
+	GetAll(ctx context.Context) (*[]domainTaskExecution.TaskExecutionLog, error)
+	Create(ctx context.Context, logDomain *domainTaskExecution.TaskExecutionLog) (*domainTaskExecution.TaskExecutionLog, error)
+	GetByID(ctx context.Context, id int) (*domainTaskExecution.TaskExecutionLog, error)
+	Update(ctx context.Context, id int, apiMap map[string]interface{}) (*domainTaskExecution.TaskExecutionLog, error)
+	Delete(ctx context.Context, ids []int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainTaskExecution.TaskExecutionLog], error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetByTaskID(ctx context.Context, taskID uint, limit int) (*[]domainTaskExecution.TaskExecutionLog, error)
 }
 
 var ColumnsTaskExecutionLogMapping = map[string]string{
@@ -53,9 +54,9 @@ func NewTaskExecutionLogRepository(db *gorm.DB, loggerInstance *logger.Logger) I
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainTaskExecution.TaskExecutionLog, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainTaskExecution.TaskExecutionLog, error) {
 	var tasks []TaskExecutionLog
-	if err := r.DB.Find(&tasks).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&tasks).Error; err != nil {
 		r.Logger.Error("Error getting all tasks", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -63,10 +64,10 @@ func (r *Repository) GetAll() (*[]domainTaskExecution.TaskExecutionLog, error) {
 	return arrayToDomainMapper(&tasks), nil
 }
 
-func (r *Repository) Create(domainTaskExecutionLog *domainTaskExecution.TaskExecutionLog) (*domainTaskExecution.TaskExecutionLog, error) {
+func (r *Repository) Create(ctx context.Context, domainTaskExecutionLog *domainTaskExecution.TaskExecutionLog) (*domainTaskExecution.TaskExecutionLog, error) {
 	r.Logger.Info("Creating new api", zap.Uint("TaskID", domainTaskExecutionLog.TaskID))
 	logRepository := fromDomainMapper(domainTaskExecutionLog)
-	txDb := r.DB.Create(logRepository)
+	txDb := r.DB.WithContext(ctx).Create(logRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating api", zap.Error(err), zap.Uint("TaskID", domainTaskExecutionLog.TaskID))
@@ -88,9 +89,9 @@ func (r *Repository) Create(domainTaskExecutionLog *domainTaskExecution.TaskExec
 	return logRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainTaskExecution.TaskExecutionLog, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainTaskExecution.TaskExecutionLog, error) {
 	var api TaskExecutionLog
-	err := r.DB.Where("id = ?", id).First(&api).Error
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&api).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("Api not found", zap.Int("id", id))
@@ -105,11 +106,11 @@ func (r *Repository) GetByID(id int) (*domainTaskExecution.TaskExecutionLog, err
 	return api.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, dataMap map[string]interface{}) (*domainTaskExecution.TaskExecutionLog, error) {
+func (r *Repository) Update(ctx context.Context, id int, dataMap map[string]interface{}) (*domainTaskExecution.TaskExecutionLog, error) {
 	var dataObj TaskExecutionLog
 	dataObj.ID = id
 	delete(dataMap, "updated_at")
-	err := r.DB.Model(&dataObj).Updates(dataMap).Error
+	err := r.DB.WithContext(ctx).Model(&dataObj).Updates(dataMap).Error
 	if err != nil {
 		r.Logger.Error("Error updating api", zap.Error(err), zap.Int("id", id))
 		byteErr, _ := json.Marshal(err)
@@ -125,7 +126,7 @@ func (r *Repository) Update(id int, dataMap map[string]interface{}) (*domainTask
 			return &domainTaskExecution.TaskExecutionLog{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&dataObj).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&dataObj).Error; err != nil {
 		r.Logger.Error("Error retrieving updated api", zap.Error(err), zap.Int("id", id))
 		return &domainTaskExecution.TaskExecutionLog{}, err
 	}
@@ -133,8 +134,8 @@ func (r *Repository) Update(id int, dataMap map[string]interface{}) (*domainTask
 	return dataObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(ids []int) error {
-	tx := r.DB.Where("id IN ?", ids).Delete(&TaskExecutionLog{})
+func (r *Repository) Delete(ctx context.Context, ids []int) error {
+	tx := r.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&TaskExecutionLog{})
 
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting api", zap.Error(tx.Error), zap.String("ids", fmt.Sprintf("%v", ids)))
@@ -148,8 +149,8 @@ func (r *Repository) Delete(ids []int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.PaginatedResult[domainTaskExecution.TaskExecutionLog], error) {
-	query := r.DB.Model(&TaskExecutionLog{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domain.PaginatedResult[domainTaskExecution.TaskExecutionLog], error) {
+	query := r.DB.WithContext(ctx).Model(&TaskExecutionLog{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -236,7 +237,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domain.Pagina
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsTaskExecutionLogMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -244,7 +245,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&TaskExecutionLog{}).
+	if err := r.DB.WithContext(ctx).Model(&TaskExecutionLog{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -261,9 +262,9 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 }
 
 // GetByTaskID implements ITaskExecutionLogRepository.
-func (r *Repository) GetByTaskID(taskID uint, limit int) (*[]domainTaskExecution.TaskExecutionLog, error) {
+func (r *Repository) GetByTaskID(ctx context.Context, taskID uint, limit int) (*[]domainTaskExecution.TaskExecutionLog, error) {
 	var tasks []TaskExecutionLog
-	if err := r.DB.Where("task_id = ?", taskID).Limit(limit).Find(&tasks).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("task_id = ?", taskID).Limit(limit).Find(&tasks).Error; err != nil {
 		r.Logger.Error("Error retrieving tasks by taskID", zap.Error(err), zap.Uint("taskID", taskID))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
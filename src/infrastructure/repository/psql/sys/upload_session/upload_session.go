@@ -0,0 +1,279 @@
+package upload_session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	filesDomain "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SysUploadSession struct {
+	ID            string    `gorm:"column:id;size:36;primaryKey"`
+	FileName      string    `gorm:"column:file_name;size:191;not null"`
+	FileSize      int64     `gorm:"column:file_size;not null"`
+	ContentType   string    `gorm:"column:content_type;size:191"`
+	FileMD5       string    `gorm:"column:file_md5;size:32"`
+	UploadOffset  int64     `gorm:"column:upload_offset;not null;default:0"`
+	Status        string    `gorm:"column:status;size:16;not null"`
+	FileID        *int64    `gorm:"column:file_id"`
+	CreatedBy     int64     `gorm:"column:created_by;index"`
+	CreatedByRole int64     `gorm:"column:created_by_role;not null;default:0"`
+	Visibility    string    `gorm:"column:visibility;size:10"`
+	ExpiresAt     time.Time `gorm:"column:expires_at;index"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (SysUploadSession) TableName() string {
+	return "sys_upload_sessions"
+}
+
+type SysUploadPart struct {
+	UploadID   string    `gorm:"column:upload_id;size:36;primaryKey"`
+	PartNumber int       `gorm:"column:part_number;primaryKey"`
+	Size       int64     `gorm:"column:size;not null"`
+	MD5        string    `gorm:"column:md5;size:32;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (SysUploadPart) TableName() string {
+	return "sys_upload_parts"
+}
+
+type IUploadSessionRepository interface {
+	Create(ctx context.Context, session *filesDomain.UploadSession) (*filesDomain.UploadSession, error)
+	GetByID(ctx context.Context, id string) (*filesDomain.UploadSession, error)
+	GetParts(ctx context.Context, id string) (*[]filesDomain.UploadPart, error)
+	// SavePart records or replaces a part of an uploading session and extends its expiry
+	SavePart(ctx context.Context, id string, part *filesDomain.UploadPart, expiresAt time.Time) (*filesDomain.UploadSession, error)
+	// AppendPart records a new part starting at offset, the session offset must match
+	AppendPart(ctx context.Context, id string, offset int64, part *filesDomain.UploadPart, expiresAt time.Time) (*filesDomain.UploadSession, error)
+	// Transition moves the session from one status to another, false when it wasn't in from
+	Transition(ctx context.Context, id string, from string, to string, fileID *int64, expiresAt time.Time) (bool, error)
+	Delete(ctx context.Context, id string) error
+	GetExpired(ctx context.Context, at time.Time, limit int) (*[]filesDomain.UploadSession, error)
+}
+
+type Repository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewUploadSessionRepository(db *gorm.DB, loggerInstance *logger.Logger) IUploadSessionRepository {
+	return &Repository{DB: db, Logger: loggerInstance}
+}
+
+// errNotUploading aborts the part transactions of a session that left the uploading status
+var errNotUploading = errors.New("session is not uploading")
+
+// Create implements IUploadSessionRepository.
+func (r *Repository) Create(ctx context.Context, session *filesDomain.UploadSession) (*filesDomain.UploadSession, error) {
+	model := fromDomainMapper(session)
+	if err := r.DB.WithContext(ctx).Create(model).Error; err != nil {
+		r.Logger.Error("Error creating upload session", zap.Error(err), zap.String("fileName", session.FileName))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return model.toDomainMapper(), nil
+}
+
+// GetByID implements IUploadSessionRepository.
+func (r *Repository) GetByID(ctx context.Context, id string) (*filesDomain.UploadSession, error) {
+	var model SysUploadSession
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		r.Logger.Error("Error getting upload session", zap.Error(err), zap.String("uploadId", id))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return model.toDomainMapper(), nil
+}
+
+// GetParts implements IUploadSessionRepository.
+func (r *Repository) GetParts(ctx context.Context, id string) (*[]filesDomain.UploadPart, error) {
+	var models []SysUploadPart
+	if err := r.DB.WithContext(ctx).Where("upload_id = ?", id).Order("part_number asc").Find(&models).Error; err != nil {
+		r.Logger.Error("Error getting upload parts", zap.Error(err), zap.String("uploadId", id))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	parts := make([]filesDomain.UploadPart, len(models))
+	for i := range models {
+		parts[i] = models[i].toDomainMapper()
+	}
+	return &parts, nil
+}
+
+// SavePart implements IUploadSessionRepository.
+func (r *Repository) SavePart(ctx context.Context, id string, part *filesDomain.UploadPart, expiresAt time.Time) (*filesDomain.UploadSession, error) {
+	var session SysUploadSession
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockUploading(tx, id, &session); err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "upload_id"}, {Name: "part_number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"size", "md5", "created_at"}),
+		}).Create(&SysUploadPart{UploadID: id, PartNumber: part.PartNumber, Size: part.Size, MD5: part.MD5}).Error
+		if err != nil {
+			return err
+		}
+		var offset int64
+		if err := tx.Model(&SysUploadPart{}).Where("upload_id = ?", id).
+			Select("COALESCE(SUM(size), 0)").Scan(&offset).Error; err != nil {
+			return err
+		}
+		// parts are numbered by the client, only their sum tells whether one was sent twice
+		if offset > session.FileSize {
+			return filesDomain.ErrUploadTooLarge
+		}
+		session.UploadOffset = offset
+		session.ExpiresAt = expiresAt
+		return tx.Model(&session).Updates(map[string]any{"upload_offset": offset, "expires_at": expiresAt}).Error
+	})
+	if err != nil {
+		return nil, r.partError(err, "Error saving upload part", id)
+	}
+	return session.toDomainMapper(), nil
+}
+
+// AppendPart implements IUploadSessionRepository.
+func (r *Repository) AppendPart(ctx context.Context, id string, offset int64, part *filesDomain.UploadPart, expiresAt time.Time) (*filesDomain.UploadSession, error) {
+	var session SysUploadSession
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockUploading(tx, id, &session); err != nil {
+			return err
+		}
+		if session.UploadOffset != offset {
+			return filesDomain.ErrUploadOffsetMismatch
+		}
+		if err := tx.Create(&SysUploadPart{UploadID: id, PartNumber: part.PartNumber, Size: part.Size, MD5: part.MD5}).Error; err != nil {
+			return err
+		}
+		session.UploadOffset += part.Size
+		session.ExpiresAt = expiresAt
+		return tx.Model(&session).Updates(map[string]any{"upload_offset": session.UploadOffset, "expires_at": expiresAt}).Error
+	})
+	if err != nil {
+		return nil, r.partError(err, "Error appending upload part", id)
+	}
+	return session.toDomainMapper(), nil
+}
+
+// lockUploading loads the session for update, parts are only accepted while it is uploading
+func lockUploading(tx *gorm.DB, id string, session *SysUploadSession) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(session).Error; err != nil {
+		return err
+	}
+	if session.Status != filesDomain.UploadStatusUploading {
+		return errNotUploading
+	}
+	return nil
+}
+
+func (r *Repository) partError(err error, message string, id string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	case errors.Is(err, errNotUploading):
+		return domainErrors.NewAppError(filesDomain.ErrUploadNotActive, domainErrors.ValidationError)
+	case errors.Is(err, filesDomain.ErrUploadOffsetMismatch):
+		return domainErrors.NewAppError(filesDomain.ErrUploadOffsetMismatch, domainErrors.ValidationError)
+	case errors.Is(err, filesDomain.ErrUploadTooLarge):
+		return domainErrors.NewAppError(filesDomain.ErrUploadTooLarge, domainErrors.ValidationError)
+	}
+	r.Logger.Error(message, zap.Error(err), zap.String("uploadId", id))
+	return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+}
+
+// Transition implements IUploadSessionRepository.
+func (r *Repository) Transition(ctx context.Context, id string, from string, to string, fileID *int64, expiresAt time.Time) (bool, error) {
+	tx := r.DB.WithContext(ctx).Model(&SysUploadSession{}).Where("id = ? AND status = ?", id, from).
+		Updates(map[string]any{"status": to, "file_id": fileID, "expires_at": expiresAt})
+	if tx.Error != nil {
+		r.Logger.Error("Error updating upload session status", zap.Error(tx.Error), zap.String("uploadId", id),
+			zap.String("from", from), zap.String("to", to))
+		return false, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+// Delete implements IUploadSessionRepository.
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("upload_id = ?", id).Delete(&SysUploadPart{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&SysUploadSession{}).Error
+	})
+	if err != nil {
+		r.Logger.Error("Error deleting upload session", zap.Error(err), zap.String("uploadId", id))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return nil
+}
+
+// GetExpired implements IUploadSessionRepository.
+func (r *Repository) GetExpired(ctx context.Context, at time.Time, limit int) (*[]filesDomain.UploadSession, error) {
+	var models []SysUploadSession
+	if err := r.DB.WithContext(ctx).Where("expires_at <= ?", at).Order("expires_at asc").Limit(limit).Find(&models).Error; err != nil {
+		r.Logger.Error("Error getting expired upload sessions", zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	sessions := make([]filesDomain.UploadSession, len(models))
+	for i := range models {
+		sessions[i] = *models[i].toDomainMapper()
+	}
+	return &sessions, nil
+}
+
+func (s *SysUploadSession) toDomainMapper() *filesDomain.UploadSession {
+	return &filesDomain.UploadSession{
+		ID:            s.ID,
+		FileName:      s.FileName,
+		FileSize:      s.FileSize,
+		ContentType:   s.ContentType,
+		FileMD5:       s.FileMD5,
+		Offset:        s.UploadOffset,
+		Status:        s.Status,
+		FileID:        s.FileID,
+		CreatedBy:     s.CreatedBy,
+		CreatedByRole: s.CreatedByRole,
+		Visibility:    s.Visibility,
+		ExpiresAt:     s.ExpiresAt,
+		CreatedAt:     s.CreatedAt,
+		UpdatedAt:     s.UpdatedAt,
+	}
+}
+
+func fromDomainMapper(s *filesDomain.UploadSession) *SysUploadSession {
+	return &SysUploadSession{
+		ID:            s.ID,
+		FileName:      s.FileName,
+		FileSize:      s.FileSize,
+		ContentType:   s.ContentType,
+		FileMD5:       s.FileMD5,
+		UploadOffset:  s.Offset,
+		Status:        s.Status,
+		FileID:        s.FileID,
+		CreatedBy:     s.CreatedBy,
+		CreatedByRole: s.CreatedByRole,
+		Visibility:    s.Visibility,
+		ExpiresAt:     s.ExpiresAt,
+	}
+}
+
+func (p *SysUploadPart) toDomainMapper() filesDomain.UploadPart {
+	return filesDomain.UploadPart{
+		PartNumber: p.PartNumber,
+		Size:       p.Size,
+		MD5:        p.MD5,
+		CreatedAt:  p.CreatedAt,
+	}
+}
@@ -1,17 +1,25 @@
 package user_role
 
 import (
+	"context"
 	"strconv"
+	"time"
 
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainUser "github.com/gbrayhan/microservices-go/src/domain/user"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SysUserRole struct {
-	SysUserID int64 `gorm:"column:sys_user_id;primaryKey" json:"sysUserId"`
-	SysRoleID int64 `gorm:"column:sys_role_id;primaryKey" json:"sysRoleId"`
+	SysUserID int64      `gorm:"column:sys_user_id;primaryKey" json:"sysUserId"`
+	SysRoleID int64      `gorm:"column:sys_role_id;primaryKey" json:"sysRoleId"`
+	StartAt   *time.Time `gorm:"column:start_at" json:"startAt"`
+	ExpiresAt *time.Time `gorm:"column:expires_at;index" json:"expiresAt"`
+	GrantedBy int64      `gorm:"column:granted_by" json:"grantedBy"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime:milli" json:"createdAt"`
 }
 
 func (SysUserRole) TableName() string {
@@ -19,8 +27,12 @@ func (SysUserRole) TableName() string {
 }
 
 type ISysUserRoleRepository interface {
-	Insert(userId int64, UpdateMap map[string]any) error
-	GetByUserId(userId int64) ([]int, error)
+	Insert(ctx context.Context, userId int64, grantedBy int64, UpdateMap map[string]any) error
+	GetByUserId(ctx context.Context, userId int64) ([]int, error)
+	GetBindingsByUserId(ctx context.Context, userId int64) (*[]domainUser.UserRole, error)
+	GetValidRoleIds(ctx context.Context, userId int64, at time.Time) ([]int64, error)
+	Grant(ctx context.Context, binding *domainUser.UserRole) error
+	RevokeExpired(ctx context.Context, at time.Time) (int64, error)
 }
 
 type Repository struct {
@@ -28,10 +40,15 @@ type Repository struct {
 	Logger *logger.Logger
 }
 
+// validAt restricts the query to bindings that have started and not yet expired at the given time
+func validAt(db *gorm.DB, at time.Time) *gorm.DB {
+	return db.Where("(start_at IS NULL OR start_at <= ?) AND (expires_at IS NULL OR expires_at > ?)", at, at)
+}
+
 // GetByRoleId implements ISysUserRoleRepository.
-func (r *Repository) GetByUserId(userId int64) ([]int, error) {
+func (r *Repository) GetByUserId(ctx context.Context, userId int64) ([]int, error) {
 	var userRoles []SysUserRole
-	err := r.DB.Where("sys_user_id = ?", userId).Find(&userRoles).Error
+	err := r.DB.WithContext(ctx).Where("sys_user_id = ?", userId).Find(&userRoles).Error
 	if err != nil {
 		r.Logger.Error("Error getting all roles", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -43,8 +60,75 @@ func (r *Repository) GetByUserId(userId int64) ([]int, error) {
 	return roleIds, nil
 }
 
+// GetBindingsByUserId implements ISysUserRoleRepository.
+func (r *Repository) GetBindingsByUserId(ctx context.Context, userId int64) (*[]domainUser.UserRole, error) {
+	var userRoles []SysUserRole
+	err := r.DB.WithContext(ctx).Where("sys_user_id = ?", userId).Order("sys_role_id asc").Find(&userRoles).Error
+	if err != nil {
+		r.Logger.Error("Error getting user role bindings", zap.Error(err), zap.Int64("userId", userId))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	now := time.Now()
+	bindings := make([]domainUser.UserRole, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		binding := userRole.toDomainMapper()
+		binding.Valid = binding.IsValidAt(now)
+		bindings = append(bindings, binding)
+	}
+	return &bindings, nil
+}
+
+// GetValidRoleIds implements ISysUserRoleRepository.
+func (r *Repository) GetValidRoleIds(ctx context.Context, userId int64, at time.Time) ([]int64, error) {
+	roleIds := make([]int64, 0)
+	err := validAt(r.DB.WithContext(ctx).Model(&SysUserRole{}), at).
+		Where("sys_user_id = ?", userId).
+		Pluck("sys_role_id", &roleIds).Error
+	if err != nil {
+		r.Logger.Error("Error getting valid user roles", zap.Error(err), zap.Int64("userId", userId))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return roleIds, nil
+}
+
+// Grant implements ISysUserRoleRepository.
+// An existing binding for the same user and role has its validity window and grantor replaced.
+func (r *Repository) Grant(ctx context.Context, binding *domainUser.UserRole) error {
+	userRole := SysUserRole{
+		SysUserID: binding.UserID,
+		SysRoleID: binding.RoleID,
+		StartAt:   binding.StartAt,
+		ExpiresAt: binding.ExpiresAt,
+		GrantedBy: binding.GrantedBy,
+	}
+	err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "sys_user_id"}, {Name: "sys_role_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"start_at", "expires_at", "granted_by"}),
+	}).Create(&userRole).Error
+	if err != nil {
+		r.Logger.Error("Error granting user role", zap.Error(err),
+			zap.Int64("userId", binding.UserID), zap.Int64("roleId", binding.RoleID))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	r.Logger.Info("Successfully granted user role",
+		zap.Int64("userId", binding.UserID), zap.Int64("roleId", binding.RoleID), zap.Int64("grantedBy", binding.GrantedBy))
+	return nil
+}
+
+// RevokeExpired implements ISysUserRoleRepository.
+func (r *Repository) RevokeExpired(ctx context.Context, at time.Time) (int64, error) {
+	tx := r.DB.WithContext(ctx).Where("expires_at IS NOT NULL AND expires_at <= ?", at).Delete(&SysUserRole{})
+	if tx.Error != nil {
+		r.Logger.Error("Error revoking expired user roles", zap.Error(tx.Error))
+		return 0, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	r.Logger.Info("Revoked expired user roles", zap.Int64("count", tx.RowsAffected))
+	return tx.RowsAffected, nil
+}
+
 // Insert implements ISysUserRoleRepository.
-func (r *Repository) Insert(userId int64, UpdateMap map[string]any) error {
+// Roles that stay bound keep their validity window, newly bound roles are permanent.
+func (r *Repository) Insert(ctx context.Context, userId int64, grantedBy int64, UpdateMap map[string]any) error {
 
 	roleIdsInterface, ok := UpdateMap["roleIds"]
 
@@ -57,6 +141,15 @@ func (r *Repository) Insert(userId int64, UpdateMap map[string]any) error {
 		r.Logger.Error("roleIds is not an array")
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
+	var existingRoles []SysUserRole
+	if err := r.DB.WithContext(ctx).Where("sys_user_id = ?", userId).Find(&existingRoles).Error; err != nil {
+		r.Logger.Error("Error getting existing user roles", zap.Error(err))
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	existing := make(map[int64]SysUserRole, len(existingRoles))
+	for _, userRole := range existingRoles {
+		existing[userRole.SysRoleID] = userRole
+	}
 	userRoles := make([]SysUserRole, 0, len(roleIdsInterfaceSlice))
 	for _, item := range roleIdsInterfaceSlice {
 		roleIdString, ok := item.(string)
@@ -72,13 +165,19 @@ func (r *Repository) Insert(userId int64, UpdateMap map[string]any) error {
 		roleMenu := SysUserRole{
 			SysUserID: int64(userId),
 			SysRoleID: int64(roleId),
+			GrantedBy: grantedBy,
+		}
+		if previous, ok := existing[int64(roleId)]; ok {
+			roleMenu.StartAt = previous.StartAt
+			roleMenu.ExpiresAt = previous.ExpiresAt
+			roleMenu.GrantedBy = previous.GrantedBy
 		}
 		userRoles = append(userRoles, roleMenu)
 	}
 	if len(userRoles) == 0 {
 		return nil
 	}
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		r.Logger.Error("Failed to begin transaction", zap.Error(tx.Error))
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -99,6 +198,17 @@ func (r *Repository) Insert(userId int64, UpdateMap map[string]any) error {
 	return nil
 }
 
+func (u *SysUserRole) toDomainMapper() domainUser.UserRole {
+	return domainUser.UserRole{
+		UserID:    u.SysUserID,
+		RoleID:    u.SysRoleID,
+		StartAt:   u.StartAt,
+		ExpiresAt: u.ExpiresAt,
+		GrantedBy: u.GrantedBy,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
 func NewSysUserRoleRepository(db *gorm.DB, loggerInstance *logger.Logger) ISysUserRoleRepository {
 	return &Repository{DB: db, Logger: loggerInstance}
 }
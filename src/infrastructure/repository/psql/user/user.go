@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -8,8 +9,10 @@ import (
 	"github.com/gbrayhan/microservices-go/src/domain"
 	"github.com/gbrayhan/microservices-go/src/domain/constants"
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
 	domainUser "github.com/gbrayhan/microservices-go/src/domain/user"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	auditLogRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
 	roleRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/role"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/repository/utils"
 	"go.uber.org/zap"
@@ -55,16 +58,16 @@ var ColumnsUserMapping = map[string]string{
 
 // UserRepositoryInterface defines the interface for user repository operations
 type UserRepositoryInterface interface {
-	GetAll() (*[]domainUser.User, error)
-	Create(userDomain *domainUser.User) (*domainUser.User, error)
-	GetByID(id int) (*domainUser.User, error)
-	GetByEmail(email string) (*domainUser.User, error)
-	GetByUsername(username string) (*domainUser.User, error)
-	Update(id int64, userMap map[string]interface{}) (*domainUser.User, error)
-	Delete(id int) error
-	SearchPaginated(filters domain.DataFilters) (*domainUser.SearchResultUser, error)
-	SearchByProperty(property string, searchText string) (*[]string, error)
-	GetOneByMap(userMap map[string]interface{}) (*domainUser.User, error)
+	GetAll(ctx context.Context) (*[]domainUser.User, error)
+	Create(ctx context.Context, userDomain *domainUser.User) (*domainUser.User, error)
+	GetByID(ctx context.Context, id int) (*domainUser.User, error)
+	GetByEmail(ctx context.Context, email string) (*domainUser.User, error)
+	GetByUsername(ctx context.Context, username string) (*domainUser.User, error)
+	Update(ctx context.Context, id int64, actorId int64, userMap map[string]interface{}) (*domainUser.User, error)
+	Delete(ctx context.Context, id int) error
+	SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domainUser.SearchResultUser, error)
+	SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error)
+	GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*domainUser.User, error)
 }
 
 type Repository struct {
@@ -76,9 +79,9 @@ func NewUserRepository(db *gorm.DB, loggerInstance *logger.Logger) UserRepositor
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]domainUser.User, error) {
+func (r *Repository) GetAll(ctx context.Context) (*[]domainUser.User, error) {
 	var users []User
-	if err := r.DB.Find(&users).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&users).Error; err != nil {
 		r.Logger.Error("Error getting all users", zap.Error(err))
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
@@ -86,10 +89,10 @@ func (r *Repository) GetAll() (*[]domainUser.User, error) {
 	return arrayToDomainMapper(&users), nil
 }
 
-func (r *Repository) Create(userDomain *domainUser.User) (*domainUser.User, error) {
+func (r *Repository) Create(ctx context.Context, userDomain *domainUser.User) (*domainUser.User, error) {
 	r.Logger.Info("Creating new user", zap.String("email", userDomain.Email))
 	userRepository := fromDomainMapper(userDomain)
-	txDb := r.DB.Create(userRepository)
+	txDb := r.DB.WithContext(ctx).Create(userRepository)
 	err := txDb.Error
 	if err != nil {
 		r.Logger.Error("Error creating user", zap.Error(err), zap.String("email", userDomain.Email))
@@ -111,9 +114,9 @@ func (r *Repository) Create(userDomain *domainUser.User) (*domainUser.User, erro
 	return userRepository.toDomainMapper(), err
 }
 
-func (r *Repository) GetByID(id int) (*domainUser.User, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*domainUser.User, error) {
 	var user User
-	err := r.DB.Where("id = ?", id).Preload("Roles", func(db *gorm.DB) *gorm.DB {
+	err := r.DB.WithContext(ctx).Where("id = ?", id).Preload("Roles", func(db *gorm.DB) *gorm.DB {
 		return db.Where("status = ?", constants.StatusEnabled).Order("id asc")
 	}).First(&user).Error
 	if err != nil {
@@ -130,9 +133,9 @@ func (r *Repository) GetByID(id int) (*domainUser.User, error) {
 	return user.toDomainMapper(), nil
 }
 
-func (r *Repository) GetByEmail(email string) (*domainUser.User, error) {
+func (r *Repository) GetByEmail(ctx context.Context, email string) (*domainUser.User, error) {
 	var user User
-	err := r.DB.Where("email = ?", email).First(&user).Error
+	err := r.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			r.Logger.Warn("User not found", zap.String("email", email))
@@ -147,9 +150,9 @@ func (r *Repository) GetByEmail(email string) (*domainUser.User, error) {
 	return user.toDomainMapper(), nil
 }
 
-func (r *Repository) GetByUsername(username string) (*domainUser.User, error) {
+func (r *Repository) GetByUsername(ctx context.Context, username string) (*domainUser.User, error) {
 	var user User
-	err := r.DB.Where("user_name = ?", username).Preload("Roles", func(db *gorm.DB) *gorm.DB {
+	err := r.DB.WithContext(ctx).Where("user_name = ?", username).Preload("Roles", func(db *gorm.DB) *gorm.DB {
 		return db.Where("status = ?", constants.StatusEnabled).Order("id asc")
 	}).First(&user).Error
 
@@ -167,15 +170,21 @@ func (r *Repository) GetByUsername(username string) (*domainUser.User, error) {
 	return user.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int64, userMap map[string]interface{}) (*domainUser.User, error) {
+func (r *Repository) Update(ctx context.Context, id int64, actorId int64, userMap map[string]interface{}) (*domainUser.User, error) {
 	var userObj User
 	userObj.ID = id
 	delete(userMap, "updated_at")
-	err := r.DB.Model(&userObj).
-		Select("user_name", "email", "nick_name", "status", "phone", "header_img", "hash_password").
-		Updates(userMap).Error
+	var before User
+	err := auditLogRepo.UpdateAudited(r.DB.WithContext(ctx), domainAuditLog.EntityUser, id, actorId, &before, &userObj, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&userObj).
+			Select("user_name", "email", "nick_name", "status", "phone", "header_img", "hash_password").
+			Updates(userMap)
+	})
 	if err != nil {
 		r.Logger.Error("Error updating user", zap.Error(err), zap.Int64("id", id))
+		if err == gorm.ErrRecordNotFound {
+			return &domainUser.User{}, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
 		byteErr, _ := json.Marshal(err)
 		var newError domainErrors.GormErr
 		errUnmarshal := json.Unmarshal(byteErr, &newError)
@@ -189,16 +198,12 @@ func (r *Repository) Update(id int64, userMap map[string]interface{}) (*domainUs
 			return &domainUser.User{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 		}
 	}
-	if err := r.DB.Where("id = ?", id).First(&userObj).Error; err != nil {
-		r.Logger.Error("Error retrieving updated user", zap.Error(err), zap.Int64("id", id))
-		return &domainUser.User{}, err
-	}
 	r.Logger.Info("Successfully updated user", zap.Int64("id", id))
 	return userObj.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(id int) error {
-	tx := r.DB.Delete(&User{}, id)
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	tx := r.DB.WithContext(ctx).Delete(&User{}, id)
 	if tx.Error != nil {
 		r.Logger.Error("Error deleting user", zap.Error(tx.Error), zap.Int("id", id))
 		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -211,8 +216,8 @@ func (r *Repository) Delete(id int) error {
 	return nil
 }
 
-func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domainUser.SearchResultUser, error) {
-	query := r.DB.Model(&User{})
+func (r *Repository) SearchPaginated(ctx context.Context, filters domain.DataFilters) (*domainUser.SearchResultUser, error) {
+	query := r.DB.WithContext(ctx).Model(&User{})
 
 	// Apply like filters
 	for field, values := range filters.LikeFilters {
@@ -299,7 +304,7 @@ func (r *Repository) SearchPaginated(filters domain.DataFilters) (*domainUser.Se
 	return result, nil
 }
 
-func (r *Repository) SearchByProperty(property string, searchText string) (*[]string, error) {
+func (r *Repository) SearchByProperty(ctx context.Context, property string, searchText string) (*[]string, error) {
 	column := ColumnsUserMapping[property]
 	if column == "" {
 		r.Logger.Warn("Invalid property for search", zap.String("property", property))
@@ -307,7 +312,7 @@ func (r *Repository) SearchByProperty(property string, searchText string) (*[]st
 	}
 
 	var coincidences []string
-	if err := r.DB.Model(&User{}).
+	if err := r.DB.WithContext(ctx).Model(&User{}).
 		Distinct(column).
 		Where(column+" ILIKE ?", "%"+searchText+"%").
 		Limit(20).
@@ -366,9 +371,9 @@ func arrayToDomainMapper(users *[]User) *[]domainUser.User {
 	return &usersDomain
 }
 
-func (r *Repository) GetOneByMap(userMap map[string]interface{}) (*domainUser.User, error) {
+func (r *Repository) GetOneByMap(ctx context.Context, userMap map[string]interface{}) (*domainUser.User, error) {
 	var userRepository User
-	tx := r.DB.Limit(1)
+	tx := r.DB.WithContext(ctx).Limit(1)
 	for key, value := range userMap {
 		if !utils.IsZeroValue(value) {
 			tx = tx.Where(fmt.Sprintf("%s = ?", key), value)
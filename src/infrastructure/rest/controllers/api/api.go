@@ -32,7 +32,12 @@ type DeleteBatchApiRequest struct {
 }
 
 type SynchronizeResponse struct {
-	Count int `json:"count"`
+	Count   int             `json:"count"`
+	Updated int             `json:"updated"`
+	Removed []domainApi.Api `json:"removed"`
+	Pruned  bool            `json:"pruned"`
+	// Undocumented routes are missing from the swagger docs, swag init classifies them by their tags
+	Undocumented []string `json:"undocumented"`
 }
 
 type ResponseApi struct {
@@ -91,7 +96,7 @@ func (c *ApiController) NewApi(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	apiModel, err := c.apiService.Create(toUsecaseMapper(&request))
+	apiModel, err := c.apiService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating api", zap.Error(err), zap.String("path", request.Path))
 		_ = ctx.Error(err)
@@ -116,7 +121,7 @@ func (c *ApiController) NewApi(ctx *gin.Context) {
 // @Router /v1/api [get]
 func (c *ApiController) GetAllApis(ctx *gin.Context) {
 	c.Logger.Info("Getting all apis")
-	apis, err := c.apiService.GetAll()
+	apis, err := c.apiService.GetAll(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all apis", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -146,7 +151,7 @@ func (c *ApiController) GetApisByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting api by ID", zap.Int("id", apiID))
-	api, err := c.apiService.GetByID(apiID)
+	api, err := c.apiService.GetByID(ctx.Request.Context(), apiID)
 	if err != nil {
 		c.Logger.Error("Error getting api by ID", zap.Error(err), zap.Int("id", apiID))
 		_ = ctx.Error(err)
@@ -188,7 +193,7 @@ func (c *ApiController) UpdateApi(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	apiUpdated, err := c.apiService.Update(apiID, requestMap)
+	apiUpdated, err := c.apiService.Update(ctx.Request.Context(), apiID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating api", zap.Error(err), zap.Int("id", apiID))
 		_ = ctx.Error(err)
@@ -220,7 +225,7 @@ func (c *ApiController) DeleteApi(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting api", zap.Int("id", apiID))
-	err = c.apiService.Delete([]int{apiID})
+	err = c.apiService.Delete(ctx.Request.Context(), []int{apiID})
 	if err != nil {
 		c.Logger.Error("Error deleting api", zap.Error(err), zap.Int("id", apiID))
 		_ = ctx.Error(err)
@@ -319,7 +324,7 @@ func (c *ApiController) SearchPaginated(ctx *gin.Context) {
 		filters.SortDirection = sortDirection
 	}
 
-	result, err := c.apiService.SearchPaginated(filters)
+	result, err := c.apiService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching apis", zap.Error(err))
 		_ = ctx.Error(err)
@@ -379,7 +384,7 @@ func (c *ApiController) SearchByProperty(ctx *gin.Context) {
 		return
 	}
 
-	coincidences, err := c.apiService.SearchByProperty(property, searchText)
+	coincidences, err := c.apiService.SearchByProperty(ctx.Request.Context(), property, searchText)
 	if err != nil {
 		c.Logger.Error("Error searching by property", zap.Error(err), zap.String("property", property))
 		_ = ctx.Error(err)
@@ -412,7 +417,7 @@ func (c *ApiController) DeleteApis(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting operation", zap.String("ids", fmt.Sprintf("%v", request.IDS)))
-	err = c.apiService.Delete(request.IDS)
+	err = c.apiService.Delete(ctx.Request.Context(), request.IDS)
 	if err != nil {
 		c.Logger.Error("Error deleting operation", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", request.IDS)))
 		_ = ctx.Error(err)
@@ -436,7 +441,7 @@ func (c *ApiController) DeleteApis(ctx *gin.Context) {
 // @Router /v1/api/group-list [get]
 func (c *ApiController) GetApisGroup(ctx *gin.Context) {
 	c.Logger.Info("Getting all apis")
-	apis, err := c.apiService.GetApisGroup()
+	apis, err := c.apiService.GetApisGroup(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all apis", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -458,6 +463,7 @@ func (c *ApiController) GetApisGroup(ctx *gin.Context) {
 // @Tags sync apis
 // @Accept json
 // @Produce json
+// @Param prune query bool false "delete apis whose route was removed, with their casbin rules"
 // @Success 200 {object} SynchronizeResponse
 // @Router /v1/api/synchronize [post]
 func (c *ApiController) SynchronizeRouterToApi(ctx *gin.Context) {
@@ -472,8 +478,8 @@ func (c *ApiController) SynchronizeRouterToApi(ctx *gin.Context) {
 	}
 	// 获取所有路由信息
 	routes := c.Router.Routes()
-	fmt.Println("Synchronizing router to api", routes)
-	apis, err := c.apiService.SynchronizeRouterToApi(routes)
+	prune, _ := strconv.ParseBool(ctx.DefaultQuery("prune", "false"))
+	result, err := c.apiService.SynchronizeRouterToApi(ctx.Request.Context(), routes, prune)
 	if err != nil {
 		c.Logger.Error("Error synchronizing router to api", zap.Error(err))
 		appError := domainErrors.NewAppError(err, domainErrors.RepositoryError)
@@ -481,11 +487,20 @@ func (c *ApiController) SynchronizeRouterToApi(ctx *gin.Context) {
 		return
 	}
 	apiResponse := controllers.NewCommonResponseBuilder[SynchronizeResponse]().
-		Data(SynchronizeResponse{Count: *apis}).
+		Data(SynchronizeResponse{
+			Count:        result.Created,
+			Updated:      result.Updated,
+			Removed:      result.Removed,
+			Pruned:       result.Pruned,
+			Undocumented: result.Undocumented,
+		}).
 		Message("success").
 		Status(0).
 		Build()
-	c.Logger.Info("Successfully synchronized router to api", zap.Int("count", *apis))
+	c.Logger.Info("Successfully synchronized router to api",
+		zap.Int("count", result.Created),
+		zap.Int("removed", len(result.Removed)),
+		zap.Int("undocumented", len(result.Undocumented)))
 	ctx.JSON(http.StatusOK, apiResponse)
 }
 
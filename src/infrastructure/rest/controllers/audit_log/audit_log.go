@@ -0,0 +1,193 @@
+package audit_log
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gbrayhan/microservices-go/src/domain"
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainAuditLog "github.com/gbrayhan/microservices-go/src/domain/sys/audit_log"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	auditLogRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/audit_log"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Structures
+type ResponseAuditLog struct {
+	ID         int                              `json:"id"`
+	EntityType string                           `json:"entity_type"`
+	EntityID   string                           `json:"entity_id"`
+	ActorID    int64                            `json:"actor_id"`
+	Action     string                           `json:"action"`
+	Changes    map[string]domainAuditLog.Change `json:"changes"`
+	CreatedAt  domain.CustomTime                `json:"created_at"`
+}
+
+type IAuditLogController interface {
+	GetAuditLogByID(ctx *gin.Context)
+	SearchPaginated(ctx *gin.Context)
+}
+
+type AuditLogController struct {
+	auditLogService domainAuditLog.IAuditLogService
+	Logger          *logger.Logger
+}
+
+func NewAuditLogController(auditLogService domainAuditLog.IAuditLogService, loggerInstance *logger.Logger) IAuditLogController {
+	return &AuditLogController{auditLogService: auditLogService, Logger: loggerInstance}
+}
+
+// GetAuditLogByID
+// @Summary get audit log
+// @Description get audit log by id
+// @Tags audit log
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[ResponseAuditLog]
+// @Router /v1/audit-log/{id} [get]
+func (c *AuditLogController) GetAuditLogByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid audit log ID parameter", zap.Error(err), zap.String("id", ctx.Param("id")))
+		appError := domainErrors.NewAppError(errors.New("audit log id is invalid"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	auditLog, err := c.auditLogService.GetByID(ctx.Request.Context(), id)
+	if err != nil {
+		c.Logger.Error("Error getting audit log by ID", zap.Error(err), zap.Int("id", id))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[*ResponseAuditLog]().
+		Data(domainToResponseMapper(auditLog)).
+		Message("success").
+		Status(0).
+		Build()
+	ctx.JSON(http.StatusOK, response)
+}
+
+// SearchAuditLogPageList
+// @Summary search audit logs
+// @Description search audit logs, filter by entity with entityType_match and entityId_match and by actor with actorId_match
+// @Tags audit log
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.PageList[[]ResponseAuditLog]
+// @Router /v1/audit-log/search [get]
+func (c *AuditLogController) SearchPaginated(ctx *gin.Context) {
+	c.Logger.Info("Searching audit logs with pagination")
+
+	// Parse query parameters
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	// Build filters
+	filters := domain.DataFilters{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	// Parse like filters
+	likeFilters := make(map[string][]string)
+	for field := range auditLogRepo.ColumnsAuditLogMapping {
+		if values := ctx.QueryArray(field + "_like"); len(values) > 0 {
+			likeFilters[field] = values
+		}
+	}
+	filters.LikeFilters = likeFilters
+
+	// Parse exact matches
+	matches := make(map[string][]string)
+	for field := range auditLogRepo.ColumnsAuditLogMapping {
+		if values := ctx.QueryArray(field + "_match"); len(values) > 0 {
+			matches[field] = values
+		}
+	}
+	filters.Matches = matches
+
+	// Parse date range filters
+	var dateRanges []domain.DateRangeFilter
+	for field := range auditLogRepo.ColumnsAuditLogMapping {
+		startStr := ctx.Query(field + "_start")
+		endStr := ctx.Query(field + "_end")
+
+		if startStr != "" || endStr != "" {
+			dateRange := domain.DateRangeFilter{Field: field}
+
+			if startStr != "" {
+				if startTime, err := time.Parse(time.RFC3339, startStr); err == nil {
+					dateRange.Start = &startTime
+				}
+			}
+
+			if endStr != "" {
+				if endTime, err := time.Parse(time.RFC3339, endStr); err == nil {
+					dateRange.End = &endTime
+				}
+			}
+
+			dateRanges = append(dateRanges, dateRange)
+		}
+	}
+	filters.DateRangeFilters = dateRanges
+
+	// Parse sorting
+	filters.SortBy = ctx.QueryArray("sortBy")
+	sortDirection := domain.SortDirection(ctx.DefaultQuery("sortDirection", "desc"))
+	if sortDirection.IsValid() {
+		filters.SortDirection = sortDirection
+	}
+
+	result, err := c.auditLogService.SearchPaginated(ctx.Request.Context(), filters)
+	if err != nil {
+		c.Logger.Error("Error searching audit logs", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	type PageResult = domain.PageList[*[]*ResponseAuditLog]
+	response := controllers.NewCommonResponseBuilder[PageResult]().
+		Data(PageResult{
+			List:       arrayDomainToResponseMapper(result.Data),
+			Total:      result.Total,
+			Page:       result.Page,
+			PageSize:   result.PageSize,
+			TotalPages: result.TotalPages,
+			Filters:    filters,
+		}).
+		Message("success").
+		Status(0).
+		Build()
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Mappers
+func domainToResponseMapper(auditLog *domainAuditLog.AuditLog) *ResponseAuditLog {
+	return &ResponseAuditLog{
+		ID:         auditLog.ID,
+		EntityType: auditLog.EntityType,
+		EntityID:   auditLog.EntityID,
+		ActorID:    auditLog.ActorID,
+		Action:     auditLog.Action,
+		Changes:    auditLog.Changes,
+		CreatedAt:  domain.CustomTime{Time: auditLog.CreatedAt},
+	}
+}
+
+func arrayDomainToResponseMapper(auditLogs *[]domainAuditLog.AuditLog) *[]*ResponseAuditLog {
+	res := make([]*ResponseAuditLog, len(*auditLogs))
+	for i, auditLog := range *auditLogs {
+		res[i] = domainToResponseMapper(&auditLog)
+	}
+	return &res
+}
@@ -53,7 +53,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		Password: request.Password,
 	}
 
-	registerUser, err := c.authUseCase.Register(userRegister)
+	registerUser, err := c.authUseCase.Register(ctx.Request.Context(), userRegister)
 	if err != nil {
 		appError := domainErrors.NewAppError(err, domainErrors.ValidationError)
 		_ = ctx.Error(appError)
@@ -78,7 +78,7 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	res, err := c.authUseCase.Logout(tokens[1])
+	res, err := c.authUseCase.Logout(ctx.Request.Context(), tokens[1])
 	if err != nil {
 		appError := domainErrors.NewAppError(err, domainErrors.ValidationError)
 		_ = ctx.Error(appError)
@@ -113,7 +113,7 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	domainUser, authTokens, role, err := c.authUseCase.Login(request.Username, request.Password)
+	domainUser, authTokens, role, err := c.authUseCase.Login(ctx.Request.Context(), request.Username, request.Password)
 	if err != nil {
 		c.Logger.Error("Login failed", zap.Error(err), zap.String("email", request.Username))
 		_ = ctx.Error(err)
@@ -165,7 +165,7 @@ func (c *AuthController) GetAccessTokenByRefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	domainUser, authTokens, err := c.authUseCase.AccessTokenByRefreshToken(request.RefreshToken)
+	domainUser, authTokens, err := c.authUseCase.AccessTokenByRefreshToken(ctx.Request.Context(), request.RefreshToken)
 	if err != nil {
 		c.Logger.Error("Error Token", zap.Error(err))
 		appError := domainErrors.NewAppError(err, domainErrors.TokenError)
@@ -214,7 +214,7 @@ func (c *AuthController) SwitchRole(ctx *gin.Context) {
 		_ = ctx.Error(errors.New("user id is invalid"))
 		return
 	}
-	domainUser, authTokens, role, err := c.authUseCase.SwitchRole(userId, int64(roleId))
+	domainUser, authTokens, role, err := c.authUseCase.SwitchRole(ctx.Request.Context(), userId, int64(roleId))
 	if err != nil {
 		c.Logger.Error("Login failed", zap.Error(err), zap.Int("userId", userId))
 		_ = ctx.Error(err)
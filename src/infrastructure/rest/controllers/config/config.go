@@ -48,7 +48,7 @@ func NewConfigController(configService domainConfig.IConfigService, loggerInstan
 // @Router /v1/api/config [get]
 func (c *ConfigController) GetAllConfigs(ctx *gin.Context) {
 	c.Logger.Info("Getting all configs")
-	configs, err := c.configService.GetConfigByGroup()
+	configs, err := c.configService.GetConfigByGroup(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all configs", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -86,7 +86,8 @@ func (c *ConfigController) UpdateConfig(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	err = c.configService.Update(ctx.Param("module"), requestMap)
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	err = c.configService.Update(ctx.Request.Context(), ctx.Param("module"), int64(actorId), requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating config", zap.Error(err))
 		_ = ctx.Error(err)
@@ -112,7 +113,7 @@ func (c *ConfigController) UpdateConfig(ctx *gin.Context) {
 // @Router /v1/api/config/module/{module} [get]
 func (c *ConfigController) GetConfigByModule(ctx *gin.Context) {
 	c.Logger.Info("Getting all configs")
-	configs, err := c.configService.GetConfigByModule(ctx.Param("module"))
+	configs, err := c.configService.GetConfigByModule(ctx.Request.Context(), ctx.Param("module"))
 	if err != nil {
 		c.Logger.Error("Error getting all configs", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -136,7 +137,7 @@ func (c *ConfigController) GetConfigByModule(ctx *gin.Context) {
 // @Router /v1/api/config/system [get]
 func (c *ConfigController) GetConfigBySystem(ctx *gin.Context) {
 	c.Logger.Info("Getting all configs")
-	configs, err := c.configService.GetConfigByModule("system")
+	configs, err := c.configService.GetConfigByModule(ctx.Request.Context(), "system")
 	if err != nil {
 		c.Logger.Error("Error getting all configs", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -74,7 +74,7 @@ func (c *DictionaryController) NewDictionary(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	dictionaryModel, err := c.dictionaryService.Create(toUsecaseMapper(&request))
+	dictionaryModel, err := c.dictionaryService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating dictionary", zap.Error(err), zap.String("Name", request.Name))
 		_ = ctx.Error(err)
@@ -99,7 +99,7 @@ func (c *DictionaryController) NewDictionary(ctx *gin.Context) {
 // @Router /v1/dictionary [get]
 func (c *DictionaryController) GetAllDictionaries(ctx *gin.Context) {
 	c.Logger.Info("Getting all dictionaries")
-	dictionaries, err := c.dictionaryService.GetAll()
+	dictionaries, err := c.dictionaryService.GetAll(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all dictionaries", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -129,7 +129,7 @@ func (c *DictionaryController) GetDictionariesByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting dictionary by ID", zap.Int("id", dictionaryID))
-	dictionary, err := c.dictionaryService.GetByID(dictionaryID)
+	dictionary, err := c.dictionaryService.GetByID(ctx.Request.Context(), dictionaryID)
 	if err != nil {
 		c.Logger.Error("Error getting dictionary by ID", zap.Error(err), zap.Int("id", dictionaryID))
 		_ = ctx.Error(err)
@@ -171,7 +171,7 @@ func (c *DictionaryController) UpdateDictionary(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	dictionaryUpdated, err := c.dictionaryService.Update(dictionaryID, requestMap)
+	dictionaryUpdated, err := c.dictionaryService.Update(ctx.Request.Context(), dictionaryID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating dictionary", zap.Error(err), zap.Int("id", dictionaryID))
 		_ = ctx.Error(err)
@@ -203,7 +203,7 @@ func (c *DictionaryController) DeleteDictionary(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting dictionary", zap.Int("id", dictionaryID))
-	err = c.dictionaryService.Delete(dictionaryID)
+	err = c.dictionaryService.Delete(ctx.Request.Context(), dictionaryID)
 	if err != nil {
 		c.Logger.Error("Error deleting dictionary", zap.Error(err), zap.Int("id", dictionaryID))
 		_ = ctx.Error(err)
@@ -301,7 +301,7 @@ func (c *DictionaryController) SearchPaginated(ctx *gin.Context) {
 		filters.SortDirection = sortDirection
 	}
 
-	result, err := c.dictionaryService.SearchPaginated(filters)
+	result, err := c.dictionaryService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching dictionaries", zap.Error(err))
 		_ = ctx.Error(err)
@@ -361,7 +361,7 @@ func (c *DictionaryController) SearchByProperty(ctx *gin.Context) {
 		return
 	}
 
-	coincidences, err := c.dictionaryService.SearchByProperty(property, searchText)
+	coincidences, err := c.dictionaryService.SearchByProperty(ctx.Request.Context(), property, searchText)
 	if err != nil {
 		c.Logger.Error("Error searching by property", zap.Error(err), zap.String("property", property))
 		_ = ctx.Error(err)
@@ -377,7 +377,7 @@ func (c *DictionaryController) SearchByProperty(ctx *gin.Context) {
 func (c *DictionaryController) GetByType(ctx *gin.Context) {
 	typeText := ctx.Param("type")
 	c.Logger.Info("getting dictionary by type", zap.String("type", typeText))
-	dictionaries, err := c.dictionaryService.GetByType(typeText)
+	dictionaries, err := c.dictionaryService.GetByType(ctx.Request.Context(), typeText)
 	if err != nil {
 		c.Logger.Error("Error getting all dictionaries", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
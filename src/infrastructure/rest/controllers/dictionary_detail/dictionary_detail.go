@@ -83,7 +83,7 @@ func (c *DictionaryDetailController) NewDictionary(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	dictionaryModel, err := c.dictionaryService.Create(toUsecaseMapper(&request))
+	dictionaryModel, err := c.dictionaryService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating dictionary", zap.Error(err), zap.String("Label", request.Label))
 		_ = ctx.Error(err)
@@ -108,7 +108,7 @@ func (c *DictionaryDetailController) NewDictionary(ctx *gin.Context) {
 // @Router /v1/dictionary [get]
 func (c *DictionaryDetailController) GetAllDictionaries(ctx *gin.Context) {
 	c.Logger.Info("Getting all dictionaries")
-	dictionaries, err := c.dictionaryService.GetAll()
+	dictionaries, err := c.dictionaryService.GetAll(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all dictionaries", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -138,7 +138,7 @@ func (c *DictionaryDetailController) GetDictionariesByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting dictionary by ID", zap.Int("id", dictionaryID))
-	dictionary, err := c.dictionaryService.GetByID(dictionaryID)
+	dictionary, err := c.dictionaryService.GetByID(ctx.Request.Context(), dictionaryID)
 	if err != nil {
 		c.Logger.Error("Error getting dictionary by ID", zap.Error(err), zap.Int("id", dictionaryID))
 		_ = ctx.Error(err)
@@ -180,7 +180,7 @@ func (c *DictionaryDetailController) UpdateDictionary(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	dictionaryUpdated, err := c.dictionaryService.Update(dictionaryID, requestMap)
+	dictionaryUpdated, err := c.dictionaryService.Update(ctx.Request.Context(), dictionaryID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating dictionary", zap.Error(err), zap.Int("id", dictionaryID))
 		_ = ctx.Error(err)
@@ -212,7 +212,7 @@ func (c *DictionaryDetailController) DeleteDictionary(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting dictionary", zap.Int("id", dictionaryID))
-	err = c.dictionaryService.Delete([]int{dictionaryID})
+	err = c.dictionaryService.Delete(ctx.Request.Context(), []int{dictionaryID})
 	if err != nil {
 		c.Logger.Error("Error deleting dictionary", zap.Error(err), zap.Int("id", dictionaryID))
 		_ = ctx.Error(err)
@@ -311,7 +311,7 @@ func (c *DictionaryDetailController) SearchPaginated(ctx *gin.Context) {
 		filters.SortDirection = sortDirection
 	}
 
-	result, err := c.dictionaryService.SearchPaginated(filters)
+	result, err := c.dictionaryService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching dictionaries", zap.Error(err))
 		_ = ctx.Error(err)
@@ -371,7 +371,7 @@ func (c *DictionaryDetailController) SearchByProperty(ctx *gin.Context) {
 		return
 	}
 
-	coincidences, err := c.dictionaryService.SearchByProperty(property, searchText)
+	coincidences, err := c.dictionaryService.SearchByProperty(ctx.Request.Context(), property, searchText)
 	if err != nil {
 		c.Logger.Error("Error searching by property", zap.Error(err), zap.String("property", property))
 		_ = ctx.Error(err)
@@ -404,7 +404,7 @@ func (c *DictionaryDetailController) DeleteDictionaryDetails(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting operation", zap.String("ids", fmt.Sprintf("%v", request.IDS)))
-	err = c.dictionaryService.Delete(request.IDS)
+	err = c.dictionaryService.Delete(ctx.Request.Context(), request.IDS)
 	if err != nil {
 		c.Logger.Error("Error deleting operation", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", request.IDS)))
 		_ = ctx.Error(err)
@@ -0,0 +1,171 @@
+package file
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainFile "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type IFileAttachmentController interface {
+	ListAttachments(ctx *gin.Context)
+	Attach(ctx *gin.Context)
+	Detach(ctx *gin.Context)
+	ReorderAttachments(ctx *gin.Context)
+}
+
+type AttachFileRequest struct {
+	FileID int64  `json:"file_id" binding:"required"`
+	Label  string `json:"label"`
+}
+
+type ReorderAttachmentsRequest struct {
+	FileIDs []int64 `json:"file_ids" binding:"required"`
+}
+
+type FileAttachmentController struct {
+	attachments domainFile.IFileAttachmentService
+	Logger      *logger.Logger
+}
+
+func NewFileAttachmentController(attachments domainFile.IFileAttachmentService, loggerInstance *logger.Logger) IFileAttachmentController {
+	return &FileAttachmentController{attachments: attachments, Logger: loggerInstance}
+}
+
+// entity reads the entity of the path, the error is set on the context when it returns false
+func (c *FileAttachmentController) entity(ctx *gin.Context) (string, int64, bool) {
+	entityID, err := strconv.ParseInt(ctx.Param("entity_id"), 10, 64)
+	if err != nil || entityID <= 0 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("entity id is invalid"), domainErrors.ValidationError))
+		return "", 0, false
+	}
+	return ctx.Param("entity_type"), entityID, true
+}
+
+// ListAttachments
+// @Summary list attachments
+// @Description the files attached to an entity in their order
+// @Tags file attachment
+// @Produce json
+// @Param entity_type path string true "entity type, like user"
+// @Param entity_id path int true "entity id"
+// @Success 200 {object} domain.CommonResponse[[]domainFile.FileAttachment]
+// @Router /v1/file/attachments/{entity_type}/{entity_id} [get]
+func (c *FileAttachmentController) ListAttachments(ctx *gin.Context) {
+	entityType, entityID, ok := c.entity(ctx)
+	if !ok {
+		return
+	}
+	attachments, err := c.attachments.List(ctx.Request.Context(), entityType, entityID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*[]domainFile.FileAttachment]().
+		Data(attachments).Message("success").Status(0).Build())
+}
+
+// Attach
+// @Summary attach a file
+// @Description attaches a file the requester may read after the other attachments of the entity,
+// @Description attaching it again changes its label
+// @Tags file attachment
+// @Accept json
+// @Produce json
+// @Param entity_type path string true "entity type, like user"
+// @Param entity_id path int true "entity id"
+// @Param request body AttachFileRequest true "the file"
+// @Success 200 {object} domain.CommonResponse[domainFile.FileAttachment]
+// @Router /v1/file/attachments/{entity_type}/{entity_id} [post]
+func (c *FileAttachmentController) Attach(ctx *gin.Context) {
+	entityType, entityID, ok := c.entity(ctx)
+	if !ok {
+		return
+	}
+	var request AttachFileRequest
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	appUtils := controllers.NewAppUtils(ctx)
+	userID, _ := appUtils.GetUserID()
+	roleID, _ := appUtils.GetRoleID()
+	attachment, err := c.attachments.Attach(ctx.Request.Context(), domainFile.FileAttachment{
+		EntityType: entityType,
+		EntityID:   entityID,
+		FileID:     request.FileID,
+		Label:      request.Label,
+	}, domainFile.FileAccess{UserID: int64(userID), RoleID: roleID, Granted: userID == 1})
+	if err != nil {
+		c.Logger.Error("Error attaching file", zap.Error(err), zap.String("entityType", entityType),
+			zap.Int64("entityId", entityID), zap.Int64("fileId", request.FileID))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainFile.FileAttachment]().
+		Data(attachment).Message("success").Status(0).Build())
+}
+
+// Detach
+// @Summary detach a file
+// @Description removes a file from the attachments of an entity, the file itself is kept
+// @Tags file attachment
+// @Produce json
+// @Param entity_type path string true "entity type, like user"
+// @Param entity_id path int true "entity id"
+// @Param file_id path int true "file id"
+// @Success 200 {object} domain.CommonResponse[int64]
+// @Router /v1/file/attachments/{entity_type}/{entity_id}/{file_id} [delete]
+func (c *FileAttachmentController) Detach(ctx *gin.Context) {
+	entityType, entityID, ok := c.entity(ctx)
+	if !ok {
+		return
+	}
+	fileID, err := strconv.ParseInt(ctx.Param("file_id"), 10, 64)
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("file id is invalid"), domainErrors.ValidationError))
+		return
+	}
+	if err := c.attachments.Detach(ctx.Request.Context(), entityType, entityID, fileID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[int64]().
+		Data(fileID).Message("resource deleted successfully").Status(0).Build())
+}
+
+// ReorderAttachments
+// @Summary reorder attachments
+// @Description sorts the attachments of an entity, file_ids lists every attached file once
+// @Tags file attachment
+// @Accept json
+// @Produce json
+// @Param entity_type path string true "entity type, like user"
+// @Param entity_id path int true "entity id"
+// @Param request body ReorderAttachmentsRequest true "the files in their new order"
+// @Success 200 {object} domain.CommonResponse[[]domainFile.FileAttachment]
+// @Router /v1/file/attachments/{entity_type}/{entity_id}/order [put]
+func (c *FileAttachmentController) ReorderAttachments(ctx *gin.Context) {
+	entityType, entityID, ok := c.entity(ctx)
+	if !ok {
+		return
+	}
+	var request ReorderAttachmentsRequest
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	attachments, err := c.attachments.Reorder(ctx.Request.Context(), entityType, entityID, request.FileIDs)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*[]domainFile.FileAttachment]().
+		Data(attachments).Message("success").Status(0).Build())
+}
@@ -3,10 +3,15 @@ package file
 import (
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/casbin/casbin/v2"
 	"github.com/gbrayhan/microservices-go/src/domain"
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
 	domainFile "github.com/gbrayhan/microservices-go/src/domain/sys/files"
@@ -36,15 +41,31 @@ type SynchronizeResponse struct {
 }
 
 type ResponseFile struct {
-	ID             int64             `json:"id"`
-	FileName       string            `json:"file_name"`
-	FileMD5        string            `json:"file_md5"`
-	FilePath       string            `json:"file_path"`
-	FileUrl        string            `json:"file_url"`
-	StorageEngine  string            `json:"storage_engine"`
-	FileOriginName string            `json:"file_origin_name"`
-	CreatedAt      domain.CustomTime `json:"created_at,omitempty"`
-	UpdatedAt      domain.CustomTime `json:"updated_at,omitempty"`
+	ID             int64               `json:"id"`
+	FileName       string              `json:"file_name"`
+	FileMD5        string              `json:"file_md5"`
+	FilePath       string              `json:"file_path"`
+	FileSize       int64               `json:"file_size"`
+	FileUrl        string              `json:"file_url"`
+	StorageEngine  string              `json:"storage_engine"`
+	FileOriginName string              `json:"file_origin_name"`
+	CreatedAt      domain.CustomTime   `json:"created_at,omitempty"`
+	UpdatedAt      domain.CustomTime   `json:"updated_at,omitempty"`
+	Renditions     []ResponseRendition `json:"renditions,omitempty"`
+}
+
+type SignedURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ResponseRendition struct {
+	Size        string `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ContentType string `json:"content_type"`
+	FileSize    int64  `json:"file_size"`
+	FileUrl     string `json:"file_url"`
 }
 type IFileController interface {
 	NewFile(ctx *gin.Context)
@@ -55,9 +76,17 @@ type IFileController interface {
 	SearchPaginated(ctx *gin.Context)
 	SearchByProperty(ctx *gin.Context)
 	DeleteFiles(ctx *gin.Context)
+	UsageByUser(ctx *gin.Context)
+	UsageByEngine(ctx *gin.Context)
+	GetThumbnail(ctx *gin.Context)
+	Download(ctx *gin.Context)
+	SignURL(ctx *gin.Context)
+	ServeSigned(ctx *gin.Context)
+	ServePublic(ctx *gin.Context)
 }
 type FileController struct {
 	apiService domainFile.ISysFilesService
+	enforcer   *casbin.Enforcer
 	Logger     *logger.Logger
 	Router     *gin.Engine
 }
@@ -69,8 +98,25 @@ func (c *FileController) SetRouter(router *gin.Engine) {
 	c.Router = router
 }
 
-func NewFileController(apiService domainFile.ISysFilesService, loggerInstance *logger.Logger) IFileController {
-	return &FileController{apiService: apiService, Logger: loggerInstance}
+func NewFileController(apiService domainFile.ISysFilesService, enforcer *casbin.Enforcer, loggerInstance *logger.Logger) IFileController {
+	return &FileController{apiService: apiService, enforcer: enforcer, Logger: loggerInstance}
+}
+
+// access describes the requester to the service. Files that aren't public are read by their
+// owners, the super user and the roles Casbin allows on the requested path read every file.
+func (c *FileController) access(ctx *gin.Context) domainFile.FileAccess {
+	appUtils := controllers.NewAppUtils(ctx)
+	userID, _ := appUtils.GetUserID()
+	roleID, _ := appUtils.GetRoleID()
+	access := domainFile.FileAccess{UserID: int64(userID), RoleID: roleID, Granted: userID == 1}
+	if !access.Granted && c.enforcer != nil && roleID != 0 {
+		granted, err := c.enforcer.Enforce(strconv.FormatInt(roleID, 10), ctx.Request.URL.Path, ctx.Request.Method)
+		if err != nil {
+			c.Logger.Error("Error checking file access policy", zap.Error(err), zap.Int64("roleId", roleID))
+		}
+		access.Granted = granted
+	}
+	return access
 }
 
 // CreateFile
@@ -91,7 +137,7 @@ func (c *FileController) NewFile(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	apiModel, err := c.apiService.Create(toUsecaseMapper(&request))
+	apiModel, err := c.apiService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating api", zap.Error(err), zap.String("path", request.FileName))
 		_ = ctx.Error(err)
@@ -116,7 +162,7 @@ func (c *FileController) NewFile(ctx *gin.Context) {
 // @Router /v1/api [get]
 func (c *FileController) GetAllFiles(ctx *gin.Context) {
 	c.Logger.Info("Getting all apis")
-	apis, err := c.apiService.GetAll()
+	apis, err := c.apiService.GetAll(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all apis", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -146,7 +192,7 @@ func (c *FileController) GetFilesByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting api by ID", zap.Int("id", apiID))
-	api, err := c.apiService.GetByID(apiID)
+	api, err := c.apiService.GetByID(ctx.Request.Context(), apiID)
 	if err != nil {
 		c.Logger.Error("Error getting api by ID", zap.Error(err), zap.Int("id", apiID))
 		_ = ctx.Error(err)
@@ -156,6 +202,146 @@ func (c *FileController) GetFilesByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, domainToResponseMapper(api))
 }
 
+// GetThumbnail
+// @Summary get thumbnail
+// @Description get the thumbnail of an image file, rendered when missing
+// @Tags file
+// @Produce image/jpeg,image/png,image/webp
+// @Param id path int true "file id"
+// @Param size query string false "thumbnail size name, the first configured size when empty"
+// @Success 200 {file} binary
+// @Router /v1/file/{id}/thumbnail [get]
+func (c *FileController) GetThumbnail(ctx *gin.Context) {
+	fileID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid file ID parameter", zap.Error(err), zap.String("id", ctx.Param("id")))
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("file id is invalid"), domainErrors.ValidationError))
+		return
+	}
+	rendition, content, err := c.apiService.Thumbnail(ctx.Request.Context(), fileID, ctx.Query("size"), c.access(ctx))
+	if err != nil {
+		c.Logger.Error("Error getting thumbnail", zap.Error(err), zap.Int("id", fileID))
+		_ = ctx.Error(err)
+		return
+	}
+	defer func() { _ = content.Close() }()
+	ctx.DataFromReader(http.StatusOK, rendition.FileSize, rendition.ContentType, content, map[string]string{
+		"Cache-Control": "private, max-age=86400",
+	})
+}
+
+// Download
+// @Summary download a file
+// @Description streams the content of a file the requester may read, ranges are supported for local files
+// @Tags file
+// @Produce octet-stream
+// @Param id path int true "file id"
+// @Success 200 {file} binary
+// @Router /v1/file/{id}/download [get]
+func (c *FileController) Download(ctx *gin.Context) {
+	fileID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid file ID parameter", zap.Error(err), zap.String("id", ctx.Param("id")))
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("file id is invalid"), domainErrors.ValidationError))
+		return
+	}
+	file, content, err := c.apiService.Download(ctx.Request.Context(), fileID, c.access(ctx))
+	if err != nil {
+		c.Logger.Error("Error downloading file", zap.Error(err), zap.Int("id", fileID))
+		_ = ctx.Error(err)
+		return
+	}
+	defer func() { _ = content.Close() }()
+	serve(ctx, file.FileOriginName, file.FileSize, content, "attachment", "private, no-cache")
+}
+
+// SignURL
+// @Summary sign a download url
+// @Description returns a url reading the file without a token until it expires
+// @Tags file
+// @Produce json
+// @Param id path int true "file id"
+// @Param expires_in query int false "lifetime of the url in seconds, the configured default when empty"
+// @Success 200 {object} domain.CommonResponse[SignedURLResponse]
+// @Router /v1/file/{id}/signed-url [post]
+func (c *FileController) SignURL(ctx *gin.Context) {
+	fileID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid file ID parameter", zap.Error(err), zap.String("id", ctx.Param("id")))
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("file id is invalid"), domainErrors.ValidationError))
+		return
+	}
+	var expires time.Duration
+	if value := ctx.Query("expires_in"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			_ = ctx.Error(domainErrors.NewAppError(errors.New("expires_in must be a positive number of seconds"),
+				domainErrors.ValidationError))
+			return
+		}
+		expires = time.Duration(seconds) * time.Second
+	}
+	signed, expiresAt, err := c.apiService.SignedURL(ctx.Request.Context(), fileID, c.access(ctx), expires)
+	if err != nil {
+		c.Logger.Error("Error signing file url", zap.Error(err), zap.Int("id", fileID))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*SignedURLResponse]().
+		Data(&SignedURLResponse{URL: signed, ExpiresAt: expiresAt}).Message("success").Status(0).Build())
+}
+
+// ServeSigned
+// @Summary read a file by signed url
+// @Description streams a local file with the signature of a url returned by signed-url, no token is needed
+// @Tags file
+// @Produce octet-stream
+// @Param key path string true "storage key"
+// @Param expires query int true "unix time the url expires at"
+// @Param signature query string true "url signature"
+// @Success 200 {file} binary
+// @Router /v1/file/signed/{key} [get]
+func (c *FileController) ServeSigned(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	content, err := c.apiService.OpenSigned(ctx.Request.Context(), key, ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer func() { _ = content.Close() }()
+	serve(ctx, path.Base(key), -1, content, "attachment", "private, no-cache")
+}
+
+// ServePublic serves the local files of public visibility under the storage key prefix, it
+// replaces the static route that exposed every upload
+func (c *FileController) ServePublic(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Request.URL.Path, "/")
+	content, err := c.apiService.OpenPublic(ctx.Request.Context(), key)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer func() { _ = content.Close() }()
+	serve(ctx, path.Base(key), -1, content, "inline", "public, max-age=86400")
+}
+
+// serve writes a stored file, a local file is served with http.ServeContent for range and
+// conditional requests. A negative size is unknown.
+func serve(ctx *gin.Context, name string, size int64, content io.Reader, disposition string, cacheControl string) {
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": name}))
+	ctx.Header("Cache-Control", cacheControl)
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		http.ServeContent(ctx.Writer, ctx.Request, name, time.Time{}, seeker)
+		return
+	}
+	ctx.DataFromReader(http.StatusOK, size, contentType, content, nil)
+}
+
 // UpdateFile
 // @Summary update api
 // @Description update api
@@ -188,7 +374,7 @@ func (c *FileController) UpdateFile(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	apiUpdated, err := c.apiService.Update(apiID, requestMap)
+	apiUpdated, err := c.apiService.Update(ctx.Request.Context(), apiID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating api", zap.Error(err), zap.Int("id", apiID))
 		_ = ctx.Error(err)
@@ -220,7 +406,7 @@ func (c *FileController) DeleteFile(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting api", zap.Int("id", apiID))
-	err = c.apiService.Delete([]int64{int64(apiID)})
+	err = c.apiService.Delete(ctx.Request.Context(), []int64{int64(apiID)})
 	if err != nil {
 		c.Logger.Error("Error deleting api", zap.Error(err), zap.Int("id", apiID))
 		_ = ctx.Error(err)
@@ -320,7 +506,7 @@ func (c *FileController) SearchPaginated(ctx *gin.Context) {
 		filters.SortDirection = sortDirection
 	}
 
-	result, err := c.apiService.SearchPaginated(filters)
+	result, err := c.apiService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching apis", zap.Error(err))
 		_ = ctx.Error(err)
@@ -380,7 +566,7 @@ func (c *FileController) SearchByProperty(ctx *gin.Context) {
 		return
 	}
 
-	coincidences, err := c.apiService.SearchByProperty(property, searchText)
+	coincidences, err := c.apiService.SearchByProperty(ctx.Request.Context(), property, searchText)
 	if err != nil {
 		c.Logger.Error("Error searching by property", zap.Error(err), zap.String("property", property))
 		_ = ctx.Error(err)
@@ -394,28 +580,27 @@ func (c *FileController) SearchByProperty(ctx *gin.Context) {
 }
 
 // BatchDeleteFile
-// @Summary delete operations
-// @Description delete operations by id
+// @Summary delete files
+// @Description delete files by id, their stored objects are removed once no other file uses them
 // @Tags batch delete
 // @Accept json
 // @Produce json
 // @Param book body DeleteBatchFileRequest true  "JSON Data"
 // @Success 200 {object} domain.CommonResponse[int]
-// @Router /v1/api/delete-batch [post]
+// @Router /v1/file/delete-batch [post]
 func (c *FileController) DeleteFiles(ctx *gin.Context) {
-	c.Logger.Info("Creating new dictionary")
 	var request DeleteBatchFileRequest
 	var err error
 	if err = controllers.BindJSON(ctx, &request); err != nil {
-		c.Logger.Error("Error binding JSON for new dictionary", zap.Error(err))
+		c.Logger.Error("Error binding JSON for file batch delete", zap.Error(err))
 		appError := domainErrors.NewAppError(err, domainErrors.ValidationError)
 		_ = ctx.Error(appError)
 		return
 	}
-	c.Logger.Info("Deleting operation", zap.String("ids", fmt.Sprintf("%v", request.IDS)))
-	err = c.apiService.Delete(request.IDS)
+	c.Logger.Info("Deleting files", zap.String("ids", fmt.Sprintf("%v", request.IDS)))
+	err = c.apiService.Delete(ctx.Request.Context(), request.IDS)
 	if err != nil {
-		c.Logger.Error("Error deleting operation", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", request.IDS)))
+		c.Logger.Error("Error deleting files", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", request.IDS)))
 		_ = ctx.Error(err)
 		return
 	}
@@ -427,6 +612,53 @@ func (c *FileController) DeleteFiles(ctx *gin.Context) {
 	})
 }
 
+// UsageByUser
+// @Summary storage usage of the users
+// @Description files and bytes of each owner, the largest first. The files recorded without owner
+// @Description are counted under a null user_id.
+// @Tags file
+// @Produce json
+// @Param user_id query int false "only this user"
+// @Param limit query int false "rows, 100 by default and 1000 at most"
+// @Success 200 {object} domain.CommonResponse[[]domainFile.UserStorageUsage]
+// @Router /v1/file/usage/users [get]
+func (c *FileController) UsageByUser(ctx *gin.Context) {
+	userID, err := strconv.ParseInt(ctx.DefaultQuery("user_id", "0"), 10, 64)
+	if err != nil || userID < 0 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("user_id is invalid"), domainErrors.ValidationError))
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("limit is invalid"), domainErrors.ValidationError))
+		return
+	}
+	usage, err := c.apiService.UsageByUser(ctx.Request.Context(), userID, limit)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*[]domainFile.UserStorageUsage]().
+		Data(usage).Message("success").Status(0).Build())
+}
+
+// UsageByEngine
+// @Summary storage usage of the engines
+// @Description files and bytes recorded on each storage engine, and the distinct objects holding them
+// @Tags file
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[[]domainFile.EngineStorageUsage]
+// @Router /v1/file/usage/engines [get]
+func (c *FileController) UsageByEngine(ctx *gin.Context) {
+	usage, err := c.apiService.UsageByEngine(ctx.Request.Context())
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*[]domainFile.EngineStorageUsage]().
+		Data(usage).Message("success").Status(0).Build())
+}
+
 // Mappers
 func domainToResponseMapper(domainFile *domainFile.SysFiles) *ResponseFile {
 	return &ResponseFile{
@@ -434,12 +666,32 @@ func domainToResponseMapper(domainFile *domainFile.SysFiles) *ResponseFile {
 		FileName:       domainFile.FileName,
 		FileMD5:        domainFile.FileMD5,
 		FilePath:       domainFile.FilePath,
+		FileSize:       domainFile.FileSize,
 		FileUrl:        domainFile.FileUrl,
 		FileOriginName: domainFile.FileOriginName,
 		StorageEngine:  domainFile.StorageEngine,
 		CreatedAt:      domain.CustomTime{Time: domainFile.CreatedAt},
 		UpdatedAt:      domain.CustomTime{Time: domainFile.UpdatedAt},
+		Renditions:     renditionsToResponseMapper(domainFile.Renditions),
+	}
+}
+
+func renditionsToResponseMapper(renditions []domainFile.FileRendition) []ResponseRendition {
+	if len(renditions) == 0 {
+		return nil
+	}
+	res := make([]ResponseRendition, len(renditions))
+	for i, rendition := range renditions {
+		res[i] = ResponseRendition{
+			Size:        rendition.Size,
+			Width:       rendition.Width,
+			Height:      rendition.Height,
+			ContentType: rendition.ContentType,
+			FileSize:    rendition.FileSize,
+			FileUrl:     rendition.FileUrl,
+		}
 	}
+	return res
 }
 
 func arrayDomainToResponseMapper(apis *[]domainFile.SysFiles) *[]*ResponseFile {
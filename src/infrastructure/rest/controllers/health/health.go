@@ -0,0 +1,53 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/health"
+	"github.com/gin-gonic/gin"
+)
+
+type IHealthController interface {
+	Liveness(ctx *gin.Context)
+	Readiness(ctx *gin.Context)
+}
+
+type HealthController struct {
+	probe *health.Probe
+}
+
+func NewHealthController(probe *health.Probe) IHealthController {
+	return &HealthController{probe: probe}
+}
+
+// Liveness
+// @Summary liveness probe
+// @Description reports that the process is running, dependencies are not checked
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /v1/health/live [get]
+func (c *HealthController) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "Service is running",
+	})
+}
+
+// Readiness
+// @Summary readiness probe
+// @Description checks the database, redis, event bus, scheduler and casbin policy, answers 503
+// @Description when one of them is down or the service is shutting down
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /v1/health/ready [get]
+func (c *HealthController) Readiness(ctx *gin.Context) {
+	report := c.probe.Readiness(ctx.Request.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, report)
+}
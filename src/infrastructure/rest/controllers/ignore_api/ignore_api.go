@@ -0,0 +1,271 @@
+package ignore_api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gbrayhan/microservices-go/src/domain"
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainIgnoreApi "github.com/gbrayhan/microservices-go/src/domain/sys/ignore_api"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Structures
+type NewIgnoreApiRequest struct {
+	Path        string `json:"path" binding:"required"`
+	Method      string `json:"method"`
+	Description string `json:"description"`
+}
+
+type DeleteBatchIgnoreApiRequest struct {
+	IDS []int `json:"ids"`
+}
+
+type ResponseIgnoreApi struct {
+	ID          int               `json:"id"`
+	Path        string            `json:"path"`
+	Method      string            `json:"method"`
+	Description string            `json:"description"`
+	CreatedAt   domain.CustomTime `json:"created_at,omitempty"`
+	UpdatedAt   domain.CustomTime `json:"updated_at,omitempty"`
+}
+
+type IIgnoreApiController interface {
+	NewIgnoreApi(ctx *gin.Context)
+	GetAllIgnoreApis(ctx *gin.Context)
+	GetIgnoreApiByID(ctx *gin.Context)
+	UpdateIgnoreApi(ctx *gin.Context)
+	DeleteIgnoreApi(ctx *gin.Context)
+	DeleteIgnoreApis(ctx *gin.Context)
+}
+
+type IgnoreApiController struct {
+	ignoreApiService domainIgnoreApi.IIgnoreApiService
+	Logger           *logger.Logger
+}
+
+func NewIgnoreApiController(ignoreApiService domainIgnoreApi.IIgnoreApiService, loggerInstance *logger.Logger) IIgnoreApiController {
+	return &IgnoreApiController{ignoreApiService: ignoreApiService, Logger: loggerInstance}
+}
+
+// NewIgnoreApi
+// @Summary create ignore api
+// @Description create a path and method pattern that skips casbin and body logging
+// @Tags ignore api
+// @Accept json
+// @Produce json
+// @Param book body NewIgnoreApiRequest true  "JSON Data"
+// @Success 200 {object} domain.CommonResponse[ResponseIgnoreApi]
+// @Router /v1/ignore-api [post]
+func (c *IgnoreApiController) NewIgnoreApi(ctx *gin.Context) {
+	c.Logger.Info("Creating new ignore api")
+	var request NewIgnoreApiRequest
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		c.Logger.Error("Error binding JSON for new ignore api", zap.Error(err))
+		appError := domainErrors.NewAppError(err, domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	ignoreApi, err := c.ignoreApiService.Create(ctx.Request.Context(), &domainIgnoreApi.IgnoreApi{
+		Path:        request.Path,
+		Method:      request.Method,
+		Description: request.Description,
+	})
+	if err != nil {
+		c.Logger.Error("Error creating ignore api", zap.Error(err), zap.String("path", request.Path))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[*ResponseIgnoreApi]().
+		Data(domainToResponseMapper(ignoreApi)).
+		Message("success").
+		Status(0).
+		Build()
+	c.Logger.Info("Ignore api created successfully", zap.String("path", request.Path), zap.Int("id", ignoreApi.ID))
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetAllIgnoreApis
+// @Summary get all ignore apis
+// @Description get all ignore apis
+// @Tags ignore api
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[[]ResponseIgnoreApi]
+// @Router /v1/ignore-api [get]
+func (c *IgnoreApiController) GetAllIgnoreApis(ctx *gin.Context) {
+	c.Logger.Info("Getting all ignore apis")
+	ignoreApis, err := c.ignoreApiService.GetAll(ctx.Request.Context())
+	if err != nil {
+		c.Logger.Error("Error getting all ignore apis", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[*[]ResponseIgnoreApi]().
+		Data(arrayDomainToResponseMapper(ignoreApis)).
+		Message("success").
+		Status(0).
+		Build()
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetIgnoreApiByID
+// @Summary get ignore api
+// @Description get ignore api by id
+// @Tags ignore api
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[ResponseIgnoreApi]
+// @Router /v1/ignore-api/{id} [get]
+func (c *IgnoreApiController) GetIgnoreApiByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid ignore api ID parameter", zap.Error(err), zap.String("id", ctx.Param("id")))
+		appError := domainErrors.NewAppError(errors.New("ignore api id is invalid"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	ignoreApi, err := c.ignoreApiService.GetByID(ctx.Request.Context(), id)
+	if err != nil {
+		c.Logger.Error("Error getting ignore api by ID", zap.Error(err), zap.Int("id", id))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[*ResponseIgnoreApi]().
+		Data(domainToResponseMapper(ignoreApi)).
+		Message("success").
+		Status(0).
+		Build()
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateIgnoreApi
+// @Summary update ignore api
+// @Description update ignore api
+// @Tags ignore api
+// @Accept json
+// @Produce json
+// @Param book body map[string]any  true  "JSON Data"
+// @Success 200 {object} domain.CommonResponse[ResponseIgnoreApi]
+// @Router /v1/ignore-api/{id} [put]
+func (c *IgnoreApiController) UpdateIgnoreApi(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid ignore api ID parameter for update", zap.Error(err), zap.String("id", ctx.Param("id")))
+		appError := domainErrors.NewAppError(errors.New("param id is necessary"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	var requestMap map[string]any
+	err = controllers.BindJSONMap(ctx, &requestMap)
+	if err != nil {
+		c.Logger.Error("Error binding JSON for ignore api update", zap.Error(err), zap.Int("id", id))
+		appError := domainErrors.NewAppError(err, domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	err = updateValidation(requestMap)
+	if err != nil {
+		c.Logger.Error("Validation error for ignore api update", zap.Error(err), zap.Int("id", id))
+		_ = ctx.Error(err)
+		return
+	}
+	ignoreApi, err := c.ignoreApiService.Update(ctx.Request.Context(), id, requestMap)
+	if err != nil {
+		c.Logger.Error("Error updating ignore api", zap.Error(err), zap.Int("id", id))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[*ResponseIgnoreApi]().
+		Data(domainToResponseMapper(ignoreApi)).
+		Message("success").
+		Status(0).
+		Build()
+	c.Logger.Info("Ignore api updated successfully", zap.Int("id", id))
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteIgnoreApi
+// @Summary delete ignore api
+// @Description delete ignore api by id
+// @Tags ignore api
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[int]
+// @Router /v1/ignore-api/{id} [delete]
+func (c *IgnoreApiController) DeleteIgnoreApi(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid ignore api ID parameter for deletion", zap.Error(err), zap.String("id", ctx.Param("id")))
+		appError := domainErrors.NewAppError(errors.New("param id is necessary"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	err = c.ignoreApiService.Delete(ctx.Request.Context(), []int{id})
+	if err != nil {
+		c.Logger.Error("Error deleting ignore api", zap.Error(err), zap.Int("id", id))
+		_ = ctx.Error(err)
+		return
+	}
+	c.Logger.Info("Ignore api deleted successfully", zap.Int("id", id))
+	ctx.JSON(http.StatusOK, domain.CommonResponse[int]{
+		Data:    id,
+		Message: "resource deleted successfully",
+		Status:  0,
+	})
+}
+
+// DeleteIgnoreApis
+// @Summary delete ignore apis
+// @Description delete ignore apis by id
+// @Tags ignore api
+// @Accept json
+// @Produce json
+// @Param book body DeleteBatchIgnoreApiRequest true  "JSON Data"
+// @Success 200 {object} domain.CommonResponse[[]int]
+// @Router /v1/ignore-api/delete-batch [post]
+func (c *IgnoreApiController) DeleteIgnoreApis(ctx *gin.Context) {
+	var request DeleteBatchIgnoreApiRequest
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		c.Logger.Error("Error binding JSON for ignore api batch delete", zap.Error(err))
+		appError := domainErrors.NewAppError(err, domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	err := c.ignoreApiService.Delete(ctx.Request.Context(), request.IDS)
+	if err != nil {
+		c.Logger.Error("Error deleting ignore apis", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", request.IDS)))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, domain.CommonResponse[[]int]{
+		Data:    request.IDS,
+		Message: "resource deleted successfully",
+		Status:  0,
+	})
+}
+
+// Mappers
+func domainToResponseMapper(ignoreApi *domainIgnoreApi.IgnoreApi) *ResponseIgnoreApi {
+	return &ResponseIgnoreApi{
+		ID:          ignoreApi.ID,
+		Path:        ignoreApi.Path,
+		Method:      ignoreApi.Method,
+		Description: ignoreApi.Description,
+		CreatedAt:   domain.CustomTime{Time: ignoreApi.CreatedAt},
+		UpdatedAt:   domain.CustomTime{Time: ignoreApi.UpdatedAt},
+	}
+}
+
+func arrayDomainToResponseMapper(ignoreApis *[]domainIgnoreApi.IgnoreApi) *[]ResponseIgnoreApi {
+	res := make([]ResponseIgnoreApi, len(*ignoreApis))
+	for i, ignoreApi := range *ignoreApis {
+		res[i] = *domainToResponseMapper(&ignoreApi)
+	}
+	return &res
+}
@@ -0,0 +1,14 @@
+package ignore_api
+
+import "github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+
+var customRules = map[string]string{
+	"path":        "required,max=255",
+	"method":      "omitempty,max=20",
+	"description": "omitempty",
+}
+
+func updateValidation(request map[string]any) error {
+	validator := controllers.NewCommonValidator(customRules)
+	return validator.ValidateUpdate(request)
+}
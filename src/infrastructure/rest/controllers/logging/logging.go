@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"net/http"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainLogging "github.com/gbrayhan/microservices-go/src/domain/sys/logging"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetLevelRequest changes the default level when component is empty
+type SetLevelRequest struct {
+	Component string `json:"component"`
+	Level     string `json:"level" binding:"required"`
+}
+
+type ILoggingController interface {
+	GetLevels(ctx *gin.Context)
+	SetLevel(ctx *gin.Context)
+	ResetLevel(ctx *gin.Context)
+	Reload(ctx *gin.Context)
+}
+
+type LoggingController struct {
+	loggingService domainLogging.ILoggingService
+	Logger         *logger.Logger
+}
+
+func NewLoggingController(loggingService domainLogging.ILoggingService, loggerInstance *logger.Logger) ILoggingController {
+	return &LoggingController{loggingService: loggingService, Logger: loggerInstance}
+}
+
+// GetLevels
+// @Summary log levels
+// @Description default level, per component overrides and the components that log
+// @Tags logging
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[domainLogging.Levels]
+// @Router /v1/logging/levels [get]
+func (c *LoggingController) GetLevels(ctx *gin.Context) {
+	levels, err := c.loggingService.GetLevels()
+	if err != nil {
+		c.Logger.Error("Error getting log levels", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainLogging.Levels]().
+		Data(levels).Message("success").Status(0).Build())
+}
+
+// SetLevel
+// @Summary change a log level
+// @Description changes the level of a component, or the default level without component, until the next reload
+// @Tags logging
+// @Accept json
+// @Produce json
+// @Param request body SetLevelRequest true "component and level"
+// @Success 200 {object} domain.CommonResponse[domainLogging.Levels]
+// @Router /v1/logging/levels [put]
+func (c *LoggingController) SetLevel(ctx *gin.Context) {
+	var request SetLevelRequest
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	levels, err := c.loggingService.SetLevel(request.Component, request.Level)
+	if err != nil {
+		c.Logger.Error("Error setting log level", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainLogging.Levels]().
+		Data(levels).Message("success").Status(0).Build())
+}
+
+// ResetLevel
+// @Summary remove a log level override
+// @Description the component follows the default level again
+// @Tags logging
+// @Produce json
+// @Param component path string true "component name"
+// @Success 200 {object} domain.CommonResponse[domainLogging.Levels]
+// @Router /v1/logging/levels/{component} [delete]
+func (c *LoggingController) ResetLevel(ctx *gin.Context) {
+	levels, err := c.loggingService.ResetLevel(ctx.Param("component"))
+	if err != nil {
+		c.Logger.Error("Error resetting log level", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainLogging.Levels]().
+		Data(levels).Message("success").Status(0).Build())
+}
+
+// Reload
+// @Summary reload the logging configuration
+// @Description applies the levels and outputs of the "log" module of sys_config
+// @Tags logging
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[domainLogging.Levels]
+// @Router /v1/logging/reload [post]
+func (c *LoggingController) Reload(ctx *gin.Context) {
+	levels, err := c.loggingService.Reload(ctx.Request.Context())
+	if err != nil {
+		c.Logger.Error("Error reloading logging configuration", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainLogging.Levels]().
+		Data(levels).Message("success").Status(0).Build())
+}
@@ -79,7 +79,7 @@ func (c *MenuController) NewMenu(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	menuModel, err := c.menuService.Create(toUsecaseMapper(&request))
+	menuModel, err := c.menuService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating menu", zap.Error(err), zap.String("path", request.Path))
 		_ = ctx.Error(err)
@@ -108,7 +108,7 @@ func (c *MenuController) GetAllMenus(ctx *gin.Context) {
 	if err != nil {
 		groupId = 0
 	}
-	menus, err := c.menuService.GetAll(groupId)
+	menus, err := c.menuService.GetAll(ctx.Request.Context(), groupId)
 	if err != nil {
 		c.Logger.Error("Error getting all menus", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -144,7 +144,7 @@ func (c *MenuController) GetMenusByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting menu by ID", zap.Int("id", menuID))
-	menu, err := c.menuService.GetByID(menuID)
+	menu, err := c.menuService.GetByID(ctx.Request.Context(), menuID)
 	if err != nil {
 		c.Logger.Error("Error getting menu by ID", zap.Error(err), zap.Int("id", menuID))
 		_ = ctx.Error(err)
@@ -186,7 +186,8 @@ func (c *MenuController) UpdateMenu(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	menuUpdated, err := c.menuService.Update(menuID, requestMap)
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	menuUpdated, err := c.menuService.Update(ctx.Request.Context(), menuID, int64(actorId), requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating menu", zap.Error(err), zap.Int("id", menuID))
 		_ = ctx.Error(err)
@@ -218,7 +219,7 @@ func (c *MenuController) DeleteMenu(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting menu", zap.Int("id", menuID))
-	err = c.menuService.Delete(menuID)
+	err = c.menuService.Delete(ctx.Request.Context(), menuID)
 	if err != nil {
 		c.Logger.Error("Error deleting menu", zap.Error(err), zap.Int("id", menuID))
 		_ = ctx.Error(err)
@@ -259,7 +260,7 @@ func (c *MenuController) GetUserMenus(ctx *gin.Context) {
 			return
 		}
 	}
-	menus, err := c.menuService.GetUserMenus(roleID)
+	menus, err := c.menuService.GetUserMenus(ctx.Request.Context(), roleID)
 	if err != nil {
 		c.Logger.Error("Error getting all menu user", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
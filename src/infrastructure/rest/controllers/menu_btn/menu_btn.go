@@ -35,6 +35,8 @@ type IMenuBtnController interface {
 	GetMenuBtnsByID(ctx *gin.Context)
 	UpdateMenuBtn(ctx *gin.Context)
 	DeleteMenuBtn(ctx *gin.Context)
+	GetMenuBtnApis(ctx *gin.Context)
+	BindMenuBtnApis(ctx *gin.Context)
 }
 type MenuBtnController struct {
 	menuBtnService domainMenuBtn.IMenuBtnService
@@ -63,7 +65,7 @@ func (c *MenuBtnController) NewMenuBtn(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	menuBtnModel, err := c.menuBtnService.Create(toUsecaseMapper(&request))
+	menuBtnModel, err := c.menuBtnService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating menuBtn", zap.Error(err), zap.String("Name", request.Name))
 		_ = ctx.Error(err)
@@ -95,7 +97,7 @@ func (c *MenuBtnController) GetAllMenuBtns(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	menuBtns, err := c.menuBtnService.GetAll(int64(menuBaseID))
+	menuBtns, err := c.menuBtnService.GetAll(ctx.Request.Context(), int64(menuBaseID))
 	if err != nil {
 		c.Logger.Error("Error getting all menuBtns", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -125,7 +127,7 @@ func (c *MenuBtnController) GetMenuBtnsByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting menuBtn by ID", zap.Int("id", menuBtnID))
-	menuBtn, err := c.menuBtnService.GetByID(menuBtnID)
+	menuBtn, err := c.menuBtnService.GetByID(ctx.Request.Context(), menuBtnID)
 	if err != nil {
 		c.Logger.Error("Error getting menuBtn by ID", zap.Error(err), zap.Int("id", menuBtnID))
 		_ = ctx.Error(err)
@@ -167,7 +169,7 @@ func (c *MenuBtnController) UpdateMenuBtn(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	menuBtnUpdated, err := c.menuBtnService.Update(menuBtnID, requestMap)
+	menuBtnUpdated, err := c.menuBtnService.Update(ctx.Request.Context(), menuBtnID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating menuBtn", zap.Error(err), zap.Int("id", menuBtnID))
 		_ = ctx.Error(err)
@@ -199,7 +201,7 @@ func (c *MenuBtnController) DeleteMenuBtn(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting menuBtn", zap.Int("id", menuBtnID))
-	err = c.menuBtnService.Delete(menuBtnID)
+	err = c.menuBtnService.Delete(ctx.Request.Context(), menuBtnID)
 	if err != nil {
 		c.Logger.Error("Error deleting menuBtn", zap.Error(err), zap.Int("id", menuBtnID))
 		_ = ctx.Error(err)
@@ -213,6 +215,76 @@ func (c *MenuBtnController) DeleteMenuBtn(ctx *gin.Context) {
 	})
 }
 
+// GetMenuBtnApis
+// @Summary get menuBtn apis
+// @Description get the ids of the apis guarded by the menuBtn
+// @Tags menuBtn
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[[]int64]
+// @Router /v1/menu_btn/{id}/api [get]
+func (c *MenuBtnController) GetMenuBtnApis(ctx *gin.Context) {
+	menuBtnID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid menuBtn ID parameter for get apis", zap.Error(err), zap.String("id", ctx.Param("id")))
+		appError := domainErrors.NewAppError(errors.New("param id is necessary"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	apiIds, err := c.menuBtnService.GetBtnApiIds(ctx.Request.Context(), menuBtnID)
+	if err != nil {
+		c.Logger.Error("Error getting menuBtn apis", zap.Error(err), zap.Int("id", menuBtnID))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[[]int64]().
+		Data(apiIds).
+		Message("success").
+		Status(0).
+		Build()
+	ctx.JSON(http.StatusOK, response)
+}
+
+// BindMenuBtnApis
+// @Summary bind menuBtn apis
+// @Description bind the apis guarded by the menuBtn
+// @Tags menuBtn
+// @Accept json
+// @Produce json
+// @Param book body map[string]any  true  "JSON Data"
+// @Success 200 {object} domain.CommonResponse[bool]
+// @Router /v1/menu_btn/{id}/api [post]
+func (c *MenuBtnController) BindMenuBtnApis(ctx *gin.Context) {
+	menuBtnID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid menuBtn ID parameter for bind apis", zap.Error(err), zap.String("id", ctx.Param("id")))
+		appError := domainErrors.NewAppError(errors.New("param id is necessary"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	var requestMap map[string]any
+	err = controllers.BindJSONMap(ctx, &requestMap)
+	if err != nil {
+		c.Logger.Error("Error binding JSON for menuBtn apis", zap.Error(err), zap.Int("id", menuBtnID))
+		appError := domainErrors.NewAppError(err, domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	err = c.menuBtnService.BindBtnApis(ctx.Request.Context(), menuBtnID, requestMap)
+	if err != nil {
+		c.Logger.Error("Error binding menuBtn apis", zap.Error(err), zap.Int("id", menuBtnID))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[bool]().
+		Data(true).
+		Message("success").
+		Status(0).
+		Build()
+	c.Logger.Info("MenuBtn apis bound successfully", zap.Int("id", menuBtnID))
+	ctx.JSON(http.StatusOK, response)
+}
+
 // Mappers
 func domainToResponseMapper(domainMenuBtn *domainMenuBtn.MenuBtn) *ResponseMenuBtn {
 
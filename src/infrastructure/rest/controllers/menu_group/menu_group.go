@@ -71,7 +71,7 @@ func (c *MenuGroupController) NewMenuGroup(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	menuGroupModel, err := c.menuGroupService.Create(toUsecaseMapper(&request))
+	menuGroupModel, err := c.menuGroupService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating menuGroup", zap.Error(err), zap.String("Name", request.Name))
 		_ = ctx.Error(err)
@@ -96,7 +96,7 @@ func (c *MenuGroupController) NewMenuGroup(ctx *gin.Context) {
 // @Router /v1/menuGroup [get]
 func (c *MenuGroupController) GetAllMenuGroups(ctx *gin.Context) {
 	c.Logger.Info("Getting all dictionaries")
-	dictionaries, err := c.menuGroupService.GetAll()
+	dictionaries, err := c.menuGroupService.GetAll(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all dictionaries", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -126,7 +126,7 @@ func (c *MenuGroupController) GetMenuGroupsByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting menuGroup by ID", zap.Int("id", menuGroupID))
-	menuGroup, err := c.menuGroupService.GetByID(menuGroupID)
+	menuGroup, err := c.menuGroupService.GetByID(ctx.Request.Context(), menuGroupID)
 	if err != nil {
 		c.Logger.Error("Error getting menuGroup by ID", zap.Error(err), zap.Int("id", menuGroupID))
 		_ = ctx.Error(err)
@@ -168,7 +168,7 @@ func (c *MenuGroupController) UpdateMenuGroup(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	menuGroupUpdated, err := c.menuGroupService.Update(menuGroupID, requestMap)
+	menuGroupUpdated, err := c.menuGroupService.Update(ctx.Request.Context(), menuGroupID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating menuGroup", zap.Error(err), zap.Int("id", menuGroupID))
 		_ = ctx.Error(err)
@@ -200,7 +200,7 @@ func (c *MenuGroupController) DeleteMenuGroup(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting menuGroup", zap.Int("id", menuGroupID))
-	err = c.menuGroupService.Delete([]int{menuGroupID})
+	err = c.menuGroupService.Delete(ctx.Request.Context(), []int{menuGroupID})
 	if err != nil {
 		c.Logger.Error("Error deleting menuGroup", zap.Error(err), zap.Int("id", menuGroupID))
 		_ = ctx.Error(err)
@@ -299,7 +299,7 @@ func (c *MenuGroupController) SearchPaginated(ctx *gin.Context) {
 		filters.SortDirection = sortDirection
 	}
 
-	result, err := c.menuGroupService.SearchPaginated(filters)
+	result, err := c.menuGroupService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching dictionaries", zap.Error(err))
 		_ = ctx.Error(err)
@@ -359,7 +359,7 @@ func (c *MenuGroupController) SearchByProperty(ctx *gin.Context) {
 		return
 	}
 
-	coincidences, err := c.menuGroupService.SearchByProperty(property, searchText)
+	coincidences, err := c.menuGroupService.SearchByProperty(ctx.Request.Context(), property, searchText)
 	if err != nil {
 		c.Logger.Error("Error searching by property", zap.Error(err), zap.String("property", property))
 		_ = ctx.Error(err)
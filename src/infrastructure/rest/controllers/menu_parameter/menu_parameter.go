@@ -63,7 +63,7 @@ func (c *MenuParameterController) NewMenuParameter(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	menuParameterModel, err := c.menuParameterService.Create(toUsecaseMapper(&request))
+	menuParameterModel, err := c.menuParameterService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating menuParameter", zap.Error(err), zap.String("Key", request.Key))
 		_ = ctx.Error(err)
@@ -97,7 +97,7 @@ func (c *MenuParameterController) GetAllMenuParameters(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	menuParameters, err := c.menuParameterService.GetAll(int64(menuBaseID))
+	menuParameters, err := c.menuParameterService.GetAll(ctx.Request.Context(), int64(menuBaseID))
 	if err != nil {
 		c.Logger.Error("Error getting all menuParameters", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -127,7 +127,7 @@ func (c *MenuParameterController) GetMenuParametersByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting menuParameter by ID", zap.Int("id", menuParameterID))
-	menuParameter, err := c.menuParameterService.GetByID(menuParameterID)
+	menuParameter, err := c.menuParameterService.GetByID(ctx.Request.Context(), menuParameterID)
 	if err != nil {
 		c.Logger.Error("Error getting menuParameter by ID", zap.Error(err), zap.Int("id", menuParameterID))
 		_ = ctx.Error(err)
@@ -169,7 +169,7 @@ func (c *MenuParameterController) UpdateMenuParameter(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	menuParameterUpdated, err := c.menuParameterService.Update(menuParameterID, requestMap)
+	menuParameterUpdated, err := c.menuParameterService.Update(ctx.Request.Context(), menuParameterID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating menuParameter", zap.Error(err), zap.Int("id", menuParameterID))
 		_ = ctx.Error(err)
@@ -201,7 +201,7 @@ func (c *MenuParameterController) DeleteMenuParameter(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting menuParameter", zap.Int("id", menuParameterID))
-	err = c.menuParameterService.Delete(menuParameterID)
+	err = c.menuParameterService.Delete(ctx.Request.Context(), menuParameterID)
 	if err != nil {
 		c.Logger.Error("Error deleting menuParameter", zap.Error(err), zap.Int("id", menuParameterID))
 		_ = ctx.Error(err)
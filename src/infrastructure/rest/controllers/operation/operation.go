@@ -1,12 +1,17 @@
 package operation
 
 import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
+	operationUseCase "github.com/gbrayhan/microservices-go/src/application/services/sys/operation_record"
 	"github.com/gbrayhan/microservices-go/src/domain"
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
 	domainOperation "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
@@ -33,6 +38,7 @@ type ResponseOperation struct {
 	ErrorMessage string            `json:"error_message"`
 	Body         string            `json:"body"`
 	Resp         string            `json:"resp"`
+	RequestID    string            `json:"request_id"`
 	CreatedAt    domain.CustomTime `json:"created_at,omitempty"`
 	UpdatedAt    domain.CustomTime `json:"updated_at,omitempty"`
 }
@@ -42,14 +48,23 @@ type IOperationController interface {
 	DeleteOperation(ctx *gin.Context)
 	DeleteOperations(ctx *gin.Context)
 	SearchPaginated(ctx *gin.Context)
+	Export(ctx *gin.Context)
+	LatencyPercentiles(ctx *gin.Context)
+	ErrorRates(ctx *gin.Context)
+	TopUsers(ctx *gin.Context)
+	SlowestEndpoints(ctx *gin.Context)
 }
 type OperationController struct {
 	operationService domainOperation.ISysOperationRecordService
+	analyticsService operationUseCase.ISysOperationAnalyticsService
 	Logger           *logger.Logger
 }
 
-func NewOperationController(operationService domainOperation.ISysOperationRecordService, loggerInstance *logger.Logger) IOperationController {
-	return &OperationController{operationService: operationService, Logger: loggerInstance}
+func NewOperationController(
+	operationService domainOperation.ISysOperationRecordService,
+	analyticsService operationUseCase.ISysOperationAnalyticsService,
+	loggerInstance *logger.Logger) IOperationController {
+	return &OperationController{operationService: operationService, analyticsService: analyticsService, Logger: loggerInstance}
 }
 
 // GetAllOperations
@@ -62,7 +77,7 @@ func NewOperationController(operationService domainOperation.ISysOperationRecord
 // @Router /v1/operation [get]
 func (c *OperationController) GetAllOperations(ctx *gin.Context) {
 	c.Logger.Info("Getting all operations")
-	operations, err := c.operationService.GetAll()
+	operations, err := c.operationService.GetAll(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all operations", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -92,7 +107,7 @@ func (c *OperationController) GetOperationsByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting operation by ID", zap.Int("id", operationID))
-	operation, err := c.operationService.GetByID(operationID)
+	operation, err := c.operationService.GetByID(ctx.Request.Context(), operationID)
 	if err != nil {
 		c.Logger.Error("Error getting operation by ID", zap.Error(err), zap.Int("id", operationID))
 		_ = ctx.Error(err)
@@ -119,7 +134,7 @@ func (c *OperationController) DeleteOperation(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting operation", zap.Int("id", operationID))
-	err = c.operationService.Delete([]int{operationID})
+	err = c.operationService.Delete(ctx.Request.Context(), []int{operationID})
 	if err != nil {
 		c.Logger.Error("Error deleting operation", zap.Error(err), zap.Int("id", operationID))
 		_ = ctx.Error(err)
@@ -153,7 +168,7 @@ func (c *OperationController) DeleteOperations(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting operation", zap.String("ids", fmt.Sprintf("%v", request.IDS)))
-	err = c.operationService.Delete(request.IDS)
+	err = c.operationService.Delete(ctx.Request.Context(), request.IDS)
 	if err != nil {
 		c.Logger.Error("Error deleting operation", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", request.IDS)))
 		_ = ctx.Error(err)
@@ -253,7 +268,7 @@ func (c *OperationController) SearchPaginated(ctx *gin.Context) {
 	}
 	fmt.Println(sortBy, sortDirection)
 
-	result, err := c.operationService.SearchPaginated(filters)
+	result, err := c.operationService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching operations", zap.Error(err))
 		_ = ctx.Error(err)
@@ -279,6 +294,218 @@ func (c *OperationController) SearchPaginated(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// ExportOperations
+// @Summary export operations
+// @Description stream the operations created in [start, end) as CSV or JSONL, optionally gzip compressed
+// @Tags operations
+// @Produce text/csv,application/x-ndjson,application/gzip
+// @Param start query string true "RFC3339 start time"
+// @Param end query string true "RFC3339 end time"
+// @Param format query string false "csv or jsonl, default jsonl"
+// @Param compress query string false "gzip to compress the export"
+// @Success 200 {file} file
+// @Router /v1/operation/export [get]
+func (c *OperationController) Export(ctx *gin.Context) {
+	start, err := time.Parse(time.RFC3339, ctx.Query("start"))
+	if err != nil {
+		appError := domainErrors.NewAppError(errors.New("start must be an RFC3339 time"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	end, err := time.Parse(time.RFC3339, ctx.Query("end"))
+	if err != nil || !end.After(start) {
+		appError := domainErrors.NewAppError(errors.New("end must be an RFC3339 time after start"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	format := ctx.DefaultQuery("format", "jsonl")
+	contentType := "application/x-ndjson"
+	switch format {
+	case "jsonl":
+	case "csv":
+		contentType = "text/csv"
+	default:
+		appError := domainErrors.NewAppError(errors.New("format must be csv or jsonl"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	fileName := fmt.Sprintf("operations_%s_%s.%s", start.Format("20060102150405"), end.Format("20060102150405"), format)
+
+	// the response is streamed, errors after the first byte can only be logged
+	var writer io.Writer = ctx.Writer
+	if ctx.Query("compress") == "gzip" {
+		gzipWriter := gzip.NewWriter(ctx.Writer)
+		defer gzipWriter.Close()
+		writer = gzipWriter
+		contentType = "application/gzip"
+		fileName += ".gz"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Status(http.StatusOK)
+
+	var write func(record *domainOperation.SysOperationRecord) error
+	if format == "csv" {
+		csvWriter := csv.NewWriter(writer)
+		defer csvWriter.Flush()
+		if err := csvWriter.Write(domainOperation.CSVHeader); err != nil {
+			c.Logger.Error("Error writing operations export", zap.Error(err))
+			return
+		}
+		write = func(record *domainOperation.SysOperationRecord) error {
+			return csvWriter.Write(record.ToExport().CSVRow())
+		}
+	} else {
+		encoder := json.NewEncoder(writer)
+		write = func(record *domainOperation.SysOperationRecord) error {
+			return encoder.Encode(record.ToExport())
+		}
+	}
+	if err := c.operationService.Export(ctx.Request.Context(), start, end, write); err != nil {
+		c.Logger.Error("Error exporting operations", zap.Error(err))
+		return
+	}
+	c.Logger.Info("Operations exported", zap.Time("start", start), zap.Time("end", end), zap.String("format", format))
+}
+
+// LatencyPercentiles
+// @Summary operation latency percentiles
+// @Description p50/p95/p99 latency in milliseconds per route, slowest p95 first
+// @Tags operations
+// @Produce json
+// @Param start query string false "RFC3339 start time, default 24 hours before end"
+// @Param end query string false "RFC3339 end time, default now"
+// @Param limit query int false "number of routes, default 20"
+// @Success 200 {object} domain.CommonResponse[[]domainOperation.RouteLatency]
+// @Router /v1/operation/analytics/latency [get]
+func (c *OperationController) LatencyPercentiles(ctx *gin.Context) {
+	query, ok := parseAnalyticsQuery(ctx)
+	if !ok {
+		return
+	}
+	rows, err := c.analyticsService.LatencyPercentiles(ctx.Request.Context(), query)
+	if err != nil {
+		c.Logger.Error("Error computing latency percentiles", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*[]domainOperation.RouteLatency]().
+		Data(rows).Message("success").Status(0).Build())
+}
+
+// ErrorRates
+// @Summary operation error rates
+// @Description 4xx and 5xx counts and error rate per time bucket
+// @Tags operations
+// @Produce json
+// @Param start query string false "RFC3339 start time, default 24 hours before end"
+// @Param end query string false "RFC3339 end time, default now"
+// @Param bucket query string false "minute, hour or day, default hour"
+// @Success 200 {object} domain.CommonResponse[[]domainOperation.ErrorRateBucket]
+// @Router /v1/operation/analytics/error-rate [get]
+func (c *OperationController) ErrorRates(ctx *gin.Context) {
+	query, ok := parseAnalyticsQuery(ctx)
+	if !ok {
+		return
+	}
+	query.Bucket = ctx.DefaultQuery("bucket", domainOperation.BucketHour)
+	if !domainOperation.IsValidBucket(query.Bucket) {
+		appError := domainErrors.NewAppError(errors.New("bucket must be minute, hour or day"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	rows, err := c.analyticsService.ErrorRates(ctx.Request.Context(), query)
+	if err != nil {
+		c.Logger.Error("Error computing error rates", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*[]domainOperation.ErrorRateBucket]().
+		Data(rows).Message("success").Status(0).Build())
+}
+
+// TopUsers
+// @Summary most active users
+// @Description users ranked by request count
+// @Tags operations
+// @Produce json
+// @Param start query string false "RFC3339 start time, default 24 hours before end"
+// @Param end query string false "RFC3339 end time, default now"
+// @Param limit query int false "number of users, default 20"
+// @Success 200 {object} domain.CommonResponse[[]domainOperation.UserActivity]
+// @Router /v1/operation/analytics/top-users [get]
+func (c *OperationController) TopUsers(ctx *gin.Context) {
+	query, ok := parseAnalyticsQuery(ctx)
+	if !ok {
+		return
+	}
+	rows, err := c.analyticsService.TopUsers(ctx.Request.Context(), query)
+	if err != nil {
+		c.Logger.Error("Error computing top users", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*[]domainOperation.UserActivity]().
+		Data(rows).Message("success").Status(0).Build())
+}
+
+// SlowestEndpoints
+// @Summary slowest endpoints
+// @Description routes ranked by average latency in milliseconds
+// @Tags operations
+// @Produce json
+// @Param start query string false "RFC3339 start time, default 24 hours before end"
+// @Param end query string false "RFC3339 end time, default now"
+// @Param limit query int false "number of routes, default 20"
+// @Success 200 {object} domain.CommonResponse[[]domainOperation.SlowEndpoint]
+// @Router /v1/operation/analytics/slowest [get]
+func (c *OperationController) SlowestEndpoints(ctx *gin.Context) {
+	query, ok := parseAnalyticsQuery(ctx)
+	if !ok {
+		return
+	}
+	rows, err := c.analyticsService.SlowestEndpoints(ctx.Request.Context(), query)
+	if err != nil {
+		c.Logger.Error("Error computing slowest endpoints", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*[]domainOperation.SlowEndpoint]().
+		Data(rows).Message("success").Status(0).Build())
+}
+
+// parseAnalyticsQuery reads start, end and limit, the period defaults to the last 24 hours
+func parseAnalyticsQuery(ctx *gin.Context) (domainOperation.AnalyticsQuery, bool) {
+	query := domainOperation.AnalyticsQuery{End: time.Now().Truncate(time.Minute), Limit: 20}
+	if endStr := ctx.Query("end"); endStr != "" {
+		end, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			_ = ctx.Error(domainErrors.NewAppError(errors.New("end must be an RFC3339 time"), domainErrors.ValidationError))
+			return query, false
+		}
+		query.End = end
+	}
+	query.Start = query.End.Add(-24 * time.Hour)
+	if startStr := ctx.Query("start"); startStr != "" {
+		start, err := time.Parse(time.RFC3339, startStr)
+		if err != nil || !query.End.After(start) {
+			_ = ctx.Error(domainErrors.NewAppError(errors.New("start must be an RFC3339 time before end"), domainErrors.ValidationError))
+			return query, false
+		}
+		query.Start = start
+	}
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > domainOperation.MaxAnalyticsLimit {
+			_ = ctx.Error(domainErrors.NewAppError(
+				fmt.Errorf("limit must be between 1 and %d", domainOperation.MaxAnalyticsLimit), domainErrors.ValidationError))
+			return query, false
+		}
+		query.Limit = limit
+	}
+	return query, true
+}
+
 // Mappers
 func domainToResponseMapper(domainOperation *domainOperation.SysOperationRecord) *ResponseOperation {
 
@@ -293,6 +520,7 @@ func domainToResponseMapper(domainOperation *domainOperation.SysOperationRecord)
 		ErrorMessage: domainOperation.ErrorMessage,
 		Body:         domainOperation.Body,
 		Resp:         domainOperation.Resp,
+		RequestID:    domainOperation.RequestID,
 		CreatedAt:    domainOperation.CreatedAt,
 		UpdatedAt:    domainOperation.UpdatedAt,
 	}
@@ -0,0 +1,151 @@
+package rbac
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainRbac "github.com/gbrayhan/microservices-go/src/domain/sys/rbac"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+type IRbacController interface {
+	Export(ctx *gin.Context)
+	Preview(ctx *gin.Context)
+	Import(ctx *gin.Context)
+}
+
+type RbacController struct {
+	rbacService domainRbac.IRbacService
+	Logger      *logger.Logger
+}
+
+func NewRbacController(rbacService domainRbac.IRbacService, loggerInstance *logger.Logger) IRbacController {
+	return &RbacController{rbacService: rbacService, Logger: loggerInstance}
+}
+
+// Export
+// @Summary export rbac configuration
+// @Description export roles, menus, buttons, apis and casbin policies as a versioned document
+// @Tags rbac
+// @Produce json
+// @Param format query string false "json or yaml"
+// @Success 200 {object} domainRbac.Document
+// @Router /v1/rbac/export [get]
+func (c *RbacController) Export(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", formatJSON)
+	if format != formatJSON && format != formatYAML {
+		appError := domainErrors.NewAppError(errors.New("format must be json or yaml"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	doc, err := c.rbacService.Export(ctx.Request.Context())
+	if err != nil {
+		c.Logger.Error("Error exporting rbac configuration", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	var content []byte
+	contentType := "application/json"
+	if format == formatYAML {
+		content, err = yaml.Marshal(doc)
+		contentType = "application/x-yaml"
+	} else {
+		content, err = json.MarshalIndent(doc, "", "  ")
+	}
+	if err != nil {
+		c.Logger.Error("Error encoding rbac configuration", zap.Error(err))
+		_ = ctx.Error(domainErrors.NewAppErrorWithType(domainErrors.UnknownError))
+		return
+	}
+	fileName := fmt.Sprintf("rbac-%s.%s", time.Now().Format("20060102150405"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	ctx.Data(http.StatusOK, contentType, content)
+}
+
+// Preview
+// @Summary preview rbac import
+// @Description diff an rbac document against the current configuration without applying it
+// @Tags rbac
+// @Accept json
+// @Produce json
+// @Param prune query bool false "delete entities missing from the document"
+// @Success 200 {object} domain.CommonResponse[domainRbac.ImportResult]
+// @Router /v1/rbac/import/preview [post]
+func (c *RbacController) Preview(ctx *gin.Context) {
+	c.handleImport(ctx, true)
+}
+
+// Import
+// @Summary import rbac configuration
+// @Description apply an rbac document in a single transaction
+// @Tags rbac
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "only compute the changes"
+// @Param prune query bool false "delete entities missing from the document"
+// @Success 200 {object} domain.CommonResponse[domainRbac.ImportResult]
+// @Router /v1/rbac/import [post]
+func (c *RbacController) Import(ctx *gin.Context) {
+	dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	c.handleImport(ctx, dryRun)
+}
+
+func (c *RbacController) handleImport(ctx *gin.Context, dryRun bool) {
+	prune, _ := strconv.ParseBool(ctx.DefaultQuery("prune", "false"))
+	doc, err := decodeDocument(ctx)
+	if err != nil {
+		c.Logger.Error("Error decoding rbac document", zap.Error(err))
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	result, err := c.rbacService.Import(ctx.Request.Context(), doc, domainRbac.ImportOptions{DryRun: dryRun, Prune: prune})
+	if err != nil {
+		c.Logger.Error("Error importing rbac configuration", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[*domainRbac.ImportResult]().
+		Data(result).
+		Message("success").
+		Status(0).
+		Build()
+	ctx.JSON(http.StatusOK, response)
+}
+
+// decodeDocument reads the document as yaml when asked via ?format=yaml or a yaml content type, json otherwise
+func decodeDocument(ctx *gin.Context) (*domainRbac.Document, error) {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, errors.New("document is empty")
+	}
+	var doc domainRbac.Document
+	if ctx.Query("format") == formatYAML || strings.Contains(ctx.ContentType(), formatYAML) {
+		err = yaml.Unmarshal(body, &doc)
+	} else {
+		err = json.Unmarshal(body, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
@@ -82,7 +82,7 @@ func (c *RoleController) NewRole(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	roleModel, err := c.roleService.Create(toUsecaseMapper(&request))
+	roleModel, err := c.roleService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating role", zap.Error(err), zap.String("Name", request.Name))
 		_ = ctx.Error(err)
@@ -111,7 +111,7 @@ func (c *RoleController) GetAllRoles(ctx *gin.Context) {
 	if err != nil {
 		status = 0
 	}
-	roles, err := c.roleService.GetAll(status)
+	roles, err := c.roleService.GetAll(ctx.Request.Context(), status)
 	if err != nil {
 		c.Logger.Error("Error getting all roles", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -144,7 +144,7 @@ func (c *RoleController) GetRolesByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting role by ID", zap.Int("id", roleID))
-	role, err := c.roleService.GetByID(roleID)
+	role, err := c.roleService.GetByID(ctx.Request.Context(), roleID)
 	if err != nil {
 		c.Logger.Error("Error getting role by ID", zap.Error(err), zap.Int("id", roleID))
 		_ = ctx.Error(err)
@@ -186,7 +186,8 @@ func (c *RoleController) UpdateRole(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	roleUpdated, err := c.roleService.Update(roleID, requestMap)
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	roleUpdated, err := c.roleService.Update(ctx.Request.Context(), roleID, int64(actorId), requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating role", zap.Error(err), zap.Int("id", roleID))
 		_ = ctx.Error(err)
@@ -218,7 +219,7 @@ func (c *RoleController) DeleteRole(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting role", zap.Int("id", roleID))
-	err = c.roleService.Delete(roleID)
+	err = c.roleService.Delete(ctx.Request.Context(), roleID)
 	if err != nil {
 		c.Logger.Error("Error deleting role", zap.Error(err), zap.Int("id", roleID))
 		_ = ctx.Error(err)
@@ -249,7 +250,7 @@ func (c *RoleController) GetTreeRoles(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	roles, err := c.roleService.GetTreeRoles(status)
+	roles, err := c.roleService.GetTreeRoles(ctx.Request.Context(), status)
 	if err != nil {
 		c.Logger.Error("Error getting all roles tree", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -271,7 +272,7 @@ func (c *RoleController) GetRoleSetting(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	roleMenus, roleBtns, err := c.roleService.GetRoleMenuIds(int64(roleID))
+	roleMenus, roleBtns, err := c.roleService.GetRoleMenuIds(ctx.Request.Context(), int64(roleID))
 	if err != nil {
 		c.Logger.Error("Error getting role menu ids", zap.Error(err), zap.Int("id", roleID))
 		_ = ctx.Error(err)
@@ -281,7 +282,7 @@ func (c *RoleController) GetRoleSetting(ctx *gin.Context) {
 	if roleMenus == nil {
 		roleMenus = make(map[int][]int)
 	}
-	rules, err := c.roleService.GetApiRuleList(roleID)
+	rules, err := c.roleService.GetApiRuleList(ctx.Request.Context(), roleID)
 	if err != nil {
 		c.Logger.Error("Error getting all apis", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -320,7 +321,7 @@ func (c *RoleController) UpdateRoleMenuIds(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	err = c.roleService.UpdateRoleMenuIds(roleID, requestMap)
+	err = c.roleService.UpdateRoleMenuIds(ctx.Request.Context(), roleID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating role", zap.Error(err), zap.Int("id", roleID))
 		_ = ctx.Error(err)
@@ -360,7 +361,7 @@ func (c *RoleController) BindApiRule(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	err = c.roleService.BindApiRule(roleID, requestMap)
+	err = c.roleService.BindApiRule(ctx.Request.Context(), roleID, requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating casbin rule ", zap.Error(err), zap.Int("id", roleID))
 		_ = ctx.Error(err)
@@ -399,7 +400,7 @@ func (c *RoleController) BindRoleMenuBtns(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	err = c.roleService.BindRoleMenuBtns(int64(roleID), requestMap)
+	err = c.roleService.BindRoleMenuBtns(ctx.Request.Context(), int64(roleID), requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating casbin rule ", zap.Error(err), zap.Int("id", roleID))
 		_ = ctx.Error(err)
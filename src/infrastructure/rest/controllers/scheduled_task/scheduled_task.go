@@ -97,7 +97,7 @@ func (c *ScheduledTasController) NewScheduledTask(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	ScheduledTaskModel, err := c.scheduledTaskService.Create(toUsecaseMapper(&request))
+	ScheduledTaskModel, err := c.scheduledTaskService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating ScheduledTask", zap.Error(err), zap.String("TaskName", request.TaskName))
 		_ = ctx.Error(err)
@@ -122,7 +122,7 @@ func (c *ScheduledTasController) NewScheduledTask(ctx *gin.Context) {
 // @Router /v1/scheduled_task [get]
 func (c *ScheduledTasController) GetAllScheduledTasks(ctx *gin.Context) {
 	c.Logger.Info("Getting all scheduled_task")
-	scheduled_task, err := c.scheduledTaskService.GetAll()
+	scheduled_task, err := c.scheduledTaskService.GetAll(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all scheduled_task", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -152,7 +152,7 @@ func (c *ScheduledTasController) GetScheduledTaskByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting ScheduledTask by ID", zap.Int("id", ScheduledTaskID))
-	ScheduledTask, err := c.scheduledTaskService.GetByID(ScheduledTaskID)
+	ScheduledTask, err := c.scheduledTaskService.GetByID(ctx.Request.Context(), ScheduledTaskID)
 	if err != nil {
 		c.Logger.Error("Error getting ScheduledTask by ID", zap.Error(err), zap.Int("id", ScheduledTaskID))
 		_ = ctx.Error(err)
@@ -194,7 +194,8 @@ func (c *ScheduledTasController) UpdateScheduledTask(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	ScheduledTaskUpdated, err := c.scheduledTaskService.Update(ScheduledTaskID, requestMap)
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	ScheduledTaskUpdated, err := c.scheduledTaskService.Update(ctx.Request.Context(), ScheduledTaskID, int64(actorId), requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating ScheduledTask", zap.Error(err), zap.Int("id", ScheduledTaskID))
 		_ = ctx.Error(err)
@@ -226,7 +227,7 @@ func (c *ScheduledTasController) DeleteScheduledTask(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting ScheduledTask", zap.Int("id", ScheduledTaskID))
-	err = c.scheduledTaskService.Delete([]int{ScheduledTaskID})
+	err = c.scheduledTaskService.Delete(ctx.Request.Context(), []int{ScheduledTaskID})
 	if err != nil {
 		c.Logger.Error("Error deleting ScheduledTask", zap.Error(err), zap.Int("id", ScheduledTaskID))
 		_ = ctx.Error(err)
@@ -325,7 +326,7 @@ func (c *ScheduledTasController) SearchPaginated(ctx *gin.Context) {
 		filters.SortDirection = sortDirection
 	}
 
-	result, err := c.scheduledTaskService.SearchPaginated(filters)
+	result, err := c.scheduledTaskService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching scheduled_task", zap.Error(err))
 		_ = ctx.Error(err)
@@ -385,7 +386,7 @@ func (c *ScheduledTasController) SearchByProperty(ctx *gin.Context) {
 		return
 	}
 
-	coincidences, err := c.scheduledTaskService.SearchByProperty(property, searchText)
+	coincidences, err := c.scheduledTaskService.SearchByProperty(ctx.Request.Context(), property, searchText)
 	if err != nil {
 		c.Logger.Error("Error searching by property", zap.Error(err), zap.String("property", property))
 		_ = ctx.Error(err)
@@ -418,7 +419,7 @@ func (c *ScheduledTasController) DeleteScheduledTasks(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting operation", zap.String("ids", fmt.Sprintf("%v", request.IDS)))
-	err = c.scheduledTaskService.Delete(request.IDS)
+	err = c.scheduledTaskService.Delete(ctx.Request.Context(), request.IDS)
 	if err != nil {
 		c.Logger.Error("Error deleting operation", zap.Error(err), zap.String("ids", fmt.Sprintf("%v", request.IDS)))
 		_ = ctx.Error(err)
@@ -515,7 +516,8 @@ func (c *ScheduledTasController) EnableTaskById(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Starting ScheduledTask by ID", zap.Int("id", scheduledTaskID))
-	err = c.scheduledTaskService.EnableTask(scheduledTaskID)
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	err = c.scheduledTaskService.EnableTask(ctx.Request.Context(), scheduledTaskID, int64(actorId))
 	if err != nil {
 		c.Logger.Error("Error Starting ScheduledTask by ID", zap.Error(err), zap.Int("id", scheduledTaskID))
 		_ = ctx.Error(err)
@@ -548,7 +550,8 @@ func (c *ScheduledTasController) DisableTaskById(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("disabling ScheduledTask by ID", zap.Int("id", scheduledTaskID))
-	err = c.scheduledTaskService.DisableTask(scheduledTaskID)
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	err = c.scheduledTaskService.DisableTask(ctx.Request.Context(), scheduledTaskID, int64(actorId))
 	if err != nil {
 		c.Logger.Error("Error disabling ScheduledTask by ID", zap.Error(err), zap.Int("id", scheduledTaskID))
 		_ = ctx.Error(err)
@@ -130,7 +130,7 @@ func (c *TaskExecutionLogController) SearchPaginated(ctx *gin.Context) {
 		filters.SortDirection = sortDirection
 	}
 
-	result, err := c.scheduledTaskService.SearchPaginated(filters)
+	result, err := c.scheduledTaskService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching task_execution_log", zap.Error(err))
 		_ = ctx.Error(err)
@@ -0,0 +1,177 @@
+package upload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadcallback"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxCallbackBody bounds the body of a callback, it only describes the object
+const maxCallbackBody = 64 << 10
+
+type IUploadCallbackController interface {
+	OSSCallback(ctx *gin.Context)
+	Callback(ctx *gin.Context)
+}
+
+// CallbackRequest is the body of the generic callback
+type CallbackRequest struct {
+	Bucket     string `json:"bucket"`
+	Key        string `json:"key"`
+	FileName   string `json:"file_name"`
+	Visibility string `json:"visibility"`
+}
+
+type UploadCallbackController struct {
+	callbackUseCase domainFiles.IUploadCallbackService
+	ossVerifier     uploadcallback.Verifier
+	// hmacVerifier is nil when no callback secret is configured, the generic callbacks are then
+	// refused
+	hmacVerifier uploadcallback.Verifier
+	Logger       *logger.Logger
+}
+
+func NewUploadCallbackController(callbackUseCase domainFiles.IUploadCallbackService, ossVerifier uploadcallback.Verifier,
+	hmacVerifier uploadcallback.Verifier, loggerInstance *logger.Logger) IUploadCallbackController {
+	return &UploadCallbackController{
+		callbackUseCase: callbackUseCase,
+		ossVerifier:     ossVerifier,
+		hmacVerifier:    hmacVerifier,
+		Logger:          loggerInstance,
+	}
+}
+
+// OSSCallback
+// @Summary register an object uploaded to OSS
+// @Description callback of an upload made with an sts token, signed by OSS. The callbackBody is
+// @Description bucket=${bucket}&object=${object}&x:file_name=${x:file_name}&x:visibility=${x:visibility},
+// @Description form encoded or as a JSON object. The response is returned to the client by OSS.
+// @Tags upload
+// @Accept x-www-form-urlencoded,json
+// @Produce json
+// @Success 200 {object} domain.CommonResponse[domainFiles.SysFiles]
+// @Router /v1/upload/callback/oss [post]
+func (c *UploadCallbackController) OSSCallback(ctx *gin.Context) {
+	body, ok := c.verifiedBody(ctx, c.ossVerifier)
+	if !ok {
+		return
+	}
+	fields, err := parseOSSCallback(ctx.GetHeader("Content-Type"), body)
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	if fields["object"] == "" {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("object is required"), domainErrors.ValidationError))
+		return
+	}
+	c.register(ctx, domainFiles.UploadedObject{
+		Bucket:     fields["bucket"],
+		Key:        fields["object"],
+		FileName:   fields["x:file_name"],
+		Visibility: fields["x:visibility"],
+	})
+}
+
+// Callback
+// @Summary register an object uploaded to the bucket
+// @Description generic callback of an upload made with an sts token, X-Callback-Signature is the
+// @Description hex HMAC-SHA256 of X-Callback-Timestamp, a newline and the body with the callback secret
+// @Tags upload
+// @Accept json
+// @Produce json
+// @Param request body CallbackRequest true "the uploaded object"
+// @Success 200 {object} domain.CommonResponse[domainFiles.SysFiles]
+// @Router /v1/upload/callback [post]
+func (c *UploadCallbackController) Callback(ctx *gin.Context) {
+	if c.hmacVerifier == nil {
+		_ = ctx.Error(domainErrors.NewAppError(domainFiles.ErrCallbackNotConfigured, domainErrors.NotFound))
+		return
+	}
+	body, ok := c.verifiedBody(ctx, c.hmacVerifier)
+	if !ok {
+		return
+	}
+	var request CallbackRequest
+	if err := json.Unmarshal(body, &request); err != nil || request.Key == "" {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("the body must be a JSON object with a key"), domainErrors.ValidationError))
+		return
+	}
+	c.register(ctx, domainFiles.UploadedObject{
+		Bucket:     request.Bucket,
+		Key:        request.Key,
+		FileName:   request.FileName,
+		Visibility: request.Visibility,
+	})
+}
+
+// verifiedBody reads the body and checks its signature, the error is set on the context when
+// it returns false
+func (c *UploadCallbackController) verifiedBody(ctx *gin.Context, verifier uploadcallback.Verifier) ([]byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxCallbackBody+1))
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return nil, false
+	}
+	if len(body) > maxCallbackBody {
+		_ = ctx.Error(domainErrors.NewAppError(fmt.Errorf("the callback body exceeds %d bytes", maxCallbackBody),
+			domainErrors.ValidationError))
+		return nil, false
+	}
+	if err := verifier.Verify(ctx.Request, body); err != nil {
+		c.Logger.Warn("Upload callback rejected", zap.String("path", ctx.Request.URL.Path), zap.String("ip", ctx.ClientIP()),
+			zap.Error(err))
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.NotAuthorized))
+		return nil, false
+	}
+	return body, true
+}
+
+func (c *UploadCallbackController) register(ctx *gin.Context, object domainFiles.UploadedObject) {
+	file, err := c.callbackUseCase.Register(ctx.Request.Context(), object)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainFiles.SysFiles]().
+		Data(file).Message("success").Status(0).Build())
+}
+
+// parseOSSCallback reads the callback body, form encoded or a JSON object
+func parseOSSCallback(contentType string, body []byte) (map[string]string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	fields := map[string]string{}
+	if mediaType == "application/json" {
+		var values map[string]any
+		if err := json.Unmarshal(body, &values); err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			// the numbers of the system variables, like size, are not used
+			if text, ok := value.(string); ok {
+				fields[key] = text
+			}
+		}
+		return fields, nil
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for key := range values {
+		fields[key] = values.Get(key)
+	}
+	return fields, nil
+}
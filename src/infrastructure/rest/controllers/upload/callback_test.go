@@ -0,0 +1,95 @@
+package upload
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/uploadcallback"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeCallbacks keeps the registered objects
+type fakeCallbacks struct {
+	domainFiles.IUploadCallbackService
+	registered []domainFiles.UploadedObject
+}
+
+func (f *fakeCallbacks) Register(_ context.Context, object domainFiles.UploadedObject) (*domainFiles.SysFiles, error) {
+	f.registered = append(f.registered, object)
+	return &domainFiles.SysFiles{ID: 42, FilePath: object.Key}, nil
+}
+
+// headerVerifier accepts the requests with the header X-Test-Signed
+type headerVerifier struct{}
+
+func (headerVerifier) Verify(r *http.Request, _ []byte) error {
+	if r.Header.Get("X-Test-Signed") == "" {
+		return uploadcallback.ErrInvalidSignature
+	}
+	return nil
+}
+
+func TestUploadCallbacks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	callbacks := &fakeCallbacks{}
+	controller := NewUploadCallbackController(callbacks, headerVerifier{}, headerVerifier{}, &logger.Logger{Log: zap.NewNop()})
+	router := gin.New()
+	router.POST("/callback/oss", controller.OSSCallback)
+	router.POST("/callback", controller.Callback)
+	post := func(target, contentType, body string, signed bool) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		request.Header.Set("Content-Type", contentType)
+		if signed {
+			request.Header.Set("X-Test-Signed", "1")
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	response := post("/callback/oss", "application/x-www-form-urlencoded",
+		"bucket=media&object=public%2Fsts%2F7%2Fa%2Fx.png&size=5&x%3Afile_name=Holiday.png&x%3Avisibility=private", true)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"id":42`)
+	response = post("/callback/oss", "application/json",
+		`{"bucket":"media","object":"public/sts/7/a/y.png","size":5,"x:file_name":"y.png"}`, true)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = post("/callback", "application/json", `{"key":"public/sts/7/a/z.png","visibility":"role"}`, true)
+	assert.Equal(t, http.StatusOK, response.Code)
+	require.Len(t, callbacks.registered, 3)
+	assert.Equal(t, domainFiles.UploadedObject{Bucket: "media", Key: "public/sts/7/a/x.png", FileName: "Holiday.png",
+		Visibility: "private"}, callbacks.registered[0])
+	assert.Equal(t, "y.png", callbacks.registered[1].FileName)
+	assert.Equal(t, domainFiles.UploadedObject{Key: "public/sts/7/a/z.png", Visibility: "role"}, callbacks.registered[2])
+
+	post("/callback/oss", "application/x-www-form-urlencoded", "object=public%2Fsts%2F7%2Fa%2Fx.png", false)
+	post("/callback/oss", "application/x-www-form-urlencoded", "bucket=media", true)
+	post("/callback", "application/json", `{"key":"public/sts/7/a/z.png"}`, false)
+	post("/callback", "application/json", strings.Repeat(" ", maxCallbackBody+1)+"{}", true)
+	assert.Len(t, callbacks.registered, 3, "unsigned and invalid callbacks are not registered")
+
+	unconfigured := NewUploadCallbackController(callbacks, headerVerifier{}, nil, &logger.Logger{Log: zap.NewNop()})
+	router.POST("/unconfigured", unconfigured.Callback)
+	post("/unconfigured", "application/json", `{"key":"public/sts/7/a/z.png"}`, true)
+	assert.Len(t, callbacks.registered, 3)
+}
+
+func TestParseOSSCallback(t *testing.T) {
+	fields, err := parseOSSCallback("application/json; charset=utf-8", []byte(`{"object":"a.png","size":12}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"object": "a.png"}, fields)
+	_, err = parseOSSCallback("application/json", []byte(`[1]`))
+	assert.Error(t, err)
+	fields, err = parseOSSCallback("", []byte("object=a%20b.png&x:visibility=public"))
+	require.NoError(t, err)
+	assert.Equal(t, "a b.png", fields["object"])
+	assert.Equal(t, "public", fields["x:visibility"])
+}
@@ -0,0 +1,481 @@
+package upload
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TusVersion is the tus protocol version served by the tus endpoints
+const TusVersion = "1.0.0"
+
+// tus headers, also exposed through CORS
+const (
+	HeaderTusResumable   = "Tus-Resumable"
+	HeaderUploadOffset   = "Upload-Offset"
+	HeaderUploadLength   = "Upload-Length"
+	HeaderUploadMetadata = "Upload-Metadata"
+	HeaderUploadExpires  = "Upload-Expires"
+	HeaderUploadChecksum = "Upload-Checksum"
+	// HeaderFileID carries the sys_files id once a tus upload is complete
+	HeaderFileID = "X-File-Id"
+)
+
+// statusChecksumMismatch is the tus checksum extension status for a part failing its checksum
+const statusChecksumMismatch = 460
+
+type IChunkedUploadController interface {
+	Initiate(ctx *gin.Context)
+	Get(ctx *gin.Context)
+	UploadPart(ctx *gin.Context)
+	ListParts(ctx *gin.Context)
+	Complete(ctx *gin.Context)
+	Abort(ctx *gin.Context)
+
+	TusOptions(ctx *gin.Context)
+	TusCreate(ctx *gin.Context)
+	TusHead(ctx *gin.Context)
+	TusPatch(ctx *gin.Context)
+	TusDelete(ctx *gin.Context)
+}
+
+// InitiateUploadRequest describes the file of a chunked upload, file_md5 is checked against the
+// assembled file when given
+type InitiateUploadRequest struct {
+	FileName    string `json:"file_name" binding:"required"`
+	FileSize    int64  `json:"file_size" binding:"required,gt=0"`
+	ContentType string `json:"content_type"`
+	FileMD5     string `json:"file_md5" binding:"omitempty,len=32,hexadecimal"`
+	// Visibility of the completed file: public, role or private, the configured default when empty
+	Visibility string `json:"visibility"`
+}
+
+type CompleteUploadRequest struct {
+	FileMD5 string `json:"file_md5" binding:"omitempty,len=32,hexadecimal"`
+}
+
+type ChunkedUploadController struct {
+	chunkedUploadUseCase domainFiles.IChunkedUploadService
+	// maxSize is announced as Tus-Max-Size, 0 is unlimited
+	maxSize int64
+	Logger  *logger.Logger
+}
+
+func NewChunkedUploadController(chunkedUploadUseCase domainFiles.IChunkedUploadService, maxSize int64, loggerInstance *logger.Logger) IChunkedUploadController {
+	return &ChunkedUploadController{
+		chunkedUploadUseCase: chunkedUploadUseCase,
+		maxSize:              maxSize,
+		Logger:               loggerInstance,
+	}
+}
+
+func userID(ctx *gin.Context) int64 {
+	id, _ := controllers.NewAppUtils(ctx).GetUserID()
+	return int64(id)
+}
+
+func roleID(ctx *gin.Context) int64 {
+	id, _ := controllers.NewAppUtils(ctx).GetRoleID()
+	return id
+}
+
+// Initiate
+// @Summary initiate a chunked upload
+// @Description starts an upload session, the parts are then sent with PUT .../parts/{number}
+// @Tags upload
+// @Accept json
+// @Produce json
+// @Param request body InitiateUploadRequest true "file name, size and optional md5"
+// @Success 200 {object} domain.CommonResponse[domainFiles.UploadSession]
+// @Router /v1/upload/multipart [post]
+func (c *ChunkedUploadController) Initiate(ctx *gin.Context) {
+	var request InitiateUploadRequest
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	session, err := c.chunkedUploadUseCase.Initiate(ctx.Request.Context(), domainFiles.InitiateUpload{
+		FileName:      filepath.Base(request.FileName),
+		FileSize:      request.FileSize,
+		ContentType:   request.ContentType,
+		FileMD5:       request.FileMD5,
+		CreatedBy:     userID(ctx),
+		CreatedByRole: roleID(ctx),
+		Visibility:    request.Visibility,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainFiles.UploadSession]().
+		Data(session).Message("success").Status(0).Build())
+}
+
+// Get
+// @Summary get a chunked upload
+// @Description returns the upload session with its parts, file_id is set once it is completed
+// @Tags upload
+// @Produce json
+// @Param id path string true "upload id"
+// @Success 200 {object} domain.CommonResponse[domainFiles.UploadSession]
+// @Router /v1/upload/multipart/{id} [get]
+func (c *ChunkedUploadController) Get(ctx *gin.Context) {
+	session, err := c.chunkedUploadUseCase.Get(ctx.Request.Context(), ctx.Param("id"), userID(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainFiles.UploadSession]().
+		Data(session).Message("success").Status(0).Build())
+}
+
+// UploadPart
+// @Summary upload a part
+// @Description stores the request body as part {number}, an existing part with that number is replaced.
+// @Description Content-MD5 is the base64 md5 of the body.
+// @Tags upload
+// @Accept application/octet-stream
+// @Produce json
+// @Param id path string true "upload id"
+// @Param number path int true "part number, 1 to 10000"
+// @Param Content-MD5 header string true "base64 md5 of the part"
+// @Success 200 {object} domain.CommonResponse[domainFiles.UploadPart]
+// @Router /v1/upload/multipart/{id}/parts/{number} [put]
+func (c *ChunkedUploadController) UploadPart(ctx *gin.Context) {
+	partNumber, err := strconv.Atoi(ctx.Param("number"))
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid part number"), domainErrors.ValidationError))
+		return
+	}
+	partMD5, err := decodeMD5(ctx.GetHeader("Content-MD5"))
+	if err != nil || partMD5 == "" {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("Content-MD5 header with the base64 md5 of the part is required"),
+			domainErrors.ValidationError))
+		return
+	}
+	part, err := c.chunkedUploadUseCase.UploadPart(ctx.Request.Context(), ctx.Param("id"), userID(ctx), partNumber, partMD5,
+		ctx.Request.Body)
+	if err != nil {
+		c.Logger.Warn("Error uploading part", zap.String("uploadId", ctx.Param("id")), zap.Int("part", partNumber), zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainFiles.UploadPart]().
+		Data(part).Message("success").Status(0).Build())
+}
+
+// ListParts
+// @Summary list the uploaded parts
+// @Tags upload
+// @Produce json
+// @Param id path string true "upload id"
+// @Success 200 {object} domain.CommonResponse[[]domainFiles.UploadPart]
+// @Router /v1/upload/multipart/{id}/parts [get]
+func (c *ChunkedUploadController) ListParts(ctx *gin.Context) {
+	parts, err := c.chunkedUploadUseCase.ListParts(ctx.Request.Context(), ctx.Param("id"), userID(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[[]domainFiles.UploadPart]().
+		Data(*parts).Message("success").Status(0).Build())
+}
+
+// Complete
+// @Summary complete a chunked upload
+// @Description assembles the parts in part number order and records the file, they have to add up to the file size
+// @Tags upload
+// @Accept json
+// @Produce json
+// @Param id path string true "upload id"
+// @Param request body CompleteUploadRequest false "optional md5 of the whole file"
+// @Success 200 {object} domain.CommonResponse[domainFiles.SysFiles]
+// @Router /v1/upload/multipart/{id}/complete [post]
+func (c *ChunkedUploadController) Complete(ctx *gin.Context) {
+	var request CompleteUploadRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := controllers.BindJSON(ctx, &request); err != nil {
+			_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+			return
+		}
+	}
+	file, err := c.chunkedUploadUseCase.Complete(ctx.Request.Context(), ctx.Param("id"), userID(ctx), request.FileMD5)
+	if err != nil {
+		c.Logger.Warn("Error completing upload", zap.String("uploadId", ctx.Param("id")), zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*domainFiles.SysFiles]().
+		Data(file).Message("success").Status(0).Build())
+}
+
+// Abort
+// @Summary abort a chunked upload
+// @Description removes the session and its parts
+// @Tags upload
+// @Produce json
+// @Param id path string true "upload id"
+// @Success 200 {object} controllers.MessageResponse
+// @Router /v1/upload/multipart/{id} [delete]
+func (c *ChunkedUploadController) Abort(ctx *gin.Context) {
+	if err := c.chunkedUploadUseCase.Abort(ctx.Request.Context(), ctx.Param("id"), userID(ctx)); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+// TusOptions
+// @Summary tus discovery
+// @Description tus 1.0.0 with the creation, expiration, checksum (md5) and termination extensions
+// @Tags upload
+// @Success 204
+// @Router /v1/upload/tus [options]
+func (c *ChunkedUploadController) TusOptions(ctx *gin.Context) {
+	ctx.Header(HeaderTusResumable, TusVersion)
+	ctx.Header("Tus-Version", TusVersion)
+	ctx.Header("Tus-Extension", "creation,expiration,checksum,termination")
+	ctx.Header("Tus-Checksum-Algorithm", "md5")
+	if c.maxSize > 0 {
+		ctx.Header("Tus-Max-Size", strconv.FormatInt(c.maxSize, 10))
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// TusCreate
+// @Summary tus upload creation
+// @Description Upload-Length is required, Upload-Metadata may carry filename, filetype and visibility
+// @Tags upload
+// @Param Upload-Length header int true "file size"
+// @Param Upload-Metadata header string false "tus metadata"
+// @Success 201
+// @Router /v1/upload/tus [post]
+func (c *ChunkedUploadController) TusCreate(ctx *gin.Context) {
+	if !tusResumable(ctx) {
+		return
+	}
+	size, err := strconv.ParseInt(ctx.GetHeader(HeaderUploadLength), 10, 64)
+	if err != nil || size <= 0 {
+		tusAbort(ctx, http.StatusBadRequest, "Upload-Length must be a positive integer")
+		return
+	}
+	if c.maxSize > 0 && size > c.maxSize {
+		tusAbort(ctx, http.StatusRequestEntityTooLarge, "Upload-Length exceeds Tus-Max-Size")
+		return
+	}
+	metadata := parseTusMetadata(ctx.GetHeader(HeaderUploadMetadata))
+	fileName := firstNonEmpty(metadata["filename"], metadata["name"], "upload")
+	session, err := c.chunkedUploadUseCase.Initiate(ctx.Request.Context(), domainFiles.InitiateUpload{
+		FileName:      filepath.Base(fileName),
+		FileSize:      size,
+		ContentType:   firstNonEmpty(metadata["filetype"], metadata["type"], metadata["contentType"]),
+		CreatedBy:     userID(ctx),
+		CreatedByRole: roleID(ctx),
+		Visibility:    metadata["visibility"],
+	})
+	if err != nil {
+		c.tusError(ctx, err)
+		return
+	}
+	ctx.Header("Location", strings.TrimSuffix(ctx.Request.URL.Path, "/")+"/"+session.ID)
+	ctx.Header(HeaderUploadExpires, session.ExpiresAt.UTC().Format(http.TimeFormat))
+	ctx.Status(http.StatusCreated)
+}
+
+// TusHead
+// @Summary tus upload offset
+// @Description a session whose parts add up to its length but that isn't completed yet is completed here
+// @Tags upload
+// @Param id path string true "upload id"
+// @Success 200
+// @Router /v1/upload/tus/{id} [head]
+func (c *ChunkedUploadController) TusHead(ctx *gin.Context) {
+	if !tusResumable(ctx) {
+		return
+	}
+	session, err := c.chunkedUploadUseCase.Get(ctx.Request.Context(), ctx.Param("id"), userID(ctx))
+	if err != nil {
+		c.tusError(ctx, err)
+		return
+	}
+	// the client takes a full offset as success, a completion that failed after the last part
+	// is retried here
+	if session.Offset == session.FileSize && session.Status == domainFiles.UploadStatusUploading {
+		if !c.tusComplete(ctx, session.ID) {
+			return
+		}
+	} else if session.FileID != nil {
+		ctx.Header(HeaderFileID, strconv.FormatInt(*session.FileID, 10))
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header(HeaderUploadOffset, strconv.FormatInt(session.Offset, 10))
+	ctx.Header(HeaderUploadLength, strconv.FormatInt(session.FileSize, 10))
+	ctx.Header(HeaderUploadExpires, session.ExpiresAt.UTC().Format(http.TimeFormat))
+	ctx.Status(http.StatusOK)
+}
+
+// TusPatch
+// @Summary tus upload content
+// @Description appends the body at Upload-Offset, the file is recorded once the offset reaches the length
+// @Tags upload
+// @Accept application/offset+octet-stream
+// @Param id path string true "upload id"
+// @Param Upload-Offset header int true "offset of the body"
+// @Param Upload-Checksum header string false "md5 followed by the base64 md5 of the body"
+// @Success 204
+// @Router /v1/upload/tus/{id} [patch]
+func (c *ChunkedUploadController) TusPatch(ctx *gin.Context) {
+	if !tusResumable(ctx) {
+		return
+	}
+	if ctx.ContentType() != "application/offset+octet-stream" {
+		tusAbort(ctx, http.StatusUnsupportedMediaType, "Content-Type must be application/offset+octet-stream")
+		return
+	}
+	offset, err := strconv.ParseInt(ctx.GetHeader(HeaderUploadOffset), 10, 64)
+	if err != nil || offset < 0 {
+		tusAbort(ctx, http.StatusBadRequest, "Upload-Offset must be a non-negative integer")
+		return
+	}
+	var partMD5 string
+	if checksum := ctx.GetHeader(HeaderUploadChecksum); checksum != "" {
+		algorithm, value, _ := strings.Cut(checksum, " ")
+		if algorithm != "md5" {
+			tusAbort(ctx, http.StatusBadRequest, "unsupported checksum algorithm")
+			return
+		}
+		if partMD5, err = decodeMD5(value); err != nil || partMD5 == "" {
+			tusAbort(ctx, http.StatusBadRequest, "invalid checksum")
+			return
+		}
+	}
+
+	session, err := c.chunkedUploadUseCase.Append(ctx.Request.Context(), ctx.Param("id"), userID(ctx), offset, partMD5,
+		ctx.Request.Body)
+	if err != nil {
+		c.Logger.Warn("Error appending tus upload", zap.String("uploadId", ctx.Param("id")), zap.Int64("offset", offset), zap.Error(err))
+		c.tusError(ctx, err)
+		return
+	}
+	if session.Offset == session.FileSize && !c.tusComplete(ctx, session.ID) {
+		return
+	}
+	ctx.Header(HeaderUploadOffset, strconv.FormatInt(session.Offset, 10))
+	ctx.Header(HeaderUploadExpires, session.ExpiresAt.UTC().Format(http.TimeFormat))
+	ctx.Status(http.StatusNoContent)
+}
+
+// TusDelete
+// @Summary tus upload termination
+// @Tags upload
+// @Param id path string true "upload id"
+// @Success 204
+// @Router /v1/upload/tus/{id} [delete]
+func (c *ChunkedUploadController) TusDelete(ctx *gin.Context) {
+	if !tusResumable(ctx) {
+		return
+	}
+	if err := c.chunkedUploadUseCase.Abort(ctx.Request.Context(), ctx.Param("id"), userID(ctx)); err != nil {
+		c.tusError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// tusComplete records the file of a fully received tus upload and sets its id header
+func (c *ChunkedUploadController) tusComplete(ctx *gin.Context, uploadID string) bool {
+	file, err := c.chunkedUploadUseCase.Complete(ctx.Request.Context(), uploadID, userID(ctx), "")
+	if err != nil {
+		c.Logger.Warn("Error completing tus upload", zap.String("uploadId", uploadID), zap.Error(err))
+		c.tusError(ctx, err)
+		return false
+	}
+	ctx.Header(HeaderFileID, strconv.FormatInt(file.ID, 10))
+	return true
+}
+
+// tusResumable sets the Tus-Resumable header and rejects the requests of other protocol versions
+func tusResumable(ctx *gin.Context) bool {
+	ctx.Header(HeaderTusResumable, TusVersion)
+	if ctx.GetHeader(HeaderTusResumable) != TusVersion {
+		ctx.Header("Tus-Version", TusVersion)
+		tusAbort(ctx, http.StatusPreconditionFailed, "unsupported tus version")
+		return false
+	}
+	return true
+}
+
+// tusError answers with the status codes tus clients act on instead of the JSON error body
+func (c *ChunkedUploadController) tusError(ctx *gin.Context, err error) {
+	var appErr *domainErrors.AppError
+	if !errors.As(err, &appErr) {
+		tusAbort(ctx, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	switch {
+	case errors.Is(appErr.Err, domainFiles.ErrUploadOffsetMismatch), errors.Is(appErr.Err, domainFiles.ErrUploadNotActive):
+		tusAbort(ctx, http.StatusConflict, appErr.Error())
+	case errors.Is(appErr.Err, domainFiles.ErrUploadChecksumMismatch):
+		tusAbort(ctx, statusChecksumMismatch, appErr.Error())
+	default:
+		status, message := domainErrors.AppErrorToHTTP(appErr)
+		tusAbort(ctx, status, message)
+	}
+}
+
+func tusAbort(ctx *gin.Context, status int, message string) {
+	if ctx.Request.Method == http.MethodHead {
+		ctx.AbortWithStatus(status)
+		return
+	}
+	ctx.Abort()
+	ctx.String(status, message)
+}
+
+// parseTusMetadata decodes Upload-Metadata, comma separated keys each followed by a base64 value
+func parseTusMetadata(header string) map[string]string {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err == nil {
+			metadata[key] = string(value)
+		}
+	}
+	return metadata
+}
+
+// decodeMD5 turns a base64 md5 into hex, "" when the header is absent
+func decodeMD5(encoded string) (string, error) {
+	if encoded == "" {
+		return "", nil
+	}
+	sum, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sum) != 16 {
+		return "", errors.New("invalid md5")
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
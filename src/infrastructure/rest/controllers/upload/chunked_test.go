@@ -0,0 +1,94 @@
+package upload
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeChunkedUploads accepts appends at offset 0 only, the other methods are not used
+type fakeChunkedUploads struct {
+	domainFiles.IChunkedUploadService
+	received  string
+	completed bool
+}
+
+func (f *fakeChunkedUploads) Append(_ context.Context, uploadID string, _ int64, offset int64, partMD5 string, content io.Reader) (*domainFiles.UploadSession, error) {
+	if offset != 0 {
+		return nil, domainErrors.NewAppError(domainFiles.ErrUploadOffsetMismatch, domainErrors.ValidationError)
+	}
+	if partMD5 != "" && partMD5 != "5d41402abc4b2a76b9719d911017c592" {
+		return nil, domainErrors.NewAppError(domainFiles.ErrUploadChecksumMismatch, domainErrors.ValidationError)
+	}
+	body, _ := io.ReadAll(content)
+	f.received = string(body)
+	return &domainFiles.UploadSession{ID: uploadID, FileSize: 5, Offset: int64(len(body)), ExpiresAt: time.Now()}, nil
+}
+
+func (f *fakeChunkedUploads) Complete(context.Context, string, int64, string) (*domainFiles.SysFiles, error) {
+	f.completed = true
+	return &domainFiles.SysFiles{ID: 42}, nil
+}
+
+func patch(router *gin.Engine, headers map[string]string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPatch, "/tus/0b4e7a0e-5b3a-4a3c-9b0e-3f1c2a1b4c5d", strings.NewReader(body))
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestTusPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uploads := &fakeChunkedUploads{}
+	controller := NewChunkedUploadController(uploads, 0, &logger.Logger{Log: zap.NewNop()})
+	router := gin.New()
+	router.PATCH("/tus/:id", controller.TusPatch)
+	headers := map[string]string{
+		HeaderTusResumable: TusVersion,
+		"Content-Type":     "application/offset+octet-stream",
+		HeaderUploadOffset: "0",
+	}
+
+	response := patch(router, map[string]string{HeaderUploadOffset: "0"}, "hello")
+	assert.Equal(t, http.StatusPreconditionFailed, response.Code)
+
+	headers[HeaderUploadChecksum] = "md5 XUFAKrxLKna5cZ2REBfFkg=="
+	response = patch(router, headers, "hello")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Equal(t, "5", response.Header().Get(HeaderUploadOffset))
+	assert.Equal(t, "42", response.Header().Get(HeaderFileID))
+	assert.Equal(t, "hello", uploads.received)
+	assert.True(t, uploads.completed)
+
+	headers[HeaderUploadChecksum] = "md5 AAAAAAAAAAAAAAAAAAAAAA=="
+	assert.Equal(t, statusChecksumMismatch, patch(router, headers, "hello").Code)
+	headers[HeaderUploadChecksum] = "sha1 AAAA"
+	assert.Equal(t, http.StatusBadRequest, patch(router, headers, "hello").Code)
+
+	delete(headers, HeaderUploadChecksum)
+	headers[HeaderUploadOffset] = "3"
+	assert.Equal(t, http.StatusConflict, patch(router, headers, "lo").Code)
+	headers["Content-Type"] = "application/octet-stream"
+	assert.Equal(t, http.StatusUnsupportedMediaType, patch(router, headers, "lo").Code)
+}
+
+func TestParseTusMetadata(t *testing.T) {
+	metadata := parseTusMetadata("filename ZXhwb3J0LmNzdg==, filetype dGV4dC9jc3Y=,is_confidential")
+	assert.Equal(t, "export.csv", metadata["filename"])
+	assert.Equal(t, "text/csv", metadata["filetype"])
+	assert.Equal(t, "", metadata["is_confidential"])
+}
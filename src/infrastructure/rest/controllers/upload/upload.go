@@ -1,10 +1,9 @@
 package upload
 
 import (
-	"context"
-	"fmt"
+	"errors"
+	"mime/multipart"
 	"net/http"
-	"os"
 	"path/filepath"
 	"time"
 
@@ -13,21 +12,14 @@ import (
 	domainFiles "github.com/gbrayhan/microservices-go/src/domain/sys/files"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
-	shareUtils "github.com/gbrayhan/microservices-go/src/shared/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-
-	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
-	sts20150401 "github.com/alibabacloud-go/sts-20150401/v2/client"
-	util "github.com/alibabacloud-go/tea-utils/v2/service"
-	"github.com/alibabacloud-go/tea/tea"
-	cacheService "github.com/gbrayhan/microservices-go/src/infrastructure/lib/cache"
-	"github.com/redis/go-redis/v9"
 )
 
 type IUploadController interface {
 	Single(ctx *gin.Context)
 	Multiple(ctx *gin.Context)
+	Check(ctx *gin.Context)
 	GetSTSToken(ctx *gin.Context)
 	RefreshSTSToken(ctx *gin.Context)
 }
@@ -39,14 +31,39 @@ type STSTokenResponse struct {
 	Expiration      string `json:"expiration"`
 	BucketName      string `json:"bucket_name"`
 	Region          string `json:"region"`
-	RefreshToken    string `json:"refresh_token,omitempty"`
+	// Prefix the object key has to start with, MaxSize and ContentTypes bound the object
+	Prefix       string   `json:"prefix"`
+	MaxSize      int64    `json:"max_size"`
+	ContentTypes []string `json:"content_types,omitempty"`
+	RefreshToken string   `json:"refresh_token,omitempty"`
+}
+
+// STSTokenRequest describes the file the credentials are asked for
+type STSTokenRequest struct {
+	FileName    string `form:"file_name" binding:"required"`
+	FileSize    int64  `form:"file_size" binding:"omitempty,gt=0"`
+	ContentType string `form:"content_type"`
+}
+
+// CheckRequest identifies the content of a file before it is uploaded
+type CheckRequest struct {
+	FileMD5  string `json:"file_md5" binding:"required,len=32,hexadecimal"`
+	FileSize int64  `json:"file_size" binding:"required,gt=0"`
+	FileName string `json:"file_name" binding:"required"`
+	// Visibility of the recorded file, the configured default when empty
+	Visibility string `json:"visibility"`
+}
+
+// CheckResponse holds the recorded file when the content was already stored
+type CheckResponse struct {
+	Exists bool                  `json:"exists"`
+	File   *domainFiles.SysFiles `json:"file,omitempty"`
 }
 
 type UploadController struct {
 	sysFilesUseCase domainFiles.ISysFilesService
+	stsUseCase      domainFiles.ISTSService
 	Logger          *logger.Logger
-	RedisClient     *redis.Client
-	CacheService    *cacheService.STSCacheService
 }
 
 // MultipleUpload
@@ -56,6 +73,7 @@ type UploadController struct {
 // @Accept multipart/form-data
 // @Produce json
 // @Param file formData file true "fileResources" collectionFormat(multi)
+// @Param visibility formData string false "public, role or private"
 // @Success 200 {object} domain.CommonResponse[[]domainFiles.SysFiles]
 // @Router /v1/upload/multiple [post]
 func (u *UploadController) Multiple(ctx *gin.Context) {
@@ -72,49 +90,11 @@ func (u *UploadController) Multiple(ctx *gin.Context) {
 	var uploadedFiles []domainFiles.SysFiles
 
 	for _, file := range files {
-		filename := filepath.Base(file.Filename)
-		ext := filepath.Ext(filename)
-		newFilename := fmt.Sprintf("%d%s", time.Now().UnixNano(), ext)
-		savePath := filepath.Join("public", newFilename)
-
-		if err := os.MkdirAll("public", os.ModePerm); err != nil {
-			u.Logger.Error("Error creating dir", zap.Error(err))
-			appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-			_ = ctx.Error(appError)
-			return
-		}
-
-		if err := ctx.SaveUploadedFile(file, savePath); err != nil {
-			u.Logger.Error("Error save file", zap.Error(err))
-			appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-			_ = ctx.Error(appError)
-			return
-		}
-
-		md5Value, err := shareUtils.CalculateFileMD5(savePath)
+		res, err := u.upload(ctx, file)
 		if err != nil {
-			u.Logger.Error("calculate file to md5", zap.Error(err))
-			appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-			_ = ctx.Error(appError)
+			_ = ctx.Error(err)
 			return
 		}
-
-		fileInfo := domainFiles.SysFiles{
-			FileName:       newFilename,
-			FilePath:       savePath,
-			FileMD5:        md5Value,
-			FileOriginName: filename,
-			StorageEngine:  "local",
-		}
-
-		res, err := u.sysFilesUseCase.Create(&fileInfo)
-		if err != nil {
-			u.Logger.Error("insert file info to database", zap.Error(err))
-			appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-			_ = ctx.Error(appError)
-			return
-		}
-
 		uploadedFiles = append(uploadedFiles, *res)
 	}
 
@@ -136,6 +116,7 @@ func (u *UploadController) Multiple(ctx *gin.Context) {
 // @Accept multipart/form-data
 // @Produce json
 // @Param file formData file true "fileResource"
+// @Param visibility formData string false "public, role or private"
 // @Success 200 {object} domain.CommonResponse[domainFiles.SysFiles]
 // @Router /v1/upload/single [post]
 func (u *UploadController) Single(ctx *gin.Context) {
@@ -147,50 +128,9 @@ func (u *UploadController) Single(ctx *gin.Context) {
 		return
 	}
 
-	filename := filepath.Base(file.Filename)
-	// only name
-	ext := filepath.Ext(filename)
-	newFilename := fmt.Sprintf("%d%s", time.Now().UnixNano(), ext)
-
-	// join name
-	savePath := filepath.Join("public", newFilename)
-
-	// create save file dir
-	if err := os.MkdirAll("public", os.ModePerm); err != nil {
-		u.Logger.Error("Error creating dir", zap.Error(err))
-		appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-		_ = ctx.Error(appError)
-		return
-	}
-	if err := ctx.SaveUploadedFile(file, savePath); err != nil {
-		u.Logger.Error("Error save file", zap.Error(err))
-		appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-		_ = ctx.Error(appError)
-		return
-	}
-
-	// calculate md5 file
-	md5Value, err := shareUtils.CalculateFileMD5(savePath)
-	if err != nil {
-		u.Logger.Error("calculate  file to md5", zap.Error(err))
-		appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-		_ = ctx.Error(appError)
-		return
-	}
-
-	// insert to database
-	files := domainFiles.SysFiles{
-		FileName:       newFilename,
-		FilePath:       savePath,
-		FileMD5:        md5Value,
-		FileOriginName: filename,
-		StorageEngine:  "local",
-	}
-	res, err := u.sysFilesUseCase.Create(&files)
+	res, err := u.upload(ctx, file)
 	if err != nil {
-		u.Logger.Error("insert file info to database", zap.Error(err))
-		appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-		_ = ctx.Error(appError)
+		_ = ctx.Error(err)
 		return
 	}
 	response := &domain.CommonResponse[domainFiles.SysFiles]{
@@ -199,190 +139,158 @@ func (u *UploadController) Single(ctx *gin.Context) {
 		Status:  200,
 	}
 
-	u.Logger.Info("upload successful", zap.String("filename", newFilename))
+	u.Logger.Info("upload successful", zap.String("filename", res.FileName))
 
 	ctx.JSON(http.StatusOK, response)
 }
 
+// Check
+// @Summary instant upload check
+// @Description when a file with the same md5 and size is already stored, records a file named file_name
+// @Description sharing its content and returns it, the upload can then be skipped
+// @Tags upload
+// @Accept json
+// @Produce json
+// @Param request body CheckRequest true "md5, size and name of the file"
+// @Success 200 {object} domain.CommonResponse[CheckResponse]
+// @Router /v1/upload/check [post]
+func (u *UploadController) Check(ctx *gin.Context) {
+	var request CheckRequest
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	file, err := u.sysFilesUseCase.InstantUpload(ctx.Request.Context(), request.FileMD5, request.FileSize,
+		filepath.Base(request.FileName), owner(ctx, request.Visibility))
+	if err != nil {
+		u.Logger.Error("Error checking instant upload", zap.String("md5", request.FileMD5), zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*CheckResponse]().
+		Data(&CheckResponse{Exists: file != nil, File: file}).Message("success").Status(0).Build())
+}
+
+// owner makes the requester the owner of the uploaded files
+func owner(ctx *gin.Context, visibility string) domainFiles.FileOwner {
+	return domainFiles.FileOwner{UserID: userID(ctx), RoleID: roleID(ctx), Visibility: visibility}
+}
+
+// upload streams one multipart file to the storage driver, the visibility form field applies to
+// every file of the request
+func (u *UploadController) upload(ctx *gin.Context, file *multipart.FileHeader) (*domainFiles.SysFiles, error) {
+	content, err := file.Open()
+	if err != nil {
+		u.Logger.Error("Error opening uploaded file", zap.Error(err))
+		return nil, domainErrors.NewAppError(err, domainErrors.UploadError)
+	}
+	defer content.Close()
+
+	res, err := u.sysFilesUseCase.Upload(ctx.Request.Context(), domainFiles.UploadFile{
+		OriginName:  filepath.Base(file.Filename),
+		Size:        file.Size,
+		ContentType: file.Header.Get("Content-Type"),
+		Content:     content,
+		Owner:       owner(ctx, ctx.PostForm("visibility")),
+	})
+	if err != nil {
+		u.Logger.Error("Error uploading file", zap.String("filename", file.Filename), zap.Error(err))
+		return nil, err
+	}
+	return res, nil
+}
+
 // GetSTSToken
 // @Summary get sts token with aliyun
-// @Description get sts token
+// @Description get sts credentials allowing the upload of one file to a prefix of the user, the
+// @Description size and content type of the object are checked against the scope when recorded
 // @Tags sts token
 // @Accept json
 // @Produce json
-// @Success 200 {object} domain.CommonResponse
+// @Param file_name query string true "name of the file to upload"
+// @Param file_size query int false "size of the file in bytes"
+// @Param content_type query string false "content type of the file"
+// @Success 200 {object} domain.CommonResponse[STSTokenResponse]
 // @Router /v1/upload/sts-token [get]
 func (u *UploadController) GetSTSToken(ctx *gin.Context) {
-	userID := ctx.GetString("user_id") // 从JWT中获取用户ID
-	if userID == "" {
-		userID = "anonymous" // 匿名用户
+	userID, ok := controllers.NewAppUtils(ctx).GetUserID()
+	if !ok {
+		_ = ctx.Error(domainErrors.NewAppErrorWithType(domainErrors.NotAuthenticated))
+		return
 	}
-
-	cacheKey := fmt.Sprintf("sts_token:%s", userID)
-	ctxBg := context.Background()
-
-	// 尝试从缓存获取
-	if cachedToken, err := u.CacheService.GetSTSToken(ctxBg, cacheKey); err == nil {
-		// 检查token是否即将过期（提前5分钟刷新）
-		if time.Until(cachedToken.Expiration) > 5*time.Minute {
-			result := controllers.NewCommonResponseBuilder[*STSTokenResponse]().
-				Data(&STSTokenResponse{
-					AccessKeyId:     cachedToken.AccessKeyId,
-					AccessKeySecret: cachedToken.AccessKeySecret,
-					SecurityToken:   cachedToken.SecurityToken,
-					Expiration:      cachedToken.Expiration.Format(time.RFC3339),
-					BucketName:      cachedToken.BucketName,
-					Region:          cachedToken.Region,
-				}).
-				Message("success").
-				Status(0).
-				Build()
-			ctx.JSON(http.StatusOK, result)
-			return
-		}
+	var request STSTokenRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
 	}
-
-	// 如果缓存中没有有效token，则生成新的token
-	u.processSTSToken(ctx, userID, cacheKey)
+	fileSize := request.FileSize
+	if fileSize == 0 {
+		fileSize = -1
+	}
+	token, err := u.stsUseCase.Issue(ctx.Request.Context(), domainFiles.STSRequest{
+		UserID:      int64(userID),
+		RoleID:      roleID(ctx),
+		FileName:    filepath.Base(request.FileName),
+		FileSize:    fileSize,
+		ContentType: request.ContentType,
+	})
+	if err != nil {
+		u.Logger.Error("Failed to get STS token", zap.Int("userId", userID), zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*STSTokenResponse]().
+		Data(stsTokenToResponse(token)).Message("success").Status(0).Build())
 }
 
-// 添加刷新Token的接口
+// RefreshSTSToken
 // @Summary refresh token
-// @Description refresh sts token
+// @Description exchanges a refresh token of the user for new credentials of the same scope
 // @Tags sts token
 // @Accept json
 // @Produce json
-// @Success 200 {object} domain.CommonResponse
+// @Param refresh_token query string true "refresh token"
+// @Success 200 {object} domain.CommonResponse[STSTokenResponse]
 // @Router /v1/upload/refresh-sts [get]
 func (u *UploadController) RefreshSTSToken(ctx *gin.Context) {
 	refreshToken := ctx.Query("refresh_token")
 	if refreshToken == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token is required"})
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("refresh_token is required"), domainErrors.ValidationError))
 		return
 	}
-
-	ctxBg := context.Background()
-
-	// 验证Refresh Token
-	rt, err := u.CacheService.ValidateRefreshToken(ctxBg, refreshToken)
+	userID, ok := controllers.NewAppUtils(ctx).GetUserID()
+	if !ok {
+		_ = ctx.Error(domainErrors.NewAppErrorWithType(domainErrors.NotAuthenticated))
+		return
+	}
+	token, err := u.stsUseCase.Refresh(ctx.Request.Context(), int64(userID), roleID(ctx), refreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
+		_ = ctx.Error(err)
 		return
 	}
-
-	// 删除已使用的refresh token（一次性使用）
-	u.CacheService.DeleteRefreshToken(ctxBg, refreshToken)
-
-	// 生成新的STS Token
-	userID := rt.UserID
-	cacheKey := fmt.Sprintf("sts_token:%s", userID)
-
-	u.processSTSToken(ctx, userID, cacheKey)
+	ctx.JSON(http.StatusOK, controllers.NewCommonResponseBuilder[*STSTokenResponse]().
+		Data(stsTokenToResponse(token)).Message("success").Status(0).Build())
 }
-func NewAuthController(sysFilesUseCase domainFiles.ISysFilesService, loggerInstance *logger.Logger, redisClient *redis.Client) IUploadController {
+
+func NewAuthController(sysFilesUseCase domainFiles.ISysFilesService, stsUseCase domainFiles.ISTSService, loggerInstance *logger.Logger) IUploadController {
 	return &UploadController{
 		sysFilesUseCase: sysFilesUseCase,
+		stsUseCase:      stsUseCase,
 		Logger:          loggerInstance,
-		RedisClient:     redisClient,
-		CacheService:    cacheService.NewSTSCacheService(redisClient),
-	}
-}
-
-// generateSTSToken 生成新的STS Token
-func (u *UploadController) generateSTSToken(sessionName string, roleArn string) (*sts20150401.AssumeRoleResponseBodyCredentials, error) {
-	// 从环境变量中获取步骤1.1生成的RAM用户的访问密钥（AccessKey ID和AccessKey Secret）。
-	accessKeyId := os.Getenv("ALIBABA_CLOUD_ACCESS_KEY_ID")
-	accessKeySecret := os.Getenv("ALIBABA_CLOUD_ACCESS_KEY_SECRET")
-	serviceAddress := os.Getenv("SECURITY_SERVICE_ADDRESS")
-
-	// 创建权限策略客户端。
-	config := &openapi.Config{
-		// 必填，步骤1.1获取到的 AccessKey ID。
-		AccessKeyId: tea.String(accessKeyId),
-		// 必填，步骤1.1获取到的 AccessKey Secret。
-		AccessKeySecret: tea.String(accessKeySecret),
-	}
-
-	// Endpoint 请参考 https://api.aliyun.com/product/Sts
-	config.Endpoint = tea.String(serviceAddress)
-	client, err := sts20150401.NewClient(config)
-	if err != nil {
-		return nil, err
-	}
-
-	// 使用RAM用户的AccessKey ID和AccessKey Secret向STS申请临时访问凭证。
-	request := &sts20150401.AssumeRoleRequest{
-		// 指定STS临时访问凭证过期时间为3600秒。
-		DurationSeconds: tea.Int64(3600),
-		// 从环境变量中获取步骤1.3生成的RAM角色的RamRoleArn。
-		RoleArn: tea.String(roleArn),
-		// 指定自定义角色会话名称
-		RoleSessionName: tea.String(sessionName),
 	}
-
-	response, err := client.AssumeRoleWithOptions(request, &util.RuntimeOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	return response.Body.Credentials, nil
 }
 
-// processSTSToken 处理STS Token的完整流程
-func (u *UploadController) processSTSToken(ctx *gin.Context, userID string, cacheKey string) {
-	roleArn := os.Getenv("RAM_ROLE_ARN")
-	// 生成唯一的会话名称
-	sessionName := fmt.Sprintf("upload-session-%d", time.Now().Unix())
-	//  *gin.Context, userID生成STS Token
-	credentials, err := u.generateSTSToken(sessionName, roleArn)
-	ctxBg := context.Background()
-
-	// 从环境变量中获取步骤1.3生成的RAM角色的RamRole.generateSTSToken(sessionName, roleArn)
-	if err != nil {
-		u.Logger.Error("Failed to generate STS token:", zap.Error(err))
-		appError := domainErrors.NewAppError(err, domainErrors.UploadError)
-		_ = ctx.Error(appError)
-		return
-	}
-
-	// 生成Refresh Token
-	refreshToken, err := u.CacheService.GenerateRefreshToken(ctxBg, userID)
-	if err != nil {
-		u.Logger.Error("Failed to generate refresh token:", zap.Error(err))
-		// 即使生成refresh token失败，也继续返回STS token
+func stsTokenToResponse(token *domainFiles.STSToken) *STSTokenResponse {
+	return &STSTokenResponse{
+		AccessKeyId:     token.AccessKeyId,
+		AccessKeySecret: token.AccessKeySecret,
+		SecurityToken:   token.SecurityToken,
+		Expiration:      token.Expiration.Format(time.RFC3339),
+		BucketName:      token.BucketName,
+		Region:          token.Region,
+		Prefix:          token.Scope.Prefix,
+		MaxSize:         token.Scope.MaxSize,
+		ContentTypes:    token.Scope.ContentTypes,
+		RefreshToken:    token.RefreshToken,
 	}
-
-	// 缓存STS Token
-	expirationTime, _ := time.Parse(time.RFC3339, *credentials.Expiration)
-	tokenCache := &domainFiles.STSTokenCache{
-		AccessKeyId:     *credentials.AccessKeyId,
-		AccessKeySecret: *credentials.AccessKeySecret,
-		SecurityToken:   *credentials.SecurityToken,
-		Expiration:      expirationTime,
-		BucketName:      os.Getenv("OSS_BUCKET_NAME"),
-		Region:          os.Getenv("SECURITY_REGION_ID"),
-		CreatedAt:       time.Now(),
-	}
-
-	// 缓存token，设置为过期时间减去1分钟，确保在过期前刷新
-	cacheDuration := time.Until(expirationTime) - time.Minute
-	if cacheDuration > 0 {
-		u.CacheService.SetSTSToken(ctxBg, cacheKey, tokenCache, cacheDuration)
-	}
-
-	result := controllers.NewCommonResponseBuilder[*STSTokenResponse]().
-		Data(&STSTokenResponse{
-			AccessKeyId:     *credentials.AccessKeyId,
-			AccessKeySecret: *credentials.AccessKeySecret,
-			SecurityToken:   *credentials.SecurityToken,
-			Expiration:      *credentials.Expiration,
-			BucketName:      os.Getenv("OSS_BUCKET_NAME"),
-			Region:          os.Getenv("SECURITY_REGION_ID"),
-			RefreshToken:    refreshToken,
-		}).
-		Message("success").
-		Status(0).
-		Build()
-
-	ctx.JSON(http.StatusOK, result)
 }
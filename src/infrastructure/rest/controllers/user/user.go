@@ -51,6 +51,8 @@ type IUserController interface {
 	SearchPaginated(ctx *gin.Context)
 	SearchByProperty(ctx *gin.Context)
 	UserBindRoles(ctx *gin.Context)
+	GrantUserRole(ctx *gin.Context)
+	GetUserRoles(ctx *gin.Context)
 	ResetPassword(ctx *gin.Context)
 	EditPassword(ctx *gin.Context)
 }
@@ -82,7 +84,7 @@ func (c *UserController) NewUser(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	userModel, err := c.userService.Create(toUsecaseMapper(&request))
+	userModel, err := c.userService.Create(ctx.Request.Context(), toUsecaseMapper(&request))
 	if err != nil {
 		c.Logger.Error("Error creating user", zap.Error(err), zap.String("email", request.Email))
 		_ = ctx.Error(err)
@@ -107,7 +109,7 @@ func (c *UserController) NewUser(ctx *gin.Context) {
 // @Router /v1/user [get]
 func (c *UserController) GetAllUsers(ctx *gin.Context) {
 	c.Logger.Info("Getting all users")
-	users, err := c.userService.GetAll()
+	users, err := c.userService.GetAll(ctx.Request.Context())
 	if err != nil {
 		c.Logger.Error("Error getting all users", zap.Error(err))
 		appError := domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
@@ -137,7 +139,7 @@ func (c *UserController) GetUsersByID(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Getting user by ID", zap.Int("id", userID))
-	user, err := c.userService.GetByID(userID)
+	user, err := c.userService.GetByID(ctx.Request.Context(), userID)
 	if err != nil {
 		c.Logger.Error("Error getting user by ID", zap.Error(err), zap.Int("id", userID))
 		_ = ctx.Error(err)
@@ -179,7 +181,8 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	userUpdated, err := c.userService.Update(int64(userID), requestMap)
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	userUpdated, err := c.userService.Update(ctx.Request.Context(), int64(userID), int64(actorId), requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating user", zap.Error(err), zap.Int("id", userID))
 		_ = ctx.Error(err)
@@ -211,7 +214,7 @@ func (c *UserController) DeleteUser(ctx *gin.Context) {
 		return
 	}
 	c.Logger.Info("Deleting user", zap.Int("id", userID))
-	err = c.userService.Delete(userID)
+	err = c.userService.Delete(ctx.Request.Context(), userID)
 	if err != nil {
 		c.Logger.Error("Error deleting user", zap.Error(err), zap.Int("id", userID))
 		_ = ctx.Error(err)
@@ -315,7 +318,7 @@ func (c *UserController) SearchPaginated(ctx *gin.Context) {
 		filters.SortDirection = sortDirection
 	}
 
-	result, err := c.userService.SearchPaginated(filters)
+	result, err := c.userService.SearchPaginated(ctx.Request.Context(), filters)
 	if err != nil {
 		c.Logger.Error("Error searching users", zap.Error(err))
 		_ = ctx.Error(err)
@@ -375,7 +378,7 @@ func (c *UserController) SearchByProperty(ctx *gin.Context) {
 		return
 	}
 
-	coincidences, err := c.userService.SearchByProperty(property, searchText)
+	coincidences, err := c.userService.SearchByProperty(ctx.Request.Context(), property, searchText)
 	if err != nil {
 		c.Logger.Error("Error searching by property", zap.Error(err), zap.String("property", property))
 		_ = ctx.Error(err)
@@ -412,7 +415,8 @@ func (c *UserController) UserBindRoles(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	err = c.userService.UserBindRoles(int64(userId), requestMap)
+	grantedBy, _ := controllers.NewAppUtils(ctx).GetUserID()
+	err = c.userService.UserBindRoles(ctx.Request.Context(), int64(userId), int64(grantedBy), requestMap)
 	if err != nil {
 		c.Logger.Error("Error updating  user bind role ", zap.Error(err), zap.Int("id", userId))
 		_ = ctx.Error(err)
@@ -427,6 +431,77 @@ func (c *UserController) UserBindRoles(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// GrantUserRole
+// @Summary grant user role
+// @Description grant a single role to the user, optionally limited by startAt and expiresAt
+// @Tags user role
+// @Accept json
+// @Produce json
+// @Param id path int true "user id"
+// @Param grant body domainUser.UserRoleGrant true "role grant"
+// @Success 200 {object} domain.CommonResponse[bool]
+// @Router /v1/user/{id}/role/grant [post]
+func (c *UserController) GrantUserRole(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid user ID parameter ", zap.Error(err), zap.String("id", ctx.Param("id")))
+		appError := domainErrors.NewAppError(errors.New("param id is necessary"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	var request domainUser.UserRoleGrant
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		c.Logger.Error("Error binding JSON for user role grant", zap.Error(err))
+		appError := domainErrors.NewAppError(err, domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	grantedBy, _ := controllers.NewAppUtils(ctx).GetUserID()
+	err = c.userService.GrantRole(ctx.Request.Context(), int64(userId), int64(grantedBy), request)
+	if err != nil {
+		c.Logger.Error("Error granting user role", zap.Error(err), zap.Int("id", userId))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[bool]().
+		Data(true).
+		Message("success").
+		Status(0).
+		Build()
+	c.Logger.Info("Role granted successfully", zap.Int("id", userId), zap.Int64("roleId", request.RoleID))
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetUserRoles
+// @Summary get user role bindings
+// @Description get the role bindings of the user with their validity window
+// @Tags user role
+// @Produce json
+// @Param id path int true "user id"
+// @Success 200 {object} domain.CommonResponse[[]domainUser.UserRole]
+// @Router /v1/user/{id}/role [get]
+func (c *UserController) GetUserRoles(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		c.Logger.Error("Invalid user ID parameter ", zap.Error(err), zap.String("id", ctx.Param("id")))
+		appError := domainErrors.NewAppError(errors.New("param id is necessary"), domainErrors.ValidationError)
+		_ = ctx.Error(appError)
+		return
+	}
+	bindings, err := c.userService.GetUserRoles(ctx.Request.Context(), int64(userId))
+	if err != nil {
+		c.Logger.Error("Error getting user roles", zap.Error(err), zap.Int("id", userId))
+		_ = ctx.Error(err)
+		return
+	}
+	response := controllers.NewCommonResponseBuilder[*[]domainUser.UserRole]().
+		Data(bindings).
+		Message("success").
+		Status(0).
+		Build()
+	ctx.JSON(http.StatusOK, response)
+}
+
 // ResetPassword
 // @Summary reset password
 // @Description reset password
@@ -443,7 +518,8 @@ func (c *UserController) ResetPassword(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	userModal, err := c.userService.ResetPassword(int64(userId))
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	userModal, err := c.userService.ResetPassword(ctx.Request.Context(), int64(userId), int64(actorId))
 	if err != nil {
 		c.Logger.Error("Error updating  user bind role ", zap.Error(err), zap.Int("id", userId))
 		_ = ctx.Error(err)
@@ -482,7 +558,8 @@ func (c *UserController) EditPassword(ctx *gin.Context) {
 		_ = ctx.Error(appError)
 		return
 	}
-	userModal, err := c.userService.EditPassword(int64(userId), request)
+	actorId, _ := controllers.NewAppUtils(ctx).GetUserID()
+	userModal, err := c.userService.EditPassword(ctx.Request.Context(), int64(userId), int64(actorId), request)
 	if err != nil {
 		c.Logger.Error("Error updating  user bind role ", zap.Error(err), zap.Int("id", userId))
 		_ = ctx.Error(err)
@@ -0,0 +1,61 @@
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	userUseCase "github.com/gbrayhan/microservices-go/src/application/services/user"
+	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
+	userRoleRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/user_role"
+	userRepo "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/user"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
+)
+
+// TestGetUsersByID_QuerySpansJoinRequestTrace follows a request through the controller, the use
+// case and the repository, the statements are built on a dry run database
+func TestGetUsersByID_QuerySpansJoinRequestTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: gormLogger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(gormtracing.NewPlugin(gormtracing.WithTracerProvider(provider), gormtracing.WithoutMetrics())))
+
+	loggerInstance := &logger.Logger{Log: zap.NewNop()}
+	useCase := userUseCase.NewUserUseCase(userRepo.NewUserRepository(db, loggerInstance),
+		userRoleRepo.NewSysUserRoleRepository(db, loggerInstance), nil, loggerInstance)
+	controller := NewUserController(useCase, loggerInstance)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(otelgin.Middleware("test", otelgin.WithTracerProvider(provider)))
+	router.GET("/v1/user/:id", controller.GetUsersByID)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/user/1", nil))
+
+	var server sdktrace.ReadOnlySpan
+	var queries []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.SpanKind() == trace.SpanKindServer {
+			server = span
+		} else {
+			queries = append(queries, span)
+		}
+	}
+	require.NotNil(t, server)
+	require.NotEmpty(t, queries)
+	for _, query := range queries {
+		assert.Equal(t, server.SpanContext().TraceID(), query.SpanContext().TraceID(), query.Name())
+		assert.Equal(t, server.SpanContext().SpanID(), query.Parent().SpanID(), query.Name())
+	}
+}
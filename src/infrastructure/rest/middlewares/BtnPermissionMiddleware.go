@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// BtnPermissionChecker 判断角色是否拥有接口绑定的按钮权限
+type BtnPermissionChecker interface {
+	HasBtnPermission(ctx context.Context, roleId int64, path string, method string) (bool, error)
+}
+
+// BtnPermissionMiddleware 按钮级权限校验中间件, 需在 AuthJWTMiddleware 之后使用
+func BtnPermissionMiddleware(checker BtnPermissionChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isIgnoredApi(c) {
+			c.Next()
+			return
+		}
+		appCtx := controllers.NewAppUtils(c)
+		roleId, ok := appCtx.GetRoleID()
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized - Role not found",
+			})
+			return
+		}
+
+		// super user jump verify
+		if userId, _ := appCtx.GetUserID(); userId == 1 {
+			c.Next()
+			return
+		}
+
+		// sys_apis 中存储的是路由模板, 例如 /v1/role/:id
+		ok, err := checker.HasBtnPermission(c.Request.Context(), roleId, c.FullPath(), c.Request.Method)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "Internal server error during authorization",
+			})
+			return
+		}
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Forbidden - Button permission required",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
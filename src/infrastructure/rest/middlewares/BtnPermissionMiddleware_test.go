@@ -0,0 +1,95 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBtnPermissionChecker struct {
+	allowed bool
+	err     error
+	path    string
+	method  string
+}
+
+func (m *mockBtnPermissionChecker) HasBtnPermission(_ context.Context, roleId int64, path string, method string) (bool, error) {
+	m.path = path
+	m.method = method
+	return m.allowed, m.err
+}
+
+func setupBtnPermissionRouter(checker BtnPermissionChecker, userId int, roleId int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userId)
+		if roleId != 0 {
+			c.Set("role_id", roleId)
+		}
+		c.Next()
+	})
+	router.Use(BtnPermissionMiddleware(checker))
+	router.DELETE("/v1/role/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestBtnPermissionMiddleware_Allowed(t *testing.T) {
+	checker := &mockBtnPermissionChecker{allowed: true}
+	router := setupBtnPermissionRouter(checker, 2, 3)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/role/5", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/v1/role/:id", checker.path)
+	assert.Equal(t, "DELETE", checker.method)
+}
+
+func TestBtnPermissionMiddleware_Forbidden(t *testing.T) {
+	checker := &mockBtnPermissionChecker{allowed: false}
+	router := setupBtnPermissionRouter(checker, 2, 3)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/role/5", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestBtnPermissionMiddleware_CheckerError(t *testing.T) {
+	checker := &mockBtnPermissionChecker{err: errors.New("db down")}
+	router := setupBtnPermissionRouter(checker, 2, 3)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/role/5", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestBtnPermissionMiddleware_SuperUser(t *testing.T) {
+	checker := &mockBtnPermissionChecker{allowed: false}
+	router := setupBtnPermissionRouter(checker, 1, 3)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/role/5", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, checker.path)
+}
+
+func TestBtnPermissionMiddleware_NoRole(t *testing.T) {
+	checker := &mockBtnPermissionChecker{allowed: true}
+	router := setupBtnPermissionRouter(checker, 2, 0)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/role/5", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
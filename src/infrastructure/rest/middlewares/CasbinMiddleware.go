@@ -12,6 +12,11 @@ import (
 // CasbinMiddleware 创建一个Casbin权限验证中间件
 func CasbinMiddleware(enforcer *casbin.Enforcer) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 忽略列表中的接口不做权限验证
+		if isIgnoredApi(c) {
+			c.Next()
+			return
+		}
 		// 获取应用上下文
 		appCtx := controllers.NewAppUtils(c)
 		// 获取角色ID
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
 	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -30,10 +31,14 @@ func CorsHeader() gin.HandlerFunc {
 	origins := strings.Split(sharedUtil.GetEnv("ALLOWED_ORIGINS", ""), ",")
 
 	return cors.New(cors.Config{
-		AllowOrigins:     origins,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Cache-Control", "X-Requested-With", "User-Agent", " Content-Length", "Accept-Encoding", "X-CSRF-Token"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowOrigins: origins,
+		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "Cache-Control", "X-Requested-With", "User-Agent", " Content-Length", "Accept-Encoding", "X-CSRF-Token", requestid.Header,
+			"Content-MD5", "Tus-Resumable", "Upload-Length", "Upload-Offset", "Upload-Metadata", "Upload-Checksum"},
+		// tus clients read the upload state from the response headers
+		ExposeHeaders: []string{"Content-Length", requestid.Header,
+			"Location", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size", "Tus-Checksum-Algorithm",
+			"Upload-Offset", "Upload-Length", "Upload-Expires", "X-File-Id"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	})
@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ignoreApiKey marks requests matching the ignore list in the gin context
+const ignoreApiKey = "ignore_api"
+
+// IgnoreApiMatcher 判断请求是否在忽略列表中
+type IgnoreApiMatcher interface {
+	IsIgnored(path string, method string) bool
+}
+
+// IgnoreApiMiddleware 标记忽略列表中的请求, 需在 GinBodyLogMiddleware 之前全局注册,
+// 被标记的请求跳过 Casbin 与按钮权限校验以及请求体记录
+func IgnoreApiMiddleware(matcher IgnoreApiMatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if matcher.IsIgnored(c.Request.URL.Path, c.Request.Method) {
+			c.Set(ignoreApiKey, true)
+		}
+		c.Next()
+	}
+}
+
+func isIgnoredApi(c *gin.Context) bool {
+	return c.GetBool(ignoreApiKey)
+}
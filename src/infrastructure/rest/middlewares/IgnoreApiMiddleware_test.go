@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	domainIgnoreApi "github.com/gbrayhan/microservices-go/src/domain/sys/ignore_api"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIgnoreApiMatcher struct {
+	ignoreApis []domainIgnoreApi.IgnoreApi
+}
+
+func (m *mockIgnoreApiMatcher) IsIgnored(path string, method string) bool {
+	for _, ignoreApi := range m.ignoreApis {
+		if ignoreApi.Matches(path, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func setupIgnoreApiRouter(matcher IgnoreApiMatcher) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(IgnoreApiMiddleware(matcher))
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", 2)
+		c.Set("role_id", int64(3))
+		c.Next()
+	})
+	// an enforcer without policies denies every request
+	enforcer, _ := casbin.NewEnforcer("../../../../config/model.conf")
+	router.Use(CasbinMiddleware(enforcer))
+	router.GET("/v1/dictionary/:type", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.POST("/v1/dictionary/:type", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestIgnoreApiMiddleware_BypassesCasbin(t *testing.T) {
+	matcher := &mockIgnoreApiMatcher{ignoreApis: []domainIgnoreApi.IgnoreApi{
+		{Path: "/v1/dictionary/:type", Method: "GET"},
+	}}
+	router := setupIgnoreApiRouter(matcher)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/dictionary/status", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/dictionary/status", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestIgnoreApiMiddleware_AnyMethod(t *testing.T) {
+	matcher := &mockIgnoreApiMatcher{ignoreApis: []domainIgnoreApi.IgnoreApi{
+		{Path: "/v1/dictionary/*", Method: "*"},
+	}}
+	router := setupIgnoreApiRouter(matcher)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/v1/dictionary/status", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -9,45 +9,71 @@ import (
 
 	"github.com/gbrayhan/microservices-go/src/domain"
 	operationRecordsDomain "github.com/gbrayhan/microservices-go/src/domain/sys/operation_records"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
 	logger "github.com/gbrayhan/microservices-go/src/infrastructure/logger"
-	operationRecordsRepository "github.com/gbrayhan/microservices-go/src/infrastructure/repository/psql/sys/operation_records"
 	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
+	"go.uber.org/zap"
 )
 
 type bodyLogWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
+	// limit caps the captured bytes, 0 captures everything
+	limit int
 }
 
 func (w bodyLogWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
+	if w.limit <= 0 {
+		w.body.Write(b)
+	} else if room := w.limit - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(room, len(b))])
+	}
 	return w.ResponseWriter.Write(b)
 }
 
-func GinBodyLogMiddleware(db *gorm.DB, logger *logger.Logger) gin.HandlerFunc {
+// OperationRecordWriter 异步写入操作记录, 缓冲区满时按配置丢弃或等待
+type OperationRecordWriter interface {
+	Write(record *operationRecordsDomain.SysOperationRecord) bool
+}
+
+// BodyRedactor 在操作记录入库前屏蔽密码、令牌等敏感字段并截断过长的内容
+type BodyRedactor interface {
+	Redact(body string, contentType string) string
+	MaxBodySize() int
+}
+
+func GinBodyLogMiddleware(recordWriter OperationRecordWriter, redactor BodyRedactor, logger *logger.Logger) gin.HandlerFunc {
+	// capture one extra byte so the redactor notices the body was cut and can mark it truncated
+	captureSize := 4096
+	if redactor.MaxBodySize() > 0 {
+		captureSize = redactor.MaxBodySize() + 1
+	}
+
 	return func(c *gin.Context) {
+		// prometheus scrapes and probes would flood the operation records
+		if IsMonitoringPath(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
 		var reqBody string
 		var resp string
+		var blw *bodyLogWriter
 		appCtx := controllers.NewAppUtils(c)
 
-		// jump upload api
-		if strings.HasPrefix(c.Request.RequestURI, "/v1/upload") {
-			reqBody = ""
-			resp = ""
-		} else {
-			blw := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
+		// jump upload api and apis in the ignore list
+		if !strings.HasPrefix(c.Request.RequestURI, "/v1/upload") && !isIgnoredApi(c) {
+			blw = &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer, limit: captureSize}
 			c.Writer = blw
 
-			buf := make([]byte, 4096)
-			num, err := c.Request.Body.Read(buf)
-			if err != nil && err.Error() != "EOF" {
-				_ = fmt.Errorf("error reading buffer: %s", err.Error())
+			buf := make([]byte, captureSize)
+			num, err := io.ReadFull(c.Request.Body, buf)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				logger.Warn("Error reading request body", zap.Error(err))
 			}
-			reqBody := string(buf[0:num])
-			resp = blw.body.String()
-			c.Request.Body = io.NopCloser(bytes.NewBuffer([]byte(reqBody)))
+			reqBody = redactor.Redact(string(buf[0:num]), c.ContentType())
+			// keep the unread remainder so handlers still receive bodies larger than the buffer
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewBuffer(buf[0:num]), c.Request.Body))
 		}
 
 		loc, _ := time.LoadLocation("America/Mexico_City")
@@ -55,9 +81,11 @@ func GinBodyLogMiddleware(db *gorm.DB, logger *logger.Logger) gin.HandlerFunc {
 		start := time.Now()
 		c.Next()
 		latency := time.Since(start).Milliseconds()
-		operationRecordsRepository := operationRecordsRepository.NewOperationRepository(db, logger)
+		if blw != nil {
+			resp = redactor.Redact(blw.body.String(), c.Writer.Header().Get("Content-Type"))
+		}
 		userId, _ := appCtx.GetUserID()
-		operationRecordsRepository.Create(&operationRecordsDomain.SysOperationRecord{
+		recordWriter.Write(&operationRecordsDomain.SysOperationRecord{
 			IP:           c.ClientIP(),
 			Method:       c.Request.Method,
 			Path:         c.Request.RequestURI,
@@ -67,6 +95,7 @@ func GinBodyLogMiddleware(db *gorm.DB, logger *logger.Logger) gin.HandlerFunc {
 			Resp:         resp,
 			ErrorMessage: c.Errors.String(),
 			UserID:       int64(userId),
+			RequestID:    c.GetString(requestid.ContextKey),
 			Latency:      latency,
 			CreatedAt:    domain.CustomTime{Time: time.Now().In(loc)},
 		})
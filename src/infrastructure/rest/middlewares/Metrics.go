@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
+	sharedUtil "github.com/gbrayhan/microservices-go/src/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics observes the request latency by route template, requests matching no route are
+// grouped under "unmatched" to keep the label cardinality bounded
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsAuth protects /metrics with the bearer token in METRICS_TOKEN, every scrape is refused
+// while the variable is empty so the runtime details are never exposed by default
+func MetricsAuth() gin.HandlerFunc {
+	token := sharedUtil.GetEnv("METRICS_TOKEN", "")
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		expected := "Bearer " + token
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte(expected)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
+
+// IsMonitoringPath reports the paths polled by prometheus and the health probes, recording or
+// tracing them would only add noise
+func IsMonitoringPath(path string) bool {
+	return path == metrics.Path || path == "/v1/health" || strings.HasPrefix(path, "/v1/health/")
+}
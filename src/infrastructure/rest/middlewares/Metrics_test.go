@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/items/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/items/1", "/items/2", "/missing"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	count := func(route, status string) uint64 {
+		metric := &dto.Metric{}
+		observer, _ := metrics.HTTPRequestDuration.GetMetricWithLabelValues("GET", route, status)
+		_ = observer.(prometheus.Metric).Write(metric)
+		return metric.GetHistogram().GetSampleCount()
+	}
+	assert.Equal(t, uint64(2), count("/items/:id", "200"))
+	assert.Equal(t, uint64(1), count("unmatched", "404"))
+}
+
+func TestMetricsAuth_RequiresToken(t *testing.T) {
+	t.Setenv("METRICS_TOKEN", "secret")
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(metrics.Path, MetricsAuth(), gin.WrapH(metrics.Handler()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", metrics.Path, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	req.Header.Set("Authorization", "Bearer secret")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "microservices_http_requests_in_flight")
+}
+
+func TestMetricsAuth_DeniesWithoutToken(t *testing.T) {
+	t.Setenv("METRICS_TOKEN", "")
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(metrics.Path, MetricsAuth(), gin.WrapH(metrics.Handler()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", metrics.Path, nil)
+	req.Header.Set("Authorization", "Bearer ")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestID keeps a valid X-Request-ID sent by the client or creates one, stores it in the gin
+// and request contexts and echoes it in the response. It must be registered before every other
+// middleware but the tracing one, whose server span it tags with the id.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		c.Set(requestid.ContextKey, id)
+		c.Request = c.Request.WithContext(requestid.WithContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("http.request_id", id))
+		c.Next()
+	}
+}
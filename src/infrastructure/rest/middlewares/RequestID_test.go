@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequestIDRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(ErrorHandler())
+	router.GET("/test", handler)
+	return router
+}
+
+func TestRequestID_KeepsValidClientID(t *testing.T) {
+	var fromGin, fromRequest string
+	router := newRequestIDRouter(func(c *gin.Context) {
+		fromGin = c.GetString(requestid.ContextKey)
+		fromRequest = requestid.FromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set(requestid.Header, "client-id-1")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "client-id-1", w.Header().Get(requestid.Header))
+	assert.Equal(t, "client-id-1", fromGin)
+	assert.Equal(t, "client-id-1", fromRequest)
+}
+
+func TestRequestID_ReplacesInvalidClientID(t *testing.T) {
+	router := newRequestIDRouter(func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set(requestid.Header, "has spaces\tand tabs")
+	router.ServeHTTP(w, req)
+
+	id := w.Header().Get(requestid.Header)
+	assert.NotEqual(t, "has spaces\tand tabs", id)
+	assert.True(t, requestid.Valid(id))
+}
+
+func TestRequestID_EchoedInErrorResponse(t *testing.T) {
+	router := newRequestIDRouter(func(c *gin.Context) {
+		_ = c.Error(domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set(requestid.Header, "abc")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"record not found","request_id":"abc"}`, w.Body.String())
+}
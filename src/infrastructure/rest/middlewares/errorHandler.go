@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	domainErrors "github.com/gbrayhan/microservices-go/src/domain/errors"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/requestid"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,13 +15,18 @@ func ErrorHandler() gin.HandlerFunc {
 
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last().Err
+			status, body := http.StatusInternalServerError, gin.H{"error": "Internal Server Error"}
 			var appErr *domainErrors.AppError
 			if errors.As(err, &appErr) {
-				status, message := domainErrors.AppErrorToHTTP(appErr)
-				c.JSON(status, gin.H{"error": message})
-			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+				var message string
+				status, message = domainErrors.AppErrorToHTTP(appErr)
+				body = gin.H{"error": message}
 			}
+			// the request id lets clients quote the failing request when reporting it
+			if id := c.GetString(requestid.ContextKey); id != "" {
+				body["request_id"] = id
+			}
+			c.JSON(status, body)
 		}
 	}
 }
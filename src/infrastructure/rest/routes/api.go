@@ -7,7 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func ApiRouters(router *gin.RouterGroup, routerEngine *gin.Engine, controller api.IApiController, enforcer *casbin.Enforcer) {
+func ApiRouters(router *gin.RouterGroup, routerEngine *gin.Engine, controller api.IApiController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 
 	// 用户获取接口列表
 	if routerSetter, ok := controller.(api.RouterSetter); ok {
@@ -16,6 +16,7 @@ func ApiRouters(router *gin.RouterGroup, routerEngine *gin.Engine, controller ap
 	u := router.Group("/api")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewApi)
 		u.GET("", controller.GetAllApis)
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/casbin/casbin/v2"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/audit_log"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func AuditLogRouters(router *gin.RouterGroup, controller audit_log.IAuditLogController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
+	u := router.Group("/audit-log")
+	u.Use(middlewares.AuthJWTMiddleware())
+	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
+	{
+		u.GET("/search", controller.SearchPaginated)
+		u.GET("/:id", controller.GetAuditLogByID)
+	}
+}
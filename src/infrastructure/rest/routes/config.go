@@ -7,12 +7,13 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func ConfigRouters(router *gin.RouterGroup, controller config.IConfigController, enforcer *casbin.Enforcer) {
+func ConfigRouters(router *gin.RouterGroup, controller config.IConfigController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/config")
 	u.GET("/system", controller.GetConfigBySystem)
 
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.GET("", controller.GetAllConfigs)
 		u.PUT("/:module", controller.UpdateConfig)
@@ -7,11 +7,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func DictionaryRouters(router *gin.RouterGroup, controller dictionary.IDictionaryController, enforcer *casbin.Enforcer) {
+func DictionaryRouters(router *gin.RouterGroup, controller dictionary.IDictionaryController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/dictionary")
 
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewDictionary)
 		u.GET("", controller.GetAllDictionaries)
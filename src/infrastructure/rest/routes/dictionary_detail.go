@@ -7,10 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func DictionaryDetailRouters(router *gin.RouterGroup, controller dictionary_detail.IIDictionaryDetailController, enforcer *casbin.Enforcer) {
+func DictionaryDetailRouters(router *gin.RouterGroup, controller dictionary_detail.IIDictionaryDetailController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/dictionary_detail")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewDictionary)
 		u.GET("", controller.GetAllDictionaries)
@@ -7,10 +7,29 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func FileRouters(router *gin.RouterGroup, controller file.IFileController, enforcer *casbin.Enforcer) {
+// FileRouters registers the file apis. Public files are served under publicPrefix, the key
+// prefix of the local storage, no route is registered when it is empty.
+func FileRouters(router *gin.RouterGroup, routerEngine *gin.Engine, controller file.IFileController, publicPrefix string,
+	enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
+	// signed urls and public files are read without a token
+	router.GET("/file/signed/*key", controller.ServeSigned)
+	if publicPrefix != "" {
+		routerEngine.GET("/"+publicPrefix+"/*filepath", controller.ServePublic)
+	}
+
+	// reading a file is checked against its owner, a Casbin policy on these paths grants every file
+	r := router.Group("/file")
+	r.Use(middlewares.AuthJWTMiddleware())
+	{
+		r.GET("/:id/download", controller.Download)
+		r.GET("/:id/thumbnail", controller.GetThumbnail)
+		r.POST("/:id/signed-url", controller.SignURL)
+	}
+
 	u := router.Group("/file")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewFile)
 		u.GET("", controller.GetAllFiles)
@@ -19,5 +38,23 @@ func FileRouters(router *gin.RouterGroup, controller file.IFileController, enfor
 		u.DELETE("/:id", controller.DeleteFile)
 		u.GET("/search", controller.SearchPaginated)
 		u.GET("/search-property", controller.SearchByProperty)
+		u.POST("/delete-batch", controller.DeleteFiles)
+		u.GET("/usage/users", controller.UsageByUser)
+		u.GET("/usage/engines", controller.UsageByEngine)
+	}
+}
+
+// FileAttachmentRouters registers the apis attaching files to the entities
+func FileAttachmentRouters(router *gin.RouterGroup, controller file.IFileAttachmentController, enforcer *casbin.Enforcer,
+	btnChecker middlewares.BtnPermissionChecker) {
+	u := router.Group("/file/attachments")
+	u.Use(middlewares.AuthJWTMiddleware())
+	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
+	{
+		u.GET("/:entity_type/:entity_id", controller.ListAttachments)
+		u.POST("/:entity_type/:entity_id", controller.Attach)
+		u.PUT("/:entity_type/:entity_id/order", controller.ReorderAttachments)
+		u.DELETE("/:entity_type/:entity_id/:file_id", controller.Detach)
 	}
 }
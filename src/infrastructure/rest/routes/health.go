@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/health"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRouters registers the probes without authentication, /health is kept as liveness
+func HealthRouters(router *gin.RouterGroup, controller health.IHealthController) {
+	router.GET("/health", controller.Liveness)
+	router.GET("/health/live", controller.Liveness)
+	router.GET("/health/ready", controller.Readiness)
+}
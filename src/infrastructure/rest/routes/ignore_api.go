@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/casbin/casbin/v2"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/ignore_api"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func IgnoreApiRouters(router *gin.RouterGroup, controller ignore_api.IIgnoreApiController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
+	u := router.Group("/ignore-api")
+	u.Use(middlewares.AuthJWTMiddleware())
+	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
+	{
+		u.POST("", controller.NewIgnoreApi)
+		u.GET("", controller.GetAllIgnoreApis)
+		u.GET("/:id", controller.GetIgnoreApiByID)
+		u.PUT("/:id", controller.UpdateIgnoreApi)
+		u.DELETE("/:id", controller.DeleteIgnoreApi)
+		u.POST("/delete-batch", controller.DeleteIgnoreApis)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/casbin/casbin/v2"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/logging"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func LoggingRouters(router *gin.RouterGroup, controller logging.ILoggingController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
+	u := router.Group("/logging")
+	u.Use(middlewares.AuthJWTMiddleware())
+	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
+	{
+		u.GET("/levels", controller.GetLevels)
+		u.PUT("/levels", controller.SetLevel)
+		u.DELETE("/levels/:component", controller.ResetLevel)
+		u.POST("/reload", controller.Reload)
+	}
+}
@@ -7,11 +7,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func MenuRouters(router *gin.RouterGroup, controller menu.IMenuController, enforcer *casbin.Enforcer) {
+func MenuRouters(router *gin.RouterGroup, controller menu.IMenuController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	router.Use(middlewares.OptionalAuthMiddleware()).GET("/menu/user", controller.GetUserMenus)
 	u := router.Group("/menu")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewMenu)
 		u.GET("", controller.GetAllMenus)
@@ -7,14 +7,17 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func MenuBtnRouters(router *gin.RouterGroup, controller menuBtn.IMenuBtnController, enforcer *casbin.Enforcer) {
+func MenuBtnRouters(router *gin.RouterGroup, controller menuBtn.IMenuBtnController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/menu_btn")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewMenuBtn)
 		u.GET("", controller.GetAllMenuBtns)
 		u.GET("/:id", controller.GetMenuBtnsByID)
+		u.GET("/:id/api", controller.GetMenuBtnApis)
+		u.POST("/:id/api", controller.BindMenuBtnApis)
 		u.PUT("/:id", controller.UpdateMenuBtn)
 		u.DELETE("/:id", controller.DeleteMenuBtn)
 	}
@@ -7,10 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func MenuGroupRouters(router *gin.RouterGroup, controller menu_group.IMenuGroupController, enforcer *casbin.Enforcer) {
+func MenuGroupRouters(router *gin.RouterGroup, controller menu_group.IMenuGroupController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/menu_group")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewMenuGroup)
 		u.GET("", controller.GetAllMenuGroups)
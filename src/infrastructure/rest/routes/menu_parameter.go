@@ -7,10 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func MenuParameterRouters(router *gin.RouterGroup, controller menuParameter.IMenuParameterController, enforcer *casbin.Enforcer) {
+func MenuParameterRouters(router *gin.RouterGroup, controller menuParameter.IMenuParameterController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/menu_parameter")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewMenuParameter)
 		u.GET("", controller.GetAllMenuParameters)
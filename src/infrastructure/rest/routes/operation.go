@@ -7,15 +7,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func OperationRouters(router *gin.RouterGroup, controller operation.IOperationController, enforcer *casbin.Enforcer) {
+func OperationRouters(router *gin.RouterGroup, controller operation.IOperationController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/operation")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.GET("", controller.GetAllOperations)
 		u.GET("/:id", controller.GetOperationsByID)
 		u.DELETE("/:id", controller.DeleteOperation)
 		u.POST("/delete-batch", controller.DeleteOperations)
 		u.GET("/search", controller.SearchPaginated)
+		u.GET("/export", controller.Export)
+		u.GET("/analytics/latency", controller.LatencyPercentiles)
+		u.GET("/analytics/error-rate", controller.ErrorRates)
+		u.GET("/analytics/top-users", controller.TopUsers)
+		u.GET("/analytics/slowest", controller.SlowestEndpoints)
 	}
 }
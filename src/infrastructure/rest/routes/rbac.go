@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/casbin/casbin/v2"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/rbac"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func RbacRouters(router *gin.RouterGroup, controller rbac.IRbacController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
+	u := router.Group("/rbac")
+	u.Use(middlewares.AuthJWTMiddleware())
+	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
+	{
+		u.GET("/export", controller.Export)
+		u.POST("/import/preview", controller.Preview)
+		u.POST("/import", controller.Import)
+	}
+}
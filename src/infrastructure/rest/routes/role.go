@@ -7,10 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RoleRoutes(router *gin.RouterGroup, controller role.IRoleController, enforcer *casbin.Enforcer) {
+func RoleRoutes(router *gin.RouterGroup, controller role.IRoleController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/role")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewRole)
 		u.GET("", controller.GetAllRoles)
@@ -1,9 +1,10 @@
 package routes
 
 import (
-	"net/http"
-
 	"github.com/gbrayhan/microservices-go/src/infrastructure/di"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/lib/metrics"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/controllers/health"
+	"github.com/gbrayhan/microservices-go/src/infrastructure/rest/middlewares"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
 	ginSwagger "github.com/swaggo/gin-swagger" // gin-swagger middleware
@@ -12,29 +13,35 @@ import (
 func ApplicationRouter(router *gin.Engine, appContext *di.ApplicationContext) {
 	v1 := router.Group("/v1")
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	v1.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "ok",
-			"message": "Service is running",
-		})
-	})
+	router.GET(metrics.Path, middlewares.MetricsAuth(), gin.WrapH(metrics.Handler()))
+	HealthRouters(v1, health.NewHealthController(appContext.Health))
+
+	// button level permission, apis bound to menu buttons require the button
+	btnChecker := appContext.MenuBtnModule.UseCase
 
 	AuthRoutes(v1, appContext.AuthModule.Controller, appContext.Enforcer)
-	UserRoutes(v1, appContext.UserModule.Controller, appContext.Enforcer)
-	UploadRoutes(v1, appContext.UploadModule.Controller, appContext.Enforcer)
-	RoleRoutes(v1, appContext.RoleModule.Controller, appContext.Enforcer)
-	ApiRouters(v1, router, appContext.ApiModule.Controller, appContext.Enforcer)
-	OperationRouters(v1, appContext.OperationModule.Controller, appContext.Enforcer)
-	DictionaryRouters(v1, appContext.DictionaryModule.Controller, appContext.Enforcer)
-	DictionaryDetailRouters(v1, appContext.DictionaryDetailModule.Controller, appContext.Enforcer)
-	MenuRouters(v1, appContext.MenuModule.Controller, appContext.Enforcer)
-	MenuGroupRouters(v1, appContext.MenuGroupModule.Controller, appContext.Enforcer)
-	MenuBtnRouters(v1, appContext.MenuBtnModule.Controller, appContext.Enforcer)
-	MenuParameterRouters(v1, appContext.MenuParameterModule.Controller, appContext.Enforcer)
-	FileRouters(v1, appContext.FileModule.Controller, appContext.Enforcer)
+	UserRoutes(v1, appContext.UserModule.Controller, appContext.Enforcer, btnChecker)
+	UploadRoutes(v1, appContext.UploadModule.Controller, appContext.Enforcer, btnChecker)
+	ChunkedUploadRoutes(v1, appContext.UploadModule.ChunkedController, appContext.Enforcer, btnChecker)
+	UploadCallbackRoutes(v1, appContext.UploadModule.CallbackController)
+	RoleRoutes(v1, appContext.RoleModule.Controller, appContext.Enforcer, btnChecker)
+	ApiRouters(v1, router, appContext.ApiModule.Controller, appContext.Enforcer, btnChecker)
+	OperationRouters(v1, appContext.OperationModule.Controller, appContext.Enforcer, btnChecker)
+	DictionaryRouters(v1, appContext.DictionaryModule.Controller, appContext.Enforcer, btnChecker)
+	DictionaryDetailRouters(v1, appContext.DictionaryDetailModule.Controller, appContext.Enforcer, btnChecker)
+	MenuRouters(v1, appContext.MenuModule.Controller, appContext.Enforcer, btnChecker)
+	MenuGroupRouters(v1, appContext.MenuGroupModule.Controller, appContext.Enforcer, btnChecker)
+	MenuBtnRouters(v1, appContext.MenuBtnModule.Controller, appContext.Enforcer, btnChecker)
+	MenuParameterRouters(v1, appContext.MenuParameterModule.Controller, appContext.Enforcer, btnChecker)
+	FileRouters(v1, router, appContext.FileModule.Controller, appContext.Storage.KeyPrefix(), appContext.Enforcer, btnChecker)
+	FileAttachmentRouters(v1, appContext.FileModule.AttachmentController, appContext.Enforcer, btnChecker)
 
-	ScheduledTaskRouters(v1, appContext.ScheduledTaskModule.Controller, appContext.Enforcer)
-	ConfigRouters(v1, appContext.ConfigModule.Controller, appContext.Enforcer)
-	TaskExecutionLogRouters(v1, appContext.TaskExecutionLogModule.Controller, appContext.Enforcer)
+	ScheduledTaskRouters(v1, appContext.ScheduledTaskModule.Controller, appContext.Enforcer, btnChecker)
+	ConfigRouters(v1, appContext.ConfigModule.Controller, appContext.Enforcer, btnChecker)
+	LoggingRouters(v1, appContext.LoggingModule.Controller, appContext.Enforcer, btnChecker)
+	TaskExecutionLogRouters(v1, appContext.TaskExecutionLogModule.Controller, appContext.Enforcer, btnChecker)
+	RbacRouters(v1, appContext.RbacModule.Controller, appContext.Enforcer, btnChecker)
+	IgnoreApiRouters(v1, appContext.IgnoreApiModule.Controller, appContext.Enforcer, btnChecker)
+	AuditLogRouters(v1, appContext.AuditLogModule.Controller, appContext.Enforcer, btnChecker)
 
 }
@@ -7,10 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func ScheduledTaskRouters(router *gin.RouterGroup, controller scheduled_task.IScheduledTaskController, enforcer *casbin.Enforcer) {
+func ScheduledTaskRouters(router *gin.RouterGroup, controller scheduled_task.IScheduledTaskController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/scheduled_task")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewScheduledTask)
 		u.GET("", controller.GetAllScheduledTasks)
@@ -7,10 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func TaskExecutionLogRouters(router *gin.RouterGroup, controller task_execution_log.ITaskExecutionLogController, enforcer *casbin.Enforcer) {
+func TaskExecutionLogRouters(router *gin.RouterGroup, controller task_execution_log.ITaskExecutionLogController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/task_execution_log")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.GET("/search", controller.SearchPaginated)
 	}
@@ -7,14 +7,46 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func UploadRoutes(router *gin.RouterGroup, controller upload.IUploadController, enforcer *casbin.Enforcer) {
+func UploadRoutes(router *gin.RouterGroup, controller upload.IUploadController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/upload")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("/single", controller.Single)
 		u.POST("/multiple", controller.Multiple)
+		u.POST("/check", controller.Check)
 		u.GET("/sts-token", controller.GetSTSToken)
 		u.GET("/refresh-sts", controller.RefreshSTSToken)
 	}
 }
+
+func ChunkedUploadRoutes(router *gin.RouterGroup, controller upload.IChunkedUploadController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
+	// tus discovery is answered without a token, it only lists the supported extensions
+	router.OPTIONS("/upload/tus", controller.TusOptions)
+
+	u := router.Group("/upload")
+	u.Use(middlewares.AuthJWTMiddleware())
+	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
+	{
+		u.POST("/multipart", controller.Initiate)
+		u.GET("/multipart/:id", controller.Get)
+		u.DELETE("/multipart/:id", controller.Abort)
+		u.GET("/multipart/:id/parts", controller.ListParts)
+		u.PUT("/multipart/:id/parts/:number", controller.UploadPart)
+		u.POST("/multipart/:id/complete", controller.Complete)
+
+		u.POST("/tus", controller.TusCreate)
+		u.HEAD("/tus/:id", controller.TusHead)
+		u.PATCH("/tus/:id", controller.TusPatch)
+		u.DELETE("/tus/:id", controller.TusDelete)
+	}
+}
+
+// UploadCallbackRoutes are called by the bucket or a trusted sender without a token, the
+// callbacks are signed
+func UploadCallbackRoutes(router *gin.RouterGroup, controller upload.IUploadCallbackController) {
+	router.POST("/upload/callback/oss", controller.OSSCallback)
+	router.POST("/upload/callback", controller.Callback)
+}
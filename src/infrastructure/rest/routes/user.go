@@ -7,10 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func UserRoutes(router *gin.RouterGroup, controller user.IUserController, enforcer *casbin.Enforcer) {
+func UserRoutes(router *gin.RouterGroup, controller user.IUserController, enforcer *casbin.Enforcer, btnChecker middlewares.BtnPermissionChecker) {
 	u := router.Group("/user")
 	u.Use(middlewares.AuthJWTMiddleware())
 	u.Use(middlewares.CasbinMiddleware(enforcer))
+	u.Use(middlewares.BtnPermissionMiddleware(btnChecker))
 	{
 		u.POST("", controller.NewUser)
 		u.GET("", controller.GetAllUsers)
@@ -20,6 +21,8 @@ func UserRoutes(router *gin.RouterGroup, controller user.IUserController, enforc
 		u.GET("/search", controller.SearchPaginated)
 		u.GET("/search-property", controller.SearchByProperty)
 		u.POST(":id/role", controller.UserBindRoles)
+		u.GET("/:id/role", controller.GetUserRoles)
+		u.POST("/:id/role/grant", controller.GrantUserRole)
 		u.POST("/:id/reset-password", controller.ResetPassword)
 		u.POST("/:id/edit-password", controller.EditPassword)
 	}
@@ -1,6 +1,7 @@
 package task_execution_log
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
@@ -38,7 +39,7 @@ func (ch *LogHandler) OnConnectWithContext(conn *websocket.Conn, ctx *ws.WebSock
 func (ch *LogHandler) OnConnect(conn *websocket.Conn) {
 	ch.Logger.Info("Log handler: Client connected")
 
-	result, err := ch.taskExecutionLogService.GetByTaskID(0, 100)
+	result, err := ch.taskExecutionLogService.GetByTaskID(context.Background(), 0, 100)
 	if err != nil {
 		ch.sendError(conn, "Failed to fetch initial logs: "+err.Error())
 		return
@@ -67,7 +68,7 @@ func (ch *LogHandler) OnMessage(conn *websocket.Conn, message []byte) {
 	}
 
 	// 调用 GetByTaskID 获取日志数据
-	result, err := ch.taskExecutionLogService.GetByTaskID(uint(request.TaskID), request.Limit)
+	result, err := ch.taskExecutionLogService.GetByTaskID(context.Background(), uint(request.TaskID), request.Limit)
 	if err != nil {
 		ch.sendError(conn, "Failed to fetch logs: "+err.Error())
 		return
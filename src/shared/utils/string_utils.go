@@ -3,6 +3,7 @@ package utils
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -39,3 +40,27 @@ func GetEnvAsInt(key string, defaultVal int) int {
 	}
 	return defaultVal
 }
+
+// GetEnvAsList splits a comma separated variable, dropping the empty items
+func GetEnvAsList(key string, defaultVal []string) []string {
+	valStr, ok := os.LookupEnv(key)
+	if !ok || strings.TrimSpace(valStr) == "" {
+		return defaultVal
+	}
+	items := make([]string, 0)
+	for _, item := range strings.Split(valStr, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func GetEnvAsBool(key string, defaultVal bool) bool {
+	if valStr, ok := os.LookupEnv(key); ok {
+		if val, err := strconv.ParseBool(valStr); err == nil {
+			return val
+		}
+	}
+	return defaultVal
+}